@@ -5,6 +5,7 @@ import (
 	"neobase-ai/internal/constants"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -45,14 +46,74 @@ type Environment struct {
 	// OpenAI configs
 	OpenAIAPIKey              string
 	OpenAIModel               string
+	OpenAIModels              []string // Other models chats may select
 	OpenAIMaxCompletionTokens int
 	OpenAITemperature         float64
+	OpenAIContextLimit        int
+	OpenAIEmbeddingModel      string
 
 	// Gemini configs
 	GeminiAPIKey              string
 	GeminiModel               string
+	GeminiModels              []string
 	GeminiMaxCompletionTokens int
 	GeminiTemperature         float64
+	GeminiContextLimit        int
+	GeminiEmbeddingModel      string
+
+	// Anthropic configs
+	AnthropicAPIKey              string
+	AnthropicModel               string
+	AnthropicModels              []string
+	AnthropicMaxCompletionTokens int
+	AnthropicTemperature         float64
+	AnthropicBaseURL             string
+	AnthropicContextLimit        int
+
+	// Ollama / OpenAI compatible configs
+	OllamaBaseURL             string
+	OllamaAPIKey              string
+	OllamaModel               string
+	OllamaModels              []string
+	OllamaMaxCompletionTokens int
+	OllamaTemperature         float64
+	OllamaContextLimit        int
+	OllamaStructuredOutput    bool
+	OllamaEnabled             bool
+	OllamaEmbeddingModel      string // Empty disables embeddings for the self-hosted provider
+
+	// LLM retry and fallback configs
+	LLMFallbackProviders      []string
+	LLMMaxAttempts            int
+	LLMRetryBaseDelayMs       int
+	LLMRetryMaxDelayMs        int
+	LLMCircuitBreakerFailures int
+	LLMCircuitBreakerCooldown int // in seconds
+
+	// Schema retrieval configs
+	SchemaRetrievalMinTables  int // Schemas with fewer tables are sent to the LLM in full
+	SchemaRetrievalTopK       int
+	SchemaRetrievalEmbeddings bool
+
+	// Agent mode configs
+	AgentMaxSteps      int // Rounds of tool calls before the LLM has to answer
+	AgentQueryRowLimit int // Rows returned to the LLM by a tool call
+
+	// Query auto-fix configs
+	QueryAutoFixAttempts int // Corrections asked to the LLM when an auto-executed read-only query fails
+
+	// Query plan configs
+	QueryExplainAnalyze bool // Allow EXPLAIN ANALYZE, which runs the explained read-only query
+
+	// Query result configs
+	QueryResultBatchSize   int // Rows per streamed result chunk
+	QueryResultMaxRows     int // Default cap of the rows streamed for a query, chats can lower or raise it
+	QueryResultMaxBytes    int // Default cap of the size of the rows streamed for a query, as JSON
+	QueryResultPreviewRows int // Rows of a result stored with the message
+	QueryExportMaxRows     int // Cap of the rows of an exported result
+
+	// Query snapshot configs
+	QuerySnapshotMaxRows int // Cap of the rows saved before an UPDATE/DELETE to roll it back, 0 disables snapshots
 
 	// SMTP Email configs
 	SMTPHost      string
@@ -71,6 +132,13 @@ type Environment struct {
 	SpreadsheetPostgresPassword   string
 	SpreadsheetPostgresSSLMode    string
 	SpreadsheetDataEncryptionKey  string
+
+	// Uploaded database file (SQLite/DuckDB) configs
+	DatabaseFilesDir      string
+	DatabaseFileMaxSizeMB int
+
+	// SSH tunnel configs
+	SSHKnownHostsFile string
 }
 
 var Env Environment
@@ -124,14 +192,75 @@ func LoadEnv() error {
 	// OpenAI configs
 	Env.OpenAIAPIKey = getRequiredEnv("OPENAI_API_KEY", "")
 	Env.OpenAIModel = getEnvWithDefault("OPENAI_MODEL", constants.OpenAIModel)
+	Env.OpenAIModels = getListEnv("OPENAI_MODELS")
 	Env.OpenAIMaxCompletionTokens = getIntEnvWithDefault("OPENAI_MAX_COMPLETION_TOKENS", constants.OpenAIMaxCompletionTokens)
 	Env.OpenAITemperature = getFloatEnvWithDefault("OPENAI_TEMPERATURE", constants.OpenAITemperature)
+	Env.OpenAIContextLimit = getIntEnvWithDefault("OPENAI_CONTEXT_LIMIT", constants.OpenAIContextLimit)
+	Env.OpenAIEmbeddingModel = getEnvWithDefault("OPENAI_EMBEDDING_MODEL", constants.OpenAIEmbeddingModel)
 
 	// Gemini configs
 	Env.GeminiAPIKey = getRequiredEnv("GEMINI_API_KEY", "")
 	Env.GeminiModel = getEnvWithDefault("GEMINI_MODEL", constants.GeminiModel)
+	Env.GeminiModels = getListEnv("GEMINI_MODELS")
 	Env.GeminiMaxCompletionTokens = getIntEnvWithDefault("GEMINI_MAX_COMPLETION_TOKENS", constants.GeminiMaxCompletionTokens)
 	Env.GeminiTemperature = getFloatEnvWithDefault("GEMINI_TEMPERATURE", constants.GeminiTemperature)
+	Env.GeminiContextLimit = getIntEnvWithDefault("GEMINI_CONTEXT_LIMIT", constants.GeminiContextLimit)
+	Env.GeminiEmbeddingModel = getEnvWithDefault("GEMINI_EMBEDDING_MODEL", constants.GeminiEmbeddingModel)
+
+	// Anthropic configs
+	Env.AnthropicAPIKey = getRequiredEnv("ANTHROPIC_API_KEY", "")
+	Env.AnthropicModel = getEnvWithDefault("ANTHROPIC_MODEL", constants.AnthropicModel)
+	Env.AnthropicModels = getListEnv("ANTHROPIC_MODELS")
+	Env.AnthropicMaxCompletionTokens = getIntEnvWithDefault("ANTHROPIC_MAX_COMPLETION_TOKENS", constants.AnthropicMaxCompletionTokens)
+	Env.AnthropicTemperature = getFloatEnvWithDefault("ANTHROPIC_TEMPERATURE", constants.AnthropicTemperature)
+	Env.AnthropicBaseURL = getEnvWithDefault("ANTHROPIC_BASE_URL", constants.AnthropicBaseURL)
+	Env.AnthropicContextLimit = getIntEnvWithDefault("ANTHROPIC_CONTEXT_LIMIT", constants.AnthropicContextLimit)
+
+	// Ollama / OpenAI compatible configs, used when DEFAULT_LLM_CLIENT is ollama or openai-compatible or OLLAMA_BASE_URL is set
+	Env.OllamaEnabled = os.Getenv("OLLAMA_BASE_URL") != "" ||
+		Env.DefaultLLMClient == constants.Ollama || Env.DefaultLLMClient == constants.OpenAICompatible
+	Env.OllamaBaseURL = getEnvWithDefault("OLLAMA_BASE_URL", constants.OllamaBaseURL)
+	Env.OllamaAPIKey = getRequiredEnv("OLLAMA_API_KEY", "")
+	Env.OllamaModel = getEnvWithDefault("OLLAMA_MODEL", constants.OllamaModel)
+	Env.OllamaModels = getListEnv("OLLAMA_MODELS")
+	Env.OllamaMaxCompletionTokens = getIntEnvWithDefault("OLLAMA_MAX_COMPLETION_TOKENS", constants.OllamaMaxCompletionTokens)
+	Env.OllamaTemperature = getFloatEnvWithDefault("OLLAMA_TEMPERATURE", constants.OllamaTemperature)
+	Env.OllamaContextLimit = getIntEnvWithDefault("OLLAMA_CONTEXT_LIMIT", constants.OllamaContextLimit)
+	Env.OllamaStructuredOutput = getEnvWithDefault("OLLAMA_STRUCTURED_OUTPUT", "true") == "true"
+	Env.OllamaEmbeddingModel = getEnvWithDefault("OLLAMA_EMBEDDING_MODEL", "")
+
+	// LLM retry and fallback configs
+	Env.LLMFallbackProviders = getListEnv("LLM_FALLBACK_PROVIDERS")
+	Env.LLMMaxAttempts = getIntEnvWithDefault("LLM_MAX_ATTEMPTS", 3)
+	Env.LLMRetryBaseDelayMs = getIntEnvWithDefault("LLM_RETRY_BASE_DELAY_MS", 500)
+	Env.LLMRetryMaxDelayMs = getIntEnvWithDefault("LLM_RETRY_MAX_DELAY_MS", 8000)
+	Env.LLMCircuitBreakerFailures = getIntEnvWithDefault("LLM_CIRCUIT_BREAKER_FAILURES", 5)
+	Env.LLMCircuitBreakerCooldown = getIntEnvWithDefault("LLM_CIRCUIT_BREAKER_COOLDOWN", 60)
+
+	// Schema retrieval configs
+	Env.SchemaRetrievalMinTables = getIntEnvWithDefault("SCHEMA_RETRIEVAL_MIN_TABLES", 40)
+	Env.SchemaRetrievalTopK = getIntEnvWithDefault("SCHEMA_RETRIEVAL_TOP_K", 15)
+	Env.SchemaRetrievalEmbeddings = getEnvWithDefault("SCHEMA_RETRIEVAL_EMBEDDINGS", "false") == "true"
+
+	// Agent mode configs
+	Env.AgentMaxSteps = getIntEnvWithDefault("AGENT_MAX_STEPS", 6)
+	Env.AgentQueryRowLimit = getIntEnvWithDefault("AGENT_QUERY_ROW_LIMIT", 50)
+
+	// Query auto-fix configs
+	Env.QueryAutoFixAttempts = getIntEnvWithDefault("QUERY_AUTO_FIX_ATTEMPTS", 2)
+
+	// Query plan configs
+	Env.QueryExplainAnalyze = getEnvWithDefault("QUERY_EXPLAIN_ANALYZE", "true") == "true"
+
+	// Query result configs
+	Env.QueryResultBatchSize = getIntEnvWithDefault("QUERY_RESULT_BATCH_SIZE", 500)
+	Env.QueryResultMaxRows = getIntEnvWithDefault("QUERY_RESULT_MAX_ROWS", 100000)
+	Env.QueryResultMaxBytes = getIntEnvWithDefault("QUERY_RESULT_MAX_BYTES", 50*1024*1024)
+	Env.QueryResultPreviewRows = getIntEnvWithDefault("QUERY_RESULT_PREVIEW_ROWS", 50)
+	Env.QueryExportMaxRows = getIntEnvWithDefault("QUERY_EXPORT_MAX_ROWS", 1000000)
+
+	// Query snapshot configs
+	Env.QuerySnapshotMaxRows = getIntEnvWithDefault("QUERY_SNAPSHOT_MAX_ROWS", 10000)
 
 	// SMTP Email configs
 	Env.SMTPHost = getEnvWithDefault("SMTP_HOST", "")
@@ -151,6 +280,13 @@ func LoadEnv() error {
 	Env.SpreadsheetPostgresSSLMode = getEnvWithDefault("SPREADSHEET_POSTGRES_SSL_MODE", "disable")
 	Env.SpreadsheetDataEncryptionKey = getRequiredEnv("SPREADSHEET_DATA_ENCRYPTION_KEY", "spreadsheet_data_key_32bytes")
 
+	// Uploaded database file (SQLite/DuckDB) configs, files are encrypted with the spreadsheet data key
+	Env.DatabaseFilesDir = getEnvWithDefault("DATABASE_FILES_DIR", "./data/database_files")
+	Env.DatabaseFileMaxSizeMB = getIntEnvWithDefault("DATABASE_FILE_MAX_SIZE_MB", 200)
+
+	// SSH tunnel configs, bastion host keys are only verified when a known_hosts file is given
+	Env.SSHKnownHostsFile = getEnvWithDefault("SSH_KNOWN_HOSTS_FILE", "")
+
 	return validateConfig()
 }
 
@@ -183,6 +319,17 @@ func getIntEnvWithDefault(key string, defaultValue int) int {
 	return value
 }
 
+// getListEnv reads a comma separated list, ignoring empty items
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getFloatEnvWithDefault(key string, defaultValue float64) float64 {
 	strValue := os.Getenv(key)
 	if strValue == "" {
@@ -217,6 +364,10 @@ func validateConfig() error {
 		return fmt.Errorf("SPREADSHEET_DATA_ENCRYPTION_KEY must be exactly 32 bytes for AES-GCM encryption, got: %d bytes", len(Env.SpreadsheetDataEncryptionKey))
 	}
 
+	if Env.DatabaseFileMaxSizeMB <= 0 {
+		return fmt.Errorf("DATABASE_FILE_MAX_SIZE_MB must be positive, got: %d", Env.DatabaseFileMaxSizeMB)
+	}
+
 	// Validate SSL mode
 	validSSLModes := map[string]bool{"disable": true, "require": true, "verify-ca": true, "verify-full": true}
 	if !validSSLModes[Env.SpreadsheetPostgresSSLMode] {
@@ -0,0 +1,82 @@
+package dtos
+
+type CreateAlertRuleRequest struct {
+	ScheduledQueryID string         `json:"scheduled_query_id" binding:"required"`
+	Name             string         `json:"name" binding:"required"`
+	Condition        QueryThreshold `json:"condition"`
+	Emails           []string       `json:"emails" binding:"omitempty,max=10,dive,email"` // Defaults to the email of the user when there are no webhooks
+	Webhooks         []string       `json:"webhooks" binding:"omitempty,max=5,dive,url"`
+	CooldownMinutes  *int           `json:"cooldown_minutes"` // Minimum time between two firing notifications, defaults to 60
+	NotifyOnResolve  bool           `json:"notify_on_resolve"`
+}
+
+type UpdateAlertRuleRequest struct {
+	Name            *string         `json:"name"`
+	Condition       *QueryThreshold `json:"condition"`
+	Emails          *[]string       `json:"emails" binding:"omitempty,max=10,dive,email"`
+	Webhooks        *[]string       `json:"webhooks" binding:"omitempty,max=5,dive,url"`
+	CooldownMinutes *int            `json:"cooldown_minutes"`
+	NotifyOnResolve *bool           `json:"notify_on_resolve"`
+	Enabled         *bool           `json:"enabled"`
+	RotateSecret    bool            `json:"rotate_secret"` // Generates a new webhook secret, returned once
+}
+
+type AlertRuleResponse struct {
+	ID               string         `json:"id"`
+	ScheduledQueryID string         `json:"scheduled_query_id"`
+	Name             string         `json:"name"`
+	Condition        QueryThreshold `json:"condition"`
+	Emails           []string       `json:"emails"`
+	Webhooks         []string       `json:"webhooks"`
+	WebhookSecret    *string        `json:"webhook_secret,omitempty"` // Only returned when created or rotated
+	CooldownMinutes  int            `json:"cooldown_minutes"`
+	NotifyOnResolve  bool           `json:"notify_on_resolve"`
+	Enabled          bool           `json:"enabled"`
+	Firing           bool           `json:"firing"`
+	LastValue        *float64       `json:"last_value,omitempty"`
+	LastEvaluatedAt  *string        `json:"last_evaluated_at,omitempty"`
+	LastFiredAt      *string        `json:"last_fired_at,omitempty"`
+	LastNotifiedAt   *string        `json:"last_notified_at,omitempty"`
+	CreatedAt        string         `json:"created_at"`
+	UpdatedAt        string         `json:"updated_at"`
+}
+
+type AlertRuleListResponse struct {
+	Rules []AlertRuleResponse `json:"rules"`
+	Total int64               `json:"total"`
+}
+
+type AlertDelivery struct {
+	Channel    string `json:"channel"`
+	Target     string `json:"target"`
+	Success    bool   `json:"success"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Attempts   int    `json:"attempts"`
+	Error      string `json:"error,omitempty"`
+}
+
+type AlertEventResponse struct {
+	ID               string          `json:"id"`
+	RuleID           string          `json:"rule_id"`
+	ScheduledQueryID string          `json:"scheduled_query_id"`
+	SnapshotID       string          `json:"snapshot_id"`
+	RuleName         string          `json:"rule_name"`
+	Status           string          `json:"status"`
+	Condition        QueryThreshold  `json:"condition"`
+	Actual           *float64        `json:"actual,omitempty"`
+	Previous         *float64        `json:"previous,omitempty"`
+	Notified         bool            `json:"notified"`
+	Suppressed       string          `json:"suppressed,omitempty"`
+	Deliveries       []AlertDelivery `json:"deliveries"`
+	CreatedAt        string          `json:"created_at"`
+}
+
+type AlertEventListResponse struct {
+	Events []AlertEventResponse `json:"events"`
+	Total  int64                `json:"total"`
+}
+
+// TestAlertRuleResponse holds the deliveries of a test notification of an alert rule
+type TestAlertRuleResponse struct {
+	Deliveries []AlertDelivery `json:"deliveries"`
+}
@@ -1,23 +1,35 @@
 package dtos
 
 type CreateChatSettings struct {
-	AutoExecuteQuery *bool `json:"auto_execute_query"`
-	ShareDataWithAI  *bool `json:"share_data_with_ai"`
-	NonTechMode      *bool `json:"non_tech_mode"`
+	AutoExecuteQuery *bool   `json:"auto_execute_query"`
+	ShareDataWithAI  *bool   `json:"share_data_with_ai"`
+	NonTechMode      *bool   `json:"non_tech_mode"`
+	LLMProvider      *string `json:"llm_provider"` // Empty string resets to the default provider
+	LLMModel         *string `json:"llm_model"`    // Empty string resets to the provider's default model
+	AgentMode        *bool   `json:"agent_mode"`
+	ReadOnly         *bool   `json:"read_only"`
+	MaxResultRows    *int    `json:"max_result_rows"`  // 0 resets to the server default
+	MaxResultBytes   *int    `json:"max_result_bytes"` // 0 resets to the server default
 }
 
 type ChatSettingsResponse struct {
-	AutoExecuteQuery bool `json:"auto_execute_query"`
-	ShareDataWithAI  bool `json:"share_data_with_ai"`
-	NonTechMode      bool `json:"non_tech_mode"`
+	AutoExecuteQuery bool   `json:"auto_execute_query"`
+	ShareDataWithAI  bool   `json:"share_data_with_ai"`
+	NonTechMode      bool   `json:"non_tech_mode"`
+	LLMProvider      string `json:"llm_provider,omitempty"`
+	LLMModel         string `json:"llm_model,omitempty"`
+	AgentMode        bool   `json:"agent_mode"`
+	ReadOnly         bool   `json:"read_only"`
+	MaxResultRows    int    `json:"max_result_rows"`
+	MaxResultBytes   int    `json:"max_result_bytes"`
 }
 type CreateConnectionRequest struct {
-	Type         string  `json:"type" binding:"required,oneof=postgresql yugabytedb mysql clickhouse mongodb redis neo4j cassandra spreadsheet"`
-	Host         string  `json:"host" binding:"required_unless=Type spreadsheet"`
+	Type         string  `json:"type" binding:"required,oneof=postgresql yugabytedb mysql mssql clickhouse mongodb redis neo4j cassandra spreadsheet sqlite duckdb"`
+	Host         string  `json:"host"` // Required for server databases, validated by the chat service
 	Port         *string `json:"port"`
-	Username     string  `json:"username" binding:"required_unless=Type spreadsheet"`
+	Username     string  `json:"username"`
 	Password     *string `json:"password"`
-	Database     string  `json:"database" binding:"required_unless=Type spreadsheet"`
+	Database     string  `json:"database"`
 	AuthDatabase *string `json:"auth_database,omitempty"` // Database to authenticate against (for MongoDB)
 
 	// SSL/TLS Configuration
@@ -26,6 +38,16 @@ type CreateConnectionRequest struct {
 	SSLCertURL     *string `json:"ssl_cert_url,omitempty"`
 	SSLKeyURL      *string `json:"ssl_key_url,omitempty"`
 	SSLRootCertURL *string `json:"ssl_root_cert_url,omitempty"`
+
+	// SSH Tunnel Configuration
+	SSHEnabled     bool    `json:"ssh_enabled"`
+	SSHHost        *string `json:"ssh_host,omitempty"`
+	SSHPort        *string `json:"ssh_port,omitempty"`
+	SSHUsername    *string `json:"ssh_username,omitempty"`
+	SSHPrivateKey  *string `json:"ssh_private_key,omitempty"`
+	SSHPassphrase  *string `json:"ssh_passphrase,omitempty"`          // passphrase of the private key, or the SSH password when no key is given
+	SSHHostKey     *string `json:"ssh_host_key,omitempty"`            // public key of the bastion (known_hosts format) or its SHA256 fingerprint
+	SSHSkipHostKey bool    `json:"ssh_skip_host_key_check,omitempty"` // accept any bastion host key, open to man-in-the-middle attacks
 }
 
 type ConnectionResponse struct {
@@ -44,6 +66,14 @@ type ConnectionResponse struct {
 	SSLCertURL     *string `json:"ssl_cert_url,omitempty"`
 	SSLKeyURL      *string `json:"ssl_key_url,omitempty"`
 	SSLRootCertURL *string `json:"ssl_root_cert_url,omitempty"`
+
+	// SSH Tunnel Configuration, the private key and passphrase are never returned
+	SSHEnabled     bool    `json:"ssh_enabled"`
+	SSHHost        *string `json:"ssh_host,omitempty"`
+	SSHPort        *string `json:"ssh_port,omitempty"`
+	SSHUsername    *string `json:"ssh_username,omitempty"`
+	SSHHostKey     *string `json:"ssh_host_key,omitempty"`
+	SSHSkipHostKey bool    `json:"ssh_skip_host_key_check,omitempty"`
 }
 
 type CreateChatRequest struct {
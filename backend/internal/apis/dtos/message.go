@@ -23,6 +23,7 @@ type MessageResponse struct {
 	NonTechMode   bool            `json:"non_tech_mode"` // Whether this message was generated in non-tech mode
 	IsPinned      bool            `json:"is_pinned"`     // Whether this message is pinned
 	PinnedAt      *string         `json:"pinned_at,omitempty"` // When the message was pinned
+	LLMMetadata   *models.LLMMetadata `json:"llm_metadata,omitempty"` // Which LLM provider generated the message
 	CreatedAt     string          `json:"created_at"`
 	UpdatedAt     string          `json:"updated_at"`
 }
@@ -54,7 +55,38 @@ type Query struct {
 	RollbackDependentQuery *string                `json:"rollback_dependent_query,omitempty"`
 	Pagination             *Pagination            `json:"pagination,omitempty"`
 	IsEdited               bool                   `json:"is_edited"`
-	ActionAt               *string                `json:"action_at,omitempty"` // The timestamp when the action was taken
+	ActionAt               *string                `json:"action_at,omitempty"`      // The timestamp when the action was taken
+	OriginalQuery          *string                `json:"original_query,omitempty"` // The query of the LLM before it was auto-fixed
+	FixAttempts            []QueryFixAttempt      `json:"fix_attempts,omitempty"`
+	SafetyWarnings         []string               `json:"safety_warnings,omitempty"` // Why the query needs confirmation, e.g. a DELETE without WHERE
+	ResultHandle           *QueryResultHandle     `json:"result_handle,omitempty"`   // Set when the result was streamed, ExecutionResult then only holds its preview
+	RollbackSnapshot       *QuerySnapshotInfo     `json:"rollback_snapshot,omitempty"` // Set when the rows the query changed were saved, it can then be rolled back without a rollback query
+}
+
+type QuerySnapshotInfo struct {
+	Operation  string `json:"operation"`
+	Table      string `json:"table"`
+	RowCount   int    `json:"row_count"`
+	CapturedAt string `json:"captured_at"`
+}
+
+type QueryResultHandle struct {
+	ID            string   `json:"id"`
+	ExecutedQuery string   `json:"executed_query"`
+	Columns       []string `json:"columns,omitempty"`
+	RowCount      int64    `json:"row_count"`
+	ByteCount     int64    `json:"byte_count"`
+	PreviewRows   int      `json:"preview_rows"`
+	Truncated     bool     `json:"truncated"`
+	TruncatedBy   string   `json:"truncated_by,omitempty"`
+	ExecutedAt    string   `json:"executed_at"`
+}
+
+type QueryFixAttempt struct {
+	Query       string     `json:"query"`
+	Error       QueryError `json:"error"`
+	Explanation string     `json:"explanation"`
+	AttemptedAt string     `json:"attempted_at"`
 }
 
 type Pagination struct {
@@ -164,11 +196,34 @@ func ToQueryDtoWithDecryption(queries *[]models.Query, decryptFunc func(string)
 			Pagination:             pagination,
 			IsEdited:               query.IsEdited,
 			ActionAt:               query.ActionAt,
+			OriginalQuery:          query.OriginalQuery,
+			FixAttempts:            ToQueryFixAttemptsDto(query.FixAttempts),
+			SafetyWarnings:         query.SafetyWarnings,
+			ResultHandle:           (*QueryResultHandle)(query.ResultHandle),
+			RollbackSnapshot:       (*QuerySnapshotInfo)(query.RollbackSnapshotInfo),
 		}
 	}
 	return &queriesDto
 }
 
+// ToQueryFixAttemptsDto converts model query fix attempts to DTO query fix attempts
+func ToQueryFixAttemptsDto(attempts []models.QueryFixAttempt) []QueryFixAttempt {
+	if len(attempts) == 0 {
+		return nil
+	}
+
+	attemptsDto := make([]QueryFixAttempt, len(attempts))
+	for i, attempt := range attempts {
+		attemptsDto[i] = QueryFixAttempt{
+			Query:       attempt.Query,
+			Error:       QueryError(attempt.Error),
+			Explanation: attempt.Explanation,
+			AttemptedAt: attempt.AttemptedAt,
+		}
+	}
+	return attemptsDto
+}
+
 // ToActionButtonDto converts model action buttons to DTO action buttons
 func ToActionButtonDto(actionButtons *[]models.ActionButton) *[]ActionButton {
 	log.Printf("ToActionButtonDto -> input actionButtons: %+v", actionButtons)
@@ -7,11 +7,39 @@ type ExecuteQueryRequest struct {
 }
 
 type RollbackQueryRequest struct {
+	MessageID   string `json:"message_id" binding:"required"`
+	QueryID     string `json:"query_id" binding:"required"`
+	StreamID    string `json:"stream_id" binding:"required"`
+	UseSnapshot *bool  `json:"use_snapshot"` // Write back the rows saved when the query ran, default when the query has a snapshot
+}
+
+type ExecuteAllQueriesRequest struct {
 	MessageID string `json:"message_id" binding:"required"`
-	QueryID   string `json:"query_id" binding:"required"`
 	StreamID  string `json:"stream_id" binding:"required"`
 }
 
+// ExecutionPlanStep is the progress of a query run by ExecuteAllQueries, also sent as an execution-plan-step event
+type ExecutionPlanStep struct {
+	ChatID          string      `json:"chat_id"`
+	MessageID       string      `json:"message_id"`
+	QueryID         string      `json:"query_id"`
+	Index           int         `json:"index"`
+	Total           int         `json:"total"`
+	Status          string      `json:"status"` // running, succeeded or failed
+	ExecutionTime   *int        `json:"execution_time,omitempty"`
+	ExecutionResult interface{} `json:"execution_result,omitempty"`
+	Error           *QueryError `json:"error,omitempty"`
+}
+
+type ExecuteAllQueriesResponse struct {
+	ChatID        string              `json:"chat_id"`
+	MessageID     string              `json:"message_id"`
+	Status        string              `json:"status"` // committed or rolled_back
+	Steps         []ExecutionPlanStep `json:"steps"`
+	Error         *QueryError         `json:"error,omitempty"`
+	ActionButtons *[]ActionButton     `json:"action_buttons,omitempty"`
+}
+
 type CancelQueryExecutionRequest struct {
 	MessageID string `json:"message_id" binding:"required"`
 	QueryID   string `json:"query_id" binding:"required"`
@@ -19,17 +47,18 @@ type CancelQueryExecutionRequest struct {
 }
 
 type QueryExecutionResponse struct {
-	ChatID            string          `json:"chat_id"`
-	MessageID         string          `json:"message_id"`
-	QueryID           string          `json:"query_id"`
-	IsExecuted        bool            `json:"is_executed"`
-	IsRolledBack      bool            `json:"is_rolled_back"`
-	ExecutionTime     *int            `json:"execution_time"`
-	ExecutionResult   interface{}     `json:"execution_result"`
-	Error             *QueryError     `json:"error,omitempty"`
-	TotalRecordsCount *int            `json:"total_records_count"`
-	ActionButtons     *[]ActionButton `json:"action_buttons,omitempty"`
-	ActionAt          *string         `json:"action_at,omitempty"`
+	ChatID            string             `json:"chat_id"`
+	MessageID         string             `json:"message_id"`
+	QueryID           string             `json:"query_id"`
+	IsExecuted        bool               `json:"is_executed"`
+	IsRolledBack      bool               `json:"is_rolled_back"`
+	ExecutionTime     *int               `json:"execution_time"`
+	ExecutionResult   interface{}        `json:"execution_result"`
+	Error             *QueryError        `json:"error,omitempty"`
+	TotalRecordsCount *int               `json:"total_records_count"`
+	ActionButtons     *[]ActionButton    `json:"action_buttons,omitempty"`
+	ActionAt          *string            `json:"action_at,omitempty"`
+	ResultHandle      *QueryResultHandle `json:"result_handle,omitempty"`
 }
 
 type QueryResultsRequest struct {
@@ -57,9 +86,49 @@ type EditQueryRequest struct {
 }
 
 type EditQueryResponse struct {
-	ChatID    string `json:"chat_id"`
-	MessageID string `json:"message_id"`
-	QueryID   string `json:"query_id"`
-	Query     string `json:"query"`
-	IsEdited  bool   `json:"is_edited"`
+	ChatID         string   `json:"chat_id"`
+	MessageID      string   `json:"message_id"`
+	QueryID        string   `json:"query_id"`
+	Query          string   `json:"query"`
+	IsEdited       bool     `json:"is_edited"`
+	IsCritical     bool     `json:"is_critical"`
+	SafetyWarnings []string `json:"safety_warnings,omitempty"`
+}
+
+type ExplainQueryRequest struct {
+	MessageID string `json:"message_id" binding:"required"`
+	QueryID   string `json:"query_id" binding:"required"`
+	StreamID  string `json:"stream_id" binding:"required"`
+	Analyze   bool   `json:"analyze"` // Run the query to get actual rows and timings, only for read-only queries
+}
+
+// QueryPlanNode is a step of a query plan, normalized across databases
+type QueryPlanNode struct {
+	Operation    string           `json:"operation"`
+	Relation     string           `json:"relation,omitempty"` // Table or collection read by the step
+	Index        string           `json:"index,omitempty"`    // Index used by the step
+	FullScan     bool             `json:"full_scan,omitempty"`
+	Cost         *float64         `json:"cost,omitempty"` // Estimated cost, in the database's own unit
+	Rows         *float64         `json:"rows,omitempty"` // Estimated rows
+	ActualRows   *float64         `json:"actual_rows,omitempty"`
+	ActualTimeMs *float64         `json:"actual_time_ms,omitempty"`
+	Details      []string         `json:"details,omitempty"` // Conditions, sort keys etc.
+	Children     []*QueryPlanNode `json:"children,omitempty"`
+}
+
+type QueryPlan struct {
+	Root        *QueryPlanNode `json:"root"`
+	Analyzed    bool           `json:"analyzed"`
+	IndexesUsed []string       `json:"indexes_used"`
+	FullScans   []string       `json:"full_scans"` // Relations read without an index
+	Raw         string         `json:"raw"`        // Plan as returned by the database
+}
+
+type ExplainQueryResponse struct {
+	ChatID           string     `json:"chat_id"`
+	MessageID        string     `json:"message_id"`
+	QueryID          string     `json:"query_id"`
+	Query            string     `json:"query"`
+	Plan             *QueryPlan `json:"plan"`
+	IndexSuggestions string     `json:"index_suggestions,omitempty"`
 }
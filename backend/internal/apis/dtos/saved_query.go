@@ -0,0 +1,85 @@
+package dtos
+
+type SavedQueryParam struct {
+	Name        string      `json:"name" binding:"required"`
+	Type        string      `json:"type" binding:"required"` // string, integer, number, boolean, date or timestamp
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+type CreateSavedQueryRequest struct {
+	ChatID      string            `json:"chat_id" binding:"required"` // Chat whose connection the query targets
+	Name        string            `json:"name" binding:"required"`
+	Description string            `json:"description"`
+	Tags        []string          `json:"tags"`
+	Query       string            `json:"query" binding:"required"`
+	QueryType   *string           `json:"query_type"`
+	Params      []SavedQueryParam `json:"params"`
+}
+
+type UpdateSavedQueryRequest struct {
+	ChatID      *string            `json:"chat_id"`
+	Name        *string            `json:"name"`
+	Description *string            `json:"description"`
+	Tags        *[]string          `json:"tags"`
+	Query       *string            `json:"query"`
+	QueryType   *string            `json:"query_type"`
+	Params      *[]SavedQueryParam `json:"params"`
+}
+
+// SaveMessageQueryRequest saves the query of a message to the library, the name defaults to its description
+type SaveMessageQueryRequest struct {
+	ChatID      string            `json:"chat_id" binding:"required"`
+	MessageID   string            `json:"message_id" binding:"required"`
+	QueryID     string            `json:"query_id" binding:"required"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Tags        []string          `json:"tags"`
+	Params      []SavedQueryParam `json:"params"`
+}
+
+// SavedQueryFilter holds the query parameters of the list of saved queries
+type SavedQueryFilter struct {
+	ChatID string `form:"chat_id"`
+	Tag    string `form:"tag"`
+	Search string `form:"search"` // Matched against the name and the description
+}
+
+type RunSavedQueryRequest struct {
+	ChatID   string                 `json:"chat_id"` // Defaults to the chat the query was saved for
+	StreamID string                 `json:"stream_id" binding:"required"`
+	Params   map[string]interface{} `json:"params"`
+	Confirm  bool                   `json:"confirm"` // Required to run a query that may change data
+}
+
+type SavedQueryResponse struct {
+	ID              string            `json:"id"`
+	ChatID          string            `json:"chat_id"`
+	ConnectionType  string            `json:"connection_type"`
+	Name            string            `json:"name"`
+	Description     string            `json:"description"`
+	Tags            []string          `json:"tags"`
+	Query           string            `json:"query"`
+	QueryType       *string           `json:"query_type,omitempty"`
+	Params          []SavedQueryParam `json:"params"`
+	SourceMessageID *string           `json:"source_message_id,omitempty"`
+	SourceQueryID   *string           `json:"source_query_id,omitempty"`
+	LastRunAt       *string           `json:"last_run_at,omitempty"`
+	CreatedAt       string            `json:"created_at"`
+	UpdatedAt       string            `json:"updated_at"`
+}
+
+type SavedQueryListResponse struct {
+	SavedQueries []SavedQueryResponse `json:"saved_queries"`
+	Total        int64                `json:"total"`
+}
+
+type RunSavedQueryResponse struct {
+	SavedQueryID    string      `json:"saved_query_id"`
+	ChatID          string      `json:"chat_id"`
+	Query           string      `json:"query"` // The query that ran, with its parameters bound
+	ExecutionTime   *int        `json:"execution_time"`
+	ExecutionResult interface{} `json:"execution_result"`
+	Error           *QueryError `json:"error,omitempty"`
+}
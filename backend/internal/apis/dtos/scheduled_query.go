@@ -0,0 +1,84 @@
+package dtos
+
+type QueryThreshold struct {
+	Metric   string  `json:"metric" binding:"required"`   // row_count, or value of column in the first row
+	Column   string  `json:"column,omitempty"`            // Column read by the value metric
+	Operator string  `json:"operator" binding:"required"` // gt, gte, lt, lte, eq, ne, change or change_pct
+	Value    float64 `json:"value"`
+}
+
+// CreateScheduledQueryRequest schedules a saved query, or the query of a message when saved_query_id is empty
+type CreateScheduledQueryRequest struct {
+	ChatID         string                 `json:"chat_id" binding:"required"` // Chat whose connection the query runs against
+	Name           string                 `json:"name"`
+	SavedQueryID   string                 `json:"saved_query_id"`
+	Params         map[string]interface{} `json:"params"` // Values of the parameters of the saved query
+	MessageID      string                 `json:"message_id"`
+	QueryID        string                 `json:"query_id"`
+	CronExpression string                 `json:"cron_expression" binding:"required"`
+	Timezone       string                 `json:"timezone"` // IANA name, defaults to UTC
+	Thresholds     []QueryThreshold       `json:"thresholds"`
+}
+
+type UpdateScheduledQueryRequest struct {
+	Name           *string                 `json:"name"`
+	Params         *map[string]interface{} `json:"params"`
+	CronExpression *string                 `json:"cron_expression"`
+	Timezone       *string                 `json:"timezone"`
+	Enabled        *bool                   `json:"enabled"`
+	Thresholds     *[]QueryThreshold       `json:"thresholds"`
+}
+
+type ScheduledQueryResponse struct {
+	ID              string                 `json:"id"`
+	ChatID          string                 `json:"chat_id"`
+	Name            string                 `json:"name"`
+	Query           string                 `json:"query"`
+	QueryType       *string                `json:"query_type,omitempty"`
+	SavedQueryID    *string                `json:"saved_query_id,omitempty"`
+	Params          map[string]interface{} `json:"params,omitempty"`
+	SourceMessageID *string                `json:"source_message_id,omitempty"`
+	SourceQueryID   *string                `json:"source_query_id,omitempty"`
+	CronExpression  string                 `json:"cron_expression"`
+	Timezone        string                 `json:"timezone"`
+	Enabled         bool                   `json:"enabled"`
+	Thresholds      []QueryThreshold       `json:"thresholds"`
+	NextRunAt       *string                `json:"next_run_at,omitempty"`
+	LastRunAt       *string                `json:"last_run_at,omitempty"`
+	LastStatus      string                 `json:"last_status,omitempty"`
+	CreatedAt       string                 `json:"created_at"`
+	UpdatedAt       string                 `json:"updated_at"`
+}
+
+type ScheduledQueryListResponse struct {
+	ScheduledQueries []ScheduledQueryResponse `json:"scheduled_queries"`
+	Total            int64                    `json:"total"`
+}
+
+type ThresholdResult struct {
+	QueryThreshold
+	Actual   *float64 `json:"actual,omitempty"`
+	Previous *float64 `json:"previous,omitempty"` // Metric of the previous snapshot, for the change operators
+	Breached bool     `json:"breached"`
+	Error    string   `json:"error,omitempty"`
+}
+
+type ScheduledQuerySnapshotResponse struct {
+	ID               string            `json:"id"`
+	ScheduledQueryID string            `json:"scheduled_query_id"`
+	RanAt            string            `json:"ran_at"`
+	Status           string            `json:"status"`
+	Query            string            `json:"query"`
+	ExecutionTime    *int              `json:"execution_time,omitempty"`
+	RowCount         int               `json:"row_count"`
+	Truncated        bool              `json:"truncated"`
+	Result           interface{}       `json:"result,omitempty"`
+	Error            *QueryError       `json:"error,omitempty"`
+	Thresholds       []ThresholdResult `json:"thresholds"`
+	Breached         bool              `json:"breached"`
+}
+
+type ScheduledQuerySnapshotListResponse struct {
+	Snapshots []ScheduledQuerySnapshotResponse `json:"snapshots"`
+	Total     int64                            `json:"total"`
+}
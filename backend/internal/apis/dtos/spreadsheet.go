@@ -27,4 +27,11 @@ type SpreadsheetDownloadResponse struct {
 	TableName string                   `json:"table_name"`
 	Columns   []string                 `json:"columns"`
 	Rows      []map[string]interface{} `json:"rows"`
-}
\ No newline at end of file
+}
+// DatabaseFileUploadResponse represents the response after uploading a SQLite/DuckDB file
+type DatabaseFileUploadResponse struct {
+	Type       string    `json:"type"`
+	FileName   string    `json:"file_name"`
+	SizeBytes  int64     `json:"size_bytes"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
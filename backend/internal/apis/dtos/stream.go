@@ -1,6 +1,26 @@
 package dtos
 
 type StreamResponse struct {
-	Event string      `json:"event"` // ai-response, ai-response-step, ai-response-error, db-connected, db-disconnected, sse-connected, response-cancelled, query-results, rollback-executed, rollback-query-failed
+	Event string      `json:"event"` // ai-response, ai-response-step, ai-response-delta, ai-response-error, db-connected, db-disconnected, sse-connected, response-cancelled, query-results, query-result-chunk, rollback-executed, rollback-query-failed
 	Data  interface{} `json:"data,omitempty"`
 }
+
+// StreamDelta is the data of an ai-response-delta event, a part of the response while it is generated
+type StreamDelta struct {
+	Type  string                 `json:"type"`            // message, query or reset
+	Text  string                 `json:"text,omitempty"`  // Next part of the assistant message
+	Index int                    `json:"index"`           // Position of the query in the response
+	Query map[string]interface{} `json:"query,omitempty"` // Query as generated, before it is analyzed
+}
+
+// QueryResultChunk is the data of a query-result-chunk event, a batch of the rows of a streamed result
+type QueryResultChunk struct {
+	ChatID    string                   `json:"chat_id"`
+	MessageID string                   `json:"message_id"`
+	QueryID   string                   `json:"query_id"`
+	HandleID  string                   `json:"handle_id"` // ID of the result handle stored with the query
+	Index     int                      `json:"index"`     // Position of the batch in the result
+	Offset    int64                    `json:"offset"`    // Position of the first row of the batch in the result
+	Columns   []string                 `json:"columns,omitempty"`
+	Rows      []map[string]interface{} `json:"rows"`
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/services"
+	"neobase-ai/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AlertHandler struct {
+	alertService services.AlertService
+}
+
+func NewAlertHandler(alertService services.AlertService) *AlertHandler {
+	return &AlertHandler{
+		alertService: alertService,
+	}
+}
+
+// @Summary Create an alert rule
+// @Description Create an alert rule on a scheduled query, notifying its emails and signed webhooks when its condition starts and stops holding
+// @Accept json
+// @Produce json
+// @Param createAlertRuleRequest body dtos.CreateAlertRuleRequest true "Create alert rule request"
+
+func (h *AlertHandler) CreateRule(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req dtos.CreateAlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	response, status, err := h.alertService.CreateRule(userID, &req)
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// @Summary List alert rules
+// @Description List the alert rules of the user, only those of a scheduled query when scheduled_query_id is given
+// @Accept json
+// @Produce json
+
+func (h *AlertHandler) ListRules(c *gin.Context) {
+	userID := c.GetString("userID")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	response, status, err := h.alertService.ListRules(userID, c.Query("scheduled_query_id"), page, pageSize)
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// @Summary Get alert rule by ID
+// @Description Get an alert rule by its ID
+// @Accept json
+// @Produce json
+// @Param id path string true "Alert rule ID"
+
+func (h *AlertHandler) GetRule(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	response, status, err := h.alertService.GetRule(userID, c.Param("id"))
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// @Summary Update an alert rule
+// @Description Update the condition, recipients or cooldown of an alert rule, enable and disable it, or rotate its webhook secret
+// @Accept json
+// @Produce json
+// @Param id path string true "Alert rule ID"
+
+func (h *AlertHandler) UpdateRule(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req dtos.UpdateAlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	response, status, err := h.alertService.UpdateRule(userID, c.Param("id"), &req)
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// @Summary Delete an alert rule
+// @Description Delete an alert rule and its history
+// @Accept json
+// @Produce json
+// @Param id path string true "Alert rule ID"
+
+func (h *AlertHandler) DeleteRule(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	status, err := h.alertService.DeleteRule(userID, c.Param("id"))
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    "Alert rule deleted successfully",
+	})
+}
+
+// @Summary Test an alert rule
+// @Description Send a test notification to the emails and webhooks of an alert rule
+// @Accept json
+// @Produce json
+// @Param id path string true "Alert rule ID"
+
+func (h *AlertHandler) TestRule(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	response, status, err := h.alertService.TestRule(userID, c.Param("id"))
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// @Summary List the alert history
+// @Description List the alert events of the user, the latest first, filtered by rule_id, scheduled_query_id and status when given
+// @Accept json
+// @Produce json
+
+func (h *AlertHandler) ListHistory(c *gin.Context) {
+	userID := c.GetString("userID")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	response, status, err := h.alertService.ListEvents(userID, c.Query("rule_id"), c.Query("scheduled_query_id"), c.Query("status"), page, pageSize)
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
@@ -768,6 +768,40 @@ func (h *ChatHandler) RollbackQuery(c *gin.Context) {
 	})
 }
 
+// @Summary Execute all queries
+// @Description Execute the queries of a message in order in a single transaction, committed only if all of them succeed
+// @Accept json
+// @Produce json
+// @Param id path string true "Chat ID"
+
+func (h *ChatHandler) ExecuteAllQueries(c *gin.Context) {
+	userID := c.GetString("userID")
+	chatID := c.Param("id")
+
+	var req dtos.ExecuteAllQueriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	response, status, err := h.chatService.ExecuteAllQueries(c.Request.Context(), userID, chatID, &req)
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
 // @Summary Cancel query execution
 // @Description Cancel a query execution
 // @Accept json
@@ -948,6 +982,71 @@ func (h *ChatHandler) EditQuery(c *gin.Context) {
 	})
 }
 
+// @Summary Explain query
+// @Description Get the execution plan of a query with index suggestions, ANALYZE runs read-only queries to get actual rows and timings
+// @Accept json
+// @Produce json
+// @Param id path string true "Chat ID"
+
+func (h *ChatHandler) ExplainQuery(c *gin.Context) {
+	userID := c.GetString("userID")
+	chatID := c.Param("id")
+	var req dtos.ExplainQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	response, status, err := h.chatService.ExplainQuery(c.Request.Context(), userID, chatID, &req)
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// ExportQueryResult streams the result of a read query as a csv, jsonl, xlsx or parquet file
+func (h *ChatHandler) ExportQueryResult(c *gin.Context) {
+	userID := c.GetString("userID")
+	chatID := c.Param("id")
+	queryID := c.Param("queryId")
+	format := c.DefaultQuery("format", "csv")
+
+	export, status, err := h.chatService.ExportQueryResult(c.Request.Context(), userID, chatID, queryID, format, c.Query("stream_id"))
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.Header("Content-Type", export.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", export.FileName))
+	if err := export.Write(c.Writer); err != nil {
+		log.Printf("ChatHandler -> ExportQueryResult -> Error exporting query %s: %v", queryID, err)
+		// The error can still be returned as JSON until the first rows are written
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			c.JSON(http.StatusBadRequest, dtos.Response{
+				Success: false,
+				Error:   utils.ToStringPtr(err.Error()),
+			})
+		}
+	}
+}
+
 // @Summary Get tables
 // @Description Get all tables with their columns for a specific chat, marking which ones are selected
 // @Accept json
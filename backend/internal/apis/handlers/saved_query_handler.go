@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/services"
+	"neobase-ai/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SavedQueryHandler struct {
+	savedQueryService services.SavedQueryService
+}
+
+func NewSavedQueryHandler(savedQueryService services.SavedQueryService) *SavedQueryHandler {
+	return &SavedQueryHandler{
+		savedQueryService: savedQueryService,
+	}
+}
+
+// @Summary Save a query
+// @Description Save a query with its parameters to the library of the user, for the connection of a chat
+// @Accept json
+// @Produce json
+// @Param createSavedQueryRequest body dtos.CreateSavedQueryRequest true "Create saved query request"
+
+func (h *SavedQueryHandler) Create(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req dtos.CreateSavedQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	response, status, err := h.savedQueryService.Create(userID, &req)
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// @Summary Save the query of a message
+// @Description Save a query of a message to the library of the user, for the connection of its chat
+// @Accept json
+// @Produce json
+// @Param saveMessageQueryRequest body dtos.SaveMessageQueryRequest true "Save message query request"
+
+func (h *SavedQueryHandler) SaveFromMessage(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req dtos.SaveMessageQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	response, status, err := h.savedQueryService.SaveFromMessage(userID, &req)
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// @Summary List saved queries
+// @Description List the saved queries of the user, filtered by chat, tag or a search of their name and description
+// @Accept json
+// @Produce json
+// @Param chat_id query string false "Chat ID"
+// @Param tag query string false "Tag"
+// @Param search query string false "Search"
+
+func (h *SavedQueryHandler) List(c *gin.Context) {
+	userID := c.GetString("userID")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	var filter dtos.SavedQueryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	response, status, err := h.savedQueryService.List(userID, filter, page, pageSize)
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// @Summary Get saved query by ID
+// @Description Get a saved query by its ID
+// @Accept json
+// @Produce json
+// @Param id path string true "Saved query ID"
+
+func (h *SavedQueryHandler) GetByID(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	response, status, err := h.savedQueryService.GetByID(userID, c.Param("id"))
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// @Summary Update a saved query
+// @Description Update the fields of a saved query, the parameters are checked against the query again
+// @Accept json
+// @Produce json
+// @Param id path string true "Saved query ID"
+
+func (h *SavedQueryHandler) Update(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req dtos.UpdateSavedQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	response, status, err := h.savedQueryService.Update(userID, c.Param("id"), &req)
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// @Summary Delete a saved query
+// @Description Delete a saved query
+// @Accept json
+// @Produce json
+// @Param id path string true "Saved query ID"
+
+func (h *SavedQueryHandler) Delete(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	status, err := h.savedQueryService.Delete(userID, c.Param("id"))
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    "Saved query deleted successfully",
+	})
+}
+
+// @Summary Run a saved query
+// @Description Bind the parameters of a saved query and run it against the connection of a chat
+// @Accept json
+// @Produce json
+// @Param id path string true "Saved query ID"
+
+func (h *SavedQueryHandler) Run(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req dtos.RunSavedQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	response, status, err := h.savedQueryService.Run(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
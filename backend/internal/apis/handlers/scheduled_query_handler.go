@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/services"
+	"neobase-ai/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduledQueryHandler struct {
+	scheduledQueryService services.ScheduledQueryService
+}
+
+func NewScheduledQueryHandler(scheduledQueryService services.ScheduledQueryService) *ScheduledQueryHandler {
+	return &ScheduledQueryHandler{
+		scheduledQueryService: scheduledQueryService,
+	}
+}
+
+// @Summary Schedule a query
+// @Description Schedule a read-only saved query, or the query of a message, on a cron expression against the connection of a chat
+// @Accept json
+// @Produce json
+// @Param createScheduledQueryRequest body dtos.CreateScheduledQueryRequest true "Create scheduled query request"
+
+func (h *ScheduledQueryHandler) Create(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req dtos.CreateScheduledQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	response, status, err := h.scheduledQueryService.Create(userID, &req)
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// @Summary List scheduled queries
+// @Description List the scheduled queries of the user
+// @Accept json
+// @Produce json
+
+func (h *ScheduledQueryHandler) List(c *gin.Context) {
+	userID := c.GetString("userID")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	response, status, err := h.scheduledQueryService.List(userID, page, pageSize)
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// @Summary Get scheduled query by ID
+// @Description Get a scheduled query by its ID
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheduled query ID"
+
+func (h *ScheduledQueryHandler) GetByID(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	response, status, err := h.scheduledQueryService.GetByID(userID, c.Param("id"))
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// @Summary Update a scheduled query
+// @Description Update the schedule, thresholds or parameters of a scheduled query, or enable and disable it
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheduled query ID"
+
+func (h *ScheduledQueryHandler) Update(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req dtos.UpdateScheduledQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	response, status, err := h.scheduledQueryService.Update(userID, c.Param("id"), &req)
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// @Summary Delete a scheduled query
+// @Description Delete a scheduled query and its snapshots
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheduled query ID"
+
+func (h *ScheduledQueryHandler) Delete(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	status, err := h.scheduledQueryService.Delete(userID, c.Param("id"))
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    "Scheduled query deleted successfully",
+	})
+}
+
+// @Summary List the snapshots of a scheduled query
+// @Description List the results of the runs of a scheduled query with their thresholds, the latest first
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheduled query ID"
+
+func (h *ScheduledQueryHandler) ListSnapshots(c *gin.Context) {
+	userID := c.GetString("userID")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	response, status, err := h.scheduledQueryService.ListSnapshots(userID, c.Param("id"), page, pageSize)
+	if err != nil {
+		c.JSON(int(status), dtos.Response{
+			Success: false,
+			Error:   utils.ToStringPtr(err.Error()),
+		})
+		return
+	}
+
+	c.JSON(int(status), dtos.Response{
+		Success: true,
+		Data:    response,
+	})
+}
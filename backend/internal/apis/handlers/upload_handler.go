@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"neobase-ai/config"
 	"neobase-ai/internal/apis/dtos"
 	"neobase-ai/internal/services"
 
@@ -114,6 +115,59 @@ func (h *UploadHandler) UploadFile(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// UploadDatabaseFile handles SQLite/DuckDB database file uploads
+func (h *UploadHandler) UploadDatabaseFile(c *gin.Context) {
+	userID := c.GetString("userID")
+	chatID := c.Param("chatID")
+
+	if userID == "" || chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing userID or chatID"})
+		return
+	}
+
+	maxSize := int64(config.Env.DatabaseFileMaxSizeMB) << 20
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+(1<<20)) // Leave room for the multipart overhead
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to get file"})
+		return
+	}
+	defer file.Close()
+
+	// Validate file extension
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if ext != ".sqlite" && ext != ".sqlite3" && ext != ".db" && ext != ".db3" && ext != ".duckdb" && ext != ".ddb" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file type. Only SQLite (.sqlite, .sqlite3, .db, .db3) and DuckDB (.duckdb, .ddb, .db) files are allowed"})
+		return
+	}
+
+	if header.Size > maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("File is too large. The maximum size is %d MB", config.Env.DatabaseFileMaxSizeMB)})
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	if int64(len(data)) > maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("File is too large. The maximum size is %d MB", config.Env.DatabaseFileMaxSizeMB)})
+		return
+	}
+
+	log.Printf("UploadHandler -> Processing database file: %s (%d bytes)", header.Filename, len(data))
+
+	result, statusCode, err := h.chatService.StoreDatabaseFile(userID, chatID, filepath.Base(header.Filename), data)
+	if err != nil {
+		c.JSON(int(statusCode), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // processCSV reads and processes CSV file
 func (h *UploadHandler) processCSV(file io.Reader) ([][]string, []string, error) {
 	reader := csv.NewReader(file)
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"log"
+	"neobase-ai/internal/apis/middlewares"
+	"neobase-ai/internal/di"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAlertRoutes(router *gin.Engine) {
+	alertHandler, err := di.GetAlertHandler()
+	if err != nil {
+		log.Fatalf("Failed to get alert handler: %v", err)
+	}
+
+	protected := router.Group("/api/alerts")
+	protected.Use(middlewares.AuthMiddleware())
+	{
+		protected.POST("/rules", alertHandler.CreateRule)
+		protected.GET("/rules", alertHandler.ListRules)
+		protected.GET("/rules/:id", alertHandler.GetRule)
+		protected.PATCH("/rules/:id", alertHandler.UpdateRule)
+		protected.DELETE("/rules/:id", alertHandler.DeleteRule)
+		protected.POST("/rules/:id/test", alertHandler.TestRule)
+		protected.GET("/history", alertHandler.ListHistory)
+	}
+}
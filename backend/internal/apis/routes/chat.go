@@ -49,9 +49,12 @@ func SetupChatRoutes(router *gin.Engine) {
 
 		// Query execution routes
 		protected.POST("/:id/queries/execute", chatHandler.ExecuteQuery)
+		protected.POST("/:id/queries/execute-all", chatHandler.ExecuteAllQueries)
 		protected.POST("/:id/queries/rollback", chatHandler.RollbackQuery)
+		protected.POST("/:id/queries/explain", chatHandler.ExplainQuery)
 		protected.POST("/:id/queries/cancel", chatHandler.CancelQueryExecution)
 		protected.POST("/:id/queries/results", chatHandler.GetQueryResults)
+		protected.GET("/:id/queries/:queryId/export", chatHandler.ExportQueryResult)
 		protected.PATCH("/:id/queries/edit", chatHandler.EditQuery)
 
 		// Query recommendations
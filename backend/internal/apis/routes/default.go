@@ -32,6 +32,9 @@ func SetupDefaultRoutes(router *gin.Engine) {
 	// Setup all route groups
 	SetupAuthRoutes(router)
 	SetupChatRoutes(router)
+	SetupSavedQueryRoutes(router)
+	SetupScheduledQueryRoutes(router)
+	SetupAlertRoutes(router)
 	SetupWaitlistRoutes(router)
 	SetupUploadRoutes(router)
 }
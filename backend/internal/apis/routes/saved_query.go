@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"log"
+	"neobase-ai/internal/apis/middlewares"
+	"neobase-ai/internal/di"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupSavedQueryRoutes(router *gin.Engine) {
+	savedQueryHandler, err := di.GetSavedQueryHandler()
+	if err != nil {
+		log.Fatalf("Failed to get saved query handler: %v", err)
+	}
+
+	protected := router.Group("/api/saved-queries")
+	protected.Use(middlewares.AuthMiddleware())
+	{
+		protected.POST("", savedQueryHandler.Create)
+		protected.GET("", savedQueryHandler.List) // Has query params "chat_id", "tag" and "search"
+		protected.POST("/from-message", savedQueryHandler.SaveFromMessage)
+		protected.GET("/:id", savedQueryHandler.GetByID)
+		protected.PATCH("/:id", savedQueryHandler.Update)
+		protected.DELETE("/:id", savedQueryHandler.Delete)
+		protected.POST("/:id/run", savedQueryHandler.Run)
+	}
+}
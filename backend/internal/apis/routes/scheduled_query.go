@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"log"
+	"neobase-ai/internal/apis/middlewares"
+	"neobase-ai/internal/di"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupScheduledQueryRoutes(router *gin.Engine) {
+	scheduledQueryHandler, err := di.GetScheduledQueryHandler()
+	if err != nil {
+		log.Fatalf("Failed to get scheduled query handler: %v", err)
+	}
+
+	protected := router.Group("/api/scheduled-queries")
+	protected.Use(middlewares.AuthMiddleware())
+	{
+		protected.POST("", scheduledQueryHandler.Create)
+		protected.GET("", scheduledQueryHandler.List)
+		protected.GET("/:id", scheduledQueryHandler.GetByID)
+		protected.PATCH("/:id", scheduledQueryHandler.Update)
+		protected.DELETE("/:id", scheduledQueryHandler.Delete)
+		protected.GET("/:id/snapshots", scheduledQueryHandler.ListSnapshots)
+	}
+}
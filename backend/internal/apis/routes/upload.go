@@ -24,6 +24,9 @@ func SetupUploadRoutes(router *gin.Engine) {
 	{
 		// File upload for spreadsheet connections
 		protected.POST("/:chatID/file", uploadHandler.UploadFile)
+
+		// Database file upload for SQLite/DuckDB connections
+		protected.POST("/:chatID/database", uploadHandler.UploadDatabaseFile)
 		
 		// Table data operations
 		protected.GET("/:chatID/tables/:tableName", uploadHandler.GetTableData)
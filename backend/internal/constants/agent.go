@@ -0,0 +1,28 @@
+package constants
+
+import "fmt"
+
+// Agent mode settings
+const (
+	AgentToolResultMaxChars = 4000  // Characters of a tool result sent back to the LLM
+	AgentFindingsMaxChars   = 12000 // Characters of the exploration transcript added to the user's message
+	AgentSampleRowsDefault  = 5     // Rows returned by sample_rows and distinct_values when no limit is given
+)
+
+const agentSystemPrompt = `You are NeoBase, an AI assistant that writes %s queries. Before the final answer is written, you can explore the user's database with the tools provided to check what the request depends on: which tables/collections hold the data, their columns, sample rows, the distinct values of a column or the result of a small query.
+Rules:
+- Only call tools whose results change the answer, e.g. to find the right table, the exact spelling of a value or whether a join returns rows. Don't look up what the schema already tells you.
+- Only read-only queries are allowed and results are capped to a few rows, so filter or aggregate instead of reading whole tables.
+- Call independent tools together in the same round. You have at most %d rounds of tool calls.
+- When you know enough, or the request doesn't need the database, reply without calling any tool with a short summary of your findings for the final answer. Don't write the final answer yourself.`
+
+// GetAgentPrompt returns the system prompt used while the LLM explores the database with tools
+func GetAgentPrompt(dbType string, maxSteps int) string {
+	return fmt.Sprintf(agentSystemPrompt, dbType, maxSteps)
+}
+
+// AgentFindingsPrompt is appended to the user's message for the final answer, with the exploration transcript
+const AgentFindingsPrompt = `
+
+[Database exploration done before answering. Base the response and its queries on these findings, they were not shown to the user]
+%s`
@@ -0,0 +1,10 @@
+package constants
+
+const (
+	AnthropicModel               = "claude-sonnet-4-5"
+	AnthropicTemperature         = 1
+	AnthropicMaxCompletionTokens = 16000
+	AnthropicContextLimit        = 200000
+	AnthropicBaseURL             = "https://api.anthropic.com"
+	AnthropicAPIVersion          = "2023-06-01"
+)
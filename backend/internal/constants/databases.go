@@ -4,10 +4,27 @@ const (
 	DatabaseTypePostgreSQL  = "postgresql"
 	DatabaseTypeYugabyteDB  = "yugabytedb"
 	DatabaseTypeMySQL       = "mysql"
+	DatabaseTypeMSSQL       = "mssql"
 	DatabaseTypeMongoDB     = "mongodb"
 	DatabaseTypeRedis       = "redis"
 	DatabaseTypeNeo4j       = "neo4j"
 	DatabaseTypeClickhouse  = "clickhouse"
 	DatabaseTypeCassandra   = "cassandra"
 	DatabaseTypeSpreadsheet = "spreadsheet"
+	DatabaseTypeSQLite      = "sqlite"
+	DatabaseTypeDuckDB      = "duckdb"
 )
+
+// SupportsReadOnlyMode reports whether the data source can be opened with read-only sessions
+func SupportsReadOnlyMode(dbType string) bool {
+	switch dbType {
+	case DatabaseTypePostgreSQL, DatabaseTypeYugabyteDB, DatabaseTypeMySQL, DatabaseTypeClickhouse, DatabaseTypeMongoDB:
+		return true
+	}
+	return false
+}
+
+// IsFileDatabaseType reports whether the data source is an uploaded database file
+func IsFileDatabaseType(dbType string) bool {
+	return dbType == DatabaseTypeSQLite || dbType == DatabaseTypeDuckDB
+}
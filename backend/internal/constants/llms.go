@@ -3,13 +3,18 @@ package constants
 import "log"
 
 const (
-	OpenAI = "openai"
-	Gemini = "gemini"
+	OpenAI    = "openai"
+	Gemini    = "gemini"
+	Anthropic = "anthropic"
+
+	// Self-hosted models served through an OpenAI compatible API
+	Ollama           = "ollama"
+	OpenAICompatible = "openai-compatible"
 )
 
 func GetLLMResponseSchema(provider string, dbType string) interface{} {
 	switch provider {
-	case OpenAI:
+	case OpenAI, Anthropic, Ollama, OpenAICompatible: // These providers take the same JSON schemas as OpenAI
 		switch dbType {
 		case DatabaseTypePostgreSQL:
 			return OpenAIPostgresLLMResponseSchema
@@ -17,10 +22,22 @@ func GetLLMResponseSchema(provider string, dbType string) interface{} {
 			return OpenAIYugabyteDBLLMResponseSchema
 		case DatabaseTypeMySQL:
 			return OpenAIMySQLLLMResponseSchema
+		case DatabaseTypeMSSQL:
+			return OpenAIMSSQLLLMResponseSchema
 		case DatabaseTypeClickhouse:
 			return OpenAIClickhouseLLMResponseSchema
 		case DatabaseTypeMongoDB:
 			return OpenAIMongoDBLLMResponseSchema
+		case DatabaseTypeRedis:
+			return OpenAIRedisLLMResponseSchema
+		case DatabaseTypeCassandra:
+			return OpenAICassandraLLMResponseSchema
+		case DatabaseTypeNeo4j:
+			return OpenAINeo4jLLMResponseSchema
+		case DatabaseTypeSQLite:
+			return OpenAISQLiteLLMResponseSchema
+		case DatabaseTypeDuckDB:
+			return OpenAIDuckDBLLMResponseSchema
 		case DatabaseTypeSpreadsheet:
 			return OpenAIPostgresLLMResponseSchema // Use PostgreSQL schema since spreadsheet uses PostgreSQL internally
 		default:
@@ -34,10 +51,22 @@ func GetLLMResponseSchema(provider string, dbType string) interface{} {
 			return GeminiYugabyteDBLLMResponseSchema
 		case DatabaseTypeMySQL:
 			return GeminiMySQLLLMResponseSchema
+		case DatabaseTypeMSSQL:
+			return GeminiMSSQLLLMResponseSchema
 		case DatabaseTypeClickhouse:
 			return GeminiClickhouseLLMResponseSchema
 		case DatabaseTypeMongoDB:
 			return GeminiMongoDBLLMResponseSchema
+		case DatabaseTypeRedis:
+			return GeminiRedisLLMResponseSchema
+		case DatabaseTypeCassandra:
+			return GeminiCassandraLLMResponseSchema
+		case DatabaseTypeNeo4j:
+			return GeminiNeo4jLLMResponseSchema
+		case DatabaseTypeSQLite:
+			return GeminiSQLiteLLMResponseSchema
+		case DatabaseTypeDuckDB:
+			return GeminiDuckDBLLMResponseSchema
 		case DatabaseTypeSpreadsheet:
 			return GeminiPostgresLLMResponseSchema // Use PostgreSQL schema since spreadsheet uses PostgreSQL internally
 		default:
@@ -53,18 +82,30 @@ func GetSystemPrompt(provider string, dbType string, nonTechMode bool) string {
 	var basePrompt string
 
 	switch provider {
-	case OpenAI:
+	case OpenAI, Anthropic, Ollama, OpenAICompatible:
 		switch dbType {
 		case DatabaseTypePostgreSQL:
 			basePrompt = OpenAIPostgreSQLPrompt
 		case DatabaseTypeMySQL:
 			basePrompt = OpenAIMySQLPrompt
+		case DatabaseTypeMSSQL:
+			basePrompt = OpenAIMSSQLPrompt
 		case DatabaseTypeYugabyteDB:
 			basePrompt = OpenAIYugabyteDBPrompt
 		case DatabaseTypeClickhouse:
 			basePrompt = OpenAIClickhousePrompt
 		case DatabaseTypeMongoDB:
 			basePrompt = OpenAIMongoDBPrompt
+		case DatabaseTypeRedis:
+			basePrompt = OpenAIRedisPrompt
+		case DatabaseTypeCassandra:
+			basePrompt = OpenAICassandraPrompt
+		case DatabaseTypeNeo4j:
+			basePrompt = OpenAINeo4jPrompt
+		case DatabaseTypeSQLite:
+			basePrompt = OpenAISQLitePrompt
+		case DatabaseTypeDuckDB:
+			basePrompt = OpenAIDuckDBPrompt
 		case DatabaseTypeSpreadsheet:
 			basePrompt = OpenAISpreadsheetPrompt
 		default:
@@ -78,10 +119,22 @@ func GetSystemPrompt(provider string, dbType string, nonTechMode bool) string {
 			basePrompt = GeminiYugabyteDBPrompt
 		case DatabaseTypeMySQL:
 			basePrompt = GeminiMySQLPrompt
+		case DatabaseTypeMSSQL:
+			basePrompt = GeminiMSSQLPrompt
 		case DatabaseTypeClickhouse:
 			basePrompt = GeminiClickhousePrompt
 		case DatabaseTypeMongoDB:
 			basePrompt = GeminiMongoDBPrompt
+		case DatabaseTypeRedis:
+			basePrompt = GeminiRedisPrompt
+		case DatabaseTypeCassandra:
+			basePrompt = GeminiCassandraPrompt
+		case DatabaseTypeNeo4j:
+			basePrompt = GeminiNeo4jPrompt
+		case DatabaseTypeSQLite:
+			basePrompt = GeminiSQLitePrompt
+		case DatabaseTypeDuckDB:
+			basePrompt = GeminiDuckDBPrompt
 		case DatabaseTypeSpreadsheet:
 			basePrompt = GeminiSpreadsheetPrompt
 		default:
@@ -155,8 +208,20 @@ Examples of CORRECT assistantMessage:
 		return baseInstructions + getPostgreSQLNonTechInstructions()
 	case DatabaseTypeMySQL:
 		return baseInstructions + getMySQLNonTechInstructions()
+	case DatabaseTypeMSSQL:
+		return baseInstructions + getMSSQLNonTechInstructions()
 	case DatabaseTypeClickhouse:
 		return baseInstructions + getClickhouseNonTechInstructions()
+	case DatabaseTypeRedis:
+		return baseInstructions + getRedisNonTechInstructions()
+	case DatabaseTypeCassandra:
+		return baseInstructions + getCassandraNonTechInstructions()
+	case DatabaseTypeNeo4j:
+		return baseInstructions + getNeo4jNonTechInstructions()
+	case DatabaseTypeSQLite:
+		return baseInstructions + getSQLiteNonTechInstructions()
+	case DatabaseTypeDuckDB:
+		return baseInstructions + getDuckDBNonTechInstructions()
 	default:
 		return baseInstructions + getPostgreSQLNonTechInstructions()
 	}
@@ -370,10 +435,252 @@ CRITICAL - The 'assistantMessage' MUST be simple and non-technical:
 `
 }
 
+// Redis specific non-tech instructions
+func getRedisNonTechInstructions() string {
+	return `
+
+**REDIS SPECIFIC REQUIREMENTS**:
+
+You MUST read whole records instead of single raw values:
+
+1. Prefer HGETALL or HMGET with business fields over HGET of a single field
+2. NEVER use KEYS, use SCAN with a MATCH pattern and COUNT
+3. Read related keys together with MGET or multiple commands (one per line)
+4. Always bound range reads (LRANGE key 0 9, ZREVRANGE key 0 9 WITHSCORES)
+5. NEVER show raw keys, TTLs or internal fields in the explanation
+
+Example for "Show top 10 players":
+WRONG: ZRANGE leaderboard 0 -1
+
+CORRECT:
+ZREVRANGE leaderboard 0 9 WITHSCORES
+
+The 'explanation' field should be: "Shows your top 10 players by score"
+
+CRITICAL - The 'assistantMessage' MUST be simple and non-technical:
+- ✅ CORRECT: "Here are your top players:"
+- ❌ WRONG: "Here's the ZREVRANGE command on the leaderboard sorted set"
+- ❌ WRONG: "I'm scanning keys matching user:*"
+`
+}
+
+// Cassandra specific non-tech instructions
+func getCassandraNonTechInstructions() string {
+	return `
+
+**CASSANDRA SPECIFIC REQUIREMENTS**:
+
+You MUST select business columns and respect the table keys for ALL queries:
+
+1. NEVER use SELECT * - always specify columns
+2. NEVER use ALLOW FILTERING, filter only on partition/clustering keys or indexed columns
+3. There are no JOINs, read the table designed for the question (e.g. orders_by_customer)
+4. ALWAYS format timestamps using toDate() or read the date column when available
+5. NEVER include uuid/timeuuid ids in raw format unless they are the only identifier
+
+Example for "Show latest orders of customer 42":
+WRONG: SELECT * FROM orders WHERE customer_id = 42 ALLOW FILTERING
+
+CORRECT:
+SELECT order_number, product_name, quantity, total_amount, toDate(created_at), status
+FROM orders_by_customer
+WHERE customer_id = 42
+LIMIT 10
+
+The 'explanation' field should be: "Shows the most recent orders of this customer"
+
+CRITICAL - The 'assistantMessage' MUST be simple and non-technical:
+- ✅ CORRECT: "Here are the latest orders:"
+- ❌ WRONG: "Here's the CQL query reading the orders_by_customer partition"
+- ❌ WRONG: "I'm filtering on the partition key customer_id"
+`
+}
+
+// Neo4j specific non-tech instructions
+func getNeo4jNonTechInstructions() string {
+	return `
+
+**NEO4J SPECIFIC REQUIREMENTS**:
+
+You MUST return readable properties instead of raw nodes for ALL queries:
+
+1. NEVER return whole nodes or relationships (RETURN p), return named properties (RETURN p.name AS name)
+2. ALWAYS follow relationships to show related names instead of ids
+3. ALWAYS format dates using toString(date(...)) or the date part of datetimes
+4. NEVER include elementId(), id() or internal identifiers
+5. ALWAYS use readable aliases with AS (e.g. AS movie, AS releasedIn)
+
+Example for "Show movies Tom Hanks acted in":
+WRONG: MATCH (p:Person {name: 'Tom Hanks'})-[r:ACTED_IN]->(m) RETURN p, r, m
+
+CORRECT:
+MATCH (p:Person {name: 'Tom Hanks'})-[:ACTED_IN]->(m:Movie)
+RETURN m.title AS movie, m.released AS releasedIn
+ORDER BY releasedIn DESC
+LIMIT 10
+
+The 'explanation' field should be: "Shows the movies Tom Hanks acted in, newest first"
+
+CRITICAL - The 'assistantMessage' MUST be simple and non-technical:
+- ✅ CORRECT: "Here are the movies Tom Hanks acted in:"
+- ❌ WRONG: "Here's the Cypher query matching the ACTED_IN relationship"
+- ❌ WRONG: "I'm traversing from the Person node to Movie nodes"
+`
+}
+
+// SQLite specific non-tech instructions
+func getSQLiteNonTechInstructions() string {
+	return `
+
+**SQLITE SPECIFIC REQUIREMENTS**:
+
+You MUST use proper JOINs and column selection for ALL queries:
+
+1. NEVER use SELECT * - always specify columns
+2. ALWAYS JOIN to get names instead of IDs
+3. ALWAYS use column aliases with business-friendly names
+4. ALWAYS format dates using strftime
+5. NEVER include id, created_at, updated_at in raw format
+
+Example for "Show latest order":
+WRONG: SELECT * FROM orders ORDER BY created_at DESC LIMIT 1
+
+CORRECT:
+SELECT 
+  o.order_number AS "Order Number",
+  c.name AS "Customer Name",
+  c.email AS "Customer Email",
+  p.name AS "Product",
+  o.quantity AS "Quantity",
+  o.total_amount AS "Total Amount",
+  strftime('%d/%m/%Y %H:%M', o.created_at) AS "Order Date",
+  o.status AS "Status"
+FROM orders o
+JOIN customers c ON o.customer_id = c.id
+JOIN products p ON o.product_id = p.id
+ORDER BY o.created_at DESC
+LIMIT 1
+
+The 'explanation' field should be: "Shows your most recent order"
+
+**DATE RANGE EXAMPLES FOR SQLITE**:
+- "orders on August 9, 2025":
+  WHERE created_at >= '2025-08-09 00:00:00' AND created_at < '2025-08-10 00:00:00'
+- "sales from last month" (assuming today is Aug 10):
+  WHERE created_at >= date('now', 'start of month', '-1 month') AND created_at < date('now', 'start of month')
+- "data between Aug 5 and Aug 8":
+  WHERE created_at >= '2025-08-05' AND created_at < '2025-08-09'
+
+CRITICAL - The 'assistantMessage' MUST be simple and non-technical:
+- ✅ CORRECT: "Here's your latest order:"
+- ❌ WRONG: "Here's the query to fetch the latest order from the orders table"
+- ❌ WRONG: "I'm joining the orders with customers and products tables"
+`
+}
+
+// DuckDB specific non-tech instructions
+func getDuckDBNonTechInstructions() string {
+	return `
+
+**DUCKDB SPECIFIC REQUIREMENTS**:
+
+You MUST use proper JOINs and column selection for ALL queries:
+
+1. NEVER use SELECT * - always specify columns
+2. ALWAYS JOIN to get names instead of IDs
+3. ALWAYS use column aliases with business-friendly names
+4. ALWAYS format dates using strftime
+5. NEVER include id, created_at, updated_at in raw format
+
+Example for "Show latest order":
+WRONG: SELECT * FROM orders ORDER BY created_at DESC LIMIT 1
+
+CORRECT:
+SELECT 
+  o.order_number AS "Order Number",
+  c.name AS "Customer Name",
+  c.email AS "Customer Email",
+  p.name AS "Product",
+  o.quantity AS "Quantity",
+  o.total_amount AS "Total Amount",
+  strftime(o.created_at, '%B %d, %Y at %I:%M %p') AS "Order Date",
+  o.status AS "Status"
+FROM orders o
+JOIN customers c ON o.customer_id = c.id
+JOIN products p ON o.product_id = p.id
+ORDER BY o.created_at DESC
+LIMIT 1
+
+The 'explanation' field should be: "Shows your most recent order"
+
+**DATE RANGE EXAMPLES FOR DUCKDB**:
+- "orders on August 9, 2025":
+  WHERE created_at >= TIMESTAMP '2025-08-09 00:00:00' AND created_at < TIMESTAMP '2025-08-10 00:00:00'
+- "sales from last month" (assuming today is Aug 10):
+  WHERE created_at >= date_trunc('month', current_date) - INTERVAL 1 MONTH AND created_at < date_trunc('month', current_date)
+- "data between Aug 5 and Aug 8":
+  WHERE created_at >= DATE '2025-08-05' AND created_at < DATE '2025-08-09'
+
+CRITICAL - The 'assistantMessage' MUST be simple and non-technical:
+- ✅ CORRECT: "Here's your latest order:"
+- ❌ WRONG: "Here's the query to fetch the latest order from the orders table"
+- ❌ WRONG: "I'm joining the orders with customers and products tables"
+`
+}
+
+// SQL Server specific non-tech instructions
+func getMSSQLNonTechInstructions() string {
+	return `
+
+**SQL SERVER SPECIFIC REQUIREMENTS**:
+
+You MUST use proper JOINs and column selection for ALL queries:
+
+1. NEVER use SELECT * - always specify columns
+2. ALWAYS JOIN to get names instead of IDs
+3. ALWAYS use column aliases with business-friendly names
+4. ALWAYS format dates using FORMAT
+5. NEVER include id, created_at, updated_at in raw format
+
+Example for "Show latest order":
+WRONG: SELECT TOP (1) * FROM orders ORDER BY created_at DESC
+
+CORRECT:
+SELECT TOP (1)
+  o.order_number AS [Order Number],
+  c.name AS [Customer Name],
+  c.email AS [Customer Email],
+  p.name AS [Product],
+  o.quantity AS [Quantity],
+  o.total_amount AS [Total Amount],
+  FORMAT(o.created_at, 'MMMM dd, yyyy ''at'' hh:mm tt') AS [Order Date],
+  o.status AS [Status]
+FROM orders o
+JOIN customers c ON o.customer_id = c.id
+JOIN products p ON o.product_id = p.id
+ORDER BY o.created_at DESC
+
+The 'explanation' field should be: "Shows your most recent order"
+
+**DATE RANGE EXAMPLES FOR SQL SERVER**:
+- "orders on August 9, 2025":
+  WHERE created_at >= '2025-08-09T00:00:00' AND created_at < '2025-08-10T00:00:00'
+- "sales from last month" (assuming today is Aug 10):
+  WHERE created_at >= DATEADD(MONTH, -1, DATEFROMPARTS(YEAR(GETDATE()), MONTH(GETDATE()), 1)) AND created_at < DATEFROMPARTS(YEAR(GETDATE()), MONTH(GETDATE()), 1)
+- "data between Aug 5 and Aug 8":
+  WHERE created_at >= '2025-08-05' AND created_at < '2025-08-09'
+
+CRITICAL - The 'assistantMessage' MUST be simple and non-technical:
+- ✅ CORRECT: "Here's your latest order:"
+- ❌ WRONG: "Here's the query to fetch the latest order from the orders table"
+- ❌ WRONG: "I'm joining the orders with customers and products tables"
+`
+}
+
 // GetRecommendationsPrompt returns the appropriate recommendations prompt based on provider
 func GetRecommendationsPrompt(provider string) string {
 	switch provider {
-	case OpenAI:
+	case OpenAI, Anthropic, Ollama, OpenAICompatible:
 		return OpenAIRecommendationsPrompt
 	case Gemini:
 		return GeminiRecommendationsPrompt
@@ -385,7 +692,7 @@ func GetRecommendationsPrompt(provider string) string {
 // GetRecommendationsSchema returns the appropriate recommendations schema based on provider
 func GetRecommendationsSchema(provider string) interface{} {
 	switch provider {
-	case OpenAI:
+	case OpenAI, Anthropic, Ollama, OpenAICompatible:
 		return OpenAIRecommendationsResponseSchema
 	case Gemini:
 		return GeminiRecommendationsResponseSchema
@@ -6,4 +6,57 @@ const (
 	MessageTypeUser      MessageType = "user"
 	MessageTypeAssistant MessageType = "assistant"
 	MessageTypeSystem    MessageType = "system"
+	MessageTypeSummary   MessageType = "summary" // Rolling summary of the turns that no longer fit the model's context
 )
+
+// Conversation summary settings
+const (
+	ConversationSummaryMaxTokens = 1024 // Tokens set aside for the summary in the context
+	ContextSafetyMarginPercent   = 5    // Part of the context limit left unused as token counts are estimates
+)
+
+const ConversationSummaryPrompt = `You maintain a running summary of a conversation between a user and NeoBase, an AI assistant that writes database queries.
+Update the existing summary with the new part of the conversation. Keep what later requests may depend on:
+- the user's goals, preferences and corrections
+- the tables/collections, columns, filters and time ranges discussed
+- the queries that were suggested and what they returned or why they failed
+- open questions that were not answered yet
+Drop small talk and example results. Write plain text, at most 400 words, without any preamble.`
+
+const ConversationSummaryRequest = `Existing summary:
+%s
+
+New part of the conversation:
+%s`
+
+// QueryFixRequest is sent as a user message when an auto-executed query failed, with the query and the database error
+const QueryFixRequest = `The query below failed when it was executed:
+%s
+
+Database error: %s
+
+Reply with the corrected query for the same request as the only item of queries, keeping it read-only. Use the schema to fix wrong table, column or function names, and explain in the query's explanation what was changed.`
+
+// ReadOnlyModePrompt is appended to the user's message in read-only chats, the database rejects writes
+const ReadOnlyModePrompt = `
+
+[This chat is read-only: the database connection rejects writes. Only write queries that read data (no INSERT, UPDATE, DELETE, DDL or other commands changing data, schema, permissions or settings). If the request needs a write, explain that writes are not allowed in this chat instead of writing the query.]`
+
+// QueryPlanSuggestionPrompt asks for index suggestions from the plan of a query and the indexes of the tables it reads
+const QueryPlanSuggestionPrompt = `You are NeoBase, an AI database administrator reviewing the execution plan of a query before it is run on large tables.
+Based on the plan and the existing indexes:
+- point out the steps that are expensive, e.g. full scans of large tables, sorts or joins without an index
+- suggest indexes that would make the query cheaper, as CREATE INDEX statements (or createIndex calls for MongoDB), and say which step each one helps
+- do not suggest an index that already exists, and say so when the existing indexes are already used well
+Keep the reply short and in plain text, without any preamble.`
+
+// QueryPlanSuggestionRequest holds the database type, the query, its plan and the existing indexes
+const QueryPlanSuggestionRequest = `Database: %s
+
+Query:
+%s
+
+Plan:
+%s
+Existing indexes:
+%s`
@@ -0,0 +1,20 @@
+package constants
+
+const (
+	OllamaModel               = "llama3.1"
+	OllamaTemperature         = 0.2
+	OllamaMaxCompletionTokens = 8192
+	OllamaContextLimit        = 8192
+	OllamaBaseURL             = "http://localhost:11434/v1"
+)
+
+// StructuredOutputFallbackPrompt is appended to the system prompt when the model can't enforce the response schema itself
+const StructuredOutputFallbackPrompt = `
+---
+RESPONSE FORMAT (MANDATORY):
+Reply with a single JSON object that follows the JSON schema below. Do not wrap it in markdown, do not add any text before or after it, and do not add comments.
+JSON schema:
+%s`
+
+// StructuredOutputRetryPrompt asks the model to resend a reply that could not be parsed as JSON
+const StructuredOutputRetryPrompt = "Your previous reply was not a valid JSON object. Reply again with only the JSON object that follows the required schema."
@@ -81,8 +81,14 @@ func Initialize() {
 		manager.RegisterDriver(constants.DatabaseTypePostgreSQL, dbmanager.NewPostgresDriver())
 		manager.RegisterDriver(constants.DatabaseTypeYugabyteDB, dbmanager.NewPostgresDriver()) // Use same driver for both
 		manager.RegisterDriver(constants.DatabaseTypeMySQL, dbmanager.NewMySQLDriver())
+		manager.RegisterDriver(constants.DatabaseTypeMSSQL, dbmanager.NewMSSQLDriver())
 		manager.RegisterDriver(constants.DatabaseTypeClickhouse, dbmanager.NewClickHouseDriver())
 		manager.RegisterDriver(constants.DatabaseTypeMongoDB, dbmanager.NewMongoDBDriver())
+		manager.RegisterDriver(constants.DatabaseTypeRedis, dbmanager.NewRedisDriver())
+		manager.RegisterDriver(constants.DatabaseTypeCassandra, dbmanager.NewCassandraDriver())
+		manager.RegisterDriver(constants.DatabaseTypeNeo4j, dbmanager.NewNeo4jDriver())
+		manager.RegisterDriver(constants.DatabaseTypeSQLite, dbmanager.NewSQLiteDriver())
+		manager.RegisterDriver(constants.DatabaseTypeDuckDB, dbmanager.NewDuckDBDriver())
 		manager.RegisterDriver(constants.DatabaseTypeSpreadsheet, dbmanager.NewSpreadsheetDriver())
 		
 		// Register schema fetchers
@@ -95,12 +101,30 @@ func Initialize() {
 		manager.RegisterFetcher(constants.DatabaseTypeMySQL, func(db dbmanager.DBExecutor) dbmanager.SchemaFetcher {
 			return dbmanager.NewMySQLSchemaFetcher(db)
 		})
+		manager.RegisterFetcher(constants.DatabaseTypeMSSQL, func(db dbmanager.DBExecutor) dbmanager.SchemaFetcher {
+			return dbmanager.NewMSSQLSchemaFetcher(db)
+		})
 		manager.RegisterFetcher(constants.DatabaseTypeClickhouse, func(db dbmanager.DBExecutor) dbmanager.SchemaFetcher {
 			return &dbmanager.ClickHouseDriver{}
 		})
 		manager.RegisterFetcher(constants.DatabaseTypeMongoDB, func(db dbmanager.DBExecutor) dbmanager.SchemaFetcher {
 			return &dbmanager.MongoDBDriver{}
 		})
+		manager.RegisterFetcher(constants.DatabaseTypeRedis, func(db dbmanager.DBExecutor) dbmanager.SchemaFetcher {
+			return dbmanager.NewRedisSchemaFetcher(db)
+		})
+		manager.RegisterFetcher(constants.DatabaseTypeCassandra, func(db dbmanager.DBExecutor) dbmanager.SchemaFetcher {
+			return dbmanager.NewCassandraSchemaFetcher(db)
+		})
+		manager.RegisterFetcher(constants.DatabaseTypeNeo4j, func(db dbmanager.DBExecutor) dbmanager.SchemaFetcher {
+			return dbmanager.NewNeo4jSchemaFetcher(db)
+		})
+		manager.RegisterFetcher(constants.DatabaseTypeSQLite, func(db dbmanager.DBExecutor) dbmanager.SchemaFetcher {
+			return dbmanager.NewSQLiteSchemaFetcher(db)
+		})
+		manager.RegisterFetcher(constants.DatabaseTypeDuckDB, func(db dbmanager.DBExecutor) dbmanager.SchemaFetcher {
+			return dbmanager.NewDuckDBSchemaFetcher(db)
+		})
 		manager.RegisterFetcher(constants.DatabaseTypeSpreadsheet, func(db dbmanager.DBExecutor) dbmanager.SchemaFetcher {
 			return &dbmanager.PostgresDriver{}
 		})
@@ -159,15 +183,18 @@ func Initialize() {
 	if err := DiContainer.Provide(func() *llm.Manager {
 		manager := llm.NewManager()
 
-		switch config.Env.DefaultLLMClient {
-		case constants.OpenAI:
-			// Register default OpenAI client
+		// Register every configured provider so chats can pick one, the default one is always registered
+		if config.Env.OpenAIAPIKey != "" || config.Env.DefaultLLMClient == constants.OpenAI {
+			// Register OpenAI client
 			err := manager.RegisterClient(constants.OpenAI, llm.Config{
 				Provider:            constants.OpenAI,
 				Model:               config.Env.OpenAIModel,
+				Models:              config.Env.OpenAIModels,
 				APIKey:              config.Env.OpenAIAPIKey,
 				MaxCompletionTokens: config.Env.OpenAIMaxCompletionTokens,
 				Temperature:         config.Env.OpenAITemperature,
+				ContextLimit:        config.Env.OpenAIContextLimit,
+				EmbeddingModel:      config.Env.OpenAIEmbeddingModel,
 				DBConfigs: []llm.LLMDBConfig{
 					{
 						DBType:       constants.DatabaseTypePostgreSQL,
@@ -184,6 +211,11 @@ func Initialize() {
 						Schema:       constants.GetLLMResponseSchema(constants.OpenAI, constants.DatabaseTypeMySQL),
 						SystemPrompt: constants.GetSystemPrompt(constants.OpenAI, constants.DatabaseTypeMySQL, false),
 					},
+					{
+						DBType:       constants.DatabaseTypeMSSQL,
+						Schema:       constants.GetLLMResponseSchema(constants.OpenAI, constants.DatabaseTypeMSSQL),
+						SystemPrompt: constants.GetSystemPrompt(constants.OpenAI, constants.DatabaseTypeMSSQL, false),
+					},
 					{
 						DBType:       constants.DatabaseTypeClickhouse,
 						Schema:       constants.GetLLMResponseSchema(constants.OpenAI, constants.DatabaseTypeClickhouse),
@@ -194,6 +226,31 @@ func Initialize() {
 						Schema:       constants.GetLLMResponseSchema(constants.OpenAI, constants.DatabaseTypeMongoDB),
 						SystemPrompt: constants.GetSystemPrompt(constants.OpenAI, constants.DatabaseTypeMongoDB, false),
 					},
+					{
+						DBType:       constants.DatabaseTypeRedis,
+						Schema:       constants.GetLLMResponseSchema(constants.OpenAI, constants.DatabaseTypeRedis),
+						SystemPrompt: constants.GetSystemPrompt(constants.OpenAI, constants.DatabaseTypeRedis, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeCassandra,
+						Schema:       constants.GetLLMResponseSchema(constants.OpenAI, constants.DatabaseTypeCassandra),
+						SystemPrompt: constants.GetSystemPrompt(constants.OpenAI, constants.DatabaseTypeCassandra, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeNeo4j,
+						Schema:       constants.GetLLMResponseSchema(constants.OpenAI, constants.DatabaseTypeNeo4j),
+						SystemPrompt: constants.GetSystemPrompt(constants.OpenAI, constants.DatabaseTypeNeo4j, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeSQLite,
+						Schema:       constants.GetLLMResponseSchema(constants.OpenAI, constants.DatabaseTypeSQLite),
+						SystemPrompt: constants.GetSystemPrompt(constants.OpenAI, constants.DatabaseTypeSQLite, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeDuckDB,
+						Schema:       constants.GetLLMResponseSchema(constants.OpenAI, constants.DatabaseTypeDuckDB),
+						SystemPrompt: constants.GetSystemPrompt(constants.OpenAI, constants.DatabaseTypeDuckDB, false),
+					},
 					{
 						DBType:       constants.DatabaseTypeSpreadsheet,
 						Schema:       constants.GetLLMResponseSchema(constants.OpenAI, constants.DatabaseTypeSpreadsheet),
@@ -204,14 +261,18 @@ func Initialize() {
 			if err != nil {
 				log.Printf("Warning: Failed to register OpenAI client: %v", err)
 			}
-		case constants.Gemini:
-			// Register default Gemini client
+		}
+		if config.Env.GeminiAPIKey != "" || config.Env.DefaultLLMClient == constants.Gemini {
+			// Register Gemini client
 			err := manager.RegisterClient(constants.Gemini, llm.Config{
 				Provider:            constants.Gemini,
 				Model:               config.Env.GeminiModel,
+				Models:              config.Env.GeminiModels,
 				APIKey:              config.Env.GeminiAPIKey,
 				MaxCompletionTokens: config.Env.GeminiMaxCompletionTokens,
 				Temperature:         config.Env.GeminiTemperature,
+				ContextLimit:        config.Env.GeminiContextLimit,
+				EmbeddingModel:      config.Env.GeminiEmbeddingModel,
 				DBConfigs: []llm.LLMDBConfig{
 					{
 						DBType:       constants.DatabaseTypePostgreSQL,
@@ -228,6 +289,11 @@ func Initialize() {
 						Schema:       constants.GetLLMResponseSchema(constants.Gemini, constants.DatabaseTypeMySQL),
 						SystemPrompt: constants.GetSystemPrompt(constants.Gemini, constants.DatabaseTypeMySQL, false),
 					},
+					{
+						DBType:       constants.DatabaseTypeMSSQL,
+						Schema:       constants.GetLLMResponseSchema(constants.Gemini, constants.DatabaseTypeMSSQL),
+						SystemPrompt: constants.GetSystemPrompt(constants.Gemini, constants.DatabaseTypeMSSQL, false),
+					},
 					{
 						DBType:       constants.DatabaseTypeClickhouse,
 						Schema:       constants.GetLLMResponseSchema(constants.Gemini, constants.DatabaseTypeClickhouse),
@@ -238,6 +304,31 @@ func Initialize() {
 						Schema:       constants.GetLLMResponseSchema(constants.Gemini, constants.DatabaseTypeMongoDB),
 						SystemPrompt: constants.GetSystemPrompt(constants.Gemini, constants.DatabaseTypeMongoDB, false),
 					},
+					{
+						DBType:       constants.DatabaseTypeRedis,
+						Schema:       constants.GetLLMResponseSchema(constants.Gemini, constants.DatabaseTypeRedis),
+						SystemPrompt: constants.GetSystemPrompt(constants.Gemini, constants.DatabaseTypeRedis, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeCassandra,
+						Schema:       constants.GetLLMResponseSchema(constants.Gemini, constants.DatabaseTypeCassandra),
+						SystemPrompt: constants.GetSystemPrompt(constants.Gemini, constants.DatabaseTypeCassandra, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeNeo4j,
+						Schema:       constants.GetLLMResponseSchema(constants.Gemini, constants.DatabaseTypeNeo4j),
+						SystemPrompt: constants.GetSystemPrompt(constants.Gemini, constants.DatabaseTypeNeo4j, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeSQLite,
+						Schema:       constants.GetLLMResponseSchema(constants.Gemini, constants.DatabaseTypeSQLite),
+						SystemPrompt: constants.GetSystemPrompt(constants.Gemini, constants.DatabaseTypeSQLite, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeDuckDB,
+						Schema:       constants.GetLLMResponseSchema(constants.Gemini, constants.DatabaseTypeDuckDB),
+						SystemPrompt: constants.GetSystemPrompt(constants.Gemini, constants.DatabaseTypeDuckDB, false),
+					},
 					{
 						DBType:       constants.DatabaseTypeSpreadsheet,
 						Schema:       constants.GetLLMResponseSchema(constants.Gemini, constants.DatabaseTypeSpreadsheet),
@@ -249,6 +340,179 @@ func Initialize() {
 				log.Printf("Warning: Failed to register Gemini client: %v", err)
 			}
 		}
+		if config.Env.AnthropicAPIKey != "" || config.Env.DefaultLLMClient == constants.Anthropic {
+			// Register Anthropic client
+			err := manager.RegisterClient(constants.Anthropic, llm.Config{
+				Provider:            constants.Anthropic,
+				Model:               config.Env.AnthropicModel,
+				Models:              config.Env.AnthropicModels,
+				APIKey:              config.Env.AnthropicAPIKey,
+				BaseURL:             config.Env.AnthropicBaseURL,
+				MaxCompletionTokens: config.Env.AnthropicMaxCompletionTokens,
+				Temperature:         config.Env.AnthropicTemperature,
+				ContextLimit:        config.Env.AnthropicContextLimit,
+				DBConfigs: []llm.LLMDBConfig{
+					{
+						DBType:       constants.DatabaseTypePostgreSQL,
+						Schema:       constants.GetLLMResponseSchema(constants.Anthropic, constants.DatabaseTypePostgreSQL),
+						SystemPrompt: constants.GetSystemPrompt(constants.Anthropic, constants.DatabaseTypePostgreSQL, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeYugabyteDB,
+						Schema:       constants.GetLLMResponseSchema(constants.Anthropic, constants.DatabaseTypeYugabyteDB),
+						SystemPrompt: constants.GetSystemPrompt(constants.Anthropic, constants.DatabaseTypeYugabyteDB, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeMySQL,
+						Schema:       constants.GetLLMResponseSchema(constants.Anthropic, constants.DatabaseTypeMySQL),
+						SystemPrompt: constants.GetSystemPrompt(constants.Anthropic, constants.DatabaseTypeMySQL, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeMSSQL,
+						Schema:       constants.GetLLMResponseSchema(constants.Anthropic, constants.DatabaseTypeMSSQL),
+						SystemPrompt: constants.GetSystemPrompt(constants.Anthropic, constants.DatabaseTypeMSSQL, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeClickhouse,
+						Schema:       constants.GetLLMResponseSchema(constants.Anthropic, constants.DatabaseTypeClickhouse),
+						SystemPrompt: constants.GetSystemPrompt(constants.Anthropic, constants.DatabaseTypeClickhouse, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeMongoDB,
+						Schema:       constants.GetLLMResponseSchema(constants.Anthropic, constants.DatabaseTypeMongoDB),
+						SystemPrompt: constants.GetSystemPrompt(constants.Anthropic, constants.DatabaseTypeMongoDB, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeRedis,
+						Schema:       constants.GetLLMResponseSchema(constants.Anthropic, constants.DatabaseTypeRedis),
+						SystemPrompt: constants.GetSystemPrompt(constants.Anthropic, constants.DatabaseTypeRedis, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeCassandra,
+						Schema:       constants.GetLLMResponseSchema(constants.Anthropic, constants.DatabaseTypeCassandra),
+						SystemPrompt: constants.GetSystemPrompt(constants.Anthropic, constants.DatabaseTypeCassandra, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeNeo4j,
+						Schema:       constants.GetLLMResponseSchema(constants.Anthropic, constants.DatabaseTypeNeo4j),
+						SystemPrompt: constants.GetSystemPrompt(constants.Anthropic, constants.DatabaseTypeNeo4j, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeSQLite,
+						Schema:       constants.GetLLMResponseSchema(constants.Anthropic, constants.DatabaseTypeSQLite),
+						SystemPrompt: constants.GetSystemPrompt(constants.Anthropic, constants.DatabaseTypeSQLite, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeDuckDB,
+						Schema:       constants.GetLLMResponseSchema(constants.Anthropic, constants.DatabaseTypeDuckDB),
+						SystemPrompt: constants.GetSystemPrompt(constants.Anthropic, constants.DatabaseTypeDuckDB, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeSpreadsheet,
+						Schema:       constants.GetLLMResponseSchema(constants.Anthropic, constants.DatabaseTypeSpreadsheet),
+						SystemPrompt: constants.GetSystemPrompt(constants.Anthropic, constants.DatabaseTypeSpreadsheet, false),
+					},
+				},
+			})
+			if err != nil {
+				log.Printf("Warning: Failed to register Anthropic client: %v", err)
+			}
+		}
+		if config.Env.OllamaEnabled {
+			// Register the self-hosted model client, named openai-compatible when that is the default client
+			selfHostedProvider := constants.Ollama
+			if config.Env.DefaultLLMClient == constants.OpenAICompatible {
+				selfHostedProvider = constants.OpenAICompatible
+			}
+			err := manager.RegisterClient(selfHostedProvider, llm.Config{
+				Provider:            selfHostedProvider,
+				Model:               config.Env.OllamaModel,
+				Models:              config.Env.OllamaModels,
+				APIKey:              config.Env.OllamaAPIKey,
+				BaseURL:             config.Env.OllamaBaseURL,
+				MaxCompletionTokens: config.Env.OllamaMaxCompletionTokens,
+				Temperature:         config.Env.OllamaTemperature,
+				ContextLimit:        config.Env.OllamaContextLimit,
+				EmbeddingModel:      config.Env.OllamaEmbeddingModel,
+				// Servers without structured output get the schema in the prompt instead
+				DisableStructuredOutput: !config.Env.OllamaStructuredOutput,
+				DBConfigs: []llm.LLMDBConfig{
+					{
+						DBType:       constants.DatabaseTypePostgreSQL,
+						Schema:       constants.GetLLMResponseSchema(selfHostedProvider, constants.DatabaseTypePostgreSQL),
+						SystemPrompt: constants.GetSystemPrompt(selfHostedProvider, constants.DatabaseTypePostgreSQL, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeYugabyteDB,
+						Schema:       constants.GetLLMResponseSchema(selfHostedProvider, constants.DatabaseTypeYugabyteDB),
+						SystemPrompt: constants.GetSystemPrompt(selfHostedProvider, constants.DatabaseTypeYugabyteDB, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeMySQL,
+						Schema:       constants.GetLLMResponseSchema(selfHostedProvider, constants.DatabaseTypeMySQL),
+						SystemPrompt: constants.GetSystemPrompt(selfHostedProvider, constants.DatabaseTypeMySQL, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeMSSQL,
+						Schema:       constants.GetLLMResponseSchema(selfHostedProvider, constants.DatabaseTypeMSSQL),
+						SystemPrompt: constants.GetSystemPrompt(selfHostedProvider, constants.DatabaseTypeMSSQL, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeClickhouse,
+						Schema:       constants.GetLLMResponseSchema(selfHostedProvider, constants.DatabaseTypeClickhouse),
+						SystemPrompt: constants.GetSystemPrompt(selfHostedProvider, constants.DatabaseTypeClickhouse, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeMongoDB,
+						Schema:       constants.GetLLMResponseSchema(selfHostedProvider, constants.DatabaseTypeMongoDB),
+						SystemPrompt: constants.GetSystemPrompt(selfHostedProvider, constants.DatabaseTypeMongoDB, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeRedis,
+						Schema:       constants.GetLLMResponseSchema(selfHostedProvider, constants.DatabaseTypeRedis),
+						SystemPrompt: constants.GetSystemPrompt(selfHostedProvider, constants.DatabaseTypeRedis, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeCassandra,
+						Schema:       constants.GetLLMResponseSchema(selfHostedProvider, constants.DatabaseTypeCassandra),
+						SystemPrompt: constants.GetSystemPrompt(selfHostedProvider, constants.DatabaseTypeCassandra, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeNeo4j,
+						Schema:       constants.GetLLMResponseSchema(selfHostedProvider, constants.DatabaseTypeNeo4j),
+						SystemPrompt: constants.GetSystemPrompt(selfHostedProvider, constants.DatabaseTypeNeo4j, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeSQLite,
+						Schema:       constants.GetLLMResponseSchema(selfHostedProvider, constants.DatabaseTypeSQLite),
+						SystemPrompt: constants.GetSystemPrompt(selfHostedProvider, constants.DatabaseTypeSQLite, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeDuckDB,
+						Schema:       constants.GetLLMResponseSchema(selfHostedProvider, constants.DatabaseTypeDuckDB),
+						SystemPrompt: constants.GetSystemPrompt(selfHostedProvider, constants.DatabaseTypeDuckDB, false),
+					},
+					{
+						DBType:       constants.DatabaseTypeSpreadsheet,
+						Schema:       constants.GetLLMResponseSchema(selfHostedProvider, constants.DatabaseTypeSpreadsheet),
+						SystemPrompt: constants.GetSystemPrompt(selfHostedProvider, constants.DatabaseTypeSpreadsheet, false),
+					},
+				},
+			})
+			if err != nil {
+				log.Printf("Warning: Failed to register %s client: %v", selfHostedProvider, err)
+			}
+		}
+
+		// Retry and fallback policy used by the chat service
+		manager.SetFallbackPolicy(llm.FallbackPolicy{
+			Providers:        config.Env.LLMFallbackProviders,
+			MaxAttempts:      config.Env.LLMMaxAttempts,
+			BaseDelay:        time.Duration(config.Env.LLMRetryBaseDelayMs) * time.Millisecond,
+			MaxDelay:         time.Duration(config.Env.LLMRetryMaxDelayMs) * time.Millisecond,
+			BreakerThreshold: config.Env.LLMCircuitBreakerFailures,
+			BreakerCooldown:  time.Duration(config.Env.LLMCircuitBreakerCooldown) * time.Second,
+		})
 		return manager
 	}); err != nil {
 		log.Fatalf("Failed to provide LLM manager: %v", err)
@@ -267,7 +531,7 @@ func Initialize() {
 			log.Printf("Warning: Failed to get default LLM client: %v", err)
 		}
 
-		chatService := services.NewChatService(chatRepo, llmRepo, dbManager, llmClient)
+		chatService := services.NewChatService(chatRepo, llmRepo, dbManager, llmClient, llmManager)
 
 		// Set chat service as stream handler for DB manager
 		dbManager.SetStreamHandler(chatService)
@@ -313,6 +577,88 @@ func Initialize() {
 	}); err != nil {
 		log.Fatalf("Failed to provide chat handler: %v", err)
 	}
+
+	// Saved queries
+	if err := DiContainer.Provide(func(db *mongodb.MongoDBClient) repositories.SavedQueryRepository {
+		return repositories.NewSavedQueryRepository(db)
+	}); err != nil {
+		log.Fatalf("Failed to provide saved query repository: %v", err)
+	}
+
+	if err := DiContainer.Provide(func(
+		savedQueryRepo repositories.SavedQueryRepository,
+		chatRepo repositories.ChatRepository,
+		chatService services.ChatService,
+		dbManager *dbmanager.Manager,
+	) services.SavedQueryService {
+		return services.NewSavedQueryService(savedQueryRepo, chatRepo, chatService, dbManager)
+	}); err != nil {
+		log.Fatalf("Failed to provide saved query service: %v", err)
+	}
+
+	if err := DiContainer.Provide(func(savedQueryService services.SavedQueryService) *handlers.SavedQueryHandler {
+		return handlers.NewSavedQueryHandler(savedQueryService)
+	}); err != nil {
+		log.Fatalf("Failed to provide saved query handler: %v", err)
+	}
+
+	// Scheduled queries
+	if err := DiContainer.Provide(func(db *mongodb.MongoDBClient) repositories.ScheduledQueryRepository {
+		return repositories.NewScheduledQueryRepository(db)
+	}); err != nil {
+		log.Fatalf("Failed to provide scheduled query repository: %v", err)
+	}
+
+	// Alerts on scheduled queries
+	if err := DiContainer.Provide(func(db *mongodb.MongoDBClient) repositories.AlertRepository {
+		return repositories.NewAlertRepository(db)
+	}); err != nil {
+		log.Fatalf("Failed to provide alert repository: %v", err)
+	}
+
+	if err := DiContainer.Provide(func(
+		alertRepo repositories.AlertRepository,
+		scheduledQueryRepo repositories.ScheduledQueryRepository,
+		userRepo repositories.UserRepository,
+		emailService services.EmailService,
+	) services.AlertService {
+		return services.NewAlertService(alertRepo, scheduledQueryRepo, userRepo, emailService)
+	}); err != nil {
+		log.Fatalf("Failed to provide alert service: %v", err)
+	}
+
+	if err := DiContainer.Provide(func(alertService services.AlertService) *handlers.AlertHandler {
+		return handlers.NewAlertHandler(alertService)
+	}); err != nil {
+		log.Fatalf("Failed to provide alert handler: %v", err)
+	}
+
+	if err := DiContainer.Provide(func(
+		scheduledQueryRepo repositories.ScheduledQueryRepository,
+		savedQueryRepo repositories.SavedQueryRepository,
+		chatRepo repositories.ChatRepository,
+		chatService services.ChatService,
+		alertService services.AlertService,
+		dbManager *dbmanager.Manager,
+		redisRepo redis.IRedisRepositories,
+	) services.ScheduledQueryService {
+		return services.NewScheduledQueryService(scheduledQueryRepo, savedQueryRepo, chatRepo, chatService, alertService, dbManager, redisRepo)
+	}); err != nil {
+		log.Fatalf("Failed to provide scheduled query service: %v", err)
+	}
+
+	if err := DiContainer.Provide(func(scheduledQueryService services.ScheduledQueryService) *handlers.ScheduledQueryHandler {
+		return handlers.NewScheduledQueryHandler(scheduledQueryService)
+	}); err != nil {
+		log.Fatalf("Failed to provide scheduled query handler: %v", err)
+	}
+
+	// Start running the scheduled queries
+	if err := DiContainer.Invoke(func(scheduledQueryService services.ScheduledQueryService) {
+		scheduledQueryService.StartScheduler()
+	}); err != nil {
+		log.Fatalf("Failed to start the scheduled queries: %v", err)
+	}
 }
 
 // GetAuthHandler retrieves the AuthHandler from the DI container
@@ -362,3 +708,39 @@ func GetWaitlistHandler() (*handlers.WaitlistHandler, error) {
 	}
 	return handler, nil
 }
+
+// GetSavedQueryHandler retrieves the SavedQueryHandler from the DI container
+func GetSavedQueryHandler() (*handlers.SavedQueryHandler, error) {
+	var handler *handlers.SavedQueryHandler
+	err := DiContainer.Invoke(func(h *handlers.SavedQueryHandler) {
+		handler = h
+	})
+	if err != nil {
+		return nil, err
+	}
+	return handler, nil
+}
+
+// GetScheduledQueryHandler retrieves the ScheduledQueryHandler from the DI container
+func GetScheduledQueryHandler() (*handlers.ScheduledQueryHandler, error) {
+	var handler *handlers.ScheduledQueryHandler
+	err := DiContainer.Invoke(func(h *handlers.ScheduledQueryHandler) {
+		handler = h
+	})
+	if err != nil {
+		return nil, err
+	}
+	return handler, nil
+}
+
+// GetAlertHandler retrieves the AlertHandler from the DI container
+func GetAlertHandler() (*handlers.AlertHandler, error) {
+	var handler *handlers.AlertHandler
+	err := DiContainer.Invoke(func(h *handlers.AlertHandler) {
+		handler = h
+	})
+	if err != nil {
+		return nil, err
+	}
+	return handler, nil
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AlertRule notifies when its condition starts holding on the snapshots of a scheduled query, and when it stops
+type AlertRule struct {
+	UserID           primitive.ObjectID `bson:"user_id" json:"user_id"`
+	ScheduledQueryID primitive.ObjectID `bson:"scheduled_query_id" json:"scheduled_query_id"`
+	Name             string             `bson:"name" json:"name"`
+	Condition        QueryThreshold     `bson:"condition" json:"condition"`
+	Emails           []string           `bson:"emails" json:"emails"`
+	Webhooks         []string           `bson:"webhooks" json:"webhooks"`
+	WebhookSecret    string             `bson:"webhook_secret" json:"-"` // Encrypted, signs the webhook payloads
+	CooldownMinutes  int                `bson:"cooldown_minutes" json:"cooldown_minutes"`
+	NotifyOnResolve  bool               `bson:"notify_on_resolve" json:"notify_on_resolve"`
+	Enabled          bool               `bson:"enabled" json:"enabled"`
+	Firing           bool               `bson:"firing" json:"firing"`
+	Pending          bool               `bson:"pending" json:"pending"`                           // Holds but not notified yet, e.g. within the cooldown
+	LastValue        *float64           `bson:"last_value,omitempty" json:"last_value,omitempty"` // Metric of the last snapshot, for the change operators
+	LastEvaluatedAt  *time.Time         `bson:"last_evaluated_at,omitempty" json:"last_evaluated_at,omitempty"`
+	LastFiredAt      *time.Time         `bson:"last_fired_at,omitempty" json:"last_fired_at,omitempty"`
+	LastNotifiedAt   *time.Time         `bson:"last_notified_at,omitempty" json:"last_notified_at,omitempty"`
+	Base             `bson:",inline"`
+}
+
+// AlertDelivery is a notification of an alert event sent to an email address or a webhook
+type AlertDelivery struct {
+	Channel    string `bson:"channel" json:"channel"` // email or webhook
+	Target     string `bson:"target" json:"target"`
+	Success    bool   `bson:"success" json:"success"`
+	StatusCode int    `bson:"status_code,omitempty" json:"status_code,omitempty"`
+	Attempts   int    `bson:"attempts" json:"attempts"`
+	Error      string `bson:"error,omitempty" json:"error,omitempty"`
+}
+
+// AlertEvent records an alert rule starting or stopping to fire
+type AlertEvent struct {
+	RuleID           primitive.ObjectID `bson:"rule_id" json:"rule_id"`
+	UserID           primitive.ObjectID `bson:"user_id" json:"user_id"`
+	ScheduledQueryID primitive.ObjectID `bson:"scheduled_query_id" json:"scheduled_query_id"`
+	SnapshotID       primitive.ObjectID `bson:"snapshot_id" json:"snapshot_id"`
+	RuleName         string             `bson:"rule_name" json:"rule_name"`
+	Status           string             `bson:"status" json:"status"` // firing or resolved
+	Condition        QueryThreshold     `bson:"condition" json:"condition"`
+	Actual           *float64           `bson:"actual,omitempty" json:"actual,omitempty"`
+	Previous         *float64           `bson:"previous,omitempty" json:"previous,omitempty"`
+	Notified         bool               `bson:"notified" json:"notified"`
+	Suppressed       string             `bson:"suppressed,omitempty" json:"suppressed,omitempty"` // Why no notification was sent, e.g. cooldown
+	Deliveries       []AlertDelivery    `bson:"deliveries" json:"deliveries"`
+	Base             `bson:",inline"`
+}
+
+func NewAlertRule(userID, scheduledQueryID primitive.ObjectID, name string, condition QueryThreshold, emails, webhooks []string, cooldownMinutes int, notifyOnResolve bool) *AlertRule {
+	return &AlertRule{
+		UserID:           userID,
+		ScheduledQueryID: scheduledQueryID,
+		Name:             name,
+		Condition:        condition,
+		Emails:           emails,
+		Webhooks:         webhooks,
+		CooldownMinutes:  cooldownMinutes,
+		NotifyOnResolve:  notifyOnResolve,
+		Enabled:          true,
+		Base:             NewBase(),
+	}
+}
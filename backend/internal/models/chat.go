@@ -5,9 +5,15 @@ import (
 )
 
 type ChatSettings struct {
-	AutoExecuteQuery bool `bson:"auto_execute_query" json:"auto_execute_query,omitempty"` // default is false, Execute query automatically when LLM response is received
-	ShareDataWithAI  bool `bson:"share_data_with_ai" json:"share_data_with_ai,omitempty"` // default is false, Don't share data with AI
-	NonTechMode      bool `bson:"non_tech_mode" json:"non_tech_mode,omitempty"`           // default is false, Enable non-technical mode for simplified responses
+	AutoExecuteQuery bool   `bson:"auto_execute_query" json:"auto_execute_query,omitempty"` // default is false, Execute query automatically when LLM response is received
+	ShareDataWithAI  bool   `bson:"share_data_with_ai" json:"share_data_with_ai,omitempty"` // default is false, Don't share data with AI
+	NonTechMode      bool   `bson:"non_tech_mode" json:"non_tech_mode,omitempty"`           // default is false, Enable non-technical mode for simplified responses
+	LLMProvider      string `bson:"llm_provider" json:"llm_provider,omitempty"`             // default is empty, Use DEFAULT_LLM_CLIENT
+	LLMModel         string `bson:"llm_model" json:"llm_model,omitempty"`                   // default is empty, Use the provider's default model
+	AgentMode        bool   `bson:"agent_mode" json:"agent_mode,omitempty"`                 // default is false, Let the LLM explore the database with tools before answering
+	ReadOnly         bool   `bson:"read_only" json:"read_only,omitempty"`                   // default is false, Open the database with read-only sessions, no writes are allowed
+	MaxResultRows    int    `bson:"max_result_rows" json:"max_result_rows,omitempty"`       // default is 0, Use QUERY_RESULT_MAX_ROWS as the cap of the rows streamed for a query
+	MaxResultBytes   int    `bson:"max_result_bytes" json:"max_result_bytes,omitempty"`     // default is 0, Use QUERY_RESULT_MAX_BYTES as the cap of the size of a streamed result
 }
 
 type Connection struct {
@@ -27,6 +33,16 @@ type Connection struct {
 	SSLKeyURL      *string `bson:"ssl_key_url,omitempty" json:"ssl_key_url,omitempty"`
 	SSLRootCertURL *string `bson:"ssl_root_cert_url,omitempty" json:"ssl_root_cert_url,omitempty"`
 
+	// SSH Tunnel Configuration
+	SSHEnabled     bool    `bson:"ssh_enabled" json:"ssh_enabled"`
+	SSHHost        *string `bson:"ssh_host,omitempty" json:"ssh_host,omitempty"`
+	SSHPort        *string `bson:"ssh_port,omitempty" json:"ssh_port,omitempty"`
+	SSHUsername    *string `bson:"ssh_username,omitempty" json:"ssh_username,omitempty"`
+	SSHPrivateKey  *string `bson:"ssh_private_key,omitempty" json:"-"`                                         // Hide in JSON
+	SSHPassphrase  *string `bson:"ssh_passphrase,omitempty" json:"-"`                                          // Hide in JSON
+	SSHHostKey     *string `bson:"ssh_host_key,omitempty" json:"ssh_host_key,omitempty"`                       // Public key or SHA256 fingerprint of the bastion
+	SSHSkipHostKey bool    `bson:"ssh_skip_host_key_check,omitempty" json:"ssh_skip_host_key_check,omitempty"` // Accept any bastion host key, an explicit opt-in
+
 	Base `bson:",inline"`
 }
 
@@ -53,5 +69,9 @@ func DefaultChatSettings() ChatSettings {
 		AutoExecuteQuery: true,  // default is true, Execute query automatically when LLM response is received
 		ShareDataWithAI:  false, // default is false, Don't share data with AI
 		NonTechMode:      false, // default is false, Technical mode enabled by default
+		AgentMode:        false, // default is false, Answer in a single LLM request
+		ReadOnly:         false, // default is false, Queries may write after the user's approval
+		MaxResultRows:    0,     // default is 0, Use QUERY_RESULT_MAX_ROWS
+		MaxResultBytes:   0,     // default is 0, Use QUERY_RESULT_MAX_BYTES
 	}
 }
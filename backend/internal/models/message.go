@@ -19,9 +19,18 @@ type Message struct {
 	NonTechMode   bool                `bson:"non_tech_mode" json:"non_tech_mode"`                       // Whether this message was generated in non-tech mode
 	IsPinned      bool                `bson:"is_pinned" json:"is_pinned"`                               // Whether this message is pinned
 	PinnedAt      *time.Time          `bson:"pinned_at,omitempty" json:"pinned_at,omitempty"`           // When the message was pinned
+	LLMMetadata   *LLMMetadata        `bson:"llm_metadata,omitempty" json:"llm_metadata,omitempty"`     // Which LLM provider generated this message, only for Type assistant
 	Base          `bson:",inline"`
 }
 
+// LLMMetadata records the LLM provider and model that answered, for auditing response quality per provider
+type LLMMetadata struct {
+	Provider        string   `bson:"provider" json:"provider"`
+	Model           string   `bson:"model" json:"model"`
+	Attempts        int      `bson:"attempts" json:"attempts"`                                     // Requests made including retries and fallbacks
+	FailedProviders []string `bson:"failed_providers,omitempty" json:"failed_providers,omitempty"` // Providers that failed before the answering one
+}
+
 // ActionButton represents a UI action button that can be suggested by the LLM
 type ActionButton struct {
 	ID        primitive.ObjectID `bson:"id" json:"id"`
@@ -51,6 +60,41 @@ type Query struct {
 	IsEdited               bool               `bson:"is_edited" json:"is_edited"`                                   // if the query has been edited
 	Metadata               *string            `bson:"metadata,omitempty" json:"metadata,omitempty"`                 // JSON string for database-specific metadata (e.g., ClickHouse engine type)
 	ActionAt               *string            `bson:"action_at,omitempty" json:"action_at,omitempty"`               // The timestamp when the action was taken
+	OriginalQuery          *string            `bson:"original_query,omitempty" json:"original_query,omitempty"`     // The query of the LLM before it was auto-fixed
+	FixAttempts            []QueryFixAttempt  `bson:"fix_attempts,omitempty" json:"fix_attempts,omitempty"`         // Failed runs replaced by a corrected query, oldest first
+	SafetyWarnings         []string           `bson:"safety_warnings,omitempty" json:"safety_warnings,omitempty"`   // Dangerous patterns found by the static analysis, e.g. a DELETE without WHERE
+	ResultHandle           *QueryResultHandle `bson:"result_handle,omitempty" json:"result_handle,omitempty"`       // Set when the result was streamed, ExecutionResult then only holds its preview
+	RollbackSnapshot       *string            `bson:"rollback_snapshot,omitempty" json:"-"`                         // Encrypted JSON of the rows an UPDATE/DELETE changed, restored on rollback
+	RollbackSnapshotInfo   *QuerySnapshotInfo `bson:"rollback_snapshot_info,omitempty" json:"rollback_snapshot_info,omitempty"`
+}
+
+// QuerySnapshotInfo describes the snapshot of a query without its rows
+type QuerySnapshotInfo struct {
+	Operation  string `bson:"operation" json:"operation"` // update or delete
+	Table      string `bson:"table" json:"table"`         // Table or collection
+	RowCount   int    `bson:"row_count" json:"row_count"`
+	CapturedAt string `bson:"captured_at" json:"captured_at"`
+}
+
+// QueryResultHandle describes a streamed result, whose rows were sent to the client but not stored
+type QueryResultHandle struct {
+	ID            string   `bson:"id" json:"id"`                         // Sent with each query-result-chunk event of the result
+	ExecutedQuery string   `bson:"executed_query" json:"executed_query"` // The query that was streamed, e.g. the paginated query
+	Columns       []string `bson:"columns,omitempty" json:"columns,omitempty"`
+	RowCount      int64    `bson:"row_count" json:"row_count"`
+	ByteCount     int64    `bson:"byte_count" json:"byte_count"` // Size of the rows as JSON
+	PreviewRows   int      `bson:"preview_rows" json:"preview_rows"`
+	Truncated     bool     `bson:"truncated" json:"truncated"`
+	TruncatedBy   string   `bson:"truncated_by,omitempty" json:"truncated_by,omitempty"` // max_rows or max_bytes
+	ExecutedAt    string   `bson:"executed_at" json:"executed_at"`
+}
+
+// QueryFixAttempt is a failed run of a query that the LLM corrected
+type QueryFixAttempt struct {
+	Query       string     `bson:"query" json:"query"`             // The query that failed
+	Error       QueryError `bson:"error" json:"error"`             // The error it failed with
+	Explanation string     `bson:"explanation" json:"explanation"` // What the LLM changed in the next query
+	AttemptedAt string     `bson:"attempted_at" json:"attempted_at"`
 }
 
 type QueryError struct {
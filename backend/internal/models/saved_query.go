@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SavedQueryParam is a {{name}} placeholder of a saved query
+type SavedQueryParam struct {
+	Name        string      `bson:"name" json:"name"`
+	Type        string      `bson:"type" json:"type"` // string, integer, number, boolean, date or timestamp
+	Required    bool        `bson:"required" json:"required"`
+	Default     interface{} `bson:"default,omitempty" json:"default,omitempty"`
+	Description string      `bson:"description,omitempty" json:"description,omitempty"`
+}
+
+// SavedQuery is a query of a user's library, run against the connection of a chat
+type SavedQuery struct {
+	UserID          primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	ChatID          primitive.ObjectID  `bson:"chat_id" json:"chat_id"`                 // Chat whose connection the query targets
+	ConnectionType  string              `bson:"connection_type" json:"connection_type"` // Type of the database, the query only runs on connections of this type
+	Name            string              `bson:"name" json:"name"`
+	Description     string              `bson:"description" json:"description"`
+	Tags            []string            `bson:"tags" json:"tags"`
+	Query           string              `bson:"query" json:"query"`
+	QueryType       *string             `bson:"query_type,omitempty" json:"query_type,omitempty"`
+	Params          []SavedQueryParam   `bson:"params" json:"params"`
+	SourceMessageID *primitive.ObjectID `bson:"source_message_id,omitempty" json:"source_message_id,omitempty"` // Set when saved from the query of a message
+	SourceQueryID   *primitive.ObjectID `bson:"source_query_id,omitempty" json:"source_query_id,omitempty"`
+	LastRunAt       *time.Time          `bson:"last_run_at,omitempty" json:"last_run_at,omitempty"`
+	Base            `bson:",inline"`
+}
+
+func NewSavedQuery(userID, chatID primitive.ObjectID, connectionType, name, description string, tags []string, query string, queryType *string, params []SavedQueryParam) *SavedQuery {
+	return &SavedQuery{
+		UserID:         userID,
+		ChatID:         chatID,
+		ConnectionType: connectionType,
+		Name:           name,
+		Description:    description,
+		Tags:           tags,
+		Query:          query,
+		QueryType:      queryType,
+		Params:         params,
+		Base:           NewBase(),
+	}
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QueryThreshold compares a metric of each snapshot of a scheduled query with a value, or with the previous snapshot
+type QueryThreshold struct {
+	Metric   string  `bson:"metric" json:"metric"`                     // row_count, or value of Column in the first row
+	Column   string  `bson:"column,omitempty" json:"column,omitempty"` // Column read by the value metric
+	Operator string  `bson:"operator" json:"operator"`                 // gt, gte, lt, lte, eq, ne, change or change_pct
+	Value    float64 `bson:"value" json:"value"`
+}
+
+// ScheduledQuery runs a read-only query on a cron expression against the connection of a chat
+type ScheduledQuery struct {
+	UserID          primitive.ObjectID     `bson:"user_id" json:"user_id"`
+	ChatID          primitive.ObjectID     `bson:"chat_id" json:"chat_id"`
+	Name            string                 `bson:"name" json:"name"`
+	Query           string                 `bson:"query" json:"query"` // Bound query, refreshed from the saved query on each run
+	QueryType       *string                `bson:"query_type,omitempty" json:"query_type,omitempty"`
+	SavedQueryID    *primitive.ObjectID    `bson:"saved_query_id,omitempty" json:"saved_query_id,omitempty"` // Set when scheduling a saved query
+	Params          map[string]interface{} `bson:"params,omitempty" json:"params,omitempty"`                 // Values of the parameters of the saved query
+	SourceMessageID *primitive.ObjectID    `bson:"source_message_id,omitempty" json:"source_message_id,omitempty"`
+	SourceQueryID   *primitive.ObjectID    `bson:"source_query_id,omitempty" json:"source_query_id,omitempty"`
+	CronExpression  string                 `bson:"cron_expression" json:"cron_expression"`
+	Timezone        string                 `bson:"timezone" json:"timezone"`
+	Enabled         bool                   `bson:"enabled" json:"enabled"`
+	Thresholds      []QueryThreshold       `bson:"thresholds" json:"thresholds"`
+	NextRunAt       *time.Time             `bson:"next_run_at,omitempty" json:"next_run_at,omitempty"`
+	LastRunAt       *time.Time             `bson:"last_run_at,omitempty" json:"last_run_at,omitempty"`
+	LastStatus      string                 `bson:"last_status,omitempty" json:"last_status,omitempty"` // succeeded or failed
+	Base            `bson:",inline"`
+}
+
+// ThresholdResult is a threshold evaluated on a snapshot, Actual is nil when the metric couldn't be read
+type ThresholdResult struct {
+	QueryThreshold `bson:",inline"`
+	Actual         *float64 `bson:"actual,omitempty" json:"actual,omitempty"`
+	Previous       *float64 `bson:"previous,omitempty" json:"previous,omitempty"`
+	Breached       bool     `bson:"breached" json:"breached"`
+	Error          string   `bson:"error,omitempty" json:"error,omitempty"`
+}
+
+// ScheduledQuerySnapshot is the result of a run of a scheduled query
+type ScheduledQuerySnapshot struct {
+	ScheduledQueryID primitive.ObjectID `bson:"scheduled_query_id" json:"scheduled_query_id"`
+	UserID           primitive.ObjectID `bson:"user_id" json:"user_id"`
+	RanAt            time.Time          `bson:"ran_at" json:"ran_at"`
+	Status           string             `bson:"status" json:"status"` // succeeded or failed
+	Query            string             `bson:"query" json:"query"`
+	ExecutionTime    *int               `bson:"execution_time,omitempty" json:"execution_time,omitempty"`
+	RowCount         int                `bson:"row_count" json:"row_count"`
+	Truncated        bool               `bson:"truncated" json:"truncated"`               // The rows were capped, RowCount is a lower bound
+	Result           *string            `bson:"result,omitempty" json:"result,omitempty"` // Encrypted, capped to the first rows
+	Error            *QueryError        `bson:"error,omitempty" json:"error,omitempty"`
+	Thresholds       []ThresholdResult  `bson:"thresholds" json:"thresholds"`
+	Breached         bool               `bson:"breached" json:"breached"`
+	Base             `bson:",inline"`
+}
+
+func NewScheduledQuery(userID, chatID primitive.ObjectID, name, query string, queryType *string, cronExpression, timezone string, thresholds []QueryThreshold) *ScheduledQuery {
+	return &ScheduledQuery{
+		UserID:         userID,
+		ChatID:         chatID,
+		Name:           name,
+		Query:          query,
+		QueryType:      queryType,
+		CronExpression: cronExpression,
+		Timezone:       timezone,
+		Enabled:        true,
+		Thresholds:     thresholds,
+		Base:           NewBase(),
+	}
+}
@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"neobase-ai/internal/models"
+	"neobase-ai/pkg/mongodb"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AlertRepository interface {
+	CreateRule(rule *models.AlertRule) error
+	UpdateRule(id primitive.ObjectID, rule *models.AlertRule) error
+	UpdateRuleState(rule *models.AlertRule) error
+	DeleteRule(id primitive.ObjectID) error
+	DeleteRulesByScheduledQueryID(scheduledQueryID primitive.ObjectID) error
+	FindRuleByID(id primitive.ObjectID) (*models.AlertRule, error)
+	FindRulesByUserID(userID primitive.ObjectID, scheduledQueryID *primitive.ObjectID, page, pageSize int) ([]*models.AlertRule, int64, error)
+	FindEnabledRulesByScheduledQueryID(scheduledQueryID primitive.ObjectID) ([]*models.AlertRule, error)
+	CreateEvent(event *models.AlertEvent) error
+	FindEvents(userID primitive.ObjectID, ruleID, scheduledQueryID *primitive.ObjectID, status string, page, pageSize int) ([]*models.AlertEvent, int64, error)
+}
+
+type alertRepository struct {
+	ruleCollection  *mongo.Collection
+	eventCollection *mongo.Collection
+}
+
+func NewAlertRepository(mongoClient *mongodb.MongoDBClient) AlertRepository {
+	return &alertRepository{
+		ruleCollection:  mongoClient.GetCollectionByName("alert_rules"),
+		eventCollection: mongoClient.GetCollectionByName("alert_events"),
+	}
+}
+
+func (r *alertRepository) CreateRule(rule *models.AlertRule) error {
+	_, err := r.ruleCollection.InsertOne(context.Background(), rule)
+	return err
+}
+
+func (r *alertRepository) UpdateRule(id primitive.ObjectID, rule *models.AlertRule) error {
+	rule.UpdatedAt = time.Now()
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": rule}
+	_, err := r.ruleCollection.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+// UpdateRuleState records the evaluation of a rule, leaving the fields the user may have changed meanwhile untouched
+func (r *alertRepository) UpdateRuleState(rule *models.AlertRule) error {
+	update := bson.M{"$set": bson.M{
+		"firing":            rule.Firing,
+		"pending":           rule.Pending,
+		"last_value":        rule.LastValue,
+		"last_evaluated_at": rule.LastEvaluatedAt,
+		"last_fired_at":     rule.LastFiredAt,
+		"last_notified_at":  rule.LastNotifiedAt,
+	}}
+	_, err := r.ruleCollection.UpdateOne(context.Background(), bson.M{"_id": rule.ID}, update)
+	return err
+}
+
+// DeleteRule deletes the rule and its events
+func (r *alertRepository) DeleteRule(id primitive.ObjectID) error {
+	if _, err := r.ruleCollection.DeleteOne(context.Background(), bson.M{"_id": id}); err != nil {
+		return err
+	}
+	_, err := r.eventCollection.DeleteMany(context.Background(), bson.M{"rule_id": id})
+	return err
+}
+
+// DeleteRulesByScheduledQueryID deletes the rules of a scheduled query and their events
+func (r *alertRepository) DeleteRulesByScheduledQueryID(scheduledQueryID primitive.ObjectID) error {
+	filter := bson.M{"scheduled_query_id": scheduledQueryID}
+	if _, err := r.ruleCollection.DeleteMany(context.Background(), filter); err != nil {
+		return err
+	}
+	_, err := r.eventCollection.DeleteMany(context.Background(), filter)
+	return err
+}
+
+func (r *alertRepository) FindRuleByID(id primitive.ObjectID) (*models.AlertRule, error) {
+	var rule models.AlertRule
+	err := r.ruleCollection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&rule)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return &rule, err
+}
+
+// FindRulesByUserID returns the rules of the user, only those of a scheduled query when its ID is given
+func (r *alertRepository) FindRulesByUserID(userID primitive.ObjectID, scheduledQueryID *primitive.ObjectID, page, pageSize int) ([]*models.AlertRule, int64, error) {
+	var rules []*models.AlertRule
+	filter := bson.M{"user_id": userID}
+	if scheduledQueryID != nil {
+		filter["scheduled_query_id"] = *scheduledQueryID
+	}
+
+	// Get total count
+	total, err := r.ruleCollection.CountDocuments(context.Background(), filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Setup pagination
+	skip := int64((page - 1) * pageSize)
+	opts := options.Find().
+		SetSkip(skip).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.ruleCollection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(context.Background())
+
+	err = cursor.All(context.Background(), &rules)
+	return rules, total, err
+}
+
+func (r *alertRepository) FindEnabledRulesByScheduledQueryID(scheduledQueryID primitive.ObjectID) ([]*models.AlertRule, error) {
+	var rules []*models.AlertRule
+	filter := bson.M{"scheduled_query_id": scheduledQueryID, "enabled": true}
+
+	cursor, err := r.ruleCollection.Find(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	err = cursor.All(context.Background(), &rules)
+	return rules, err
+}
+
+func (r *alertRepository) CreateEvent(event *models.AlertEvent) error {
+	_, err := r.eventCollection.InsertOne(context.Background(), event)
+	return err
+}
+
+// FindEvents returns the alert events of the user, the latest first, filtered by rule, scheduled query and status
+// when given
+func (r *alertRepository) FindEvents(userID primitive.ObjectID, ruleID, scheduledQueryID *primitive.ObjectID, status string, page, pageSize int) ([]*models.AlertEvent, int64, error) {
+	var events []*models.AlertEvent
+	filter := bson.M{"user_id": userID}
+	if ruleID != nil {
+		filter["rule_id"] = *ruleID
+	}
+	if scheduledQueryID != nil {
+		filter["scheduled_query_id"] = *scheduledQueryID
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	// Get total count
+	total, err := r.eventCollection.CountDocuments(context.Background(), filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Setup pagination
+	skip := int64((page - 1) * pageSize)
+	opts := options.Find().
+		SetSkip(skip).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.eventCollection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(context.Background())
+
+	err = cursor.All(context.Background(), &events)
+	return events, total, err
+}
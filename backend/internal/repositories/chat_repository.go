@@ -25,6 +25,7 @@ type ChatRepository interface {
 	FindMessagesByChat(chatID primitive.ObjectID, page, pageSize int) ([]*models.Message, int64, error)
 	FindLatestMessageByChat(chatID primitive.ObjectID, page, pageSize int) ([]*models.Message, int64, error)
 	FindMessageByID(id primitive.ObjectID) (*models.Message, error)
+	FindMessageByQueryID(chatID, queryID primitive.ObjectID) (*models.Message, error)
 	FindNextMessageByID(id primitive.ObjectID) (*models.Message, error)
 	FindPinnedMessagesByChat(chatID primitive.ObjectID) ([]models.Message, error)
 	FindMessagesByChatAfterTime(chatID primitive.ObjectID, after time.Time, page, pageSize int) ([]models.Message, int64, error)
@@ -179,6 +180,13 @@ func (r *chatRepository) FindMessageByID(id primitive.ObjectID) (*models.Message
 	return &message, err
 }
 
+// FindMessageByQueryID finds the message of a chat holding a query
+func (r *chatRepository) FindMessageByQueryID(chatID, queryID primitive.ObjectID) (*models.Message, error) {
+	var message models.Message
+	err := r.messageCollection.FindOne(context.Background(), bson.M{"chat_id": chatID, "queries.id": queryID}).Decode(&message)
+	return &message, err
+}
+
 func (r *chatRepository) updateChatTimeStamp(chatID primitive.ObjectID) error {
 	go func() {
 		filter := bson.M{"_id": chatID}
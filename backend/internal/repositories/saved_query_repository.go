@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"neobase-ai/internal/models"
+	"neobase-ai/pkg/mongodb"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SavedQueryFilter narrows the saved queries of a user, empty fields match every query
+type SavedQueryFilter struct {
+	ChatID primitive.ObjectID
+	Tag    string
+	Search string // Matched against the name and the description
+}
+
+type SavedQueryRepository interface {
+	Create(savedQuery *models.SavedQuery) error
+	Update(id primitive.ObjectID, savedQuery *models.SavedQuery) error
+	Delete(id primitive.ObjectID) error
+	FindByID(id primitive.ObjectID) (*models.SavedQuery, error)
+	FindByUserID(userID primitive.ObjectID, filter SavedQueryFilter, page, pageSize int) ([]*models.SavedQuery, int64, error)
+}
+
+type savedQueryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSavedQueryRepository(mongoClient *mongodb.MongoDBClient) SavedQueryRepository {
+	return &savedQueryRepository{
+		collection: mongoClient.GetCollectionByName("saved_queries"),
+	}
+}
+
+func (r *savedQueryRepository) Create(savedQuery *models.SavedQuery) error {
+	_, err := r.collection.InsertOne(context.Background(), savedQuery)
+	return err
+}
+
+func (r *savedQueryRepository) Update(id primitive.ObjectID, savedQuery *models.SavedQuery) error {
+	savedQuery.UpdatedAt = time.Now()
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": savedQuery}
+	_, err := r.collection.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+func (r *savedQueryRepository) Delete(id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(context.Background(), bson.M{"_id": id})
+	return err
+}
+
+func (r *savedQueryRepository) FindByID(id primitive.ObjectID) (*models.SavedQuery, error) {
+	var savedQuery models.SavedQuery
+	err := r.collection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&savedQuery)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return &savedQuery, err
+}
+
+func (r *savedQueryRepository) FindByUserID(userID primitive.ObjectID, filter SavedQueryFilter, page, pageSize int) ([]*models.SavedQuery, int64, error) {
+	var savedQueries []*models.SavedQuery
+	query := bson.M{"user_id": userID}
+	if !filter.ChatID.IsZero() {
+		query["chat_id"] = filter.ChatID
+	}
+	if filter.Tag != "" {
+		query["tags"] = filter.Tag
+	}
+	if filter.Search != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(filter.Search), Options: "i"}
+		query["$or"] = bson.A{bson.M{"name": pattern}, bson.M{"description": pattern}}
+	}
+
+	// Get total count
+	total, err := r.collection.CountDocuments(context.Background(), query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Setup pagination
+	skip := int64((page - 1) * pageSize)
+	opts := options.Find().
+		SetSkip(skip).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "updated_at", Value: -1}})
+
+	cursor, err := r.collection.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(context.Background())
+
+	err = cursor.All(context.Background(), &savedQueries)
+	return savedQueries, total, err
+}
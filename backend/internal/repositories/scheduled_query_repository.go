@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"context"
+	"neobase-ai/internal/models"
+	"neobase-ai/pkg/mongodb"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ScheduledQueryRepository interface {
+	Create(scheduledQuery *models.ScheduledQuery) error
+	Update(id primitive.ObjectID, scheduledQuery *models.ScheduledQuery) error
+	Delete(id primitive.ObjectID) error
+	FindByID(id primitive.ObjectID) (*models.ScheduledQuery, error)
+	FindByUserID(userID primitive.ObjectID, page, pageSize int) ([]*models.ScheduledQuery, int64, error)
+	FindDue(now time.Time, limit int) ([]*models.ScheduledQuery, error)
+	ClaimRun(id primitive.ObjectID, nextRunAt time.Time, newNextRunAt *time.Time) (bool, error)
+	UpdateLastRun(id primitive.ObjectID, lastRunAt time.Time, status, query string) error
+	CreateSnapshot(snapshot *models.ScheduledQuerySnapshot) error
+	FindSnapshots(scheduledQueryID primitive.ObjectID, page, pageSize int) ([]*models.ScheduledQuerySnapshot, int64, error)
+	FindLatestSnapshot(scheduledQueryID primitive.ObjectID, status string) (*models.ScheduledQuerySnapshot, error)
+	DeleteSnapshotsBeyond(scheduledQueryID primitive.ObjectID, keep int) error
+}
+
+type scheduledQueryRepository struct {
+	collection         *mongo.Collection
+	snapshotCollection *mongo.Collection
+}
+
+func NewScheduledQueryRepository(mongoClient *mongodb.MongoDBClient) ScheduledQueryRepository {
+	return &scheduledQueryRepository{
+		collection:         mongoClient.GetCollectionByName("scheduled_queries"),
+		snapshotCollection: mongoClient.GetCollectionByName("scheduled_query_snapshots"),
+	}
+}
+
+func (r *scheduledQueryRepository) Create(scheduledQuery *models.ScheduledQuery) error {
+	_, err := r.collection.InsertOne(context.Background(), scheduledQuery)
+	return err
+}
+
+func (r *scheduledQueryRepository) Update(id primitive.ObjectID, scheduledQuery *models.ScheduledQuery) error {
+	scheduledQuery.UpdatedAt = time.Now()
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": scheduledQuery}
+	_, err := r.collection.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+// Delete deletes the scheduled query and its snapshots
+func (r *scheduledQueryRepository) Delete(id primitive.ObjectID) error {
+	if _, err := r.collection.DeleteOne(context.Background(), bson.M{"_id": id}); err != nil {
+		return err
+	}
+	_, err := r.snapshotCollection.DeleteMany(context.Background(), bson.M{"scheduled_query_id": id})
+	return err
+}
+
+func (r *scheduledQueryRepository) FindByID(id primitive.ObjectID) (*models.ScheduledQuery, error) {
+	var scheduledQuery models.ScheduledQuery
+	err := r.collection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&scheduledQuery)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return &scheduledQuery, err
+}
+
+func (r *scheduledQueryRepository) FindByUserID(userID primitive.ObjectID, page, pageSize int) ([]*models.ScheduledQuery, int64, error) {
+	var scheduledQueries []*models.ScheduledQuery
+	filter := bson.M{"user_id": userID}
+
+	// Get total count
+	total, err := r.collection.CountDocuments(context.Background(), filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Setup pagination
+	skip := int64((page - 1) * pageSize)
+	opts := options.Find().
+		SetSkip(skip).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(context.Background())
+
+	err = cursor.All(context.Background(), &scheduledQueries)
+	return scheduledQueries, total, err
+}
+
+// FindDue returns the enabled scheduled queries whose next run is due, the most overdue first
+func (r *scheduledQueryRepository) FindDue(now time.Time, limit int) ([]*models.ScheduledQuery, error) {
+	var scheduledQueries []*models.ScheduledQuery
+	filter := bson.M{
+		"enabled":     true,
+		"next_run_at": bson.M{"$lte": now},
+	}
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "next_run_at", Value: 1}})
+
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	err = cursor.All(context.Background(), &scheduledQueries)
+	return scheduledQueries, err
+}
+
+// ClaimRun moves the next run of a scheduled query, unless another instance already did. Returns false when the run
+// was claimed elsewhere or the query was disabled.
+func (r *scheduledQueryRepository) ClaimRun(id primitive.ObjectID, nextRunAt time.Time, newNextRunAt *time.Time) (bool, error) {
+	filter := bson.M{"_id": id, "enabled": true, "next_run_at": nextRunAt}
+	update := bson.M{"$set": bson.M{"next_run_at": newNextRunAt}}
+	result, err := r.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// UpdateLastRun records a run, leaving the fields the user may have changed meanwhile untouched
+func (r *scheduledQueryRepository) UpdateLastRun(id primitive.ObjectID, lastRunAt time.Time, status, query string) error {
+	update := bson.M{"$set": bson.M{"last_run_at": lastRunAt, "last_status": status, "query": query}}
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": id}, update)
+	return err
+}
+
+func (r *scheduledQueryRepository) CreateSnapshot(snapshot *models.ScheduledQuerySnapshot) error {
+	_, err := r.snapshotCollection.InsertOne(context.Background(), snapshot)
+	return err
+}
+
+// FindSnapshots returns the snapshots of a scheduled query, the latest first
+func (r *scheduledQueryRepository) FindSnapshots(scheduledQueryID primitive.ObjectID, page, pageSize int) ([]*models.ScheduledQuerySnapshot, int64, error) {
+	var snapshots []*models.ScheduledQuerySnapshot
+	filter := bson.M{"scheduled_query_id": scheduledQueryID}
+
+	// Get total count
+	total, err := r.snapshotCollection.CountDocuments(context.Background(), filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Setup pagination
+	skip := int64((page - 1) * pageSize)
+	opts := options.Find().
+		SetSkip(skip).
+		SetLimit(int64(pageSize)).
+		SetSort(bson.D{{Key: "ran_at", Value: -1}})
+
+	cursor, err := r.snapshotCollection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(context.Background())
+
+	err = cursor.All(context.Background(), &snapshots)
+	return snapshots, total, err
+}
+
+// FindLatestSnapshot returns the latest snapshot of a scheduled query with the status, nil when there is none
+func (r *scheduledQueryRepository) FindLatestSnapshot(scheduledQueryID primitive.ObjectID, status string) (*models.ScheduledQuerySnapshot, error) {
+	var snapshot models.ScheduledQuerySnapshot
+	filter := bson.M{"scheduled_query_id": scheduledQueryID, "status": status}
+	opts := options.FindOne().SetSort(bson.D{{Key: "ran_at", Value: -1}})
+	err := r.snapshotCollection.FindOne(context.Background(), filter, opts).Decode(&snapshot)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return &snapshot, err
+}
+
+// DeleteSnapshotsBeyond keeps the latest snapshots of a scheduled query and deletes the older ones
+func (r *scheduledQueryRepository) DeleteSnapshotsBeyond(scheduledQueryID primitive.ObjectID, keep int) error {
+	var oldest models.ScheduledQuerySnapshot
+	filter := bson.M{"scheduled_query_id": scheduledQueryID}
+	opts := options.FindOne().
+		SetSkip(int64(keep)).
+		SetSort(bson.D{{Key: "ran_at", Value: -1}})
+	err := r.snapshotCollection.FindOne(context.Background(), filter, opts).Decode(&oldest)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = r.snapshotCollection.DeleteMany(context.Background(), bson.M{
+		"scheduled_query_id": scheduledQueryID,
+		"ran_at":             bson.M{"$lte": oldest.RanAt},
+	})
+	return err
+}
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/models"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	alertWebhookTimeout  = 10 * time.Second
+	alertWebhookAttempts = 3
+	alertStatusTest      = "test" // Sent by the test endpoint, never stored
+)
+
+// Headers of the webhook requests. The signature is the hex HMAC-SHA256, keyed with the secret of the rule, of the
+// timestamp, a dot and the body, so receivers can reject forged and replayed payloads.
+const (
+	alertWebhookEventHeader     = "X-NeoBase-Event"
+	alertWebhookDeliveryHeader  = "X-NeoBase-Delivery"
+	alertWebhookTimestampHeader = "X-NeoBase-Timestamp"
+	alertWebhookSignatureHeader = "X-NeoBase-Signature"
+)
+
+// errWebhookAddressNotAllowed is returned for the webhooks resolving to an address of the server's own network
+var errWebhookAddressNotAllowed = errors.New("webhook address is not allowed")
+
+// blockedWebhookNetworks are the ranges not covered by the net.IP checks that still reach the server's network:
+// carrier-grade NAT (also used by cloud metadata services), "this network", IETF protocol assignments, benchmarking,
+// reserved, and NAT64 which maps onto IPv4 addresses
+var blockedWebhookNetworks = mustParseCIDRs("100.64.0.0/10", "0.0.0.0/8", "192.0.0.0/24", "198.18.0.0/15", "240.0.0.0/4", "64:ff9b::/96", "64:ff9b:1::/48")
+
+// alertWebhookPayload is the body posted to the webhooks of a rule. Its ID is the ID of the event, receivers can
+// use it to drop the deliveries they already processed.
+type alertWebhookPayload struct {
+	ID             string              `json:"id"`
+	Event          string              `json:"event"` // alert.firing, alert.resolved or alert.test
+	Status         string              `json:"status"`
+	Rule           alertWebhookRule    `json:"rule"`
+	ScheduledQuery alertWebhookQuery   `json:"scheduled_query"`
+	SnapshotID     string              `json:"snapshot_id,omitempty"`
+	Actual         *float64            `json:"actual,omitempty"`
+	Previous       *float64            `json:"previous,omitempty"`
+	Message        string              `json:"message"`
+	Condition      dtos.QueryThreshold `json:"condition"`
+	TriggeredAt    string              `json:"triggered_at"`
+}
+
+type alertWebhookRule struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type alertWebhookQuery struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// newAlertWebhookClient returns the client calling the webhooks. It resolves the host itself and only dials the
+// public addresses it got, so a webhook can't probe the server's network, even through a host name resolving to
+// another address on each lookup.
+func newAlertWebhookClient() *http.Client {
+	dialer := &net.Dialer{Timeout: alertWebhookTimeout}
+	return &http.Client{
+		Timeout: alertWebhookTimeout,
+		Transport: &http.Transport{
+			// A proxy would make the requests on our behalf, to addresses the dialer never sees
+			Proxy: nil,
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				host, port, err := net.SplitHostPort(addr)
+				if err != nil {
+					return nil, err
+				}
+				ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+				if err != nil {
+					return nil, err
+				}
+				for _, ip := range ips {
+					if !isAllowedWebhookIP(ip.IP) {
+						return nil, errWebhookAddressNotAllowed
+					}
+				}
+				var dialErr error
+				for _, ip := range ips {
+					conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.IP.String(), port))
+					if err == nil {
+						return conn, nil
+					}
+					dialErr = err
+				}
+				return nil, dialErr
+			},
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: alertWebhookTimeout,
+		},
+		// A redirect would send the signed payload to a URL the user didn't configure
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// isAllowedWebhookIP reports whether a webhook may be called on an address, only public unicast addresses are
+func isAllowedWebhookIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, network := range blockedWebhookNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// notify sends an alert event to the emails and the webhooks of its rule and returns the deliveries
+func (s *alertService) notify(rule *models.AlertRule, scheduledQuery *models.ScheduledQuery, event *models.AlertEvent, triggeredAt time.Time) []models.AlertDelivery {
+	deliveries := make([]models.AlertDelivery, 0, len(rule.Emails)+len(rule.Webhooks))
+	message := alertMessage(rule, event)
+
+	if len(rule.Emails) > 0 {
+		user, err := s.userRepo.FindByID(rule.UserID.Hex())
+		if err != nil {
+			log.Printf("AlertService -> notify -> Error fetching user: %v", err)
+		}
+		alert := &QueryAlertEmail{
+			Subject:   alertSubject(rule, event),
+			Message:   message,
+			RuleName:  rule.Name,
+			QueryName: scheduledQuery.Name,
+			Condition: formatAlertCondition(rule.Condition),
+			Actual:    formatAlertValue(event.Actual),
+			RanAt:     triggeredAt.UTC().Format(time.RFC1123),
+		}
+		for _, email := range rule.Emails {
+			// The owner is greeted by name, the other recipients by their address
+			username := email
+			if user != nil && strings.EqualFold(user.Email, email) {
+				username = user.Username
+			}
+			delivery := models.AlertDelivery{Channel: "email", Target: email, Attempts: 1}
+			if err := s.emailService.SendQueryAlertEmail(email, username, alert); err != nil {
+				delivery.Error = err.Error()
+			} else {
+				delivery.Success = true
+			}
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	if len(rule.Webhooks) > 0 {
+		payload := alertWebhookPayload{
+			ID:             event.ID.Hex(),
+			Event:          "alert." + event.Status,
+			Status:         event.Status,
+			Rule:           alertWebhookRule{ID: rule.ID.Hex(), Name: rule.Name},
+			ScheduledQuery: alertWebhookQuery{ID: scheduledQuery.ID.Hex(), Name: scheduledQuery.Name},
+			Actual:         event.Actual,
+			Previous:       event.Previous,
+			Message:        message,
+			Condition:      toQueryThresholdDto(rule.Condition),
+			TriggeredAt:    triggeredAt.UTC().Format(time.RFC3339),
+		}
+		if !event.SnapshotID.IsZero() {
+			payload.SnapshotID = event.SnapshotID.Hex()
+		}
+		body, err := json.Marshal(payload)
+		if err != nil {
+			log.Printf("AlertService -> notify -> Error marshaling webhook payload: %v", err)
+			return deliveries
+		}
+		secret := s.webhookSecret(rule)
+		for _, webhook := range rule.Webhooks {
+			deliveries = append(deliveries, s.sendWebhook(webhook, secret, payload.Event, payload.ID, body))
+		}
+	}
+	return deliveries
+}
+
+// sendWebhook posts a signed payload to a webhook, retrying on network errors and server errors
+func (s *alertService) sendWebhook(webhook, secret, eventType, deliveryID string, body []byte) models.AlertDelivery {
+	delivery := models.AlertDelivery{Channel: "webhook", Target: webhook}
+	for attempt := 1; attempt <= alertWebhookAttempts; attempt++ {
+		delivery.Attempts = attempt
+		retry, err := s.postWebhook(webhook, secret, eventType, deliveryID, body, &delivery)
+		if err == nil {
+			delivery.Success = true
+			delivery.Error = ""
+			return delivery
+		}
+		delivery.Error = err.Error()
+		log.Printf("AlertService -> sendWebhook -> Attempt %d to %s failed: %v", attempt, webhook, err)
+		if !retry {
+			break
+		}
+		if attempt < alertWebhookAttempts {
+			time.Sleep(time.Duration(attempt) * 2 * time.Second)
+		}
+	}
+	return delivery
+}
+
+// postWebhook posts the payload once, returning whether a failure is worth retrying
+func (s *alertService) postWebhook(webhook, secret, eventType, deliveryID string, body []byte, delivery *models.AlertDelivery) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %v", err)
+	}
+	// The timestamp is signed with the body, it changes on each attempt
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "NeoBase-Alerts")
+	req.Header.Set(alertWebhookEventHeader, eventType)
+	req.Header.Set(alertWebhookDeliveryHeader, deliveryID)
+	req.Header.Set(alertWebhookTimestampHeader, timestamp)
+	req.Header.Set(alertWebhookSignatureHeader, signAlertWebhook(secret, timestamp, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		if errors.Is(err, errWebhookAddressNotAllowed) {
+			return false, errWebhookAddressNotAllowed
+		}
+		// The network errors tell which hosts and ports of the server's network answer, they stay in the logs
+		log.Printf("AlertService -> postWebhook -> Error calling %s: %v", webhook, err)
+		return true, fmt.Errorf("failed to call webhook")
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	delivery.StatusCode = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+}
+
+// signAlertWebhook returns the signature header of a webhook payload
+func signAlertWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func alertSubject(rule *models.AlertRule, event *models.AlertEvent) string {
+	switch event.Status {
+	case alertStatusFiring:
+		return fmt.Sprintf("[NeoBase] Alert firing: %s", rule.Name)
+	case alertStatusResolved:
+		return fmt.Sprintf("[NeoBase] Alert resolved: %s", rule.Name)
+	}
+	return fmt.Sprintf("[NeoBase] Test alert: %s", rule.Name)
+}
+
+func alertMessage(rule *models.AlertRule, event *models.AlertEvent) string {
+	switch event.Status {
+	case alertStatusFiring:
+		return fmt.Sprintf("The condition %s of the alert rule holds on the latest run of the scheduled query.", formatAlertCondition(rule.Condition))
+	case alertStatusResolved:
+		return fmt.Sprintf("The condition %s of the alert rule no longer holds on the latest run of the scheduled query.", formatAlertCondition(rule.Condition))
+	}
+	return "This is a test notification of the alert rule, its state didn't change."
+}
+
+// formatAlertCondition describes a condition, e.g. "row_count > 0" or "total changed by more than 10%"
+func formatAlertCondition(condition models.QueryThreshold) string {
+	metric := condition.Metric
+	if condition.Metric == thresholdMetricValue {
+		metric = condition.Column
+	}
+	value := strconv.FormatFloat(condition.Value, 'f', -1, 64)
+	switch condition.Operator {
+	case "change":
+		return fmt.Sprintf("%s changed by more than %s", metric, value)
+	case "change_pct":
+		return fmt.Sprintf("%s changed by more than %s%%", metric, value)
+	}
+	operators := map[string]string{"gt": ">", "gte": ">=", "lt": "<", "lte": "<=", "eq": "=", "ne": "!="}
+	return fmt.Sprintf("%s %s %s", metric, operators[condition.Operator], value)
+}
+
+func formatAlertValue(value *float64) string {
+	if value == nil {
+		return "not read yet"
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"neobase-ai/internal/models"
+	"neobase-ai/internal/repositories"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIsAllowedWebhookIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		allowed bool
+	}{
+		{"8.8.8.8", true},
+		{"2001:4860:4860::8888", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fd00:ec2::254", false},
+		{"fe80::1", false},
+		{"100.100.100.200", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+	for _, tt := range tests {
+		if got := isAllowedWebhookIP(net.ParseIP(tt.ip)); got != tt.allowed {
+			t.Errorf("isAllowedWebhookIP(%s) = %v, want %v", tt.ip, got, tt.allowed)
+		}
+	}
+}
+
+func TestNormalizeAlertWebhooks(t *testing.T) {
+	webhooks, err := normalizeAlertWebhooks([]string{" https://hooks.example.com/a ", "https://hooks.example.com/a", "http://203.0.113.7:8080/b"})
+	if err != nil {
+		t.Fatalf("normalizeAlertWebhooks: %v", err)
+	}
+	if strings.Join(webhooks, ",") != "https://hooks.example.com/a,http://203.0.113.7:8080/b" {
+		t.Errorf("unexpected webhooks %v", webhooks)
+	}
+
+	for _, webhook := range []string{"ftp://hooks.example.com", "https://", "not a url", "http://localhost:8080/", "http://127.0.0.1/", "http://[::1]/", "http://169.254.169.254/latest/meta-data"} {
+		if _, err := normalizeAlertWebhooks([]string{webhook}); err == nil {
+			t.Errorf("webhook %q should be rejected", webhook)
+		}
+	}
+}
+
+func TestWebhookClientRefusesLocalAddresses(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	s := &alertService{httpClient: newAlertWebhookClient()}
+	delivery := s.sendWebhook(server.URL, "secret", "alert.test", "1", []byte("{}"))
+	if delivery.Success || delivery.Error != errWebhookAddressNotAllowed.Error() || delivery.StatusCode != 0 {
+		t.Errorf("unexpected delivery %+v", delivery)
+	}
+	// The refused address isn't retried
+	if delivery.Attempts != 1 || atomic.LoadInt32(&calls) != 0 {
+		t.Errorf("the webhook was called %d times in %d attempts", calls, delivery.Attempts)
+	}
+}
+
+func TestSignAlertWebhook(t *testing.T) {
+	// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"
+	if got := signAlertWebhook("secret", "1700000000", []byte("{}")); got != want {
+		t.Errorf("got signature %s, want %s", got, want)
+	}
+}
+
+// fakeAlertRepository records the events and the rule states stored by evaluateRule
+type fakeAlertRepository struct {
+	repositories.AlertRepository
+	events []*models.AlertEvent
+}
+
+func (r *fakeAlertRepository) CreateEvent(event *models.AlertEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *fakeAlertRepository) UpdateRuleState(rule *models.AlertRule) error {
+	return nil
+}
+
+func TestEvaluateRuleNotifiesAfterCooldown(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	repo := &fakeAlertRepository{}
+	// The test server listens on loopback, which the webhook client refuses
+	s := &alertService{alertRepo: repo, httpClient: server.Client()}
+	rule := &models.AlertRule{
+		Name:            "Failed payments",
+		Condition:       models.QueryThreshold{Metric: thresholdMetricRowCount, Operator: "gt", Value: 0},
+		Webhooks:        []string{server.URL},
+		CooldownMinutes: 60,
+		Enabled:         true,
+		Base:            models.NewBase(),
+	}
+	scheduledQuery := &models.ScheduledQuery{Name: "Payments", Base: models.NewBase()}
+	breached := []interface{}{map[string]interface{}{"id": 1}}
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		after       time.Duration
+		rows        []interface{}
+		wantFiring  bool
+		wantPending bool
+		wantCalls   int32
+		wantEvents  int
+	}{
+		{0, breached, true, false, 1, 1},                 // Fires and notifies
+		{5 * time.Minute, nil, false, false, 1, 2},       // Resolves, without a notification
+		{10 * time.Minute, breached, false, true, 1, 3},  // Fires again within the cooldown, muted
+		{20 * time.Minute, breached, false, true, 1, 3},  // Still holds, the muted event isn't recorded again
+		{70 * time.Minute, breached, true, false, 2, 4},  // Notifies once the cooldown is over
+		{80 * time.Minute, breached, true, false, 2, 4},  // Keeps firing without notifying again
+		{90 * time.Minute, nil, false, false, 2, 5},      // Resolves
+		{100 * time.Minute, breached, false, true, 2, 6}, // Within the cooldown of the second notification
+		{105 * time.Minute, nil, false, false, 2, 6},     // Stops holding before it was notified, nothing to resolve
+	}
+	for i, step := range steps {
+		snapshot := &models.ScheduledQuerySnapshot{Status: scheduledRunSucceeded, RanAt: start.Add(step.after), Base: models.NewBase()}
+		s.evaluateRule(rule, scheduledQuery, snapshot, step.rows)
+		if rule.Firing != step.wantFiring || rule.Pending != step.wantPending {
+			t.Errorf("step %d: firing %v, pending %v, want %v, %v", i, rule.Firing, rule.Pending, step.wantFiring, step.wantPending)
+		}
+		if got := atomic.LoadInt32(&calls); got != step.wantCalls || len(repo.events) != step.wantEvents {
+			t.Errorf("step %d: %d webhook calls and %d events, want %d and %d", i, got, len(repo.events), step.wantCalls, step.wantEvents)
+		}
+	}
+
+	if muted := repo.events[2]; muted.Status != alertStatusFiring || muted.Suppressed != alertSuppressedCooldown || muted.Notified {
+		t.Errorf("unexpected muted event %+v", muted)
+	}
+	if notified := repo.events[3]; notified.Status != alertStatusFiring || !notified.Notified {
+		t.Errorf("unexpected event after the cooldown %+v", notified)
+	}
+	if rule.LastFiredAt == nil || !rule.LastFiredAt.Equal(start.Add(70*time.Minute)) {
+		t.Errorf("LastFiredAt is %v, want the last notified firing", rule.LastFiredAt)
+	}
+}
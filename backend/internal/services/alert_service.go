@@ -0,0 +1,580 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/models"
+	"neobase-ai/internal/repositories"
+	"neobase-ai/internal/utils"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultAlertCooldownMinutes = 60
+	maxAlertCooldownMinutes     = 7 * 24 * 60
+)
+
+// Statuses of the alert events, and why an event wasn't notified
+const (
+	alertStatusFiring          = "firing"
+	alertStatusResolved        = "resolved"
+	alertSuppressedCooldown    = "cooldown"
+	alertSuppressedResolveOff  = "notify_on_resolve_disabled"
+	alertSuppressedNoRecipient = "no_recipients"
+)
+
+type AlertService interface {
+	CreateRule(userID string, req *dtos.CreateAlertRuleRequest) (*dtos.AlertRuleResponse, uint32, error)
+	UpdateRule(userID, ruleID string, req *dtos.UpdateAlertRuleRequest) (*dtos.AlertRuleResponse, uint32, error)
+	DeleteRule(userID, ruleID string) (uint32, error)
+	GetRule(userID, ruleID string) (*dtos.AlertRuleResponse, uint32, error)
+	ListRules(userID, scheduledQueryID string, page, pageSize int) (*dtos.AlertRuleListResponse, uint32, error)
+	ListEvents(userID, ruleID, scheduledQueryID, status string, page, pageSize int) (*dtos.AlertEventListResponse, uint32, error)
+	TestRule(userID, ruleID string) (*dtos.TestAlertRuleResponse, uint32, error)
+	EvaluateSnapshot(scheduledQuery *models.ScheduledQuery, snapshot *models.ScheduledQuerySnapshot, rows []interface{})
+	DeleteScheduledQueryRules(scheduledQueryID primitive.ObjectID) error
+}
+
+type alertService struct {
+	alertRepo          repositories.AlertRepository
+	scheduledQueryRepo repositories.ScheduledQueryRepository
+	userRepo           repositories.UserRepository
+	emailService       EmailService
+	crypto             *utils.AESGCMCrypto
+	httpClient         *http.Client
+}
+
+func NewAlertService(alertRepo repositories.AlertRepository, scheduledQueryRepo repositories.ScheduledQueryRepository, userRepo repositories.UserRepository, emailService EmailService) AlertService {
+	// Initialize crypto instance
+	crypto, err := utils.NewFromConfig()
+	if err != nil {
+		log.Printf("AlertService -> NewAlertService -> Failed to initialize crypto: %v", err)
+	}
+
+	return &alertService{
+		alertRepo:          alertRepo,
+		scheduledQueryRepo: scheduledQueryRepo,
+		userRepo:           userRepo,
+		emailService:       emailService,
+		crypto:             crypto,
+		httpClient:         newAlertWebhookClient(),
+	}
+}
+
+// CreateRule adds an alert rule on a scheduled query of the user. The webhook secret is only returned here and when
+// rotated.
+func (s *alertService) CreateRule(userID string, req *dtos.CreateAlertRuleRequest) (*dtos.AlertRuleResponse, uint32, error) {
+	scheduledQuery, status, err := findUserScheduledQuery(s.scheduledQueryRepo, userID, req.ScheduledQueryID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("name is required")
+	}
+	condition, err := validateThreshold(req.Condition)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid condition: %v", err)
+	}
+	cooldownMinutes := defaultAlertCooldownMinutes
+	if req.CooldownMinutes != nil {
+		cooldownMinutes = *req.CooldownMinutes
+	}
+	webhooks, err := normalizeAlertWebhooks(req.Webhooks)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	emails := normalizeAlertEmails(req.Emails)
+	if len(emails) == 0 && len(webhooks) == 0 {
+		// Without recipients, the alerts go to the user
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil || user == nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to fetch user: %v", err)
+		}
+		emails = normalizeAlertEmails([]string{user.Email})
+	}
+
+	rule := models.NewAlertRule(scheduledQuery.UserID, scheduledQuery.ID, name, condition, emails, webhooks, cooldownMinutes, req.NotifyOnResolve)
+	if err := validateAlertRule(rule); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	secret, err := s.rotateWebhookSecret(rule)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err := s.alertRepo.CreateRule(rule); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to create alert rule: %v", err)
+	}
+	return toAlertRuleResponse(rule, &secret), http.StatusCreated, nil
+}
+
+func (s *alertService) UpdateRule(userID, ruleID string, req *dtos.UpdateAlertRuleRequest) (*dtos.AlertRuleResponse, uint32, error) {
+	rule, status, err := s.findUserAlertRule(userID, ruleID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	// A rule whose condition changed, or enabled again, starts over without firing
+	resetState := false
+	if req.Name != nil {
+		rule.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Condition != nil {
+		if rule.Condition, err = validateThreshold(*req.Condition); err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("invalid condition: %v", err)
+		}
+		resetState = true
+	}
+	if req.Emails != nil {
+		rule.Emails = normalizeAlertEmails(*req.Emails)
+	}
+	if req.Webhooks != nil {
+		if rule.Webhooks, err = normalizeAlertWebhooks(*req.Webhooks); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+	}
+	if req.CooldownMinutes != nil {
+		rule.CooldownMinutes = *req.CooldownMinutes
+	}
+	if req.NotifyOnResolve != nil {
+		rule.NotifyOnResolve = *req.NotifyOnResolve
+	}
+	if req.Enabled != nil {
+		resetState = resetState || (*req.Enabled && !rule.Enabled)
+		rule.Enabled = *req.Enabled
+	}
+	if err := validateAlertRule(rule); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if resetState {
+		rule.Firing = false
+		rule.Pending = false
+		rule.LastValue = nil
+	}
+
+	var secret *string
+	if req.RotateSecret {
+		rotated, err := s.rotateWebhookSecret(rule)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		secret = &rotated
+	}
+
+	if err := s.alertRepo.UpdateRule(rule.ID, rule); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to update alert rule: %v", err)
+	}
+	return toAlertRuleResponse(rule, secret), http.StatusOK, nil
+}
+
+func (s *alertService) DeleteRule(userID, ruleID string) (uint32, error) {
+	rule, status, err := s.findUserAlertRule(userID, ruleID)
+	if err != nil {
+		return status, err
+	}
+	if err := s.alertRepo.DeleteRule(rule.ID); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to delete alert rule: %v", err)
+	}
+	return http.StatusOK, nil
+}
+
+func (s *alertService) GetRule(userID, ruleID string) (*dtos.AlertRuleResponse, uint32, error) {
+	rule, status, err := s.findUserAlertRule(userID, ruleID)
+	if err != nil {
+		return nil, status, err
+	}
+	return toAlertRuleResponse(rule, nil), http.StatusOK, nil
+}
+
+// ListRules returns the alert rules of the user, only those of a scheduled query when its ID is given
+func (s *alertService) ListRules(userID, scheduledQueryID string, page, pageSize int) (*dtos.AlertRuleListResponse, uint32, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid user ID format")
+	}
+	scheduledQueryObjID, err := optionalObjectID(scheduledQueryID, "scheduled query")
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	rules, total, err := s.alertRepo.FindRulesByUserID(userObjID, scheduledQueryObjID, page, pageSize)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to fetch alert rules: %v", err)
+	}
+
+	response := &dtos.AlertRuleListResponse{
+		Rules: make([]dtos.AlertRuleResponse, len(rules)),
+		Total: total,
+	}
+	for i, rule := range rules {
+		response.Rules[i] = *toAlertRuleResponse(rule, nil)
+	}
+	return response, http.StatusOK, nil
+}
+
+// ListEvents returns the alert history of the user, the latest first, filtered by rule, scheduled query and status
+// when given
+func (s *alertService) ListEvents(userID, ruleID, scheduledQueryID, status string, page, pageSize int) (*dtos.AlertEventListResponse, uint32, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid user ID format")
+	}
+	ruleObjID, err := optionalObjectID(ruleID, "alert rule")
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	scheduledQueryObjID, err := optionalObjectID(scheduledQueryID, "scheduled query")
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if status != "" && status != alertStatusFiring && status != alertStatusResolved {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid status %q, must be firing or resolved", status)
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	events, total, err := s.alertRepo.FindEvents(userObjID, ruleObjID, scheduledQueryObjID, status, page, pageSize)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to fetch alert history: %v", err)
+	}
+
+	response := &dtos.AlertEventListResponse{
+		Events: make([]dtos.AlertEventResponse, len(events)),
+		Total:  total,
+	}
+	for i, event := range events {
+		response.Events[i] = toAlertEventResponse(event)
+	}
+	return response, http.StatusOK, nil
+}
+
+// TestRule sends a test notification to the recipients of a rule, without changing its state or its history
+func (s *alertService) TestRule(userID, ruleID string) (*dtos.TestAlertRuleResponse, uint32, error) {
+	rule, status, err := s.findUserAlertRule(userID, ruleID)
+	if err != nil {
+		return nil, status, err
+	}
+	scheduledQuery, status, err := findUserScheduledQuery(s.scheduledQueryRepo, userID, rule.ScheduledQueryID.Hex())
+	if err != nil {
+		return nil, status, err
+	}
+
+	event := newAlertEvent(rule, scheduledQuery, primitive.NilObjectID, alertStatusTest, rule.LastValue, nil)
+	deliveries := s.notify(rule, scheduledQuery, event, time.Now())
+
+	response := &dtos.TestAlertRuleResponse{Deliveries: make([]dtos.AlertDelivery, len(deliveries))}
+	for i, delivery := range deliveries {
+		response.Deliveries[i] = toAlertDeliveryDto(delivery)
+	}
+	return response, http.StatusOK, nil
+}
+
+// EvaluateSnapshot evaluates the enabled rules of a scheduled query on the rows of a snapshot. A rule records an
+// event and notifies when it starts firing and when it resolves, not on every run while it keeps firing.
+func (s *alertService) EvaluateSnapshot(scheduledQuery *models.ScheduledQuery, snapshot *models.ScheduledQuerySnapshot, rows []interface{}) {
+	// A failed run says nothing about the condition, the rules keep their state
+	if snapshot.Status != scheduledRunSucceeded {
+		return
+	}
+	rules, err := s.alertRepo.FindEnabledRulesByScheduledQueryID(scheduledQuery.ID)
+	if err != nil {
+		log.Printf("AlertService -> EvaluateSnapshot -> Error fetching alert rules: %v", err)
+		return
+	}
+	for _, rule := range rules {
+		s.evaluateRule(rule, scheduledQuery, snapshot, rows)
+	}
+}
+
+func (s *alertService) evaluateRule(rule *models.AlertRule, scheduledQuery *models.ScheduledQuery, snapshot *models.ScheduledQuerySnapshot, rows []interface{}) {
+	ranAt := snapshot.RanAt
+	rule.LastEvaluatedAt = &ranAt
+
+	actual, err := thresholdMetric(rule.Condition, rows)
+	if err != nil {
+		log.Printf("AlertService -> evaluateRule -> Alert rule %s couldn't be evaluated: %v", rule.ID.Hex(), err)
+		if err := s.alertRepo.UpdateRuleState(rule); err != nil {
+			log.Printf("AlertService -> evaluateRule -> Error updating alert rule state: %v", err)
+		}
+		return
+	}
+	previous := rule.LastValue
+	breached := isThresholdBreached(rule.Condition, actual, previous)
+	rule.LastValue = &actual
+
+	var event *models.AlertEvent
+	switch {
+	case breached && !rule.Firing:
+		// A rule muted by the cooldown stays pending and notifies on the first run after the cooldown, the muted
+		// event is only recorded once
+		cooldown := time.Duration(rule.CooldownMinutes) * time.Minute
+		inCooldown := rule.LastNotifiedAt != nil && ranAt.Sub(*rule.LastNotifiedAt) < cooldown
+		if inCooldown && rule.Pending {
+			break
+		}
+		rule.Pending = true
+		event = newAlertEvent(rule, scheduledQuery, snapshot.ID, alertStatusFiring, &actual, previous)
+		if inCooldown {
+			event.Suppressed = alertSuppressedCooldown
+		}
+	case !breached && rule.Pending:
+		// Nothing was notified, there is nothing to resolve
+		rule.Pending = false
+	case !breached && rule.Firing:
+		rule.Firing = false
+		event = newAlertEvent(rule, scheduledQuery, snapshot.ID, alertStatusResolved, &actual, previous)
+		if !rule.NotifyOnResolve {
+			event.Suppressed = alertSuppressedResolveOff
+		}
+	}
+
+	if event != nil {
+		if event.Suppressed == "" {
+			event.Deliveries = s.notify(rule, scheduledQuery, event, ranAt)
+			for _, delivery := range event.Deliveries {
+				event.Notified = event.Notified || delivery.Success
+			}
+			if len(event.Deliveries) == 0 {
+				event.Suppressed = alertSuppressedNoRecipient
+			}
+			// The rule only fires once the owner was told, a failed notification is sent again on the next run. The
+			// cooldown starts from the last firing notification.
+			if event.Status == alertStatusFiring && (event.Notified || event.Suppressed == alertSuppressedNoRecipient) {
+				rule.Firing = true
+				rule.Pending = false
+				rule.LastFiredAt = &ranAt
+			}
+			if event.Notified && event.Status == alertStatusFiring {
+				rule.LastNotifiedAt = &ranAt
+			}
+		}
+		if err := s.alertRepo.CreateEvent(event); err != nil {
+			log.Printf("AlertService -> evaluateRule -> Error storing alert event: %v", err)
+		}
+		log.Printf("AlertService -> evaluateRule -> Alert rule %s is %s, notified: %v", rule.ID.Hex(), event.Status, event.Notified)
+	}
+
+	if err := s.alertRepo.UpdateRuleState(rule); err != nil {
+		log.Printf("AlertService -> evaluateRule -> Error updating alert rule state: %v", err)
+	}
+}
+
+// DeleteScheduledQueryRules deletes the rules of a deleted scheduled query and their history
+func (s *alertService) DeleteScheduledQueryRules(scheduledQueryID primitive.ObjectID) error {
+	return s.alertRepo.DeleteRulesByScheduledQueryID(scheduledQueryID)
+}
+
+// findUserAlertRule returns an alert rule of the user
+func (s *alertService) findUserAlertRule(userID, ruleID string) (*models.AlertRule, uint32, error) {
+	ruleObjID, err := primitive.ObjectIDFromHex(ruleID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid alert rule ID format")
+	}
+	rule, err := s.alertRepo.FindRuleByID(ruleObjID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to fetch alert rule: %v", err)
+	}
+	if rule == nil || rule.UserID.Hex() != userID {
+		return nil, http.StatusNotFound, fmt.Errorf("alert rule not found")
+	}
+	return rule, http.StatusOK, nil
+}
+
+// rotateWebhookSecret generates a new webhook secret for the rule, stores it encrypted and returns it in clear
+func (s *alertService) rotateWebhookSecret(rule *models.AlertRule) (string, error) {
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %v", err)
+	}
+	secret := hex.EncodeToString(secretBytes)
+	rule.WebhookSecret = secret
+	if s.crypto != nil {
+		encrypted, err := s.crypto.EncryptField(secret)
+		if err != nil {
+			return "", fmt.Errorf("failed to encrypt webhook secret: %v", err)
+		}
+		rule.WebhookSecret = encrypted
+	}
+	return secret, nil
+}
+
+// webhookSecret returns the webhook secret of the rule in clear
+func (s *alertService) webhookSecret(rule *models.AlertRule) string {
+	if s.crypto == nil || rule.WebhookSecret == "" {
+		return rule.WebhookSecret
+	}
+	decrypted, err := s.crypto.DecryptField(rule.WebhookSecret)
+	if err != nil {
+		log.Printf("AlertService -> webhookSecret -> Failed to decrypt: %v", err)
+		return rule.WebhookSecret
+	}
+	return decrypted
+}
+
+func validateAlertRule(rule *models.AlertRule) error {
+	if rule.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	// The name is in the subject of the emails
+	if strings.ContainsAny(rule.Name, "\r\n") {
+		return fmt.Errorf("name can't contain line breaks")
+	}
+	if rule.CooldownMinutes < 0 || rule.CooldownMinutes > maxAlertCooldownMinutes {
+		return fmt.Errorf("cooldown_minutes must be between 0 and %d", maxAlertCooldownMinutes)
+	}
+	if len(rule.Emails) == 0 && len(rule.Webhooks) == 0 {
+		return fmt.Errorf("an alert rule needs at least one email or webhook")
+	}
+	return nil
+}
+
+// normalizeAlertEmails trims the email addresses and drops the duplicates
+func normalizeAlertEmails(emails []string) []string {
+	normalized := make([]string, 0, len(emails))
+	seen := make(map[string]bool)
+	for _, email := range emails {
+		email = strings.TrimSpace(email)
+		if email == "" || seen[strings.ToLower(email)] {
+			continue
+		}
+		seen[strings.ToLower(email)] = true
+		normalized = append(normalized, email)
+	}
+	return normalized
+}
+
+// normalizeAlertWebhooks checks the webhook URLs and drops the duplicates, only http and https URLs of public hosts
+// are called
+func normalizeAlertWebhooks(webhooks []string) ([]string, error) {
+	normalized := make([]string, 0, len(webhooks))
+	seen := make(map[string]bool)
+	for _, webhook := range webhooks {
+		webhook = strings.TrimSpace(webhook)
+		parsed, err := url.Parse(webhook)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL %q, must be an http or https URL", webhook)
+		}
+		// The host names are checked when the webhook is called, on the addresses they resolve to
+		host := parsed.Hostname()
+		if ip := net.ParseIP(host); strings.EqualFold(host, "localhost") || (ip != nil && !isAllowedWebhookIP(ip)) {
+			return nil, fmt.Errorf("invalid webhook URL %q, private and local addresses are not allowed", webhook)
+		}
+		if seen[webhook] {
+			continue
+		}
+		seen[webhook] = true
+		normalized = append(normalized, webhook)
+	}
+	return normalized, nil
+}
+
+// optionalObjectID parses the ID of a filter, nil when it is empty
+func optionalObjectID(id, name string) (*primitive.ObjectID, error) {
+	if id == "" {
+		return nil, nil
+	}
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s ID format", name)
+	}
+	return &objID, nil
+}
+
+func newAlertEvent(rule *models.AlertRule, scheduledQuery *models.ScheduledQuery, snapshotID primitive.ObjectID, status string, actual, previous *float64) *models.AlertEvent {
+	return &models.AlertEvent{
+		RuleID:           rule.ID,
+		UserID:           rule.UserID,
+		ScheduledQueryID: scheduledQuery.ID,
+		SnapshotID:       snapshotID,
+		RuleName:         rule.Name,
+		Status:           status,
+		Condition:        rule.Condition,
+		Actual:           actual,
+		Previous:         previous,
+		Deliveries:       []models.AlertDelivery{},
+		Base:             models.NewBase(),
+	}
+}
+
+func toAlertRuleResponse(rule *models.AlertRule, secret *string) *dtos.AlertRuleResponse {
+	response := &dtos.AlertRuleResponse{
+		ID:               rule.ID.Hex(),
+		ScheduledQueryID: rule.ScheduledQueryID.Hex(),
+		Name:             rule.Name,
+		Condition:        toQueryThresholdDto(rule.Condition),
+		Emails:           rule.Emails,
+		Webhooks:         rule.Webhooks,
+		WebhookSecret:    secret,
+		CooldownMinutes:  rule.CooldownMinutes,
+		NotifyOnResolve:  rule.NotifyOnResolve,
+		Enabled:          rule.Enabled,
+		Firing:           rule.Firing,
+		LastValue:        rule.LastValue,
+		CreatedAt:        rule.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        rule.UpdatedAt.Format(time.RFC3339),
+	}
+	if rule.LastEvaluatedAt != nil {
+		response.LastEvaluatedAt = utils.ToStringPtr(rule.LastEvaluatedAt.Format(time.RFC3339))
+	}
+	if rule.LastFiredAt != nil {
+		response.LastFiredAt = utils.ToStringPtr(rule.LastFiredAt.Format(time.RFC3339))
+	}
+	if rule.LastNotifiedAt != nil {
+		response.LastNotifiedAt = utils.ToStringPtr(rule.LastNotifiedAt.Format(time.RFC3339))
+	}
+	return response
+}
+
+func toAlertEventResponse(event *models.AlertEvent) dtos.AlertEventResponse {
+	response := dtos.AlertEventResponse{
+		ID:               event.ID.Hex(),
+		RuleID:           event.RuleID.Hex(),
+		ScheduledQueryID: event.ScheduledQueryID.Hex(),
+		SnapshotID:       event.SnapshotID.Hex(),
+		RuleName:         event.RuleName,
+		Status:           event.Status,
+		Condition:        toQueryThresholdDto(event.Condition),
+		Actual:           event.Actual,
+		Previous:         event.Previous,
+		Notified:         event.Notified,
+		Suppressed:       event.Suppressed,
+		Deliveries:       make([]dtos.AlertDelivery, len(event.Deliveries)),
+		CreatedAt:        event.CreatedAt.Format(time.RFC3339),
+	}
+	for i, delivery := range event.Deliveries {
+		response.Deliveries[i] = toAlertDeliveryDto(delivery)
+	}
+	return response
+}
+
+func toAlertDeliveryDto(delivery models.AlertDelivery) dtos.AlertDelivery {
+	return dtos.AlertDelivery{
+		Channel:    delivery.Channel,
+		Target:     delivery.Target,
+		Success:    delivery.Success,
+		StatusCode: delivery.StatusCode,
+		Attempts:   delivery.Attempts,
+		Error:      delivery.Error,
+	}
+}
@@ -0,0 +1,531 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"neobase-ai/config"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/constants"
+	"neobase-ai/internal/models"
+	"neobase-ai/pkg/dbmanager"
+	"neobase-ai/pkg/llm"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Tools the LLM can call in agent mode
+const (
+	agentToolListTables     = "list_tables"
+	agentToolDescribeTable  = "describe_table"
+	agentToolSampleRows     = "sample_rows"
+	agentToolRunQuery       = "run_query"
+	agentToolDistinctValues = "distinct_values"
+)
+
+var (
+	agentTableParameter = llm.ToolParameter{Name: "table", Type: "string", Description: "Name of the table or collection", Required: true}
+	agentLimitParameter = llm.ToolParameter{Name: "limit", Type: "integer", Description: "Maximum number of rows to return"}
+
+	// agentSchemaTools only read the stored schema
+	agentSchemaTools = []llm.AgentTool{
+		{
+			Name:        agentToolListTables,
+			Description: "Lists the tables/collections of the database with their number of rows.",
+		},
+		{
+			Name:        agentToolDescribeTable,
+			Description: "Returns the columns, primary key and relationships of a table/collection.",
+			Parameters:  []llm.ToolParameter{agentTableParameter},
+		},
+	}
+
+	// agentDataTools read the data, they are only offered to chats sharing data with AI
+	agentDataTools = []llm.AgentTool{
+		{
+			Name:        agentToolSampleRows,
+			Description: "Returns a few rows/documents of a table/collection.",
+			Parameters:  []llm.ToolParameter{agentTableParameter, agentLimitParameter},
+		},
+		{
+			Name:        agentToolRunQuery,
+			Description: "Runs a read-only query and returns its first rows. Writes and multiple statements are rejected.",
+			Parameters: []llm.ToolParameter{
+				{Name: "query", Type: "string", Description: "The read-only query, in the syntax of the database", Required: true},
+			},
+		},
+		{
+			Name:        agentToolDistinctValues,
+			Description: "Returns the most frequent distinct values of a column/field with their number of rows.",
+			Parameters: []llm.ToolParameter{
+				agentTableParameter,
+				{Name: "column", Type: "string", Description: "Name of the column or field", Required: true},
+				agentLimitParameter,
+			},
+		},
+	}
+)
+
+// runAgentExploration lets the LLM explore the database with tools before it answers, streaming each call as a
+// response step. The exploration stops early on LLM errors, the answer then uses the steps done so far.
+func (s *chatService) runAgentExploration(ctx context.Context, chat *models.Chat, userID, streamID string, sendSteps bool, client llm.Client, messages []*models.LLMMessage, dbType string) []llm.AgentStep {
+	toolClient, ok := client.(llm.ToolCallingClient)
+	if !ok || config.Env.AgentMaxSteps <= 0 {
+		return nil
+	}
+	chatID := chat.ID.Hex()
+
+	tools := agentSchemaTools
+	if chat.Settings.ShareDataWithAI {
+		tools = append(append([]llm.AgentTool{}, agentSchemaTools...), agentDataTools...)
+	}
+	systemPrompt := constants.GetAgentPrompt(dbType, config.Env.AgentMaxSteps)
+
+	steps := make([]llm.AgentStep, 0, config.Env.AgentMaxSteps)
+	for len(steps) < config.Env.AgentMaxSteps {
+		step, err := toolClient.NextToolCalls(ctx, systemPrompt, messages, tools, steps)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("ChatService -> runAgentExploration -> Error getting tool calls, answering with %d steps: %v", len(steps), err)
+			}
+			break
+		}
+		if len(step.Calls) == 0 {
+			// The model is ready to answer, its findings are kept for the response
+			if step.Thought != "" {
+				steps = append(steps, *step)
+			}
+			break
+		}
+
+		for _, call := range step.Calls {
+			if ctx.Err() != nil {
+				return steps
+			}
+			if sendSteps {
+				s.sendStreamEvent(userID, chatID, streamID, dtos.StreamResponse{
+					Event: "ai-response-step",
+					Data:  describeAgentToolCall(call),
+				})
+			}
+
+			result := llm.ToolResult{CallID: call.ID, Name: call.Name}
+			content, err := s.runAgentTool(ctx, chat, dbType, tools, call)
+			if err != nil {
+				log.Printf("ChatService -> runAgentExploration -> %s failed: %v", call.Name, err)
+				content, result.IsError = "Error: "+err.Error(), true
+			}
+			result.Content = truncateAgentText(content, constants.AgentToolResultMaxChars)
+			step.Results = append(step.Results, result)
+		}
+		steps = append(steps, *step)
+	}
+	log.Printf("ChatService -> runAgentExploration -> Explored the database in %d steps for chatID %s", len(steps), chatID)
+	return steps
+}
+
+// withAgentFindings returns the messages with the exploration transcript appended to the last user message.
+// The stored message is left untouched.
+func withAgentFindings(messages []*models.LLMMessage, steps []llm.AgentStep) []*models.LLMMessage {
+	if len(steps) == 0 {
+		return messages
+	}
+
+	var transcript strings.Builder
+	for _, step := range steps {
+		if step.Thought != "" {
+			transcript.WriteString(step.Thought + "\n")
+		}
+		for i, call := range step.Calls {
+			transcript.WriteString(fmt.Sprintf("- %s(%s)", call.Name, formatAgentArguments(call.Arguments)))
+			if i < len(step.Results) {
+				transcript.WriteString(" returned:\n" + step.Results[i].Content)
+			}
+			transcript.WriteString("\n")
+		}
+	}
+
+	return appendToUserMessage(messages, fmt.Sprintf(constants.AgentFindingsPrompt,
+		truncateAgentText(transcript.String(), constants.AgentFindingsMaxChars)))
+}
+
+// appendToUserMessage returns a copy of the messages with the text appended to the last user message,
+// the stored messages are left unchanged
+func appendToUserMessage(messages []*models.LLMMessage, text string) []*models.LLMMessage {
+	result := make([]*models.LLMMessage, len(messages))
+	copy(result, messages)
+	for i := len(result) - 1; i >= 0; i-- {
+		userMsg, ok := result[i].Content["user_message"].(string)
+		if result[i].Role != string(constants.MessageTypeUser) || !ok {
+			continue
+		}
+		content := make(map[string]interface{}, len(result[i].Content))
+		for key, value := range result[i].Content {
+			content[key] = value
+		}
+		content["user_message"] = userMsg + text
+		message := *result[i]
+		message.Content = content
+		result[i] = &message
+		break
+	}
+	return result
+}
+
+// describeAgentToolCall returns the response step shown to the user for a tool call
+func describeAgentToolCall(call llm.ToolCall) string {
+	table := agentStringArgument(call.Arguments, "table")
+	switch call.Name {
+	case agentToolListTables:
+		return "Listing the tables of the database.."
+	case agentToolDescribeTable:
+		return fmt.Sprintf("Looking at the structure of %s..", table)
+	case agentToolSampleRows:
+		return fmt.Sprintf("Reading sample rows of %s..", table)
+	case agentToolRunQuery:
+		return "Running a read-only query to check the data.."
+	case agentToolDistinctValues:
+		return fmt.Sprintf("Checking the values of %s.%s..", table, agentStringArgument(call.Arguments, "column"))
+	default:
+		return fmt.Sprintf("Calling %s..", call.Name)
+	}
+}
+
+// runAgentTool runs a tool call and returns the result sent back to the LLM
+func (s *chatService) runAgentTool(ctx context.Context, chat *models.Chat, dbType string, tools []llm.AgentTool, call llm.ToolCall) (string, error) {
+	offered := false
+	for _, tool := range tools {
+		offered = offered || tool.Name == call.Name
+	}
+	if !offered {
+		return "", fmt.Errorf("unknown tool %s", call.Name)
+	}
+
+	chatID := chat.ID.Hex()
+	if call.Name == agentToolRunQuery {
+		query := strings.TrimSpace(agentStringArgument(call.Arguments, "query"))
+		if query == "" {
+			return "", fmt.Errorf("query is required")
+		}
+		limit := agentRowLimit(0)
+		query, err := limitAgentQuery(dbType, query, limit)
+		if err != nil {
+			return "", err
+		}
+		return s.runAgentQuery(ctx, chatID, query, limit)
+	}
+
+	schema, err := s.getAgentSchema(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+	if call.Name == agentToolListTables {
+		names := make([]string, 0, len(schema.Tables))
+		for name := range schema.Tables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var result strings.Builder
+		for _, name := range names {
+			result.WriteString(fmt.Sprintf("%s (%d rows)\n", name, schema.Tables[name].RowCount))
+		}
+		return result.String(), nil
+	}
+
+	table, ok := findAgentTable(schema, agentStringArgument(call.Arguments, "table"))
+	if !ok {
+		return "", fmt.Errorf("table %q doesn't exist, call %s to get the tables", agentStringArgument(call.Arguments, "table"), agentToolListTables)
+	}
+
+	switch call.Name {
+	case agentToolDescribeTable:
+		tableInfo := schema.Tables[table]
+		tableInfo.ExampleRecords = nil
+		relationships := make([]dbmanager.SchemaRelationship, 0)
+		for _, relationship := range schema.Relationships {
+			if relationship.FromTable == table || relationship.ToTable == table {
+				relationships = append(relationships, relationship)
+			}
+		}
+		description, err := json.Marshal(map[string]interface{}{
+			"table":         tableInfo,
+			"relationships": relationships,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to describe table: %v", err)
+		}
+		return string(description), nil
+
+	case agentToolSampleRows:
+		limit := agentRowLimit(agentIntArgument(call.Arguments, "limit"))
+		records, err := s.dbManager.FetchExampleRecords(ctx, chatID, table, limit)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch rows: %v", err)
+		}
+		return formatAgentRows(records, limit), nil
+
+	case agentToolDistinctValues:
+		column := agentStringArgument(call.Arguments, "column")
+		columnExists := false
+		for _, col := range schema.Tables[table].Columns {
+			if col.Name == column {
+				columnExists = true
+				break
+			}
+		}
+		if !columnExists {
+			return "", fmt.Errorf("column %q doesn't exist in %s, call %s to get its columns", column, table, agentToolDescribeTable)
+		}
+		limit := agentRowLimit(agentIntArgument(call.Arguments, "limit"))
+		query, err := distinctValuesQuery(dbType, table, column, limit)
+		if err != nil {
+			return "", err
+		}
+		return s.runAgentQuery(ctx, chatID, query, limit)
+	}
+	return "", fmt.Errorf("unknown tool %s", call.Name)
+}
+
+// getAgentSchema returns the schema of the chat's selected tables
+func (s *chatService) getAgentSchema(ctx context.Context, chat *models.Chat) (*dbmanager.LLMSchemaInfo, error) {
+	var selectedCollections []string
+	if chat.SelectedCollections != "ALL" && chat.SelectedCollections != "" {
+		selectedCollections = strings.Split(chat.SelectedCollections, ",")
+	}
+
+	storage, err := s.dbManager.GetSchemaWithExamples(ctx, chat.ID.Hex(), selectedCollections)
+	if err != nil {
+		return nil, err
+	}
+	if storage.LLMSchema == nil {
+		return nil, fmt.Errorf("the schema isn't available yet")
+	}
+	if len(selectedCollections) == 0 {
+		return storage.LLMSchema, nil
+	}
+
+	selected := make(map[string]bool, len(selectedCollections))
+	for _, name := range selectedCollections {
+		selected[strings.TrimSpace(name)] = true
+	}
+	schema := &dbmanager.LLMSchemaInfo{Tables: make(map[string]dbmanager.LLMTableInfo)}
+	for name, table := range storage.LLMSchema.Tables {
+		if selected[name] {
+			schema.Tables[name] = table
+		}
+	}
+	for _, relationship := range storage.LLMSchema.Relationships {
+		if selected[relationship.FromTable] && selected[relationship.ToTable] {
+			schema.Relationships = append(schema.Relationships, relationship)
+		}
+	}
+	return schema, nil
+}
+
+// runAgentQuery runs a read-only query and formats its first rows
+func (s *chatService) runAgentQuery(ctx context.Context, chatID string, query string, limit int) (string, error) {
+	log.Printf("ChatService -> runAgentQuery -> chatID: %s, query: %s", chatID, query)
+	result, err := s.dbManager.ExecuteReadOnlyQuery(ctx, chatID, query)
+	if err != nil {
+		return "", fmt.Errorf("query failed: %v", err)
+	}
+
+	// Results are usually wrapped in a "results" field
+	encoded, err := json.Marshal(result.Result)
+	if err != nil {
+		return "", fmt.Errorf("failed to read query result: %v", err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return "", fmt.Errorf("failed to read query result: %v", err)
+	}
+	if wrapped, ok := decoded.(map[string]interface{}); ok {
+		if rows, ok := wrapped["results"]; ok {
+			decoded = rows
+		}
+	}
+
+	rows, ok := decoded.([]interface{})
+	if !ok {
+		return string(encoded), nil
+	}
+	records := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if record, ok := row.(map[string]interface{}); ok {
+			records = append(records, record)
+		} else {
+			records = append(records, map[string]interface{}{"value": row})
+		}
+	}
+	return formatAgentRows(records, limit), nil
+}
+
+func formatAgentRows(records []map[string]interface{}, limit int) string {
+	note := ""
+	if len(records) >= limit {
+		records = records[:limit]
+		note = fmt.Sprintf(" (capped at %d rows)", limit)
+	}
+	encoded, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Sprintf("%d rows%s", len(records), note)
+	}
+	return fmt.Sprintf("%d rows%s:\n%s", len(records), note, string(encoded))
+}
+
+// agentRowLimit caps the rows requested by the LLM, 0 means the default
+func agentRowLimit(requested int) int {
+	maxRows := config.Env.AgentQueryRowLimit
+	if maxRows <= 0 {
+		maxRows = constants.AgentSampleRowsDefault
+	}
+	if requested <= 0 {
+		return maxRows
+	}
+	return min(requested, maxRows)
+}
+
+// findAgentTable returns the stored name of a table, ignoring the case the LLM may have changed
+func findAgentTable(schema *dbmanager.LLMSchemaInfo, name string) (string, bool) {
+	if _, ok := schema.Tables[name]; ok {
+		return name, true
+	}
+	for tableName := range schema.Tables {
+		if strings.EqualFold(tableName, name) {
+			return tableName, true
+		}
+	}
+	return "", false
+}
+
+func agentStringArgument(args map[string]interface{}, name string) string {
+	value, _ := args[name].(string)
+	return value
+}
+
+// agentIntArgument reads an integer argument, models may send numbers as strings
+func agentIntArgument(args map[string]interface{}, name string) int {
+	switch value := args[name].(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	case int32:
+		return int(value)
+	case int64:
+		return int(value)
+	case string:
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return 0
+}
+
+func formatAgentArguments(args map[string]interface{}) string {
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", key, args[key]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func truncateAgentText(text string, maxChars int) string {
+	if len(text) <= maxChars {
+		return text
+	}
+	// Don't cut a multi-byte character
+	cut := maxChars
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + "\n... (truncated)"
+}
+
+var (
+	sqlCommentRegex       = regexp.MustCompile(`(?s)--[^\n]*|/\*.*?\*/`)
+	sqlStringLiteralRegex = regexp.MustCompile(`'(?:[^'\\]|''|\\.)*'`)
+	sqlWordRegex          = regexp.MustCompile(`[A-Za-z_]+`)
+	limitKeywordRegex     = regexp.MustCompile(`(?i)\blimit\b`)
+)
+
+// limitAgentQuery checks that a query of the LLM is a single read-only statement and caps the rows it returns.
+// Queries also run in a transaction that is rolled back, the check protects databases without transactions.
+func limitAgentQuery(dbType string, query string, limit int) (string, error) {
+	query = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+
+	if dbType == constants.DatabaseTypeRedis {
+		return "", fmt.Errorf("%s is not available for redis, use %s", agentToolRunQuery, agentToolSampleRows)
+	}
+	if err := dbmanager.CheckReadOnlyQuery(dbType, query); err != nil {
+		return "", err
+	}
+
+	if dbType == constants.DatabaseTypeMongoDB {
+		if strings.Contains(query, ".find(") && !strings.Contains(query, ".limit(") {
+			query += fmt.Sprintf(".limit(%d)", limit)
+		}
+		return query, nil
+	}
+
+	stripped := sqlStringLiteralRegex.ReplaceAllString(sqlCommentRegex.ReplaceAllString(query, " "), "''")
+	first := strings.ToUpper(sqlWordRegex.FindString(stripped))
+	switch dbType {
+	case constants.DatabaseTypeNeo4j, constants.DatabaseTypeCassandra:
+		if !limitKeywordRegex.MatchString(stripped) {
+			query += fmt.Sprintf(" LIMIT %d", limit)
+		}
+	case constants.DatabaseTypeMSSQL:
+		// Results are capped after the query ran, ORDER BY isn't allowed in MSSQL subqueries
+	default:
+		if first == "SELECT" || first == "WITH" {
+			query = fmt.Sprintf("SELECT * FROM (%s) AS agent_query LIMIT %d", query, limit)
+		}
+	}
+	return query, nil
+}
+
+// distinctValuesQuery returns the query counting the rows of the most frequent values of a column
+func distinctValuesQuery(dbType string, table string, column string, limit int) (string, error) {
+	switch dbType {
+	case constants.DatabaseTypePostgreSQL, constants.DatabaseTypeYugabyteDB, constants.DatabaseTypeSQLite,
+		constants.DatabaseTypeDuckDB, constants.DatabaseTypeSpreadsheet:
+		return fmt.Sprintf(`SELECT %s AS value, COUNT(*) AS total FROM %s GROUP BY %s ORDER BY total DESC LIMIT %d`,
+			quoteAgentIdentifier(column, `"`, `"`), quoteAgentIdentifier(table, `"`, `"`), quoteAgentIdentifier(column, `"`, `"`), limit), nil
+	case constants.DatabaseTypeMySQL, constants.DatabaseTypeClickhouse:
+		return fmt.Sprintf("SELECT %s AS value, COUNT(*) AS total FROM %s GROUP BY %s ORDER BY total DESC LIMIT %d",
+			quoteAgentIdentifier(column, "`", "`"), quoteAgentIdentifier(table, "`", "`"), quoteAgentIdentifier(column, "`", "`"), limit), nil
+	case constants.DatabaseTypeMSSQL:
+		return fmt.Sprintf("SELECT TOP %d %s AS value, COUNT(*) AS total FROM %s GROUP BY %s ORDER BY total DESC",
+			limit, quoteAgentIdentifier(column, "[", "]"), quoteAgentIdentifier(table, "[", "]"), quoteAgentIdentifier(column, "[", "]")), nil
+	case constants.DatabaseTypeNeo4j:
+		return fmt.Sprintf("MATCH (n:%s) RETURN n.%s AS value, count(*) AS total ORDER BY total DESC LIMIT %d",
+			quoteAgentIdentifier(table, "`", "`"), quoteAgentIdentifier(column, "`", "`"), limit), nil
+	case constants.DatabaseTypeMongoDB:
+		pipeline, err := json.Marshal([]map[string]interface{}{
+			{"$group": map[string]interface{}{"_id": "$" + column, "total": map[string]interface{}{"$sum": 1}}},
+			{"$sort": map[string]interface{}{"total": -1}},
+			{"$limit": limit},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to build the pipeline: %v", err)
+		}
+		return fmt.Sprintf("db.%s.aggregate(%s)", table, string(pipeline)), nil
+	default:
+		return "", fmt.Errorf("%s is not available for %s databases", agentToolDistinctValues, dbType)
+	}
+}
+
+// quoteAgentIdentifier quotes a table or column name, doubling the closing quote inside it
+func quoteAgentIdentifier(name string, open string, close string) string {
+	return open + strings.ReplaceAll(name, close, close+close) + close
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"neobase-ai/config"
+	"neobase-ai/internal/constants"
+	"neobase-ai/internal/models"
+	"neobase-ai/pkg/dbmanager"
+	"neobase-ai/pkg/llm"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// buildLLMContext fits the chat's LLM messages into the model's context limit. The schema and pinned messages are
+// always kept, older turns that don't fit are replaced by a rolling summary persisted as a summary LLM message.
+func (s *chatService) buildLLMContext(ctx context.Context, chat *models.Chat, client llm.Client, messages []*models.LLMMessage, dbType string) []*models.LLMMessage {
+	modelInfo := client.GetModelInfo()
+
+	var existingSummary *models.LLMMessage
+	history := make([]*models.LLMMessage, 0, len(messages))
+	for _, msg := range messages {
+		if msg.Role == string(constants.MessageTypeSummary) {
+			existingSummary = msg
+			continue
+		}
+		history = append(history, msg)
+	}
+
+	if modelInfo.ContextLimit <= 0 {
+		return history
+	}
+
+	// Leave room for the system prompt, the response schema and the completion
+	budget := modelInfo.ContextLimit * (100 - constants.ContextSafetyMarginPercent) / 100
+	budget -= modelInfo.MaxCompletionTokens
+	budget -= llm.EstimateTokens(modelInfo.Provider, constants.GetSystemPrompt(modelInfo.Provider, dbType, chat.Settings.NonTechMode))
+	if responseSchema, ok := constants.GetLLMResponseSchema(modelInfo.Provider, dbType).(string); ok {
+		budget -= llm.EstimateTokens(modelInfo.Provider, responseSchema)
+	}
+
+	pinned := make(map[primitive.ObjectID]bool)
+	pinnedMessages, err := s.chatRepo.FindPinnedMessagesByChat(chat.ID)
+	if err != nil {
+		log.Printf("ChatService -> buildLLMContext -> Error fetching pinned messages: %v", err)
+	}
+	for _, msg := range pinnedMessages {
+		pinned[msg.ID] = true
+	}
+
+	window := llm.BuildContextWindow(modelInfo.Provider, history, pinned, budget, constants.ConversationSummaryMaxTokens)
+	if len(window.Dropped) == 0 {
+		return append(window.System, window.Kept...)
+	}
+	log.Printf("ChatService -> buildLLMContext -> Context limit %d of %s reached, summarizing %d older messages",
+		modelInfo.ContextLimit, modelInfo.Name, len(window.Dropped))
+
+	contextMessages := make([]*models.LLMMessage, 0, len(window.System)+len(window.Kept)+1)
+	contextMessages = append(contextMessages, window.System...)
+	summary, err := s.getConversationSummary(ctx, chat, client, existingSummary, window.Dropped, budget)
+	if err != nil {
+		// The dropped turns are left out rather than exceeding the context limit
+		log.Printf("ChatService -> buildLLMContext -> Error summarizing conversation: %v", err)
+	} else {
+		contextMessages = append(contextMessages, summary)
+	}
+	return append(contextMessages, window.Kept...)
+}
+
+// getConversationSummary returns the summary of the dropped messages, reusing or extending the stored one when it
+// still matches the history. The stored summary is regenerated when a summarized message was edited.
+func (s *chatService) getConversationSummary(ctx context.Context, chat *models.Chat, client llm.Client, existing *models.LLMMessage, dropped []*models.LLMMessage, budget int) (*models.LLMMessage, error) {
+	previousSummary := ""
+	toSummarize := dropped
+	if existing != nil {
+		summarizedCount := 0
+		switch count := existing.Content["message_count"].(type) {
+		case int32:
+			summarizedCount = int(count)
+		case int64:
+			summarizedCount = int(count)
+		case int:
+			summarizedCount = count
+		}
+		fingerprint, _ := existing.Content["fingerprint"].(string)
+		summary, _ := existing.Content["summary"].(string)
+
+		if summarizedCount > 0 && summarizedCount <= len(dropped) && fingerprint == llm.ContextFingerprint(dropped[:summarizedCount]) {
+			if summarizedCount == len(dropped) {
+				return existing, nil
+			}
+			previousSummary = summary
+			toSummarize = dropped[summarizedCount:]
+		} else {
+			log.Printf("ChatService -> getConversationSummary -> Stored summary no longer matches the history, regenerating it")
+		}
+	}
+
+	// Summarize in chunks so each request fits the context as well
+	modelInfo := client.GetModelInfo()
+	chunkBudget := budget / 2
+	for start := 0; start < len(toSummarize); {
+		end, tokens := start, 0
+		for end < len(toSummarize) {
+			msgTokens := llm.EstimateMessageTokens(modelInfo.Provider, toSummarize[end])
+			if end > start && tokens+msgTokens > chunkBudget {
+				break
+			}
+			tokens += msgTokens
+			end++
+		}
+
+		transcript := llm.SummaryTranscript(toSummarize[start:end])
+		if transcript != "" {
+			if previousSummary == "" {
+				previousSummary = "(none)"
+			}
+			summary, err := client.GenerateText(ctx, constants.ConversationSummaryPrompt, fmt.Sprintf(constants.ConversationSummaryRequest, previousSummary, transcript))
+			if err != nil {
+				return nil, err
+			}
+			previousSummary = summary
+		}
+		start = end
+	}
+
+	summaryMsg := &models.LLMMessage{
+		Base:      models.NewBase(),
+		UserID:    chat.UserID,
+		ChatID:    chat.ID,
+		MessageID: dropped[len(dropped)-1].MessageID, // Last message covered by the summary
+		Role:      string(constants.MessageTypeSummary),
+		Content: map[string]interface{}{
+			"summary":       previousSummary,
+			"message_count": len(dropped),
+			"fingerprint":   llm.ContextFingerprint(dropped),
+		},
+	}
+
+	// Only the latest summary is kept
+	if err := s.llmRepo.DeleteMessagesByRole(chat.ID, string(constants.MessageTypeSummary)); err != nil {
+		log.Printf("ChatService -> getConversationSummary -> Error deleting previous summary: %v", err)
+	}
+	if err := s.llmRepo.CreateMessage(summaryMsg); err != nil {
+		log.Printf("ChatService -> getConversationSummary -> Error saving summary: %v", err)
+	}
+	return summaryMsg, nil
+}
+
+// Number of latest user messages the relevant tables are looked up for, so follow-ups keep their tables
+const schemaRetrievalUserMessages = 2
+
+// applyRelevantSchema replaces the schema sent to the LLM by the tables relevant to the latest user messages when the
+// schema is large. The stored system message is left untouched, the full schema is kept on any error.
+func (s *chatService) applyRelevantSchema(ctx context.Context, chat *models.Chat, messages []*models.LLMMessage) []*models.LLMMessage {
+	if config.Env.SchemaRetrievalTopK <= 0 {
+		return messages
+	}
+
+	var questions []string
+	for i := len(messages) - 1; i >= 0 && len(questions) < schemaRetrievalUserMessages; i-- {
+		if messages[i].Role != string(constants.MessageTypeUser) {
+			continue
+		}
+		if userMsg, ok := messages[i].Content["user_message"].(string); ok && userMsg != "" {
+			questions = append(questions, userMsg)
+		}
+	}
+	if len(questions) == 0 {
+		return messages
+	}
+
+	opts := dbmanager.SchemaRetrievalOptions{
+		TopK:      config.Env.SchemaRetrievalTopK,
+		MinTables: config.Env.SchemaRetrievalMinTables,
+	}
+	if config.Env.SchemaRetrievalEmbeddings && s.llmManager != nil {
+		embedder, ok := s.llmManager.GetEmbedder(chat.Settings.LLMProvider)
+		if !ok {
+			embedder, ok = s.llmManager.GetEmbedder(config.Env.DefaultLLMClient)
+		}
+		if ok {
+			opts.Embed = embedder.Embed
+			opts.EmbeddingModel = embedder.EmbeddingModel()
+		}
+	}
+
+	schema, ok, err := s.dbManager.GetSchemaManager().FormatRelevantSchema(ctx, chat.ID.Hex(), strings.Join(questions, "\n"), opts)
+	if err != nil {
+		log.Printf("ChatService -> applyRelevantSchema -> Error selecting relevant tables, sending the full schema: %v", err)
+		return messages
+	}
+	if !ok {
+		return messages
+	}
+
+	result := make([]*models.LLMMessage, len(messages))
+	for i, msg := range messages {
+		result[i] = msg
+		if _, isSchema := msg.Content["schema_update"].(string); msg.Role == string(constants.MessageTypeSystem) && isSchema {
+			schemaMsg := *msg
+			schemaMsg.Content = map[string]interface{}{"schema_update": schema}
+			result[i] = &schemaMsg
+		}
+	}
+	return result
+}
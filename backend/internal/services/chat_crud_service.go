@@ -59,6 +59,9 @@ type ChatService interface {
 	DisconnectDB(ctx context.Context, userID, chatID string, streamID string) (uint32, error)
 	ExecuteQuery(ctx context.Context, userID, chatID string, req *dtos.ExecuteQueryRequest) (*dtos.QueryExecutionResponse, uint32, error)
 	RollbackQuery(ctx context.Context, userID, chatID string, req *dtos.RollbackQueryRequest) (*dtos.QueryExecutionResponse, uint32, error)
+	ExplainQuery(ctx context.Context, userID, chatID string, req *dtos.ExplainQueryRequest) (*dtos.ExplainQueryResponse, uint32, error)
+	ExecuteAllQueries(ctx context.Context, userID, chatID string, req *dtos.ExecuteAllQueriesRequest) (*dtos.ExecuteAllQueriesResponse, uint32, error)
+	ExportQueryResult(ctx context.Context, userID, chatID, queryID, format, streamID string) (*QueryResultExport, uint32, error)
 	CancelQueryExecution(userID, chatID, messageID, queryID, streamID string)
 	processMessage(ctx context.Context, userID, chatID string, messageID, streamID string) error
 	processLLMResponseAndRunQuery(ctx context.Context, userID, chatID string, messageID, streamID string) error
@@ -71,6 +74,9 @@ type ChatService interface {
 	DownloadSpreadsheetTableData(userID, chatID, tableName string) (*dtos.SpreadsheetDownloadResponse, uint32, error)
 	DownloadSpreadsheetTableDataWithFilter(userID, chatID, tableName string, rowIDs []string) (*dtos.SpreadsheetDownloadResponse, uint32, error)
 
+	// Database file operations
+	StoreDatabaseFile(userID, chatID, fileName string, data []byte) (*dtos.DatabaseFileUploadResponse, uint32, error)
+
 	RefreshSchema(ctx context.Context, userID, chatID string, sync bool) (uint32, error)
 	GetQueryResults(ctx context.Context, userID, chatID, messageID, queryID, streamID string, offset int) (*dtos.QueryResultsResponse, uint32, error)
 	GetQueryRecommendations(ctx context.Context, userID, chatID string) (*dtos.QueryRecommendationsResponse, uint32, error)
@@ -81,6 +87,7 @@ type chatService struct {
 	llmRepo         repositories.LLMMessageRepository
 	dbManager       *dbmanager.Manager
 	llmClient       llm.Client
+	llmManager      *llm.Manager
 	streamChans     map[string]chan dtos.StreamResponse
 	streamHandler   StreamHandler
 	activeProcesses map[string]context.CancelFunc // key: streamID
@@ -93,11 +100,15 @@ func isValidDBType(dbType string) bool {
 		constants.DatabaseTypePostgreSQL,
 		constants.DatabaseTypeYugabyteDB,
 		constants.DatabaseTypeMySQL,
+		constants.DatabaseTypeMSSQL,
 		constants.DatabaseTypeClickhouse,
 		constants.DatabaseTypeMongoDB,
 		constants.DatabaseTypeRedis,
 		constants.DatabaseTypeNeo4j,
+		constants.DatabaseTypeCassandra,
 		constants.DatabaseTypeSpreadsheet,
+		constants.DatabaseTypeSQLite,
+		constants.DatabaseTypeDuckDB,
 	}
 
 	for _, validType := range validTypes {
@@ -109,6 +120,40 @@ func isValidDBType(dbType string) bool {
 	return false
 }
 
+// validateConnectionFields checks the fields required by the connection type, spreadsheet and file databases have no server to connect to
+func validateConnectionFields(conn *dtos.CreateConnectionRequest) error {
+	if conn.Type == constants.DatabaseTypeSpreadsheet || constants.IsFileDatabaseType(conn.Type) {
+		return nil
+	}
+	if conn.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+	if conn.Username == "" {
+		return fmt.Errorf("username is required")
+	}
+	if conn.Database == "" {
+		return fmt.Errorf("database is required")
+	}
+	if conn.SSHEnabled {
+		if !dbmanager.SupportsSSHTunnel(conn.Type) {
+			return fmt.Errorf("SSH tunnel is not supported for %s connections", conn.Type)
+		}
+		if conn.SSHHost == nil || *conn.SSHHost == "" {
+			return fmt.Errorf("SSH host is required when SSH tunnel is enabled")
+		}
+		if conn.SSHUsername == nil || *conn.SSHUsername == "" {
+			return fmt.Errorf("SSH username is required when SSH tunnel is enabled")
+		}
+		if (conn.SSHPrivateKey == nil || *conn.SSHPrivateKey == "") && (conn.SSHPassphrase == nil || *conn.SSHPassphrase == "") {
+			return fmt.Errorf("SSH private key or passphrase is required when SSH tunnel is enabled")
+		}
+		if err := dbmanager.ValidateSSHHostKey(conn.SSHHostKey, conn.SSHSkipHostKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *chatService) SetStreamHandler(handler StreamHandler) {
 	s.streamHandler = handler
 }
@@ -137,6 +182,7 @@ func NewChatService(
 	llmRepo repositories.LLMMessageRepository,
 	dbManager *dbmanager.Manager,
 	llmClient llm.Client,
+	llmManager *llm.Manager,
 ) ChatService {
 	// Initialize crypto instance
 	crypto, err := utils.NewFromConfig()
@@ -150,6 +196,7 @@ func NewChatService(
 		llmRepo:         llmRepo,
 		dbManager:       dbManager,
 		llmClient:       llmClient,
+		llmManager:      llmManager,
 		streamChans:     make(map[string]chan dtos.StreamResponse),
 		activeProcesses: make(map[string]context.CancelFunc),
 		crypto:          crypto,
@@ -187,6 +234,29 @@ func (s *chatService) decryptQueryResult(result string) string {
 }
 
 // Create a new chat
+// sshConfigChanged checks if the SSH tunnel of a connection differs from the requested one
+func sshConfigChanged(existing *models.Connection, req *dtos.CreateConnectionRequest) bool {
+	if existing.SSHEnabled != req.SSHEnabled {
+		return true
+	}
+	if !req.SSHEnabled {
+		return false
+	}
+	stringValue := func(value *string) string {
+		if value == nil {
+			return ""
+		}
+		return *value
+	}
+	return stringValue(existing.SSHHost) != stringValue(req.SSHHost) ||
+		stringValue(existing.SSHPort) != stringValue(req.SSHPort) ||
+		stringValue(existing.SSHUsername) != stringValue(req.SSHUsername) ||
+		stringValue(existing.SSHPrivateKey) != stringValue(req.SSHPrivateKey) ||
+		stringValue(existing.SSHPassphrase) != stringValue(req.SSHPassphrase) ||
+		stringValue(existing.SSHHostKey) != stringValue(req.SSHHostKey) ||
+		existing.SSHSkipHostKey != req.SSHSkipHostKey
+}
+
 func (s *chatService) Create(userID string, req *dtos.CreateChatRequest) (*dtos.ChatResponse, uint32, error) {
 	log.Printf("Creating chat for user %s", userID)
 
@@ -210,9 +280,12 @@ func (s *chatService) Create(userID string, req *dtos.CreateChatRequest) (*dtos.
 	if !isValidDBType(req.Connection.Type) {
 		return nil, http.StatusBadRequest, fmt.Errorf("Unsupported data source type: %s", req.Connection.Type)
 	}
+	if err := validateConnectionFields(&req.Connection); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
 
-	// Skip connection test for spreadsheet type as it doesn't have traditional database connection
-	if req.Connection.Type != constants.DatabaseTypeSpreadsheet {
+	// Skip connection test for spreadsheet and file database types as they don't have traditional database connection
+	if req.Connection.Type != constants.DatabaseTypeSpreadsheet && !constants.IsFileDatabaseType(req.Connection.Type) {
 		// Test connection without creating a persistent connection
 		err := s.dbManager.TestConnection(&dbmanager.ConnectionConfig{
 			Type:           req.Connection.Type,
@@ -227,6 +300,14 @@ func (s *chatService) Create(userID string, req *dtos.CreateChatRequest) (*dtos.
 			SSLCertURL:     req.Connection.SSLCertURL,
 			SSLKeyURL:      req.Connection.SSLKeyURL,
 			SSLRootCertURL: req.Connection.SSLRootCertURL,
+			SSHEnabled:     req.Connection.SSHEnabled,
+			SSHHost:        req.Connection.SSHHost,
+			SSHPort:        req.Connection.SSHPort,
+			SSHUsername:    req.Connection.SSHUsername,
+			SSHPrivateKey:  req.Connection.SSHPrivateKey,
+			SSHPassphrase:  req.Connection.SSHPassphrase,
+			SSHHostKey:     req.Connection.SSHHostKey,
+			SSHSkipHostKey: req.Connection.SSHSkipHostKey,
 		})
 		if err != nil {
 			return nil, http.StatusBadRequest, fmt.Errorf("%v", err)
@@ -258,6 +339,13 @@ func (s *chatService) Create(userID string, req *dtos.CreateChatRequest) (*dtos.
 		connection.Username = &placeholderUsername
 		connection.Password = &placeholderPassword
 		connection.Port = &placeholderPort
+	} else if constants.IsFileDatabaseType(req.Connection.Type) {
+		// File databases get their storage path and file name once a file is uploaded
+		connection.IsExampleDB = false
+		emptyUsername := ""
+		placeholderPort := "0"
+		connection.Username = &emptyUsername
+		connection.Port = &placeholderPort
 	} else {
 		// For traditional database connections
 		connection.Host = req.Connection.Host
@@ -271,6 +359,14 @@ func (s *chatService) Create(userID string, req *dtos.CreateChatRequest) (*dtos.
 		connection.SSLCertURL = req.Connection.SSLCertURL
 		connection.SSLKeyURL = req.Connection.SSLKeyURL
 		connection.SSLRootCertURL = req.Connection.SSLRootCertURL
+		connection.SSHEnabled = req.Connection.SSHEnabled
+		connection.SSHHost = req.Connection.SSHHost
+		connection.SSHPort = req.Connection.SSHPort
+		connection.SSHUsername = req.Connection.SSHUsername
+		connection.SSHPrivateKey = req.Connection.SSHPrivateKey
+		connection.SSHPassphrase = req.Connection.SSHPassphrase
+		connection.SSHHostKey = req.Connection.SSHHostKey
+		connection.SSHSkipHostKey = req.Connection.SSHSkipHostKey
 	}
 
 	// Encrypt connection details
@@ -289,8 +385,23 @@ func (s *chatService) Create(userID string, req *dtos.CreateChatRequest) (*dtos.
 	if req.Settings.NonTechMode != nil {
 		settings.NonTechMode = *req.Settings.NonTechMode
 	}
-	log.Printf("ChatService -> Create -> Creating chat with settings: AutoExecuteQuery=%v, ShareDataWithAI=%v, NonTechMode=%v",
-		settings.AutoExecuteQuery, settings.ShareDataWithAI, settings.NonTechMode)
+	if req.Settings.AgentMode != nil {
+		settings.AgentMode = *req.Settings.AgentMode
+	}
+	if req.Settings.ReadOnly != nil {
+		settings.ReadOnly = *req.Settings.ReadOnly
+	}
+	if settings.ReadOnly && !constants.SupportsReadOnlyMode(connection.Type) {
+		return nil, http.StatusBadRequest, fmt.Errorf("read-only mode is not supported for %s", connection.Type)
+	}
+	if err := s.applyLLMSettings(&settings, &req.Settings); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if err := applyResultLimitSettings(&settings, &req.Settings); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	log.Printf("ChatService -> Create -> Creating chat with settings: AutoExecuteQuery=%v, ShareDataWithAI=%v, NonTechMode=%v, ReadOnly=%v",
+		settings.AutoExecuteQuery, settings.ShareDataWithAI, settings.NonTechMode, settings.ReadOnly)
 	// Create chat with connection
 	chat := models.NewChat(userObjID, connection, settings)
 	if err := s.chatRepo.Create(chat); err != nil {
@@ -323,6 +434,9 @@ func (s *chatService) CreateWithoutConnectionPing(userID string, req *dtos.Creat
 	if !isValidDBType(req.Connection.Type) {
 		return nil, http.StatusBadRequest, fmt.Errorf("Unsupported data source type: %s", req.Connection.Type)
 	}
+	if err := validateConnectionFields(&req.Connection); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
 
 	userObjID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
@@ -349,6 +463,13 @@ func (s *chatService) CreateWithoutConnectionPing(userID string, req *dtos.Creat
 		connection.Username = &placeholderUsername
 		connection.Password = &placeholderPassword
 		connection.Port = &placeholderPort
+	} else if constants.IsFileDatabaseType(req.Connection.Type) {
+		// File databases get their storage path and file name once a file is uploaded
+		connection.IsExampleDB = false
+		emptyUsername := ""
+		placeholderPort := "0"
+		connection.Username = &emptyUsername
+		connection.Port = &placeholderPort
 	} else {
 		// For traditional database connections
 		connection.Host = req.Connection.Host
@@ -363,6 +484,14 @@ func (s *chatService) CreateWithoutConnectionPing(userID string, req *dtos.Creat
 		connection.SSLCertURL = req.Connection.SSLCertURL
 		connection.SSLKeyURL = req.Connection.SSLKeyURL
 		connection.SSLRootCertURL = req.Connection.SSLRootCertURL
+		connection.SSHEnabled = req.Connection.SSHEnabled
+		connection.SSHHost = req.Connection.SSHHost
+		connection.SSHPort = req.Connection.SSHPort
+		connection.SSHUsername = req.Connection.SSHUsername
+		connection.SSHPrivateKey = req.Connection.SSHPrivateKey
+		connection.SSHPassphrase = req.Connection.SSHPassphrase
+		connection.SSHHostKey = req.Connection.SSHHostKey
+		connection.SSHSkipHostKey = req.Connection.SSHSkipHostKey
 	}
 
 	// Encrypt connection details
@@ -379,6 +508,12 @@ func (s *chatService) CreateWithoutConnectionPing(userID string, req *dtos.Creat
 	if req.Settings.ShareDataWithAI != nil {
 		settings.ShareDataWithAI = *req.Settings.ShareDataWithAI
 	}
+	if err := s.applyLLMSettings(&settings, &req.Settings); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if err := applyResultLimitSettings(&settings, &req.Settings); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
 	// Create chat with connection
 	chat := models.NewChat(userObjID, connection, settings)
 	if err := s.chatRepo.Create(chat); err != nil {
@@ -425,20 +560,43 @@ func (s *chatService) Update(userID, chatID string, req *dtos.UpdateChatRequest)
 		existingConn := chat.Connection
 		utils.DecryptConnection(&existingConn)
 
+		// SSH secrets are never returned to the client, keep the stored ones unless new ones are sent
+		if req.Connection.SSHEnabled && existingConn.SSHEnabled && req.Connection.SSHPrivateKey == nil && req.Connection.SSHPassphrase == nil {
+			req.Connection.SSHPrivateKey = existingConn.SSHPrivateKey
+			req.Connection.SSHPassphrase = existingConn.SSHPassphrase
+		}
+
+		if err := validateConnectionFields(req.Connection); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+
 		// Check if critical connection details have changed
 		// For spreadsheet connections, we never consider credentials as changed since they use internal credentials
 		if req.Connection.Type == constants.DatabaseTypeSpreadsheet {
 			credentialsChanged = false
+		} else if constants.IsFileDatabaseType(req.Connection.Type) {
+			// The uploaded file stays attached to the chat, unless it was uploaded for another database type
+			if existingConn.Type == req.Connection.Type {
+				req.Connection.Host = existingConn.Host
+				req.Connection.Database = existingConn.Database
+			} else {
+				req.Connection.Host = ""
+				req.Connection.Database = ""
+			}
+			req.Connection.Username = ""
+			req.Connection.Password = nil
+			credentialsChanged = existingConn.Type != req.Connection.Type
 		} else {
 			credentialsChanged = existingConn.Database != req.Connection.Database ||
 				existingConn.Host != req.Connection.Host ||
 				existingConn.Port != req.Connection.Port ||
 				*existingConn.Username != req.Connection.Username ||
-				(req.Connection.Password != nil && existingConn.Password != nil && *existingConn.Password != *req.Connection.Password)
+				(req.Connection.Password != nil && existingConn.Password != nil && *existingConn.Password != *req.Connection.Password) ||
+				sshConfigChanged(&existingConn, req.Connection)
 		}
 
-		// Skip connection test for spreadsheet type as it doesn't have traditional database connection
-		if req.Connection.Type != constants.DatabaseTypeSpreadsheet {
+		// Skip connection test for spreadsheet and file database types as they don't have traditional database connection
+		if req.Connection.Type != constants.DatabaseTypeSpreadsheet && !constants.IsFileDatabaseType(req.Connection.Type) {
 			// Test connection without creating a persistent connection
 			err = s.dbManager.TestConnection(&dbmanager.ConnectionConfig{
 				Type:           req.Connection.Type,
@@ -453,6 +611,14 @@ func (s *chatService) Update(userID, chatID string, req *dtos.UpdateChatRequest)
 				SSLCertURL:     req.Connection.SSLCertURL,
 				SSLKeyURL:      req.Connection.SSLKeyURL,
 				SSLRootCertURL: req.Connection.SSLRootCertURL,
+				SSHEnabled:     req.Connection.SSHEnabled,
+				SSHHost:        req.Connection.SSHHost,
+				SSHPort:        req.Connection.SSHPort,
+				SSHUsername:    req.Connection.SSHUsername,
+				SSHPrivateKey:  req.Connection.SSHPrivateKey,
+				SSHPassphrase:  req.Connection.SSHPassphrase,
+				SSHHostKey:     req.Connection.SSHHostKey,
+				SSHSkipHostKey: req.Connection.SSHSkipHostKey,
 			})
 			if err != nil {
 				return nil, http.StatusBadRequest, fmt.Errorf("%v", err)
@@ -473,6 +639,14 @@ func (s *chatService) Update(userID, chatID string, req *dtos.UpdateChatRequest)
 			SSLCertURL:     req.Connection.SSLCertURL,
 			SSLKeyURL:      req.Connection.SSLKeyURL,
 			SSLRootCertURL: req.Connection.SSLRootCertURL,
+			SSHEnabled:     req.Connection.SSHEnabled,
+			SSHHost:        req.Connection.SSHHost,
+			SSHPort:        req.Connection.SSHPort,
+			SSHUsername:    req.Connection.SSHUsername,
+			SSHPrivateKey:  req.Connection.SSHPrivateKey,
+			SSHPassphrase:  req.Connection.SSHPassphrase,
+			SSHHostKey:     req.Connection.SSHHostKey,
+			SSHSkipHostKey: req.Connection.SSHSkipHostKey,
 			Base:           models.NewBase(),
 		}
 
@@ -514,6 +688,9 @@ func (s *chatService) Update(userID, chatID string, req *dtos.UpdateChatRequest)
 		chat.SelectedCollections = *req.SelectedCollections
 	}
 
+	// Sessions are opened in the chat's mode, a new mode needs a new connection
+	readOnlyChanged := false
+
 	// Update auto execute query if provided
 	if req.Settings != nil {
 		if req.Settings.AutoExecuteQuery != nil {
@@ -528,6 +705,25 @@ func (s *chatService) Update(userID, chatID string, req *dtos.UpdateChatRequest)
 			log.Printf("ChatService -> Update -> NonTechMode: %v", *req.Settings.NonTechMode)
 			chat.Settings.NonTechMode = *req.Settings.NonTechMode
 		}
+		if req.Settings.AgentMode != nil {
+			log.Printf("ChatService -> Update -> AgentMode: %v", *req.Settings.AgentMode)
+			chat.Settings.AgentMode = *req.Settings.AgentMode
+		}
+		if req.Settings.ReadOnly != nil && *req.Settings.ReadOnly != chat.Settings.ReadOnly {
+			log.Printf("ChatService -> Update -> ReadOnly: %v", *req.Settings.ReadOnly)
+			chat.Settings.ReadOnly = *req.Settings.ReadOnly
+			readOnlyChanged = true
+		}
+		if err := s.applyLLMSettings(&chat.Settings, req.Settings); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		if err := applyResultLimitSettings(&chat.Settings, req.Settings); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+	}
+
+	if chat.Settings.ReadOnly && !constants.SupportsReadOnlyMode(chat.Connection.Type) {
+		return nil, http.StatusBadRequest, fmt.Errorf("read-only mode is not supported for %s", chat.Connection.Type)
 	}
 
 	// Update the chat
@@ -535,6 +731,14 @@ func (s *chatService) Update(userID, chatID string, req *dtos.UpdateChatRequest)
 		return nil, http.StatusInternalServerError, fmt.Errorf("failed to update chat: %v", err)
 	}
 
+	// The next ConnectDB opens the sessions in the new mode
+	if readOnlyChanged {
+		log.Printf("ChatService -> Update -> Read-only mode changed, disconnecting existing connection")
+		if err := s.dbManager.Disconnect(chatID, userID, false); err != nil {
+			log.Printf("ChatService -> Update -> Warning: Failed to disconnect existing connection: %v", err)
+		}
+	}
+
 	// If selected collections changed, trigger a schema refresh
 	if selectedCollectionsChanged {
 		log.Printf("ChatService -> Update -> Triggering schema refresh due to selected collections change")
@@ -599,6 +803,11 @@ func (s *chatService) Delete(userID, chatID string) (uint32, error) {
 		if err := s.dbManager.Disconnect(chatID, userID, true); err != nil {
 			log.Printf("failed to delete DB connection: %v", err)
 		}
+
+		// Remove the uploaded database file once it's no longer open
+		if constants.IsFileDatabaseType(chat.Connection.Type) {
+			s.removeDatabaseFile(chatID, &chat.Connection)
+		}
 	}()
 
 	return http.StatusOK, nil
@@ -915,6 +1124,13 @@ func (s *chatService) Duplicate(userID, chatID string, duplicateMessages bool) (
 		Base:                models.NewBase(), // Create a new Base with new ID and timestamps
 	}
 
+	// Uploaded database files belong to a single chat, so the duplicate gets its own copy
+	if constants.IsFileDatabaseType(newChat.Connection.Type) {
+		if err := s.copyDatabaseFile(&newChat.Connection, newChat.ID.Hex()); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+	}
+
 	if err := s.chatRepo.Create(newChat); err != nil {
 		return nil, http.StatusInternalServerError, fmt.Errorf("failed to create duplicate chat: %v", err)
 	}
@@ -991,6 +1207,9 @@ func (s *chatService) Duplicate(userID, chatID string, duplicateMessages bool) (
 							IsEdited:               q.IsEdited,
 							Metadata:               q.Metadata,
 							ActionAt:               q.ActionAt,
+							OriginalQuery:          q.OriginalQuery,
+							FixAttempts:            q.FixAttempts,
+							SafetyWarnings:         q.SafetyWarnings,
 						}
 
 						// Copy pagination if it exists
@@ -1362,7 +1581,7 @@ func (s *chatService) ListPinnedMessages(userID, chatID string) (*dtos.MessageLi
 func (s *chatService) EditQuery(ctx context.Context, userID, chatID, messageID, queryID string, query string) (*dtos.EditQueryResponse, uint32, error) {
 	log.Printf("ChatService -> EditQuery -> userID: %s, chatID: %s, messageID: %s, queryID: %s, query: %s", userID, chatID, messageID, queryID, query)
 
-	_, message, queryData, err := s.verifyQueryOwnership(userID, chatID, messageID, queryID)
+	chat, message, queryData, err := s.verifyQueryOwnership(userID, chatID, messageID, queryID)
 	if err != nil {
 		return nil, http.StatusBadRequest, err
 	}
@@ -1372,6 +1591,7 @@ func (s *chatService) EditQuery(ctx context.Context, userID, chatID, messageID,
 	}
 
 	originalQuery := queryData.Query
+	var editedQuery *models.Query
 	// Fix the query update logic
 	for i := range *message.Queries {
 		if (*message.Queries)[i].ID == queryData.ID {
@@ -1380,6 +1600,9 @@ func (s *chatService) EditQuery(ctx context.Context, userID, chatID, messageID,
 			if (*message.Queries)[i].Pagination != nil && (*message.Queries)[i].Pagination.PaginatedQuery != nil {
 				(*message.Queries)[i].Pagination.PaginatedQuery = utils.ToStringPtr(strings.Replace(*(*message.Queries)[i].Pagination.PaginatedQuery, originalQuery, query, 1))
 			}
+			// The edited query may do something else than the LLM's one
+			analyzeQuerySafety(chat.Connection.Type, &(*message.Queries)[i])
+			editedQuery = &(*message.Queries)[i]
 		}
 	}
 
@@ -1449,13 +1672,18 @@ func (s *chatService) EditQuery(ctx context.Context, userID, chatID, messageID,
 		return nil, http.StatusBadRequest, fmt.Errorf("failed to update LLM message: %v", err)
 	}
 
-	return &dtos.EditQueryResponse{
+	response := &dtos.EditQueryResponse{
 		ChatID:    chatID,
 		MessageID: messageID,
 		QueryID:   queryID,
 		Query:     query,
 		IsEdited:  true,
-	}, http.StatusOK, nil
+	}
+	if editedQuery != nil {
+		response.IsCritical = editedQuery.IsCritical
+		response.SafetyWarnings = editedQuery.SafetyWarnings
+	}
+	return response, http.StatusOK, nil
 }
 
 // Get the DB connection status for current chat
@@ -1481,10 +1709,16 @@ func (s *chatService) GetDBConnectionStatus(ctx context.Context, userID, chatID
 		}
 	}
 
+	// The host of file databases is the server side storage path, which isn't exposed
+	host := connInfo.Config.Host
+	if constants.IsFileDatabaseType(connInfo.Config.Type) {
+		host = ""
+	}
+
 	return &dtos.ConnectionStatusResponse{
 		IsConnected: isConnected,
 		Type:        connInfo.Config.Type,
-		Host:        connInfo.Config.Host,
+		Host:        host,
 		Port:        port,
 		Database:    connInfo.Config.Database,
 		Username:    *connInfo.Config.Username,
@@ -1630,6 +1864,11 @@ func (s *chatService) buildChatResponse(chat *models.Chat) *dtos.ChatResponse {
 		username = *connectionCopy.Username
 	}
 
+	// The host of file databases is the server side storage path, which isn't exposed
+	if constants.IsFileDatabaseType(connectionCopy.Type) {
+		connectionCopy.Host = ""
+	}
+
 	return &dtos.ChatResponse{
 		ID:     chat.ID.Hex(),
 		UserID: chat.UserID.Hex(),
@@ -1646,6 +1885,12 @@ func (s *chatService) buildChatResponse(chat *models.Chat) *dtos.ChatResponse {
 			SSLCertURL:     connectionCopy.SSLCertURL,
 			SSLKeyURL:      connectionCopy.SSLKeyURL,
 			SSLRootCertURL: connectionCopy.SSLRootCertURL,
+			SSHEnabled:     connectionCopy.SSHEnabled,
+			SSHHost:        connectionCopy.SSHHost,
+			SSHPort:        connectionCopy.SSHPort,
+			SSHUsername:    connectionCopy.SSHUsername,
+			SSHHostKey:     connectionCopy.SSHHostKey,
+			SSHSkipHostKey: connectionCopy.SSHSkipHostKey,
 		},
 		SelectedCollections: chat.SelectedCollections,
 		CreatedAt:           chat.CreatedAt.Format(time.RFC3339),
@@ -1654,10 +1899,118 @@ func (s *chatService) buildChatResponse(chat *models.Chat) *dtos.ChatResponse {
 			AutoExecuteQuery: chat.Settings.AutoExecuteQuery,
 			ShareDataWithAI:  chat.Settings.ShareDataWithAI,
 			NonTechMode:      chat.Settings.NonTechMode,
+			LLMProvider:      chat.Settings.LLMProvider,
+			LLMModel:         chat.Settings.LLMModel,
+			AgentMode:        chat.Settings.AgentMode,
+			ReadOnly:         chat.Settings.ReadOnly,
+			MaxResultRows:    chat.Settings.MaxResultRows,
+			MaxResultBytes:   chat.Settings.MaxResultBytes,
 		},
 	}
 }
 
+// applyResultLimitSettings sets the chat's caps of streamed query results, 0 uses the server defaults
+func applyResultLimitSettings(settings *models.ChatSettings, req *dtos.CreateChatSettings) error {
+	if req.MaxResultRows != nil {
+		if *req.MaxResultRows < 0 {
+			return fmt.Errorf("max_result_rows can't be negative")
+		}
+		settings.MaxResultRows = *req.MaxResultRows
+	}
+	if req.MaxResultBytes != nil {
+		if *req.MaxResultBytes < 0 {
+			return fmt.Errorf("max_result_bytes can't be negative")
+		}
+		settings.MaxResultBytes = *req.MaxResultBytes
+	}
+	return nil
+}
+
+// applyLLMSettings sets the chat's LLM provider and model, checking the provider is registered and allows the model
+func (s *chatService) applyLLMSettings(settings *models.ChatSettings, req *dtos.CreateChatSettings) error {
+	if req.LLMProvider == nil && req.LLMModel == nil {
+		return nil
+	}
+
+	provider, model := settings.LLMProvider, settings.LLMModel
+	if req.LLMProvider != nil {
+		// Switching provider drops a model picked for the previous one
+		if requestedProvider := strings.TrimSpace(*req.LLMProvider); requestedProvider != provider {
+			provider, model = requestedProvider, ""
+		}
+	}
+	if req.LLMModel != nil {
+		model = strings.TrimSpace(*req.LLMModel)
+	}
+
+	if provider != "" || model != "" {
+		if s.llmManager == nil {
+			return fmt.Errorf("LLM selection is not available")
+		}
+		// A model without a provider belongs to the default provider
+		validateProvider := provider
+		if validateProvider == "" {
+			validateProvider = config.Env.DefaultLLMClient
+		}
+		if err := s.llmManager.ValidateModel(validateProvider, model); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("ChatService -> applyLLMSettings -> LLMProvider: %q, LLMModel: %q", provider, model)
+	settings.LLMProvider = provider
+	settings.LLMModel = model
+	return nil
+}
+
+// getLLMClient returns the LLM client selected for the chat, wrapped with the retry and provider fallback policy
+func (s *chatService) getLLMClient(chat *models.Chat) llm.Client {
+	if s.llmManager == nil {
+		return s.llmClient
+	}
+
+	provider, model := config.Env.DefaultLLMClient, ""
+	if chat != nil && (chat.Settings.LLMProvider != "" || chat.Settings.LLMModel != "") {
+		if chat.Settings.LLMProvider != "" {
+			provider = chat.Settings.LLMProvider
+		}
+		model = chat.Settings.LLMModel
+	}
+
+	client, err := s.llmManager.GetFallbackClient(provider, model)
+	if err != nil && provider != config.Env.DefaultLLMClient {
+		// The provider or model may have been removed from the configuration since the chat was saved
+		log.Printf("ChatService -> getLLMClient -> Falling back to default LLM client for chat %s: %v", chat.ID.Hex(), err)
+		client, err = s.llmManager.GetFallbackClient(config.Env.DefaultLLMClient, "")
+	}
+	if err != nil {
+		log.Printf("ChatService -> getLLMClient -> Using default LLM client without fallback: %v", err)
+		return s.llmClient
+	}
+	return client
+}
+
+// buildLLMMetadata records the provider that answered, from the fallback client's response info or the client itself
+func buildLLMMetadata(client llm.Client, responseInfo *llm.ResponseInfo) *models.LLMMetadata {
+	if responseInfo != nil && responseInfo.Provider != "" {
+		return &models.LLMMetadata{
+			Provider:        responseInfo.Provider,
+			Model:           responseInfo.Model,
+			Attempts:        responseInfo.Attempts,
+			FailedProviders: responseInfo.FailedProviders,
+		}
+	}
+	if client == nil {
+		return nil
+	}
+	modelInfo := client.GetModelInfo()
+	return &models.LLMMetadata{
+		Provider: modelInfo.Provider,
+		Model:    modelInfo.Name,
+		Attempts: 1,
+	}
+}
+
 func (s *chatService) buildMessageResponse(msg *models.Message) *dtos.MessageResponse {
 	var userMessageID *string
 	if msg.UserMessageId != nil {
@@ -1686,6 +2039,7 @@ func (s *chatService) buildMessageResponse(msg *models.Message) *dtos.MessageRes
 		NonTechMode:   msg.NonTechMode,
 		IsPinned:      msg.IsPinned,
 		PinnedAt:      pinnedAt,
+		LLMMetadata:   msg.LLMMetadata,
 		CreatedAt:     msg.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:     msg.UpdatedAt.Format(time.RFC3339),
 	}
@@ -1838,13 +2192,21 @@ func (s *chatService) GetAllTables(ctx context.Context, userID, chatID string) (
 
 			// Connection not found, try to connect with proper config
 			connectErr := s.dbManager.Connect(chatID, userID, "", dbmanager.ConnectionConfig{
-				Type:         chat.Connection.Type,
-				Host:         chat.Connection.Host,
-				Port:         chat.Connection.Port,
-				Username:     chat.Connection.Username,
-				Password:     chat.Connection.Password,
-				Database:     chat.Connection.Database,
-				AuthDatabase: chat.Connection.AuthDatabase,
+				Type:           chat.Connection.Type,
+				Host:           chat.Connection.Host,
+				Port:           chat.Connection.Port,
+				Username:       chat.Connection.Username,
+				Password:       chat.Connection.Password,
+				Database:       chat.Connection.Database,
+				AuthDatabase:   chat.Connection.AuthDatabase,
+				SSHEnabled:     chat.Connection.SSHEnabled,
+				SSHHost:        chat.Connection.SSHHost,
+				SSHPort:        chat.Connection.SSHPort,
+				SSHUsername:    chat.Connection.SSHUsername,
+				SSHPrivateKey:  chat.Connection.SSHPrivateKey,
+				SSHPassphrase:  chat.Connection.SSHPassphrase,
+				SSHHostKey:     chat.Connection.SSHHostKey,
+				SSHSkipHostKey: chat.Connection.SSHSkipHostKey,
 			})
 			if connectErr != nil {
 				log.Printf("ChatService -> GetAllTables -> Failed to connect: %v", connectErr)
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/constants"
+	"neobase-ai/internal/models"
+	"neobase-ai/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// File headers used to check that an upload matches the chat's database type
+var (
+	sqliteFileHeader = []byte("SQLite format 3\x00")
+	duckDBFileMagic  = []byte("DUCK") // Stored at offset 8 of the file
+)
+
+// StoreDatabaseFile stores an uploaded SQLite/DuckDB file encrypted on disk and attaches it to the chat
+func (s *chatService) StoreDatabaseFile(userID, chatID, fileName string, data []byte) (*dtos.DatabaseFileUploadResponse, uint32, error) {
+	log.Printf("ChatService -> StoreDatabaseFile -> Starting for chatID: %s, file: %s, size: %d", chatID, fileName, len(data))
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid user ID format")
+	}
+
+	chatObjID, err := primitive.ObjectIDFromHex(chatID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid chat ID format")
+	}
+
+	chat, err := s.chatRepo.FindByID(chatObjID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, http.StatusNotFound, fmt.Errorf("chat not found")
+		}
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to fetch chat: %v", err)
+	}
+	if chat.UserID != userObjID {
+		return nil, http.StatusForbidden, fmt.Errorf("chat does not belong to user")
+	}
+
+	dbType := chat.Connection.Type
+	if !constants.IsFileDatabaseType(dbType) {
+		return nil, http.StatusBadRequest, fmt.Errorf("connection is not a SQLite or DuckDB type")
+	}
+
+	if err := validateDatabaseFile(dbType, data); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	// Close the current file first, so pending writes to it can't overwrite the new upload
+	if _, exists := s.dbManager.GetConnectionInfo(chatID); exists {
+		if err := s.dbManager.Disconnect(chatID, userID, true); err != nil {
+			log.Printf("ChatService -> StoreDatabaseFile -> Warning: Failed to disconnect existing connection: %v", err)
+		}
+	}
+
+	storagePath, err := utils.DatabaseFileStoragePath(chatID, dbType)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if err := utils.WriteEncryptedFile(storagePath, data); err != nil {
+		log.Printf("ChatService -> StoreDatabaseFile -> Error storing file: %v", err)
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to store database file: %v", err)
+	}
+
+	if err := setDatabaseFileLocation(&chat.Connection, storagePath, fileName); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to secure connection details: %v", err)
+	}
+
+	// Tables of the previous file don't apply to the new one
+	chat.SelectedCollections = ""
+
+	if err := s.chatRepo.Update(chatObjID, chat); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to update chat: %v", err)
+	}
+
+	log.Printf("ChatService -> StoreDatabaseFile -> Stored %s file for chatID: %s", dbType, chatID)
+
+	return &dtos.DatabaseFileUploadResponse{
+		Type:       dbType,
+		FileName:   fileName,
+		SizeBytes:  int64(len(data)),
+		UploadedAt: time.Now(),
+	}, http.StatusOK, nil
+}
+
+// validateDatabaseFile checks the file header against the expected database type
+func validateDatabaseFile(dbType string, data []byte) error {
+	switch dbType {
+	case constants.DatabaseTypeSQLite:
+		if !bytes.HasPrefix(data, sqliteFileHeader) {
+			return fmt.Errorf("file is not a valid SQLite database")
+		}
+	case constants.DatabaseTypeDuckDB:
+		if len(data) < 12 || !bytes.Equal(data[8:12], duckDBFileMagic) {
+			return fmt.Errorf("file is not a valid DuckDB database")
+		}
+	default:
+		return fmt.Errorf("unsupported database file type: %s", dbType)
+	}
+	return nil
+}
+
+// setDatabaseFileLocation stores the encrypted storage path and original file name on a connection
+func setDatabaseFileLocation(conn *models.Connection, storagePath, fileName string) error {
+	// Only the host and database are encrypted, so the other fields of conn are left untouched
+	location := models.Connection{Host: storagePath, Database: fileName}
+	if err := utils.EncryptConnection(&location); err != nil {
+		return err
+	}
+	conn.Host = location.Host
+	conn.Database = location.Database
+	return nil
+}
+
+// getDatabaseFileLocation returns the decrypted storage path and file name of a connection
+func getDatabaseFileLocation(conn *models.Connection) (string, string) {
+	location := models.Connection{Host: conn.Host, Database: conn.Database}
+	utils.DecryptConnection(&location)
+	return location.Host, location.Database
+}
+
+// copyDatabaseFile gives a duplicated chat its own copy of the stored database file
+func (s *chatService) copyDatabaseFile(conn *models.Connection, newChatID string) error {
+	storagePath, fileName := getDatabaseFileLocation(conn)
+	if storagePath == "" {
+		return nil
+	}
+
+	data, err := utils.ReadEncryptedFile(storagePath)
+	if err != nil {
+		return fmt.Errorf("failed to read database file: %v", err)
+	}
+
+	newStoragePath, err := utils.DatabaseFileStoragePath(newChatID, conn.Type)
+	if err != nil {
+		return err
+	}
+	if err := utils.WriteEncryptedFile(newStoragePath, data); err != nil {
+		return fmt.Errorf("failed to copy database file: %v", err)
+	}
+
+	return setDatabaseFileLocation(conn, newStoragePath, fileName)
+}
+
+// removeDatabaseFile deletes the stored database file of a chat
+func (s *chatService) removeDatabaseFile(chatID string, conn *models.Connection) {
+	storagePath, _ := getDatabaseFileLocation(conn)
+	if storagePath == "" {
+		return
+	}
+
+	// Only the chat's own file is removed
+	expectedPath, err := utils.DatabaseFileStoragePath(chatID, conn.Type)
+	if err != nil || storagePath != expectedPath {
+		return
+	}
+
+	if err := os.Remove(storagePath); err != nil && !os.IsNotExist(err) {
+		log.Printf("ChatService -> removeDatabaseFile -> Failed to remove database file for chatID %s: %v", chatID, err)
+	}
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/models"
+	"neobase-ai/internal/utils"
+	"neobase-ai/pkg/dbmanager"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Rows of the result of a query kept in the message, like ExecuteQuery
+const storedResultRows = 50
+
+// ExecuteAllQueries runs the queries of a message in order in a single transaction, committed only when all of them
+// succeed. The progress of each query is sent as execution-plan-step events.
+func (s *chatService) ExecuteAllQueries(ctx context.Context, userID, chatID string, req *dtos.ExecuteAllQueriesRequest) (*dtos.ExecuteAllQueriesResponse, uint32, error) {
+	chatObjID, err := primitive.ObjectIDFromHex(chatID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid chat ID format")
+	}
+	msgObjID, err := primitive.ObjectIDFromHex(req.MessageID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid message ID format")
+	}
+	chat, err := s.chatRepo.FindByID(chatObjID)
+	if err != nil || chat == nil {
+		return nil, http.StatusNotFound, fmt.Errorf("chat not found")
+	}
+	if chat.UserID.Hex() != userID {
+		return nil, http.StatusForbidden, fmt.Errorf("unauthorized access to chat")
+	}
+	msg, err := s.chatRepo.FindMessageByID(msgObjID)
+	if err != nil || msg == nil {
+		return nil, http.StatusNotFound, fmt.Errorf("message not found")
+	}
+	if msg.ChatID != chat.ID {
+		return nil, http.StatusForbidden, fmt.Errorf("message does not belong to this chat")
+	}
+	if msg.Queries == nil || len(*msg.Queries) == 0 {
+		return nil, http.StatusBadRequest, fmt.Errorf("the message has no queries")
+	}
+
+	steps := make([]dbmanager.ExecutionPlanStep, len(*msg.Queries))
+	queries := make([]string, len(*msg.Queries))
+	for i, query := range *msg.Queries {
+		// Running a write again would apply it twice
+		isWrite := query.IsCritical || dbmanager.CheckReadOnlyQuery(chat.Connection.Type, query.Query) != nil
+		if isWrite && query.IsExecuted && !query.IsRolledBack && query.Error == nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("query %d was already executed, roll it back before running all the queries", i+1)
+		}
+		queryType := ""
+		if query.QueryType != nil {
+			queryType = *query.QueryType
+		}
+		// Reads are capped by their paginated form, like in ExecuteQuery
+		queryToExecute := query.Query
+		if query.Pagination != nil && query.Pagination.PaginatedQuery != nil && *query.Pagination.PaginatedQuery != "" {
+			queryToExecute = strings.Replace(*query.Pagination.PaginatedQuery, "offset_size", "0", 1)
+		}
+		// The plan is checked on the text that runs
+		queries[i] = queryToExecute
+		steps[i] = dbmanager.ExecutionPlanStep{QueryID: query.ID.Hex(), Query: queryToExecute, QueryType: queryType}
+	}
+	// A plan is all or nothing, the plans the database couldn't fully undo are refused rather than partly applied
+	if err := dbmanager.CheckPlanAtomicity(chat.Connection.Type, queries); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	// Check connection status and connect if needed
+	if !s.dbManager.IsConnected(chatID) {
+		log.Printf("ChatService -> ExecuteAllQueries -> Database not connected, initiating connection")
+		status, err := s.ConnectDB(ctx, userID, chatID, req.StreamID)
+		if err != nil {
+			return nil, status, err
+		}
+		// Give a small delay for connection to stabilize
+		time.Sleep(1 * time.Second)
+	}
+
+	plan := s.dbManager.ExecutePlan(ctx, chatID, req.MessageID, req.StreamID, steps, func(step dbmanager.ExecutionPlanStepResult) {
+		event := dtos.ExecutionPlanStep{
+			ChatID:    chatID,
+			MessageID: req.MessageID,
+			QueryID:   step.QueryID,
+			Index:     step.Index,
+			Total:     len(steps),
+			Status:    step.Status,
+			Error:     step.Error,
+		}
+		if step.Result != nil {
+			event.ExecutionTime = &step.Result.ExecutionTime
+		}
+		s.sendStreamEvent(userID, chatID, req.StreamID, dtos.StreamResponse{
+			Event: "execution-plan-step",
+			Data:  event,
+		})
+	})
+
+	response := &dtos.ExecuteAllQueriesResponse{
+		ChatID:    chatID,
+		MessageID: req.MessageID,
+		Status:    "committed",
+		Steps:     make([]dtos.ExecutionPlanStep, 0, len(plan.Steps)),
+		Error:     plan.Error,
+	}
+	if !plan.Committed {
+		response.Status = "rolled_back"
+	}
+	log.Printf("ChatService -> ExecuteAllQueries -> Plan of %d queries %s after %d steps", len(steps), response.Status, len(plan.Steps))
+
+	// The steps that succeeded are only stored as executed when their writes were kept
+	keepSucceeded := plan.Committed || !plan.Atomic
+	actionAt := utils.ToStringPtr(time.Now().Format(time.RFC3339))
+	updated := map[primitive.ObjectID]bool{}
+	failed := false
+	for _, step := range plan.Steps {
+		stepResponse := dtos.ExecutionPlanStep{
+			ChatID:    chatID,
+			MessageID: req.MessageID,
+			QueryID:   step.QueryID,
+			Index:     step.Index,
+			Total:     len(steps),
+			Status:    step.Status,
+			Error:     step.Error,
+		}
+		query := &(*msg.Queries)[step.Index]
+		switch {
+		case step.Status == dbmanager.PlanStepFailed:
+			failed = true
+			query.IsExecuted = true
+			query.IsRolledBack = false
+			query.ExecutionTime = nil
+			query.Error = &models.QueryError{
+				Code:    step.Error.Code,
+				Message: step.Error.Message,
+				Details: step.Error.Details,
+			}
+			query.ActionAt = actionAt
+			updated[query.ID] = true
+		case keepSucceeded:
+			resultJSON, formattedResult := capQueryResult(step.Result.Result)
+			stepResponse.ExecutionTime = &step.Result.ExecutionTime
+			stepResponse.ExecutionResult = formattedResult
+
+			encryptedResult := s.encryptQueryResult(resultJSON)
+			executionTime := step.Result.ExecutionTime
+			query.IsExecuted = true
+			query.IsRolledBack = false
+			query.ExecutionTime = &executionTime
+			query.ExecutionResult = &encryptedResult
+			query.ResultHandle = nil
+			query.Error = nil
+			query.ActionAt = actionAt
+			query.RollbackSnapshot, query.RollbackSnapshotInfo = s.encryptQuerySnapshot(step.Result.Snapshot)
+			if query.RollbackSnapshot != nil {
+				query.CanRollback = true
+			}
+			updated[query.ID] = true
+		}
+		response.Steps = append(response.Steps, stepResponse)
+	}
+
+	// Add "Fix Error" action button to the Message & LLM content if a query failed
+	if failed {
+		s.addFixErrorButton(msg)
+	} else if plan.Committed {
+		s.removeFixErrorButton(msg)
+	}
+	response.ActionButtons = dtos.ToActionButtonDto(msg.ActionButtons)
+
+	if len(updated) > 0 {
+		if err := s.chatRepo.UpdateMessage(msg.ID, msg); err != nil {
+			log.Printf("ChatService -> ExecuteAllQueries -> Error updating message: %v", err)
+		}
+		go s.updateLLMMessageQueries(chat, msg, updated)
+	}
+	return response, http.StatusOK, nil
+}
+
+// capQueryResult returns the JSON of a result with at most storedResultRows rows, and the capped result itself
+func capQueryResult(result interface{}) (string, interface{}) {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("ChatService -> capQueryResult -> Error marshalling result: %v", err)
+		return "{}", nil
+	}
+
+	var formatted interface{}
+	if err := json.Unmarshal(resultJSON, &formatted); err != nil {
+		return string(resultJSON), result
+	}
+	switch value := formatted.(type) {
+	case []interface{}:
+		if len(value) <= storedResultRows {
+			return string(resultJSON), value
+		}
+		formatted = value[:storedResultRows]
+	case map[string]interface{}:
+		rows, ok := value["results"].([]interface{})
+		if !ok || len(rows) <= storedResultRows {
+			return string(resultJSON), value
+		}
+		formatted = map[string]interface{}{"results": rows[:storedResultRows]}
+	default:
+		return string(resultJSON), value
+	}
+
+	cappedJSON, err := json.Marshal(formatted)
+	if err != nil {
+		log.Printf("ChatService -> capQueryResult -> Error marshalling capped result: %v", err)
+		return string(resultJSON), formatted
+	}
+	return string(cappedJSON), formatted
+}
+
+// updateLLMMessageQueries copies the execution state of the given queries of a message to its LLM message, so the
+// LLM sees what ran in the next turns
+func (s *chatService) updateLLMMessageQueries(chat *models.Chat, msg *models.Message, queryIDs map[primitive.ObjectID]bool) {
+	llmMsg, err := s.llmRepo.FindMessageByChatMessageID(msg.ID)
+	if err != nil || llmMsg == nil {
+		log.Printf("ChatService -> updateLLMMessageQueries -> Error finding LLM message: %v", err)
+		return
+	}
+	assistantResponse, ok := llmMsg.Content["assistant_response"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	var llmQueries []interface{}
+	switch queriesVal := assistantResponse["queries"].(type) {
+	case primitive.A:
+		llmQueries = []interface{}(queriesVal)
+	case []interface{}:
+		llmQueries = queriesVal
+	default:
+		return
+	}
+
+	for _, query := range *msg.Queries {
+		if !queryIDs[query.ID] {
+			continue
+		}
+		for _, q := range llmQueries {
+			queryMap, ok := q.(map[string]interface{})
+			if !ok || queryMap["query"] != query.Query || query.QueryType == nil || queryMap["queryType"] != *query.QueryType || queryMap["explanation"] != query.Description {
+				continue
+			}
+			queryMap["isExecuted"] = query.IsExecuted
+			queryMap["isRolledBack"] = query.IsRolledBack
+			queryMap["executionTime"] = query.ExecutionTime
+			queryMap["actionAt"] = query.ActionAt
+			if query.Error != nil {
+				queryMap["error"] = map[string]interface{}{
+					"code":    query.Error.Code,
+					"message": query.Error.Message,
+					"details": query.Error.Details,
+				}
+				break
+			}
+			queryMap["error"] = nil
+			// If share data with AI is true, then we need to share the result with AI
+			if chat.Settings.ShareDataWithAI && query.ExecutionResult != nil {
+				queryMap["executionResult"] = map[string]interface{}{
+					"result": s.decryptQueryResult(*query.ExecutionResult),
+				}
+			} else {
+				queryMap["executionResult"] = map[string]interface{}{
+					"result": "Query executed successfully",
+				}
+			}
+			break
+		}
+	}
+
+	assistantResponse["queries"] = llmQueries
+	llmMsg.Content["assistant_response"] = assistantResponse
+	if err := s.llmRepo.UpdateMessage(llmMsg.ID, llmMsg); err != nil {
+		log.Printf("ChatService -> updateLLMMessageQueries -> Error updating LLM message: %v", err)
+	}
+}
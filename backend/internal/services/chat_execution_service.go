@@ -10,6 +10,7 @@ import (
 	"neobase-ai/internal/models"
 	"neobase-ai/internal/utils"
 	"neobase-ai/pkg/dbmanager"
+	"neobase-ai/pkg/llm"
 	"net/http"
 	"strconv"
 	"strings"
@@ -77,8 +78,8 @@ func (s *chatService) processLLMResponse(ctx context.Context, userID, chatID, us
 		return nil, fmt.Errorf("failed to fetch chat: %v", err)
 	}
 
-	log.Printf("ChatService -> Execute -> Chat settings: AutoExecuteQuery=%v, ShareDataWithAI=%v, NonTechMode=%v",
-		chat.Settings.AutoExecuteQuery, chat.Settings.ShareDataWithAI, chat.Settings.NonTechMode)
+	log.Printf("ChatService -> Execute -> Chat settings: AutoExecuteQuery=%v, ShareDataWithAI=%v, NonTechMode=%v, AgentMode=%v",
+		chat.Settings.AutoExecuteQuery, chat.Settings.ShareDataWithAI, chat.Settings.NonTechMode, chat.Settings.AgentMode)
 
 	// Get connection info
 	connInfo, exists := s.dbManager.GetConnectionInfo(chatID)
@@ -150,9 +151,43 @@ func (s *chatService) processLLMResponse(ctx context.Context, userID, chatID, us
 		return nil, fmt.Errorf("operation cancelled")
 	}
 
-	// Generate LLM response
-	response, err := s.llmClient.GenerateResponse(ctx, filteredMessages, connInfo.Config.Type, chat.Settings.NonTechMode)
+	// Send only the tables relevant to the request, then fit the history into the model's context,
+	// summarizing older turns if needed
+	llmClient := s.getLLMClient(chat)
+	filteredMessages = s.applyRelevantSchema(ctx, chat, filteredMessages)
+	filteredMessages = s.buildLLMContext(ctx, chat, llmClient, filteredMessages, connInfo.Config.Type)
+	if chat.Settings.ReadOnly {
+		filteredMessages = appendToUserMessage(filteredMessages, constants.ReadOnlyModePrompt)
+	}
+	if checkCancellation() {
+		return nil, fmt.Errorf("operation cancelled")
+	}
+
+	// In agent mode the LLM explores the database first, its findings are sent with the request
+	var agentSteps []llm.AgentStep
+	if chat.Settings.AgentMode {
+		agentSteps = s.runAgentExploration(ctx, chat, userID, streamID, !synchronous || allowSSEUpdates, llmClient, filteredMessages, connInfo.Config.Type)
+		if checkCancellation() {
+			return nil, fmt.Errorf("operation cancelled")
+		}
+		filteredMessages = withAgentFindings(filteredMessages, agentSteps)
+	}
+
+	// Generate LLM response, recording which provider answered
+	llmCtx, responseInfo := llm.WithResponseInfo(ctx)
+	var response string
+	if streamingClient, ok := llmClient.(llm.StreamingClient); ok && (!synchronous || allowSSEUpdates) {
+		// Stream the assistant message and the queries to the client while they are generated
+		response, err = streamingClient.GenerateResponseStream(llmCtx, filteredMessages, connInfo.Config.Type, chat.Settings.NonTechMode,
+			s.newResponseStreamHandler(ctx, userID, chatID, streamID, connInfo.Config.Type))
+	} else {
+		response, err = llmClient.GenerateResponse(llmCtx, filteredMessages, connInfo.Config.Type, chat.Settings.NonTechMode)
+	}
 	if err != nil {
+		// CancelProcessing already notified the client
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("operation cancelled")
+		}
 		if !synchronous || allowSSEUpdates {
 			s.sendStreamEvent(userID, chatID, streamID, dtos.StreamResponse{
 				Event: "ai-response-error",
@@ -308,6 +343,9 @@ func (s *chatService) processLLMResponse(ctx context.Context, userID, chatID, us
 				}
 			}
 
+			// The static analysis of the SQL decides if the query needs confirmation, not the LLM
+			analyzeQuerySafety(connInfo.Config.Type, &query)
+
 			queries = append(queries, query)
 		}
 	}
@@ -373,6 +411,7 @@ func (s *chatService) processLLMResponse(ctx context.Context, userID, chatID, us
 		existingMessage.Queries = queriesPtr // Now correctly typed as *[]models.Query
 		existingMessage.ActionButtons = actionButtonsPtr
 		existingMessage.IsEdited = true
+		existingMessage.LLMMetadata = buildLLMMetadata(llmClient, responseInfo)
 
 		// Update the message in the database
 		if err := s.chatRepo.UpdateMessage(existingMessage.ID, existingMessage); err != nil {
@@ -390,6 +429,9 @@ func (s *chatService) processLLMResponse(ctx context.Context, userID, chatID, us
 		formattedJsonResponse := map[string]interface{}{
 			"assistant_response": jsonResponse,
 		}
+		if len(agentSteps) > 0 {
+			formattedJsonResponse["agent_steps"] = agentSteps
+		}
 		existingLLMMsg.Content = formattedJsonResponse
 
 		if err := s.llmRepo.UpdateMessage(existingLLMMsg.ID, existingLLMMsg); err != nil {
@@ -409,6 +451,8 @@ func (s *chatService) processLLMResponse(ctx context.Context, userID, chatID, us
 					Queries:       dtos.ToQueryDtoWithDecryption(existingMessage.Queries, s.decryptQueryResult),
 					ActionButtons: dtos.ToActionButtonDto(existingMessage.ActionButtons),
 					Type:          existingMessage.Type,
+					NonTechMode:   existingMessage.NonTechMode,
+					LLMMetadata:   existingMessage.LLMMetadata,
 					CreatedAt:     existingMessage.CreatedAt.Format(time.RFC3339),
 					UpdatedAt:     existingMessage.UpdatedAt.Format(time.RFC3339),
 					IsEdited:      existingMessage.IsEdited,
@@ -425,6 +469,7 @@ func (s *chatService) processLLMResponse(ctx context.Context, userID, chatID, us
 			ActionButtons: dtos.ToActionButtonDto(existingMessage.ActionButtons),
 			Type:          existingMessage.Type,
 			NonTechMode:   existingMessage.NonTechMode,
+			LLMMetadata:   existingMessage.LLMMetadata,
 			CreatedAt:     existingMessage.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:     existingMessage.UpdatedAt.Format(time.RFC3339),
 			IsEdited:      existingMessage.IsEdited,
@@ -446,6 +491,7 @@ func (s *chatService) processLLMResponse(ctx context.Context, userID, chatID, us
 		IsEdited:      false,
 		UserMessageId: &userMessageObjID,         // Set the user message ID that this AI message is responding to
 		NonTechMode:   chat.Settings.NonTechMode, // Store the non-tech mode setting with the message
+		LLMMetadata:   buildLLMMetadata(llmClient, responseInfo),
 	}
 
 	if err := s.chatRepo.CreateMessage(chatResponseMsg); err != nil {
@@ -456,6 +502,9 @@ func (s *chatService) processLLMResponse(ctx context.Context, userID, chatID, us
 	formattedJsonResponse := map[string]interface{}{
 		"assistant_response": jsonResponse,
 	}
+	if len(agentSteps) > 0 {
+		formattedJsonResponse["agent_steps"] = agentSteps
+	}
 	llmMsg := &models.LLMMessage{
 		Base:        models.NewBase(),
 		UserID:      userObjID,
@@ -482,6 +531,7 @@ func (s *chatService) processLLMResponse(ctx context.Context, userID, chatID, us
 				ActionButtons: dtos.ToActionButtonDto(chatResponseMsg.ActionButtons),
 				Type:          chatResponseMsg.Type,
 				NonTechMode:   chatResponseMsg.NonTechMode,
+				LLMMetadata:   chatResponseMsg.LLMMetadata,
 				CreatedAt:     chatResponseMsg.CreatedAt.Format(time.RFC3339),
 				UpdatedAt:     chatResponseMsg.UpdatedAt.Format(time.RFC3339),
 			},
@@ -496,11 +546,38 @@ func (s *chatService) processLLMResponse(ctx context.Context, userID, chatID, us
 		ActionButtons: dtos.ToActionButtonDto(chatResponseMsg.ActionButtons),
 		Type:          chatResponseMsg.Type,
 		NonTechMode:   chatResponseMsg.NonTechMode,
+		LLMMetadata:   chatResponseMsg.LLMMetadata,
 		CreatedAt:     chatResponseMsg.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:     chatResponseMsg.UpdatedAt.Format(time.RFC3339),
 	}, nil
 }
 
+// newResponseStreamHandler forwards the parts of the LLM response as ai-response-delta events until ctx is cancelled
+func (s *chatService) newResponseStreamHandler(ctx context.Context, userID, chatID, streamID, dbType string) llm.StreamHandler {
+	send := func(delta dtos.StreamDelta) {
+		if ctx.Err() != nil {
+			return
+		}
+		s.sendStreamEvent(userID, chatID, streamID, dtos.StreamResponse{
+			Event: "ai-response-delta",
+			Data:  delta,
+		})
+	}
+
+	return llm.StreamHandler{
+		OnMessageDelta: func(text string) {
+			send(dtos.StreamDelta{Type: "message", Text: text})
+		},
+		OnQuery: func(index int, query map[string]interface{}) {
+			analyzeStreamedQuerySafety(dbType, query)
+			send(dtos.StreamDelta{Type: "query", Index: index, Query: query})
+		},
+		OnRestart: func() {
+			send(dtos.StreamDelta{Type: "reset"})
+		},
+	}
+}
+
 // Cancels the ongoing LLM processing for the given streamID
 func (s *chatService) CancelProcessing(userID, chatID, streamID string) {
 	s.processesMu.Lock()
@@ -578,6 +655,11 @@ func (s *chatService) ConnectDB(ctx context.Context, userID, chatID string, stre
 	// Decrypt connection details
 	utils.DecryptConnection(&chat.Connection)
 
+	// File databases have no connection details until a file is uploaded
+	if constants.IsFileDatabaseType(chat.Connection.Type) && chat.Connection.Host == "" {
+		return http.StatusBadRequest, fmt.Errorf("no database file has been uploaded for this chat")
+	}
+
 	// Log connection details for debugging spreadsheet connections
 	if chat.Connection.Type == constants.DatabaseTypeSpreadsheet {
 		log.Printf("ChatService -> ConnectDB -> Spreadsheet connection after decrypt: Host=%s, Database=%s", chat.Connection.Host, chat.Connection.Database)
@@ -593,10 +675,18 @@ func (s *chatService) ConnectDB(ctx context.Context, userID, chatID string, stre
 			defaultPort = "5433"
 		case constants.DatabaseTypeMySQL:
 			defaultPort = "3306"
+		case constants.DatabaseTypeMSSQL:
+			defaultPort = "1433"
 		case constants.DatabaseTypeClickhouse:
 			defaultPort = "9000"
 		case constants.DatabaseTypeMongoDB:
 			defaultPort = "27017"
+		case constants.DatabaseTypeRedis:
+			defaultPort = "6379"
+		case constants.DatabaseTypeCassandra:
+			defaultPort = "9042"
+		case constants.DatabaseTypeNeo4j:
+			defaultPort = "7687"
 		}
 		chat.Connection.Port = &defaultPort
 	}
@@ -615,6 +705,15 @@ func (s *chatService) ConnectDB(ctx context.Context, userID, chatID string, stre
 		SSLCertURL:     chat.Connection.SSLCertURL,
 		SSLKeyURL:      chat.Connection.SSLKeyURL,
 		SSLRootCertURL: chat.Connection.SSLRootCertURL,
+		SSHEnabled:     chat.Connection.SSHEnabled,
+		SSHHost:        chat.Connection.SSHHost,
+		SSHPort:        chat.Connection.SSHPort,
+		SSHUsername:    chat.Connection.SSHUsername,
+		SSHPrivateKey:  chat.Connection.SSHPrivateKey,
+		SSHPassphrase:  chat.Connection.SSHPassphrase,
+		SSHHostKey:     chat.Connection.SSHHostKey,
+		SSHSkipHostKey: chat.Connection.SSHSkipHostKey,
+		ReadOnly:       chat.Settings.ReadOnly,
 	})
 
 	if err != nil {
@@ -848,13 +947,26 @@ func (s *chatService) ExecuteQuery(ctx context.Context, userID, chatID string, r
 
 	log.Printf("ChatService -> ExecuteQuery -> queryToExecute: %+v", queryToExecute)
 	// Execute query, we will be executing the pagination.paginatedQuery if it exists, else the query.Query
-	result, queryErr := s.dbManager.ExecuteQuery(ctx, chatID, req.MessageID, req.QueryID, req.StreamID, queryToExecute, *query.QueryType, false, false)
+	// Single read queries are streamed to the client in capped batches and only their preview is stored
+	var resultHandle *models.QueryResultHandle
+	runQuery := func(queryToExecute string) (*dbmanager.QueryExecutionResult, *dtos.QueryError) {
+		if dbmanager.CanStreamQuery(chat.Connection.Type, queryToExecute) {
+			result, handle, queryErr := s.streamQueryResult(ctx, userID, chat, req, queryToExecute)
+			if queryErr == nil || queryErr.Code != "STREAMING_NOT_SUPPORTED" {
+				resultHandle = handle
+				return result, queryErr
+			}
+		}
+		resultHandle = nil
+		return s.dbManager.ExecuteQuery(ctx, chatID, req.MessageID, req.QueryID, req.StreamID, queryToExecute, *query.QueryType, false, false)
+	}
+	result, queryErr := runQuery(queryToExecute)
 	if queryErr != nil {
 		// Checking if executed query was paginatedQuery, if so, let's try to execute it again with the original query
 		if query.Pagination != nil && query.Pagination.PaginatedQuery != nil && *query.Pagination.PaginatedQuery != "" && queryToExecute == strings.Replace(*query.Pagination.PaginatedQuery, "offset_size", strconv.Itoa(0), 1) {
 			log.Printf("ChatService -> ExecuteQuery -> query.Pagination.PaginatedQuery was executed but faced an error, will try to execute the original query")
 			queryToExecute = query.Query
-			result, queryErr = s.dbManager.ExecuteQuery(ctx, chatID, req.MessageID, req.QueryID, req.StreamID, queryToExecute, *query.QueryType, false, false)
+			result, queryErr = runQuery(queryToExecute)
 		}
 	}
 	if queryErr != nil {
@@ -1090,6 +1202,14 @@ func (s *chatService) ExecuteQuery(ctx context.Context, userID, chatID string, r
 	encryptedResult := s.encryptQueryResult(resultJSONStr)
 	query.ExecutionResult = &encryptedResult
 	query.ActionAt = utils.ToStringPtr(time.Now().Format(time.RFC3339))
+	query.ResultHandle = resultHandle
+	// The rows an UPDATE/DELETE changed let it be rolled back without a rollback query
+	rollbackSnapshot, rollbackSnapshotInfo := s.encryptQuerySnapshot(result.Snapshot)
+	query.RollbackSnapshot = rollbackSnapshot
+	query.RollbackSnapshotInfo = rollbackSnapshotInfo
+	if rollbackSnapshot != nil {
+		query.CanRollback = true
+	}
 	if totalRecordsCount != nil {
 		if query.Pagination == nil {
 			query.Pagination = &models.Pagination{}
@@ -1128,6 +1248,12 @@ func (s *chatService) ExecuteQuery(ctx context.Context, userID, chatID string, r
 					encryptedResult := s.encryptQueryResult(resultJSONStr)
 					(*msg.Queries)[i].ExecutionResult = &encryptedResult
 					log.Printf("ChatService -> ExecuteQuery -> ExecutionResult after update: %v", (*msg.Queries)[i].ExecutionResult)
+					(*msg.Queries)[i].ResultHandle = resultHandle
+					(*msg.Queries)[i].RollbackSnapshot = rollbackSnapshot
+					(*msg.Queries)[i].RollbackSnapshotInfo = rollbackSnapshotInfo
+					if rollbackSnapshot != nil {
+						(*msg.Queries)[i].CanRollback = true
+					}
 					if result.Error != nil {
 						(*msg.Queries)[i].Error = &models.QueryError{
 							Code:    result.Error.Code,
@@ -1311,6 +1437,7 @@ func (s *chatService) ExecuteQuery(ctx context.Context, userID, chatID string, r
 		TotalRecordsCount: totalRecordsCount,
 		ActionButtons:     dtos.ToActionButtonDto(msg.ActionButtons),
 		ActionAt:          query.ActionAt,
+		ResultHandle:      (*dtos.QueryResultHandle)(query.ResultHandle),
 	}, http.StatusOK, nil
 }
 
@@ -1343,8 +1470,16 @@ func (s *chatService) RollbackQuery(ctx context.Context, userID, chatID string,
 	if !query.CanRollback {
 		return nil, http.StatusBadRequest, fmt.Errorf("query cannot be rolled back")
 	}
+	// The rows saved when the query ran are written back rather than running the LLM's rollback query, unless asked otherwise
+	snapshot, err := s.decryptQuerySnapshot(query)
+	if err != nil {
+		log.Printf("ChatService -> RollbackQuery -> %v, falling back to the rollback query", err)
+	}
+	if req.UseSnapshot != nil && !*req.UseSnapshot {
+		snapshot = nil
+	}
 	// Check if we need to generate rollback query
-	if query.RollbackQuery == nil || *query.RollbackQuery == "" {
+	if snapshot == nil && (query.RollbackQuery == nil || *query.RollbackQuery == "") {
 		// First execute the dependent query to get context
 		if query.RollbackDependentQuery == nil {
 			return nil, http.StatusBadRequest, fmt.Errorf("rollback dependent query is required but not provided")
@@ -1490,7 +1625,9 @@ func (s *chatService) RollbackQuery(ctx context.Context, userID, chatID string,
 		copy(llmMessages, llmMsgs)
 
 		// Get rollback query from LLM
-		llmResponse, err := s.llmClient.GenerateResponse(
+		llmClient := s.getLLMClient(chat)
+		llmMessages = s.buildLLMContext(ctx, chat, llmClient, llmMessages, conn.Config.Type)
+		llmResponse, err := llmClient.GenerateResponse(
 			ctx,
 			llmMessages,               // Pass the LLM messages array
 			conn.Config.Type,          // Pass the database type
@@ -1629,7 +1766,7 @@ func (s *chatService) RollbackQuery(ctx context.Context, userID, chatID string,
 	}
 
 	// Now execute the rollback query
-	if query.RollbackQuery == nil || *query.RollbackQuery == "" {
+	if snapshot == nil && (query.RollbackQuery == nil || *query.RollbackQuery == "") {
 		// Send event about rollback query failure
 		s.sendStreamEvent(userID, chatID, req.StreamID, dtos.StreamResponse{
 			Event: "rollback-query-failed",
@@ -1654,7 +1791,14 @@ func (s *chatService) RollbackQuery(ctx context.Context, userID, chatID string,
 	}
 
 	// Execute rollback query
-	result, queryErr := s.dbManager.ExecuteQuery(ctx, chatID, req.MessageID, req.QueryID, req.StreamID, *query.RollbackQuery, *query.QueryType, true, false)
+	var result *dbmanager.QueryExecutionResult
+	var queryErr *dtos.QueryError
+	if snapshot != nil {
+		log.Printf("ChatService -> RollbackQuery -> Restoring the snapshot of %d row(s) of %s", snapshot.RowCount, snapshot.Table)
+		result, queryErr = s.dbManager.ExecuteSnapshotRollback(ctx, chatID, req.MessageID, req.QueryID, req.StreamID, snapshot)
+	} else {
+		result, queryErr = s.dbManager.ExecuteQuery(ctx, chatID, req.MessageID, req.QueryID, req.StreamID, *query.RollbackQuery, *query.QueryType, true, false)
+	}
 	if queryErr != nil {
 		log.Printf("ChatService -> RollbackQuery -> queryErr: %+v", queryErr)
 		if queryErr.Code == "FAILED_TO_START_TRANSACTION" || strings.Contains(queryErr.Message, "context deadline exceeded") || strings.Contains(queryErr.Message, "context canceled") {
@@ -2041,6 +2185,14 @@ func (s *chatService) processLLMResponseAndRunQuery(ctx context.Context, userID,
 							QueryID:   query.ID,
 							StreamID:  streamID,
 						})
+						// Failed read-only queries are corrected by the LLM and run again
+						if queryErr == nil && executionResult.Error != nil {
+							fixCtx, fixCancel := context.WithTimeout(msgCtx, 2*time.Minute)
+							var fixedQuery *models.Query
+							executionResult, fixedQuery, queryErr = s.autoFixQuery(fixCtx, userID, chatID, msgResp.ID, query.ID, streamID, executionResult)
+							fixCancel()
+							query = withQueryFix(query, fixedQuery)
+						}
 						if queryErr != nil {
 							log.Printf("Error executing query: %v", queryErr)
 							// Send existing msgResp so far
@@ -2308,7 +2460,7 @@ func (s *chatService) GetQueryResults(ctx context.Context, userID, chatID, messa
 	result, queryErr := s.dbManager.ExecuteQuery(ctx, chatID, messageID, queryID, streamID, offSettPaginatedQuery, *query.QueryType, false, false)
 	if queryErr != nil {
 		log.Printf("ChatService -> GetQueryResults -> queryErr: %+v", queryErr)
-		return nil, http.StatusBadRequest, fmt.Errorf(queryErr.Message)
+		return nil, http.StatusBadRequest, fmt.Errorf("%s", queryErr.Message)
 	}
 
 	// Convert Result to JSON string
@@ -2514,7 +2666,7 @@ func (s *chatService) GetQueryRecommendations(ctx context.Context, userID, chatI
 		return nil, http.StatusBadRequest, fmt.Errorf("invalid chat ID format")
 	}
 
-	_, err = s.chatRepo.FindByID(chatObjID)
+	chat, err := s.chatRepo.FindByID(chatObjID)
 	if err != nil {
 		return nil, http.StatusInternalServerError, fmt.Errorf("failed to fetch chat: %v", err)
 	}
@@ -2527,11 +2679,12 @@ func (s *chatService) GetQueryRecommendations(ctx context.Context, userID, chatI
 		llmMessages = []*models.LLMMessage{}
 	}
 
-	// Use the existing conversation context directly
-	contextMessages := llmMessages
+	// Use the existing conversation context, fitted to the model's context limit
+	llmClient := s.getLLMClient(chat)
+	contextMessages := s.buildLLMContext(ctx, chat, llmClient, llmMessages, connInfo.Config.Type)
 
 	// Generate recommendations using LLM
-	response, err := s.llmClient.GenerateRecommendations(ctx, contextMessages, connInfo.Config.Type)
+	response, err := llmClient.GenerateRecommendations(ctx, contextMessages, connInfo.Config.Type)
 	if err != nil {
 		return nil, http.StatusInternalServerError, fmt.Errorf("failed to generate recommendations: %v", err)
 	}
@@ -2601,6 +2754,12 @@ func (s *chatService) getFallbackRecommendations(dbType string) []dtos.QueryReco
 			{Text: "Show me the structure of the main tables"},
 			{Text: "What processes are currently running?"},
 		}
+	case constants.DatabaseTypeMSSQL:
+		return []dtos.QueryRecommendation{
+			{Text: "List all tables in this database with their schemas"},
+			{Text: "Show me the row counts of the main tables"},
+			{Text: "Which tables have foreign keys to each other?"},
+		}
 	case constants.DatabaseTypeMongoDB:
 		return []dtos.QueryRecommendation{
 			{Text: "What collections are available in this database?"},
@@ -2613,6 +2772,36 @@ func (s *chatService) getFallbackRecommendations(dbType string) []dtos.QueryReco
 			{Text: "What are the recent query performance metrics?"},
 			{Text: "Which tables have the most data?"},
 		}
+	case constants.DatabaseTypeRedis:
+		return []dtos.QueryRecommendation{
+			{Text: "What key patterns are stored in this database?"},
+			{Text: "How many keys are there in total?"},
+			{Text: "Show me the server memory usage"},
+		}
+	case constants.DatabaseTypeCassandra:
+		return []dtos.QueryRecommendation{
+			{Text: "What tables are in this keyspace?"},
+			{Text: "Show me the partition and clustering keys of the main tables"},
+			{Text: "Which tables are the largest by estimated size?"},
+		}
+	case constants.DatabaseTypeNeo4j:
+		return []dtos.QueryRecommendation{
+			{Text: "What node labels and relationship types are in this graph?"},
+			{Text: "How many nodes are there for each label?"},
+			{Text: "Which nodes have the most relationships?"},
+		}
+	case constants.DatabaseTypeSQLite:
+		return []dtos.QueryRecommendation{
+			{Text: "List all tables in this database"},
+			{Text: "Show me the row counts of the main tables"},
+			{Text: "Which columns are indexed?"},
+		}
+	case constants.DatabaseTypeDuckDB:
+		return []dtos.QueryRecommendation{
+			{Text: "Summarize the columns of the main tables"},
+			{Text: "Show me all tables with their row counts"},
+			{Text: "Which tables have the most data?"},
+		}
 	default:
 		return []dtos.QueryRecommendation{
 			{Text: "Test the database connection"},
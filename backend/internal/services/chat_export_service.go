@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"neobase-ai/config"
+	"neobase-ai/internal/constants"
+	"neobase-ai/internal/utils"
+	"neobase-ai/pkg/dbmanager"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QueryResultExport is the file export of the result of a query, Write runs the query and writes the file
+type QueryResultExport struct {
+	FileName    string
+	ContentType string
+	Write       func(w io.Writer) error
+}
+
+// ExportQueryResult prepares the export of the result of a read query as a csv, jsonl, xlsx or parquet file. The query
+// is run again page by page with its paginated form, or streamed when it has none, so the file is written as the rows
+// are read and never held in memory.
+func (s *chatService) ExportQueryResult(ctx context.Context, userID, chatID, queryID, format, streamID string) (*QueryResultExport, uint32, error) {
+	contentType := utils.ExportContentType(format)
+	if contentType == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid format, must be one of csv, jsonl, xlsx or parquet")
+	}
+
+	chatObjID, err := primitive.ObjectIDFromHex(chatID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid chat ID format")
+	}
+	queryObjID, err := primitive.ObjectIDFromHex(queryID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid query ID format")
+	}
+	msg, err := s.chatRepo.FindMessageByQueryID(chatObjID, queryObjID)
+	if err != nil {
+		return nil, http.StatusNotFound, fmt.Errorf("query not found")
+	}
+	chat, _, query, err := s.verifyQueryOwnership(userID, chatID, msg.ID.Hex(), queryID)
+	if err != nil {
+		return nil, http.StatusForbidden, err
+	}
+	if chat.UserID.Hex() != userID {
+		return nil, http.StatusForbidden, fmt.Errorf("unauthorized access to chat")
+	}
+	// Exports run the query again, which is only safe for reads
+	if !dbmanager.CanStreamQuery(chat.Connection.Type, query.Query) {
+		return nil, http.StatusBadRequest, fmt.Errorf("only the results of single read queries can be exported")
+	}
+
+	if !s.dbManager.IsConnected(chatID) {
+		log.Printf("ChatService -> ExportQueryResult -> Database not connected, initiating connection")
+		status, err := s.ConnectDB(ctx, userID, chatID, streamID)
+		if err != nil {
+			return nil, status, err
+		}
+		// Give a small delay for connection to stabilize
+		time.Sleep(1 * time.Second)
+	}
+
+	// Pages are read until one comes back empty, so the paginated query must take the offset
+	paginatedQuery := ""
+	if query.Pagination != nil && query.Pagination.PaginatedQuery != nil && strings.Contains(*query.Pagination.PaginatedQuery, "offset_size") {
+		paginatedQuery = *query.Pagination.PaginatedQuery
+		if !dbmanager.CanStreamQuery(chat.Connection.Type, strings.Replace(paginatedQuery, "offset_size", "0", 1)) {
+			paginatedQuery = ""
+		}
+	}
+
+	// Queries stored before the query type was recorded have none
+	queryType := ""
+	if query.QueryType != nil {
+		queryType = *query.QueryType
+	} else if analysis, ok := dbmanager.AnalyzeSQL(chat.Connection.Type, query.Query); ok && len(analysis.Statements) == 1 {
+		queryType = analysis.Statements[0].Command
+	}
+
+	messageID := msg.ID.Hex()
+	exportID := "export-" + primitive.NewObjectID().Hex()
+	maxRows := int64(config.Env.QueryExportMaxRows)
+	read := func(onRows func(columns []string, rows []map[string]interface{}) error) (int64, error) {
+		if paginatedQuery != "" {
+			return s.exportPages(ctx, chatID, messageID, queryID, exportID, queryType, paginatedQuery, maxRows, onRows)
+		}
+		return s.exportStream(ctx, chatID, messageID, queryID, exportID, query.Query, maxRows, onRows)
+	}
+	// Documents don't share the same fields, the columns of tabular files are scanned before the rows are written
+	scanColumns := chat.Connection.Type == constants.DatabaseTypeMongoDB && format != utils.ExportFormatJSONL
+	return &QueryResultExport{
+		FileName:    fmt.Sprintf("query-%s.%s", queryID, format),
+		ContentType: contentType,
+		Write: func(w io.Writer) error {
+			return writeExport(queryID, format, scanColumns, read, w)
+		},
+	}, http.StatusOK, nil
+}
+
+// writeExport writes the rows read by read to w, reading them twice when their columns must be scanned first
+func writeExport(queryID, format string, scanColumns bool, read func(onRows func(columns []string, rows []map[string]interface{}) error) (int64, error), w io.Writer) error {
+	var documentColumns *utils.ResultColumns
+	if scanColumns {
+		documentColumns = &utils.ResultColumns{}
+		if _, err := read(func(_ []string, rows []map[string]interface{}) error {
+			return documentColumns.Add(rows)
+		}); err != nil {
+			return err
+		}
+	}
+
+	var writer utils.ResultWriter
+	newWriter := func(columns []string) error {
+		var err error
+		if documentColumns != nil {
+			writer, err = utils.NewDocumentResultWriter(format, w, documentColumns.Columns())
+		} else {
+			writer, err = utils.NewResultWriter(format, w, columns)
+		}
+		return err
+	}
+	written, err := read(func(columns []string, rows []map[string]interface{}) error {
+		if writer == nil {
+			if err := newWriter(columns); err != nil {
+				return err
+			}
+		}
+		return writer.WriteRows(rows)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Empty results still get a file, e.g. with the CSV header
+	if writer == nil {
+		if err := newWriter(nil); err != nil {
+			return err
+		}
+	}
+	log.Printf("ChatService -> writeExport -> Exported %d rows of query %s as %s", written, queryID, format)
+	return writer.Close()
+}
+
+// exportPages reads the pages of a paginated query, from offset 0 until a page is empty or maxRows rows were read
+func (s *chatService) exportPages(ctx context.Context, chatID, messageID, queryID, exportID, queryType, paginatedQuery string, maxRows int64, onRows func(columns []string, rows []map[string]interface{}) error) (int64, error) {
+	var written int64
+	for maxRows <= 0 || written < maxRows {
+		if err := ctx.Err(); err != nil {
+			return written, fmt.Errorf("export cancelled: %v", err)
+		}
+		pageQuery := strings.Replace(paginatedQuery, "offset_size", strconv.FormatInt(written, 10), 1)
+		result, queryErr := s.dbManager.ExecuteQuery(ctx, chatID, messageID, queryID, exportID, pageQuery, queryType, false, false)
+		if queryErr != nil {
+			return written, fmt.Errorf("failed to read page at offset %d: %s", written, queryErr.Message)
+		}
+		rows, err := dbmanager.ResultRows(result)
+		if err != nil {
+			return written, fmt.Errorf("failed to read page at offset %d: %v", written, err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+		if maxRows > 0 && written+int64(len(rows)) > maxRows {
+			rows = rows[:maxRows-written]
+		}
+		if err := onRows(nil, rows); err != nil {
+			return written, err
+		}
+		written += int64(len(rows))
+	}
+	return written, nil
+}
+
+// exportStream reads the rows of a query without a paginated form as it streams them. An empty result is passed
+// without rows so its columns are known.
+func (s *chatService) exportStream(ctx context.Context, chatID, messageID, queryID, exportID, query string, maxRows int64, onRows func(columns []string, rows []map[string]interface{}) error) (int64, error) {
+	batches := 0
+	limits := dbmanager.ResultLimits{BatchSize: config.Env.QueryResultBatchSize, MaxRows: maxRows}
+	streamed, queryErr := s.dbManager.StreamQuery(ctx, chatID, messageID, queryID, exportID, query, limits, func(columns []string, batch []map[string]interface{}) error {
+		batches++
+		return onRows(columns, batch)
+	})
+	if queryErr != nil {
+		return 0, fmt.Errorf("failed to read query result: %s", queryErr.Message)
+	}
+	if batches == 0 {
+		if err := onRows(streamed.Columns, nil); err != nil {
+			return 0, err
+		}
+	}
+	if streamed.Truncated {
+		log.Printf("ChatService -> exportStream -> Export of query %s stopped after %d rows", queryID, streamed.RowCount)
+	}
+	return streamed.RowCount, nil
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"neobase-ai/config"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/constants"
+	"neobase-ai/internal/models"
+	"neobase-ai/internal/utils"
+	"neobase-ai/pkg/dbmanager"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Query types that are auto-fixed when their execution fails, the query itself is checked too
+var readOnlyQueryTypes = map[string]bool{
+	"SELECT": true, "WITH": true, "SHOW": true, "DESCRIBE": true, "EXPLAIN": true, "COUNT": true,
+	"FIND": true, "AGGREGATE": true, "MATCH": true,
+}
+
+// queryFix is the corrected query suggested by the LLM
+type queryFix struct {
+	Query          string
+	PaginatedQuery *string
+	CountQuery     *string
+	Explanation    string
+}
+
+// autoFixQuery sends a failed read-only query and its error to the LLM and runs the corrected query instead,
+// up to QueryAutoFixAttempts times. Returns the last execution result and the query as stored after the fixes,
+// the query is nil when it wasn't fixed.
+func (s *chatService) autoFixQuery(ctx context.Context, userID, chatID, messageID, queryID, streamID string, result *dtos.QueryExecutionResponse) (*dtos.QueryExecutionResponse, *models.Query, error) {
+	var fixedQuery *models.Query
+	for attempt := 1; attempt <= config.Env.QueryAutoFixAttempts && result != nil && result.Error != nil; attempt++ {
+		chat, msg, query, err := s.verifyQueryOwnership(userID, chatID, messageID, queryID)
+		if err != nil {
+			return result, fixedQuery, nil
+		}
+		if !canAutoFixQuery(chat.Connection.Type, query) {
+			log.Printf("ChatService -> autoFixQuery -> Query %s is not read-only, leaving it to the user", queryID)
+			return result, fixedQuery, nil
+		}
+
+		s.sendStreamEvent(userID, chatID, streamID, dtos.StreamResponse{
+			Event: "ai-response-step",
+			Data:  fmt.Sprintf("The query failed, fixing it (attempt %d of %d)..", attempt, config.Env.QueryAutoFixAttempts),
+		})
+
+		fix, err := s.generateQueryFix(ctx, chat, msg, query, result.Error)
+		if err != nil {
+			log.Printf("ChatService -> autoFixQuery -> Error generating fix for query %s: %v", queryID, err)
+			return result, fixedQuery, nil
+		}
+
+		fixedQuery, err = s.applyQueryFix(msg, query, fix, result.Error)
+		if err != nil {
+			log.Printf("ChatService -> autoFixQuery -> Error applying fix for query %s: %v", queryID, err)
+			return result, fixedQuery, nil
+		}
+		log.Printf("ChatService -> autoFixQuery -> Running fixed query %s: %s", queryID, fix.Query)
+
+		result, _, err = s.ExecuteQuery(ctx, userID, chatID, &dtos.ExecuteQueryRequest{
+			MessageID: messageID,
+			QueryID:   queryID,
+			StreamID:  streamID,
+		})
+		if err != nil {
+			return nil, fixedQuery, err
+		}
+	}
+	return result, fixedQuery, nil
+}
+
+// canAutoFixQuery reports if a query only reads data, so a corrected version can run without the user's approval
+func canAutoFixQuery(dbType string, query *models.Query) bool {
+	if query.IsCritical || query.QueryType == nil || !readOnlyQueryTypes[strings.ToUpper(*query.QueryType)] {
+		return false
+	}
+	return dbmanager.CheckReadOnlyQuery(dbType, query.Query) == nil
+}
+
+// generateQueryFix asks the LLM to correct the query of an assistant message, with the chat history up to that message
+func (s *chatService) generateQueryFix(ctx context.Context, chat *models.Chat, msg *models.Message, query *models.Query, queryErr *dtos.QueryError) (*queryFix, error) {
+	messages, err := s.llmRepo.GetByChatID(chat.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch LLM messages: %v", err)
+	}
+
+	filteredMessages := make([]*models.LLMMessage, 0, len(messages)+1)
+	for _, llmMsg := range messages {
+		filteredMessages = append(filteredMessages, llmMsg)
+		if llmMsg.MessageID == msg.ID {
+			break
+		}
+	}
+
+	errorText := queryErr.Message
+	if queryErr.Details != "" {
+		errorText += " (" + queryErr.Details + ")"
+	}
+	filteredMessages = append(filteredMessages, &models.LLMMessage{
+		Base:    models.NewBase(),
+		ChatID:  chat.ID,
+		UserID:  chat.UserID,
+		Role:    string(constants.MessageTypeUser),
+		Content: map[string]interface{}{"user_message": fmt.Sprintf(constants.QueryFixRequest, query.Query, errorText)},
+	})
+
+	dbType := chat.Connection.Type
+	llmClient := s.getLLMClient(chat)
+	filteredMessages = s.applyRelevantSchema(ctx, chat, filteredMessages)
+	filteredMessages = s.buildLLMContext(ctx, chat, llmClient, filteredMessages, dbType)
+
+	response, err := llmClient.GenerateResponse(ctx, filteredMessages, dbType, chat.Settings.NonTechMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate LLM response: %v", err)
+	}
+
+	var jsonResponse map[string]interface{}
+	if err := json.Unmarshal([]byte(response), &jsonResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse LLM response: %v", err)
+	}
+	queries, ok := jsonResponse["queries"].([]interface{})
+	if !ok || len(queries) == 0 {
+		return nil, fmt.Errorf("no query in LLM response")
+	}
+	queryMap, ok := queries[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid query in LLM response")
+	}
+
+	fix := &queryFix{}
+	fix.Query, _ = queryMap["query"].(string)
+	fix.Explanation, _ = queryMap["explanation"].(string)
+	if pagination, ok := queryMap["pagination"].(map[string]interface{}); ok {
+		if paginatedQuery, ok := pagination["paginatedQuery"].(string); ok && paginatedQuery != "" {
+			fix.PaginatedQuery = utils.ToStringPtr(paginatedQuery)
+		}
+		if countQuery, ok := pagination["countQuery"].(string); ok && countQuery != "" {
+			fix.CountQuery = utils.ToStringPtr(countQuery)
+		}
+	}
+
+	if strings.TrimSpace(fix.Query) == "" {
+		return nil, fmt.Errorf("LLM returned an empty query")
+	}
+	if strings.TrimSpace(fix.Query) == strings.TrimSpace(query.Query) {
+		return nil, fmt.Errorf("LLM returned the same query")
+	}
+	if isCritical, _ := queryMap["isCritical"].(bool); isCritical {
+		return nil, fmt.Errorf("LLM returned a critical query")
+	}
+	if err := dbmanager.CheckReadOnlyQuery(dbType, fix.Query); err != nil {
+		return nil, fmt.Errorf("LLM returned a query that is not read-only: %v", err)
+	}
+	for _, extra := range []*string{fix.PaginatedQuery, fix.CountQuery} {
+		if extra != nil && dbmanager.CheckReadOnlyQuery(dbType, strings.Replace(*extra, "offset_size", "0", 1)) != nil {
+			return nil, fmt.Errorf("LLM returned a pagination query that is not read-only")
+		}
+	}
+	return fix, nil
+}
+
+// applyQueryFix replaces the failed query of the message and its LLM message with the fix, recording the failed attempt
+func (s *chatService) applyQueryFix(msg *models.Message, query *models.Query, fix *queryFix, queryErr *dtos.QueryError) (*models.Query, error) {
+	var fixedQuery *models.Query
+	for i := range *msg.Queries {
+		msgQuery := &(*msg.Queries)[i]
+		if msgQuery.ID != query.ID {
+			continue
+		}
+
+		if msgQuery.OriginalQuery == nil {
+			msgQuery.OriginalQuery = utils.ToStringPtr(msgQuery.Query)
+		}
+		msgQuery.FixAttempts = append(msgQuery.FixAttempts, models.QueryFixAttempt{
+			Query: msgQuery.Query,
+			Error: models.QueryError{
+				Code:    queryErr.Code,
+				Message: queryErr.Message,
+				Details: queryErr.Details,
+			},
+			Explanation: fix.Explanation,
+			AttemptedAt: time.Now().Format(time.RFC3339),
+		})
+
+		if msgQuery.Pagination != nil {
+			msgQuery.Pagination.PaginatedQuery = fixPaginationQuery(msgQuery.Pagination.PaginatedQuery, fix.PaginatedQuery, query.Query, fix.Query)
+			msgQuery.Pagination.CountQuery = fixPaginationQuery(msgQuery.Pagination.CountQuery, fix.CountQuery, query.Query, fix.Query)
+		}
+		msgQuery.Query = fix.Query
+		msgQuery.Error = nil
+		msgQuery.IsExecuted = false
+		fixedQuery = msgQuery
+		break
+	}
+	if fixedQuery == nil {
+		return nil, fmt.Errorf("query not found in message")
+	}
+
+	if err := s.chatRepo.UpdateMessage(msg.ID, msg); err != nil {
+		return nil, fmt.Errorf("failed to update message: %v", err)
+	}
+
+	// The LLM sees the fixed query in the history, ExecuteQuery also finds the query by its text
+	llmMsg, err := s.llmRepo.FindMessageByChatMessageID(msg.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find LLM message: %v", err)
+	}
+	if assistantResponse, ok := llmMsg.Content["assistant_response"].(map[string]interface{}); ok {
+		var queries []interface{}
+		switch queriesVal := assistantResponse["queries"].(type) {
+		case primitive.A:
+			queries = queriesVal
+		case []interface{}:
+			queries = queriesVal
+		}
+		for i, q := range queries {
+			queryMap, ok := q.(map[string]interface{})
+			if !ok || queryMap["query"] != query.Query || queryMap["queryType"] != *query.QueryType || queryMap["explanation"] != query.Description {
+				continue
+			}
+			queryMap["query"] = fix.Query
+			queryMap["error"] = nil
+			if pagination, ok := queryMap["pagination"].(map[string]interface{}); ok && fixedQuery.Pagination != nil {
+				for key, value := range map[string]*string{
+					"paginatedQuery": fixedQuery.Pagination.PaginatedQuery,
+					"countQuery":     fixedQuery.Pagination.CountQuery,
+				} {
+					if value != nil {
+						pagination[key] = *value
+					} else {
+						delete(pagination, key)
+					}
+				}
+			}
+			queries[i] = queryMap
+			break
+		}
+		if queries != nil {
+			assistantResponse["queries"] = queries
+		}
+	}
+
+	if err := s.llmRepo.UpdateMessage(llmMsg.ID, llmMsg); err != nil {
+		return nil, fmt.Errorf("failed to update LLM message: %v", err)
+	}
+	return fixedQuery, nil
+}
+
+// fixPaginationQuery returns the pagination query to use with the fixed query. The LLM's own is preferred, else the
+// failed query is replaced in the current one. Returns nil when neither applies, the query then runs unpaginated.
+func fixPaginationQuery(current *string, suggested *string, oldQuery string, newQuery string) *string {
+	if suggested != nil {
+		return suggested
+	}
+	if current == nil || !strings.Contains(*current, oldQuery) {
+		return nil
+	}
+	return utils.ToStringPtr(strings.Replace(*current, oldQuery, newQuery, 1))
+}
+
+// withQueryFix copies the fixed query and its attempt history to the response query
+func withQueryFix(query dtos.Query, fixedQuery *models.Query) dtos.Query {
+	if fixedQuery == nil {
+		return query
+	}
+	query.Query = fixedQuery.Query
+	query.OriginalQuery = fixedQuery.OriginalQuery
+	query.FixAttempts = dtos.ToQueryFixAttemptsDto(fixedQuery.FixAttempts)
+	return query
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"neobase-ai/config"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/constants"
+	"neobase-ai/internal/models"
+	"neobase-ai/pkg/dbmanager"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExplainQuery returns the plan of a query of a message without running it, unless ANALYZE is requested, along with
+// the indexes suggested by the LLM for the plan
+func (s *chatService) ExplainQuery(ctx context.Context, userID, chatID string, req *dtos.ExplainQueryRequest) (*dtos.ExplainQueryResponse, uint32, error) {
+	chat, _, query, err := s.verifyQueryOwnership(userID, chatID, req.MessageID, req.QueryID)
+	if err != nil {
+		return nil, http.StatusForbidden, err
+	}
+	if req.Analyze && !config.Env.QueryExplainAnalyze {
+		return nil, http.StatusBadRequest, fmt.Errorf("ANALYZE is disabled on this server, explain the query without it")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	// Check connection status and connect if needed
+	if !s.dbManager.IsConnected(chatID) {
+		log.Printf("ChatService -> ExplainQuery -> Database not connected, initiating connection")
+		status, err := s.ConnectDB(ctx, userID, chatID, req.StreamID)
+		if err != nil {
+			return nil, status, err
+		}
+		// Give a small delay for connection to stabilize
+		time.Sleep(1 * time.Second)
+	}
+
+	plan, err := s.dbManager.ExplainQuery(ctx, chatID, query.Query, req.Analyze)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	response := &dtos.ExplainQueryResponse{
+		ChatID:    chatID,
+		MessageID: req.MessageID,
+		QueryID:   req.QueryID,
+		Query:     query.Query,
+		Plan:      plan,
+	}
+
+	// The plan is still returned when the suggestions fail
+	suggestions, err := s.suggestIndexes(ctx, chat, query.Query, plan)
+	if err != nil {
+		log.Printf("ChatService -> ExplainQuery -> Error generating index suggestions: %v", err)
+	} else {
+		response.IndexSuggestions = suggestions
+	}
+	return response, http.StatusOK, nil
+}
+
+// suggestIndexes sends the plan and the stored indexes of the tables it reads to the LLM
+func (s *chatService) suggestIndexes(ctx context.Context, chat *models.Chat, query string, plan *dtos.QueryPlan) (string, error) {
+	relations := dbmanager.QueryPlanRelations(plan)
+	indexes, err := s.dbManager.GetSchemaManager().GetStoredIndexes(ctx, chat.ID.Hex(), relations)
+	if err != nil {
+		// The LLM is told the indexes are unknown rather than that there are none
+		log.Printf("ChatService -> suggestIndexes -> Error getting stored indexes: %v", err)
+	}
+
+	var indexText strings.Builder
+	for _, relation := range relations {
+		tableIndexes, exists := indexes[relation]
+		if !exists {
+			indexText.WriteString(fmt.Sprintf("%s: unknown\n", relation))
+			continue
+		}
+		if len(tableIndexes) == 0 {
+			indexText.WriteString(fmt.Sprintf("%s: none\n", relation))
+			continue
+		}
+		names := make([]string, 0, len(tableIndexes))
+		for name := range tableIndexes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			index := tableIndexes[name]
+			unique := ""
+			if index.IsUnique {
+				unique = " UNIQUE"
+			}
+			indexText.WriteString(fmt.Sprintf("%s: %s%s (%s)\n", relation, name, unique, strings.Join(index.Columns, ", ")))
+		}
+	}
+	if indexText.Len() == 0 {
+		indexText.WriteString("(no tables read)\n")
+	}
+
+	request := fmt.Sprintf(constants.QueryPlanSuggestionRequest, chat.Connection.Type, query, dbmanager.FormatQueryPlan(plan), indexText.String())
+	return s.getLLMClient(chat).GenerateText(ctx, constants.QueryPlanSuggestionPrompt, request)
+}
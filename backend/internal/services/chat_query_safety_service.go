@@ -0,0 +1,57 @@
+package services
+
+import (
+	"log"
+	"neobase-ai/internal/models"
+	"neobase-ai/pkg/dbmanager"
+	"slices"
+	"strings"
+)
+
+// analyzeQuerySafety overrides the LLM's IsCritical flag of a query with the static analysis of its SQL. The paginated
+// and count queries are analyzed too as they are the ones executed. Queries of unsupported databases keep the LLM's flag.
+func analyzeQuerySafety(dbType string, query *models.Query) {
+	statements := []string{query.Query}
+	if query.Pagination != nil {
+		if query.Pagination.PaginatedQuery != nil && *query.Pagination.PaginatedQuery != "" {
+			statements = append(statements, strings.Replace(*query.Pagination.PaginatedQuery, "offset_size", "0", 1))
+		}
+		if query.Pagination.CountQuery != nil && *query.Pagination.CountQuery != "" {
+			statements = append(statements, *query.Pagination.CountQuery)
+		}
+	}
+
+	isCritical := false
+	var warnings []string
+	for _, statement := range statements {
+		analysis, ok := dbmanager.AnalyzeSQL(dbType, statement)
+		if !ok {
+			return
+		}
+		isCritical = isCritical || analysis.IsCritical
+		for _, warning := range analysis.Warnings {
+			if !slices.Contains(warnings, warning) {
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+
+	if isCritical != query.IsCritical {
+		log.Printf("ChatService -> analyzeQuerySafety -> Overriding isCritical=%v of the LLM with %v for query: %s", query.IsCritical, isCritical, query.Query)
+	}
+	query.IsCritical = isCritical
+	query.SafetyWarnings = warnings
+}
+
+// analyzeStreamedQuerySafety flags a query of a streamed response as critical when the static analysis does.
+// The final response carries the full analysis.
+func analyzeStreamedQuerySafety(dbType string, queryMap map[string]interface{}) {
+	queryText, ok := queryMap["query"].(string)
+	if !ok {
+		return
+	}
+	if analysis, ok := dbmanager.AnalyzeSQL(dbType, queryText); ok {
+		isCritical, _ := queryMap["isCritical"].(bool)
+		queryMap["isCritical"] = isCritical || analysis.IsCritical
+	}
+}
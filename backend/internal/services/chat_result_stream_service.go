@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"log"
+	"neobase-ai/config"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/models"
+	"neobase-ai/pkg/dbmanager"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// resultLimits returns the caps of the streamed results of a chat, its settings override the server's
+func resultLimits(chat *models.Chat) dbmanager.ResultLimits {
+	limits := dbmanager.ResultLimits{
+		BatchSize:   config.Env.QueryResultBatchSize,
+		MaxRows:     int64(config.Env.QueryResultMaxRows),
+		MaxBytes:    int64(config.Env.QueryResultMaxBytes),
+		PreviewRows: config.Env.QueryResultPreviewRows,
+	}
+	if chat.Settings.MaxResultRows > 0 {
+		limits.MaxRows = int64(chat.Settings.MaxResultRows)
+	}
+	if chat.Settings.MaxResultBytes > 0 {
+		limits.MaxBytes = int64(chat.Settings.MaxResultBytes)
+	}
+	return limits
+}
+
+// streamQueryResult runs a read query batch by batch, sending the rows as query-result-chunk events. The returned
+// result only holds the preview of the rows, in the {"results": [...]} shape of ExecuteQuery, along with the handle
+// to store with the query.
+func (s *chatService) streamQueryResult(ctx context.Context, userID string, chat *models.Chat, req *dtos.ExecuteQueryRequest, query string) (*dbmanager.QueryExecutionResult, *models.QueryResultHandle, *dtos.QueryError) {
+	chatID := chat.ID.Hex()
+	handle := &models.QueryResultHandle{
+		ID:            primitive.NewObjectID().Hex(),
+		ExecutedQuery: query,
+	}
+
+	index := 0
+	var offset int64
+	streamed, queryErr := s.dbManager.StreamQuery(ctx, chatID, req.MessageID, req.QueryID, req.StreamID, query, resultLimits(chat), func(columns []string, batch []map[string]interface{}) error {
+		chunk := dtos.QueryResultChunk{
+			ChatID:    chatID,
+			MessageID: req.MessageID,
+			QueryID:   req.QueryID,
+			HandleID:  handle.ID,
+			Index:     index,
+			Offset:    offset,
+			Rows:      batch,
+		}
+		// The columns are only sent with the first batch
+		if index == 0 {
+			chunk.Columns = columns
+		}
+		s.sendStreamEvent(userID, chatID, req.StreamID, dtos.StreamResponse{
+			Event: "query-result-chunk",
+			Data:  chunk,
+		})
+		index++
+		offset += int64(len(batch))
+		return nil
+	})
+	if queryErr != nil {
+		return nil, nil, queryErr
+	}
+
+	log.Printf("ChatService -> streamQueryResult -> Streamed %d rows (%d bytes) in %d batches, truncated: %v", streamed.RowCount, streamed.ByteCount, index, streamed.Truncated)
+	handle.Columns = streamed.Columns
+	handle.RowCount = streamed.RowCount
+	handle.ByteCount = streamed.ByteCount
+	handle.PreviewRows = len(streamed.Preview)
+	handle.Truncated = streamed.Truncated
+	handle.TruncatedBy = streamed.TruncatedBy
+	handle.ExecutedAt = time.Now().Format(time.RFC3339)
+
+	preview := make([]interface{}, len(streamed.Preview))
+	for i, row := range streamed.Preview {
+		preview[i] = row
+	}
+	return &dbmanager.QueryExecutionResult{
+		Result:        map[string]interface{}{"results": preview},
+		ExecutionTime: streamed.ExecutionTime,
+	}, handle, nil
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"neobase-ai/internal/models"
+	"neobase-ai/pkg/dbmanager"
+	"time"
+)
+
+// encryptQuerySnapshot returns the encrypted snapshot to store with a query, along with its description
+func (s *chatService) encryptQuerySnapshot(snapshot *dbmanager.QuerySnapshot) (*string, *models.QuerySnapshotInfo) {
+	if snapshot == nil {
+		return nil, nil
+	}
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		log.Printf("ChatService -> encryptQuerySnapshot -> Failed to marshal snapshot: %v", err)
+		return nil, nil
+	}
+	encrypted := s.encryptQueryResult(string(snapshotJSON))
+	return &encrypted, &models.QuerySnapshotInfo{
+		Operation:  snapshot.Operation,
+		Table:      snapshot.Table,
+		RowCount:   snapshot.RowCount,
+		CapturedAt: snapshot.CapturedAt.Format(time.RFC3339),
+	}
+}
+
+// decryptQuerySnapshot returns the snapshot stored with a query, nil when it has none
+func (s *chatService) decryptQuerySnapshot(query *models.Query) (*dbmanager.QuerySnapshot, error) {
+	if query.RollbackSnapshot == nil || *query.RollbackSnapshot == "" {
+		return nil, nil
+	}
+	var snapshot dbmanager.QuerySnapshot
+	if err := json.Unmarshal([]byte(s.decryptQueryResult(*query.RollbackSnapshot)), &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to read the snapshot of the query: %v", err)
+	}
+	return &snapshot, nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"html"
 	"io/ioutil"
 	"log"
 	"neobase-ai/config"
@@ -18,9 +19,21 @@ type EmailService interface {
 	SendPasswordResetOTP(email, username, otp string) error
 	SendWelcomeEmail(email, username string) error
 	SendEnterpriseWaitlistEmail(email string) error
+	SendQueryAlertEmail(email, username string, alert *QueryAlertEmail) error
 	TestConnection() error
 }
 
+// QueryAlertEmail holds the details of an alert rule firing or resolving on a scheduled query
+type QueryAlertEmail struct {
+	Subject   string
+	Message   string
+	RuleName  string
+	QueryName string
+	Condition string
+	Actual    string
+	RanAt     string
+}
+
 type emailService struct {
 	smtpHost     string
 	smtpPort     int
@@ -182,6 +195,26 @@ func (s *emailService) SendEnterpriseWaitlistEmail(email string) error {
 	return s.SendEmail(email, subject, body)
 }
 
+func (s *emailService) SendQueryAlertEmail(email, username string, alert *QueryAlertEmail) error {
+	// The values come from the user and the database, they are escaped before being placed in the HTML
+	body, err := s.loadTemplate("query_alert", map[string]string{
+		"title":      html.EscapeString(alert.Subject),
+		"username":   html.EscapeString(username),
+		"message":    html.EscapeString(alert.Message),
+		"rule_name":  html.EscapeString(alert.RuleName),
+		"query_name": html.EscapeString(alert.QueryName),
+		"condition":  html.EscapeString(alert.Condition),
+		"actual":     html.EscapeString(alert.Actual),
+		"ran_at":     html.EscapeString(alert.RanAt),
+	})
+	if err != nil {
+		log.Printf("⚠️  Failed to load query alert template: %v", err)
+		return nil // Return nil to not block the application flow
+	}
+
+	return s.SendEmail(email, alert.Subject, body)
+}
+
 // loadTemplate loads an HTML template file and replaces placeholders with actual values
 func (s *emailService) loadTemplate(templateName string, placeholders map[string]string) (string, error) {
 	// Get current working directory for debugging
@@ -383,6 +416,32 @@ func (s *emailService) createFallbackTemplate(templateName string, placeholders
 	</div>
 </body>
 </html>`, baseStyles, username)
+	case "query_alert":
+		return fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+	<title>%s</title>
+	<style>%s</style>
+</head>
+<body>
+	<div class="container">
+		<div class="logo">NeoBase</div>
+		<h2>%s</h2>
+		<p>Hello <strong>%s</strong>,</p>
+		<p>%s</p>
+		<ul>
+			<li><strong>Rule:</strong> %s</li>
+			<li><strong>Query:</strong> %s</li>
+			<li><strong>Condition:</strong> %s</li>
+			<li><strong>Value:</strong> %s</li>
+			<li><strong>Checked at:</strong> %s</li>
+		</ul>
+		<p>Best regards,<br><strong>The NeoBase Team</strong></p>
+	</div>
+</body>
+</html>`, placeholders["title"], baseStyles, placeholders["title"], placeholders["username"], placeholders["message"],
+			placeholders["rule_name"], placeholders["query_name"], placeholders["condition"], placeholders["actual"], placeholders["ran_at"])
 	default:
 		return fmt.Sprintf(`
 <!DOCTYPE html>
@@ -0,0 +1,492 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/models"
+	"neobase-ai/internal/repositories"
+	"neobase-ai/internal/utils"
+	"neobase-ai/pkg/dbmanager"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Longest name given to a query of a message without one, taken from its description
+const queryNameLength = 100
+
+type SavedQueryService interface {
+	Create(userID string, req *dtos.CreateSavedQueryRequest) (*dtos.SavedQueryResponse, uint32, error)
+	SaveFromMessage(userID string, req *dtos.SaveMessageQueryRequest) (*dtos.SavedQueryResponse, uint32, error)
+	Update(userID, savedQueryID string, req *dtos.UpdateSavedQueryRequest) (*dtos.SavedQueryResponse, uint32, error)
+	Delete(userID, savedQueryID string) (uint32, error)
+	GetByID(userID, savedQueryID string) (*dtos.SavedQueryResponse, uint32, error)
+	List(userID string, filter dtos.SavedQueryFilter, page, pageSize int) (*dtos.SavedQueryListResponse, uint32, error)
+	Run(ctx context.Context, userID, savedQueryID string, req *dtos.RunSavedQueryRequest) (*dtos.RunSavedQueryResponse, uint32, error)
+}
+
+type savedQueryService struct {
+	savedQueryRepo repositories.SavedQueryRepository
+	chatRepo       repositories.ChatRepository
+	chatService    ChatService
+	dbManager      *dbmanager.Manager
+}
+
+func NewSavedQueryService(savedQueryRepo repositories.SavedQueryRepository, chatRepo repositories.ChatRepository, chatService ChatService, dbManager *dbmanager.Manager) SavedQueryService {
+	return &savedQueryService{
+		savedQueryRepo: savedQueryRepo,
+		chatRepo:       chatRepo,
+		chatService:    chatService,
+		dbManager:      dbManager,
+	}
+}
+
+func (s *savedQueryService) Create(userID string, req *dtos.CreateSavedQueryRequest) (*dtos.SavedQueryResponse, uint32, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid user ID format")
+	}
+	chat, status, err := findUserChat(s.chatRepo, userID, req.ChatID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	params, err := validateSavedQueryParams(chat.Connection.Type, req.Query, req.Params)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("name is required")
+	}
+
+	savedQuery := models.NewSavedQuery(userObjID, chat.ID, chat.Connection.Type, name, req.Description, normalizeTags(req.Tags), req.Query, req.QueryType, params)
+	if err := s.savedQueryRepo.Create(savedQuery); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to save query: %v", err)
+	}
+	return toSavedQueryResponse(savedQuery), http.StatusCreated, nil
+}
+
+// SaveFromMessage saves the query of a message to the library, for the connection of the message's chat
+func (s *savedQueryService) SaveFromMessage(userID string, req *dtos.SaveMessageQueryRequest) (*dtos.SavedQueryResponse, uint32, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid user ID format")
+	}
+	chat, status, err := findUserChat(s.chatRepo, userID, req.ChatID)
+	if err != nil {
+		return nil, status, err
+	}
+	msg, query, status, err := findChatMessageQuery(s.chatRepo, chat, req.MessageID, req.QueryID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	params, err := validateSavedQueryParams(chat.Connection.Type, query.Query, req.Params)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = queryNameFromDescription(query.Description)
+	}
+	if name == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("name is required")
+	}
+	description := req.Description
+	if description == "" {
+		description = query.Description
+	}
+
+	savedQuery := models.NewSavedQuery(userObjID, chat.ID, chat.Connection.Type, name, description, normalizeTags(req.Tags), query.Query, query.QueryType, params)
+	savedQuery.SourceMessageID = &msg.ID
+	savedQuery.SourceQueryID = &query.ID
+	if err := s.savedQueryRepo.Create(savedQuery); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to save query: %v", err)
+	}
+	return toSavedQueryResponse(savedQuery), http.StatusCreated, nil
+}
+
+func (s *savedQueryService) Update(userID, savedQueryID string, req *dtos.UpdateSavedQueryRequest) (*dtos.SavedQueryResponse, uint32, error) {
+	savedQuery, status, err := findUserSavedQuery(s.savedQueryRepo, userID, savedQueryID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	if req.ChatID != nil {
+		chat, status, err := findUserChat(s.chatRepo, userID, *req.ChatID)
+		if err != nil {
+			return nil, status, err
+		}
+		savedQuery.ChatID = chat.ID
+		savedQuery.ConnectionType = chat.Connection.Type
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, http.StatusBadRequest, fmt.Errorf("name is required")
+		}
+		savedQuery.Name = name
+	}
+	if req.Description != nil {
+		savedQuery.Description = *req.Description
+	}
+	if req.Tags != nil {
+		savedQuery.Tags = normalizeTags(*req.Tags)
+	}
+	if req.Query != nil {
+		if strings.TrimSpace(*req.Query) == "" {
+			return nil, http.StatusBadRequest, fmt.Errorf("query is required")
+		}
+		savedQuery.Query = *req.Query
+	}
+	if req.QueryType != nil {
+		savedQuery.QueryType = req.QueryType
+	}
+
+	// The parameters are checked again as the query or the connection type may have changed
+	params := toSavedQueryParamDtos(savedQuery.Params)
+	if req.Params != nil {
+		params = *req.Params
+	}
+	if savedQuery.Params, err = validateSavedQueryParams(savedQuery.ConnectionType, savedQuery.Query, params); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	if err := s.savedQueryRepo.Update(savedQuery.ID, savedQuery); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to update saved query: %v", err)
+	}
+	return toSavedQueryResponse(savedQuery), http.StatusOK, nil
+}
+
+func (s *savedQueryService) Delete(userID, savedQueryID string) (uint32, error) {
+	savedQuery, status, err := findUserSavedQuery(s.savedQueryRepo, userID, savedQueryID)
+	if err != nil {
+		return status, err
+	}
+	if err := s.savedQueryRepo.Delete(savedQuery.ID); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to delete saved query: %v", err)
+	}
+	return http.StatusOK, nil
+}
+
+func (s *savedQueryService) GetByID(userID, savedQueryID string) (*dtos.SavedQueryResponse, uint32, error) {
+	savedQuery, status, err := findUserSavedQuery(s.savedQueryRepo, userID, savedQueryID)
+	if err != nil {
+		return nil, status, err
+	}
+	return toSavedQueryResponse(savedQuery), http.StatusOK, nil
+}
+
+func (s *savedQueryService) List(userID string, filter dtos.SavedQueryFilter, page, pageSize int) (*dtos.SavedQueryListResponse, uint32, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid user ID format")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	repoFilter := repositories.SavedQueryFilter{
+		Tag:    strings.ToLower(strings.TrimSpace(filter.Tag)),
+		Search: strings.TrimSpace(filter.Search),
+	}
+	if filter.ChatID != "" {
+		if repoFilter.ChatID, err = primitive.ObjectIDFromHex(filter.ChatID); err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("invalid chat ID format")
+		}
+	}
+
+	savedQueries, total, err := s.savedQueryRepo.FindByUserID(userObjID, repoFilter, page, pageSize)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to fetch saved queries: %v", err)
+	}
+
+	response := &dtos.SavedQueryListResponse{
+		SavedQueries: make([]dtos.SavedQueryResponse, len(savedQueries)),
+		Total:        total,
+	}
+	for i, savedQuery := range savedQueries {
+		response.SavedQueries[i] = *toSavedQueryResponse(savedQuery)
+	}
+	return response, http.StatusOK, nil
+}
+
+// Run binds the parameters of a saved query and runs it against the connection of a chat, which must be of the type
+// the query was saved for
+func (s *savedQueryService) Run(ctx context.Context, userID, savedQueryID string, req *dtos.RunSavedQueryRequest) (*dtos.RunSavedQueryResponse, uint32, error) {
+	savedQuery, status, err := findUserSavedQuery(s.savedQueryRepo, userID, savedQueryID)
+	if err != nil {
+		return nil, status, err
+	}
+	chatID := req.ChatID
+	if chatID == "" {
+		chatID = savedQuery.ChatID.Hex()
+	}
+	chat, status, err := findUserChat(s.chatRepo, userID, chatID)
+	if err != nil {
+		return nil, status, err
+	}
+	boundQuery, err := bindSavedQuery(savedQuery, chat, req.Params)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	// Like the critical queries of a chat, the queries that may change data only run once the user confirmed them
+	if !req.Confirm {
+		if reason := savedQueryWriteReason(chat.Connection.Type, boundQuery); reason != "" {
+			return nil, http.StatusBadRequest, fmt.Errorf("the query may change data (%s), run it with confirm set to true to execute it", reason)
+		}
+	}
+
+	// Check connection status and connect if needed
+	if !s.dbManager.IsConnected(chatID) {
+		log.Printf("SavedQueryService -> Run -> Database not connected, initiating connection")
+		status, err := s.chatService.ConnectDB(ctx, userID, chatID, req.StreamID)
+		if err != nil {
+			return nil, status, err
+		}
+		// Give a small delay for connection to stabilize
+		time.Sleep(1 * time.Second)
+	}
+
+	queryType := ""
+	if savedQuery.QueryType != nil {
+		queryType = *savedQuery.QueryType
+	}
+	response := &dtos.RunSavedQueryResponse{
+		SavedQueryID: savedQueryID,
+		ChatID:       chatID,
+		Query:        boundQuery,
+	}
+	result, queryErr := s.dbManager.ExecuteQuery(ctx, chatID, "", savedQueryID, req.StreamID, boundQuery, queryType, false, false)
+	if queryErr != nil {
+		response.Error = queryErr
+	} else {
+		response.ExecutionTime = &result.ExecutionTime
+		_, response.ExecutionResult = capQueryResult(result.Result)
+		response.Error = result.Error
+	}
+
+	now := time.Now()
+	savedQuery.LastRunAt = &now
+	if err := s.savedQueryRepo.Update(savedQuery.ID, savedQuery); err != nil {
+		log.Printf("SavedQueryService -> Run -> Error updating last run time: %v", err)
+	}
+	return response, http.StatusOK, nil
+}
+
+// findUserChat returns a chat of the user
+func findUserChat(chatRepo repositories.ChatRepository, userID, chatID string) (*models.Chat, uint32, error) {
+	chatObjID, err := primitive.ObjectIDFromHex(chatID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid chat ID format")
+	}
+	chat, err := chatRepo.FindByID(chatObjID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to fetch chat: %v", err)
+	}
+	if chat == nil {
+		return nil, http.StatusNotFound, fmt.Errorf("chat not found")
+	}
+	if chat.UserID.Hex() != userID {
+		return nil, http.StatusForbidden, fmt.Errorf("unauthorized access to chat")
+	}
+	return chat, http.StatusOK, nil
+}
+
+// findChatMessageQuery returns a message of the chat and one of its queries
+func findChatMessageQuery(chatRepo repositories.ChatRepository, chat *models.Chat, messageID, queryID string) (*models.Message, *models.Query, uint32, error) {
+	msgObjID, err := primitive.ObjectIDFromHex(messageID)
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, fmt.Errorf("invalid message ID format")
+	}
+	queryObjID, err := primitive.ObjectIDFromHex(queryID)
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, fmt.Errorf("invalid query ID format")
+	}
+
+	msg, err := chatRepo.FindMessageByID(msgObjID)
+	if err != nil || msg == nil {
+		return nil, nil, http.StatusNotFound, fmt.Errorf("message not found")
+	}
+	if msg.ChatID != chat.ID {
+		return nil, nil, http.StatusForbidden, fmt.Errorf("message does not belong to this chat")
+	}
+	if msg.Queries != nil {
+		for i := range *msg.Queries {
+			if (*msg.Queries)[i].ID == queryObjID {
+				return msg, &(*msg.Queries)[i], http.StatusOK, nil
+			}
+		}
+	}
+	return nil, nil, http.StatusNotFound, fmt.Errorf("query not found")
+}
+
+// queryNameFromDescription names a query of a message after its description, truncated to queryNameLength runes
+func queryNameFromDescription(description string) string {
+	name := strings.TrimSpace(description)
+	if runes := []rune(name); len(runes) > queryNameLength {
+		name = strings.TrimSpace(string(runes[:queryNameLength])) + "..."
+	}
+	return name
+}
+
+// findUserSavedQuery returns a saved query of the user
+func findUserSavedQuery(savedQueryRepo repositories.SavedQueryRepository, userID, savedQueryID string) (*models.SavedQuery, uint32, error) {
+	savedQueryObjID, err := primitive.ObjectIDFromHex(savedQueryID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid saved query ID format")
+	}
+	savedQuery, err := savedQueryRepo.FindByID(savedQueryObjID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to fetch saved query: %v", err)
+	}
+	if savedQuery == nil || savedQuery.UserID.Hex() != userID {
+		return nil, http.StatusNotFound, fmt.Errorf("saved query not found")
+	}
+	return savedQuery, http.StatusOK, nil
+}
+
+// bindSavedQuery binds the parameters of a saved query for the connection of a chat
+func bindSavedQuery(savedQuery *models.SavedQuery, chat *models.Chat, params map[string]interface{}) (string, error) {
+	if chat.Connection.Type != savedQuery.ConnectionType {
+		return "", fmt.Errorf("the query was saved for %s, the chat is connected to %s", savedQuery.ConnectionType, chat.Connection.Type)
+	}
+	return dbmanager.BindQueryParams(chat.Connection.Type, savedQuery.Query, toQueryParams(savedQuery.Params), params)
+}
+
+// savedQueryWriteReason returns why a bound saved query needs the user's confirmation, empty when it only reads
+func savedQueryWriteReason(dbType, query string) string {
+	if analysis, ok := dbmanager.AnalyzeSQL(dbType, query); ok && analysis.IsCritical {
+		if len(analysis.Warnings) > 0 {
+			return strings.Join(analysis.Warnings, ", ")
+		}
+		return "critical query"
+	}
+	if err := dbmanager.CheckReadOnlyQuery(dbType, query); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// validateSavedQueryParams checks that every placeholder of the query has a parameter, that every parameter is used
+// and that the defaults can be bound to the database
+func validateSavedQueryParams(dbType, query string, params []dtos.SavedQueryParam) ([]models.SavedQueryParam, error) {
+	placeholders := map[string]bool{}
+	for _, name := range dbmanager.QueryPlaceholders(query) {
+		placeholders[name] = true
+	}
+	if err := dbmanager.CheckQueryPlaceholders(dbType, query); err != nil {
+		return nil, err
+	}
+
+	validated := make([]models.SavedQueryParam, 0, len(params))
+	declared := map[string]bool{}
+	for _, param := range params {
+		if !dbmanager.IsValidParamName(param.Name) {
+			return nil, fmt.Errorf("invalid parameter name %q, use letters, digits and underscores", param.Name)
+		}
+		if declared[param.Name] {
+			return nil, fmt.Errorf("parameter %s is declared twice", param.Name)
+		}
+		declared[param.Name] = true
+		if !placeholders[param.Name] {
+			return nil, fmt.Errorf("parameter %s is not used in the query, add {{%s}} where its value goes", param.Name, param.Name)
+		}
+		if !dbmanager.IsValidParamType(param.Type) {
+			return nil, fmt.Errorf("invalid type %q of parameter %s, must be one of string, integer, number, boolean, date or timestamp", param.Type, param.Name)
+		}
+		if param.Default != nil {
+			if err := dbmanager.CheckParamValue(dbType, param.Type, param.Default); err != nil {
+				return nil, fmt.Errorf("invalid default of parameter %s: %v", param.Name, err)
+			}
+		}
+		validated = append(validated, models.SavedQueryParam{
+			Name:        param.Name,
+			Type:        param.Type,
+			Required:    param.Required,
+			Default:     param.Default,
+			Description: param.Description,
+		})
+	}
+	for name := range placeholders {
+		if !declared[name] {
+			return nil, fmt.Errorf("placeholder {{%s}} has no parameter", name)
+		}
+	}
+	return validated, nil
+}
+
+// normalizeTags lowercases and trims the tags, dropping empty and repeated ones
+func normalizeTags(tags []string) []string {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+func toQueryParams(params []models.SavedQueryParam) []dbmanager.QueryParam {
+	queryParams := make([]dbmanager.QueryParam, len(params))
+	for i, param := range params {
+		queryParams[i] = dbmanager.QueryParam{
+			Name:     param.Name,
+			Type:     param.Type,
+			Required: param.Required,
+			Default:  param.Default,
+		}
+	}
+	return queryParams
+}
+
+func toSavedQueryParamDtos(params []models.SavedQueryParam) []dtos.SavedQueryParam {
+	paramDtos := make([]dtos.SavedQueryParam, len(params))
+	for i, param := range params {
+		paramDtos[i] = dtos.SavedQueryParam{
+			Name:        param.Name,
+			Type:        param.Type,
+			Required:    param.Required,
+			Default:     param.Default,
+			Description: param.Description,
+		}
+	}
+	return paramDtos
+}
+
+func toSavedQueryResponse(savedQuery *models.SavedQuery) *dtos.SavedQueryResponse {
+	response := &dtos.SavedQueryResponse{
+		ID:             savedQuery.ID.Hex(),
+		ChatID:         savedQuery.ChatID.Hex(),
+		ConnectionType: savedQuery.ConnectionType,
+		Name:           savedQuery.Name,
+		Description:    savedQuery.Description,
+		Tags:           savedQuery.Tags,
+		Query:          savedQuery.Query,
+		QueryType:      savedQuery.QueryType,
+		Params:         toSavedQueryParamDtos(savedQuery.Params),
+		CreatedAt:      savedQuery.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      savedQuery.UpdatedAt.Format(time.RFC3339),
+	}
+	if savedQuery.SourceMessageID != nil {
+		response.SourceMessageID = utils.ToStringPtr(savedQuery.SourceMessageID.Hex())
+	}
+	if savedQuery.SourceQueryID != nil {
+		response.SourceQueryID = utils.ToStringPtr(savedQuery.SourceQueryID.Hex())
+	}
+	if savedQuery.LastRunAt != nil {
+		response.LastRunAt = utils.ToStringPtr(savedQuery.LastRunAt.Format(time.RFC3339))
+	}
+	return response
+}
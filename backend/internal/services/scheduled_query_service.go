@@ -0,0 +1,653 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"neobase-ai/config"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/models"
+	"neobase-ai/internal/repositories"
+	"neobase-ai/internal/utils"
+	"neobase-ai/pkg/dbmanager"
+	"neobase-ai/pkg/redis"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	scheduledQueryCheckInterval = 30 * time.Second
+	scheduledQueryLockTTL       = 5 * time.Minute // Longer than a run, the lock is released when the run ends
+	scheduledQueryRunTimeout    = 2 * time.Minute
+	scheduledQueryMinInterval   = time.Minute
+	maxScheduledQueryRuns       = 5   // Runs at once on an instance, the other due queries wait for the next check
+	scheduledQuerySnapshotsKept = 500 // Latest snapshots kept for each scheduled query
+)
+
+// Statuses of the runs of scheduled queries
+const (
+	scheduledRunSucceeded = "succeeded"
+	scheduledRunFailed    = "failed"
+)
+
+type ScheduledQueryService interface {
+	Create(userID string, req *dtos.CreateScheduledQueryRequest) (*dtos.ScheduledQueryResponse, uint32, error)
+	Update(userID, scheduledQueryID string, req *dtos.UpdateScheduledQueryRequest) (*dtos.ScheduledQueryResponse, uint32, error)
+	Delete(userID, scheduledQueryID string) (uint32, error)
+	GetByID(userID, scheduledQueryID string) (*dtos.ScheduledQueryResponse, uint32, error)
+	List(userID string, page, pageSize int) (*dtos.ScheduledQueryListResponse, uint32, error)
+	ListSnapshots(userID, scheduledQueryID string, page, pageSize int) (*dtos.ScheduledQuerySnapshotListResponse, uint32, error)
+	StartScheduler()
+}
+
+type scheduledQueryService struct {
+	scheduledQueryRepo repositories.ScheduledQueryRepository
+	savedQueryRepo     repositories.SavedQueryRepository
+	chatRepo           repositories.ChatRepository
+	chatService        ChatService
+	alertService       AlertService
+	dbManager          *dbmanager.Manager
+	redisRepo          redis.IRedisRepositories
+	crypto             *utils.AESGCMCrypto
+	runSlots           chan struct{}
+	instanceID         string // Held in the run locks, to tell which instance runs a query
+}
+
+func NewScheduledQueryService(scheduledQueryRepo repositories.ScheduledQueryRepository, savedQueryRepo repositories.SavedQueryRepository, chatRepo repositories.ChatRepository, chatService ChatService, alertService AlertService, dbManager *dbmanager.Manager, redisRepo redis.IRedisRepositories) ScheduledQueryService {
+	// Initialize crypto instance
+	crypto, err := utils.NewFromConfig()
+	if err != nil {
+		log.Printf("ScheduledQueryService -> NewScheduledQueryService -> Failed to initialize crypto: %v", err)
+	}
+	hostname, _ := os.Hostname()
+
+	return &scheduledQueryService{
+		scheduledQueryRepo: scheduledQueryRepo,
+		savedQueryRepo:     savedQueryRepo,
+		chatRepo:           chatRepo,
+		chatService:        chatService,
+		alertService:       alertService,
+		dbManager:          dbManager,
+		redisRepo:          redisRepo,
+		crypto:             crypto,
+		runSlots:           make(chan struct{}, maxScheduledQueryRuns),
+		instanceID:         fmt.Sprintf("%s:%d", hostname, os.Getpid()),
+	}
+}
+
+// Create schedules a saved query, or the query of a message, against the connection of a chat. Only queries that
+// read data can be scheduled.
+func (s *scheduledQueryService) Create(userID string, req *dtos.CreateScheduledQueryRequest) (*dtos.ScheduledQueryResponse, uint32, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid user ID format")
+	}
+	chat, status, err := findUserChat(s.chatRepo, userID, req.ChatID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	thresholds, err := validateThresholds(req.Thresholds)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	cronExpression := strings.TrimSpace(req.CronExpression)
+	timezone := strings.TrimSpace(req.Timezone)
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	nextRunAt, err := nextScheduledRun(cronExpression, timezone, time.Now())
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	var scheduledQuery *models.ScheduledQuery
+	name := strings.TrimSpace(req.Name)
+	if req.SavedQueryID != "" {
+		savedQuery, status, err := findUserSavedQuery(s.savedQueryRepo, userID, req.SavedQueryID)
+		if err != nil {
+			return nil, status, err
+		}
+		query, err := bindSavedQuery(savedQuery, chat, req.Params)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		if name == "" {
+			name = savedQuery.Name
+		}
+		scheduledQuery = models.NewScheduledQuery(userObjID, chat.ID, name, query, savedQuery.QueryType, cronExpression, timezone, thresholds)
+		scheduledQuery.SavedQueryID = &savedQuery.ID
+		scheduledQuery.Params = req.Params
+	} else {
+		if req.MessageID == "" || req.QueryID == "" {
+			return nil, http.StatusBadRequest, fmt.Errorf("saved_query_id, or message_id and query_id, are required")
+		}
+		msg, query, status, err := findChatMessageQuery(s.chatRepo, chat, req.MessageID, req.QueryID)
+		if err != nil {
+			return nil, status, err
+		}
+		if name == "" {
+			name = queryNameFromDescription(query.Description)
+		}
+		scheduledQuery = models.NewScheduledQuery(userObjID, chat.ID, name, query.Query, query.QueryType, cronExpression, timezone, thresholds)
+		scheduledQuery.SourceMessageID = &msg.ID
+		scheduledQuery.SourceQueryID = &query.ID
+	}
+	if scheduledQuery.Name == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("name is required")
+	}
+	if err := dbmanager.CheckReadOnlyQuery(chat.Connection.Type, scheduledQuery.Query); err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("only queries that read data can be scheduled: %v", err)
+	}
+	scheduledQuery.NextRunAt = nextRunAt
+
+	if err := s.scheduledQueryRepo.Create(scheduledQuery); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to schedule query: %v", err)
+	}
+	return toScheduledQueryResponse(scheduledQuery), http.StatusCreated, nil
+}
+
+func (s *scheduledQueryService) Update(userID, scheduledQueryID string, req *dtos.UpdateScheduledQueryRequest) (*dtos.ScheduledQueryResponse, uint32, error) {
+	scheduledQuery, status, err := findUserScheduledQuery(s.scheduledQueryRepo, userID, scheduledQueryID)
+	if err != nil {
+		return nil, status, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, http.StatusBadRequest, fmt.Errorf("name is required")
+		}
+		scheduledQuery.Name = name
+	}
+	if req.Params != nil {
+		if scheduledQuery.SavedQueryID == nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("only scheduled saved queries have parameters")
+		}
+		chat, status, err := findUserChat(s.chatRepo, userID, scheduledQuery.ChatID.Hex())
+		if err != nil {
+			return nil, status, err
+		}
+		savedQuery, status, err := findUserSavedQuery(s.savedQueryRepo, userID, scheduledQuery.SavedQueryID.Hex())
+		if err != nil {
+			return nil, status, err
+		}
+		query, err := bindSavedQuery(savedQuery, chat, *req.Params)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		if err := dbmanager.CheckReadOnlyQuery(chat.Connection.Type, query); err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("only queries that read data can be scheduled: %v", err)
+		}
+		scheduledQuery.Query = query
+		scheduledQuery.Params = *req.Params
+	}
+	if req.CronExpression != nil {
+		scheduledQuery.CronExpression = strings.TrimSpace(*req.CronExpression)
+	}
+	if req.Timezone != nil {
+		scheduledQuery.Timezone = strings.TrimSpace(*req.Timezone)
+		if scheduledQuery.Timezone == "" {
+			scheduledQuery.Timezone = "UTC"
+		}
+	}
+	if req.Enabled != nil {
+		scheduledQuery.Enabled = *req.Enabled
+	}
+	if req.Thresholds != nil {
+		if scheduledQuery.Thresholds, err = validateThresholds(*req.Thresholds); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+	}
+
+	// The next run is computed again from now, so a query enabled again doesn't catch up the runs it missed
+	if scheduledQuery.NextRunAt, err = nextScheduledRun(scheduledQuery.CronExpression, scheduledQuery.Timezone, time.Now()); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	if err := s.scheduledQueryRepo.Update(scheduledQuery.ID, scheduledQuery); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to update scheduled query: %v", err)
+	}
+	return toScheduledQueryResponse(scheduledQuery), http.StatusOK, nil
+}
+
+func (s *scheduledQueryService) Delete(userID, scheduledQueryID string) (uint32, error) {
+	scheduledQuery, status, err := findUserScheduledQuery(s.scheduledQueryRepo, userID, scheduledQueryID)
+	if err != nil {
+		return status, err
+	}
+	if err := s.scheduledQueryRepo.Delete(scheduledQuery.ID); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to delete scheduled query: %v", err)
+	}
+	if err := s.alertService.DeleteScheduledQueryRules(scheduledQuery.ID); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to delete alert rules: %v", err)
+	}
+	return http.StatusOK, nil
+}
+
+func (s *scheduledQueryService) GetByID(userID, scheduledQueryID string) (*dtos.ScheduledQueryResponse, uint32, error) {
+	scheduledQuery, status, err := findUserScheduledQuery(s.scheduledQueryRepo, userID, scheduledQueryID)
+	if err != nil {
+		return nil, status, err
+	}
+	return toScheduledQueryResponse(scheduledQuery), http.StatusOK, nil
+}
+
+func (s *scheduledQueryService) List(userID string, page, pageSize int) (*dtos.ScheduledQueryListResponse, uint32, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid user ID format")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	scheduledQueries, total, err := s.scheduledQueryRepo.FindByUserID(userObjID, page, pageSize)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to fetch scheduled queries: %v", err)
+	}
+
+	response := &dtos.ScheduledQueryListResponse{
+		ScheduledQueries: make([]dtos.ScheduledQueryResponse, len(scheduledQueries)),
+		Total:            total,
+	}
+	for i, scheduledQuery := range scheduledQueries {
+		response.ScheduledQueries[i] = *toScheduledQueryResponse(scheduledQuery)
+	}
+	return response, http.StatusOK, nil
+}
+
+// ListSnapshots returns the snapshots of a scheduled query, the latest first
+func (s *scheduledQueryService) ListSnapshots(userID, scheduledQueryID string, page, pageSize int) (*dtos.ScheduledQuerySnapshotListResponse, uint32, error) {
+	scheduledQuery, status, err := findUserScheduledQuery(s.scheduledQueryRepo, userID, scheduledQueryID)
+	if err != nil {
+		return nil, status, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	snapshots, total, err := s.scheduledQueryRepo.FindSnapshots(scheduledQuery.ID, page, pageSize)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to fetch snapshots: %v", err)
+	}
+
+	response := &dtos.ScheduledQuerySnapshotListResponse{
+		Snapshots: make([]dtos.ScheduledQuerySnapshotResponse, len(snapshots)),
+		Total:     total,
+	}
+	for i, snapshot := range snapshots {
+		response.Snapshots[i] = s.toSnapshotResponse(snapshot)
+	}
+	return response, http.StatusOK, nil
+}
+
+// StartScheduler checks for due scheduled queries in the background. The schedules are stored in MongoDB, so they
+// survive restarts, and each run is claimed under a Redis lock so a single instance runs it.
+func (s *scheduledQueryService) StartScheduler() {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("ScheduledQueryService -> StartScheduler -> Scheduler panic recovered: %v", r)
+				// Restart the scheduler
+				s.StartScheduler()
+			}
+		}()
+
+		ticker := time.NewTicker(scheduledQueryCheckInterval)
+		defer ticker.Stop()
+
+		log.Printf("ScheduledQueryService -> StartScheduler -> Checking scheduled queries every %v", scheduledQueryCheckInterval)
+		for range ticker.C {
+			s.runDueQueries()
+		}
+	}()
+}
+
+// runDueQueries starts the runs of the due scheduled queries, as many as there are free run slots
+func (s *scheduledQueryService) runDueQueries() {
+	dueQueries, err := s.scheduledQueryRepo.FindDue(time.Now(), maxScheduledQueryRuns)
+	if err != nil {
+		log.Printf("ScheduledQueryService -> runDueQueries -> Error fetching due queries: %v", err)
+		return
+	}
+
+	for _, scheduledQuery := range dueQueries {
+		select {
+		case s.runSlots <- struct{}{}:
+		default:
+			return
+		}
+		go func(scheduledQuery *models.ScheduledQuery) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("ScheduledQueryService -> runDueQueries -> Run panic recovered: %v", r)
+				}
+				<-s.runSlots
+			}()
+			s.runScheduledQuery(scheduledQuery)
+		}(scheduledQuery)
+	}
+}
+
+// runScheduledQuery runs a due scheduled query once across the instances, stores its snapshot and evaluates its alert
+// rules on it
+func (s *scheduledQueryService) runScheduledQuery(scheduledQuery *models.ScheduledQuery) {
+	ctx, cancel := context.WithTimeout(context.Background(), scheduledQueryRunTimeout)
+	defer cancel()
+
+	// The token is unique to the run, a run that outlived the lock must not release the lock of the next one
+	lockKey := fmt.Sprintf("scheduled_query_lock:%s", scheduledQuery.ID.Hex())
+	lockToken := []byte(s.instanceID + ":" + primitive.NewObjectID().Hex())
+	locked, err := s.redisRepo.SetNX(lockKey, lockToken, scheduledQueryLockTTL, ctx)
+	if err != nil || !locked {
+		return
+	}
+	defer func() {
+		released, err := s.redisRepo.DelIfValue(lockKey, lockToken, context.Background())
+		if err != nil {
+			log.Printf("ScheduledQueryService -> runScheduledQuery -> Error releasing lock: %v", err)
+		} else if !released {
+			log.Printf("ScheduledQueryService -> runScheduledQuery -> Lock of %s expired before the run ended", scheduledQuery.ID.Hex())
+		}
+	}()
+
+	// The next run is moved before running, a run whose next run was already moved was claimed by another instance
+	nextRunAt, err := nextScheduledRun(scheduledQuery.CronExpression, scheduledQuery.Timezone, time.Now())
+	if err != nil {
+		log.Printf("ScheduledQueryService -> runScheduledQuery -> Invalid schedule of %s, it won't run again: %v", scheduledQuery.ID.Hex(), err)
+	}
+	claimed, err := s.scheduledQueryRepo.ClaimRun(scheduledQuery.ID, *scheduledQuery.NextRunAt, nextRunAt)
+	if err != nil || !claimed {
+		return
+	}
+
+	log.Printf("ScheduledQueryService -> runScheduledQuery -> Running scheduled query %s", scheduledQuery.ID.Hex())
+	snapshot, rows := s.executeScheduledQuery(ctx, scheduledQuery)
+	if err := s.scheduledQueryRepo.CreateSnapshot(snapshot); err != nil {
+		log.Printf("ScheduledQueryService -> runScheduledQuery -> Error storing snapshot: %v", err)
+	}
+	s.alertService.EvaluateSnapshot(scheduledQuery, snapshot, rows)
+	if err := s.scheduledQueryRepo.UpdateLastRun(scheduledQuery.ID, snapshot.RanAt, snapshot.Status, snapshot.Query); err != nil {
+		log.Printf("ScheduledQueryService -> runScheduledQuery -> Error updating last run: %v", err)
+	}
+	if err := s.scheduledQueryRepo.DeleteSnapshotsBeyond(scheduledQuery.ID, scheduledQuerySnapshotsKept); err != nil {
+		log.Printf("ScheduledQueryService -> runScheduledQuery -> Error deleting old snapshots: %v", err)
+	}
+}
+
+// executeScheduledQuery runs a scheduled query against the connection of its chat and returns its snapshot, with the
+// rows of its result when it succeeded
+func (s *scheduledQueryService) executeScheduledQuery(ctx context.Context, scheduledQuery *models.ScheduledQuery) (*models.ScheduledQuerySnapshot, []interface{}) {
+	snapshot := &models.ScheduledQuerySnapshot{
+		ScheduledQueryID: scheduledQuery.ID,
+		UserID:           scheduledQuery.UserID,
+		RanAt:            time.Now(),
+		Status:           scheduledRunFailed,
+		Query:            scheduledQuery.Query,
+		Thresholds:       []models.ThresholdResult{},
+		Base:             models.NewBase(),
+	}
+	fail := func(code, message string) (*models.ScheduledQuerySnapshot, []interface{}) {
+		log.Printf("ScheduledQueryService -> executeScheduledQuery -> Scheduled query %s failed: %s", scheduledQuery.ID.Hex(), message)
+		snapshot.Error = &models.QueryError{Code: code, Message: message}
+		return snapshot, nil
+	}
+
+	userID, chatID := scheduledQuery.UserID.Hex(), scheduledQuery.ChatID.Hex()
+	chat, _, err := findUserChat(s.chatRepo, userID, chatID)
+	if err != nil {
+		return fail("CHAT_NOT_FOUND", err.Error())
+	}
+
+	// Saved queries are bound again, they may have changed since they were scheduled
+	if scheduledQuery.SavedQueryID != nil {
+		savedQuery, _, err := findUserSavedQuery(s.savedQueryRepo, userID, scheduledQuery.SavedQueryID.Hex())
+		if err != nil {
+			return fail("SAVED_QUERY_NOT_FOUND", err.Error())
+		}
+		if snapshot.Query, err = bindSavedQuery(savedQuery, chat, scheduledQuery.Params); err != nil {
+			return fail("INVALID_PARAMS", err.Error())
+		}
+	}
+	if err := dbmanager.CheckReadOnlyQuery(chat.Connection.Type, snapshot.Query); err != nil {
+		return fail("READ_ONLY_MODE", fmt.Sprintf("only queries that read data can be scheduled: %v", err))
+	}
+
+	// Each scheduled query has its own stream, so its runs can't be mixed with executions of the chat
+	streamID := "scheduled-" + scheduledQuery.ID.Hex()
+	if !s.dbManager.IsConnected(chatID) {
+		log.Printf("ScheduledQueryService -> executeScheduledQuery -> Database not connected, initiating connection")
+		if _, err := s.chatService.ConnectDB(ctx, userID, chatID, streamID); err != nil {
+			return fail("CONNECTION_FAILED", err.Error())
+		}
+		// Give a small delay for connection to stabilize
+		time.Sleep(1 * time.Second)
+	}
+
+	queryType := ""
+	if scheduledQuery.QueryType != nil {
+		queryType = *scheduledQuery.QueryType
+	}
+	rows, executionTime, truncated, queryErr := s.readScheduledQuery(ctx, chatID, scheduledQuery.ID.Hex(), streamID, chat.Connection.Type, snapshot.Query, queryType)
+	if queryErr != nil {
+		log.Printf("ScheduledQueryService -> executeScheduledQuery -> Scheduled query %s failed: %s", scheduledQuery.ID.Hex(), queryErr.Message)
+		snapshot.Error = &models.QueryError{Code: queryErr.Code, Message: queryErr.Message, Details: queryErr.Details}
+		return snapshot, nil
+	}
+
+	previous, err := s.scheduledQueryRepo.FindLatestSnapshot(scheduledQuery.ID, scheduledRunSucceeded)
+	if err != nil {
+		log.Printf("ScheduledQueryService -> executeScheduledQuery -> Error fetching previous snapshot: %v", err)
+	}
+	resultJSON, _ := capQueryResult(map[string]interface{}{"results": rows})
+	encryptedResult := s.encryptSnapshotResult(resultJSON)
+
+	snapshot.Status = scheduledRunSucceeded
+	snapshot.ExecutionTime = &executionTime
+	snapshot.RowCount = len(rows)
+	snapshot.Truncated = truncated
+	snapshot.Result = &encryptedResult
+	snapshot.Thresholds, snapshot.Breached = evaluateThresholds(scheduledQuery.Thresholds, rows, previous)
+	return snapshot, rows
+}
+
+// readScheduledQuery runs a scheduled query and returns its rows, capped like the results streamed to chats so a large
+// result can't exhaust the memory. Results of databases that can't stream are capped once read.
+func (s *scheduledQueryService) readScheduledQuery(ctx context.Context, chatID, queryID, streamID, dbType, query, queryType string) ([]interface{}, int, bool, *dtos.QueryError) {
+	maxRows := config.Env.QueryResultMaxRows
+	if dbmanager.CanStreamQuery(dbType, query) {
+		limits := dbmanager.ResultLimits{
+			BatchSize: config.Env.QueryResultBatchSize,
+			MaxRows:   int64(maxRows),
+			MaxBytes:  int64(config.Env.QueryResultMaxBytes),
+		}
+		var rows []interface{}
+		streamed, queryErr := s.dbManager.StreamQuery(ctx, chatID, "", queryID, streamID, query, limits, func(_ []string, batch []map[string]interface{}) error {
+			for _, row := range batch {
+				rows = append(rows, row)
+			}
+			return nil
+		})
+		if queryErr == nil {
+			return resultRows(rows), streamed.ExecutionTime, streamed.Truncated, nil
+		}
+		if queryErr.Code != "STREAMING_NOT_SUPPORTED" {
+			return nil, 0, false, queryErr
+		}
+	}
+
+	result, queryErr := s.dbManager.ExecuteQuery(ctx, chatID, "", queryID, streamID, query, queryType, false, false)
+	if queryErr == nil {
+		queryErr = result.Error
+	}
+	if queryErr != nil {
+		return nil, 0, false, queryErr
+	}
+	rows := resultRows(result.Result)
+	truncated := maxRows > 0 && len(rows) > maxRows
+	if truncated {
+		rows = rows[:maxRows]
+	}
+	return rows, result.ExecutionTime, truncated, nil
+}
+
+// nextScheduledRun returns the next run of a cron expression after the time, in the timezone. Expressions running
+// more often than scheduledQueryMinInterval are rejected.
+func nextScheduledRun(cronExpression, timezone string, after time.Time) (*time.Time, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q", timezone)
+	}
+	schedule, err := cron.ParseStandard(cronExpression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression: %v", err)
+	}
+	next := schedule.Next(after.In(location))
+	if next.IsZero() {
+		return nil, fmt.Errorf("the cron expression never runs")
+	}
+	if following := schedule.Next(next); !following.IsZero() && following.Sub(next) < scheduledQueryMinInterval {
+		return nil, fmt.Errorf("the cron expression runs more often than every %v", scheduledQueryMinInterval)
+	}
+	next = next.UTC()
+	return &next, nil
+}
+
+// findUserScheduledQuery returns a scheduled query of the user
+func findUserScheduledQuery(scheduledQueryRepo repositories.ScheduledQueryRepository, userID, scheduledQueryID string) (*models.ScheduledQuery, uint32, error) {
+	scheduledQueryObjID, err := primitive.ObjectIDFromHex(scheduledQueryID)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid scheduled query ID format")
+	}
+	scheduledQuery, err := scheduledQueryRepo.FindByID(scheduledQueryObjID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to fetch scheduled query: %v", err)
+	}
+	if scheduledQuery == nil || scheduledQuery.UserID.Hex() != userID {
+		return nil, http.StatusNotFound, fmt.Errorf("scheduled query not found")
+	}
+	return scheduledQuery, http.StatusOK, nil
+}
+
+// encryptSnapshotResult encrypts the result of a snapshot for storage, like the results of messages
+func (s *scheduledQueryService) encryptSnapshotResult(result string) string {
+	if s.crypto == nil || result == "" {
+		return result
+	}
+	encrypted, err := s.crypto.EncryptField(result)
+	if err != nil {
+		log.Printf("ScheduledQueryService -> encryptSnapshotResult -> Failed to encrypt: %v", err)
+		return result
+	}
+	return encrypted
+}
+
+// decryptSnapshotResult decrypts the result of a snapshot from storage
+func (s *scheduledQueryService) decryptSnapshotResult(result string) string {
+	if s.crypto == nil || result == "" {
+		return result
+	}
+	decrypted, err := s.crypto.DecryptField(result)
+	if err != nil {
+		log.Printf("ScheduledQueryService -> decryptSnapshotResult -> Failed to decrypt: %v", err)
+		return result
+	}
+	return decrypted
+}
+
+func (s *scheduledQueryService) toSnapshotResponse(snapshot *models.ScheduledQuerySnapshot) dtos.ScheduledQuerySnapshotResponse {
+	response := dtos.ScheduledQuerySnapshotResponse{
+		ID:               snapshot.ID.Hex(),
+		ScheduledQueryID: snapshot.ScheduledQueryID.Hex(),
+		RanAt:            snapshot.RanAt.Format(time.RFC3339),
+		Status:           snapshot.Status,
+		Query:            snapshot.Query,
+		ExecutionTime:    snapshot.ExecutionTime,
+		RowCount:         snapshot.RowCount,
+		Truncated:        snapshot.Truncated,
+		Thresholds:       make([]dtos.ThresholdResult, len(snapshot.Thresholds)),
+		Breached:         snapshot.Breached,
+	}
+	if snapshot.Result != nil {
+		var result interface{}
+		if err := json.Unmarshal([]byte(s.decryptSnapshotResult(*snapshot.Result)), &result); err == nil {
+			response.Result = result
+		}
+	}
+	if snapshot.Error != nil {
+		response.Error = &dtos.QueryError{
+			Code:    snapshot.Error.Code,
+			Message: snapshot.Error.Message,
+			Details: snapshot.Error.Details,
+		}
+	}
+	for i, threshold := range snapshot.Thresholds {
+		response.Thresholds[i] = dtos.ThresholdResult{
+			QueryThreshold: toQueryThresholdDto(threshold.QueryThreshold),
+			Actual:         threshold.Actual,
+			Previous:       threshold.Previous,
+			Breached:       threshold.Breached,
+			Error:          threshold.Error,
+		}
+	}
+	return response
+}
+
+func toQueryThresholdDto(threshold models.QueryThreshold) dtos.QueryThreshold {
+	return dtos.QueryThreshold{
+		Metric:   threshold.Metric,
+		Column:   threshold.Column,
+		Operator: threshold.Operator,
+		Value:    threshold.Value,
+	}
+}
+
+func toScheduledQueryResponse(scheduledQuery *models.ScheduledQuery) *dtos.ScheduledQueryResponse {
+	response := &dtos.ScheduledQueryResponse{
+		ID:             scheduledQuery.ID.Hex(),
+		ChatID:         scheduledQuery.ChatID.Hex(),
+		Name:           scheduledQuery.Name,
+		Query:          scheduledQuery.Query,
+		QueryType:      scheduledQuery.QueryType,
+		Params:         scheduledQuery.Params,
+		CronExpression: scheduledQuery.CronExpression,
+		Timezone:       scheduledQuery.Timezone,
+		Enabled:        scheduledQuery.Enabled,
+		Thresholds:     make([]dtos.QueryThreshold, len(scheduledQuery.Thresholds)),
+		LastStatus:     scheduledQuery.LastStatus,
+		CreatedAt:      scheduledQuery.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      scheduledQuery.UpdatedAt.Format(time.RFC3339),
+	}
+	for i, threshold := range scheduledQuery.Thresholds {
+		response.Thresholds[i] = toQueryThresholdDto(threshold)
+	}
+	if scheduledQuery.SavedQueryID != nil {
+		response.SavedQueryID = utils.ToStringPtr(scheduledQuery.SavedQueryID.Hex())
+	}
+	if scheduledQuery.SourceMessageID != nil {
+		response.SourceMessageID = utils.ToStringPtr(scheduledQuery.SourceMessageID.Hex())
+	}
+	if scheduledQuery.SourceQueryID != nil {
+		response.SourceQueryID = utils.ToStringPtr(scheduledQuery.SourceQueryID.Hex())
+	}
+	if scheduledQuery.NextRunAt != nil {
+		response.NextRunAt = utils.ToStringPtr(scheduledQuery.NextRunAt.Format(time.RFC3339))
+	}
+	if scheduledQuery.LastRunAt != nil {
+		response.LastRunAt = utils.ToStringPtr(scheduledQuery.LastRunAt.Format(time.RFC3339))
+	}
+	return response
+}
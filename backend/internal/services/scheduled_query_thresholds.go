@@ -0,0 +1,184 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/models"
+	"strconv"
+	"strings"
+)
+
+// Metrics and operators of the thresholds of scheduled queries
+const (
+	thresholdMetricRowCount = "row_count"
+	thresholdMetricValue    = "value"
+)
+
+var thresholdOperators = map[string]bool{
+	"gt": true, "gte": true, "lt": true, "lte": true, "eq": true, "ne": true, "change": true, "change_pct": true,
+}
+
+// validateThresholds checks the thresholds of a scheduled query
+func validateThresholds(thresholds []dtos.QueryThreshold) ([]models.QueryThreshold, error) {
+	validated := make([]models.QueryThreshold, 0, len(thresholds))
+	for i, threshold := range thresholds {
+		checked, err := validateThreshold(threshold)
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold %d: %v", i+1, err)
+		}
+		validated = append(validated, checked)
+	}
+	return validated, nil
+}
+
+// validateThreshold checks a threshold, or the condition of an alert rule
+func validateThreshold(threshold dtos.QueryThreshold) (models.QueryThreshold, error) {
+	switch threshold.Metric {
+	case thresholdMetricRowCount:
+		threshold.Column = ""
+	case thresholdMetricValue:
+		if strings.TrimSpace(threshold.Column) == "" {
+			return models.QueryThreshold{}, fmt.Errorf("the value metric reads a column, its column is required")
+		}
+	default:
+		return models.QueryThreshold{}, fmt.Errorf("invalid metric %q, must be row_count or value", threshold.Metric)
+	}
+	if !thresholdOperators[threshold.Operator] {
+		return models.QueryThreshold{}, fmt.Errorf("invalid operator %q, must be one of gt, gte, lt, lte, eq, ne, change or change_pct", threshold.Operator)
+	}
+	if (threshold.Operator == "change" || threshold.Operator == "change_pct") && threshold.Value < 0 {
+		return models.QueryThreshold{}, fmt.Errorf("the change operators compare a change, the value can't be negative")
+	}
+	return models.QueryThreshold{
+		Metric:   threshold.Metric,
+		Column:   strings.TrimSpace(threshold.Column),
+		Operator: threshold.Operator,
+		Value:    threshold.Value,
+	}, nil
+}
+
+// resultRows returns the rows of a query result: the result itself when it is a list, its results when it is a map
+// holding them, or a single row
+func resultRows(result interface{}) []interface{} {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil
+	}
+	var formatted interface{}
+	if err := json.Unmarshal(resultJSON, &formatted); err != nil {
+		return nil
+	}
+	switch value := formatted.(type) {
+	case nil:
+		return []interface{}{}
+	case []interface{}:
+		return value
+	case map[string]interface{}:
+		if rows, ok := value["results"].([]interface{}); ok {
+			return rows
+		}
+	}
+	return []interface{}{formatted}
+}
+
+// evaluateThresholds evaluates the thresholds on the rows of a snapshot. The change operators compare with the
+// metric in the previous snapshot, they aren't breached on the first one.
+func evaluateThresholds(thresholds []models.QueryThreshold, rows []interface{}, previous *models.ScheduledQuerySnapshot) ([]models.ThresholdResult, bool) {
+	results := make([]models.ThresholdResult, len(thresholds))
+	breached := false
+	for i, threshold := range thresholds {
+		results[i] = models.ThresholdResult{QueryThreshold: threshold}
+		actual, err := thresholdMetric(threshold, rows)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].Actual = &actual
+		results[i].Previous = previousMetric(threshold, previous)
+		results[i].Breached = isThresholdBreached(threshold, actual, results[i].Previous)
+		breached = breached || results[i].Breached
+	}
+	return results, breached
+}
+
+// thresholdMetric reads the metric of the threshold in the rows
+func thresholdMetric(threshold models.QueryThreshold, rows []interface{}) (float64, error) {
+	if threshold.Metric == thresholdMetricRowCount {
+		return float64(len(rows)), nil
+	}
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("the result has no rows")
+	}
+	row, ok := rows[0].(map[string]interface{})
+	if !ok {
+		return 0, fmt.Errorf("the result has no columns")
+	}
+	value, exists := row[threshold.Column]
+	if !exists {
+		return 0, fmt.Errorf("the result has no column %s", threshold.Column)
+	}
+	switch value := value.(type) {
+	case float64:
+		return value, nil
+	case bool:
+		if value {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		// Some drivers return decimals as strings
+		if number, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			return number, nil
+		}
+	case nil:
+		return 0, fmt.Errorf("column %s is null", threshold.Column)
+	}
+	return 0, fmt.Errorf("column %s is not a number", threshold.Column)
+}
+
+// previousMetric returns the metric of the threshold in the previous snapshot, nil when it wasn't read
+func previousMetric(threshold models.QueryThreshold, previous *models.ScheduledQuerySnapshot) *float64 {
+	if previous == nil {
+		return nil
+	}
+	if threshold.Metric == thresholdMetricRowCount {
+		rowCount := float64(previous.RowCount)
+		return &rowCount
+	}
+	for _, result := range previous.Thresholds {
+		if result.Metric == threshold.Metric && result.Column == threshold.Column && result.Actual != nil {
+			return result.Actual
+		}
+	}
+	return nil
+}
+
+func isThresholdBreached(threshold models.QueryThreshold, actual float64, previous *float64) bool {
+	switch threshold.Operator {
+	case "gt":
+		return actual > threshold.Value
+	case "gte":
+		return actual >= threshold.Value
+	case "lt":
+		return actual < threshold.Value
+	case "lte":
+		return actual <= threshold.Value
+	case "eq":
+		return actual == threshold.Value
+	case "ne":
+		return actual != threshold.Value
+	case "change":
+		return previous != nil && math.Abs(actual-*previous) > threshold.Value
+	case "change_pct":
+		if previous == nil {
+			return false
+		}
+		if *previous == 0 {
+			return actual != 0
+		}
+		return math.Abs(actual-*previous)/math.Abs(*previous)*100 > threshold.Value
+	}
+	return false
+}
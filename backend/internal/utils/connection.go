@@ -58,6 +58,19 @@ func GenerateConfigKey(config map[string]interface{}) string {
 		username,
 		databaseStr)
 
+	// Connections through different SSH bastions must not share a pool
+	if sshHost, ok := config["ssh_host"].(*string); ok && sshHost != nil && *sshHost != "" {
+		sshUsername := ""
+		if u, ok := config["ssh_username"].(*string); ok && u != nil {
+			sshUsername = *u
+		}
+		sshPort := ""
+		if p, ok := config["ssh_port"].(*string); ok && p != nil {
+			sshPort = *p
+		}
+		key = fmt.Sprintf("%s:ssh:%s@%s:%s", key, sshUsername, *sshHost, sshPort)
+	}
+
 	return key
 }
 
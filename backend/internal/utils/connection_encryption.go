@@ -83,6 +83,55 @@ func EncryptConnection(conn *models.Connection) error {
 		}
 	}
 
+	// Encrypt SSH tunnel settings if present
+	if conn.SSHHost != nil {
+		if encryptedSSHHost, err := encrypt(*conn.SSHHost, key); err == nil {
+			*conn.SSHHost = encryptedSSHHost
+		} else {
+			return fmt.Errorf("failed to encrypt SSH host: %v", err)
+		}
+	}
+
+	if conn.SSHPort != nil {
+		if encryptedSSHPort, err := encrypt(*conn.SSHPort, key); err == nil {
+			*conn.SSHPort = encryptedSSHPort
+		} else {
+			return fmt.Errorf("failed to encrypt SSH port: %v", err)
+		}
+	}
+
+	if conn.SSHUsername != nil {
+		if encryptedSSHUsername, err := encrypt(*conn.SSHUsername, key); err == nil {
+			*conn.SSHUsername = encryptedSSHUsername
+		} else {
+			return fmt.Errorf("failed to encrypt SSH username: %v", err)
+		}
+	}
+
+	if conn.SSHPrivateKey != nil {
+		if encryptedKey, err := encrypt(*conn.SSHPrivateKey, key); err == nil {
+			*conn.SSHPrivateKey = encryptedKey
+		} else {
+			return fmt.Errorf("failed to encrypt SSH private key: %v", err)
+		}
+	}
+
+	if conn.SSHPassphrase != nil {
+		if encryptedPassphrase, err := encrypt(*conn.SSHPassphrase, key); err == nil {
+			*conn.SSHPassphrase = encryptedPassphrase
+		} else {
+			return fmt.Errorf("failed to encrypt SSH passphrase: %v", err)
+		}
+	}
+
+	if conn.SSHHostKey != nil {
+		if encryptedHostKey, err := encrypt(*conn.SSHHostKey, key); err == nil {
+			*conn.SSHHostKey = encryptedHostKey
+		} else {
+			return fmt.Errorf("failed to encrypt SSH host key: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -156,6 +205,55 @@ func DecryptConnection(conn *models.Connection) {
 			log.Printf("Warning: Failed to decrypt SSL root certificate URL, using as-is: %v", err)
 		}
 	}
+
+	// Decrypt SSH tunnel settings if present
+	if conn.SSHHost != nil {
+		if decryptedSSHHost, err := decrypt(*conn.SSHHost, key); err == nil {
+			*conn.SSHHost = decryptedSSHHost
+		} else {
+			log.Printf("Warning: Failed to decrypt SSH host, using as-is: %v", err)
+		}
+	}
+
+	if conn.SSHPort != nil {
+		if decryptedSSHPort, err := decrypt(*conn.SSHPort, key); err == nil {
+			*conn.SSHPort = decryptedSSHPort
+		} else {
+			log.Printf("Warning: Failed to decrypt SSH port, using as-is: %v", err)
+		}
+	}
+
+	if conn.SSHUsername != nil {
+		if decryptedSSHUsername, err := decrypt(*conn.SSHUsername, key); err == nil {
+			*conn.SSHUsername = decryptedSSHUsername
+		} else {
+			log.Printf("Warning: Failed to decrypt SSH username, using as-is: %v", err)
+		}
+	}
+
+	if conn.SSHPrivateKey != nil {
+		if decryptedKey, err := decrypt(*conn.SSHPrivateKey, key); err == nil {
+			*conn.SSHPrivateKey = decryptedKey
+		} else {
+			log.Printf("Warning: Failed to decrypt SSH private key, using as-is: %v", err)
+		}
+	}
+
+	if conn.SSHPassphrase != nil {
+		if decryptedPassphrase, err := decrypt(*conn.SSHPassphrase, key); err == nil {
+			*conn.SSHPassphrase = decryptedPassphrase
+		} else {
+			log.Printf("Warning: Failed to decrypt SSH passphrase, using as-is: %v", err)
+		}
+	}
+
+	if conn.SSHHostKey != nil {
+		if decryptedHostKey, err := decrypt(*conn.SSHHostKey, key); err == nil {
+			*conn.SSHHostKey = decryptedHostKey
+		} else {
+			log.Printf("Warning: Failed to decrypt SSH host key, using as-is: %v", err)
+		}
+	}
 }
 
 // encrypt encrypts a string using AES-GCM
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"neobase-ai/config"
+)
+
+// DatabaseFileStoragePath returns the path of the encrypted database file stored for a chat
+func DatabaseFileStoragePath(chatID, dbType string) (string, error) {
+	dir, err := filepath.Abs(config.Env.DatabaseFilesDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve database files directory: %v", err)
+	}
+	return filepath.Join(dir, fmt.Sprintf("%s.%s.enc", chatID, dbType)), nil
+}
+
+// IsDatabaseFileStoragePath checks that a path points inside the database files directory
+func IsDatabaseFileStoragePath(path string) bool {
+	dir, err := filepath.Abs(config.Env.DatabaseFilesDir)
+	if err != nil {
+		return false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(absPath, dir+string(os.PathSeparator))
+}
+
+// WriteEncryptedFile encrypts data and atomically writes it to path
+func WriteEncryptedFile(path string, data []byte) error {
+	crypto, err := NewFromConfig()
+	if err != nil {
+		return fmt.Errorf("failed to initialize file encryption: %v", err)
+	}
+
+	encrypted, err := crypto.EncryptBytes(data)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt file: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	// Write to a temporary file first so a failed write never corrupts the stored copy
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, encrypted, 0600); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to replace file: %v", err)
+	}
+	return nil
+}
+
+// ReadEncryptedFile reads and decrypts a file written by WriteEncryptedFile
+func ReadEncryptedFile(path string) ([]byte, error) {
+	crypto, err := NewFromConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize file encryption: %v", err)
+	}
+
+	encrypted, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+
+	data, err := crypto.DecryptBytes(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt file: %v", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,527 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/xuri/excelize/v2"
+)
+
+// Formats of the files written by ResultWriter
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatJSONL   = "jsonl"
+	ExportFormatXLSX    = "xlsx"
+	ExportFormatParquet = "parquet"
+)
+
+const (
+	xlsxMaxRows         = 1048576 // Rows of a sheet, the header included
+	xlsxMaxCellChars    = 32767
+	parquetRowGroupRows = 10000 // Rows buffered before a row group is written
+)
+
+// ResultWriter writes the rows of a query result to a file as they are read, page by page
+type ResultWriter interface {
+	WriteRows(rows []map[string]interface{}) error
+	// Close writes what is still buffered, e.g. the footer of the file, it doesn't close the underlying writer
+	Close() error
+}
+
+// ExportContentType returns the content type of a format of ResultWriter, "" for unknown formats
+func ExportContentType(format string) string {
+	switch format {
+	case ExportFormatCSV:
+		return "text/csv"
+	case ExportFormatJSONL:
+		return "application/x-ndjson"
+	case ExportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case ExportFormatParquet:
+		return "application/vnd.apache.parquet"
+	}
+	return ""
+}
+
+// NewResultWriter returns a writer of a format to w. The columns of CSV, XLSX and Parquet files are the given ones,
+// or those of the first rows written when columns is empty.
+func NewResultWriter(format string, w io.Writer, columns []string) (ResultWriter, error) {
+	return newResultWriter(format, w, newTabularWriter(columns, len(columns) == 0))
+}
+
+// NewDocumentResultWriter returns a writer of a format to w for documents, flattened into the columns collected
+// beforehand with ResultColumns
+func NewDocumentResultWriter(format string, w io.Writer, columns []string) (ResultWriter, error) {
+	return newResultWriter(format, w, newTabularWriter(columns, true))
+}
+
+func newResultWriter(format string, w io.Writer, tabular tabularWriter) (ResultWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return &csvResultWriter{tabularWriter: tabular, writer: csv.NewWriter(w)}, nil
+	case ExportFormatJSONL:
+		return &jsonlResultWriter{encoder: json.NewEncoder(w)}, nil
+	case ExportFormatXLSX:
+		file := excelize.NewFile()
+		stream, err := file.NewStreamWriter("Sheet1")
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to create xlsx file: %v", err)
+		}
+		return &xlsxResultWriter{tabularWriter: tabular, out: w, file: file, stream: stream}, nil
+	case ExportFormatParquet:
+		return &parquetResultWriter{tabularWriter: tabular, out: w}, nil
+	}
+	return nil, fmt.Errorf("unsupported export format: %s", format)
+}
+
+// NormalizeRows converts rows through JSON so they only hold maps, slices, strings, bools and json.Number, e.g.
+// ObjectIDs become their hex string. Numbers keep their precision.
+func NormalizeRows(rows []map[string]interface{}) ([]map[string]interface{}, error) {
+	rowsJSON, err := json.Marshal(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process rows: %v", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(rowsJSON))
+	decoder.UseNumber()
+	var normalized []map[string]interface{}
+	if err := decoder.Decode(&normalized); err != nil {
+		return nil, fmt.Errorf("failed to process rows: %v", err)
+	}
+	return normalized, nil
+}
+
+// FlattenRow flattens nested documents into dotted keys, e.g. {"address": {"city": "Paris"}} becomes
+// {"address.city": "Paris"}. Arrays are kept whole and written as JSON.
+func FlattenRow(row map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{}, len(row))
+	flattenInto(flat, "", row)
+	return flat
+}
+
+func flattenInto(flat map[string]interface{}, prefix string, document map[string]interface{}) {
+	for key, value := range document {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenInto(flat, key, nested)
+			continue
+		}
+		flat[key] = value
+	}
+}
+
+// ResultColumns collects the columns of rows flattened like the writers of documents flatten them. Documents don't
+// share the same keys, their result is scanned for its columns before it is written.
+type ResultColumns struct {
+	seen    map[string]bool
+	columns []string
+}
+
+// Add adds the keys of the rows missing from the columns
+func (c *ResultColumns) Add(rows []map[string]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	rows, err := NormalizeRows(rows)
+	if err != nil {
+		return err
+	}
+	if c.seen == nil {
+		c.seen = map[string]bool{}
+	}
+	for _, row := range rows {
+		for key := range FlattenRow(row) {
+			if !c.seen[key] {
+				c.seen[key] = true
+				c.columns = append(c.columns, key)
+			}
+		}
+	}
+	return nil
+}
+
+// Columns returns the columns sorted by name, _id first
+func (c *ResultColumns) Columns() []string {
+	columns := append([]string(nil), c.columns...)
+	// Map keys have no order, _id is kept first for documents
+	sort.Slice(columns, func(i, j int) bool {
+		if columns[i] == "_id" || columns[j] == "_id" {
+			return columns[i] == "_id"
+		}
+		return columns[i] < columns[j]
+	})
+	return columns
+}
+
+// tabularWriter holds the columns of the formats with a fixed set of columns, given or taken from the first rows.
+// Documents are flattened. A row with a key missing from the columns fails the write rather than losing its value.
+type tabularWriter struct {
+	columns       []string
+	flattenNested bool
+	known         map[string]bool
+}
+
+func newTabularWriter(columns []string, flattenNested bool) tabularWriter {
+	return tabularWriter{columns: columns, flattenNested: flattenNested}
+}
+
+// flatten normalizes rows, flattening them if needed, and sets the columns from the first rows when they are not set yet
+func (t *tabularWriter) flatten(rows []map[string]interface{}) ([]map[string]interface{}, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	rows, err := NormalizeRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if t.flattenNested {
+		for i := range rows {
+			rows[i] = FlattenRow(rows[i])
+		}
+	}
+
+	if len(t.columns) == 0 {
+		var columns ResultColumns
+		if err := columns.Add(rows); err != nil {
+			return nil, err
+		}
+		t.columns = columns.Columns()
+	}
+	if t.known == nil {
+		t.known = make(map[string]bool, len(t.columns))
+		for _, column := range t.columns {
+			t.known[column] = true
+		}
+	}
+	for _, row := range rows {
+		for key := range row {
+			if !t.known[key] {
+				return nil, fmt.Errorf("the field %s is missing from the columns of the file, export the result as jsonl to keep every field", key)
+			}
+		}
+	}
+	return rows, nil
+}
+
+// cellString returns a value as the text of a cell, nested values as JSON
+func cellString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(valueJSON)
+}
+
+type csvResultWriter struct {
+	tabularWriter
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (w *csvResultWriter) WriteRows(rows []map[string]interface{}) error {
+	rows, err := w.flatten(rows)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	if !w.headerWritten {
+		if err := w.writer.Write(w.columns); err != nil {
+			return fmt.Errorf("failed to write csv header: %v", err)
+		}
+		w.headerWritten = true
+	}
+	record := make([]string, len(w.columns))
+	for _, row := range rows {
+		for i, column := range w.columns {
+			record[i] = cellString(row[column])
+		}
+		if err := w.writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv row: %v", err)
+		}
+	}
+	// Each page is sent right away
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvResultWriter) Close() error {
+	if !w.headerWritten && len(w.columns) > 0 {
+		if err := w.writer.Write(w.columns); err != nil {
+			return fmt.Errorf("failed to write csv header: %v", err)
+		}
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type jsonlResultWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *jsonlResultWriter) WriteRows(rows []map[string]interface{}) error {
+	for _, row := range rows {
+		if err := w.encoder.Encode(row); err != nil {
+			return fmt.Errorf("failed to write jsonl row: %v", err)
+		}
+	}
+	return nil
+}
+
+func (w *jsonlResultWriter) Close() error {
+	return nil
+}
+
+// xlsxResultWriter writes a sheet with the stream writer of excelize, which keeps large sheets in a temporary file
+type xlsxResultWriter struct {
+	tabularWriter
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int // Last row written
+}
+
+func (w *xlsxResultWriter) WriteRows(rows []map[string]interface{}) error {
+	rows, err := w.flatten(rows)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	if w.row == 0 {
+		if err := w.writeHeader(); err != nil {
+			return err
+		}
+	}
+	if w.row+len(rows) > xlsxMaxRows {
+		return fmt.Errorf("xlsx files are limited to %d rows, export the result as csv, jsonl or parquet", xlsxMaxRows-1)
+	}
+
+	for _, row := range rows {
+		values := make([]interface{}, len(w.columns))
+		for i, column := range w.columns {
+			values[i] = xlsxCellValue(row[column])
+		}
+		w.row++
+		cell, _ := excelize.CoordinatesToCellName(1, w.row)
+		if err := w.stream.SetRow(cell, values); err != nil {
+			return fmt.Errorf("failed to write xlsx row: %v", err)
+		}
+	}
+	return nil
+}
+
+func (w *xlsxResultWriter) writeHeader() error {
+	header := make([]interface{}, len(w.columns))
+	for i, column := range w.columns {
+		header[i] = column
+	}
+	w.row = 1
+	if err := w.stream.SetRow("A1", header); err != nil {
+		return fmt.Errorf("failed to write xlsx header: %v", err)
+	}
+	return nil
+}
+
+func (w *xlsxResultWriter) Close() error {
+	defer w.file.Close()
+	if w.row == 0 {
+		if err := w.writeHeader(); err != nil {
+			return err
+		}
+	}
+	if err := w.stream.Flush(); err != nil {
+		return fmt.Errorf("failed to write xlsx file: %v", err)
+	}
+	if err := w.file.Write(w.out); err != nil {
+		return fmt.Errorf("failed to write xlsx file: %v", err)
+	}
+	return nil
+}
+
+// xlsxCellValue keeps numbers and bools typed, anything else is written as text
+func xlsxCellValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case bool:
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+	}
+	text := cellString(value)
+	if len(text) > xlsxMaxCellChars {
+		text = text[:xlsxMaxCellChars]
+	}
+	return text
+}
+
+// parquetResultWriter writes row groups of parquetRowGroupRows rows. The type of each column is inferred from the
+// first rows, values that don't match it later on are written as null.
+type parquetResultWriter struct {
+	tabularWriter
+	out        io.Writer
+	writer     *pqarrow.FileWriter
+	builder    *array.RecordBuilder
+	mismatches int
+}
+
+func (w *parquetResultWriter) WriteRows(rows []map[string]interface{}) error {
+	rows, err := w.flatten(rows)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	if w.writer == nil {
+		if err := w.open(rows); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows {
+		for i, column := range w.columns {
+			if !appendParquetValue(w.builder.Field(i), row[column]) {
+				w.mismatches++
+			}
+		}
+	}
+	record := w.builder.NewRecordBatch()
+	defer record.Release()
+	if err := w.writer.WriteBuffered(record); err != nil {
+		return fmt.Errorf("failed to write parquet rows: %v", err)
+	}
+	return nil
+}
+
+// open creates the file with a schema inferred from the first rows
+func (w *parquetResultWriter) open(rows []map[string]interface{}) error {
+	fields := make([]arrow.Field, len(w.columns))
+	for i, column := range w.columns {
+		fields[i] = arrow.Field{Name: column, Type: inferParquetType(rows, column), Nullable: true}
+	}
+	schema := arrow.NewSchema(fields, nil)
+	props := parquet.NewWriterProperties(
+		parquet.WithCompression(compress.Codecs.Snappy),
+		parquet.WithMaxRowGroupLength(parquetRowGroupRows),
+	)
+	writer, err := pqarrow.NewFileWriter(schema, w.out, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return fmt.Errorf("failed to create parquet file: %v", err)
+	}
+	w.writer = writer
+	w.builder = array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	return nil
+}
+
+func (w *parquetResultWriter) Close() error {
+	if w.writer == nil {
+		if err := w.open(nil); err != nil {
+			return err
+		}
+	}
+	defer w.builder.Release()
+	if w.mismatches > 0 {
+		log.Printf("ResultWriter -> Close -> %d parquet values didn't match the type of their column and were written as null", w.mismatches)
+	}
+	if err := w.writer.Close(); err != nil {
+		return fmt.Errorf("failed to write parquet file: %v", err)
+	}
+	return nil
+}
+
+// inferParquetType returns the type of a column from its values: bool, int64, float64 or string when they are mixed,
+// nested or all null
+func inferParquetType(rows []map[string]interface{}, column string) arrow.DataType {
+	var dataType arrow.DataType
+	for _, row := range rows {
+		var valueType arrow.DataType
+		switch v := row[column].(type) {
+		case nil:
+			continue
+		case bool:
+			valueType = arrow.FixedWidthTypes.Boolean
+		case json.Number:
+			valueType = arrow.PrimitiveTypes.Float64
+			if _, err := v.Int64(); err == nil && !strings.ContainsAny(v.String(), ".eE") {
+				valueType = arrow.PrimitiveTypes.Int64
+			}
+		default:
+			return arrow.BinaryTypes.String
+		}
+
+		switch {
+		case dataType == nil:
+			dataType = valueType
+		case arrow.TypeEqual(dataType, valueType):
+		case arrow.TypeEqual(dataType, arrow.PrimitiveTypes.Int64) && arrow.TypeEqual(valueType, arrow.PrimitiveTypes.Float64),
+			arrow.TypeEqual(dataType, arrow.PrimitiveTypes.Float64) && arrow.TypeEqual(valueType, arrow.PrimitiveTypes.Int64):
+			dataType = arrow.PrimitiveTypes.Float64
+		default:
+			return arrow.BinaryTypes.String
+		}
+	}
+	if dataType == nil {
+		return arrow.BinaryTypes.String
+	}
+	return dataType
+}
+
+// appendParquetValue appends a value to the builder of its column, false when it was appended as null because it
+// doesn't match the type of the column
+func appendParquetValue(builder array.Builder, value interface{}) bool {
+	if value == nil {
+		builder.AppendNull()
+		return true
+	}
+	switch b := builder.(type) {
+	case *array.StringBuilder:
+		b.Append(cellString(value))
+		return true
+	case *array.BooleanBuilder:
+		if v, ok := value.(bool); ok {
+			b.Append(v)
+			return true
+		}
+	case *array.Int64Builder:
+		if v, ok := value.(json.Number); ok {
+			if i, err := v.Int64(); err == nil {
+				b.Append(i)
+				return true
+			}
+		}
+	case *array.Float64Builder:
+		if v, ok := value.(json.Number); ok {
+			if f, err := v.Float64(); err == nil {
+				b.Append(f)
+				return true
+			}
+		}
+	}
+	builder.AppendNull()
+	return false
+}
@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/xuri/excelize/v2"
+)
+
+// writeResult writes the pages of rows with a writer of the format and returns the file
+func writeResult(t *testing.T, newWriter func(w *bytes.Buffer) (ResultWriter, error), pages ...[]map[string]interface{}) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	writer, err := newWriter(&out)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+	for _, page := range pages {
+		if err := writer.WriteRows(page); err != nil {
+			t.Fatalf("WriteRows: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return out.Bytes()
+}
+
+func TestCSVResultWriter(t *testing.T) {
+	got := writeResult(t, func(w *bytes.Buffer) (ResultWriter, error) {
+		return NewResultWriter(ExportFormatCSV, w, []string{"id", "name", "tags"})
+	},
+		[]map[string]interface{}{{"id": 1, "name": "a,b", "tags": []string{"x"}}},
+		[]map[string]interface{}{{"id": 2, "name": nil}},
+	)
+	want := "id,name,tags\n1,\"a,b\",\"[\"\"x\"\"]\"\n2,,\n"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestCSVResultWriterColumnsFromFirstRows(t *testing.T) {
+	got := writeResult(t, func(w *bytes.Buffer) (ResultWriter, error) {
+		return NewResultWriter(ExportFormatCSV, w, nil)
+	}, []map[string]interface{}{{"b": 1, "_id": "x", "a": map[string]interface{}{"c": true}}})
+	want := "_id,a.c,b\nx,true,1\n"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestCSVResultWriterEmptyResult(t *testing.T) {
+	got := writeResult(t, func(w *bytes.Buffer) (ResultWriter, error) {
+		return NewResultWriter(ExportFormatCSV, w, []string{"id", "name"})
+	})
+	if string(got) != "id,name\n" {
+		t.Errorf("an empty result must only have the header, got %q", got)
+	}
+}
+
+func TestResultWriterRejectsUnknownFields(t *testing.T) {
+	writer, err := NewResultWriter(ExportFormatCSV, &bytes.Buffer{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.WriteRows([]map[string]interface{}{{"a": 1}}); err != nil {
+		t.Fatal(err)
+	}
+	err = writer.WriteRows([]map[string]interface{}{{"a": 2, "b": 3}})
+	if err == nil || !strings.Contains(err.Error(), "the field b is missing") {
+		t.Errorf("expected the missing field to fail the write, got %v", err)
+	}
+}
+
+func TestDocumentResultWriter(t *testing.T) {
+	pages := [][]map[string]interface{}{
+		{{"_id": "1", "name": "a", "address": map[string]interface{}{"city": "Paris"}}},
+		{{"_id": "2", "email": "b@example.com", "address": map[string]interface{}{"zip": "75001"}}},
+	}
+	var columns ResultColumns
+	for _, page := range pages {
+		if err := columns.Add(page); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := strings.Join(columns.Columns(), ","); got != "_id,address.city,address.zip,email,name" {
+		t.Fatalf("unexpected columns %s", got)
+	}
+
+	got := writeResult(t, func(w *bytes.Buffer) (ResultWriter, error) {
+		return NewDocumentResultWriter(ExportFormatCSV, w, columns.Columns())
+	}, pages...)
+	want := "_id,address.city,address.zip,email,name\n1,Paris,,,a\n2,,75001,b@example.com,\n"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestJSONLResultWriter(t *testing.T) {
+	got := writeResult(t, func(w *bytes.Buffer) (ResultWriter, error) {
+		return NewResultWriter(ExportFormatJSONL, w, nil)
+	}, []map[string]interface{}{{"a": 1}, {"b": map[string]interface{}{"c": "d"}}})
+	if string(got) != "{\"a\":1}\n{\"b\":{\"c\":\"d\"}}\n" {
+		t.Errorf("unexpected jsonl %q", got)
+	}
+}
+
+func TestXLSXResultWriter(t *testing.T) {
+	got := writeResult(t, func(w *bytes.Buffer) (ResultWriter, error) {
+		return NewResultWriter(ExportFormatXLSX, w, []string{"id", "name", "active"})
+	}, []map[string]interface{}{{"id": 1, "name": "a", "active": true}, {"id": 2.5, "name": "b"}})
+
+	file, err := excelize.OpenReader(bytes.NewReader(got))
+	if err != nil {
+		t.Fatalf("invalid xlsx file: %v", err)
+	}
+	defer file.Close()
+	rows, err := file.GetRows("Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"id", "name", "active"}, {"1", "a", "TRUE"}, {"2.5", "b"}}
+	if len(rows) != len(want) {
+		t.Fatalf("got rows %v, want %v", rows, want)
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d: got %v, want %v", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestParquetResultWriter(t *testing.T) {
+	got := writeResult(t, func(w *bytes.Buffer) (ResultWriter, error) {
+		return NewResultWriter(ExportFormatParquet, w, []string{"id", "price", "name", "active", "mixed"})
+	},
+		[]map[string]interface{}{{"id": 1, "price": 2, "name": "a", "active": true, "mixed": 1}},
+		[]map[string]interface{}{{"id": 2, "price": 2.5, "name": nil, "active": false, "mixed": "x"}},
+	)
+
+	table, err := pqarrow.ReadTable(context.Background(), bytes.NewReader(got), nil, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		t.Fatalf("invalid parquet file: %v", err)
+	}
+	defer table.Release()
+	if table.NumRows() != 2 {
+		t.Errorf("got %d rows, want 2", table.NumRows())
+	}
+	// Types are inferred from the first page, the values of the later pages that don't match are null
+	wantTypes := map[string]arrow.DataType{
+		"id":     arrow.PrimitiveTypes.Int64,
+		"price":  arrow.PrimitiveTypes.Int64,
+		"name":   arrow.BinaryTypes.String,
+		"active": arrow.FixedWidthTypes.Boolean,
+		"mixed":  arrow.PrimitiveTypes.Int64,
+	}
+	for _, field := range table.Schema().Fields() {
+		if !arrow.TypeEqual(field.Type, wantTypes[field.Name]) {
+			t.Errorf("column %s has type %s, want %s", field.Name, field.Type, wantTypes[field.Name])
+		}
+	}
+}
+
+func TestInferParquetType(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"int": json.Number("1"), "float": json.Number("1"), "mixed": json.Number("1"), "nested": []interface{}{}},
+		{"int": json.Number("2"), "float": json.Number("1.5"), "mixed": true, "nested": nil},
+	}
+	tests := map[string]arrow.DataType{
+		"int":    arrow.PrimitiveTypes.Int64,
+		"float":  arrow.PrimitiveTypes.Float64,
+		"mixed":  arrow.BinaryTypes.String,
+		"nested": arrow.BinaryTypes.String,
+		"null":   arrow.BinaryTypes.String,
+	}
+	for column, want := range tests {
+		if got := inferParquetType(rows, column); !arrow.TypeEqual(got, want) {
+			t.Errorf("column %s: got %s, want %s", column, got, want)
+		}
+	}
+}
+
+func TestUnsupportedExportFormat(t *testing.T) {
+	if _, err := NewResultWriter("xml", &bytes.Buffer{}, nil); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+	if ExportContentType("xml") != "" || ExportContentType(ExportFormatCSV) != "text/csv" {
+		t.Error("unexpected content types")
+	}
+}
@@ -0,0 +1,437 @@
+package dbmanager
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/utils"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// CassandraDriver implements the DatabaseDriver interface for Cassandra and ScyllaDB
+type CassandraDriver struct{}
+
+// NewCassandraDriver creates a new Cassandra driver
+func NewCassandraDriver() DatabaseDriver {
+	return &CassandraDriver{}
+}
+
+// buildCassandraCluster builds the gocql cluster config from the connection config, returns temp cert files to clean up
+func buildCassandraCluster(config ConnectionConfig) (*gocql.ClusterConfig, []string, error) {
+	var tempFiles []string
+
+	port := 9042 // Default port for Cassandra/ScyllaDB
+	if config.Port != nil && *config.Port != "" {
+		parsedPort, err := strconv.Atoi(*config.Port)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid port: %s", *config.Port)
+		}
+		port = parsedPort
+	}
+
+	// Host can be a comma separated list of contact points
+	var hosts []string
+	for _, host := range strings.Split(config.Host, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, nil, fmt.Errorf("at least one host is required")
+	}
+
+	cluster := gocql.NewCluster(hosts...)
+	cluster.Port = port
+	cluster.Keyspace = config.Database
+	cluster.Consistency = gocql.LocalQuorum
+	cluster.ConnectTimeout = 10 * time.Second
+	cluster.Timeout = 30 * time.Second
+	cluster.NumConns = 2
+
+	if config.Username != nil && *config.Username != "" {
+		password := ""
+		if config.Password != nil {
+			password = *config.Password
+		}
+		cluster.Authenticator = gocql.PasswordAuthenticator{
+			Username: *config.Username,
+			Password: password,
+		}
+	}
+
+	// Configure SSL/TLS
+	if config.UseSSL {
+		sslMode := "require"
+		if config.SSLMode != nil {
+			sslMode = *config.SSLMode
+		}
+
+		if sslMode != "disable" {
+			var certURL, keyURL, rootCertURL string
+			if config.SSLCertURL != nil {
+				certURL = *config.SSLCertURL
+			}
+			if config.SSLKeyURL != nil {
+				keyURL = *config.SSLKeyURL
+			}
+			if config.SSLRootCertURL != nil {
+				rootCertURL = *config.SSLRootCertURL
+			}
+
+			// Fetch certificates from URLs
+			certPath, keyPath, rootCertPath, certTempFiles, err := utils.PrepareCertificatesFromURLs(certURL, keyURL, rootCertURL)
+			if err != nil {
+				return nil, nil, err
+			}
+			tempFiles = certTempFiles
+
+			cluster.SslOpts = &gocql.SslOptions{
+				Config: &tls.Config{
+					MinVersion:         tls.VersionTLS12,
+					InsecureSkipVerify: sslMode == "require", // Require encryption but don't verify certificates
+				},
+				CertPath:               certPath,
+				KeyPath:                keyPath,
+				CaPath:                 rootCertPath,
+				EnableHostVerification: sslMode == "verify-full",
+			}
+		}
+	}
+
+	return cluster, tempFiles, nil
+}
+
+// Connect establishes a connection to a Cassandra/ScyllaDB cluster
+func (d *CassandraDriver) Connect(config ConnectionConfig) (*Connection, error) {
+	log.Printf("CassandraDriver -> Connect -> Connecting to Cassandra at %s:%v, keyspace: %s", config.Host, config.Port, config.Database)
+
+	cluster, tempFiles, err := buildCassandraCluster(config)
+	if err != nil {
+		log.Printf("CassandraDriver -> Connect -> Error building cluster config: %v", err)
+		return nil, err
+	}
+
+	session, err := cluster.CreateSession()
+	if err != nil {
+		for _, file := range tempFiles {
+			os.Remove(file)
+		}
+		log.Printf("CassandraDriver -> Connect -> Error creating session: %v", err)
+		return nil, fmt.Errorf("failed to connect to Cassandra: %v", err)
+	}
+
+	// Create a wrapper for the Cassandra session
+	cassandraWrapper := &CassandraWrapper{
+		Session:    session,
+		Keyspace:   config.Database,
+		PageStates: NewCassandraPageStateCache(),
+	}
+
+	conn := &Connection{
+		DB:         nil, // Cassandra doesn't use GORM
+		LastUsed:   time.Now(),
+		Status:     StatusConnected,
+		Config:     config,
+		MongoDBObj: cassandraWrapper, // Store Cassandra session in the non-GORM client field
+		TempFiles:  tempFiles,
+	}
+
+	log.Printf("CassandraDriver -> Connect -> Successfully connected to Cassandra at %s:%v", config.Host, config.Port)
+	return conn, nil
+}
+
+// Disconnect closes the Cassandra session
+func (d *CassandraDriver) Disconnect(conn *Connection) error {
+	log.Printf("CassandraDriver -> Disconnect -> Disconnecting from Cassandra")
+
+	wrapper, ok := conn.MongoDBObj.(*CassandraWrapper)
+	if !ok {
+		return fmt.Errorf("invalid Cassandra connection")
+	}
+
+	wrapper.Session.Close()
+
+	// Clean up temporary certificate files
+	for _, file := range conn.TempFiles {
+		os.Remove(file)
+	}
+
+	log.Printf("CassandraDriver -> Disconnect -> Successfully disconnected from Cassandra")
+	return nil
+}
+
+// Ping checks if the Cassandra connection is alive
+func (d *CassandraDriver) Ping(conn *Connection) error {
+	wrapper, ok := conn.MongoDBObj.(*CassandraWrapper)
+	if !ok {
+		return fmt.Errorf("invalid Cassandra connection")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := pingCassandra(ctx, wrapper.Session); err != nil {
+		log.Printf("CassandraDriver -> Ping -> Error pinging Cassandra: %v", err)
+		return fmt.Errorf("failed to ping Cassandra: %v", err)
+	}
+	return nil
+}
+
+// pingCassandra runs a lightweight query against the local system table
+func pingCassandra(ctx context.Context, session *gocql.Session) error {
+	if session == nil || session.Closed() {
+		return fmt.Errorf("session is closed")
+	}
+
+	var releaseVersion string
+	return session.Query("SELECT release_version FROM system.local").WithContext(ctx).Scan(&releaseVersion)
+}
+
+// IsAlive checks if the Cassandra connection is alive
+func (d *CassandraDriver) IsAlive(conn *Connection) bool {
+	return d.Ping(conn) == nil
+}
+
+// ExecuteQuery executes a CQL query, e.g. "SELECT * FROM shop.orders WHERE customer_id = 42"
+func (d *CassandraDriver) ExecuteQuery(ctx context.Context, conn *Connection, query string, queryType string, findCount bool) *QueryExecutionResult {
+	log.Printf("CassandraDriver -> ExecuteQuery -> Executing CQL query: %s", query)
+
+	wrapper, ok := conn.MongoDBObj.(*CassandraWrapper)
+	if !ok {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: "Failed to get Cassandra wrapper from connection",
+				Code:    "INTERNAL_ERROR",
+			},
+		}
+	}
+
+	return executeCassandraQuery(ctx, wrapper, query)
+}
+
+// executeCassandraQuery executes a CQL query, multiple INSERT/UPDATE/DELETE statements are applied as a LOGGED BATCH
+func executeCassandraQuery(ctx context.Context, wrapper *CassandraWrapper, query string) *QueryExecutionResult {
+	startTime := time.Now()
+
+	var statements []string
+	for _, statement := range stripCassandraBatch(splitClickHouseStatements(query)) {
+		if strings.TrimSpace(statement) != "" {
+			statements = append(statements, strings.TrimSpace(statement))
+		}
+	}
+
+	if len(statements) == 0 {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: "No CQL statement found in query",
+				Code:    "INVALID_QUERY",
+			},
+		}
+	}
+
+	var resultData interface{}
+	var rowsAffected int64
+	var err error
+
+	if len(statements) == 1 {
+		statement := statements[0]
+		statementType := cassandraStatementType(statement)
+
+		var rows []map[string]interface{}
+		if baseQuery, offset, paginated := splitCassandraOffset(statement); paginated && statementType == "SELECT" {
+			// CQL has no OFFSET, resume from the paging state of the previous page instead
+			rows, err = fetchCassandraPage(ctx, wrapper, baseQuery, offset)
+		} else {
+			iter := wrapper.Session.Query(statement).WithContext(ctx).Iter()
+			rows, err = iter.SliceMap()
+			if closeErr := iter.Close(); err == nil {
+				err = closeErr
+			}
+		}
+
+		if err == nil {
+			for i := range rows {
+				rows[i] = normalizeCassandraRow(rows[i])
+			}
+
+			if statementType == "SELECT" {
+				resultData = map[string]interface{}{
+					"results": rows,
+				}
+			} else {
+				if statementType == "INSERT" || statementType == "UPDATE" || statementType == "DELETE" {
+					rowsAffected = 1 // Cassandra doesn't report affected rows, writes are upserts
+				}
+				message := map[string]interface{}{
+					"message": fmt.Sprintf("%s executed successfully", statementType),
+				}
+				// Lightweight transactions (IF ...) return an [applied] row
+				if len(rows) > 0 {
+					message["result"] = rows
+				}
+				resultData = message
+			}
+		}
+	} else {
+		allBatchable := true
+		for _, statement := range statements {
+			if !isCassandraBatchable(statement) {
+				allBatchable = false
+				break
+			}
+		}
+
+		if allBatchable {
+			// Apply all writes atomically
+			batch := wrapper.Session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+			for _, statement := range statements {
+				batch.Query(statement)
+			}
+			err = wrapper.Session.ExecuteBatch(batch)
+		} else {
+			// Schema changes and reads can't be batched, execute them one by one
+			for _, statement := range statements {
+				if cassandraStatementType(statement) == "SELECT" {
+					err = fmt.Errorf("SELECT statements can't be combined with other statements, execute them separately")
+					break
+				}
+				if err = wrapper.Session.Query(statement).WithContext(ctx).Exec(); err != nil {
+					break
+				}
+			}
+		}
+
+		if err == nil {
+			rowsAffected = int64(len(statements))
+			resultData = map[string]interface{}{
+				"message": fmt.Sprintf("%d statements executed successfully", len(statements)),
+			}
+		}
+	}
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return &QueryExecutionResult{
+				Error: &dtos.QueryError{
+					Message: "Query execution cancelled",
+					Code:    "EXECUTION_CANCELLED",
+				},
+			}
+		}
+		log.Printf("CassandraDriver -> executeCassandraQuery -> Error executing query: %v", err)
+		return &QueryExecutionResult{
+			ExecutionTime: int(time.Since(startTime).Milliseconds()),
+			Error: &dtos.QueryError{
+				Message: err.Error(),
+				Code:    "EXECUTION_ERROR",
+			},
+		}
+	}
+
+	executionTime := int(time.Since(startTime).Milliseconds())
+
+	// Marshal the result to JSON
+	resultJSON, err := json.Marshal(resultData)
+	if err != nil {
+		return &QueryExecutionResult{
+			ExecutionTime: executionTime,
+			Error: &dtos.QueryError{
+				Code:    "JSON_MARSHAL_FAILED",
+				Message: err.Error(),
+				Details: "Failed to marshal query results",
+			},
+		}
+	}
+
+	return &QueryExecutionResult{
+		Result:        resultData,
+		ExecutionTime: executionTime,
+		RowsAffected:  rowsAffected,
+		StreamData:    resultJSON,
+	}
+}
+
+// fetchCassandraPage returns cassandraPageSize rows starting at offset, resuming from a cached paging state when possible
+func fetchCassandraPage(ctx context.Context, wrapper *CassandraWrapper, query string, offset int) ([]map[string]interface{}, error) {
+	pageState, consumed := wrapper.PageStates.Get(query, offset)
+	log.Printf("CassandraDriver -> fetchCassandraPage -> Offset: %d, resuming from row: %d", offset, consumed)
+
+	rows := make([]map[string]interface{}, 0, cassandraPageSize)
+	for {
+		iter := wrapper.Session.Query(query).WithContext(ctx).PageSize(cassandraPageSize).PageState(pageState).Iter()
+		nextPageState := iter.PageState()
+
+		pageRows, err := iter.SliceMap()
+		if closeErr := iter.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Skip rows before the requested offset
+		pageStart := consumed
+		for i, row := range pageRows {
+			if pageStart+i >= offset && len(rows) < cassandraPageSize {
+				rows = append(rows, row)
+			}
+		}
+		consumed += len(pageRows)
+		wrapper.PageStates.Set(query, consumed, nextPageState)
+
+		if len(rows) >= cassandraPageSize || len(nextPageState) == 0 {
+			break
+		}
+		pageState = nextPageState
+	}
+
+	return rows, nil
+}
+
+// StreamQuery returns a cursor over the rows of ExecuteQuery, the result is read as a whole
+func (d *CassandraDriver) StreamQuery(ctx context.Context, conn *Connection, query string) (ResultCursor, error) {
+	return newBufferedCursor(d.ExecuteQuery(ctx, conn, query, "", false))
+}
+
+// BeginTx begins a Cassandra transaction
+func (d *CassandraDriver) BeginTx(ctx context.Context, conn *Connection) Transaction {
+	log.Printf("CassandraDriver -> BeginTx -> Beginning Cassandra transaction")
+
+	wrapper, ok := conn.MongoDBObj.(*CassandraWrapper)
+	if !ok || wrapper.Session == nil {
+		log.Printf("CassandraDriver -> BeginTx -> Invalid Cassandra connection, type: %T", conn.MongoDBObj)
+		return &CassandraTransaction{
+			Error: fmt.Errorf("invalid Cassandra connection, try disconnecting and reconnecting"),
+		}
+	}
+
+	return &CassandraTransaction{
+		Wrapper: wrapper,
+	}
+}
+
+// GetSchema retrieves the schema of the connected keyspace
+func (d *CassandraDriver) GetSchema(ctx context.Context, db DBExecutor, selectedTables []string) (*SchemaInfo, error) {
+	fetcher := NewCassandraSchemaFetcher(db)
+	return fetcher.GetSchema(ctx, db, selectedTables)
+}
+
+// GetTableChecksum calculates a checksum for a Cassandra table
+func (d *CassandraDriver) GetTableChecksum(ctx context.Context, db DBExecutor, table string) (string, error) {
+	fetcher := NewCassandraSchemaFetcher(db)
+	return fetcher.GetTableChecksum(ctx, db, table)
+}
+
+// FetchExampleRecords fetches example records from a Cassandra table
+func (d *CassandraDriver) FetchExampleRecords(ctx context.Context, db DBExecutor, table string, limit int) ([]map[string]interface{}, error) {
+	fetcher := NewCassandraSchemaFetcher(db)
+	return fetcher.FetchExampleRecords(ctx, db, table, limit)
+}
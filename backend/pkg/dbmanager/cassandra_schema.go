@@ -0,0 +1,380 @@
+package dbmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"neobase-ai/internal/utils"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// CassandraSchemaFetcher implements SchemaFetcher for Cassandra/ScyllaDB using system_schema
+type CassandraSchemaFetcher struct {
+	db DBExecutor
+}
+
+// NewCassandraSchemaFetcher creates a new Cassandra schema fetcher
+func NewCassandraSchemaFetcher(db DBExecutor) SchemaFetcher {
+	return &CassandraSchemaFetcher{
+		db: db,
+	}
+}
+
+// GetSchema reads tables, partition/clustering keys and indexes of the connected keyspace
+func (f *CassandraSchemaFetcher) GetSchema(ctx context.Context, db DBExecutor, selectedTables []string) (*SchemaInfo, error) {
+	log.Printf("CassandraSchemaFetcher -> GetSchema -> Starting schema fetch with selected tables: %v", selectedTables)
+
+	executor, ok := db.(*CassandraExecutor)
+	if !ok {
+		return nil, fmt.Errorf("invalid Cassandra executor")
+	}
+	session := executor.wrapper.Session
+	keyspace := executor.wrapper.Keyspace
+	if keyspace == "" {
+		return nil, fmt.Errorf("keyspace is required to fetch the Cassandra schema")
+	}
+
+	tables, err := f.fetchTables(ctx, session, keyspace)
+	if err != nil {
+		log.Printf("CassandraSchemaFetcher -> GetSchema -> Error fetching tables: %v", err)
+		return nil, fmt.Errorf("failed to fetch tables: %v", err)
+	}
+
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("CassandraSchemaFetcher -> GetSchema -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	if err := f.fetchColumns(ctx, session, keyspace, tables); err != nil {
+		log.Printf("CassandraSchemaFetcher -> GetSchema -> Error fetching columns: %v", err)
+		return nil, fmt.Errorf("failed to fetch columns: %v", err)
+	}
+
+	if err := f.fetchIndexes(ctx, session, keyspace, tables); err != nil {
+		// Secondary indexes are optional, don't fail the whole schema
+		log.Printf("CassandraSchemaFetcher -> GetSchema -> Error fetching indexes: %v", err)
+	}
+
+	views, err := f.fetchViews(ctx, session, keyspace)
+	if err != nil {
+		log.Printf("CassandraSchemaFetcher -> GetSchema -> Error fetching materialized views: %v", err)
+		views = make(map[string]ViewSchema)
+	}
+
+	// Size estimates are approximate, COUNT(*) would require a full cluster scan
+	rowCounts, sizes := f.fetchSizeEstimates(ctx, session, keyspace)
+
+	selectAll := len(selectedTables) == 0 || (len(selectedTables) == 1 && selectedTables[0] == "ALL")
+	selected := make(map[string]bool, len(selectedTables))
+	for _, table := range selectedTables {
+		selected[table] = true
+	}
+
+	schema := &SchemaInfo{
+		Tables:    make(map[string]TableSchema),
+		Views:     views,
+		UpdatedAt: time.Now(),
+	}
+	for name, table := range tables {
+		if !selectAll && !selected[name] {
+			continue
+		}
+		tableSchema := f.convertToTableSchema(table)
+		tableSchema.RowCount = rowCounts[name]
+		tableSchema.SizeBytes = sizes[name]
+		schema.Tables[name] = tableSchema
+	}
+
+	log.Printf("CassandraSchemaFetcher -> GetSchema -> Fetched %d tables from keyspace %s", len(schema.Tables), keyspace)
+	return schema, nil
+}
+
+// fetchTables reads the tables of a keyspace
+func (f *CassandraSchemaFetcher) fetchTables(ctx context.Context, session *gocql.Session, keyspace string) (map[string]*CassandraTable, error) {
+	tables := make(map[string]*CassandraTable)
+
+	iter := session.Query(`SELECT table_name, comment FROM system_schema.tables WHERE keyspace_name = ?`, keyspace).WithContext(ctx).Iter()
+	var name, comment string
+	for iter.Scan(&name, &comment) {
+		tables[name] = &CassandraTable{
+			Name:    name,
+			Comment: comment,
+			Indexes: make(map[string]IndexInfo),
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+// fetchColumns reads the columns of every table in the keyspace with their key kind and position
+func (f *CassandraSchemaFetcher) fetchColumns(ctx context.Context, session *gocql.Session, keyspace string, tables map[string]*CassandraTable) error {
+	iter := session.Query(`SELECT table_name, column_name, kind, position, clustering_order, type FROM system_schema.columns WHERE keyspace_name = ?`, keyspace).WithContext(ctx).Iter()
+
+	var tableName string
+	var column CassandraColumn
+	for iter.Scan(&tableName, &column.Name, &column.Kind, &column.Position, &column.ClusteringOrder, &column.Type) {
+		table, exists := tables[tableName]
+		if !exists {
+			// Columns of materialized views are listed too
+			continue
+		}
+		table.Columns = append(table.Columns, column)
+		column = CassandraColumn{}
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	// Order primary key columns by their position
+	for _, table := range tables {
+		sort.SliceStable(table.Columns, func(i, j int) bool {
+			if table.Columns[i].Kind != table.Columns[j].Kind {
+				return cassandraKindOrder(table.Columns[i].Kind) < cassandraKindOrder(table.Columns[j].Kind)
+			}
+			if table.Columns[i].Position != table.Columns[j].Position {
+				return table.Columns[i].Position < table.Columns[j].Position
+			}
+			return table.Columns[i].Name < table.Columns[j].Name
+		})
+
+		table.PartitionKeys = nil
+		table.ClusteringKeys = nil
+		for _, col := range table.Columns {
+			switch col.Kind {
+			case "partition_key":
+				table.PartitionKeys = append(table.PartitionKeys, col.Name)
+			case "clustering":
+				table.ClusteringKeys = append(table.ClusteringKeys, col.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// cassandraKindOrder orders columns as partition key, clustering, static and regular
+func cassandraKindOrder(kind string) int {
+	switch kind {
+	case "partition_key":
+		return 0
+	case "clustering":
+		return 1
+	case "static":
+		return 2
+	default:
+		return 3
+	}
+}
+
+// fetchIndexes reads secondary indexes of the keyspace
+func (f *CassandraSchemaFetcher) fetchIndexes(ctx context.Context, session *gocql.Session, keyspace string, tables map[string]*CassandraTable) error {
+	iter := session.Query(`SELECT table_name, index_name, options FROM system_schema.indexes WHERE keyspace_name = ?`, keyspace).WithContext(ctx).Iter()
+
+	var tableName, indexName string
+	var options map[string]string
+	for iter.Scan(&tableName, &indexName, &options) {
+		table, exists := tables[tableName]
+		if !exists {
+			continue
+		}
+
+		// The target is the indexed column, possibly wrapped like keys(col), values(col) or entries(col)
+		target := options["target"]
+		if open := strings.Index(target, "("); open != -1 && strings.HasSuffix(target, ")") {
+			target = target[open+1 : len(target)-1]
+		}
+		target = strings.Trim(target, `"`)
+
+		table.Indexes[indexName] = IndexInfo{
+			Name:    indexName,
+			Columns: []string{target},
+		}
+		options = nil
+	}
+	return iter.Close()
+}
+
+// fetchViews reads the materialized views of the keyspace
+func (f *CassandraSchemaFetcher) fetchViews(ctx context.Context, session *gocql.Session, keyspace string) (map[string]ViewSchema, error) {
+	views := make(map[string]ViewSchema)
+
+	iter := session.Query(`SELECT view_name, base_table_name, where_clause FROM system_schema.views WHERE keyspace_name = ?`, keyspace).WithContext(ctx).Iter()
+	var viewName, baseTable, whereClause string
+	for iter.Scan(&viewName, &baseTable, &whereClause) {
+		views[viewName] = ViewSchema{
+			Name:       viewName,
+			Definition: fmt.Sprintf("SELECT * FROM %s WHERE %s", baseTable, whereClause),
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+// fetchSizeEstimates reads estimated partition counts and sizes from system.size_estimates
+func (f *CassandraSchemaFetcher) fetchSizeEstimates(ctx context.Context, session *gocql.Session, keyspace string) (map[string]int64, map[string]int64) {
+	rowCounts := make(map[string]int64)
+	sizes := make(map[string]int64)
+
+	iter := session.Query(`SELECT table_name, partitions_count, mean_partition_size FROM system.size_estimates WHERE keyspace_name = ?`, keyspace).WithContext(ctx).Iter()
+	var tableName string
+	var partitionsCount, meanPartitionSize int64
+	for iter.Scan(&tableName, &partitionsCount, &meanPartitionSize) {
+		rowCounts[tableName] += partitionsCount
+		sizes[tableName] += partitionsCount * meanPartitionSize
+	}
+	if err := iter.Close(); err != nil {
+		log.Printf("CassandraSchemaFetcher -> fetchSizeEstimates -> Size estimates not available: %v", err)
+	}
+	return rowCounts, sizes
+}
+
+// convertToTableSchema converts a Cassandra table into the common TableSchema
+func (f *CassandraSchemaFetcher) convertToTableSchema(table *CassandraTable) TableSchema {
+	tableSchema := TableSchema{
+		Name:        table.Name,
+		Columns:     make(map[string]ColumnInfo),
+		Indexes:     make(map[string]IndexInfo),
+		ForeignKeys: make(map[string]ForeignKey),
+		Constraints: make(map[string]ConstraintInfo),
+	}
+
+	clusteringOrders := make([]string, 0, len(table.ClusteringKeys))
+	for _, col := range table.Columns {
+		comment := ""
+		switch col.Kind {
+		case "partition_key":
+			comment = fmt.Sprintf("Partition key (position %d), must be filtered with = or IN", col.Position+1)
+		case "clustering":
+			order := strings.ToUpper(col.ClusteringOrder)
+			comment = fmt.Sprintf("Clustering key (position %d, %s), can be range filtered after the partition key", col.Position+1, order)
+			clusteringOrders = append(clusteringOrders, fmt.Sprintf("%s %s", col.Name, order))
+		case "static":
+			comment = "Static column, shared by all rows of a partition"
+		}
+
+		tableSchema.Columns[col.Name] = ColumnInfo{
+			Name:       col.Name,
+			Type:       col.Type,
+			IsNullable: col.Kind != "partition_key" && col.Kind != "clustering",
+			Comment:    comment,
+		}
+	}
+
+	// Partition and clustering keys are the only columns that can be filtered without ALLOW FILTERING
+	primaryKey := append(append([]string{}, table.PartitionKeys...), table.ClusteringKeys...)
+	if len(primaryKey) > 0 {
+		definition := fmt.Sprintf("PRIMARY KEY ((%s)", strings.Join(table.PartitionKeys, ", "))
+		if len(table.ClusteringKeys) > 0 {
+			definition += ", " + strings.Join(table.ClusteringKeys, ", ")
+		}
+		definition += ")"
+
+		tableSchema.Constraints["primary_key"] = ConstraintInfo{
+			Name:       "primary_key",
+			Type:       "PRIMARY KEY",
+			Definition: definition,
+			Columns:    primaryKey,
+		}
+		tableSchema.Indexes["primary_key"] = IndexInfo{
+			Name:     "primary_key",
+			Columns:  primaryKey,
+			IsUnique: true,
+		}
+	}
+	for name, index := range table.Indexes {
+		tableSchema.Indexes[name] = index
+	}
+
+	// Describe the access pattern in the table comment so the LLM can avoid ALLOW FILTERING
+	var description []string
+	if len(table.PartitionKeys) > 0 {
+		description = append(description, fmt.Sprintf("Partition key: (%s)", strings.Join(table.PartitionKeys, ", ")))
+	}
+	if len(clusteringOrders) > 0 {
+		description = append(description, fmt.Sprintf("Clustering key: %s", strings.Join(clusteringOrders, ", ")))
+	}
+	if len(table.Indexes) > 0 {
+		indexNames := make([]string, 0, len(table.Indexes))
+		for _, index := range table.Indexes {
+			indexNames = append(indexNames, strings.Join(index.Columns, ", "))
+		}
+		sort.Strings(indexNames)
+		description = append(description, fmt.Sprintf("Secondary indexes on: %s", strings.Join(indexNames, "; ")))
+	}
+	if table.Comment != "" {
+		description = append(description, table.Comment)
+	}
+	tableSchema.Comment = strings.Join(description, ". ")
+
+	return tableSchema
+}
+
+// GetTableChecksum calculates a checksum for a Cassandra table from its columns, keys and indexes
+func (f *CassandraSchemaFetcher) GetTableChecksum(ctx context.Context, db DBExecutor, table string) (string, error) {
+	schema, err := f.GetSchema(ctx, db, []string{table})
+	if err != nil {
+		return "", err
+	}
+
+	tableSchema, exists := schema.Tables[table]
+	if !exists {
+		return "", fmt.Errorf("table %s not found", table)
+	}
+
+	columnNames := make([]string, 0, len(tableSchema.Columns))
+	for name := range tableSchema.Columns {
+		columnNames = append(columnNames, name)
+	}
+	sort.Strings(columnNames)
+
+	columnsChecksum := ""
+	for _, name := range columnNames {
+		col := tableSchema.Columns[name]
+		columnsChecksum += fmt.Sprintf("%s:%s:%s,", name, col.Type, col.Comment)
+	}
+
+	indexNames := make([]string, 0, len(tableSchema.Indexes))
+	for name, index := range tableSchema.Indexes {
+		indexNames = append(indexNames, fmt.Sprintf("%s:%s", name, strings.Join(index.Columns, ",")))
+	}
+	sort.Strings(indexNames)
+
+	return utils.MD5Hash(fmt.Sprintf("%s:%s:%s", table, columnsChecksum, strings.Join(indexNames, ";"))), nil
+}
+
+// FetchExampleRecords fetches example records from a Cassandra table
+func (f *CassandraSchemaFetcher) FetchExampleRecords(ctx context.Context, db DBExecutor, table string, limit int) ([]map[string]interface{}, error) {
+	// Ensure limit is reasonable
+	if limit <= 0 {
+		limit = 3
+	} else if limit > 10 {
+		limit = 10 // Cap at 10 records to avoid large data transfers
+	}
+
+	executor, ok := db.(*CassandraExecutor)
+	if !ok {
+		return nil, fmt.Errorf("invalid Cassandra executor")
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s.%s LIMIT %d",
+		quoteCassandraIdentifier(executor.wrapper.Keyspace), quoteCassandraIdentifier(table), limit)
+
+	var records []map[string]interface{}
+	if err := executor.QueryRows(query, &records); err != nil {
+		log.Printf("CassandraSchemaFetcher -> FetchExampleRecords -> Error fetching records from %s: %v", table, err)
+		return nil, fmt.Errorf("failed to fetch example records for table %s: %v", table, err)
+	}
+
+	if records == nil {
+		return []map[string]interface{}{}, nil
+	}
+	return records, nil
+}
@@ -0,0 +1,84 @@
+package dbmanager
+
+import (
+	"strings"
+)
+
+// CassandraSimplifier implements the SchemaSimplifier interface for Cassandra/ScyllaDB
+type CassandraSimplifier struct{}
+
+// SimplifyDataType converts CQL data types to simplified versions for LLM
+func (s *CassandraSimplifier) SimplifyDataType(dbType string) string {
+	lowerType := strings.ToLower(strings.TrimSpace(dbType))
+
+	// Unwrap frozen<...>
+	if strings.HasPrefix(lowerType, "frozen<") && strings.HasSuffix(lowerType, ">") {
+		lowerType = lowerType[7 : len(lowerType)-1]
+	}
+
+	// Collection types
+	switch {
+	case strings.HasPrefix(lowerType, "list<"):
+		return "list"
+	case strings.HasPrefix(lowerType, "set<"):
+		return "set"
+	case strings.HasPrefix(lowerType, "map<"):
+		return "map"
+	case strings.HasPrefix(lowerType, "tuple<"):
+		return "tuple"
+	case strings.HasPrefix(lowerType, "vector<"):
+		return "vector"
+	}
+
+	switch lowerType {
+	case "tinyint", "smallint", "int", "bigint", "varint", "counter":
+		return "integer"
+	case "float", "double", "decimal":
+		return "number"
+	case "text", "varchar", "ascii":
+		return "text"
+	case "boolean":
+		return "boolean"
+	case "timestamp", "date", "time":
+		return "datetime"
+	case "uuid", "timeuuid":
+		return "uuid"
+	case "blob":
+		return "binary"
+	case "inet":
+		return "ip"
+	case "duration":
+		return "duration"
+	default:
+		// User defined types
+		return lowerType
+	}
+}
+
+// GetColumnConstraints returns the key role of a Cassandra column
+func (s *CassandraSimplifier) GetColumnConstraints(col ColumnInfo, table TableSchema) []string {
+	constraints := []string{}
+
+	switch {
+	case strings.HasPrefix(col.Comment, "Partition key"):
+		constraints = append(constraints, "PARTITION KEY")
+	case strings.HasPrefix(col.Comment, "Clustering key"):
+		constraints = append(constraints, "CLUSTERING KEY")
+	case strings.HasPrefix(col.Comment, "Static column"):
+		constraints = append(constraints, "STATIC")
+	}
+
+	// Columns with a secondary index can be filtered without ALLOW FILTERING
+	for name, index := range table.Indexes {
+		if name == "primary_key" {
+			continue
+		}
+		for _, indexedCol := range index.Columns {
+			if indexedCol == col.Name {
+				constraints = append(constraints, "INDEXED")
+			}
+		}
+	}
+
+	return constraints
+}
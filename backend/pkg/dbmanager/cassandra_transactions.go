@@ -0,0 +1,53 @@
+package dbmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+)
+
+// CassandraTransaction implements the Transaction interface for Cassandra
+// Cassandra has no interactive transactions, multiple writes are applied atomically as a LOGGED BATCH inside ExecuteQuery
+type CassandraTransaction struct {
+	Wrapper *CassandraWrapper
+	Error   error
+}
+
+// ExecuteQuery executes a CQL query within the transaction
+func (tx *CassandraTransaction) ExecuteQuery(ctx context.Context, query string) (*QueryExecutionResult, error) {
+	if tx.Error != nil {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: tx.Error.Error(),
+				Code:    "TRANSACTION_ERROR",
+			},
+		}, nil
+	}
+
+	if tx.Wrapper == nil || tx.Wrapper.Session == nil {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: "No active Cassandra session",
+				Code:    "TRANSACTION_ERROR",
+			},
+		}, nil
+	}
+
+	return executeCassandraQuery(ctx, tx.Wrapper, query), nil
+}
+
+// Commit commits the Cassandra transaction, statements are already applied by ExecuteQuery
+func (tx *CassandraTransaction) Commit() error {
+	if tx.Error != nil {
+		return fmt.Errorf("cannot commit transaction: %v", tx.Error)
+	}
+	return nil
+}
+
+// Rollback rolls back the Cassandra transaction
+func (tx *CassandraTransaction) Rollback() error {
+	// A failed batch is never applied, already applied statements can't be undone
+	log.Printf("CassandraTransaction -> Rollback -> Cassandra does not support rolling back applied statements")
+	return nil
+}
@@ -0,0 +1,87 @@
+package dbmanager
+
+import (
+	"sync"
+
+	"github.com/gocql/gocql"
+)
+
+const (
+	cassandraPageSize         = 50  // Rows returned per page, matches the LIMIT 50 used by other drivers
+	cassandraMaxCachedQueries = 100 // Max queries whose paging states are kept per connection
+)
+
+// CassandraWrapper wraps the gocql session of a Cassandra/ScyllaDB connection
+type CassandraWrapper struct {
+	Session    *gocql.Session
+	Keyspace   string
+	PageStates *CassandraPageStateCache
+}
+
+// CassandraPageStateCache keeps paging states of paginated queries so that the next page
+// can resume from the server side cursor instead of re-reading all previous rows
+type CassandraPageStateCache struct {
+	mu     sync.Mutex
+	states map[string]map[int][]byte // query -> row offset -> paging state
+}
+
+// NewCassandraPageStateCache creates an empty paging state cache
+func NewCassandraPageStateCache() *CassandraPageStateCache {
+	return &CassandraPageStateCache{
+		states: make(map[string]map[int][]byte),
+	}
+}
+
+// Get returns the closest paging state at or before the given row offset, and the offset it resumes from
+func (c *CassandraPageStateCache) Get(query string, offset int) ([]byte, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var state []byte
+	resumeFrom := 0
+	for stateOffset, pageState := range c.states[query] {
+		if stateOffset <= offset && stateOffset > resumeFrom {
+			state = pageState
+			resumeFrom = stateOffset
+		}
+	}
+	return state, resumeFrom
+}
+
+// Set stores the paging state that resumes the query at the given row offset
+func (c *CassandraPageStateCache) Set(query string, offset int, state []byte) {
+	if len(state) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.states[query]; !exists {
+		// Drop everything when the cache grows too large, paging states are cheap to rebuild
+		if len(c.states) >= cassandraMaxCachedQueries {
+			c.states = make(map[string]map[int][]byte)
+		}
+		c.states[query] = make(map[int][]byte)
+	}
+	c.states[query][offset] = append([]byte(nil), state...)
+}
+
+// CassandraColumn represents a column read from system_schema.columns
+type CassandraColumn struct {
+	Name            string
+	Type            string
+	Kind            string // partition_key, clustering, regular, static
+	Position        int
+	ClusteringOrder string
+}
+
+// CassandraTable represents a table read from system_schema.tables
+type CassandraTable struct {
+	Name           string
+	Comment        string
+	Columns        []CassandraColumn
+	PartitionKeys  []string
+	ClusteringKeys []string
+	Indexes        map[string]IndexInfo
+}
@@ -0,0 +1,154 @@
+package dbmanager
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// cassandraOffsetClause matches the trailing "OFFSET <n>" that NeoBase uses in paginated CQL queries,
+// CQL has no OFFSET so it is translated to paging states by the driver
+var cassandraOffsetClause = regexp.MustCompile(`(?is)^(.*?)\s+OFFSET\s+(\d+)\s*;?\s*$`)
+
+// splitCassandraOffset strips the NeoBase OFFSET clause from a query, returns the base query and the row offset
+func splitCassandraOffset(query string) (string, int, bool) {
+	matches := cassandraOffsetClause.FindStringSubmatch(strings.TrimSpace(query))
+	if matches == nil {
+		return query, 0, false
+	}
+
+	offset, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return query, 0, false
+	}
+	return strings.TrimSpace(matches[1]), offset, true
+}
+
+// cassandraStatementType returns the upper-cased first keyword of a CQL statement
+func cassandraStatementType(statement string) string {
+	fields := strings.Fields(strings.TrimSpace(statement))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
+
+// isCassandraBatchable reports whether a statement can be part of a BATCH
+func isCassandraBatchable(statement string) bool {
+	switch cassandraStatementType(statement) {
+	case "INSERT", "UPDATE", "DELETE":
+		return true
+	default:
+		return false
+	}
+}
+
+// isCassandraDDL reports whether a statement changes the schema
+func isCassandraDDL(statement string) bool {
+	switch cassandraStatementType(statement) {
+	case "CREATE", "ALTER", "DROP", "TRUNCATE":
+		return true
+	default:
+		return false
+	}
+}
+
+// stripCassandraBatch unwraps a "BEGIN BATCH ... APPLY BATCH" block into its statements
+func stripCassandraBatch(statements []string) []string {
+	result := make([]string, 0, len(statements))
+	for _, statement := range statements {
+		trimmed := strings.TrimSpace(statement)
+		upper := strings.ToUpper(trimmed)
+
+		for _, prefix := range []string{"BEGIN UNLOGGED BATCH", "BEGIN LOGGED BATCH", "BEGIN COUNTER BATCH", "BEGIN BATCH"} {
+			if strings.HasPrefix(upper, prefix) {
+				trimmed = strings.TrimSpace(trimmed[len(prefix):])
+				upper = strings.ToUpper(trimmed)
+				break
+			}
+		}
+		if upper == "APPLY BATCH" {
+			continue
+		}
+		if trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
+
+// normalizeCassandraValue converts gocql values into JSON friendly values
+func normalizeCassandraValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case gocql.UUID:
+		return v.String()
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+		return v.UTC().Format(time.RFC3339Nano)
+	case gocql.Duration:
+		return fmt.Sprintf("%dmo%dd%dns", v.Months, v.Days, v.Nanoseconds)
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized[i] = normalizeCassandraValue(item)
+		}
+		return normalized
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized[key] = normalizeCassandraValue(item)
+		}
+		return normalized
+	case string, bool, int, int8, int16, int32, int64, float32, float64:
+		return v
+	case fmt.Stringer:
+		// varint (*big.Int), decimal (*inf.Dec), inet, durations
+		if reflectIsNilPointer(v) {
+			return nil
+		}
+		return v.String()
+	default:
+		// Typed collections (list<text>, map<int, text>, ...) marshal as is, anything else is rendered as text
+		if _, err := json.Marshal(v); err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return v
+	}
+}
+
+// reflectIsNilPointer reports whether an interface holds a typed nil pointer
+func reflectIsNilPointer(value interface{}) bool {
+	rv := reflect.ValueOf(value)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// normalizeCassandraRow converts every value in a row into a JSON friendly value
+func normalizeCassandraRow(row map[string]interface{}) map[string]interface{} {
+	for key, value := range row {
+		row[key] = normalizeCassandraValue(value)
+	}
+	return row
+}
+
+// quoteCassandraIdentifier quotes an identifier if it is not a plain lower-case name
+func quoteCassandraIdentifier(identifier string) string {
+	for _, r := range identifier {
+		if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '_' {
+			return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+		}
+	}
+	return identifier
+}
@@ -0,0 +1,99 @@
+package dbmanager
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/gocql/gocql"
+)
+
+// CassandraExecutor implements the DBExecutor interface for Cassandra
+type CassandraExecutor struct {
+	wrapper *CassandraWrapper
+	conn    *Connection
+}
+
+// NewCassandraExecutor creates a new Cassandra executor
+func NewCassandraExecutor(conn *Connection) (*CassandraExecutor, error) {
+	wrapper, ok := conn.MongoDBObj.(*CassandraWrapper)
+	if !ok || wrapper == nil {
+		return nil, fmt.Errorf("invalid Cassandra connection")
+	}
+
+	return &CassandraExecutor{
+		wrapper: wrapper,
+		conn:    conn,
+	}, nil
+}
+
+// GetDB returns nil for Cassandra as it doesn't use sql.DB
+func (e *CassandraExecutor) GetDB() *sql.DB {
+	return nil
+}
+
+// GetSession returns the underlying gocql session
+func (e *CassandraExecutor) GetSession() *gocql.Session {
+	return e.wrapper.Session
+}
+
+// Close closes the Cassandra executor
+func (e *CassandraExecutor) Close() error {
+	// Session is managed by the Cassandra driver
+	return nil
+}
+
+// Exec executes a CQL statement
+func (e *CassandraExecutor) Exec(query string, values ...interface{}) error {
+	log.Printf("CassandraExecutor -> Exec -> Query: %s", query)
+
+	if err := e.wrapper.Session.Query(query, values...).Exec(); err != nil {
+		return fmt.Errorf("failed to execute CQL statement: %v", err)
+	}
+	return nil
+}
+
+// Raw executes a raw CQL statement
+func (e *CassandraExecutor) Raw(query string, values ...interface{}) error {
+	return e.Exec(query, values...)
+}
+
+// Query executes a CQL query and scans the rows into dest
+func (e *CassandraExecutor) Query(query string, dest interface{}, values ...interface{}) error {
+	destMap, ok := dest.(*[]map[string]interface{})
+	if !ok {
+		return fmt.Errorf("destination must be *[]map[string]interface{}")
+	}
+	return e.QueryRows(query, destMap, values...)
+}
+
+// QueryRows executes a CQL query and returns the rows
+func (e *CassandraExecutor) QueryRows(query string, dest *[]map[string]interface{}, values ...interface{}) error {
+	iter := e.wrapper.Session.Query(query, values...).Iter()
+	rows, err := iter.SliceMap()
+	if closeErr := iter.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to execute CQL query: %v", err)
+	}
+
+	for i := range rows {
+		rows[i] = normalizeCassandraRow(rows[i])
+	}
+	*dest = rows
+	return nil
+}
+
+// GetSchema fetches the schema of the connected keyspace
+func (e *CassandraExecutor) GetSchema(ctx context.Context) (*SchemaInfo, error) {
+	driver := &CassandraDriver{}
+	return driver.GetSchema(ctx, e, []string{"ALL"})
+}
+
+// GetTableChecksum calculates a checksum for a Cassandra table
+func (e *CassandraExecutor) GetTableChecksum(ctx context.Context, table string) (string, error) {
+	driver := &CassandraDriver{}
+	return driver.GetTableChecksum(ctx, e, table)
+}
@@ -126,6 +126,10 @@ func (d *ClickHouseDriver) Connect(config ConnectionConfig) (*Connection, error)
 
 	// Add parameters
 	dsn += "?dial_timeout=10s&read_timeout=20s"
+	if config.ReadOnly {
+		// Only reads are allowed and the session's settings can't be changed
+		dsn += "&readonly=1"
+	}
 
 	// Create ClickHouse connection
 	options := &clickhousedriver.Config{
@@ -399,6 +403,11 @@ func (d *ClickHouseDriver) ExecuteQuery(ctx context.Context, conn *Connection, q
 	return result
 }
 
+// StreamQuery runs a read query and returns a cursor over its rows, ClickHouse reads the result block by block
+func (d *ClickHouseDriver) StreamQuery(ctx context.Context, conn *Connection, query string) (ResultCursor, error) {
+	return streamSQLQuery(ctx, conn, query, nil, nil)
+}
+
 // BeginTx starts a new transaction
 func (d *ClickHouseDriver) BeginTx(ctx context.Context, conn *Connection) Transaction {
 	if conn == nil || conn.DB == nil {
@@ -0,0 +1,198 @@
+package dbmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"strings"
+)
+
+// clickhouseIndexUsage is how a read of EXPLAIN indexes = 1 used the indexes of its table
+type clickhouseIndexUsage struct {
+	Table   string
+	Indexes []string // Indexes that skipped granules
+	Details []string
+	Pruned  bool
+}
+
+// ExplainQuery returns the plan of EXPLAIN PIPELINE, with the index usage of the reads from EXPLAIN indexes = 1.
+// ClickHouse has no EXPLAIN ANALYZE.
+func (d *ClickHouseDriver) ExplainQuery(ctx context.Context, conn *Connection, query string, analyze bool) (*dtos.QueryPlan, error) {
+	if conn == nil || conn.DB == nil {
+		return nil, fmt.Errorf("connection is not initialized")
+	}
+	if analyze {
+		return nil, fmt.Errorf("ANALYZE is not supported for clickhouse, explain the query without it")
+	}
+
+	lines, err := clickhouseExplainLines(ctx, conn, "EXPLAIN PIPELINE "+query)
+	if err != nil {
+		return nil, err
+	}
+	root, err := parseClickHousePipeline(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only MergeTree tables report their index usage
+	if indexLines, err := clickhouseExplainLines(ctx, conn, "EXPLAIN indexes = 1 "+query); err != nil {
+		log.Printf("ClickHouseDriver -> ExplainQuery -> Error fetching index usage: %v", err)
+	} else {
+		applyClickHouseIndexUsage(root, parseClickHouseIndexUsage(indexLines))
+	}
+	return newQueryPlan(root, false, strings.Join(lines, "\n")), nil
+}
+
+// clickhouseExplainLines runs an EXPLAIN statement and returns its lines
+func clickhouseExplainLines(ctx context.Context, conn *Connection, statement string) ([]string, error) {
+	rows, err := conn.DB.WithContext(ctx).Raw(statement).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain query: %v", err)
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, fmt.Errorf("failed to read query plan: %v", err)
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read query plan: %v", err)
+	}
+	return lines, nil
+}
+
+// parseClickHousePipeline converts the lines of EXPLAIN PIPELINE. Steps are in parentheses, e.g. (ReadFromMergeTree),
+// and followed by their processors at the same indentation. Input steps are indented under the step reading them.
+func parseClickHousePipeline(lines []string) (*dtos.QueryPlanNode, error) {
+	type level struct {
+		indent int
+		node   *dtos.QueryPlanNode
+	}
+	var roots []*dtos.QueryPlanNode
+	var stack []level
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		isStep := strings.HasPrefix(trimmed, "(") && strings.HasSuffix(trimmed, ")")
+		if !isStep && len(stack) > 0 && stack[len(stack)-1].indent == indent {
+			top := stack[len(stack)-1].node
+			top.Details = append(top.Details, trimmed)
+			continue
+		}
+
+		node := &dtos.QueryPlanNode{Operation: trimmed}
+		if isStep {
+			node.Operation = trimmed[1 : len(trimmed)-1]
+		}
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, node)
+		} else {
+			parent := stack[len(stack)-1].node
+			parent.Children = append(parent.Children, node)
+		}
+		stack = append(stack, level{indent: indent, node: node})
+	}
+
+	switch len(roots) {
+	case 0:
+		return nil, fmt.Errorf("failed to parse query plan: no steps found")
+	case 1:
+		return roots[0], nil
+	}
+	return &dtos.QueryPlanNode{Operation: "Pipeline", Children: roots}, nil
+}
+
+// parseClickHouseIndexUsage returns the index usage of each ReadFromMergeTree of EXPLAIN indexes = 1, in plan order
+func parseClickHouseIndexUsage(lines []string) []*clickhouseIndexUsage {
+	var reads []*clickhouseIndexUsage
+	var read *clickhouseIndexUsage
+	var kind, name, condition string
+	var keys []string
+	keysIndent := -1
+
+	// Index kinds are followed by their keys or name, condition, parts and granules
+	flush := func(granules string) {
+		var selected, total int
+		if _, err := fmt.Sscanf(granules, "%d/%d", &selected, &total); err != nil {
+			return
+		}
+		label := kind
+		switch {
+		case name != "":
+			label = name
+		case len(keys) > 0:
+			label = fmt.Sprintf("%s (%s)", kind, strings.Join(keys, ", "))
+		}
+		detail := fmt.Sprintf("%s: granules %d/%d", label, selected, total)
+		if condition != "" {
+			detail += ", condition " + condition
+		}
+		read.Details = append(read.Details, detail)
+		if selected < total {
+			read.Indexes = append(read.Indexes, label)
+			read.Pruned = true
+		}
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if keysIndent != -1 {
+			if indent > keysIndent {
+				keys = append(keys, trimmed)
+				continue
+			}
+			keysIndent = -1
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "ReadFromMergeTree"):
+			read = &clickhouseIndexUsage{}
+			if open := strings.Index(trimmed, "("); open != -1 {
+				read.Table = strings.TrimSuffix(trimmed[open+1:], ")")
+			}
+			reads = append(reads, read)
+		case read == nil:
+			continue
+		case trimmed == "MinMax" || trimmed == "Partition" || trimmed == "PrimaryKey" || trimmed == "Skip":
+			kind, name, condition, keys = trimmed, "", "", nil
+		case trimmed == "Keys:":
+			keysIndent = indent
+		case strings.HasPrefix(trimmed, "Name: "):
+			name = strings.TrimPrefix(trimmed, "Name: ")
+		case strings.HasPrefix(trimmed, "Condition: "):
+			condition = strings.TrimPrefix(trimmed, "Condition: ")
+		case strings.HasPrefix(trimmed, "Granules: ") && kind != "":
+			flush(strings.TrimPrefix(trimmed, "Granules: "))
+		}
+	}
+	return reads
+}
+
+// applyClickHouseIndexUsage sets the table and index usage of the ReadFromMergeTree steps of the pipeline
+func applyClickHouseIndexUsage(root *dtos.QueryPlanNode, reads []*clickhouseIndexUsage) {
+	i := 0
+	walkQueryPlan(root, func(node *dtos.QueryPlanNode) {
+		if !strings.HasPrefix(node.Operation, "ReadFromMergeTree") || i >= len(reads) {
+			return
+		}
+		read := reads[i]
+		i++
+		node.Relation = read.Table
+		node.Index = strings.Join(read.Indexes, "; ")
+		node.FullScan = !read.Pruned
+		node.Details = append(node.Details, read.Details...)
+	})
+}
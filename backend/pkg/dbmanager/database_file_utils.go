@@ -0,0 +1,114 @@
+package dbmanager
+
+import (
+	"fmt"
+	"log"
+	"neobase-ai/internal/utils"
+	"os"
+	"strings"
+	"sync"
+)
+
+// DatabaseFileWrapper tracks the decrypted working copy of an uploaded SQLite/DuckDB file
+type DatabaseFileWrapper struct {
+	StoragePath string // Encrypted copy under DATABASE_FILES_DIR
+	WorkingPath string // Decrypted copy opened by the driver
+	Dirty       bool   // Set when committed writes have not been persisted yet
+	mu          sync.Mutex
+}
+
+// openDatabaseFile decrypts the stored database file into a private working copy
+func openDatabaseFile(config ConnectionConfig) (*DatabaseFileWrapper, error) {
+	if config.Host == "" {
+		return nil, fmt.Errorf("no database file has been uploaded for this connection")
+	}
+	if !utils.IsDatabaseFileStoragePath(config.Host) {
+		return nil, fmt.Errorf("invalid database file location")
+	}
+
+	data, err := utils.ReadEncryptedFile(config.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load database file: %v", err)
+	}
+
+	workingFile, err := os.CreateTemp("", fmt.Sprintf("neobase-*.%s", config.Type))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create working copy: %v", err)
+	}
+	if _, err := workingFile.Write(data); err != nil {
+		workingFile.Close()
+		os.Remove(workingFile.Name())
+		return nil, fmt.Errorf("failed to write working copy: %v", err)
+	}
+	if err := workingFile.Close(); err != nil {
+		os.Remove(workingFile.Name())
+		return nil, fmt.Errorf("failed to write working copy: %v", err)
+	}
+
+	return &DatabaseFileWrapper{
+		StoragePath: config.Host,
+		WorkingPath: workingFile.Name(),
+	}, nil
+}
+
+// persistFrom encrypts a consistent copy of the database and replaces the stored file
+func (w *DatabaseFileWrapper) persistFrom(snapshotPath string) error {
+	data, err := os.ReadFile(snapshotPath)
+	if err != nil {
+		return fmt.Errorf("failed to read database snapshot: %v", err)
+	}
+	if err := utils.WriteEncryptedFile(w.StoragePath, data); err != nil {
+		return err
+	}
+	w.Dirty = false
+	return nil
+}
+
+// removeWorkingFiles deletes the decrypted working copy along with its journal files
+func (w *DatabaseFileWrapper) removeWorkingFiles() {
+	for _, suffix := range []string{"", "-journal", "-wal", "-shm", ".wal", ".snapshot"} {
+		if err := os.Remove(w.WorkingPath + suffix); err != nil && !os.IsNotExist(err) {
+			log.Printf("DatabaseFileWrapper -> removeWorkingFiles -> Failed to remove %s: %v", w.WorkingPath+suffix, err)
+		}
+	}
+}
+
+// getDatabaseFileWrapper returns the file wrapper stored on a SQLite/DuckDB connection
+func getDatabaseFileWrapper(conn *Connection) (*DatabaseFileWrapper, error) {
+	wrapper, ok := conn.MongoDBObj.(*DatabaseFileWrapper)
+	if !ok || wrapper == nil {
+		return nil, fmt.Errorf("invalid database file connection")
+	}
+	return wrapper, nil
+}
+
+// findExampleOrderColumn picks a column that likely orders rows by recency, columns maps name to data type
+func findExampleOrderColumn(columns map[string]string) string {
+	timeColumns := []string{"updated_at", "modified_at", "update_time", "updated", "modified", "last_modified", "created_at", "creation_time", "create_time", "created", "timestamp"}
+	idColumns := []string{"id", "uuid", "record_id", "key"}
+
+	for _, candidate := range timeColumns {
+		for name, dataType := range columns {
+			lowerType := strings.ToLower(dataType)
+			if strings.EqualFold(name, candidate) && (strings.Contains(lowerType, "time") || strings.Contains(lowerType, "date")) {
+				return name
+			}
+		}
+	}
+
+	for _, candidate := range idColumns {
+		for name, dataType := range columns {
+			lowerType := strings.ToLower(dataType)
+			if strings.EqualFold(name, candidate) && (strings.Contains(lowerType, "int") || strings.Contains(lowerType, "char") || strings.Contains(lowerType, "text") || lowerType == "uuid") {
+				return name
+			}
+		}
+	}
+
+	return ""
+}
+
+// quoteFileDBIdentifier quotes an identifier for SQLite and DuckDB
+func quoteFileDBIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
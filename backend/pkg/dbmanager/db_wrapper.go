@@ -331,6 +331,154 @@ func (w *MySQLWrapper) Close() error {
 	return sqlDB.Close()
 }
 
+// MSSQLWrapper implements DBExecutor for SQL Server
+type MSSQLWrapper struct {
+	BaseWrapper
+}
+
+func NewMSSQLWrapper(db *gorm.DB, manager *Manager, chatID string) *MSSQLWrapper {
+	return &MSSQLWrapper{
+		BaseWrapper: BaseWrapper{
+			db:      db,
+			manager: manager,
+			chatID:  chatID,
+		},
+	}
+}
+
+// GetDB returns the underlying *sql.DB
+func (w *MSSQLWrapper) GetDB() *sql.DB {
+	sqlDB, err := w.db.DB()
+	if err != nil {
+		log.Printf("Failed to get SQL DB: %v", err)
+		return nil
+	}
+	return sqlDB
+}
+
+// GetSchema fetches the current database schema
+func (w *MSSQLWrapper) GetSchema(ctx context.Context) (*SchemaInfo, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("MSSQLWrapper -> GetSchema -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	// Check if SQL Server driver exists
+	_, exists := w.manager.drivers["mssql"]
+	if !exists {
+		return nil, fmt.Errorf("SQL Server driver not found")
+	}
+
+	// Get the schema fetcher factory for SQL Server
+	fetcherFactory, exists := w.manager.fetchers["mssql"]
+	if !exists {
+		return nil, fmt.Errorf("SQL Server schema fetcher not found")
+	}
+
+	// Create a schema fetcher for this connection
+	fetcher := fetcherFactory(w)
+
+	// Get selected collections from the chat service if available
+	var selectedTables []string
+	if w.manager.streamHandler != nil {
+		// Try to get selected collections from the chat service
+		selectedCollections, err := w.manager.streamHandler.GetSelectedCollections(w.chatID)
+		if err == nil && selectedCollections != "ALL" && selectedCollections != "" {
+			selectedTables = strings.Split(selectedCollections, ",")
+			log.Printf("MSSQLWrapper -> GetSchema -> Using selected collections for chat %s: %v", w.chatID, selectedTables)
+		} else {
+			// Default to ALL if there's an error or no specific collections
+			selectedTables = []string{"ALL"}
+			log.Printf("MSSQLWrapper -> GetSchema -> Using ALL tables for chat %s", w.chatID)
+		}
+	} else {
+		// Default to ALL if stream handler is not available
+		selectedTables = []string{"ALL"}
+	}
+
+	// Pass the selected tables to get the schema
+	schema, err := fetcher.GetSchema(ctx, w, selectedTables)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			log.Printf("Schema fetch cancelled by context")
+			return nil, err
+		}
+		return nil, err
+	}
+	return schema, nil
+}
+
+// GetTableChecksum calculates checksum for a single table
+func (w *MSSQLWrapper) GetTableChecksum(ctx context.Context, table string) (string, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("MSSQLWrapper -> GetTableChecksum -> Context cancelled: %v", err)
+		return "", err
+	}
+
+	if err := w.updateUsage(); err != nil {
+		return "", fmt.Errorf("failed to update usage: %v", err)
+	}
+
+	// Get the schema fetcher factory for SQL Server
+	fetcherFactory, exists := w.manager.fetchers["mssql"]
+	if !exists {
+		return "", fmt.Errorf("SQL Server schema fetcher not found")
+	}
+
+	// Create a schema fetcher for this connection
+	fetcher := fetcherFactory(w)
+
+	return fetcher.GetTableChecksum(ctx, w, table)
+}
+
+// Raw executes a raw SQL query
+func (w *MSSQLWrapper) Raw(sql string, values ...interface{}) error {
+	if err := w.updateUsage(); err != nil {
+		return fmt.Errorf("failed to update usage: %v", err)
+	}
+	return w.db.Raw(sql, values...).Error
+}
+
+// Exec executes a SQL statement
+func (w *MSSQLWrapper) Exec(sql string, values ...interface{}) error {
+	if err := w.updateUsage(); err != nil {
+		return fmt.Errorf("failed to update usage: %v", err)
+	}
+	return w.db.Exec(sql, values...).Error
+}
+
+// Query executes a SQL query and scans the result into dest
+func (w *MSSQLWrapper) Query(sql string, dest interface{}, values ...interface{}) error {
+	if err := w.updateUsage(); err != nil {
+		return fmt.Errorf("failed to update usage: %v", err)
+	}
+	return w.db.Raw(sql, values...).Scan(dest).Error
+}
+
+// QueryRows executes a SQL query and scans the result into dest
+func (w *MSSQLWrapper) QueryRows(sql string, dest *[]map[string]interface{}, values ...interface{}) error {
+	if err := w.updateUsage(); err != nil {
+		return fmt.Errorf("failed to update usage: %v", err)
+	}
+	rows, err := scanMSSQLRows(w.db, sql, values...)
+	if err != nil {
+		return err
+	}
+	*dest = rows
+	return nil
+}
+
+// Close closes the database connection
+func (w *MSSQLWrapper) Close() error {
+	sqlDB, err := w.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
 // ClickHouseWrapper implements DBExecutor for ClickHouse
 type ClickHouseWrapper struct {
 	BaseWrapper
@@ -473,3 +621,286 @@ func (w *ClickHouseWrapper) Close() error {
 	}
 	return sqlDB.Close()
 }
+
+// SQLiteWrapper implements DBExecutor for SQLite
+type SQLiteWrapper struct {
+	BaseWrapper
+}
+
+func NewSQLiteWrapper(db *gorm.DB, manager *Manager, chatID string) *SQLiteWrapper {
+	return &SQLiteWrapper{
+		BaseWrapper: BaseWrapper{
+			db:      db,
+			manager: manager,
+			chatID:  chatID,
+		},
+	}
+}
+
+// GetDB returns the underlying *sql.DB
+func (w *SQLiteWrapper) GetDB() *sql.DB {
+	sqlDB, err := w.db.DB()
+	if err != nil {
+		log.Printf("Failed to get SQL DB: %v", err)
+		return nil
+	}
+	return sqlDB
+}
+
+// GetSchema fetches the current database schema
+func (w *SQLiteWrapper) GetSchema(ctx context.Context) (*SchemaInfo, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("SQLiteWrapper -> GetSchema -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	// Check if SQLite driver exists
+	_, exists := w.manager.drivers["sqlite"]
+	if !exists {
+		return nil, fmt.Errorf("SQLite driver not found")
+	}
+
+	// Get the schema fetcher factory for SQLite
+	fetcherFactory, exists := w.manager.fetchers["sqlite"]
+	if !exists {
+		return nil, fmt.Errorf("SQLite schema fetcher not found")
+	}
+
+	// Create a schema fetcher for this connection
+	fetcher := fetcherFactory(w)
+
+	// Get selected collections from the chat service if available
+	var selectedTables []string
+	if w.manager.streamHandler != nil {
+		// Try to get selected collections from the chat service
+		selectedCollections, err := w.manager.streamHandler.GetSelectedCollections(w.chatID)
+		if err == nil && selectedCollections != "ALL" && selectedCollections != "" {
+			selectedTables = strings.Split(selectedCollections, ",")
+			log.Printf("SQLiteWrapper -> GetSchema -> Using selected collections for chat %s: %v", w.chatID, selectedTables)
+		} else {
+			// Default to ALL if there's an error or no specific collections
+			selectedTables = []string{"ALL"}
+			log.Printf("SQLiteWrapper -> GetSchema -> Using ALL tables for chat %s", w.chatID)
+		}
+	} else {
+		// Default to ALL if stream handler is not available
+		selectedTables = []string{"ALL"}
+	}
+
+	// Pass the selected tables to get the schema
+	schema, err := fetcher.GetSchema(ctx, w, selectedTables)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			log.Printf("Schema fetch cancelled by context")
+			return nil, err
+		}
+		return nil, err
+	}
+	return schema, nil
+}
+
+// GetTableChecksum calculates checksum for a single table
+func (w *SQLiteWrapper) GetTableChecksum(ctx context.Context, table string) (string, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("SQLiteWrapper -> GetTableChecksum -> Context cancelled: %v", err)
+		return "", err
+	}
+
+	if err := w.updateUsage(); err != nil {
+		return "", fmt.Errorf("failed to update usage: %v", err)
+	}
+
+	// Get the schema fetcher factory for SQLite
+	fetcherFactory, exists := w.manager.fetchers["sqlite"]
+	if !exists {
+		return "", fmt.Errorf("SQLite schema fetcher not found")
+	}
+
+	// Create a schema fetcher for this connection
+	fetcher := fetcherFactory(w)
+
+	return fetcher.GetTableChecksum(ctx, w, table)
+}
+
+// Raw executes a raw SQL query
+func (w *SQLiteWrapper) Raw(sql string, values ...interface{}) error {
+	if err := w.updateUsage(); err != nil {
+		return fmt.Errorf("failed to update usage: %v", err)
+	}
+	return w.db.Raw(sql, values...).Error
+}
+
+// Exec executes a SQL statement
+func (w *SQLiteWrapper) Exec(sql string, values ...interface{}) error {
+	if err := w.updateUsage(); err != nil {
+		return fmt.Errorf("failed to update usage: %v", err)
+	}
+	return w.db.Exec(sql, values...).Error
+}
+
+// Query executes a SQL query and scans the result into dest
+func (w *SQLiteWrapper) Query(sql string, dest interface{}, values ...interface{}) error {
+	if err := w.updateUsage(); err != nil {
+		return fmt.Errorf("failed to update usage: %v", err)
+	}
+	return w.db.Raw(sql, values...).Scan(dest).Error
+}
+
+// QueryRows executes a SQL query and scans the result into dest
+func (w *SQLiteWrapper) QueryRows(sql string, dest *[]map[string]interface{}, values ...interface{}) error {
+	if err := w.updateUsage(); err != nil {
+		return fmt.Errorf("failed to update usage: %v", err)
+	}
+	rows, err := scanSQLiteRows(w.db, sql, values...)
+	if err != nil {
+		return err
+	}
+	*dest = rows
+	return nil
+}
+
+// Close is a no-op, the file connection and its working copy are managed by the driver
+func (w *SQLiteWrapper) Close() error {
+	return nil
+}
+
+// DuckDBWrapper implements DBExecutor for DuckDB
+type DuckDBWrapper struct {
+	BaseWrapper
+}
+
+func NewDuckDBWrapper(db *gorm.DB, manager *Manager, chatID string) *DuckDBWrapper {
+	return &DuckDBWrapper{
+		BaseWrapper: BaseWrapper{
+			db:      db,
+			manager: manager,
+			chatID:  chatID,
+		},
+	}
+}
+
+// GetDB returns the underlying *sql.DB
+func (w *DuckDBWrapper) GetDB() *sql.DB {
+	sqlDB, err := w.db.DB()
+	if err != nil {
+		log.Printf("Failed to get SQL DB: %v", err)
+		return nil
+	}
+	return sqlDB
+}
+
+// GetSchema fetches the current database schema
+func (w *DuckDBWrapper) GetSchema(ctx context.Context) (*SchemaInfo, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("DuckDBWrapper -> GetSchema -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	// Check if DuckDB driver exists
+	_, exists := w.manager.drivers["duckdb"]
+	if !exists {
+		return nil, fmt.Errorf("DuckDB driver not found")
+	}
+
+	// Get the schema fetcher factory for DuckDB
+	fetcherFactory, exists := w.manager.fetchers["duckdb"]
+	if !exists {
+		return nil, fmt.Errorf("DuckDB schema fetcher not found")
+	}
+
+	// Create a schema fetcher for this connection
+	fetcher := fetcherFactory(w)
+
+	// Get selected collections from the chat service if available
+	var selectedTables []string
+	if w.manager.streamHandler != nil {
+		// Try to get selected collections from the chat service
+		selectedCollections, err := w.manager.streamHandler.GetSelectedCollections(w.chatID)
+		if err == nil && selectedCollections != "ALL" && selectedCollections != "" {
+			selectedTables = strings.Split(selectedCollections, ",")
+			log.Printf("DuckDBWrapper -> GetSchema -> Using selected collections for chat %s: %v", w.chatID, selectedTables)
+		} else {
+			// Default to ALL if there's an error or no specific collections
+			selectedTables = []string{"ALL"}
+			log.Printf("DuckDBWrapper -> GetSchema -> Using ALL tables for chat %s", w.chatID)
+		}
+	} else {
+		// Default to ALL if stream handler is not available
+		selectedTables = []string{"ALL"}
+	}
+
+	// Pass the selected tables to get the schema
+	schema, err := fetcher.GetSchema(ctx, w, selectedTables)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			log.Printf("Schema fetch cancelled by context")
+			return nil, err
+		}
+		return nil, err
+	}
+	return schema, nil
+}
+
+// GetTableChecksum calculates checksum for a single table
+func (w *DuckDBWrapper) GetTableChecksum(ctx context.Context, table string) (string, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("DuckDBWrapper -> GetTableChecksum -> Context cancelled: %v", err)
+		return "", err
+	}
+
+	if err := w.updateUsage(); err != nil {
+		return "", fmt.Errorf("failed to update usage: %v", err)
+	}
+
+	// Get the schema fetcher factory for DuckDB
+	fetcherFactory, exists := w.manager.fetchers["duckdb"]
+	if !exists {
+		return "", fmt.Errorf("DuckDB schema fetcher not found")
+	}
+
+	// Create a schema fetcher for this connection
+	fetcher := fetcherFactory(w)
+
+	return fetcher.GetTableChecksum(ctx, w, table)
+}
+
+// Raw executes a raw SQL query
+func (w *DuckDBWrapper) Raw(sql string, values ...interface{}) error {
+	if err := w.updateUsage(); err != nil {
+		return fmt.Errorf("failed to update usage: %v", err)
+	}
+	return w.db.Raw(sql, values...).Error
+}
+
+// Exec executes a SQL statement
+func (w *DuckDBWrapper) Exec(sql string, values ...interface{}) error {
+	if err := w.updateUsage(); err != nil {
+		return fmt.Errorf("failed to update usage: %v", err)
+	}
+	return w.db.Exec(sql, values...).Error
+}
+
+// Query executes a SQL query and scans the result into dest
+func (w *DuckDBWrapper) Query(sql string, dest interface{}, values ...interface{}) error {
+	if err := w.updateUsage(); err != nil {
+		return fmt.Errorf("failed to update usage: %v", err)
+	}
+	return w.db.Raw(sql, values...).Scan(dest).Error
+}
+
+// QueryRows executes a SQL query and scans the result into dest
+func (w *DuckDBWrapper) QueryRows(sql string, dest *[]map[string]interface{}, values ...interface{}) error {
+	if err := w.updateUsage(); err != nil {
+		return fmt.Errorf("failed to update usage: %v", err)
+	}
+	return w.db.Raw(sql, values...).Scan(dest).Error
+}
+
+// Close is a no-op, the file connection and its working copy are managed by the driver
+func (w *DuckDBWrapper) Close() error {
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build cgo
+
+package dbmanager
+
+import (
+	"database/sql"
+
+	_ "github.com/duckdb/duckdb-go/v2" // DuckDB driver, requires cgo
+)
+
+// openDuckDB opens a DuckDB database file
+func openDuckDB(path string) (*sql.DB, error) {
+	return sql.Open("duckdb", path)
+}
@@ -0,0 +1,368 @@
+package dbmanager
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// DuckDBDriver implements the DatabaseDriver interface for uploaded DuckDB files
+type DuckDBDriver struct{}
+
+// NewDuckDBDriver creates a new DuckDB driver
+func NewDuckDBDriver() DatabaseDriver {
+	return &DuckDBDriver{}
+}
+
+// Connect decrypts the uploaded DuckDB file and opens it
+func (d *DuckDBDriver) Connect(config ConnectionConfig) (*Connection, error) {
+	wrapper, err := openDatabaseFile(config)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := openDuckDB(wrapper.WorkingPath)
+	if err != nil {
+		wrapper.removeWorkingFiles()
+		return nil, fmt.Errorf("failed to open DuckDB file: %v", err)
+	}
+
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		wrapper.removeWorkingFiles()
+		return nil, fmt.Errorf("file is not a valid DuckDB database: %v", err)
+	}
+
+	sqlDB.SetMaxOpenConns(5)
+	sqlDB.SetMaxIdleConns(2)
+	sqlDB.SetConnMaxLifetime(time.Hour)
+
+	// DuckDB's SQL dialect is PostgreSQL compatible, so the postgres dialector is used on top of the DuckDB connection
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		sqlDB.Close()
+		wrapper.removeWorkingFiles()
+		return nil, fmt.Errorf("failed to create GORM connection: %v", err)
+	}
+
+	conn := &Connection{
+		DB:          gormDB,
+		LastUsed:    time.Now(),
+		Status:      StatusConnected,
+		Config:      config,
+		Subscribers: make(map[string]bool),
+		SubLock:     sync.RWMutex{},
+		MongoDBObj:  wrapper,
+	}
+
+	return conn, nil
+}
+
+// Disconnect persists pending writes, closes the DuckDB file and removes the working copy
+func (d *DuckDBDriver) Disconnect(conn *Connection) error {
+	wrapper, err := getDatabaseFileWrapper(conn)
+	if err != nil {
+		return err
+	}
+
+	if wrapper.Dirty {
+		if err := persistDuckDBFile(conn); err != nil {
+			log.Printf("DuckDBDriver -> Disconnect -> Failed to persist database file: %v", err)
+		}
+	}
+
+	sqlDB, err := conn.DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get SQL DB: %v", err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("failed to close connection: %v", err)
+	}
+
+	wrapper.removeWorkingFiles()
+	return nil
+}
+
+// Ping checks if the DuckDB connection is alive
+func (d *DuckDBDriver) Ping(conn *Connection) error {
+	if conn == nil || conn.DB == nil {
+		return fmt.Errorf("no active connection to ping")
+	}
+
+	sqlDB, err := conn.DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %v", err)
+	}
+
+	return sqlDB.Ping()
+}
+
+// IsAlive checks if the DuckDB connection is still valid
+func (d *DuckDBDriver) IsAlive(conn *Connection) bool {
+	return d.Ping(conn) == nil
+}
+
+// ExecuteQuery executes a SQL query on the DuckDB file
+func (d *DuckDBDriver) ExecuteQuery(ctx context.Context, conn *Connection, query string, queryType string, findCount bool) *QueryExecutionResult {
+	if conn == nil || conn.DB == nil {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: "No active connection",
+				Code:    "CONNECTION_ERROR",
+			},
+		}
+	}
+
+	result, wrote := executeDuckDBQuery(ctx, conn.DB, query)
+	if wrote && result.Error == nil {
+		if err := persistDuckDBFile(conn); err != nil {
+			log.Printf("DuckDBDriver -> ExecuteQuery -> Failed to persist database file: %v", err)
+		}
+	}
+	return result
+}
+
+// executeDuckDBQuery runs each statement of the query and reports whether anything was written
+func executeDuckDBQuery(ctx context.Context, db *gorm.DB, query string) (*QueryExecutionResult, bool) {
+	startTime := time.Now()
+	result := &QueryExecutionResult{}
+	wrote := false
+
+	statements := splitMySQLStatements(query)
+
+	for _, stmt := range statements {
+		if strings.TrimSpace(stmt) == "" {
+			continue
+		}
+
+		// Check for context cancellation
+		if ctx.Err() != nil {
+			result.Error = &dtos.QueryError{
+				Message: "Query execution cancelled",
+				Code:    "EXECUTION_CANCELLED",
+			}
+			return result, wrote
+		}
+
+		if isDuckDBReadStatement(stmt) {
+			var rows []map[string]interface{}
+			if err := db.WithContext(ctx).Raw(stmt).Scan(&rows).Error; err != nil {
+				result.Error = &dtos.QueryError{
+					Message: err.Error(),
+					Code:    "EXECUTION_ERROR",
+				}
+				return result, wrote
+			}
+
+			result.Result = map[string]interface{}{
+				"results": processDuckDBRows(rows),
+			}
+		} else {
+			// For other queries (INSERT, UPDATE, DELETE, DDL), execute and return affected rows
+			execResult := db.WithContext(ctx).Exec(stmt)
+			if execResult.Error != nil {
+				result.Error = &dtos.QueryError{
+					Message: execResult.Error.Error(),
+					Code:    "EXECUTION_ERROR",
+				}
+				return result, wrote
+			}
+			wrote = true
+
+			rowsAffected := execResult.RowsAffected
+			result.RowsAffected += rowsAffected
+			if rowsAffected > 0 {
+				result.Result = map[string]interface{}{
+					"rowsAffected": rowsAffected,
+					"message":      fmt.Sprintf("%d row(s) affected", rowsAffected),
+				}
+			} else {
+				result.Result = map[string]interface{}{
+					"message": "Query performed successfully",
+				}
+			}
+		}
+	}
+
+	result.ExecutionTime = int(time.Since(startTime).Milliseconds())
+
+	// Marshal the result to JSON
+	resultJSON, err := json.Marshal(result.Result)
+	if err != nil {
+		return &QueryExecutionResult{
+			ExecutionTime: int(time.Since(startTime).Milliseconds()),
+			Error: &dtos.QueryError{
+				Code:    "JSON_MARSHAL_FAILED",
+				Message: err.Error(),
+				Details: "Failed to marshal query results",
+			},
+		}, wrote
+	}
+	result.StreamData = resultJSON
+
+	return result, wrote
+}
+
+// isDuckDBReadStatement checks whether a statement returns rows
+func isDuckDBReadStatement(stmt string) bool {
+	upperStmt := strings.ToUpper(strings.TrimSpace(stmt))
+	for _, prefix := range []string{"SELECT", "WITH", "FROM", "SHOW", "DESCRIBE", "SUMMARIZE", "PRAGMA", "EXPLAIN", "VALUES", "PIVOT", "UNPIVOT", "TABLE"} {
+		if strings.HasPrefix(upperStmt, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// processDuckDBRows converts DuckDB values into JSON friendly types
+func processDuckDBRows(rows []map[string]interface{}) []map[string]interface{} {
+	processedRows := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		processedRow := make(map[string]interface{}, len(row))
+		for key, val := range row {
+			processedRow[key] = normalizeDuckDBValue(val)
+		}
+		processedRows[i] = processedRow
+	}
+	return processedRows
+}
+
+// normalizeDuckDBValue converts nested DuckDB values (lists, structs, maps, decimals, UUIDs) into JSON friendly types
+func normalizeDuckDBValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string, bool, int8, int16, int32, int64, uint8, uint16, uint32, uint64, float32, float64, time.Time:
+		return v
+	case []byte:
+		// UUIDs are returned as their 16 raw bytes
+		if len(v) == 16 && !utf8.Valid(v) {
+			return fmt.Sprintf("%x-%x-%x-%x-%x", v[0:4], v[4:6], v[6:8], v[8:10], v[10:16])
+		}
+		return string(v)
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = normalizeDuckDBValue(item)
+		}
+		return list
+	case map[string]interface{}:
+		structValue := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			structValue[key] = normalizeDuckDBValue(item)
+		}
+		return structValue
+	case fmt.Stringer:
+		// Decimals and huge integers
+		return v.String()
+	}
+
+	// MAP values are keyed by arbitrary types, which JSON objects can't hold
+	reflected := reflect.ValueOf(value)
+	if reflected.Kind() == reflect.Map {
+		mapValue := make(map[string]interface{}, reflected.Len())
+		iter := reflected.MapRange()
+		for iter.Next() {
+			mapValue[fmt.Sprintf("%v", iter.Key().Interface())] = normalizeDuckDBValue(iter.Value().Interface())
+		}
+		return mapValue
+	}
+
+	return value
+}
+
+// persistDuckDBFile checkpoints the working copy and stores it encrypted
+func persistDuckDBFile(conn *Connection) error {
+	wrapper, err := getDatabaseFileWrapper(conn)
+	if err != nil {
+		return err
+	}
+
+	wrapper.mu.Lock()
+	defer wrapper.mu.Unlock()
+
+	// Move everything from the write-ahead log into the database file before copying it
+	if err := conn.DB.Exec("CHECKPOINT").Error; err != nil {
+		wrapper.Dirty = true
+		return fmt.Errorf("failed to checkpoint DuckDB file: %v", err)
+	}
+
+	if err := wrapper.persistFrom(wrapper.WorkingPath); err != nil {
+		wrapper.Dirty = true
+		return err
+	}
+
+	log.Printf("DuckDBDriver -> persistDuckDBFile -> Persisted database file for %s", wrapper.StoragePath)
+	return nil
+}
+
+// StreamQuery runs a read query and returns a cursor over its rows
+func (d *DuckDBDriver) StreamQuery(ctx context.Context, conn *Connection, query string) (ResultCursor, error) {
+	return streamSQLQuery(ctx, conn, query, nil, func(columnType *sql.ColumnType, value interface{}) interface{} {
+		return normalizeDuckDBValue(value)
+	})
+}
+
+// BeginTx starts a new transaction
+func (d *DuckDBDriver) BeginTx(ctx context.Context, conn *Connection) Transaction {
+	if conn == nil || conn.DB == nil {
+		log.Printf("DuckDBDriver.BeginTx: Connection or DB is nil")
+		return nil
+	}
+
+	tx := conn.DB.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		log.Printf("Failed to begin transaction: %v", tx.Error)
+		return nil
+	}
+
+	return &DuckDBTransaction{
+		tx:   tx,
+		conn: conn,
+	}
+}
+
+// GetSchema retrieves the database schema
+func (d *DuckDBDriver) GetSchema(ctx context.Context, db DBExecutor, selectedTables []string) (*SchemaInfo, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("DuckDBDriver -> GetSchema -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	fetcher := NewDuckDBSchemaFetcher(db)
+	return fetcher.GetSchema(ctx, db, selectedTables)
+}
+
+// GetTableChecksum calculates a checksum for a table
+func (d *DuckDBDriver) GetTableChecksum(ctx context.Context, db DBExecutor, table string) (string, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("DuckDBDriver -> GetTableChecksum -> Context cancelled: %v", err)
+		return "", err
+	}
+
+	fetcher := NewDuckDBSchemaFetcher(db)
+	return fetcher.GetTableChecksum(ctx, db, table)
+}
+
+// FetchExampleRecords fetches example records from a table
+func (d *DuckDBDriver) FetchExampleRecords(ctx context.Context, db DBExecutor, table string, limit int) ([]map[string]interface{}, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("DuckDBDriver -> FetchExampleRecords -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	fetcher := NewDuckDBSchemaFetcher(db)
+	return fetcher.FetchExampleRecords(ctx, db, table, limit)
+}
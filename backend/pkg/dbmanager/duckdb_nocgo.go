@@ -0,0 +1,13 @@
+//go:build !cgo
+
+package dbmanager
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// openDuckDB reports that DuckDB is unavailable, the DuckDB engine is linked through cgo
+func openDuckDB(path string) (*sql.DB, error) {
+	return nil, fmt.Errorf("DuckDB support is not available in this build, rebuild the server with CGO_ENABLED=1")
+}
@@ -0,0 +1,440 @@
+package dbmanager
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// DuckDBSchemaFetcher implements schema fetching for DuckDB
+type DuckDBSchemaFetcher struct {
+	db DBExecutor
+}
+
+// duckDBTable identifies a table, tables outside the main schema are named schema.table
+type duckDBTable struct {
+	Schema string
+	Name   string
+}
+
+// key returns the name the table is known by in the schema
+func (t duckDBTable) key() string {
+	if t.Schema == "" || t.Schema == "main" {
+		return t.Name
+	}
+	return t.Schema + "." + t.Name
+}
+
+// quoted returns the quoted, schema qualified table name
+func (t duckDBTable) quoted() string {
+	return quoteFileDBIdentifier(t.Schema) + "." + quoteFileDBIdentifier(t.Name)
+}
+
+// parseDuckDBTable splits a schema key back into schema and table name
+func parseDuckDBTable(table string) duckDBTable {
+	if schema, name, found := strings.Cut(table, "."); found {
+		return duckDBTable{Schema: schema, Name: name}
+	}
+	return duckDBTable{Schema: "main", Name: table}
+}
+
+// NewDuckDBSchemaFetcher creates a new DuckDB schema fetcher
+func NewDuckDBSchemaFetcher(db DBExecutor) SchemaFetcher {
+	return &DuckDBSchemaFetcher{db: db}
+}
+
+// GetSchema retrieves the schema for the selected tables
+func (f *DuckDBSchemaFetcher) GetSchema(ctx context.Context, db DBExecutor, selectedTables []string) (*SchemaInfo, error) {
+	log.Printf("DuckDBSchemaFetcher -> GetSchema -> Starting schema fetch with selected tables: %v", selectedTables)
+
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("DuckDBSchemaFetcher -> GetSchema -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	schema, err := f.FetchSchema(ctx)
+	if err != nil {
+		log.Printf("DuckDBSchemaFetcher -> GetSchema -> Error fetching schema: %v", err)
+		return nil, err
+	}
+
+	log.Printf("DuckDBSchemaFetcher -> GetSchema -> Successfully fetched schema with %d tables", len(schema.Tables))
+
+	return f.filterSchemaForSelectedTables(schema, selectedTables), nil
+}
+
+// FetchSchema retrieves the full database schema
+func (f *DuckDBSchemaFetcher) FetchSchema(ctx context.Context) (*SchemaInfo, error) {
+	schema := &SchemaInfo{
+		Tables:    make(map[string]TableSchema),
+		Views:     make(map[string]ViewSchema),
+		UpdatedAt: time.Now(),
+	}
+
+	tables, comments, err := f.fetchTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range tables {
+		// Check for context cancellation
+		if err := ctx.Err(); err != nil {
+			log.Printf("DuckDBSchemaFetcher -> FetchSchema -> Context cancelled: %v", err)
+			return nil, err
+		}
+
+		tableSchema := TableSchema{
+			Name:    table.key(),
+			Comment: comments[table.key()],
+		}
+
+		columns, err := f.fetchColumns(ctx, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch columns for table %s: %v", table.key(), err)
+		}
+		tableSchema.Columns = columns
+
+		tableSchema.Indexes = f.fetchIndexes(ctx, table)
+		tableSchema.Constraints, tableSchema.ForeignKeys = f.fetchConstraints(ctx, table)
+		tableSchema.RowCount = f.getTableRowCount(ctx, table)
+
+		// Calculate table schema checksum
+		tableData, _ := json.Marshal(tableSchema)
+		tableSchema.Checksum = fmt.Sprintf("%x", md5.Sum(tableData))
+
+		schema.Tables[table.key()] = tableSchema
+	}
+
+	schema.Views = f.fetchViews(ctx)
+
+	// Calculate overall schema checksum
+	schemaData, _ := json.Marshal(schema.Tables)
+	schema.Checksum = fmt.Sprintf("%x", md5.Sum(schemaData))
+
+	log.Printf("DuckDBSchemaFetcher -> FetchSchema -> Fetched %d tables and %d views", len(schema.Tables), len(schema.Views))
+	return schema, nil
+}
+
+// fetchTables retrieves all user tables of the opened database along with their comments
+func (f *DuckDBSchemaFetcher) fetchTables(_ context.Context) ([]duckDBTable, map[string]string, error) {
+	var rows []map[string]interface{}
+	query := `
+        SELECT schema_name, table_name, comment
+        FROM duckdb_tables()
+        WHERE database_name = current_database()
+        AND NOT internal
+        AND NOT temporary
+        ORDER BY schema_name, table_name;
+    `
+	if err := f.db.QueryRows(query, &rows); err != nil {
+		log.Printf("DuckDBSchemaFetcher -> fetchTables -> Error: %v", err)
+		return nil, nil, fmt.Errorf("failed to fetch tables: %v", err)
+	}
+
+	tables := make([]duckDBTable, 0, len(rows))
+	comments := make(map[string]string)
+	for _, row := range rows {
+		table := duckDBTable{Schema: sqliteString(row["schema_name"]), Name: sqliteString(row["table_name"])}
+		tables = append(tables, table)
+		if comment := sqliteString(row["comment"]); comment != "" {
+			comments[table.key()] = comment
+		}
+	}
+	return tables, comments, nil
+}
+
+// fetchColumns retrieves all columns of a table
+func (f *DuckDBSchemaFetcher) fetchColumns(_ context.Context, table duckDBTable) (map[string]ColumnInfo, error) {
+	columns := make(map[string]ColumnInfo)
+
+	var rows []map[string]interface{}
+	query := `
+        SELECT column_name, data_type, is_nullable, column_default, comment
+        FROM duckdb_columns()
+        WHERE database_name = current_database()
+        AND schema_name = ?
+        AND table_name = ?
+        ORDER BY column_index;
+    `
+	if err := f.db.QueryRows(query, &rows, table.Schema, table.Name); err != nil {
+		log.Printf("DuckDBSchemaFetcher -> fetchColumns -> Error for table %s: %v", table.key(), err)
+		return nil, err
+	}
+
+	for _, row := range rows {
+		name := sqliteString(row["column_name"])
+		isNullable, _ := row["is_nullable"].(bool)
+		columns[name] = ColumnInfo{
+			Name:         name,
+			Type:         sqliteString(row["data_type"]),
+			IsNullable:   isNullable,
+			DefaultValue: sqliteString(row["column_default"]),
+			Comment:      sqliteString(row["comment"]),
+		}
+	}
+	return columns, nil
+}
+
+// fetchIndexes retrieves the explicitly created indexes of a table
+func (f *DuckDBSchemaFetcher) fetchIndexes(_ context.Context, table duckDBTable) map[string]IndexInfo {
+	indexes := make(map[string]IndexInfo)
+
+	var rows []map[string]interface{}
+	query := `
+        SELECT index_name, is_unique, expressions
+        FROM duckdb_indexes()
+        WHERE database_name = current_database()
+        AND schema_name = ?
+        AND table_name = ?;
+    `
+	if err := f.db.QueryRows(query, &rows, table.Schema, table.Name); err != nil {
+		log.Printf("DuckDBSchemaFetcher -> fetchIndexes -> Error for table %s: %v", table.key(), err)
+		return indexes
+	}
+
+	for _, row := range rows {
+		name := sqliteString(row["index_name"])
+		isUnique, _ := row["is_unique"].(bool)
+
+		// Expressions are reported as a list literal such as "[a_id, id]"
+		var indexColumns []string
+		for _, expression := range strings.Split(strings.Trim(sqliteString(row["expressions"]), "[]"), ",") {
+			if expression = strings.Trim(strings.TrimSpace(expression), `"`); expression != "" {
+				indexColumns = append(indexColumns, expression)
+			}
+		}
+
+		indexes[name] = IndexInfo{
+			Name:     name,
+			Columns:  indexColumns,
+			IsUnique: isUnique,
+		}
+	}
+	return indexes
+}
+
+// fetchConstraints retrieves the primary key, unique, check and foreign key constraints of a table
+func (f *DuckDBSchemaFetcher) fetchConstraints(_ context.Context, table duckDBTable) (map[string]ConstraintInfo, map[string]ForeignKey) {
+	constraints := make(map[string]ConstraintInfo)
+	fkeys := make(map[string]ForeignKey)
+
+	var rows []map[string]interface{}
+	query := `
+        SELECT constraint_index, constraint_type, constraint_text, constraint_column_names, referenced_table, referenced_column_names
+        FROM duckdb_constraints()
+        WHERE database_name = current_database()
+        AND schema_name = ?
+        AND table_name = ?
+        AND constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'CHECK', 'FOREIGN KEY')
+        ORDER BY constraint_index;
+    `
+	if err := f.db.QueryRows(query, &rows, table.Schema, table.Name); err != nil {
+		log.Printf("DuckDBSchemaFetcher -> fetchConstraints -> Error for table %s: %v", table.key(), err)
+		return constraints, fkeys
+	}
+
+	for _, row := range rows {
+		constraintType := sqliteString(row["constraint_type"])
+		columns := duckDBStrings(row["constraint_column_names"])
+
+		if constraintType == "FOREIGN KEY" {
+			refColumns := duckDBStrings(row["referenced_column_names"])
+			for i, column := range columns {
+				refColumn := ""
+				if i < len(refColumns) {
+					refColumn = refColumns[i]
+				}
+				name := fmt.Sprintf("fk_%s_%s", table.Name, column)
+				fkeys[name] = ForeignKey{
+					Name:       name,
+					ColumnName: column,
+					RefTable:   sqliteString(row["referenced_table"]),
+					RefColumn:  refColumn,
+				}
+			}
+			continue
+		}
+
+		name := "PRIMARY"
+		if constraintType != "PRIMARY KEY" {
+			name = fmt.Sprintf("%s_%s_%d", table.Name, strings.ToLower(constraintType), sqliteInt(row["constraint_index"]))
+		}
+		constraints[name] = ConstraintInfo{
+			Name:       name,
+			Type:       constraintType,
+			Definition: sqliteString(row["constraint_text"]),
+			Columns:    columns,
+		}
+	}
+	return constraints, fkeys
+}
+
+// fetchViews retrieves all user views of the opened database
+func (f *DuckDBSchemaFetcher) fetchViews(_ context.Context) map[string]ViewSchema {
+	views := make(map[string]ViewSchema)
+
+	var rows []map[string]interface{}
+	query := `
+        SELECT schema_name, view_name, sql
+        FROM duckdb_views()
+        WHERE database_name = current_database()
+        AND NOT internal
+        AND NOT temporary;
+    `
+	if err := f.db.QueryRows(query, &rows); err != nil {
+		log.Printf("DuckDBSchemaFetcher -> fetchViews -> Error: %v", err)
+		return views
+	}
+
+	for _, row := range rows {
+		view := duckDBTable{Schema: sqliteString(row["schema_name"]), Name: sqliteString(row["view_name"])}
+		views[view.key()] = ViewSchema{
+			Name:       view.key(),
+			Definition: sqliteString(row["sql"]),
+		}
+	}
+	return views
+}
+
+// getTableRowCount gets the number of rows in a table
+func (f *DuckDBSchemaFetcher) getTableRowCount(_ context.Context, table duckDBTable) int64 {
+	var count int64
+	if err := f.db.Query(fmt.Sprintf("SELECT COUNT(*) FROM %s", table.quoted()), &count); err != nil {
+		log.Printf("DuckDBSchemaFetcher -> getTableRowCount -> Error for table %s: %v", table.key(), err)
+		return 0
+	}
+	return count
+}
+
+// GetTableChecksum calculates a checksum for a table's structure from its DDL and index definitions
+func (f *DuckDBSchemaFetcher) GetTableChecksum(ctx context.Context, db DBExecutor, table string) (string, error) {
+	parsed := parseDuckDBTable(table)
+
+	var definitions []string
+	query := `
+        SELECT sql FROM duckdb_tables()
+        WHERE database_name = current_database() AND schema_name = ? AND table_name = ?
+        UNION ALL
+        SELECT sql FROM (
+            SELECT sql FROM duckdb_indexes()
+            WHERE database_name = current_database() AND schema_name = ? AND table_name = ?
+            ORDER BY index_name
+        );
+    `
+	if err := db.Query(query, &definitions, parsed.Schema, parsed.Name, parsed.Schema, parsed.Name); err != nil {
+		return "", fmt.Errorf("failed to get table definition: %v", err)
+	}
+	if len(definitions) == 0 {
+		return "", fmt.Errorf("table %s not found", table)
+	}
+
+	return fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(definitions, "\n")))), nil
+}
+
+// FetchExampleRecords retrieves sample records from a table
+func (f *DuckDBSchemaFetcher) FetchExampleRecords(ctx context.Context, db DBExecutor, table string, limit int) ([]map[string]interface{}, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("DuckDBSchemaFetcher -> FetchExampleRecords -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	// Ensure limit is reasonable
+	if limit <= 0 {
+		limit = 3
+	} else if limit > 10 {
+		limit = 10
+	}
+
+	parsed := parseDuckDBTable(table)
+
+	columnTypes := make(map[string]string)
+	var columnRows []map[string]interface{}
+	columnQuery := `
+        SELECT column_name, data_type FROM duckdb_columns()
+        WHERE database_name = current_database() AND schema_name = ? AND table_name = ?;
+    `
+	if err := db.QueryRows(columnQuery, &columnRows, parsed.Schema, parsed.Name); err == nil {
+		for _, row := range columnRows {
+			columnTypes[sqliteString(row["column_name"])] = sqliteString(row["data_type"])
+		}
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s LIMIT %d", parsed.quoted(), limit)
+	if orderByColumn := findExampleOrderColumn(columnTypes); orderByColumn != "" {
+		query = fmt.Sprintf("SELECT * FROM %s ORDER BY %s DESC LIMIT %d",
+			parsed.quoted(), quoteFileDBIdentifier(orderByColumn), limit)
+	}
+
+	var records []map[string]interface{}
+	if err := db.QueryRows(query, &records); err != nil {
+		log.Printf("DuckDBSchemaFetcher -> FetchExampleRecords -> Error fetching records from table %s: %v", table, err)
+		return nil, fmt.Errorf("failed to fetch example records for table %s: %v", table, err)
+	}
+
+	return processDuckDBRows(records), nil
+}
+
+// FetchTableList retrieves a list of all tables in the database
+func (f *DuckDBSchemaFetcher) FetchTableList(ctx context.Context) ([]string, error) {
+	tables, _, err := f.fetchTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tableNames := make([]string, 0, len(tables))
+	for _, table := range tables {
+		tableNames = append(tableNames, table.key())
+	}
+	return tableNames, nil
+}
+
+// filterSchemaForSelectedTables filters the schema to only include the selected tables
+func (f *DuckDBSchemaFetcher) filterSchemaForSelectedTables(schema *SchemaInfo, selectedTables []string) *SchemaInfo {
+	// If no tables are selected or "ALL" is selected, return the full schema
+	if len(selectedTables) == 0 || (len(selectedTables) == 1 && selectedTables[0] == "ALL") {
+		return schema
+	}
+
+	selectedTablesMap := make(map[string]bool)
+	for _, table := range selectedTables {
+		selectedTablesMap[table] = true
+	}
+
+	filteredSchema := &SchemaInfo{
+		Tables:    make(map[string]TableSchema),
+		Views:     make(map[string]ViewSchema),
+		UpdatedAt: schema.UpdatedAt,
+	}
+
+	for tableName, tableSchema := range schema.Tables {
+		if selectedTablesMap[tableName] {
+			filteredSchema.Tables[tableName] = tableSchema
+		}
+	}
+
+	// Calculate new checksum for filtered schema
+	schemaData, _ := json.Marshal(filteredSchema.Tables)
+	filteredSchema.Checksum = fmt.Sprintf("%x", md5.Sum(schemaData))
+
+	return filteredSchema
+}
+
+// duckDBStrings converts a scanned DuckDB VARCHAR[] value to a string slice
+func duckDBStrings(value interface{}) []string {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		values = append(values, sqliteString(item))
+	}
+	return values
+}
@@ -0,0 +1,89 @@
+package dbmanager
+
+import (
+	"strings"
+)
+
+// DuckDBSimplifier implements the SchemaSimplifier interface for DuckDB
+type DuckDBSimplifier struct{}
+
+// SimplifyDataType converts DuckDB data types to simplified versions for LLM
+func (s *DuckDBSimplifier) SimplifyDataType(dbType string) string {
+	upperType := strings.ToUpper(dbType)
+
+	switch {
+	case strings.HasSuffix(upperType, "[]") || strings.HasPrefix(upperType, "LIST") || strings.Contains(upperType, "[]"):
+		return "list"
+	case strings.HasPrefix(upperType, "STRUCT"):
+		return "struct"
+	case strings.HasPrefix(upperType, "MAP"):
+		return "map"
+	case strings.HasPrefix(upperType, "UNION"):
+		return "union"
+	case strings.HasPrefix(upperType, "DECIMAL") || strings.HasPrefix(upperType, "NUMERIC"):
+		return "decimal"
+	case strings.HasPrefix(upperType, "INTERVAL"):
+		return "interval"
+	case strings.HasPrefix(upperType, "TIMESTAMP") || upperType == "DATETIME":
+		return "datetime"
+	case upperType == "DATE":
+		return "date"
+	case strings.HasPrefix(upperType, "TIME"):
+		return "time"
+	case strings.Contains(upperType, "INT"):
+		return "integer"
+	case upperType == "DOUBLE" || upperType == "FLOAT" || upperType == "REAL":
+		return "number"
+	case upperType == "BOOLEAN" || upperType == "BOOL":
+		return "boolean"
+	case upperType == "VARCHAR" || strings.HasPrefix(upperType, "VARCHAR(") || upperType == "TEXT" || upperType == "STRING" ||
+		strings.HasPrefix(upperType, "ENUM"):
+		return "string"
+	case upperType == "UUID":
+		return "uuid"
+	case upperType == "BLOB" || upperType == "BYTEA" || upperType == "BIT" || upperType == "BITSTRING":
+		return "binary"
+	case upperType == "JSON":
+		return "json"
+	default:
+		return dbType
+	}
+}
+
+// GetColumnConstraints returns a list of constraints for a column
+func (s *DuckDBSimplifier) GetColumnConstraints(col ColumnInfo, table TableSchema) []string {
+	var constraints []string
+
+	if !col.IsNullable {
+		constraints = append(constraints, "NOT NULL")
+	}
+
+	if col.DefaultValue != "" {
+		constraints = append(constraints, "DEFAULT "+col.DefaultValue)
+	}
+
+	for _, constraint := range table.Constraints {
+		for _, colName := range constraint.Columns {
+			if colName != col.Name {
+				continue
+			}
+			switch constraint.Type {
+			case "PRIMARY KEY":
+				constraints = append(constraints, "PRIMARY KEY")
+			case "UNIQUE":
+				if len(constraint.Columns) == 1 {
+					constraints = append(constraints, "UNIQUE")
+				}
+			}
+		}
+	}
+
+	for _, fk := range table.ForeignKeys {
+		if fk.ColumnName == col.Name {
+			constraints = append(constraints, "FOREIGN KEY REFERENCES "+fk.RefTable+"("+fk.RefColumn+")")
+			break
+		}
+	}
+
+	return constraints
+}
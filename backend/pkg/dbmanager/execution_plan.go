@@ -0,0 +1,293 @@
+package dbmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/constants"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// Statuses of the steps of an execution plan
+const (
+	PlanStepRunning   = "running"
+	PlanStepSucceeded = "succeeded"
+	PlanStepFailed    = "failed"
+)
+
+// ExecutionPlanStep is a query of an execution plan
+type ExecutionPlanStep struct {
+	QueryID   string
+	Query     string
+	QueryType string
+}
+
+// ExecutionPlanStepResult is the outcome of a step, Result is set when it succeeded and Error when it failed
+type ExecutionPlanStepResult struct {
+	Index   int
+	QueryID string
+	Status  string
+	Result  *QueryExecutionResult
+	Error   *dtos.QueryError
+}
+
+// ExecutionPlanResult is the outcome of an execution plan. Steps holds the steps that ran, the failed one last.
+type ExecutionPlanResult struct {
+	Steps     []ExecutionPlanStepResult
+	Committed bool
+	Atomic    bool // Whether the steps that ran were undone when the plan was rolled back
+	Error     *dtos.QueryError
+}
+
+// SavepointTransaction is implemented by the transactions that can roll back part of their work
+type SavepointTransaction interface {
+	Savepoint(ctx context.Context, name string) error
+	RollbackToSavepoint(ctx context.Context, name string) error
+	ReleaseSavepoint(ctx context.Context, name string) error
+}
+
+// Databases whose rollbacks undo the statements already run in the transaction. The others apply each statement as it
+// runs, so a failed plan would keep the writes of the steps before the failed one. MySQL DDL commits implicitly, see
+// CheckPlanAtomicity.
+var atomicPlanDatabases = map[string]bool{
+	constants.DatabaseTypePostgreSQL:  true,
+	constants.DatabaseTypeYugabyteDB:  true,
+	constants.DatabaseTypeMySQL:       true,
+	constants.DatabaseTypeMSSQL:       true,
+	constants.DatabaseTypeSQLite:      true,
+	constants.DatabaseTypeDuckDB:      true,
+	constants.DatabaseTypeSpreadsheet: true,
+	constants.DatabaseTypeNeo4j:       true,
+}
+
+// SupportsAtomicPlans reports whether a failed execution plan undoes the steps that already ran on the database
+func SupportsAtomicPlans(dbType string) bool {
+	return atomicPlanDatabases[dbType]
+}
+
+// CheckPlanAtomicity returns an error when a failed execution plan of the queries could leave some of its writes
+// applied. Databases that can't undo statements only run plans of a single query or of reads, and MySQL plans of
+// several queries can't hold DDL, which commits implicitly.
+func CheckPlanAtomicity(dbType string, queries []string) error {
+	if len(queries) <= 1 {
+		return nil
+	}
+	for i, query := range queries {
+		if dbType == constants.DatabaseTypeMySQL {
+			analysis, ok := AnalyzeSQL(dbType, query)
+			if ok && analysis.Kind == StatementKindDDL {
+				return fmt.Errorf("query %d changes the schema, which MySQL commits implicitly, run it on its own", i+1)
+			}
+		}
+		if !SupportsAtomicPlans(dbType) && CheckReadOnlyQuery(dbType, query) != nil {
+			return fmt.Errorf("%s can't undo the queries that already ran, run the queries one by one", dbType)
+		}
+	}
+	return nil
+}
+
+// Query types that change the schema, the schema is refreshed after a plan running one of them
+var schemaChangeQueryTypes = map[string]bool{
+	"DDL": true, "ALTER": true, "DROP": true, "CREATE": true, "MERGE": true, "CREATE_COLLECTION": true, "DROP_COLLECTION": true,
+}
+
+// ExecutePlan runs the steps in order in a single transaction, committed only when every step succeeds. Each step
+// runs in a savepoint when the transaction supports them. onStep is called when a step starts and when it ends, from
+// the goroutine running the plan.
+func (m *Manager) ExecutePlan(ctx context.Context, chatID, messageID, streamID string, steps []ExecutionPlanStep, onStep func(step ExecutionPlanStepResult)) *ExecutionPlanResult {
+	queries := make([]string, len(steps))
+	planQueryType := ""
+	for i, step := range steps {
+		queries[i] = step.Query
+		if schemaChangeQueryTypes[step.QueryType] {
+			planQueryType = step.QueryType
+		}
+	}
+
+	m.mu.RLock()
+	conn, exists := m.connections[chatID]
+	m.mu.RUnlock()
+	plan := &ExecutionPlanResult{Atomic: exists && SupportsAtomicPlans(conn.Config.Type)}
+	if exists {
+		if err := CheckPlanAtomicity(conn.Config.Type, queries); err != nil {
+			plan.Error = &dtos.QueryError{Code: "PLAN_NOT_ATOMIC", Message: err.Error()}
+			return plan
+		}
+	}
+
+	// The steps are guarded as a cancelled plan returns while its goroutine may still be running a step
+	var stepsMu sync.Mutex
+	report := func(step ExecutionPlanStepResult) {
+		if step.Status != PlanStepRunning {
+			stepsMu.Lock()
+			plan.Steps = append(plan.Steps, step)
+			stepsMu.Unlock()
+		}
+		if onStep != nil {
+			onStep(step)
+		}
+	}
+
+	_, queryErr := m.executeInTransaction(ctx, chatID, messageID, "", streamID, queries, planQueryType, false, func(execCtx context.Context, tx Transaction) (*QueryExecutionResult, error) {
+		savepointTx, hasSavepoints := tx.(SavepointTransaction)
+		for i, step := range steps {
+			report(ExecutionPlanStepResult{Index: i, QueryID: step.QueryID, Status: PlanStepRunning})
+			log.Printf("Manager -> ExecutePlan -> Executing step %d: %v", i, step.Query)
+
+			savepoint := fmt.Sprintf("neobase_step_%d", i)
+			if hasSavepoints {
+				if err := savepointTx.Savepoint(execCtx, savepoint); err != nil {
+					log.Printf("Manager -> ExecutePlan -> Failed to create savepoint %s: %v", savepoint, err)
+					hasSavepoints = false
+				}
+			}
+
+			snapshot := captureSnapshot(execCtx, tx, step.Query)
+			result, err := tx.ExecuteQuery(execCtx, step.Query)
+			stepErr := planStepError(result, err)
+			if stepErr != nil {
+				if hasSavepoints {
+					if err := savepointTx.RollbackToSavepoint(execCtx, savepoint); err != nil {
+						log.Printf("Manager -> ExecutePlan -> Failed to roll back to savepoint %s: %v", savepoint, err)
+					}
+				}
+				report(ExecutionPlanStepResult{Index: i, QueryID: step.QueryID, Status: PlanStepFailed, Error: stepErr})
+				return &QueryExecutionResult{
+					Error: &dtos.QueryError{
+						Code:    "PLAN_STEP_FAILED",
+						Message: fmt.Sprintf("step %d of the plan failed: %s", i+1, stepErr.Message),
+						Details: stepErr.Details,
+					},
+				}, nil
+			}
+
+			if hasSavepoints {
+				if err := savepointTx.ReleaseSavepoint(execCtx, savepoint); err != nil {
+					log.Printf("Manager -> ExecutePlan -> Failed to release savepoint %s: %v", savepoint, err)
+				}
+			}
+			result.Snapshot = snapshot
+			report(ExecutionPlanStepResult{Index: i, QueryID: step.QueryID, Status: PlanStepSucceeded, Result: result})
+		}
+		return &QueryExecutionResult{}, nil
+	})
+
+	stepsMu.Lock()
+	defer stepsMu.Unlock()
+	plan.Steps = append([]ExecutionPlanStepResult(nil), plan.Steps...)
+	plan.Committed = queryErr == nil
+	plan.Error = queryErr
+	return plan
+}
+
+// planStepError returns the error of a step, from the driver or from its result
+func planStepError(result *QueryExecutionResult, err error) *dtos.QueryError {
+	if err != nil {
+		return &dtos.QueryError{Code: "EXECUTION_ERROR", Message: err.Error()}
+	}
+	if result == nil {
+		return &dtos.QueryError{Code: "EXECUTION_ERROR", Message: "the query returned no result"}
+	}
+	return result.Error
+}
+
+// Savepoint creates a savepoint in the transaction
+func (tx *PostgresTransaction) Savepoint(ctx context.Context, name string) error {
+	_, err := tx.tx.ExecContext(ctx, "SAVEPOINT "+name)
+	return err
+}
+
+// RollbackToSavepoint undoes the work done since the savepoint
+func (tx *PostgresTransaction) RollbackToSavepoint(ctx context.Context, name string) error {
+	_, err := tx.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+	return err
+}
+
+// ReleaseSavepoint forgets the savepoint, keeping the work done since
+func (tx *PostgresTransaction) ReleaseSavepoint(ctx context.Context, name string) error {
+	_, err := tx.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
+
+// Savepoint creates a savepoint in the transaction
+func (t *MySQLTransaction) Savepoint(ctx context.Context, name string) error {
+	return execGormSavepoint(ctx, t.tx, "SAVEPOINT "+name)
+}
+
+// RollbackToSavepoint undoes the work done since the savepoint
+func (t *MySQLTransaction) RollbackToSavepoint(ctx context.Context, name string) error {
+	return execGormSavepoint(ctx, t.tx, "ROLLBACK TO SAVEPOINT "+name)
+}
+
+// ReleaseSavepoint forgets the savepoint, keeping the work done since
+func (t *MySQLTransaction) ReleaseSavepoint(ctx context.Context, name string) error {
+	return execGormSavepoint(ctx, t.tx, "RELEASE SAVEPOINT "+name)
+}
+
+// Savepoint creates a savepoint in the transaction
+func (t *SQLiteTransaction) Savepoint(ctx context.Context, name string) error {
+	return execGormSavepoint(ctx, t.tx, "SAVEPOINT "+name)
+}
+
+// RollbackToSavepoint undoes the work done since the savepoint
+func (t *SQLiteTransaction) RollbackToSavepoint(ctx context.Context, name string) error {
+	return execGormSavepoint(ctx, t.tx, "ROLLBACK TO SAVEPOINT "+name)
+}
+
+// ReleaseSavepoint forgets the savepoint, keeping the work done since
+func (t *SQLiteTransaction) ReleaseSavepoint(ctx context.Context, name string) error {
+	return execGormSavepoint(ctx, t.tx, "RELEASE SAVEPOINT "+name)
+}
+
+// Savepoint creates a savepoint in the transaction
+func (t *MSSQLTransaction) Savepoint(ctx context.Context, name string) error {
+	return execGormSavepoint(ctx, t.tx, "SAVE TRANSACTION "+name)
+}
+
+// RollbackToSavepoint undoes the work done since the savepoint
+func (t *MSSQLTransaction) RollbackToSavepoint(ctx context.Context, name string) error {
+	return execGormSavepoint(ctx, t.tx, "ROLLBACK TRANSACTION "+name)
+}
+
+// ReleaseSavepoint is a no-op, SQL Server savepoints last until the transaction ends
+func (t *MSSQLTransaction) ReleaseSavepoint(ctx context.Context, name string) error {
+	return nil
+}
+
+// Savepoint creates a savepoint in the transaction of the spreadsheet
+func (t *SpreadsheetTransaction) Savepoint(ctx context.Context, name string) error {
+	pgTx, ok := t.pgTx.(SavepointTransaction)
+	if !ok {
+		return fmt.Errorf("the spreadsheet transaction doesn't support savepoints")
+	}
+	return pgTx.Savepoint(ctx, name)
+}
+
+// RollbackToSavepoint undoes the work done since the savepoint
+func (t *SpreadsheetTransaction) RollbackToSavepoint(ctx context.Context, name string) error {
+	pgTx, ok := t.pgTx.(SavepointTransaction)
+	if !ok {
+		return fmt.Errorf("the spreadsheet transaction doesn't support savepoints")
+	}
+	return pgTx.RollbackToSavepoint(ctx, name)
+}
+
+// ReleaseSavepoint forgets the savepoint, keeping the work done since
+func (t *SpreadsheetTransaction) ReleaseSavepoint(ctx context.Context, name string) error {
+	pgTx, ok := t.pgTx.(SavepointTransaction)
+	if !ok {
+		return fmt.Errorf("the spreadsheet transaction doesn't support savepoints")
+	}
+	return pgTx.ReleaseSavepoint(ctx, name)
+}
+
+// execGormSavepoint runs a savepoint statement in a gorm transaction
+func execGormSavepoint(ctx context.Context, tx *gorm.DB, statement string) error {
+	if tx == nil {
+		return fmt.Errorf("no active transaction")
+	}
+	return tx.WithContext(ctx).Exec(statement).Error
+}
@@ -0,0 +1,81 @@
+package dbmanager
+
+import (
+	"neobase-ai/internal/constants"
+	"strings"
+	"testing"
+)
+
+func TestCheckPlanAtomicity(t *testing.T) {
+	tests := []struct {
+		name    string
+		dbType  string
+		queries []string
+		wantErr string // Substring of the error, empty when the plan is allowed
+	}{
+		{
+			name:    "single write on a database without transactions",
+			dbType:  constants.DatabaseTypeClickhouse,
+			queries: []string{"ALTER TABLE events DELETE WHERE id = 1"},
+		},
+		{
+			name:    "postgres writes and ddl",
+			dbType:  constants.DatabaseTypePostgreSQL,
+			queries: []string{"CREATE TABLE t (a int)", "INSERT INTO t VALUES (1)", "DELETE FROM users WHERE id = 1"},
+		},
+		{
+			name:    "mysql writes",
+			dbType:  constants.DatabaseTypeMySQL,
+			queries: []string{"INSERT INTO t VALUES (1)", "UPDATE t SET a = 2 WHERE a = 1"},
+		},
+		{
+			name:    "mysql ddl in a plan",
+			dbType:  constants.DatabaseTypeMySQL,
+			queries: []string{"INSERT INTO t VALUES (1)", "ALTER TABLE t ADD COLUMN b int"},
+			wantErr: "query 2 changes the schema",
+		},
+		{
+			name:    "mysql ddl on its own",
+			dbType:  constants.DatabaseTypeMySQL,
+			queries: []string{"DROP TABLE t"},
+		},
+		{
+			name:    "clickhouse reads",
+			dbType:  constants.DatabaseTypeClickhouse,
+			queries: []string{"SELECT count() FROM events", "SELECT max(ts) FROM events"},
+		},
+		{
+			name:    "clickhouse writes",
+			dbType:  constants.DatabaseTypeClickhouse,
+			queries: []string{"SELECT count() FROM events", "INSERT INTO events VALUES (1)"},
+			wantErr: "can't undo the queries that already ran",
+		},
+		{
+			name:    "mongodb writes",
+			dbType:  constants.DatabaseTypeMongoDB,
+			queries: []string{`db.users.insertOne({"name": "a"})`, `db.users.deleteOne({"name": "b"})`},
+			wantErr: "can't undo the queries that already ran",
+		},
+		{
+			name:    "redis writes",
+			dbType:  constants.DatabaseTypeRedis,
+			queries: []string{"GET a", "SET a 1"},
+			wantErr: "can't undo the queries that already ran",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPlanAtomicity(tt.dbType, tt.queries)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
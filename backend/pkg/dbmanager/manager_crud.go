@@ -42,6 +42,7 @@ type DatabasePool struct {
 	LastUsed   time.Time
 	Mutex      sync.Mutex // For thread-safe reference counting
 	MongoDBObj interface{}
+	Tunnel     *SSHTunnel // SSH tunnel shared by the connections of this pool, if any
 }
 
 // Manager handles database connections
@@ -128,6 +129,30 @@ func NewManager(redisRepo redis.IRedisRepositories, encryptionKey string) (*Mana
 		return NewMongoDBSchemaFetcher(db)
 	})
 
+	m.RegisterFetcher("redis", func(db DBExecutor) SchemaFetcher {
+		return NewRedisSchemaFetcher(db)
+	})
+
+	m.RegisterFetcher("mssql", func(db DBExecutor) SchemaFetcher {
+		return NewMSSQLSchemaFetcher(db)
+	})
+
+	m.RegisterFetcher("cassandra", func(db DBExecutor) SchemaFetcher {
+		return NewCassandraSchemaFetcher(db)
+	})
+
+	m.RegisterFetcher("neo4j", func(db DBExecutor) SchemaFetcher {
+		return NewNeo4jSchemaFetcher(db)
+	})
+
+	m.RegisterFetcher("sqlite", func(db DBExecutor) SchemaFetcher {
+		return NewSQLiteSchemaFetcher(db)
+	})
+
+	m.RegisterFetcher("duckdb", func(db DBExecutor) SchemaFetcher {
+		return NewDuckDBSchemaFetcher(db)
+	})
+
 	m.registerDefaultDrivers()
 
 	return m, nil
@@ -220,6 +245,22 @@ func (m *Manager) registerDefaultDrivers() {
 		return NewMongoDBSchemaFetcher(db)
 	})
 
+	// Register SQL Server driver
+	m.RegisterDriver("mssql", NewMSSQLDriver())
+
+	// Register Redis driver
+	m.RegisterDriver("redis", NewRedisDriver())
+
+	// Register Cassandra/ScyllaDB driver
+	m.RegisterDriver("cassandra", NewCassandraDriver())
+
+	// Register Neo4j driver
+	m.RegisterDriver("neo4j", NewNeo4jDriver())
+
+	// Register SQLite and DuckDB drivers for uploaded database files
+	m.RegisterDriver("sqlite", NewSQLiteDriver())
+	m.RegisterDriver("duckdb", NewDuckDBDriver())
+
 	// Register Spreadsheet (CSV/Excel) driver
 	m.RegisterDriver("spreadsheet", NewSpreadsheetDriver())
 }
@@ -276,14 +317,25 @@ func (m *Manager) Connect(chatID, userID, streamID string, config ConnectionConf
 	}
 
 	// Generate a unique key for this database configuration
-	configKey := utils.GenerateConfigKey(map[string]interface{}{
+	configKeyFields := map[string]interface{}{
 		"type":     config.Type,
 		"host":     config.Host,
 		"port":     config.Port,
 		"username": config.Username,
 		"password": config.Password,
 		"database": config.Database, // Add database to the key to differentiate connections to different databases
-	})
+	}
+	if config.ReadOnly {
+		// Read-only sessions can't be shared with read-write chats of the same database
+		configKeyFields["read_only"] = true
+	}
+	if config.SSHEnabled {
+		configKeyFields["ssh_host"] = config.SSHHost
+		configKeyFields["ssh_port"] = config.SSHPort
+		configKeyFields["ssh_username"] = config.SSHUsername
+		configKeyFields["ssh_host_key"] = config.SSHHostKey
+	}
+	configKey := utils.GenerateConfigKey(configKeyFields)
 	log.Printf("DBManager -> Connect -> Generated config key: %s", configKey)
 
 	// Check if we already have a connection to this database
@@ -343,10 +395,11 @@ func (m *Manager) Connect(chatID, userID, streamID string, config ConnectionConf
 			ConfigKey:   configKey, // Store the config key for reference
 		}
 
-		// Set MongoDBObj for MongoDB connections when reusing from pool
-		if config.Type == "mongodb" && pool.MongoDBObj != nil {
+		// Set MongoDBObj for MongoDB/Redis/Cassandra/Neo4j/file connections when reusing from pool
+		if (config.Type == "mongodb" || config.Type == "redis" || config.Type == "cassandra" || config.Type == "neo4j" ||
+			config.Type == "sqlite" || config.Type == "duckdb") && pool.MongoDBObj != nil {
 			conn.MongoDBObj = pool.MongoDBObj
-			log.Printf("DBManager -> Connect -> Set MongoDBObj from pool for MongoDB connection")
+			log.Printf("DBManager -> Connect -> Set MongoDBObj from pool for %s connection", config.Type)
 		}
 
 		// Update metrics
@@ -359,10 +412,20 @@ func (m *Manager) Connect(chatID, userID, streamID string, config ConnectionConf
 			conn.Config.SchemaName = schemaName
 		}
 	} else {
+		// Open the SSH tunnel first, the driver connects through its local end
+		driverConfig, tunnel, tunnelErr := openSSHTunnel(config)
+		if tunnelErr != nil {
+			log.Printf("DBManager -> Connect -> SSH tunnel failed: %v", tunnelErr)
+			return tunnelErr
+		}
+
 		// Create a new connection
-		conn, err = driver.Connect(config)
+		conn, err = driver.Connect(driverConfig)
 		if err != nil {
 			log.Printf("DBManager -> Connect -> Driver connection failed: %v", err)
+			if tunnel != nil {
+				tunnel.Close()
+			}
 			return err
 		}
 
@@ -375,10 +438,12 @@ func (m *Manager) Connect(chatID, userID, streamID string, config ConnectionConf
 			RefCount: 1,
 			Config:   config,
 			LastUsed: time.Now(),
+			Tunnel:   tunnel,
 		}
 
-		// For MongoDB, store the MongoDB client in the pool
-		if config.Type == "mongodb" {
+		// For MongoDB/Redis/Cassandra/Neo4j, store the client in the pool, file databases keep their working copy wrapper there
+		if config.Type == "mongodb" || config.Type == "redis" || config.Type == "cassandra" || config.Type == "neo4j" ||
+			config.Type == "sqlite" || config.Type == "duckdb" {
 			newPool.MongoDBObj = conn.MongoDBObj
 		}
 
@@ -525,6 +590,11 @@ func (m *Manager) Disconnect(chatID, userID string, deleteSchema bool) error {
 				}
 			}
 
+			// Close the SSH tunnel once no connection uses it
+			if pool.Tunnel != nil {
+				pool.Tunnel.Close()
+			}
+
 			// Remove from pool
 			delete(m.dbPools, configKey)
 			log.Printf("DBManager -> Disconnect -> Removed pool from dbPools map")
@@ -616,8 +686,14 @@ func (m *Manager) GetConnection(chatID string) (DBExecutor, error) {
 		return NewPostgresWrapper(conn.DB, m, chatID), nil
 	case constants.DatabaseTypeMySQL:
 		return NewMySQLWrapper(conn.DB, m, chatID), nil
+	case constants.DatabaseTypeMSSQL:
+		return NewMSSQLWrapper(conn.DB, m, chatID), nil
 	case constants.DatabaseTypeClickhouse:
 		return NewClickHouseWrapper(conn.DB, m, chatID), nil
+	case constants.DatabaseTypeSQLite:
+		return NewSQLiteWrapper(conn.DB, m, chatID), nil
+	case constants.DatabaseTypeDuckDB:
+		return NewDuckDBWrapper(conn.DB, m, chatID), nil
 	case constants.DatabaseTypeMongoDB:
 		// For MongoDB, we use the MongoDBObj field instead of DB
 		_, ok := conn.MongoDBObj.(*MongoDBWrapper)
@@ -629,6 +705,27 @@ func (m *Manager) GetConnection(chatID string) (DBExecutor, error) {
 			return nil, fmt.Errorf("failed to create MongoDB executor: %v", err)
 		}
 		return executor, nil
+	case constants.DatabaseTypeRedis:
+		// For Redis, the client is stored in the MongoDBObj field
+		executor, err := NewRedisExecutor(conn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Redis executor: %v", err)
+		}
+		return executor, nil
+	case constants.DatabaseTypeCassandra:
+		// For Cassandra, the session is stored in the MongoDBObj field
+		executor, err := NewCassandraExecutor(conn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Cassandra executor: %v", err)
+		}
+		return executor, nil
+	case constants.DatabaseTypeNeo4j:
+		// For Neo4j, the driver is stored in the MongoDBObj field
+		executor, err := NewNeo4jExecutor(conn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Neo4j executor: %v", err)
+		}
+		return executor, nil
 	case "spreadsheet":
 		// For Spreadsheet, we need to create a wrapper that includes the schema name
 		wrapper := &spreadsheetSchemaWrapper{
@@ -685,13 +782,21 @@ func (m *Manager) cleanup() {
 		if pool.RefCount <= 0 && time.Since(pool.LastUsed) > idleTimeout {
 			log.Printf("DBManager -> cleanup -> Removing idle connection pool: %s (idle for %v)", key, time.Since(pool.LastUsed))
 
-			// Close the connection
-			if pool.GORMDB != nil {
+			// Close the connection, file databases also persist and remove their decrypted working copy
+			if _, isFile := pool.MongoDBObj.(*DatabaseFileWrapper); isFile && m.drivers[pool.Config.Type] != nil {
+				poolConn := &Connection{DB: pool.GORMDB, Config: pool.Config, MongoDBObj: pool.MongoDBObj}
+				if err := m.drivers[pool.Config.Type].Disconnect(poolConn); err != nil {
+					log.Printf("DBManager -> cleanup -> Error disconnecting file database pool %s: %v", key, err)
+				}
+			} else if pool.GORMDB != nil {
 				sqlDB, err := pool.GORMDB.DB()
 				if err == nil && sqlDB != nil {
 					sqlDB.Close()
 				}
 			}
+			if pool.Tunnel != nil {
+				pool.Tunnel.Close()
+			}
 			delete(m.dbPools, key)
 		}
 		pool.Mutex.Unlock()
@@ -743,6 +848,9 @@ func (m *Manager) Stop() error {
 				log.Printf("DBManager -> Stop -> Closed pool: %s", key)
 			}
 		}
+		if pool.Tunnel != nil {
+			pool.Tunnel.Close()
+		}
 		delete(m.dbPools, key)
 	}
 	m.dbPoolsMu.Unlock()
@@ -1057,6 +1165,36 @@ func (m *Manager) IsConnected(chatID string) bool {
 		return false
 	}
 
+	// For Redis connections
+	if conn.Config.Type == "redis" {
+		if wrapper, ok := conn.MongoDBObj.(*RedisWrapper); ok && wrapper != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			return wrapper.Client.Ping(ctx).Err() == nil
+		}
+		return false
+	}
+
+	// For Cassandra connections
+	if conn.Config.Type == "cassandra" {
+		if wrapper, ok := conn.MongoDBObj.(*CassandraWrapper); ok && wrapper != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			return pingCassandra(ctx, wrapper.Session) == nil
+		}
+		return false
+	}
+
+	// For Neo4j connections
+	if conn.Config.Type == "neo4j" {
+		if wrapper, ok := conn.MongoDBObj.(*Neo4jWrapper); ok && wrapper != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			return wrapper.Driver.VerifyConnectivity(ctx) == nil
+		}
+		return false
+	}
+
 	// For SQL connections
 	if conn.DB != nil {
 		sqlDB, err := conn.DB.DB()
@@ -18,10 +18,12 @@ import (
 
 	"crypto/tls"
 	"crypto/x509"
+	"neobase-ai/config"
 	"neobase-ai/internal/apis/dtos"
 	"neobase-ai/internal/constants"
 	"neobase-ai/internal/utils"
 
+	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
@@ -61,6 +63,55 @@ func (m *Manager) CancelQueryExecution(streamID string) {
 
 // ExecuteQuery executes a query and returns the result, synchronous, no SSE events are sent, findCount is used to strictly get the number/count of records that the query returns
 func (m *Manager) ExecuteQuery(ctx context.Context, chatID, messageID, queryID, streamID string, query string, queryType string, isRollback bool, findCount bool) (*QueryExecutionResult, *dtos.QueryError) {
+	return m.executeInTransaction(ctx, chatID, messageID, queryID, streamID, []string{query}, queryType, isRollback, func(execCtx context.Context, tx Transaction) (*QueryExecutionResult, error) {
+		log.Printf("Manager -> ExecuteQuery -> Executing query: %v", query)
+		// The rows an UPDATE/DELETE changes are read first, so it can be rolled back without a rollback query
+		var snapshot *QuerySnapshot
+		if !isRollback {
+			snapshot = captureSnapshot(execCtx, tx, query)
+		}
+		result, err := tx.ExecuteQuery(execCtx, query)
+		if err == nil && result != nil && result.Error == nil {
+			result.Snapshot = snapshot
+		}
+		return result, err
+	})
+}
+
+// ExecuteSnapshotRollback rolls back a query by writing the rows of its snapshot back, in a transaction tracked like
+// the one of a rollback query
+func (m *Manager) ExecuteSnapshotRollback(ctx context.Context, chatID, messageID, queryID, streamID string, snapshot *QuerySnapshot) (*QueryExecutionResult, *dtos.QueryError) {
+	return m.executeInTransaction(ctx, chatID, messageID, queryID, streamID, []string{snapshot.Inverse}, "", true, func(execCtx context.Context, tx Transaction) (*QueryExecutionResult, error) {
+		snapshotTx, ok := tx.(SnapshotTransaction)
+		if !ok {
+			return nil, fmt.Errorf("the database doesn't support rollbacks from snapshots")
+		}
+		log.Printf("Manager -> ExecuteSnapshotRollback -> Restoring %d row(s) of %s", snapshot.RowCount, snapshot.Table)
+		return snapshotTx.RestoreSnapshot(execCtx, snapshot)
+	})
+}
+
+// captureSnapshot takes the snapshot of an UPDATE/DELETE when the transaction supports it. The query still runs when
+// it fails, it just can't be rolled back from a snapshot.
+func captureSnapshot(ctx context.Context, tx Transaction, query string) *QuerySnapshot {
+	snapshotTx, ok := tx.(SnapshotTransaction)
+	if !ok || config.Env.QuerySnapshotMaxRows <= 0 {
+		return nil
+	}
+	snapshot, err := snapshotTx.CaptureSnapshot(ctx, query, config.Env.QuerySnapshotMaxRows)
+	if err != nil {
+		log.Printf("Manager -> captureSnapshot -> No snapshot taken: %v", err)
+		return nil
+	}
+	if snapshot != nil {
+		log.Printf("Manager -> captureSnapshot -> Snapshot of %d row(s) of %s taken", snapshot.RowCount, snapshot.Table)
+	}
+	return snapshot
+}
+
+// executeInTransaction runs the queries with run in a transaction that is committed when it succeeds, the execution
+// can be cancelled by its stream ID
+func (m *Manager) executeInTransaction(ctx context.Context, chatID, messageID, queryID, streamID string, queries []string, queryType string, isRollback bool, run func(execCtx context.Context, tx Transaction) (*QueryExecutionResult, error)) (*QueryExecutionResult, *dtos.QueryError) {
 	m.executionMu.Lock()
 
 	// Create cancellable context with timeout
@@ -105,6 +156,20 @@ func (m *Manager) ExecuteQuery(ctx context.Context, chatID, messageID, queryID,
 		}
 	}
 
+	// Read-only chats only run queries that read data
+	if conn.Config.ReadOnly {
+		for _, query := range queries {
+			if err := checkReadOnlyMode(conn.Config.Type, query); err != nil {
+				log.Printf("Manager -> ExecuteQuery -> Rejected query on read-only connection: %v", err)
+				return nil, &dtos.QueryError{
+					Code:    "READ_ONLY_MODE",
+					Message: "writes are not allowed in read-only mode",
+					Details: err.Error(),
+				}
+			}
+		}
+	}
+
 	log.Printf("Manager -> ExecuteQuery -> Driver: %v", driver)
 	// Begin transaction
 	tx := driver.BeginTx(execCtx, conn)
@@ -135,9 +200,8 @@ func (m *Manager) ExecuteQuery(ctx context.Context, chatID, messageID, queryID,
 
 	go func() {
 		defer close(done)
-		log.Printf("Manager -> ExecuteQuery -> Executing query: %v", query)
 		var err error
-		result, err = tx.ExecuteQuery(execCtx, query)
+		result, err = run(execCtx, tx)
 		if err != nil {
 			log.Printf("Manager -> ExecuteQuery -> Error executing query: %v", err)
 			result = &QueryExecutionResult{
@@ -198,7 +262,7 @@ func (m *Manager) ExecuteQuery(ctx context.Context, chatID, messageID, queryID,
 						conn.OnSchemaChange(conn.ChatID)
 					}
 				}
-			case constants.DatabaseTypeMySQL:
+			case constants.DatabaseTypeMySQL, constants.DatabaseTypeMSSQL:
 				if queryType == "DDL" || queryType == "ALTER" || queryType == "DROP" {
 					if conn.OnSchemaChange != nil {
 						conn.OnSchemaChange(conn.ChatID)
@@ -210,6 +274,25 @@ func (m *Manager) ExecuteQuery(ctx context.Context, chatID, messageID, queryID,
 						conn.OnSchemaChange(conn.ChatID)
 					}
 				}
+			case constants.DatabaseTypeSQLite, constants.DatabaseTypeDuckDB:
+				if queryType == "DDL" || queryType == "ALTER" || queryType == "DROP" {
+					if conn.OnSchemaChange != nil {
+						conn.OnSchemaChange(conn.ChatID)
+					}
+				}
+			case constants.DatabaseTypeCassandra:
+				if queryType == "DDL" || queryType == "ALTER" || queryType == "DROP" {
+					if conn.OnSchemaChange != nil {
+						conn.OnSchemaChange(conn.ChatID)
+					}
+				}
+			case constants.DatabaseTypeNeo4j:
+				// New labels, relationship types and property keys are created by writes too
+				if queryType == "DDL" || queryType == "CREATE" || queryType == "MERGE" || queryType == "DROP" {
+					if conn.OnSchemaChange != nil {
+						conn.OnSchemaChange(conn.ChatID)
+					}
+				}
 			case constants.DatabaseTypeMongoDB:
 				if queryType == "CREATE_COLLECTION" || queryType == "DROP_COLLECTION" {
 					if conn.OnSchemaChange != nil {
@@ -223,10 +306,88 @@ func (m *Manager) ExecuteQuery(ctx context.Context, chatID, messageID, queryID,
 	}
 }
 
+// Timeout of the queries run by ExecuteReadOnlyQuery
+const readOnlyQueryTimeout = 30 * time.Second
+
+// ExecuteReadOnlyQuery runs a query the user didn't ask to run, e.g. while the LLM explores the database, in a
+// transaction that is always rolled back. Databases without transactions apply writes anyway, so callers must make
+// sure the query is read-only.
+func (m *Manager) ExecuteReadOnlyQuery(ctx context.Context, chatID string, query string) (*QueryExecutionResult, error) {
+	m.mu.RLock()
+	conn, exists := m.connections[chatID]
+	m.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("no connection found for chat ID: %s", chatID)
+	}
+
+	driver, exists := m.drivers[conn.Config.Type]
+	if !exists {
+		return nil, fmt.Errorf("no driver found for type: %s", conn.Config.Type)
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, readOnlyQueryTimeout)
+	defer cancel()
+
+	tx := driver.BeginTx(execCtx, conn)
+	if tx == nil {
+		return nil, fmt.Errorf("failed to start transaction")
+	}
+	if mongoTx, ok := tx.(*MongoDBTransaction); ok && mongoTx.Error != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", mongoTx.Error)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			log.Printf("Manager -> ExecuteReadOnlyQuery -> Error rolling back transaction: %v", err)
+		}
+	}()
+
+	result, err := tx.ExecuteQuery(execCtx, query)
+	if err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		if result.Error.Details != "" && result.Error.Details != result.Error.Message {
+			return nil, fmt.Errorf("%s: %s", result.Error.Message, result.Error.Details)
+		}
+		return nil, fmt.Errorf("%s", result.Error.Message)
+	}
+	return result, nil
+}
+
+// FetchExampleRecords returns up to limit records of a table of the chat's database
+func (m *Manager) FetchExampleRecords(ctx context.Context, chatID string, table string, limit int) ([]map[string]interface{}, error) {
+	m.mu.RLock()
+	conn, exists := m.connections[chatID]
+	m.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("connection not found for chat ID: %s", chatID)
+	}
+
+	db, err := m.GetConnection(chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database executor: %v", err)
+	}
+	fetcher, err := m.schemaManager.getFetcher(conn.Config.Type, db)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.FetchExampleRecords(ctx, db, table, limit)
+}
+
 // TestConnection tests if the provided credentials are valid without creating a persistent connection
 func (m *Manager) TestConnection(config *ConnectionConfig) error {
 	var tempFiles []string
 
+	// Route the test through an SSH tunnel when one is configured
+	tunneledConfig, tunnel, err := openSSHTunnel(*config)
+	if err != nil {
+		return err
+	}
+	if tunnel != nil {
+		defer tunnel.Close()
+		config = &tunneledConfig
+	}
+
 	switch config.Type {
 	case constants.DatabaseTypePostgreSQL, constants.DatabaseTypeYugabyteDB:
 		var dsn string
@@ -585,6 +746,11 @@ func (m *Manager) TestConnection(config *ConnectionConfig) error {
 		// Configure client options
 		clientOptions := options.Client().ApplyURI(uri)
 
+		// Through an SSH tunnel only the forwarded host is reachable, so skip replica set discovery
+		if config.SSHEnabled {
+			clientOptions.SetDirect(true)
+		}
+
 		// Configure SSL/TLS
 		if config.UseSSL {
 			// Fetch certificates from URLs
@@ -697,6 +863,132 @@ func (m *Manager) TestConnection(config *ConnectionConfig) error {
 		log.Printf("DBManager -> TestConnection -> Successfully connected to MongoDB")
 		return nil
 
+	case constants.DatabaseTypeRedis:
+		redisOptions, tempFiles, err := buildRedisOptions(*config)
+		if err != nil {
+			return err
+		}
+
+		client := redis.NewClient(redisOptions)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// Ping the server to verify connection
+		err = client.Ping(ctx).Err()
+
+		// Close regardless of ping result
+		client.Close()
+
+		// Clean up temporary files
+		for _, file := range tempFiles {
+			os.Remove(file)
+		}
+
+		if err != nil {
+			log.Printf("DBManager -> TestConnection -> Error pinging Redis: %v", err)
+			return fmt.Errorf("failed to ping Redis: %v", err)
+		}
+
+		log.Printf("DBManager -> TestConnection -> Successfully connected to Redis")
+		return nil
+
+	case constants.DatabaseTypeCassandra:
+		cluster, tempFiles, err := buildCassandraCluster(*config)
+		if err != nil {
+			return err
+		}
+
+		// Only the contact points are needed to verify credentials
+		cluster.DisableInitialHostLookup = true
+		session, err := cluster.CreateSession()
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			err = pingCassandra(ctx, session)
+			cancel()
+			session.Close()
+		}
+
+		// Clean up temporary files
+		for _, file := range tempFiles {
+			os.Remove(file)
+		}
+
+		if err != nil {
+			log.Printf("DBManager -> TestConnection -> Error connecting to Cassandra: %v", err)
+			return fmt.Errorf("failed to connect to Cassandra: %v", err)
+		}
+
+		log.Printf("DBManager -> TestConnection -> Successfully connected to Cassandra")
+		return nil
+
+	case constants.DatabaseTypeNeo4j:
+		driver, tempFiles, err := buildNeo4jDriver(*config)
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			err = driver.VerifyConnectivity(ctx)
+			driver.Close(ctx)
+			cancel()
+		}
+
+		// Clean up temporary files
+		for _, file := range tempFiles {
+			os.Remove(file)
+		}
+
+		if err != nil {
+			log.Printf("DBManager -> TestConnection -> Error connecting to Neo4j: %v", err)
+			return fmt.Errorf("failed to connect to Neo4j: %v", err)
+		}
+
+		log.Printf("DBManager -> TestConnection -> Successfully connected to Neo4j")
+		return nil
+
+	case constants.DatabaseTypeMSSQL:
+		dsn, tempFiles, err := buildMSSQLDSN(*config)
+		if err != nil {
+			return err
+		}
+
+		db, err := sql.Open("sqlserver", dsn)
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			err = db.PingContext(ctx)
+			cancel()
+			db.Close()
+		}
+
+		// Clean up temporary files
+		for _, file := range tempFiles {
+			os.Remove(file)
+		}
+
+		if err != nil {
+			log.Printf("DBManager -> TestConnection -> Error pinging SQL Server: %v", err)
+			return fmt.Errorf("failed to ping SQL Server: %v", err)
+		}
+
+		log.Printf("DBManager -> TestConnection -> Successfully connected to SQL Server")
+		return nil
+
+	case constants.DatabaseTypeSQLite, constants.DatabaseTypeDuckDB:
+		// Opening the uploaded file verifies that it can be decrypted and read
+		driver, exists := m.drivers[config.Type]
+		if !exists {
+			return fmt.Errorf("unsupported data source type: %s", config.Type)
+		}
+
+		conn, err := driver.Connect(*config)
+		if err != nil {
+			log.Printf("DBManager -> TestConnection -> Error opening %s file: %v", config.Type, err)
+			return err
+		}
+		if err := driver.Disconnect(conn); err != nil {
+			log.Printf("DBManager -> TestConnection -> Error closing %s file: %v", config.Type, err)
+		}
+
+		log.Printf("DBManager -> TestConnection -> Successfully opened %s file", config.Type)
+		return nil
+
 	default:
 		return fmt.Errorf("unsupported data source type: %s", config.Type)
 	}
@@ -614,6 +614,11 @@ func (d *MongoDBDriver) Connect(config ConnectionConfig) (*Connection, error) {
 	// Configure client options
 	clientOptions := options.Client().ApplyURI(uri)
 
+	// Through an SSH tunnel only the forwarded host is reachable, so skip replica set discovery
+	if config.SSHEnabled {
+		clientOptions.SetDirect(true)
+	}
+
 	// Set a shorter connection timeout for encrypted connections
 	if strings.Contains(config.Host, "+") || strings.Contains(config.Host, "/") || strings.Contains(config.Host, "=") {
 		clientOptions.SetConnectTimeout(5 * time.Second)
@@ -695,6 +700,11 @@ func (d *MongoDBDriver) Connect(config ConnectionConfig) (*Connection, error) {
 	clientOptions.SetMinPoolSize(5)
 	clientOptions.SetMaxConnIdleTime(time.Hour)
 
+	// Read-only chats read from secondaries when there are any, writes are rejected before they reach the server
+	if config.ReadOnly {
+		clientOptions.SetReadPreference(readpref.SecondaryPreferred())
+	}
+
 	// Connect to MongoDB with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -725,6 +735,7 @@ func (d *MongoDBDriver) Connect(config ConnectionConfig) (*Connection, error) {
 	mongoWrapper := &MongoDBWrapper{
 		Client:   client,
 		Database: config.Database,
+		ReadOnly: config.ReadOnly,
 	}
 
 	// Create a connection object
@@ -2171,6 +2182,57 @@ func (d *MongoDBDriver) ExecuteQuery(ctx context.Context, conn *Connection, quer
 	}
 }
 
+// StreamQuery returns a cursor over the documents of a find or aggregate query, read batch by batch from the server.
+// countDocuments and distinct return a single document and are run with ExecuteQuery.
+func (d *MongoDBDriver) StreamQuery(ctx context.Context, conn *Connection, query string) (ResultCursor, error) {
+	wrapper, ok := conn.MongoDBObj.(*MongoDBWrapper)
+	if !ok || wrapper.Client == nil {
+		return nil, fmt.Errorf("MongoDB connection is not initialized")
+	}
+
+	_, command, err := parseMongoReadCommand(query)
+	if err != nil {
+		return newBufferedCursor(d.ExecuteQuery(ctx, conn, query, "", false))
+	}
+	if command[0].Key != "find" && command[0].Key != "aggregate" {
+		return newBufferedCursor(d.ExecuteQuery(ctx, conn, query, "", false))
+	}
+
+	cursor, err := wrapper.Client.Database(wrapper.Database).RunCommandCursor(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+	return &mongoResultCursor{cursor: cursor}, nil
+}
+
+// mongoResultCursor reads the documents of a MongoDB cursor
+type mongoResultCursor struct {
+	cursor *mongo.Cursor
+}
+
+func (c *mongoResultCursor) Columns() []string {
+	return nil
+}
+
+func (c *mongoResultCursor) Next(ctx context.Context, batchSize int) ([]map[string]interface{}, error) {
+	batch := make([]map[string]interface{}, 0, batchSize)
+	for len(batch) < batchSize && c.cursor.Next(ctx) {
+		var document bson.M
+		if err := c.cursor.Decode(&document); err != nil {
+			return nil, fmt.Errorf("failed to decode document: %v", err)
+		}
+		batch = append(batch, map[string]interface{}(document))
+	}
+	if err := c.cursor.Err(); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+func (c *mongoResultCursor) Close() error {
+	return c.cursor.Close(context.Background())
+}
+
 // BeginTx begins a MongoDB transaction
 func (d *MongoDBDriver) BeginTx(ctx context.Context, conn *Connection) Transaction {
 	log.Printf("MongoDBDriver -> BeginTx -> Beginning MongoDB transaction")
@@ -2239,7 +2301,8 @@ func (d *MongoDBDriver) BeginTx(ctx context.Context, conn *Connection) Transacti
 
 	// Start a transaction with retry logic
 	for attempts := 0; attempts < 3; attempts++ {
-		err = session.StartTransaction()
+		// Transactions must read from the primary, read-only connections default to secondaries
+		err = session.StartTransaction(options.Transaction().SetReadPreference(readpref.Primary()))
 		if err == nil {
 			break
 		}
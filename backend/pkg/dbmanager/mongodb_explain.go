@@ -0,0 +1,300 @@
+package dbmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"neobase-ai/internal/apis/dtos"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ExplainQuery returns the plan of the explain command of a find, findOne, aggregate, countDocuments or distinct
+// query, with the "executionStats" verbosity when analyze is set
+func (d *MongoDBDriver) ExplainQuery(ctx context.Context, conn *Connection, query string, analyze bool) (*dtos.QueryPlan, error) {
+	wrapper, ok := conn.MongoDBObj.(*MongoDBWrapper)
+	if !ok || wrapper.Client == nil {
+		return nil, fmt.Errorf("MongoDB connection is not initialized")
+	}
+
+	collection, command, err := parseMongoReadCommand(query)
+	if err != nil {
+		return nil, err
+	}
+	verbosity := "queryPlanner"
+	if analyze {
+		verbosity = "executionStats"
+	}
+
+	var explained bson.M
+	err = wrapper.Client.Database(wrapper.Database).RunCommand(ctx, bson.D{
+		{Key: "explain", Value: command},
+		{Key: "verbosity", Value: verbosity},
+	}).Decode(&explained)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain query: %v", err)
+	}
+
+	// Round trip through JSON so the plan only holds maps, slices and numbers
+	rawJSON, err := bson.MarshalExtJSON(explained, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query plan: %v", err)
+	}
+	var plan map[string]interface{}
+	if err := json.Unmarshal(rawJSON, &plan); err != nil {
+		return nil, fmt.Errorf("failed to read query plan: %v", err)
+	}
+
+	var root *dtos.QueryPlanNode
+	if stages, ok := plan["stages"].([]interface{}); ok {
+		// Aggregations list their stages, the first one reads the collection
+		root = &dtos.QueryPlanNode{Operation: "aggregate", Relation: collection}
+		for _, stage := range stages {
+			stageMap, ok := stage.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for name, value := range stageMap {
+				if name == "$cursor" {
+					if cursor, ok := value.(map[string]interface{}); ok {
+						root.Children = append(root.Children, mongoExplainRoot(cursor, collection, analyze))
+					}
+					continue
+				}
+				if strings.HasPrefix(name, "$") {
+					node := &dtos.QueryPlanNode{Operation: name}
+					if nReturned := planFloat(stageMap["nReturned"]); nReturned != nil && analyze {
+						node.ActualRows = nReturned
+					}
+					if stageTime := planFloat(stageMap["executionTimeMillisEstimate"]); stageTime != nil && analyze {
+						node.ActualTimeMs = stageTime
+					}
+					root.Children = append(root.Children, node)
+				}
+			}
+		}
+	} else {
+		root = mongoExplainRoot(plan, collection, analyze)
+	}
+	return newQueryPlan(root, analyze, string(rawJSON)), nil
+}
+
+// mongoExplainRoot returns the winning plan of an explain output, with its execution stats when analyzed
+func mongoExplainRoot(plan map[string]interface{}, collection string, analyze bool) *dtos.QueryPlanNode {
+	if analyze {
+		if stats, ok := plan["executionStats"].(map[string]interface{}); ok {
+			if stages, ok := stats["executionStages"].(map[string]interface{}); ok {
+				return mongoPlanNode(stages, collection, true)
+			}
+		}
+	}
+	if planner, ok := plan["queryPlanner"].(map[string]interface{}); ok {
+		if winningPlan, ok := planner["winningPlan"].(map[string]interface{}); ok {
+			// Plans of the slot based engine are nested in queryPlan
+			if queryPlan, ok := winningPlan["queryPlan"].(map[string]interface{}); ok {
+				winningPlan = queryPlan
+			}
+			return mongoPlanNode(winningPlan, collection, false)
+		}
+	}
+	return &dtos.QueryPlanNode{Operation: "unknown", Relation: collection}
+}
+
+// mongoPlanNode converts a stage of a plan and its input stages
+func mongoPlanNode(stage map[string]interface{}, collection string, analyze bool) *dtos.QueryPlanNode {
+	node := &dtos.QueryPlanNode{
+		Operation: planString(stage["stage"]),
+		Index:     planString(stage["indexName"]),
+	}
+	switch node.Operation {
+	case "COLLSCAN":
+		node.Relation = collection
+		node.FullScan = true
+	case "IXSCAN", "COUNT_SCAN", "DISTINCT_SCAN", "IDHACK", "EXPRESS_IXSCAN":
+		node.Relation = collection
+	}
+	if node.Operation == "IDHACK" && node.Index == "" {
+		node.Index = "_id_"
+	}
+
+	if keyPattern, ok := stage["keyPattern"].(map[string]interface{}); ok {
+		keys, _ := json.Marshal(keyPattern)
+		node.Details = append(node.Details, "Key pattern: "+string(keys))
+	}
+	if filter, ok := stage["filter"].(map[string]interface{}); ok {
+		filterJSON, _ := json.Marshal(filter)
+		node.Details = append(node.Details, "Filter: "+string(filterJSON))
+	}
+	if analyze {
+		node.ActualRows = planFloat(stage["nReturned"])
+		node.ActualTimeMs = planFloat(stage["executionTimeMillisEstimate"])
+		for _, key := range []string{"keysExamined", "docsExamined"} {
+			if value := planFloat(stage[key]); value != nil {
+				node.Details = append(node.Details, fmt.Sprintf("%s: %s", key, formatPlanNumber(*value)))
+			}
+		}
+	}
+
+	if input, ok := stage["inputStage"].(map[string]interface{}); ok {
+		node.Children = append(node.Children, mongoPlanNode(input, collection, analyze))
+	}
+	if inputs, ok := stage["inputStages"].([]interface{}); ok {
+		for _, input := range inputs {
+			if inputMap, ok := input.(map[string]interface{}); ok {
+				node.Children = append(node.Children, mongoPlanNode(inputMap, collection, analyze))
+			}
+		}
+	}
+	return node
+}
+
+// parseMongoReadCommand returns the collection and the database command of a read query, e.g. to be wrapped in explain
+func parseMongoReadCommand(query string) (string, bson.D, error) {
+	query = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+	parts := strings.SplitN(query, ".", 3)
+	if len(parts) < 3 || parts[0] != "db" {
+		return "", nil, fmt.Errorf("invalid MongoDB query format. Expected: db.collection.operation({...})")
+	}
+	collection := parts[1]
+	operationWithParams := parts[2]
+
+	openParenIndex := strings.Index(operationWithParams, "(")
+	if openParenIndex == -1 {
+		return "", nil, fmt.Errorf("invalid MongoDB query format. Expected: operation({...})")
+	}
+	operation := operationWithParams[:openParenIndex]
+	paramsStr, _, err := extractParenthesisContent(operationWithParams, openParenIndex)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid MongoDB query format: %v", err)
+	}
+	args := splitMongoArguments(paramsStr)
+
+	switch operation {
+	case "find", "findOne":
+		filter, err := parseMongoExplainDocument(mongoArgument(args, 0))
+		if err != nil {
+			return "", nil, err
+		}
+		modifiers := extractModifiers(query)
+		if modifiers.Count {
+			return collection, bson.D{{Key: "count", Value: collection}, {Key: "query", Value: filter}}, nil
+		}
+
+		command := bson.D{{Key: "find", Value: collection}, {Key: "filter", Value: filter}}
+		if projection := mongoArgument(args, 1); projection != "" {
+			projectionJSON, err := processProjectionParams(projection)
+			if err != nil {
+				return "", nil, fmt.Errorf("failed to process projection parameters: %v", err)
+			}
+			projectionDoc, err := parseMongoExplainDocument(projectionJSON)
+			if err != nil {
+				return "", nil, err
+			}
+			command = append(command, bson.E{Key: "projection", Value: projectionDoc})
+		}
+		if modifiers.Sort != "" {
+			sortStr := modifiers.Sort
+			if !strings.HasPrefix(sortStr, "{") {
+				sortStr = fmt.Sprintf(`{"%s": 1}`, sortStr)
+			}
+			sort, err := parseMongoExplainDocument(sortStr)
+			if err != nil {
+				return "", nil, err
+			}
+			command = append(command, bson.E{Key: "sort", Value: sort})
+		}
+		if operation == "findOne" {
+			command = append(command, bson.E{Key: "limit", Value: 1}, bson.E{Key: "singleBatch", Value: true})
+		} else if modifiers.Limit > 0 {
+			command = append(command, bson.E{Key: "limit", Value: modifiers.Limit})
+		}
+		if modifiers.Skip > 0 {
+			command = append(command, bson.E{Key: "skip", Value: modifiers.Skip})
+		}
+		return collection, command, nil
+
+	case "aggregate":
+		pipeline, err := parseAggregationPipeline(paramsStr)
+		if err != nil {
+			return "", nil, err
+		}
+		return collection, bson.D{{Key: "aggregate", Value: collection}, {Key: "pipeline", Value: pipeline}, {Key: "cursor", Value: bson.D{}}}, nil
+
+	case "countDocuments":
+		filter, err := parseMongoExplainDocument(mongoArgument(args, 0))
+		if err != nil {
+			return "", nil, err
+		}
+		return collection, bson.D{{Key: "count", Value: collection}, {Key: "query", Value: filter}}, nil
+
+	case "distinct":
+		field := strings.Trim(mongoArgument(args, 0), `"'`)
+		filter, err := parseMongoExplainDocument(mongoArgument(args, 1))
+		if err != nil {
+			return "", nil, err
+		}
+		return collection, bson.D{{Key: "distinct", Value: collection}, {Key: "key", Value: field}, {Key: "query", Value: filter}}, nil
+	}
+	return "", nil, fmt.Errorf("%s queries can't be explained", operation)
+}
+
+// parseMongoExplainDocument parses a document of a query, accepting MongoDB syntax with unquoted keys
+func parseMongoExplainDocument(document string) (bson.M, error) {
+	document = strings.TrimSpace(document)
+	if document == "" {
+		return bson.M{}, nil
+	}
+
+	var result bson.M
+	if err := json.Unmarshal([]byte(document), &result); err != nil {
+		jsonStr, err := processMongoDBQueryParams(document)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process query parameters: %v", err)
+		}
+		if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+			return nil, fmt.Errorf("failed to parse query parameters: %v", err)
+		}
+	}
+	if err := processObjectIds(result); err != nil {
+		return nil, fmt.Errorf("failed to process ObjectIds: %v", err)
+	}
+	return result, nil
+}
+
+// splitMongoArguments splits the arguments of a call on the commas outside of documents, arrays and strings
+func splitMongoArguments(params string) []string {
+	var args []string
+	depth := 0
+	var quote rune
+	start := 0
+	for i, char := range params {
+		switch {
+		case quote != 0:
+			if char == quote && (i == 0 || params[i-1] != '\\') {
+				quote = 0
+			}
+		case char == '"' || char == '\'':
+			quote = char
+		case char == '{' || char == '[' || char == '(':
+			depth++
+		case char == '}' || char == ']' || char == ')':
+			depth--
+		case char == ',' && depth == 0:
+			args = append(args, strings.TrimSpace(params[start:i]))
+			start = i + 1
+		}
+	}
+	if rest := strings.TrimSpace(params[start:]); rest != "" {
+		args = append(args, rest)
+	}
+	return args
+}
+
+// mongoArgument returns the argument at index, or "" when the call has fewer arguments
+func mongoArgument(args []string, index int) string {
+	if index < len(args) {
+		return args[index]
+	}
+	return ""
+}
@@ -0,0 +1,184 @@
+package dbmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Write operations whose documents are snapshotted before they run
+var mongoSnapshotOperations = map[string]bool{
+	"updateOne": true, "updateMany": true, "deleteOne": true, "deleteMany": true,
+}
+
+var mongoUpsertRegex = regexp.MustCompile(`upsert["']?\s*:\s*true`)
+
+// CaptureSnapshot reads the documents an updateOne, updateMany, deleteOne or deleteMany changes. The *One operations
+// change the first document matching their filter, which is the one read with the same filter and a limit of 1.
+func (tx *MongoDBTransaction) CaptureSnapshot(ctx context.Context, query string, maxRows int) (*QuerySnapshot, error) {
+	if tx.Wrapper == nil || tx.Wrapper.Client == nil {
+		return nil, fmt.Errorf("MongoDB connection is not initialized")
+	}
+
+	query = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+	parts := strings.SplitN(query, ".", 3)
+	if len(parts) < 3 || parts[0] != "db" {
+		return nil, nil
+	}
+	collectionName := parts[1]
+	openParenIndex := strings.Index(parts[2], "(")
+	if openParenIndex == -1 || !mongoSnapshotOperations[parts[2][:openParenIndex]] {
+		return nil, nil
+	}
+	operation := parts[2][:openParenIndex]
+	paramsStr, _, err := extractParenthesisContent(parts[2], openParenIndex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MongoDB query format: %v", err)
+	}
+	args := splitMongoArguments(paramsStr)
+	if strings.HasPrefix(operation, "update") && mongoUpsertRegex.MatchString(mongoArgument(args, 2)) {
+		return nil, fmt.Errorf("upserts can't be snapshotted")
+	}
+	filter, err := parseMongoExplainDocument(mongoArgument(args, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	findOptions := options.Find().SetLimit(int64(maxRows) + 1)
+	if strings.HasSuffix(operation, "One") {
+		findOptions.SetLimit(1)
+	}
+	collection := tx.Wrapper.Client.Database(tx.Wrapper.Database).Collection(collectionName)
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the documents: %v", err)
+	}
+	documents := []bson.D{}
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("failed to read the documents: %v", err)
+	}
+	if len(documents) > maxRows {
+		return nil, fmt.Errorf("the %s changes more than %d documents", operation, maxRows)
+	}
+
+	// Canonical extended JSON keeps the BSON types, e.g. ObjectIds, dates and 64-bit integers
+	encoded, err := bson.MarshalExtJSON(bson.D{{Key: "documents", Value: documents}}, true, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the documents: %v", err)
+	}
+
+	snapshot := &QuerySnapshot{
+		Operation:  "update",
+		Table:      collectionName,
+		KeyColumns: []string{"_id"},
+		RowCount:   len(documents),
+		Documents:  string(encoded),
+		CapturedAt: time.Now(),
+	}
+	if strings.HasPrefix(operation, "delete") {
+		snapshot.Operation = "delete"
+	}
+	snapshot.Inverse, err = mongoSnapshotInverse(snapshot, documents)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// mongoSnapshotInverse returns the readable form of the restore of the documents
+func mongoSnapshotInverse(snapshot *QuerySnapshot, documents []bson.D) (string, error) {
+	if len(documents) == 0 {
+		return "", nil
+	}
+	relaxed := make([]string, len(documents))
+	for i, document := range documents {
+		documentJSON, err := bson.MarshalExtJSON(document, false, false)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode the documents: %v", err)
+		}
+		relaxed[i] = string(documentJSON)
+	}
+	if snapshot.Operation == "delete" {
+		return fmt.Sprintf("db.%s.insertMany([%s])", snapshot.Table, strings.Join(relaxed, ", ")), nil
+	}
+
+	statements := make([]string, len(documents))
+	for i, document := range documents {
+		idJSON, err := bson.MarshalExtJSON(bson.D{{Key: "_id", Value: mongoDocumentID(document)}}, false, false)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode the documents: %v", err)
+		}
+		statements[i] = fmt.Sprintf("db.%s.replaceOne(%s, %s)", snapshot.Table, idJSON, relaxed[i])
+	}
+	return strings.Join(statements, ";\n"), nil
+}
+
+// RestoreSnapshot inserts the deleted documents back, or replaces the updated ones by their old version. Like the other
+// writes of the transaction, they run outside of its session so they also work on standalone servers.
+func (tx *MongoDBTransaction) RestoreSnapshot(ctx context.Context, snapshot *QuerySnapshot) (*QueryExecutionResult, error) {
+	if tx.Wrapper == nil || tx.Wrapper.Client == nil {
+		return nil, fmt.Errorf("MongoDB connection is not initialized")
+	}
+	startTime := time.Now()
+
+	var decoded struct {
+		Documents []bson.D `bson:"documents"`
+	}
+	if err := bson.UnmarshalExtJSON([]byte(snapshot.Documents), true, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode the snapshot: %v", err)
+	}
+
+	collection := tx.Wrapper.Client.Database(tx.Wrapper.Database).Collection(snapshot.Table)
+	var restored int64
+	switch snapshot.Operation {
+	case "delete":
+		if len(decoded.Documents) > 0 {
+			documents := make([]interface{}, len(decoded.Documents))
+			for i, document := range decoded.Documents {
+				documents[i] = document
+			}
+			insertResult, err := collection.InsertMany(ctx, documents)
+			if err != nil {
+				return nil, fmt.Errorf("failed to insert the deleted documents back: %v", err)
+			}
+			restored = int64(len(insertResult.InsertedIDs))
+		}
+	case "update":
+		for _, document := range decoded.Documents {
+			replaceResult, err := collection.ReplaceOne(ctx, bson.D{{Key: "_id", Value: mongoDocumentID(document)}}, document)
+			if err != nil {
+				return nil, fmt.Errorf("failed to replace the updated documents: %v", err)
+			}
+			restored += replaceResult.MatchedCount
+		}
+	default:
+		return nil, fmt.Errorf("unsupported snapshot operation: %s", snapshot.Operation)
+	}
+
+	result := &QueryExecutionResult{
+		Result: map[string]interface{}{
+			"restoredCount": restored,
+			"message":       fmt.Sprintf("%d document(s) of %s restored from the snapshot", restored, snapshot.Table),
+		},
+		ExecutionTime: int(time.Since(startTime).Milliseconds()),
+		RowsAffected:  restored,
+	}
+	result.StreamData, _ = json.Marshal(result.Result)
+	return result, nil
+}
+
+// mongoDocumentID returns the _id of a document
+func mongoDocumentID(document bson.D) interface{} {
+	for _, element := range document {
+		if element.Key == "_id" {
+			return element.Value
+		}
+	}
+	return nil
+}
@@ -200,6 +200,20 @@ func (tx *MongoDBTransaction) ExecuteQuery(ctx context.Context, query string) (*
 		}, nil
 	}
 
+	// Reject writes on read-only connections
+	if tx.Wrapper.ReadOnly {
+		if err := CheckMongoReadOnlyQuery(query); err != nil {
+			log.Printf("MongoDBTransaction -> ExecuteQuery -> Rejected query on read-only connection: %v", err)
+			return &QueryExecutionResult{
+				Error: &dtos.QueryError{
+					Message: "write commands are not allowed in read-only mode",
+					Code:    "READ_ONLY_MODE",
+					Details: err.Error(),
+				},
+			}, nil
+		}
+	}
+
 	// Verify the session is still valid by checking if the client is still connected
 	// This is a lightweight check that doesn't require a full ping
 	if tx.Wrapper.Client.NumberSessionsInProgress() == 0 {
@@ -1353,107 +1367,14 @@ func (tx *MongoDBTransaction) ExecuteQuery(ctx context.Context, query string) (*
 			}
 		}
 
-		var pipeline []bson.M
-
-		// Try to parse the pipeline directly as a JSON array
-		if err := json.Unmarshal([]byte(paramsStr), &pipeline); err != nil {
-			// If direct parsing fails, handle MongoDB syntax with unquoted keys
-			log.Printf("MongoDBTransaction -> ExecuteQuery -> Attempting to parse MongoDB aggregation pipeline: %s", paramsStr)
-
-			// Use the new parser to properly extract stages
-			stages, parseErr := ParseAggregationPipeline(paramsStr)
-			if parseErr != nil {
-				return &QueryExecutionResult{
-					Error: &dtos.QueryError{
-						Message: fmt.Sprintf("Failed to parse aggregation pipeline: %v", parseErr),
-						Code:    "INVALID_PARAMETERS",
-					},
-				}, nil
-			}
-
-			// Create an array of processed stages
-			processedStages := make([]string, 0, len(stages))
-
-			for i, stageContent := range stages {
-				// Trim any whitespace and trailing commas from the stage
-				stageContent = strings.TrimSpace(stageContent)
-				stageContent = strings.TrimSuffix(stageContent, ",")
-				
-				log.Printf("MongoDBTransaction -> ExecuteQuery -> Processing stage %d: %s", i, stageContent)
-				
-				// Check if this is a $project stage
-				if strings.Contains(stageContent, "$project") {
-					log.Printf("MongoDBTransaction -> ExecuteQuery -> Detected $project stage in pipeline")
-				}
-
-				// Process the stage content
-				processedStage, err := processMongoDBQueryParams(stageContent)
-				if err != nil {
-					return &QueryExecutionResult{
-						Error: &dtos.QueryError{
-							Message: fmt.Sprintf("Failed to process aggregation stage: %v", err),
-							Code:    "INVALID_PARAMETERS",
-						},
-					}, nil
-				}
-
-				// Clean up the processed stage - remove any trailing commas or whitespace
-				processedStage = strings.TrimSpace(processedStage)
-				processedStage = strings.TrimSuffix(processedStage, ",")
-				
-				// Don't replace dates with placeholders - let processObjectIds handle them
-				// This preserves the date values for proper BSON conversion
-
-				log.Printf("MongoDBTransaction -> ExecuteQuery -> Processed stage %d: %s", i, processedStage)
-				
-				// Only add non-empty stages
-				if processedStage != "" && processedStage != "," {
-					processedStages = append(processedStages, processedStage)
-				}
-			}
-
-			// Combine the processed stages into a valid JSON array
-			jsonStr := "[" + strings.Join(processedStages, ", ") + "]"
-			log.Printf("MongoDBTransaction -> ExecuteQuery -> Converted aggregation pipeline: %s", jsonStr)
-
-			// Don't replace dates - processObjectIds will handle them properly
-
-			// Fix any corrupted field names with extra double quotes
-			// This matches patterns like ""user.email"" and replaces them with "user.email"
-			fixFieldNamesPattern := regexp.MustCompile(`""([^"]+)""`)
-			jsonStr = fixFieldNamesPattern.ReplaceAllString(jsonStr, `"$1"`)
-
-			log.Printf("MongoDBTransaction -> ExecuteQuery -> Final aggregation pipeline after cleanup: %s", jsonStr)
-
-			// Don't replace dates - let processObjectIds handle proper BSON conversion
-
-			// Try to parse the cleaned-up JSON
-			if err := json.Unmarshal([]byte(jsonStr), &pipeline); err != nil {
-				log.Printf("MongoDBTransaction -> ExecuteQuery -> Error parsing pipeline JSON: %v", err)
-				return &QueryExecutionResult{
-					Error: &dtos.QueryError{
-						Message: fmt.Sprintf("Failed to parse aggregation pipeline after conversion: %v", err),
-						Code:    "INVALID_PARAMETERS",
-					},
-				}, nil
-			}
-			log.Printf("MongoDBTransaction -> ExecuteQuery -> Successfully parsed aggregation pipeline with %d stages", len(pipeline))
-		}
-
-		// Process dot notation fields in the pipeline for improved support of
-		// accessing fields from joined documents after $lookup and $unwind
-		ProcessDotNotationFields(map[string]interface{}{"pipeline": pipeline})
-
-		// Also use specialized processor for dot notation in aggregations
-		if err := processDotNotationInAggregation(pipeline); err != nil {
-			log.Printf("MongoDBTransaction -> ExecuteQuery -> Error processing dot notation in pipeline: %v", err)
-		}
-		
-		// Process ObjectIds and Dates in the pipeline
-		for _, stage := range pipeline {
-			if err := processObjectIds(stage); err != nil {
-				log.Printf("MongoDBTransaction -> ExecuteQuery -> Error processing ObjectIds/Dates in pipeline: %v", err)
-			}
+		pipeline, err := parseAggregationPipeline(paramsStr)
+		if err != nil {
+			return &QueryExecutionResult{
+				Error: &dtos.QueryError{
+					Message: err.Error(),
+					Code:    "INVALID_PARAMETERS",
+				},
+			}, nil
 		}
 
 		// Execute the aggregation
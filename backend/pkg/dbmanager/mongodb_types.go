@@ -11,6 +11,7 @@ import (
 type MongoDBWrapper struct {
 	Client   *mongo.Client
 	Database string
+	ReadOnly bool // Only read queries are executed
 }
 
 // MongoDBSchema represents the schema of a MongoDB database
@@ -1469,3 +1469,83 @@ func extractParenthesisContent(str string, startIndex int) (string, int, error)
 
 	return content, closeIndex, nil
 }
+
+// parseAggregationPipeline parses the pipeline of an aggregate() call, accepting MongoDB syntax with unquoted keys,
+// and converts its ObjectIds, dates and dot notation fields
+func parseAggregationPipeline(paramsStr string) ([]bson.M, error) {
+	var pipeline []bson.M
+
+	// Try to parse the pipeline directly as a JSON array
+	if err := json.Unmarshal([]byte(paramsStr), &pipeline); err != nil {
+		// If direct parsing fails, handle MongoDB syntax with unquoted keys
+		log.Printf("MongoDB -> parseAggregationPipeline -> Attempting to parse MongoDB aggregation pipeline: %s", paramsStr)
+
+		// Use the new parser to properly extract stages
+		stages, parseErr := ParseAggregationPipeline(paramsStr)
+		if parseErr != nil {
+			return nil, fmt.Errorf("Failed to parse aggregation pipeline: %v", parseErr)
+		}
+
+		// Create an array of processed stages
+		processedStages := make([]string, 0, len(stages))
+
+		for i, stageContent := range stages {
+			// Trim any whitespace and trailing commas from the stage
+			stageContent = strings.TrimSpace(stageContent)
+			stageContent = strings.TrimSuffix(stageContent, ",")
+
+			log.Printf("MongoDB -> parseAggregationPipeline -> Processing stage %d: %s", i, stageContent)
+
+			// Process the stage content
+			processedStage, err := processMongoDBQueryParams(stageContent)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to process aggregation stage: %v", err)
+			}
+
+			// Clean up the processed stage - remove any trailing commas or whitespace
+			processedStage = strings.TrimSpace(processedStage)
+			processedStage = strings.TrimSuffix(processedStage, ",")
+
+			log.Printf("MongoDB -> parseAggregationPipeline -> Processed stage %d: %s", i, processedStage)
+
+			// Only add non-empty stages
+			if processedStage != "" && processedStage != "," {
+				processedStages = append(processedStages, processedStage)
+			}
+		}
+
+		// Combine the processed stages into a valid JSON array
+		jsonStr := "[" + strings.Join(processedStages, ", ") + "]"
+
+		// Fix any corrupted field names with extra double quotes
+		// This matches patterns like ""user.email"" and replaces them with "user.email"
+		fixFieldNamesPattern := regexp.MustCompile(`""([^"]+)""`)
+		jsonStr = fixFieldNamesPattern.ReplaceAllString(jsonStr, `"$1"`)
+
+		log.Printf("MongoDB -> parseAggregationPipeline -> Final aggregation pipeline after cleanup: %s", jsonStr)
+
+		// Try to parse the cleaned-up JSON
+		if err := json.Unmarshal([]byte(jsonStr), &pipeline); err != nil {
+			log.Printf("MongoDB -> parseAggregationPipeline -> Error parsing pipeline JSON: %v", err)
+			return nil, fmt.Errorf("Failed to parse aggregation pipeline after conversion: %v", err)
+		}
+		log.Printf("MongoDB -> parseAggregationPipeline -> Successfully parsed aggregation pipeline with %d stages", len(pipeline))
+	}
+
+	// Process dot notation fields in the pipeline for improved support of
+	// accessing fields from joined documents after $lookup and $unwind
+	ProcessDotNotationFields(map[string]interface{}{"pipeline": pipeline})
+
+	// Also use specialized processor for dot notation in aggregations
+	if err := processDotNotationInAggregation(pipeline); err != nil {
+		log.Printf("MongoDB -> parseAggregationPipeline -> Error processing dot notation in pipeline: %v", err)
+	}
+
+	// Process ObjectIds and Dates in the pipeline
+	for _, stage := range pipeline {
+		if err := processObjectIds(stage); err != nil {
+			log.Printf("MongoDB -> parseAggregationPipeline -> Error processing ObjectIds/Dates in pipeline: %v", err)
+		}
+	}
+	return pipeline, nil
+}
@@ -0,0 +1,406 @@
+package dbmanager
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/utils"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	mssql "github.com/microsoft/go-mssqldb"
+	"gorm.io/driver/sqlserver"
+	"gorm.io/gorm"
+)
+
+// MSSQLDriver implements the DatabaseDriver interface for Microsoft SQL Server
+type MSSQLDriver struct{}
+
+// NewMSSQLDriver creates a new SQL Server driver
+func NewMSSQLDriver() DatabaseDriver {
+	return &MSSQLDriver{}
+}
+
+// buildMSSQLDSN builds the sqlserver:// connection string, returning any temporary certificate files it created
+func buildMSSQLDSN(config ConnectionConfig) (string, []string, error) {
+	var tempFiles []string
+
+	host := config.Host
+	instance := ""
+	// Named instances are written as host\instance
+	if before, after, found := strings.Cut(host, `\`); found {
+		host, instance = before, after
+	}
+
+	dsnURL := &url.URL{
+		Scheme: "sqlserver",
+		Host:   host,
+		Path:   instance,
+	}
+	if config.Port != nil && *config.Port != "" && instance == "" {
+		dsnURL.Host = net.JoinHostPort(host, *config.Port)
+	}
+	if config.Username != nil {
+		if config.Password != nil {
+			dsnURL.User = url.UserPassword(*config.Username, *config.Password)
+		} else {
+			dsnURL.User = url.User(*config.Username)
+		}
+	}
+
+	params := url.Values{}
+	params.Set("database", config.Database)
+	params.Set("app name", "NeoBase")
+
+	// Configure SSL/TLS
+	if config.UseSSL {
+		sslMode := "require"
+		if config.SSLMode != nil {
+			sslMode = *config.SSLMode
+		}
+
+		switch sslMode {
+		case "disable":
+			params.Set("encrypt", "disable")
+		case "require":
+			// Require encryption but don't verify certificates
+			params.Set("encrypt", "true")
+			params.Set("TrustServerCertificate", "true")
+		case "verify-ca", "verify-full":
+			params.Set("encrypt", "true")
+			params.Set("TrustServerCertificate", "false")
+
+			// SQL Server doesn't use client certificates, only the CA certificate is needed
+			if config.SSLRootCertURL != nil && *config.SSLRootCertURL != "" {
+				_, _, rootCertPath, certTempFiles, err := utils.PrepareCertificatesFromURLs("", "", *config.SSLRootCertURL)
+				if err != nil {
+					return "", nil, err
+				}
+				tempFiles = certTempFiles
+				params.Set("certificate", rootCertPath)
+			}
+			if sslMode == "verify-full" {
+				params.Set("hostNameInCertificate", host)
+			}
+		}
+	}
+
+	dsnURL.RawQuery = params.Encode()
+	return dsnURL.String(), tempFiles, nil
+}
+
+// Connect establishes a connection to a SQL Server database
+func (d *MSSQLDriver) Connect(config ConnectionConfig) (*Connection, error) {
+	dsn, tempFiles, err := buildMSSQLDSN(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Open connection
+	db, err := sql.Open("sqlserver", dsn)
+	if err != nil {
+		// Clean up temporary files
+		for _, file := range tempFiles {
+			os.Remove(file)
+		}
+		return nil, err
+	}
+
+	// Test connection
+	if err := db.Ping(); err != nil {
+		// Clean up temporary files
+		for _, file := range tempFiles {
+			os.Remove(file)
+		}
+		db.Close()
+		return nil, err
+	}
+
+	// Configure connection pool
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(time.Hour)
+
+	// Create GORM DB on top of the verified connection
+	gormDB, err := gorm.Open(sqlserver.New(sqlserver.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	if err != nil {
+		// Clean up temporary files
+		for _, file := range tempFiles {
+			os.Remove(file)
+		}
+		db.Close()
+		return nil, fmt.Errorf("failed to create GORM connection: %v", err)
+	}
+
+	// Create connection object
+	conn := &Connection{
+		DB:          gormDB,
+		LastUsed:    time.Now(),
+		Status:      StatusConnected,
+		Config:      config,
+		Subscribers: make(map[string]bool),
+		SubLock:     sync.RWMutex{},
+		TempFiles:   tempFiles,
+	}
+
+	return conn, nil
+}
+
+// Disconnect closes a SQL Server database connection
+func (d *MSSQLDriver) Disconnect(conn *Connection) error {
+	// Get the underlying SQL DB
+	sqlDB, err := conn.DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get SQL DB: %v", err)
+	}
+
+	// Close the connection
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("failed to close connection: %v", err)
+	}
+
+	// Clean up temporary certificate files
+	for _, file := range conn.TempFiles {
+		os.Remove(file)
+	}
+
+	return nil
+}
+
+// Ping checks if the SQL Server connection is alive
+func (d *MSSQLDriver) Ping(conn *Connection) error {
+	if conn == nil || conn.DB == nil {
+		return fmt.Errorf("no active connection to ping")
+	}
+
+	sqlDB, err := conn.DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %v", err)
+	}
+
+	return sqlDB.Ping()
+}
+
+// IsAlive checks if the SQL Server connection is still valid
+func (d *MSSQLDriver) IsAlive(conn *Connection) bool {
+	return d.Ping(conn) == nil
+}
+
+// ExecuteQuery executes a T-SQL query on the SQL Server database
+func (d *MSSQLDriver) ExecuteQuery(ctx context.Context, conn *Connection, query string, queryType string, findCount bool) *QueryExecutionResult {
+	if conn == nil || conn.DB == nil {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: "No active connection",
+				Code:    "CONNECTION_ERROR",
+			},
+		}
+	}
+
+	return executeMSSQLQuery(ctx, conn.DB, query)
+}
+
+// executeMSSQLQuery runs each statement of a T-SQL script, the result of the last statement is returned
+func executeMSSQLQuery(ctx context.Context, db *gorm.DB, query string) *QueryExecutionResult {
+	startTime := time.Now()
+	result := &QueryExecutionResult{}
+
+	// Split the query into individual statements
+	statements := splitMSSQLStatements(query)
+
+	for _, stmt := range statements {
+		// Check for context cancellation
+		if ctx.Err() != nil {
+			result.Error = &dtos.QueryError{
+				Message: "Query execution cancelled",
+				Code:    "EXECUTION_CANCELLED",
+			}
+			return result
+		}
+
+		if isMSSQLReadStatement(stmt) {
+			rows, err := scanMSSQLRows(db.WithContext(ctx), stmt)
+			if err != nil {
+				result.Error = &dtos.QueryError{
+					Message: err.Error(),
+					Code:    "EXECUTION_ERROR",
+				}
+				return result
+			}
+
+			result.Result = map[string]interface{}{
+				"results": rows,
+			}
+		} else {
+			// For other queries (INSERT, UPDATE, DELETE, DDL), execute and return affected rows
+			execResult := db.WithContext(ctx).Exec(stmt)
+			if execResult.Error != nil {
+				result.Error = &dtos.QueryError{
+					Message: execResult.Error.Error(),
+					Code:    "EXECUTION_ERROR",
+				}
+				return result
+			}
+
+			rowsAffected := execResult.RowsAffected
+			result.RowsAffected += rowsAffected
+			if rowsAffected > 0 {
+				result.Result = map[string]interface{}{
+					"rowsAffected": rowsAffected,
+					"message":      fmt.Sprintf("%d row(s) affected", rowsAffected),
+				}
+			} else {
+				result.Result = map[string]interface{}{
+					"message": "Query performed successfully",
+				}
+			}
+		}
+	}
+
+	result.ExecutionTime = int(time.Since(startTime).Milliseconds())
+
+	// Marshal the result to JSON
+	resultJSON, err := json.Marshal(result.Result)
+	if err != nil {
+		return &QueryExecutionResult{
+			ExecutionTime: int(time.Since(startTime).Milliseconds()),
+			Error: &dtos.QueryError{
+				Code:    "JSON_MARSHAL_FAILED",
+				Message: err.Error(),
+				Details: "Failed to marshal query results",
+			},
+		}
+	}
+	result.StreamData = resultJSON
+
+	return result
+}
+
+// scanMSSQLRows scans a query into maps, converting SQL Server specific values by their column type
+func scanMSSQLRows(db *gorm.DB, query string, values ...interface{}) ([]map[string]interface{}, error) {
+	rows, err := db.Raw(query, values...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		rowValues := make([]interface{}, len(columnTypes))
+		rowPointers := make([]interface{}, len(columnTypes))
+		for i := range rowValues {
+			rowPointers[i] = &rowValues[i]
+		}
+		if err := rows.Scan(rowPointers...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columnTypes))
+		for i, columnType := range columnTypes {
+			row[columnType.Name()] = normalizeMSSQLValue(columnType.DatabaseTypeName(), rowValues[i])
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err()
+}
+
+// normalizeMSSQLValue converts raw driver values into JSON friendly types
+func normalizeMSSQLValue(databaseType string, value interface{}) interface{} {
+	raw, ok := value.([]byte)
+	if !ok {
+		return value
+	}
+
+	switch strings.ToUpper(databaseType) {
+	case "UNIQUEIDENTIFIER":
+		// GUIDs are returned in SQL Server's mixed endian byte order
+		var guid mssql.UniqueIdentifier
+		if err := guid.Scan(raw); err == nil {
+			return guid.String()
+		}
+		return strings.ToUpper(hex.EncodeToString(raw))
+	case "BINARY", "VARBINARY", "IMAGE", "TIMESTAMP", "ROWVERSION":
+		return "0x" + strings.ToUpper(hex.EncodeToString(raw))
+	default:
+		// DECIMAL, NUMERIC and MONEY values are returned as their text representation
+		return string(raw)
+	}
+}
+
+// StreamQuery runs a read query and returns a cursor over its rows, SQL Server has no read-only transactions
+func (d *MSSQLDriver) StreamQuery(ctx context.Context, conn *Connection, query string) (ResultCursor, error) {
+	return streamSQLQuery(ctx, conn, query, nil, func(columnType *sql.ColumnType, value interface{}) interface{} {
+		return normalizeMSSQLValue(columnType.DatabaseTypeName(), value)
+	})
+}
+
+// BeginTx starts a new transaction
+func (d *MSSQLDriver) BeginTx(ctx context.Context, conn *Connection) Transaction {
+	if conn == nil || conn.DB == nil {
+		log.Printf("MSSQLDriver.BeginTx: Connection or DB is nil")
+		return nil
+	}
+
+	// Start a new transaction
+	tx := conn.DB.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		log.Printf("Failed to begin transaction: %v", tx.Error)
+		return nil
+	}
+
+	return &MSSQLTransaction{
+		tx:   tx,
+		conn: conn,
+	}
+}
+
+// GetSchema retrieves the database schema
+func (d *MSSQLDriver) GetSchema(ctx context.Context, db DBExecutor, selectedTables []string) (*SchemaInfo, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("MSSQLDriver -> GetSchema -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	fetcher := NewMSSQLSchemaFetcher(db)
+	return fetcher.GetSchema(ctx, db, selectedTables)
+}
+
+// GetTableChecksum calculates a checksum for a table
+func (d *MSSQLDriver) GetTableChecksum(ctx context.Context, db DBExecutor, table string) (string, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("MSSQLDriver -> GetTableChecksum -> Context cancelled: %v", err)
+		return "", err
+	}
+
+	fetcher := NewMSSQLSchemaFetcher(db)
+	return fetcher.GetTableChecksum(ctx, db, table)
+}
+
+// FetchExampleRecords fetches example records from a table
+func (d *MSSQLDriver) FetchExampleRecords(ctx context.Context, db DBExecutor, table string, limit int) ([]map[string]interface{}, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("MSSQLDriver -> FetchExampleRecords -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	fetcher := NewMSSQLSchemaFetcher(db)
+	return fetcher.FetchExampleRecords(ctx, db, table, limit)
+}
@@ -0,0 +1,511 @@
+package dbmanager
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// MSSQLSchemaFetcher implements schema fetching for SQL Server
+type MSSQLSchemaFetcher struct {
+	db DBExecutor
+}
+
+// NewMSSQLSchemaFetcher creates a new SQL Server schema fetcher
+func NewMSSQLSchemaFetcher(db DBExecutor) SchemaFetcher {
+	return &MSSQLSchemaFetcher{db: db}
+}
+
+// GetSchema retrieves the schema for the selected tables
+func (f *MSSQLSchemaFetcher) GetSchema(ctx context.Context, db DBExecutor, selectedTables []string) (*SchemaInfo, error) {
+	log.Printf("MSSQLSchemaFetcher -> GetSchema -> Starting schema fetch with selected tables: %v", selectedTables)
+
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("MSSQLSchemaFetcher -> GetSchema -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	schema, err := f.FetchSchema(ctx)
+	if err != nil {
+		log.Printf("MSSQLSchemaFetcher -> GetSchema -> Error fetching schema: %v", err)
+		return nil, err
+	}
+
+	log.Printf("MSSQLSchemaFetcher -> GetSchema -> Successfully fetched schema with %d tables", len(schema.Tables))
+
+	return f.filterSchemaForSelectedTables(schema, selectedTables), nil
+}
+
+// FetchSchema retrieves the full database schema
+func (f *MSSQLSchemaFetcher) FetchSchema(ctx context.Context) (*SchemaInfo, error) {
+	schema := &SchemaInfo{
+		Tables:    make(map[string]TableSchema),
+		Views:     make(map[string]ViewSchema),
+		UpdatedAt: time.Now(),
+	}
+
+	tables, comments, err := f.fetchTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range tables {
+		// Check for context cancellation
+		if err := ctx.Err(); err != nil {
+			log.Printf("MSSQLSchemaFetcher -> FetchSchema -> Context cancelled: %v", err)
+			return nil, err
+		}
+
+		tableSchema := TableSchema{
+			Name:    table.key(),
+			Comment: comments[table.key()],
+		}
+
+		columns, err := f.fetchColumns(ctx, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch columns for table %s: %v", table.key(), err)
+		}
+		tableSchema.Columns = columns
+
+		tableSchema.Indexes = f.fetchIndexes(ctx, table)
+		tableSchema.Constraints = f.fetchConstraints(ctx, table)
+		tableSchema.ForeignKeys = f.fetchForeignKeys(ctx, table)
+		tableSchema.RowCount = f.getTableRowCount(ctx, table)
+
+		// Calculate table schema checksum
+		tableData, _ := json.Marshal(tableSchema)
+		tableSchema.Checksum = fmt.Sprintf("%x", md5.Sum(tableData))
+
+		schema.Tables[table.key()] = tableSchema
+	}
+
+	schema.Views = f.fetchViews(ctx)
+
+	// Calculate overall schema checksum
+	schemaData, _ := json.Marshal(schema.Tables)
+	schema.Checksum = fmt.Sprintf("%x", md5.Sum(schemaData))
+
+	log.Printf("MSSQLSchemaFetcher -> FetchSchema -> Fetched %d tables and %d views", len(schema.Tables), len(schema.Views))
+	return schema, nil
+}
+
+// fetchTables retrieves all user tables of the database along with their MS_Description comments
+func (f *MSSQLSchemaFetcher) fetchTables(_ context.Context) ([]mssqlTable, map[string]string, error) {
+	var rows []map[string]interface{}
+	query := `
+        SELECT s.name AS schema_name, t.name AS table_name, CAST(ep.value AS NVARCHAR(4000)) AS comment
+        FROM sys.tables t
+        JOIN sys.schemas s ON s.schema_id = t.schema_id
+        LEFT JOIN sys.extended_properties ep
+            ON ep.major_id = t.object_id AND ep.minor_id = 0 AND ep.class = 1 AND ep.name = 'MS_Description'
+        WHERE t.is_ms_shipped = 0
+        ORDER BY s.name, t.name
+    `
+	if err := f.db.QueryRows(query, &rows); err != nil {
+		log.Printf("MSSQLSchemaFetcher -> fetchTables -> Error: %v", err)
+		return nil, nil, fmt.Errorf("failed to fetch tables: %v", err)
+	}
+
+	tables := make([]mssqlTable, 0, len(rows))
+	comments := make(map[string]string)
+	for _, row := range rows {
+		table := mssqlTable{Schema: sqliteString(row["schema_name"]), Name: sqliteString(row["table_name"])}
+		tables = append(tables, table)
+		if comment := sqliteString(row["comment"]); comment != "" {
+			comments[table.key()] = comment
+		}
+	}
+	return tables, comments, nil
+}
+
+// fetchColumns retrieves all columns of a table
+func (f *MSSQLSchemaFetcher) fetchColumns(_ context.Context, table mssqlTable) (map[string]ColumnInfo, error) {
+	columns := make(map[string]ColumnInfo)
+
+	var rows []map[string]interface{}
+	query := `
+        SELECT c.name AS column_name, ty.name AS type_name, c.max_length, c.precision, c.scale,
+            c.is_nullable, c.is_identity, c.is_computed, dc.definition AS column_default,
+            CAST(ep.value AS NVARCHAR(4000)) AS comment
+        FROM sys.columns c
+        JOIN sys.tables t ON t.object_id = c.object_id
+        JOIN sys.schemas s ON s.schema_id = t.schema_id
+        JOIN sys.types ty ON ty.user_type_id = c.user_type_id
+        LEFT JOIN sys.default_constraints dc ON dc.object_id = c.default_object_id
+        LEFT JOIN sys.extended_properties ep
+            ON ep.major_id = c.object_id AND ep.minor_id = c.column_id AND ep.class = 1 AND ep.name = 'MS_Description'
+        WHERE s.name = ? AND t.name = ?
+        ORDER BY c.column_id
+    `
+	if err := f.db.QueryRows(query, &rows, table.Schema, table.Name); err != nil {
+		log.Printf("MSSQLSchemaFetcher -> fetchColumns -> Error for table %s: %v", table.key(), err)
+		return nil, err
+	}
+
+	for _, row := range rows {
+		name := sqliteString(row["column_name"])
+		columnType := formatMSSQLType(sqliteString(row["type_name"]), sqliteInt(row["max_length"]), sqliteInt(row["precision"]), sqliteInt(row["scale"]))
+		if sqliteInt(row["is_identity"]) == 1 {
+			columnType += " IDENTITY"
+		}
+
+		defaultValue := sqliteString(row["column_default"])
+		if sqliteInt(row["is_computed"]) == 1 && defaultValue == "" {
+			defaultValue = "COMPUTED"
+		}
+
+		columns[name] = ColumnInfo{
+			Name:         name,
+			Type:         columnType,
+			IsNullable:   sqliteInt(row["is_nullable"]) == 1,
+			DefaultValue: defaultValue,
+			Comment:      sqliteString(row["comment"]),
+		}
+	}
+	return columns, nil
+}
+
+// formatMSSQLType builds the declared column type from sys.columns, such as nvarchar(100) or decimal(10,2)
+func formatMSSQLType(typeName string, maxLength, precision, scale int64) string {
+	switch strings.ToLower(typeName) {
+	case "varchar", "char", "varbinary", "binary":
+		if maxLength == -1 {
+			return typeName + "(max)"
+		}
+		return fmt.Sprintf("%s(%d)", typeName, maxLength)
+	case "nvarchar", "nchar":
+		// Lengths of unicode types are stored in bytes
+		if maxLength == -1 {
+			return typeName + "(max)"
+		}
+		return fmt.Sprintf("%s(%d)", typeName, maxLength/2)
+	case "decimal", "numeric":
+		return fmt.Sprintf("%s(%d,%d)", typeName, precision, scale)
+	case "datetime2", "datetimeoffset", "time":
+		return fmt.Sprintf("%s(%d)", typeName, scale)
+	default:
+		return typeName
+	}
+}
+
+// fetchIndexes retrieves the indexes of a table, excluding heaps
+func (f *MSSQLSchemaFetcher) fetchIndexes(_ context.Context, table mssqlTable) map[string]IndexInfo {
+	indexes := make(map[string]IndexInfo)
+
+	var rows []map[string]interface{}
+	query := `
+        SELECT i.name AS index_name, i.is_unique, c.name AS column_name
+        FROM sys.indexes i
+        JOIN sys.tables t ON t.object_id = i.object_id
+        JOIN sys.schemas s ON s.schema_id = t.schema_id
+        JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+        JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+        WHERE s.name = ? AND t.name = ? AND i.type > 0 AND ic.is_included_column = 0
+        ORDER BY i.name, ic.key_ordinal
+    `
+	if err := f.db.QueryRows(query, &rows, table.Schema, table.Name); err != nil {
+		log.Printf("MSSQLSchemaFetcher -> fetchIndexes -> Error for table %s: %v", table.key(), err)
+		return indexes
+	}
+
+	for _, row := range rows {
+		name := sqliteString(row["index_name"])
+		index := indexes[name]
+		index.Name = name
+		index.IsUnique = sqliteInt(row["is_unique"]) == 1
+		index.Columns = append(index.Columns, sqliteString(row["column_name"]))
+		indexes[name] = index
+	}
+	return indexes
+}
+
+// fetchConstraints retrieves the primary key, unique and check constraints of a table
+func (f *MSSQLSchemaFetcher) fetchConstraints(_ context.Context, table mssqlTable) map[string]ConstraintInfo {
+	constraints := make(map[string]ConstraintInfo)
+
+	var keyRows []map[string]interface{}
+	keyQuery := `
+        SELECT kc.name AS constraint_name, kc.type AS constraint_type, c.name AS column_name
+        FROM sys.key_constraints kc
+        JOIN sys.tables t ON t.object_id = kc.parent_object_id
+        JOIN sys.schemas s ON s.schema_id = t.schema_id
+        JOIN sys.index_columns ic ON ic.object_id = kc.parent_object_id AND ic.index_id = kc.unique_index_id
+        JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+        WHERE s.name = ? AND t.name = ?
+        ORDER BY kc.name, ic.key_ordinal
+    `
+	if err := f.db.QueryRows(keyQuery, &keyRows, table.Schema, table.Name); err != nil {
+		log.Printf("MSSQLSchemaFetcher -> fetchConstraints -> Error fetching keys for table %s: %v", table.key(), err)
+	}
+
+	for _, row := range keyRows {
+		// The primary key is stored as PRIMARY like for the other SQL databases
+		name, constraintType := sqliteString(row["constraint_name"]), "UNIQUE"
+		if strings.TrimSpace(sqliteString(row["constraint_type"])) == "PK" {
+			name, constraintType = "PRIMARY", "PRIMARY KEY"
+		}
+
+		constraint := constraints[name]
+		constraint.Name = name
+		constraint.Type = constraintType
+		constraint.Columns = append(constraint.Columns, sqliteString(row["column_name"]))
+		constraints[name] = constraint
+	}
+
+	var checkRows []map[string]interface{}
+	checkQuery := `
+        SELECT cc.name AS constraint_name, cc.definition, c.name AS column_name
+        FROM sys.check_constraints cc
+        JOIN sys.tables t ON t.object_id = cc.parent_object_id
+        JOIN sys.schemas s ON s.schema_id = t.schema_id
+        LEFT JOIN sys.columns c ON c.object_id = cc.parent_object_id AND c.column_id = cc.parent_column_id
+        WHERE s.name = ? AND t.name = ?
+    `
+	if err := f.db.QueryRows(checkQuery, &checkRows, table.Schema, table.Name); err != nil {
+		log.Printf("MSSQLSchemaFetcher -> fetchConstraints -> Error fetching checks for table %s: %v", table.key(), err)
+	}
+
+	for _, row := range checkRows {
+		name := sqliteString(row["constraint_name"])
+		var columns []string
+		// Table level checks aren't tied to a single column
+		if column := sqliteString(row["column_name"]); column != "" {
+			columns = []string{column}
+		}
+		constraints[name] = ConstraintInfo{
+			Name:       name,
+			Type:       "CHECK",
+			Definition: sqliteString(row["definition"]),
+			Columns:    columns,
+		}
+	}
+
+	return constraints
+}
+
+// fetchForeignKeys retrieves all foreign keys of a table, composite keys get one entry per column
+func (f *MSSQLSchemaFetcher) fetchForeignKeys(_ context.Context, table mssqlTable) map[string]ForeignKey {
+	fkeys := make(map[string]ForeignKey)
+
+	var rows []map[string]interface{}
+	query := `
+        SELECT fk.name AS constraint_name, pc.name AS column_name,
+            rs.name AS ref_schema, rt.name AS ref_table, rc.name AS ref_column,
+            fk.delete_referential_action_desc AS on_delete, fk.update_referential_action_desc AS on_update
+        FROM sys.foreign_keys fk
+        JOIN sys.tables t ON t.object_id = fk.parent_object_id
+        JOIN sys.schemas s ON s.schema_id = t.schema_id
+        JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+        JOIN sys.columns pc ON pc.object_id = fkc.parent_object_id AND pc.column_id = fkc.parent_column_id
+        JOIN sys.tables rt ON rt.object_id = fkc.referenced_object_id
+        JOIN sys.schemas rs ON rs.schema_id = rt.schema_id
+        JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+        WHERE s.name = ? AND t.name = ?
+        ORDER BY fk.name, fkc.constraint_column_id
+    `
+	if err := f.db.QueryRows(query, &rows, table.Schema, table.Name); err != nil {
+		log.Printf("MSSQLSchemaFetcher -> fetchForeignKeys -> Error for table %s: %v", table.key(), err)
+		return fkeys
+	}
+
+	for _, row := range rows {
+		name := sqliteString(row["constraint_name"])
+		columnName := sqliteString(row["column_name"])
+		if _, exists := fkeys[name]; exists {
+			name = fmt.Sprintf("%s_%s", name, columnName)
+		}
+
+		refTable := mssqlTable{Schema: sqliteString(row["ref_schema"]), Name: sqliteString(row["ref_table"])}
+		fkeys[name] = ForeignKey{
+			Name:       name,
+			ColumnName: columnName,
+			RefTable:   refTable.key(),
+			RefColumn:  sqliteString(row["ref_column"]),
+			// Actions are reported as NO_ACTION, SET_NULL, etc.
+			OnDelete: strings.ReplaceAll(sqliteString(row["on_delete"]), "_", " "),
+			OnUpdate: strings.ReplaceAll(sqliteString(row["on_update"]), "_", " "),
+		}
+	}
+	return fkeys
+}
+
+// fetchViews retrieves all user views of the database
+func (f *MSSQLSchemaFetcher) fetchViews(_ context.Context) map[string]ViewSchema {
+	views := make(map[string]ViewSchema)
+
+	var rows []map[string]interface{}
+	query := `
+        SELECT s.name AS schema_name, v.name AS view_name, OBJECT_DEFINITION(v.object_id) AS definition
+        FROM sys.views v
+        JOIN sys.schemas s ON s.schema_id = v.schema_id
+        WHERE v.is_ms_shipped = 0
+    `
+	if err := f.db.QueryRows(query, &rows); err != nil {
+		log.Printf("MSSQLSchemaFetcher -> fetchViews -> Error: %v", err)
+		return views
+	}
+
+	for _, row := range rows {
+		view := mssqlTable{Schema: sqliteString(row["schema_name"]), Name: sqliteString(row["view_name"])}
+		views[view.key()] = ViewSchema{
+			Name:       view.key(),
+			Definition: sqliteString(row["definition"]),
+		}
+	}
+	return views
+}
+
+// getTableRowCount gets the number of rows in a table from its partitions, which avoids scanning the table
+func (f *MSSQLSchemaFetcher) getTableRowCount(_ context.Context, table mssqlTable) int64 {
+	var count int64
+	query := `
+        SELECT COALESCE(SUM(p.rows), 0)
+        FROM sys.partitions p
+        JOIN sys.tables t ON t.object_id = p.object_id
+        JOIN sys.schemas s ON s.schema_id = t.schema_id
+        WHERE s.name = ? AND t.name = ? AND p.index_id IN (0, 1)
+    `
+	if err := f.db.Query(query, &count, table.Schema, table.Name); err != nil {
+		log.Printf("MSSQLSchemaFetcher -> getTableRowCount -> Error for table %s: %v", table.key(), err)
+		return 0
+	}
+	return count
+}
+
+// GetTableChecksum calculates a checksum for a table's structure from its column, index and key definitions
+func (f *MSSQLSchemaFetcher) GetTableChecksum(ctx context.Context, db DBExecutor, table string) (string, error) {
+	parsed := parseMSSQLTable(table)
+
+	var definitions []string
+	query := `
+        SELECT CONCAT('column:', c.name, ':', ty.name, ':', c.max_length, ':', c.precision, ':', c.scale, ':', c.is_nullable, ':', dc.definition)
+        FROM sys.columns c
+        JOIN sys.tables t ON t.object_id = c.object_id
+        JOIN sys.schemas s ON s.schema_id = t.schema_id
+        JOIN sys.types ty ON ty.user_type_id = c.user_type_id
+        LEFT JOIN sys.default_constraints dc ON dc.object_id = c.default_object_id
+        WHERE s.name = ? AND t.name = ?
+        UNION ALL
+        SELECT CONCAT('index:', i.name, ':', i.is_unique, ':', c.name, ':', ic.key_ordinal)
+        FROM sys.indexes i
+        JOIN sys.tables t ON t.object_id = i.object_id
+        JOIN sys.schemas s ON s.schema_id = t.schema_id
+        JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+        JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+        WHERE s.name = ? AND t.name = ?
+        UNION ALL
+        SELECT CONCAT('fk:', fk.name, ':', OBJECT_NAME(fkc.referenced_object_id), ':', fkc.parent_column_id, ':', fkc.referenced_column_id)
+        FROM sys.foreign_keys fk
+        JOIN sys.tables t ON t.object_id = fk.parent_object_id
+        JOIN sys.schemas s ON s.schema_id = t.schema_id
+        JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+        WHERE s.name = ? AND t.name = ?
+        ORDER BY 1
+    `
+	if err := db.Query(query, &definitions, parsed.Schema, parsed.Name, parsed.Schema, parsed.Name, parsed.Schema, parsed.Name); err != nil {
+		return "", fmt.Errorf("failed to get table definition: %v", err)
+	}
+	if len(definitions) == 0 {
+		return "", fmt.Errorf("table %s not found", table)
+	}
+
+	return fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(definitions, "\n")))), nil
+}
+
+// FetchExampleRecords retrieves sample records from a table
+func (f *MSSQLSchemaFetcher) FetchExampleRecords(ctx context.Context, db DBExecutor, table string, limit int) ([]map[string]interface{}, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("MSSQLSchemaFetcher -> FetchExampleRecords -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	// Ensure limit is reasonable
+	if limit <= 0 {
+		limit = 3
+	} else if limit > 10 {
+		limit = 10
+	}
+
+	parsed := parseMSSQLTable(table)
+
+	columnTypes := make(map[string]string)
+	var columnRows []map[string]interface{}
+	columnQuery := `
+        SELECT c.name AS column_name, ty.name AS type_name
+        FROM sys.columns c
+        JOIN sys.tables t ON t.object_id = c.object_id
+        JOIN sys.schemas s ON s.schema_id = t.schema_id
+        JOIN sys.types ty ON ty.user_type_id = c.user_type_id
+        WHERE s.name = ? AND t.name = ?
+    `
+	if err := db.QueryRows(columnQuery, &columnRows, parsed.Schema, parsed.Name); err == nil {
+		for _, row := range columnRows {
+			columnTypes[sqliteString(row["column_name"])] = sqliteString(row["type_name"])
+		}
+	}
+
+	query := fmt.Sprintf("SELECT TOP (%d) * FROM %s", limit, parsed.quoted())
+	if orderByColumn := findExampleOrderColumn(columnTypes); orderByColumn != "" {
+		query = fmt.Sprintf("SELECT TOP (%d) * FROM %s ORDER BY %s DESC",
+			limit, parsed.quoted(), quoteMSSQLIdentifier(orderByColumn))
+	}
+
+	var records []map[string]interface{}
+	if err := db.QueryRows(query, &records); err != nil {
+		log.Printf("MSSQLSchemaFetcher -> FetchExampleRecords -> Error fetching records from table %s: %v", table, err)
+		return nil, fmt.Errorf("failed to fetch example records for table %s: %v", table, err)
+	}
+
+	return records, nil
+}
+
+// FetchTableList retrieves a list of all tables in the database
+func (f *MSSQLSchemaFetcher) FetchTableList(ctx context.Context) ([]string, error) {
+	tables, _, err := f.fetchTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tableNames := make([]string, 0, len(tables))
+	for _, table := range tables {
+		tableNames = append(tableNames, table.key())
+	}
+	return tableNames, nil
+}
+
+// filterSchemaForSelectedTables filters the schema to only include the selected tables
+func (f *MSSQLSchemaFetcher) filterSchemaForSelectedTables(schema *SchemaInfo, selectedTables []string) *SchemaInfo {
+	// If no tables are selected or "ALL" is selected, return the full schema
+	if len(selectedTables) == 0 || (len(selectedTables) == 1 && selectedTables[0] == "ALL") {
+		return schema
+	}
+
+	selectedTablesMap := make(map[string]bool)
+	for _, table := range selectedTables {
+		selectedTablesMap[table] = true
+	}
+
+	filteredSchema := &SchemaInfo{
+		Tables:    make(map[string]TableSchema),
+		Views:     make(map[string]ViewSchema),
+		UpdatedAt: schema.UpdatedAt,
+	}
+
+	for tableName, tableSchema := range schema.Tables {
+		if selectedTablesMap[tableName] {
+			filteredSchema.Tables[tableName] = tableSchema
+		}
+	}
+
+	// Calculate new checksum for filtered schema
+	schemaData, _ := json.Marshal(filteredSchema.Tables)
+	filteredSchema.Checksum = fmt.Sprintf("%x", md5.Sum(schemaData))
+
+	return filteredSchema
+}
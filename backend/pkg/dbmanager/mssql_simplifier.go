@@ -0,0 +1,91 @@
+package dbmanager
+
+import (
+	"strings"
+)
+
+// MSSQLSimplifier implements the SchemaSimplifier interface for SQL Server
+type MSSQLSimplifier struct{}
+
+// SimplifyDataType converts T-SQL data types to simplified versions for LLM
+func (s *MSSQLSimplifier) SimplifyDataType(dbType string) string {
+	// Drop the length/precision and IDENTITY suffix, e.g. "decimal(10,2)" or "int IDENTITY"
+	baseType := strings.ToLower(strings.TrimSpace(dbType))
+	if idx := strings.IndexAny(baseType, "( "); idx != -1 {
+		baseType = baseType[:idx]
+	}
+
+	switch baseType {
+	case "tinyint", "smallint", "int", "bigint":
+		return "integer"
+	case "decimal", "numeric", "money", "smallmoney":
+		return "decimal"
+	case "float", "real":
+		return "number"
+	case "bit":
+		return "boolean"
+	case "char", "varchar", "nchar", "nvarchar", "text", "ntext", "sysname":
+		return "string"
+	case "datetime", "datetime2", "smalldatetime", "datetimeoffset":
+		return "datetime"
+	case "date":
+		return "date"
+	case "time":
+		return "time"
+	case "uniqueidentifier":
+		return "uuid"
+	case "binary", "varbinary", "image", "timestamp", "rowversion":
+		return "binary"
+	case "xml":
+		return "xml"
+	case "json":
+		return "json"
+	default:
+		// Default to original type if no match
+		return dbType
+	}
+}
+
+// GetColumnConstraints returns a list of constraints for a column
+func (s *MSSQLSimplifier) GetColumnConstraints(col ColumnInfo, table TableSchema) []string {
+	var constraints []string
+
+	if !col.IsNullable {
+		constraints = append(constraints, "NOT NULL")
+	}
+
+	if strings.HasSuffix(col.Type, " IDENTITY") {
+		constraints = append(constraints, "IDENTITY")
+	}
+
+	if col.DefaultValue != "" {
+		constraints = append(constraints, "DEFAULT "+col.DefaultValue)
+	}
+
+	for _, constraint := range table.Constraints {
+		for _, colName := range constraint.Columns {
+			if colName != col.Name {
+				continue
+			}
+			switch constraint.Type {
+			case "PRIMARY KEY":
+				constraints = append(constraints, "PRIMARY KEY")
+			case "UNIQUE":
+				if len(constraint.Columns) == 1 {
+					constraints = append(constraints, "UNIQUE")
+				}
+			case "CHECK":
+				constraints = append(constraints, "CHECK "+constraint.Definition)
+			}
+		}
+	}
+
+	for _, fk := range table.ForeignKeys {
+		if fk.ColumnName == col.Name {
+			constraints = append(constraints, "FOREIGN KEY REFERENCES "+fk.RefTable+"("+fk.RefColumn+")")
+			break
+		}
+	}
+
+	return constraints
+}
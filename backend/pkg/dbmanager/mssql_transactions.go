@@ -0,0 +1,45 @@
+package dbmanager
+
+import (
+	"context"
+	"fmt"
+	"neobase-ai/internal/apis/dtos"
+
+	"gorm.io/gorm"
+)
+
+// MSSQLTransaction implements the Transaction interface for SQL Server
+type MSSQLTransaction struct {
+	tx   *gorm.DB
+	conn *Connection
+}
+
+// ExecuteQuery executes a query within a transaction
+func (t *MSSQLTransaction) ExecuteQuery(ctx context.Context, query string) (*QueryExecutionResult, error) {
+	if t.tx == nil {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: "No active transaction",
+				Code:    "TRANSACTION_ERROR",
+			},
+		}, nil
+	}
+
+	return executeMSSQLQuery(ctx, t.tx, query), nil
+}
+
+// Commit commits the transaction
+func (t *MSSQLTransaction) Commit() error {
+	if t.tx == nil {
+		return fmt.Errorf("no active transaction to commit")
+	}
+	return t.tx.Commit().Error
+}
+
+// Rollback rolls back the transaction
+func (t *MSSQLTransaction) Rollback() error {
+	if t.tx == nil {
+		return fmt.Errorf("no active transaction to rollback")
+	}
+	return t.tx.Rollback().Error
+}
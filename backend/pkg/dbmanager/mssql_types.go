@@ -0,0 +1,106 @@
+package dbmanager
+
+import (
+	"regexp"
+	"strings"
+)
+
+// mssqlSelectIntoRegex matches SELECT ... INTO statements, which create a table instead of returning rows
+var mssqlSelectIntoRegex = regexp.MustCompile(`^SELECT\b[\s\S]*?\bINTO\s+[#\[\w]`)
+
+// mssqlTable identifies a table, tables outside the dbo schema are named schema.table
+type mssqlTable struct {
+	Schema string
+	Name   string
+}
+
+// key returns the name the table is known by in the schema
+func (t mssqlTable) key() string {
+	if t.Schema == "" || strings.EqualFold(t.Schema, "dbo") {
+		return t.Name
+	}
+	return t.Schema + "." + t.Name
+}
+
+// quoted returns the bracket quoted, schema qualified table name
+func (t mssqlTable) quoted() string {
+	return quoteMSSQLIdentifier(t.Schema) + "." + quoteMSSQLIdentifier(t.Name)
+}
+
+// parseMSSQLTable splits a schema key back into schema and table name
+func parseMSSQLTable(table string) mssqlTable {
+	if schema, name, found := strings.Cut(table, "."); found {
+		return mssqlTable{Schema: schema, Name: name}
+	}
+	return mssqlTable{Schema: "dbo", Name: table}
+}
+
+// quoteMSSQLIdentifier quotes an identifier with brackets
+func quoteMSSQLIdentifier(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+// splitMSSQLStatements splits a T-SQL script into statements on semicolons and GO batch separators
+func splitMSSQLStatements(query string) []string {
+	var statements []string
+	var currentStmt strings.Builder
+	inQuote := false
+	quoteChar := rune(0)
+
+	flush := func() {
+		if strings.TrimSpace(currentStmt.String()) != "" {
+			statements = append(statements, currentStmt.String())
+		}
+		currentStmt.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(query, "\n") {
+		// GO is a client side batch separator and must be on its own line
+		if !inQuote && strings.EqualFold(strings.TrimSpace(line), "GO") {
+			flush()
+			continue
+		}
+
+		for _, char := range line {
+			switch {
+			case inQuote:
+				// Quotes are escaped by doubling them, which toggles the state twice
+				if char == quoteChar {
+					inQuote = false
+				}
+				currentStmt.WriteRune(char)
+			case char == '\'' || char == '"':
+				inQuote = true
+				quoteChar = char
+				currentStmt.WriteRune(char)
+			case char == '[':
+				inQuote = true
+				quoteChar = ']'
+				currentStmt.WriteRune(char)
+			case char == ';':
+				flush()
+			default:
+				currentStmt.WriteRune(char)
+			}
+		}
+	}
+	flush()
+
+	return statements
+}
+
+// isMSSQLReadStatement checks whether a statement returns rows
+func isMSSQLReadStatement(stmt string) bool {
+	upperStmt := strings.ToUpper(strings.TrimSpace(stmt))
+
+	if strings.HasPrefix(upperStmt, "SELECT") {
+		return !mssqlSelectIntoRegex.MatchString(upperStmt)
+	}
+
+	for _, prefix := range []string{"WITH", "EXEC", "SP_", "DBCC"} {
+		if strings.HasPrefix(upperStmt, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -365,6 +365,11 @@ func (d *MySQLDriver) ExecuteQuery(ctx context.Context, conn *Connection, query
 	return result
 }
 
+// StreamQuery runs a read query in a read-only transaction and returns a cursor over its rows
+func (d *MySQLDriver) StreamQuery(ctx context.Context, conn *Connection, query string) (ResultCursor, error) {
+	return streamSQLQuery(ctx, conn, query, &sql.TxOptions{ReadOnly: true}, nil)
+}
+
 // BeginTx starts a new transaction
 func (d *MySQLDriver) BeginTx(ctx context.Context, conn *Connection) Transaction {
 	if conn == nil || conn.DB == nil {
@@ -372,8 +377,8 @@ func (d *MySQLDriver) BeginTx(ctx context.Context, conn *Connection) Transaction
 		return nil
 	}
 
-	// Start a new transaction
-	tx := conn.DB.WithContext(ctx).Begin()
+	// Start a new transaction, read-only chats use START TRANSACTION READ ONLY
+	tx := conn.DB.WithContext(ctx).Begin(&sql.TxOptions{ReadOnly: conn.Config.ReadOnly})
 	if tx.Error != nil {
 		log.Printf("Failed to begin transaction: %v", tx.Error)
 		return nil
@@ -0,0 +1,255 @@
+package dbmanager
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"neobase-ai/internal/apis/dtos"
+	"regexp"
+	"strings"
+)
+
+// Operations of the keys of an EXPLAIN FORMAT=JSON plan holding steps, in the order they are listed
+var mysqlPlanOperations = []struct {
+	Key       string
+	Operation string
+}{
+	{"query_block", "Query block"},
+	{"union_result", "Union"},
+	{"windowing", "Window"},
+	{"ordering_operation", "Sort"},
+	{"grouping_operation", "Group"},
+	{"duplicates_removal", "Distinct"},
+	{"buffer_result", "Buffer result"},
+	{"nested_loop", "Nested loop"},
+	{"table", "Table"},
+	{"query_specifications", "Union members"},
+	{"materialized_from_subquery", "Materialized subquery"},
+	{"attached_subqueries", "Attached subqueries"},
+	{"select_list_subqueries", "Select list subqueries"},
+	{"having_subqueries", "Having subqueries"},
+	{"order_by_subqueries", "Order by subqueries"},
+	{"optimized_away_subqueries", "Optimized away subqueries"},
+}
+
+// Flags of a step listed in its details
+var mysqlPlanFlags = []struct {
+	Key       string
+	Operation string
+}{
+	{"using_filesort", "Using filesort"},
+	{"using_temporary_table", "Using temporary table"},
+	{"using_index", "Covering index"},
+}
+
+// Operations of the access types of a table
+var mysqlAccessTypes = map[string]string{
+	"ALL":             "Full table scan",
+	"index":           "Full index scan",
+	"range":           "Index range scan",
+	"ref":             "Index lookup",
+	"eq_ref":          "Unique index lookup",
+	"ref_or_null":     "Index lookup or null",
+	"const":           "Constant lookup",
+	"system":          "Constant lookup",
+	"index_merge":     "Index merge",
+	"fulltext":        "Fulltext index lookup",
+	"unique_subquery": "Subquery index lookup",
+	"index_subquery":  "Subquery index lookup",
+}
+
+var (
+	// A step of EXPLAIN ANALYZE, e.g. "-> Filter: (t.a > 1)  (cost=0.35 rows=1) (actual time=0.02..0.03 rows=2 loops=1)"
+	mysqlAnalyzeLineRegex  = regexp.MustCompile(`^(\s*)-> (.+?)(?:\s+\(cost=([\d.e+-]+)(?:\.\.([\d.e+-]+))? rows=([\d.e+-]+)\))?(?:\s+\(actual time=([\d.e+-]+)\.\.([\d.e+-]+) rows=([\d.e+-]+) loops=(\d+)\)|\s+\(never executed\))?\s*$`)
+	mysqlAnalyzeOnRegex    = regexp.MustCompile(`\bon (\S+)`)
+	mysqlAnalyzeUsingRegex = regexp.MustCompile(`\busing (\S+)`)
+)
+
+// ExplainQuery returns the plan of EXPLAIN FORMAT=JSON, or of EXPLAIN ANALYZE when analyze is set (MySQL 8.0.18+).
+// It runs in a transaction that is rolled back.
+func (d *MySQLDriver) ExplainQuery(ctx context.Context, conn *Connection, query string, analyze bool) (*dtos.QueryPlan, error) {
+	if conn == nil || conn.DB == nil {
+		return nil, fmt.Errorf("connection is not initialized")
+	}
+
+	tx := conn.DB.WithContext(ctx).Begin(&sql.TxOptions{ReadOnly: conn.Config.ReadOnly})
+	if tx.Error != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", tx.Error)
+	}
+	defer tx.Rollback()
+
+	statement := "EXPLAIN FORMAT=JSON " + query
+	if analyze {
+		statement = "EXPLAIN ANALYZE " + query
+	}
+	var raw string
+	if err := tx.Raw(statement).Row().Scan(&raw); err != nil {
+		return nil, fmt.Errorf("failed to explain query: %v", err)
+	}
+
+	if analyze {
+		root, err := parseMySQLAnalyzePlan(raw)
+		if err != nil {
+			return nil, err
+		}
+		return newQueryPlan(root, true, raw), nil
+	}
+
+	var plan map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse query plan: %v", err)
+	}
+	queryBlock, ok := plan["query_block"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to parse query plan: no query block")
+	}
+	return newQueryPlan(mysqlPlanNode("Query block", queryBlock), false, raw), nil
+}
+
+// mysqlPlanNode converts a step of an EXPLAIN FORMAT=JSON plan and the steps it holds
+func mysqlPlanNode(operation string, plan map[string]interface{}) *dtos.QueryPlanNode {
+	node := &dtos.QueryPlanNode{Operation: operation}
+	if costInfo, ok := plan["cost_info"].(map[string]interface{}); ok {
+		node.Cost = planFloat(costInfo["query_cost"])
+		if node.Cost == nil {
+			node.Cost = planFloat(costInfo["prefix_cost"])
+		}
+	}
+
+	if tableName := planString(plan["table_name"]); tableName != "" {
+		accessType := planString(plan["access_type"])
+		node.Operation = mysqlAccessTypes[accessType]
+		if node.Operation == "" {
+			node.Operation = "Table access (" + accessType + ")"
+		}
+		node.Relation = tableName
+		node.Index = planString(plan["key"])
+		node.FullScan = accessType == "ALL"
+		node.Rows = planFloat(plan["rows_examined_per_scan"])
+		if possibleKeys, ok := plan["possible_keys"].([]interface{}); ok && len(possibleKeys) > 0 {
+			keys := make([]string, 0, len(possibleKeys))
+			for _, key := range possibleKeys {
+				keys = append(keys, fmt.Sprint(key))
+			}
+			node.Details = append(node.Details, "Possible keys: "+strings.Join(keys, ", "))
+		}
+	}
+	if condition := planString(plan["attached_condition"]); condition != "" {
+		node.Details = append(node.Details, "Condition: "+condition)
+	}
+	if message := planString(plan["message"]); message != "" {
+		node.Details = append(node.Details, message)
+	}
+	for _, flag := range mysqlPlanFlags {
+		if value, _ := plan[flag.Key].(bool); value {
+			node.Details = append(node.Details, flag.Operation)
+		}
+	}
+
+	node.Children = mysqlPlanChildren(plan)
+
+	// A step only wrapping another one is replaced by it
+	if len(node.Children) == 1 && node.Relation == "" && node.Cost == nil && len(node.Details) == 0 {
+		return node.Children[0]
+	}
+	return node
+}
+
+// mysqlPlanChildren converts the steps held by a step of an EXPLAIN FORMAT=JSON plan
+func mysqlPlanChildren(plan map[string]interface{}) []*dtos.QueryPlanNode {
+	var children []*dtos.QueryPlanNode
+	for _, step := range mysqlPlanOperations {
+		switch value := plan[step.Key].(type) {
+		case map[string]interface{}:
+			children = append(children, mysqlPlanNode(step.Operation, value))
+		case []interface{}:
+			// Lists hold wrappers of steps, e.g. nested_loop: [{"table": {...}}, ...]
+			list := &dtos.QueryPlanNode{Operation: step.Operation}
+			for _, item := range value {
+				if wrapper, ok := item.(map[string]interface{}); ok {
+					list.Children = append(list.Children, mysqlPlanChildren(wrapper)...)
+				}
+			}
+			children = append(children, list)
+		}
+	}
+	return children
+}
+
+// parseMySQLAnalyzePlan converts the tree printed by EXPLAIN ANALYZE, steps are indented under their parent
+func parseMySQLAnalyzePlan(raw string) (*dtos.QueryPlanNode, error) {
+	type level struct {
+		indent int
+		node   *dtos.QueryPlanNode
+	}
+	var roots []*dtos.QueryPlanNode
+	var stack []level
+
+	for _, line := range strings.Split(raw, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		match := mysqlAnalyzeLineRegex.FindStringSubmatch(line)
+		if match == nil {
+			// Long conditions can wrap, they belong to the last step
+			if len(stack) > 0 {
+				top := stack[len(stack)-1].node
+				top.Details = append(top.Details, strings.TrimSpace(line))
+			}
+			continue
+		}
+
+		description := match[2]
+		node := &dtos.QueryPlanNode{Operation: description, Cost: planFloat(match[4]), Rows: planFloat(match[5])}
+		if node.Cost == nil {
+			node.Cost = planFloat(match[3])
+		}
+		// "Filter: <condition>" or "Index lookup on <table> using <index> (<condition>)"
+		colon := strings.Index(description, ": ")
+		on := mysqlAnalyzeOnRegex.FindStringSubmatchIndex(description)
+		if colon != -1 && (on == nil || colon < on[0]) {
+			node.Operation = description[:colon]
+			node.Details = append(node.Details, description[colon+2:])
+		} else if on != nil {
+			node.Operation = strings.TrimSpace(description[:on[0]])
+			node.Relation = description[on[2]:on[3]]
+			if using := mysqlAnalyzeUsingRegex.FindStringSubmatch(description); using != nil {
+				node.Index = using[1]
+			}
+			node.Details = append(node.Details, description)
+		}
+		node.FullScan = strings.HasPrefix(node.Operation, "Table scan")
+		if match[9] != "" {
+			loops := *planFloat(match[9])
+			if value := planFloat(match[8]); value != nil {
+				rows := *value * loops
+				node.ActualRows = &rows
+			}
+			if value := planFloat(match[7]); value != nil {
+				totalTime := *value * loops
+				node.ActualTimeMs = &totalTime
+			}
+		}
+
+		indent := len(match[1])
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, node)
+		} else {
+			parent := stack[len(stack)-1].node
+			parent.Children = append(parent.Children, node)
+		}
+		stack = append(stack, level{indent: indent, node: node})
+	}
+
+	switch len(roots) {
+	case 0:
+		return nil, fmt.Errorf("failed to parse query plan: no steps found")
+	case 1:
+		return roots[0], nil
+	}
+	return &dtos.QueryPlanNode{Operation: "Query", Children: roots}, nil
+}
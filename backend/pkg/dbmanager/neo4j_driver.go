@@ -0,0 +1,460 @@
+package dbmanager
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/utils"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+)
+
+// Neo4jDriver implements the DatabaseDriver interface for Neo4j
+type Neo4jDriver struct{}
+
+// NewNeo4jDriver creates a new Neo4j driver
+func NewNeo4jDriver() DatabaseDriver {
+	return &Neo4jDriver{}
+}
+
+// buildNeo4jDriver builds the Neo4j driver from the connection config, returns temp cert files to clean up
+func buildNeo4jDriver(cfg ConnectionConfig) (neo4j.DriverWithContext, []string, error) {
+	var tempFiles []string
+	var tlsConfig *tls.Config
+
+	port := "7687" // Default Bolt port
+	if cfg.Port != nil && *cfg.Port != "" {
+		port = *cfg.Port
+	}
+
+	// Host can be a full URI, e.g. neo4j+s://xxxx.databases.neo4j.io
+	uri := cfg.Host
+	if !strings.Contains(uri, "://") {
+		scheme := "neo4j"
+		if cfg.UseSSL {
+			sslMode := "require"
+			if cfg.SSLMode != nil {
+				sslMode = *cfg.SSLMode
+			}
+
+			if sslMode == "require" {
+				scheme = "neo4j+ssc" // Require encryption but don't verify certificates
+			} else if sslMode != "disable" {
+				scheme = "neo4j+s"
+			}
+		}
+		uri = fmt.Sprintf("%s://%s:%s", scheme, cfg.Host, port)
+	}
+
+	// Load custom certificates for verified TLS connections
+	if cfg.UseSSL && strings.HasSuffix(strings.SplitN(uri, "://", 2)[0], "+s") {
+		var certURL, keyURL, rootCertURL string
+		if cfg.SSLCertURL != nil {
+			certURL = *cfg.SSLCertURL
+		}
+		if cfg.SSLKeyURL != nil {
+			keyURL = *cfg.SSLKeyURL
+		}
+		if cfg.SSLRootCertURL != nil {
+			rootCertURL = *cfg.SSLRootCertURL
+		}
+
+		certPath, keyPath, rootCertPath, certTempFiles, err := utils.PrepareCertificatesFromURLs(certURL, keyURL, rootCertURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		tempFiles = certTempFiles
+
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		if rootCertPath != "" {
+			rootCert, err := os.ReadFile(rootCertPath)
+			if err != nil {
+				return nil, tempFiles, fmt.Errorf("failed to read root certificate: %v", err)
+			}
+			rootCAs := x509.NewCertPool()
+			if !rootCAs.AppendCertsFromPEM(rootCert) {
+				return nil, tempFiles, fmt.Errorf("failed to parse root certificate")
+			}
+			tlsConfig.RootCAs = rootCAs
+		}
+		if certPath != "" && keyPath != "" {
+			clientCert, err := tls.LoadX509KeyPair(certPath, keyPath)
+			if err != nil {
+				return nil, tempFiles, fmt.Errorf("failed to load client certificate: %v", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{clientCert}
+		}
+	}
+
+	auth := neo4j.NoAuth()
+	if cfg.Username != nil && *cfg.Username != "" {
+		password := ""
+		if cfg.Password != nil {
+			password = *cfg.Password
+		}
+		auth = neo4j.BasicAuth(*cfg.Username, password, "")
+	}
+
+	driver, err := neo4j.NewDriverWithContext(uri, auth, func(c *config.Config) {
+		c.SocketConnectTimeout = 10 * time.Second
+		c.MaxConnectionPoolSize = 20
+		if tlsConfig != nil {
+			c.TlsConfig = tlsConfig
+		}
+	})
+	if err != nil {
+		return nil, tempFiles, fmt.Errorf("failed to create Neo4j driver: %v", err)
+	}
+
+	return driver, tempFiles, nil
+}
+
+// Connect establishes a connection to a Neo4j database
+func (d *Neo4jDriver) Connect(cfg ConnectionConfig) (*Connection, error) {
+	log.Printf("Neo4jDriver -> Connect -> Connecting to Neo4j at %s:%v, database: %s", cfg.Host, cfg.Port, cfg.Database)
+
+	driver, tempFiles, err := buildNeo4jDriver(cfg)
+	if err != nil {
+		for _, file := range tempFiles {
+			os.Remove(file)
+		}
+		log.Printf("Neo4jDriver -> Connect -> Error building driver: %v", err)
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := driver.VerifyConnectivity(ctx); err != nil {
+		driver.Close(context.Background())
+		for _, file := range tempFiles {
+			os.Remove(file)
+		}
+		log.Printf("Neo4jDriver -> Connect -> Error verifying connectivity: %v", err)
+		return nil, fmt.Errorf("failed to connect to Neo4j: %v", err)
+	}
+
+	// Create a wrapper for the Neo4j driver
+	neo4jWrapper := &Neo4jWrapper{
+		Driver:   driver,
+		Database: cfg.Database,
+	}
+
+	conn := &Connection{
+		DB:         nil, // Neo4j doesn't use GORM
+		LastUsed:   time.Now(),
+		Status:     StatusConnected,
+		Config:     cfg,
+		MongoDBObj: neo4jWrapper, // Store Neo4j driver in the non-GORM client field
+		TempFiles:  tempFiles,
+	}
+
+	log.Printf("Neo4jDriver -> Connect -> Successfully connected to Neo4j at %s:%v", cfg.Host, cfg.Port)
+	return conn, nil
+}
+
+// Disconnect closes the Neo4j driver
+func (d *Neo4jDriver) Disconnect(conn *Connection) error {
+	log.Printf("Neo4jDriver -> Disconnect -> Disconnecting from Neo4j")
+
+	wrapper, ok := conn.MongoDBObj.(*Neo4jWrapper)
+	if !ok {
+		return fmt.Errorf("invalid Neo4j connection")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := wrapper.Driver.Close(ctx); err != nil {
+		log.Printf("Neo4jDriver -> Disconnect -> Error closing driver: %v", err)
+		return fmt.Errorf("failed to disconnect from Neo4j: %v", err)
+	}
+
+	// Clean up temporary certificate files
+	for _, file := range conn.TempFiles {
+		os.Remove(file)
+	}
+
+	log.Printf("Neo4jDriver -> Disconnect -> Successfully disconnected from Neo4j")
+	return nil
+}
+
+// Ping checks if the Neo4j connection is alive
+func (d *Neo4jDriver) Ping(conn *Connection) error {
+	wrapper, ok := conn.MongoDBObj.(*Neo4jWrapper)
+	if !ok {
+		return fmt.Errorf("invalid Neo4j connection")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := wrapper.Driver.VerifyConnectivity(ctx); err != nil {
+		log.Printf("Neo4jDriver -> Ping -> Error pinging Neo4j: %v", err)
+		return fmt.Errorf("failed to ping Neo4j: %v", err)
+	}
+	return nil
+}
+
+// IsAlive checks if the Neo4j connection is alive
+func (d *Neo4jDriver) IsAlive(conn *Connection) bool {
+	return d.Ping(conn) == nil
+}
+
+// ExecuteQuery executes a Cypher query, e.g. "MATCH (p:Person)-[:ACTED_IN]->(m:Movie) RETURN p, m LIMIT 50"
+func (d *Neo4jDriver) ExecuteQuery(ctx context.Context, conn *Connection, query string, queryType string, findCount bool) *QueryExecutionResult {
+	log.Printf("Neo4jDriver -> ExecuteQuery -> Executing Cypher query: %s", query)
+
+	tx := d.BeginTx(ctx, conn)
+	result, err := tx.ExecuteQuery(ctx, query)
+	if err != nil || (result != nil && result.Error != nil) {
+		tx.Rollback()
+		if err != nil {
+			return &QueryExecutionResult{
+				Error: &dtos.QueryError{
+					Message: err.Error(),
+					Code:    "EXECUTION_ERROR",
+				},
+			}
+		}
+		return result
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: fmt.Sprintf("Failed to commit transaction: %v", err),
+				Code:    "TRANSACTION_ERROR",
+			},
+		}
+	}
+	return result
+}
+
+// neo4jRunner runs Cypher statements, implemented by explicit and managed transactions
+type neo4jRunner interface {
+	Run(ctx context.Context, cypher string, params map[string]any) (neo4j.ResultWithContext, error)
+}
+
+// executeNeo4jQuery executes one or more Cypher statements separated by semicolons within the same transaction
+func executeNeo4jQuery(ctx context.Context, runner neo4jRunner, query string) *QueryExecutionResult {
+	startTime := time.Now()
+
+	var statements []string
+	for _, statement := range splitClickHouseStatements(query) {
+		if strings.TrimSpace(statement) != "" {
+			statements = append(statements, strings.TrimSpace(statement))
+		}
+	}
+
+	if len(statements) == 0 {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: "No Cypher statement found in query",
+				Code:    "INVALID_QUERY",
+			},
+		}
+	}
+
+	graph := newNeo4jGraph()
+	rows := []map[string]interface{}{}
+	counters := map[string]int{}
+	hasRecords := false
+	containsUpdates := false
+	var err error
+
+	for _, statement := range statements {
+		var result neo4j.ResultWithContext
+		result, err = runner.Run(ctx, statement, nil)
+		if err != nil {
+			break
+		}
+
+		keys, _ := result.Keys()
+		var records []*neo4j.Record
+		records, err = result.Collect(ctx)
+		if err != nil {
+			break
+		}
+
+		// Rows of the last statement returning records are shown
+		if len(keys) > 0 {
+			hasRecords = true
+			rows = make([]map[string]interface{}, 0, len(records))
+			for _, record := range records {
+				row := make(map[string]interface{}, len(record.Keys))
+				for i, key := range record.Keys {
+					row[key] = normalizeNeo4jValue(record.Values[i], graph)
+				}
+				rows = append(rows, row)
+			}
+		}
+
+		summary, consumeErr := result.Consume(ctx)
+		if consumeErr != nil {
+			err = consumeErr
+			break
+		}
+		if summary.Counters().ContainsUpdates() || summary.Counters().ContainsSystemUpdates() {
+			containsUpdates = true
+			addNeo4jCounters(counters, summary.Counters())
+		}
+	}
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return &QueryExecutionResult{
+				Error: &dtos.QueryError{
+					Message: "Query execution cancelled",
+					Code:    "EXECUTION_CANCELLED",
+				},
+			}
+		}
+		log.Printf("Neo4jDriver -> executeNeo4jQuery -> Error executing query: %v", err)
+		return &QueryExecutionResult{
+			ExecutionTime: int(time.Since(startTime).Milliseconds()),
+			Error: &dtos.QueryError{
+				Message: err.Error(),
+				Code:    "EXECUTION_ERROR",
+			},
+		}
+	}
+
+	var rowsAffected int64
+	for _, count := range counters {
+		rowsAffected += int64(count)
+	}
+
+	var resultData map[string]interface{}
+	if hasRecords {
+		resultData = map[string]interface{}{
+			"results": rows,
+		}
+		// Nodes and relationships are also returned as a graph so that clients can render them
+		if !graph.isEmpty() {
+			resultData["graph"] = graph.toMap()
+		}
+		if containsUpdates {
+			resultData["counters"] = counters
+		}
+	} else {
+		message := "Query executed successfully"
+		if len(statements) > 1 {
+			message = fmt.Sprintf("%d statements executed successfully", len(statements))
+		}
+		resultData = map[string]interface{}{
+			"message": message,
+		}
+		if containsUpdates {
+			resultData["counters"] = counters
+		}
+	}
+
+	executionTime := int(time.Since(startTime).Milliseconds())
+
+	// Marshal the result to JSON
+	resultJSON, err := json.Marshal(resultData)
+	if err != nil {
+		return &QueryExecutionResult{
+			ExecutionTime: executionTime,
+			Error: &dtos.QueryError{
+				Code:    "JSON_MARSHAL_FAILED",
+				Message: err.Error(),
+				Details: "Failed to marshal query results",
+			},
+		}
+	}
+
+	return &QueryExecutionResult{
+		Result:        resultData,
+		ExecutionTime: executionTime,
+		RowsAffected:  rowsAffected,
+		StreamData:    resultJSON,
+	}
+}
+
+// addNeo4jCounters adds the non zero update counters of a statement to counters
+func addNeo4jCounters(counters map[string]int, c neo4j.Counters) {
+	values := map[string]int{
+		"nodesCreated":         c.NodesCreated(),
+		"nodesDeleted":         c.NodesDeleted(),
+		"relationshipsCreated": c.RelationshipsCreated(),
+		"relationshipsDeleted": c.RelationshipsDeleted(),
+		"propertiesSet":        c.PropertiesSet(),
+		"labelsAdded":          c.LabelsAdded(),
+		"labelsRemoved":        c.LabelsRemoved(),
+		"indexesAdded":         c.IndexesAdded(),
+		"indexesRemoved":       c.IndexesRemoved(),
+		"constraintsAdded":     c.ConstraintsAdded(),
+		"constraintsRemoved":   c.ConstraintsRemoved(),
+		"systemUpdates":        c.SystemUpdates(),
+	}
+	for key, value := range values {
+		if value > 0 {
+			counters[key] += value
+		}
+	}
+}
+
+// StreamQuery returns a cursor over the rows of ExecuteQuery, the result is read as a whole
+func (d *Neo4jDriver) StreamQuery(ctx context.Context, conn *Connection, query string) (ResultCursor, error) {
+	return newBufferedCursor(d.ExecuteQuery(ctx, conn, query, "", false))
+}
+
+// BeginTx begins a Neo4j transaction
+func (d *Neo4jDriver) BeginTx(ctx context.Context, conn *Connection) Transaction {
+	log.Printf("Neo4jDriver -> BeginTx -> Beginning Neo4j transaction")
+
+	wrapper, ok := conn.MongoDBObj.(*Neo4jWrapper)
+	if !ok || wrapper.Driver == nil {
+		log.Printf("Neo4jDriver -> BeginTx -> Invalid Neo4j connection, type: %T", conn.MongoDBObj)
+		return &Neo4jTransaction{
+			Error: fmt.Errorf("invalid Neo4j connection, try disconnecting and reconnecting"),
+		}
+	}
+
+	session := wrapper.Driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: wrapper.Database,
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+
+	tx, err := session.BeginTransaction(ctx)
+	if err != nil {
+		session.Close(context.Background())
+		log.Printf("Neo4jDriver -> BeginTx -> Error beginning transaction: %v", err)
+		return &Neo4jTransaction{
+			Error: fmt.Errorf("failed to begin Neo4j transaction: %v", err),
+		}
+	}
+
+	return &Neo4jTransaction{
+		Session: session,
+		Tx:      tx,
+	}
+}
+
+// GetSchema retrieves the graph schema of the connected database
+func (d *Neo4jDriver) GetSchema(ctx context.Context, db DBExecutor, selectedTables []string) (*SchemaInfo, error) {
+	fetcher := NewNeo4jSchemaFetcher(db)
+	return fetcher.GetSchema(ctx, db, selectedTables)
+}
+
+// GetTableChecksum calculates a checksum for a node label or relationship type
+func (d *Neo4jDriver) GetTableChecksum(ctx context.Context, db DBExecutor, table string) (string, error) {
+	fetcher := NewNeo4jSchemaFetcher(db)
+	return fetcher.GetTableChecksum(ctx, db, table)
+}
+
+// FetchExampleRecords fetches example records for a node label or relationship type
+func (d *Neo4jDriver) FetchExampleRecords(ctx context.Context, db DBExecutor, table string, limit int) ([]map[string]interface{}, error) {
+	fetcher := NewNeo4jSchemaFetcher(db)
+	return fetcher.FetchExampleRecords(ctx, db, table, limit)
+}
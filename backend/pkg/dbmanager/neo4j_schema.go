@@ -0,0 +1,592 @@
+package dbmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"neobase-ai/internal/utils"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Neo4jSchemaFetcher implements SchemaFetcher for Neo4j, node labels and relationship types are exposed as tables
+type Neo4jSchemaFetcher struct {
+	db DBExecutor
+}
+
+// NewNeo4jSchemaFetcher creates a new Neo4j schema fetcher
+func NewNeo4jSchemaFetcher(db DBExecutor) SchemaFetcher {
+	return &Neo4jSchemaFetcher{
+		db: db,
+	}
+}
+
+// GetSchema reads node labels, relationship types, their properties, indexes and constraints
+func (f *Neo4jSchemaFetcher) GetSchema(ctx context.Context, db DBExecutor, selectedTables []string) (*SchemaInfo, error) {
+	log.Printf("Neo4jSchemaFetcher -> GetSchema -> Starting schema fetch with selected tables: %v", selectedTables)
+
+	executor, ok := db.(*Neo4jExecutor)
+	if !ok {
+		return nil, fmt.Errorf("invalid Neo4j executor")
+	}
+
+	labels, err := f.fetchLabels(executor)
+	if err != nil {
+		log.Printf("Neo4jSchemaFetcher -> GetSchema -> Error fetching node labels: %v", err)
+		return nil, fmt.Errorf("failed to fetch node labels: %v", err)
+	}
+
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("Neo4jSchemaFetcher -> GetSchema -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	relationshipTypes, err := f.fetchRelationshipTypes(executor)
+	if err != nil {
+		log.Printf("Neo4jSchemaFetcher -> GetSchema -> Error fetching relationship types: %v", err)
+		return nil, fmt.Errorf("failed to fetch relationship types: %v", err)
+	}
+
+	if err := f.fetchEndpoints(executor, relationshipTypes); err != nil {
+		// Endpoints only enrich the schema, don't fail the whole schema
+		log.Printf("Neo4jSchemaFetcher -> GetSchema -> Error fetching relationship endpoints: %v", err)
+	}
+
+	selectAll := len(selectedTables) == 0 || (len(selectedTables) == 1 && selectedTables[0] == "ALL")
+	selected := make(map[string]bool, len(selectedTables))
+	for _, table := range selectedTables {
+		selected[table] = true
+	}
+
+	schema := &SchemaInfo{
+		Tables:        make(map[string]TableSchema),
+		Relationships: make([]SchemaRelationship, 0),
+		UpdatedAt:     time.Now(),
+	}
+
+	for name, label := range labels {
+		if !selectAll && !selected[name] {
+			continue
+		}
+		label.Count = f.countLabel(executor, name)
+		schema.Tables[name] = f.convertLabelToTableSchema(label)
+	}
+
+	for name, relationshipType := range relationshipTypes {
+		tableName := neo4jRelationshipTableName(name, labels)
+		if !selectAll && !selected[tableName] && !selected[name] {
+			continue
+		}
+		relationshipType.Count = f.countRelationshipType(executor, name)
+		schema.Tables[tableName] = f.convertRelationshipToTableSchema(tableName, relationshipType)
+
+		for _, endpoint := range relationshipType.Endpoints {
+			schema.Relationships = append(schema.Relationships, SchemaRelationship{
+				FromTable: endpoint.From,
+				ToTable:   endpoint.To,
+				Type:      "many_to_many",
+				Through:   name,
+			})
+		}
+	}
+	sort.Slice(schema.Relationships, func(i, j int) bool {
+		a, b := schema.Relationships[i], schema.Relationships[j]
+		return a.Through+a.FromTable+a.ToTable < b.Through+b.FromTable+b.ToTable
+	})
+
+	// Indexes and constraints need SHOW privileges, they are optional
+	f.fetchIndexes(executor, schema.Tables, labels, relationshipTypes)
+	f.fetchConstraints(executor, schema.Tables, labels, relationshipTypes)
+
+	log.Printf("Neo4jSchemaFetcher -> GetSchema -> Fetched %d labels and relationship types", len(schema.Tables))
+	return schema, nil
+}
+
+// neo4jRelationshipTableName returns the table name of a relationship type, prefixed when a label has the same name
+func neo4jRelationshipTableName(relationshipType string, labels map[string]*Neo4jLabel) string {
+	if _, exists := labels[relationshipType]; exists {
+		return "[:" + relationshipType + "]"
+	}
+	return relationshipType
+}
+
+// fetchLabels reads the node labels and their property keys
+func (f *Neo4jSchemaFetcher) fetchLabels(executor *Neo4jExecutor) (map[string]*Neo4jLabel, error) {
+	labels := make(map[string]*Neo4jLabel)
+
+	var rows []map[string]interface{}
+	if err := executor.QueryRows(`CALL db.labels() YIELD label RETURN label`, &rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		name := neo4jString(row["label"])
+		labels[name] = &Neo4jLabel{
+			Name:       name,
+			Properties: make(map[string]Neo4jProperty),
+		}
+	}
+
+	rows = nil
+	err := executor.QueryRows(`CALL db.schema.nodeTypeProperties() YIELD nodeLabels, propertyName, propertyTypes, mandatory
+		RETURN nodeLabels, propertyName, propertyTypes, mandatory`, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		propertyName := neo4jString(row["propertyName"])
+		if propertyName == "" {
+			continue
+		}
+		// Nodes with several labels report their properties once for the label combination
+		for _, labelName := range neo4jStrings(row["nodeLabels"]) {
+			label, exists := labels[labelName]
+			if !exists {
+				continue
+			}
+			label.Properties[propertyName] = mergeNeo4jProperty(label.Properties[propertyName], propertyName,
+				neo4jStrings(row["propertyTypes"]), neo4jBool(row["mandatory"]))
+		}
+	}
+	return labels, nil
+}
+
+// fetchRelationshipTypes reads the relationship types and their property keys
+func (f *Neo4jSchemaFetcher) fetchRelationshipTypes(executor *Neo4jExecutor) (map[string]*Neo4jRelationshipType, error) {
+	relationshipTypes := make(map[string]*Neo4jRelationshipType)
+
+	var rows []map[string]interface{}
+	if err := executor.QueryRows(`CALL db.relationshipTypes() YIELD relationshipType RETURN relationshipType`, &rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		name := neo4jString(row["relationshipType"])
+		relationshipTypes[name] = &Neo4jRelationshipType{
+			Name:       name,
+			Properties: make(map[string]Neo4jProperty),
+		}
+	}
+
+	rows = nil
+	err := executor.QueryRows(`CALL db.schema.relTypeProperties() YIELD relType, propertyName, propertyTypes, mandatory
+		RETURN relType, propertyName, propertyTypes, mandatory`, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		propertyName := neo4jString(row["propertyName"])
+		if propertyName == "" {
+			continue
+		}
+		// relType is returned like :`ACTED_IN`
+		name := strings.TrimPrefix(neo4jString(row["relType"]), ":")
+		if strings.HasPrefix(name, "`") && strings.HasSuffix(name, "`") && len(name) > 1 {
+			name = strings.ReplaceAll(name[1:len(name)-1], "``", "`")
+		}
+		relationshipType, exists := relationshipTypes[name]
+		if !exists {
+			continue
+		}
+		relationshipType.Properties[propertyName] = mergeNeo4jProperty(relationshipType.Properties[propertyName], propertyName,
+			neo4jStrings(row["propertyTypes"]), neo4jBool(row["mandatory"]))
+	}
+	return relationshipTypes, nil
+}
+
+// mergeNeo4jProperty merges the types of a property reported for several label combinations
+func mergeNeo4jProperty(existing Neo4jProperty, name string, types []string, mandatory bool) Neo4jProperty {
+	if existing.Name == "" {
+		return Neo4jProperty{Name: name, Types: types, Mandatory: mandatory}
+	}
+	for _, propertyType := range types {
+		found := false
+		for _, existingType := range existing.Types {
+			if existingType == propertyType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			existing.Types = append(existing.Types, propertyType)
+		}
+	}
+	existing.Mandatory = existing.Mandatory && mandatory
+	return existing
+}
+
+// fetchEndpoints reads which labels each relationship type connects from db.schema.visualization
+func (f *Neo4jSchemaFetcher) fetchEndpoints(executor *Neo4jExecutor, relationshipTypes map[string]*Neo4jRelationshipType) error {
+	var rows []map[string]interface{}
+	err := executor.QueryRows(`CALL db.schema.visualization() YIELD nodes, relationships RETURN nodes, relationships`, &rows)
+	if err != nil || len(rows) == 0 {
+		// Fall back to sampling existing relationships
+		return f.sampleEndpoints(executor, relationshipTypes)
+	}
+
+	// Virtual nodes of the visualization carry the label in their name property
+	labelsByID := make(map[string]string)
+	nodes, _ := rows[0]["nodes"].([]interface{})
+	for _, node := range nodes {
+		nodeMap, ok := node.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		properties, _ := nodeMap["properties"].(map[string]interface{})
+		labelsByID[neo4jString(nodeMap["elementId"])] = neo4jString(properties["name"])
+	}
+
+	relationships, _ := rows[0]["relationships"].([]interface{})
+	for _, relationship := range relationships {
+		relationshipMap, ok := relationship.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		relationshipType, exists := relationshipTypes[neo4jString(relationshipMap["type"])]
+		if !exists {
+			continue
+		}
+		from := labelsByID[neo4jString(relationshipMap["startElementId"])]
+		to := labelsByID[neo4jString(relationshipMap["endElementId"])]
+		if from != "" && to != "" {
+			relationshipType.Endpoints = appendNeo4jEndpoint(relationshipType.Endpoints, Neo4jEndpoint{From: from, To: to})
+		}
+	}
+	return nil
+}
+
+// sampleEndpoints reads the label pairs of a sample of relationships
+func (f *Neo4jSchemaFetcher) sampleEndpoints(executor *Neo4jExecutor, relationshipTypes map[string]*Neo4jRelationshipType) error {
+	var rows []map[string]interface{}
+	err := executor.QueryRows(`MATCH (a)-[r]->(b) WITH type(r) AS relType, labels(a) AS fromLabels, labels(b) AS toLabels LIMIT 10000
+		RETURN DISTINCT relType, fromLabels, toLabels`, &rows)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		relationshipType, exists := relationshipTypes[neo4jString(row["relType"])]
+		if !exists {
+			continue
+		}
+		for _, from := range neo4jStrings(row["fromLabels"]) {
+			for _, to := range neo4jStrings(row["toLabels"]) {
+				relationshipType.Endpoints = appendNeo4jEndpoint(relationshipType.Endpoints, Neo4jEndpoint{From: from, To: to})
+			}
+		}
+	}
+	return nil
+}
+
+func appendNeo4jEndpoint(endpoints []Neo4jEndpoint, endpoint Neo4jEndpoint) []Neo4jEndpoint {
+	for _, existing := range endpoints {
+		if existing == endpoint {
+			return endpoints
+		}
+	}
+	return append(endpoints, endpoint)
+}
+
+// countLabel counts the nodes of a label, the count store makes this cheap
+func (f *Neo4jSchemaFetcher) countLabel(executor *Neo4jExecutor, label string) int64 {
+	var rows []map[string]interface{}
+	if err := executor.QueryRows(fmt.Sprintf("MATCH (n:%s) RETURN count(n) AS count", quoteNeo4jIdentifier(label)), &rows); err != nil || len(rows) == 0 {
+		log.Printf("Neo4jSchemaFetcher -> countLabel -> Error counting nodes of %s: %v", label, err)
+		return 0
+	}
+	return neo4jInt(rows[0]["count"])
+}
+
+// countRelationshipType counts the relationships of a type, the count store makes this cheap
+func (f *Neo4jSchemaFetcher) countRelationshipType(executor *Neo4jExecutor, relationshipType string) int64 {
+	var rows []map[string]interface{}
+	if err := executor.QueryRows(fmt.Sprintf("MATCH ()-[r:%s]->() RETURN count(r) AS count", quoteNeo4jIdentifier(relationshipType)), &rows); err != nil || len(rows) == 0 {
+		log.Printf("Neo4jSchemaFetcher -> countRelationshipType -> Error counting relationships of %s: %v", relationshipType, err)
+		return 0
+	}
+	return neo4jInt(rows[0]["count"])
+}
+
+// fetchIndexes reads the range, text, point and full text indexes of labels and relationship types
+func (f *Neo4jSchemaFetcher) fetchIndexes(executor *Neo4jExecutor, tables map[string]TableSchema, labels map[string]*Neo4jLabel, relationshipTypes map[string]*Neo4jRelationshipType) {
+	var rows []map[string]interface{}
+	err := executor.QueryRows(`SHOW INDEXES YIELD name, type, entityType, labelsOrTypes, properties, owningConstraint
+		RETURN name, type, entityType, labelsOrTypes, properties, owningConstraint`, &rows)
+	if err != nil {
+		log.Printf("Neo4jSchemaFetcher -> fetchIndexes -> Indexes not available: %v", err)
+		return
+	}
+
+	for _, row := range rows {
+		// Token lookup indexes have no labels or properties
+		properties := neo4jStrings(row["properties"])
+		if len(properties) == 0 {
+			continue
+		}
+		for _, tableName := range neo4jEntityTables(row, labels, relationshipTypes) {
+			table, exists := tables[tableName]
+			if !exists {
+				continue
+			}
+			name := neo4jString(row["name"])
+			table.Indexes[name] = IndexInfo{
+				Name:     name,
+				Columns:  properties,
+				IsUnique: neo4jString(row["owningConstraint"]) != "",
+			}
+		}
+	}
+}
+
+// fetchConstraints reads the uniqueness, key, existence and type constraints of labels and relationship types
+func (f *Neo4jSchemaFetcher) fetchConstraints(executor *Neo4jExecutor, tables map[string]TableSchema, labels map[string]*Neo4jLabel, relationshipTypes map[string]*Neo4jRelationshipType) {
+	var rows []map[string]interface{}
+	err := executor.QueryRows(`SHOW CONSTRAINTS YIELD name, type, entityType, labelsOrTypes, properties
+		RETURN name, type, entityType, labelsOrTypes, properties`, &rows)
+	if err != nil {
+		log.Printf("Neo4jSchemaFetcher -> fetchConstraints -> Constraints not available: %v", err)
+		return
+	}
+
+	for _, row := range rows {
+		properties := neo4jStrings(row["properties"])
+		constraintType := neo4jString(row["type"])
+		for _, tableName := range neo4jEntityTables(row, labels, relationshipTypes) {
+			table, exists := tables[tableName]
+			if !exists {
+				continue
+			}
+
+			// Node keys identify a node, so they are treated like primary keys
+			commonType := constraintType
+			switch {
+			case strings.HasSuffix(constraintType, "_KEY"):
+				commonType = "PRIMARY KEY"
+			case strings.Contains(constraintType, "UNIQUENESS"):
+				commonType = "UNIQUE"
+			case strings.Contains(constraintType, "PROPERTY_EXISTENCE"):
+				commonType = "NOT NULL"
+				for _, property := range properties {
+					if col, exists := table.Columns[property]; exists {
+						col.IsNullable = false
+						table.Columns[property] = col
+					}
+				}
+			}
+
+			name := neo4jString(row["name"])
+			table.Constraints[name] = ConstraintInfo{
+				Name:       name,
+				Type:       commonType,
+				Definition: fmt.Sprintf("%s on (%s)", constraintType, strings.Join(properties, ", ")),
+				Columns:    properties,
+			}
+		}
+	}
+}
+
+// neo4jEntityTables returns the table names an index or constraint row applies to
+func neo4jEntityTables(row map[string]interface{}, labels map[string]*Neo4jLabel, relationshipTypes map[string]*Neo4jRelationshipType) []string {
+	var tableNames []string
+	for _, name := range neo4jStrings(row["labelsOrTypes"]) {
+		if neo4jString(row["entityType"]) == "RELATIONSHIP" {
+			if _, exists := relationshipTypes[name]; exists {
+				tableNames = append(tableNames, neo4jRelationshipTableName(name, labels))
+			}
+			continue
+		}
+		tableNames = append(tableNames, name)
+	}
+	return tableNames
+}
+
+// convertLabelToTableSchema converts a node label into the common TableSchema
+func (f *Neo4jSchemaFetcher) convertLabelToTableSchema(label *Neo4jLabel) TableSchema {
+	tableSchema := TableSchema{
+		Name:        label.Name,
+		Columns:     convertNeo4jProperties(label.Properties),
+		Indexes:     make(map[string]IndexInfo),
+		ForeignKeys: make(map[string]ForeignKey),
+		Constraints: make(map[string]ConstraintInfo),
+		RowCount:    label.Count,
+		Comment:     fmt.Sprintf("Node label, match with (n:%s)", quoteNeo4jIdentifierIfNeeded(label.Name)),
+	}
+	return tableSchema
+}
+
+// convertRelationshipToTableSchema converts a relationship type into the common TableSchema
+func (f *Neo4jSchemaFetcher) convertRelationshipToTableSchema(tableName string, relationshipType *Neo4jRelationshipType) TableSchema {
+	tableSchema := TableSchema{
+		Name:        tableName,
+		Columns:     convertNeo4jProperties(relationshipType.Properties),
+		Indexes:     make(map[string]IndexInfo),
+		ForeignKeys: make(map[string]ForeignKey),
+		Constraints: make(map[string]ConstraintInfo),
+		RowCount:    relationshipType.Count,
+	}
+
+	// Describe the patterns in the table comment so the LLM knows the direction of the relationship
+	relationshipName := quoteNeo4jIdentifierIfNeeded(relationshipType.Name)
+	patterns := make([]string, 0, len(relationshipType.Endpoints))
+	for _, endpoint := range relationshipType.Endpoints {
+		patterns = append(patterns, fmt.Sprintf("(:%s)-[:%s]->(:%s)",
+			quoteNeo4jIdentifierIfNeeded(endpoint.From), relationshipName, quoteNeo4jIdentifierIfNeeded(endpoint.To)))
+	}
+	sort.Strings(patterns)
+
+	if len(patterns) > 0 {
+		tableSchema.Comment = fmt.Sprintf("Relationship type, patterns: %s", strings.Join(patterns, ", "))
+	} else {
+		tableSchema.Comment = fmt.Sprintf("Relationship type, match with ()-[r:%s]->()", relationshipName)
+	}
+	return tableSchema
+}
+
+// convertNeo4jProperties converts property keys into columns
+func convertNeo4jProperties(properties map[string]Neo4jProperty) map[string]ColumnInfo {
+	columns := make(map[string]ColumnInfo, len(properties))
+	for name, property := range properties {
+		types := append([]string{}, property.Types...)
+		sort.Strings(types)
+		columns[name] = ColumnInfo{
+			Name:       name,
+			Type:       strings.Join(types, "|"),
+			IsNullable: !property.Mandatory,
+		}
+	}
+	return columns
+}
+
+// quoteNeo4jIdentifierIfNeeded quotes a name with backticks only when it isn't a plain identifier
+func quoteNeo4jIdentifierIfNeeded(name string) string {
+	for i, r := range name {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return quoteNeo4jIdentifier(name)
+	}
+	return name
+}
+
+// GetTableChecksum calculates a checksum for a node label or relationship type from its properties, indexes and patterns
+func (f *Neo4jSchemaFetcher) GetTableChecksum(ctx context.Context, db DBExecutor, table string) (string, error) {
+	schema, err := f.GetSchema(ctx, db, []string{table})
+	if err != nil {
+		return "", err
+	}
+
+	tableSchema, exists := schema.Tables[table]
+	if !exists {
+		return "", fmt.Errorf("label or relationship type %s not found", table)
+	}
+
+	columnNames := make([]string, 0, len(tableSchema.Columns))
+	for name := range tableSchema.Columns {
+		columnNames = append(columnNames, name)
+	}
+	sort.Strings(columnNames)
+
+	columnsChecksum := ""
+	for _, name := range columnNames {
+		col := tableSchema.Columns[name]
+		columnsChecksum += fmt.Sprintf("%s:%s:%v,", name, col.Type, col.IsNullable)
+	}
+
+	indexNames := make([]string, 0, len(tableSchema.Indexes))
+	for name, index := range tableSchema.Indexes {
+		indexNames = append(indexNames, fmt.Sprintf("%s:%s", name, strings.Join(index.Columns, ",")))
+	}
+	sort.Strings(indexNames)
+
+	return utils.MD5Hash(fmt.Sprintf("%s:%s:%s:%s", table, columnsChecksum, strings.Join(indexNames, ";"), tableSchema.Comment)), nil
+}
+
+// FetchExampleRecords fetches the properties of example nodes or relationships
+func (f *Neo4jSchemaFetcher) FetchExampleRecords(ctx context.Context, db DBExecutor, table string, limit int) ([]map[string]interface{}, error) {
+	// Ensure limit is reasonable
+	if limit <= 0 {
+		limit = 3
+	} else if limit > 10 {
+		limit = 10 // Cap at 10 records to avoid large data transfers
+	}
+
+	executor, ok := db.(*Neo4jExecutor)
+	if !ok {
+		return nil, fmt.Errorf("invalid Neo4j executor")
+	}
+
+	params := map[string]interface{}{"limit": limit}
+	var records []map[string]interface{}
+
+	// Relationship types that share a name with a label are prefixed with [:
+	if !strings.HasPrefix(table, "[:") {
+		query := fmt.Sprintf("MATCH (n:%s) RETURN properties(n) AS properties LIMIT $limit", quoteNeo4jIdentifier(table))
+		if err := executor.QueryRows(query, &records, params); err != nil {
+			log.Printf("Neo4jSchemaFetcher -> FetchExampleRecords -> Error fetching nodes of %s: %v", table, err)
+			return nil, fmt.Errorf("failed to fetch example records for label %s: %v", table, err)
+		}
+	}
+
+	if len(records) == 0 {
+		relationshipType := strings.TrimSuffix(strings.TrimPrefix(table, "[:"), "]")
+		query := fmt.Sprintf("MATCH ()-[r:%s]->() RETURN properties(r) AS properties LIMIT $limit", quoteNeo4jIdentifier(relationshipType))
+		if err := executor.QueryRows(query, &records, params); err != nil {
+			log.Printf("Neo4jSchemaFetcher -> FetchExampleRecords -> Error fetching relationships of %s: %v", table, err)
+			return nil, fmt.Errorf("failed to fetch example records for relationship type %s: %v", table, err)
+		}
+	}
+
+	examples := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		if properties, ok := record["properties"].(map[string]interface{}); ok {
+			examples = append(examples, properties)
+		}
+	}
+	return examples, nil
+}
+
+// neo4jString converts a result value into a string
+func neo4jString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// neo4jStrings converts a list result value into a string slice
+func neo4jStrings(value interface{}) []string {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		values = append(values, neo4jString(item))
+	}
+	return values
+}
+
+// neo4jBool converts a result value into a bool
+func neo4jBool(value interface{}) bool {
+	b, _ := value.(bool)
+	return b
+}
+
+// neo4jInt converts a result value into an int64
+func neo4jInt(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,83 @@
+package dbmanager
+
+import (
+	"strings"
+)
+
+// Neo4jSimplifier implements the SchemaSimplifier interface for Neo4j
+type Neo4jSimplifier struct{}
+
+// SimplifyDataType converts Neo4j property types to simplified versions for LLM
+func (s *Neo4jSimplifier) SimplifyDataType(dbType string) string {
+	// Properties with mixed types are reported as e.g. "Long|String"
+	types := strings.Split(dbType, "|")
+	simplified := make([]string, 0, len(types))
+	for _, propertyType := range types {
+		simplified = append(simplified, simplifyNeo4jType(strings.TrimSpace(propertyType)))
+	}
+	return strings.Join(simplified, "|")
+}
+
+func simplifyNeo4jType(propertyType string) string {
+	lowerType := strings.ToLower(propertyType)
+
+	// Arrays are reported as e.g. StringArray or LIST<STRING NOT NULL>
+	if strings.HasSuffix(lowerType, "array") || strings.HasPrefix(lowerType, "list") {
+		return "list"
+	}
+
+	switch lowerType {
+	case "integer", "long", "short", "byte", "int":
+		return "integer"
+	case "float", "double":
+		return "number"
+	case "string", "char":
+		return "text"
+	case "boolean":
+		return "boolean"
+	case "date", "datetime", "localdatetime", "localtime", "time", "date_time", "local_date_time", "local_time", "zoned_time", "zoned_datetime":
+		return "datetime"
+	case "duration":
+		return "duration"
+	case "point":
+		return "point"
+	case "":
+		return "any"
+	default:
+		return lowerType
+	}
+}
+
+// GetColumnConstraints returns the constraints and indexes of a Neo4j property
+func (s *Neo4jSimplifier) GetColumnConstraints(col ColumnInfo, table TableSchema) []string {
+	constraints := []string{}
+
+	for _, constraint := range table.Constraints {
+		for _, property := range constraint.Columns {
+			if property != col.Name {
+				continue
+			}
+			switch constraint.Type {
+			case "PRIMARY KEY":
+				constraints = append(constraints, "KEY")
+			case "UNIQUE":
+				constraints = append(constraints, "UNIQUE")
+			}
+		}
+	}
+
+	if !col.IsNullable {
+		constraints = append(constraints, "NOT NULL")
+	}
+
+	for _, index := range table.Indexes {
+		for _, indexedProperty := range index.Columns {
+			if indexedProperty == col.Name {
+				constraints = append(constraints, "INDEXED")
+				return constraints
+			}
+		}
+	}
+
+	return constraints
+}
@@ -0,0 +1,78 @@
+package dbmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Neo4jTransaction implements the Transaction interface for Neo4j
+type Neo4jTransaction struct {
+	Session neo4j.SessionWithContext
+	Tx      neo4j.ExplicitTransaction
+	Error   error
+}
+
+// ExecuteQuery executes a Cypher query within the transaction
+func (tx *Neo4jTransaction) ExecuteQuery(ctx context.Context, query string) (*QueryExecutionResult, error) {
+	if tx.Error != nil {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: tx.Error.Error(),
+				Code:    "TRANSACTION_ERROR",
+			},
+		}, nil
+	}
+
+	if tx.Tx == nil {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: "No active Neo4j transaction",
+				Code:    "TRANSACTION_ERROR",
+			},
+		}, nil
+	}
+
+	return executeNeo4jQuery(ctx, tx.Tx, query), nil
+}
+
+// Commit commits the Neo4j transaction and closes its session
+func (tx *Neo4jTransaction) Commit() error {
+	if tx.Error != nil {
+		return fmt.Errorf("cannot commit transaction: %v", tx.Error)
+	}
+	if tx.Tx == nil {
+		return fmt.Errorf("no active transaction to commit")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	defer tx.Session.Close(ctx)
+
+	if err := tx.Tx.Commit(ctx); err != nil {
+		log.Printf("Neo4jTransaction -> Commit -> Error committing transaction: %v", err)
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
+
+// Rollback rolls back the Neo4j transaction and closes its session
+func (tx *Neo4jTransaction) Rollback() error {
+	if tx.Tx == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	defer tx.Session.Close(ctx)
+
+	if err := tx.Tx.Rollback(ctx); err != nil {
+		log.Printf("Neo4jTransaction -> Rollback -> Error rolling back transaction: %v", err)
+		return fmt.Errorf("failed to rollback transaction: %v", err)
+	}
+	return nil
+}
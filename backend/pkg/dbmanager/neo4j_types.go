@@ -0,0 +1,39 @@
+package dbmanager
+
+import (
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Neo4jWrapper wraps the Neo4j driver of a connection
+type Neo4jWrapper struct {
+	Driver   neo4j.DriverWithContext
+	Database string // Empty uses the default database of the server
+}
+
+// Neo4jProperty represents a property key found on a node label or relationship type
+type Neo4jProperty struct {
+	Name      string
+	Types     []string
+	Mandatory bool
+}
+
+// Neo4jLabel represents a node label with its property keys
+type Neo4jLabel struct {
+	Name       string
+	Properties map[string]Neo4jProperty
+	Count      int64
+}
+
+// Neo4jRelationshipType represents a relationship type with its property keys and the labels it connects
+type Neo4jRelationshipType struct {
+	Name       string
+	Properties map[string]Neo4jProperty
+	Count      int64
+	Endpoints  []Neo4jEndpoint
+}
+
+// Neo4jEndpoint represents a (:From)-[:TYPE]->(:To) pattern of a relationship type
+type Neo4jEndpoint struct {
+	From string
+	To   string
+}
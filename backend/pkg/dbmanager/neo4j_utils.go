@@ -0,0 +1,146 @@
+package dbmanager
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// quoteNeo4jIdentifier quotes a label, relationship type or property key with backticks
+func quoteNeo4jIdentifier(identifier string) string {
+	return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+}
+
+// neo4jGraph collects the distinct nodes and relationships of a result so that clients can render it as a graph
+type neo4jGraph struct {
+	nodes         map[string]map[string]interface{}
+	relationships map[string]map[string]interface{}
+}
+
+func newNeo4jGraph() *neo4jGraph {
+	return &neo4jGraph{
+		nodes:         make(map[string]map[string]interface{}),
+		relationships: make(map[string]map[string]interface{}),
+	}
+}
+
+// isEmpty reports whether no node or relationship was collected
+func (g *neo4jGraph) isEmpty() bool {
+	return len(g.nodes) == 0 && len(g.relationships) == 0
+}
+
+// toMap returns the graph as {"nodes": [...], "relationships": [...]} sorted by element id
+func (g *neo4jGraph) toMap() map[string]interface{} {
+	nodes := make([]map[string]interface{}, 0, len(g.nodes))
+	for _, id := range sortedNeo4jKeys(g.nodes) {
+		nodes = append(nodes, g.nodes[id])
+	}
+	relationships := make([]map[string]interface{}, 0, len(g.relationships))
+	for _, id := range sortedNeo4jKeys(g.relationships) {
+		relationships = append(relationships, g.relationships[id])
+	}
+	return map[string]interface{}{
+		"nodes":         nodes,
+		"relationships": relationships,
+	}
+}
+
+func sortedNeo4jKeys(items map[string]map[string]interface{}) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// neo4jNodeToMap converts a node into a JSON friendly map
+func neo4jNodeToMap(node dbtype.Node, graph *neo4jGraph) map[string]interface{} {
+	nodeMap := map[string]interface{}{
+		"_type":      "node",
+		"elementId":  node.ElementId,
+		"labels":     node.Labels,
+		"properties": normalizeNeo4jValue(node.Props, graph),
+	}
+	if graph != nil {
+		graph.nodes[node.ElementId] = nodeMap
+	}
+	return nodeMap
+}
+
+// neo4jRelationshipToMap converts a relationship into a JSON friendly map
+func neo4jRelationshipToMap(relationship dbtype.Relationship, graph *neo4jGraph) map[string]interface{} {
+	relationshipMap := map[string]interface{}{
+		"_type":          "relationship",
+		"elementId":      relationship.ElementId,
+		"type":           relationship.Type,
+		"startElementId": relationship.StartElementId,
+		"endElementId":   relationship.EndElementId,
+		"properties":     normalizeNeo4jValue(relationship.Props, graph),
+	}
+	if graph != nil {
+		graph.relationships[relationship.ElementId] = relationshipMap
+	}
+	return relationshipMap
+}
+
+// normalizeNeo4jValue converts Neo4j values (nodes, relationships, paths, temporal and spatial types) into JSON friendly values
+func normalizeNeo4jValue(value interface{}, graph *neo4jGraph) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case dbtype.Node:
+		return neo4jNodeToMap(v, graph)
+	case dbtype.Relationship:
+		return neo4jRelationshipToMap(v, graph)
+	case dbtype.Path:
+		nodes := make([]interface{}, len(v.Nodes))
+		for i, node := range v.Nodes {
+			nodes[i] = neo4jNodeToMap(node, graph)
+		}
+		relationships := make([]interface{}, len(v.Relationships))
+		for i, relationship := range v.Relationships {
+			relationships[i] = neo4jRelationshipToMap(relationship, graph)
+		}
+		return map[string]interface{}{
+			"_type":         "path",
+			"length":        len(v.Relationships),
+			"nodes":         nodes,
+			"relationships": relationships,
+		}
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case dbtype.Date:
+		return v.String()
+	case dbtype.LocalTime:
+		return v.String()
+	case dbtype.LocalDateTime:
+		return v.String()
+	case dbtype.Time:
+		return v.String()
+	case dbtype.Duration:
+		return v.String()
+	case dbtype.Point2D:
+		return map[string]interface{}{"srid": v.SpatialRefId, "x": v.X, "y": v.Y}
+	case dbtype.Point3D:
+		return map[string]interface{}{"srid": v.SpatialRefId, "x": v.X, "y": v.Y, "z": v.Z}
+	case []byte:
+		return string(v)
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized[i] = normalizeNeo4jValue(item, graph)
+		}
+		return normalized
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized[key] = normalizeNeo4jValue(item, graph)
+		}
+		return normalized
+	default:
+		return v
+	}
+}
@@ -0,0 +1,113 @@
+package dbmanager
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Neo4jExecutor implements the DBExecutor interface for Neo4j
+type Neo4jExecutor struct {
+	wrapper *Neo4jWrapper
+	conn    *Connection
+}
+
+// NewNeo4jExecutor creates a new Neo4j executor
+func NewNeo4jExecutor(conn *Connection) (*Neo4jExecutor, error) {
+	wrapper, ok := conn.MongoDBObj.(*Neo4jWrapper)
+	if !ok || wrapper == nil {
+		return nil, fmt.Errorf("invalid Neo4j connection")
+	}
+
+	return &Neo4jExecutor{
+		wrapper: wrapper,
+		conn:    conn,
+	}, nil
+}
+
+// GetDB returns nil for Neo4j as it doesn't use sql.DB
+func (e *Neo4jExecutor) GetDB() *sql.DB {
+	return nil
+}
+
+// GetDriver returns the underlying Neo4j driver
+func (e *Neo4jExecutor) GetDriver() neo4j.DriverWithContext {
+	return e.wrapper.Driver
+}
+
+// Close closes the Neo4j executor
+func (e *Neo4jExecutor) Close() error {
+	// Driver is managed by the Neo4j driver
+	return nil
+}
+
+// Exec executes a Cypher statement
+func (e *Neo4jExecutor) Exec(query string, values ...interface{}) error {
+	log.Printf("Neo4jExecutor -> Exec -> Query: %s", query)
+
+	_, err := neo4j.ExecuteQuery(context.Background(), e.wrapper.Driver, query, neo4jParams(values),
+		neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(e.wrapper.Database))
+	if err != nil {
+		return fmt.Errorf("failed to execute Cypher statement: %v", err)
+	}
+	return nil
+}
+
+// Raw executes a raw Cypher statement
+func (e *Neo4jExecutor) Raw(query string, values ...interface{}) error {
+	return e.Exec(query, values...)
+}
+
+// Query executes a Cypher query and scans the records into dest
+func (e *Neo4jExecutor) Query(query string, dest interface{}, values ...interface{}) error {
+	destMap, ok := dest.(*[]map[string]interface{})
+	if !ok {
+		return fmt.Errorf("destination must be *[]map[string]interface{}")
+	}
+	return e.QueryRows(query, destMap, values...)
+}
+
+// QueryRows executes a read only Cypher query and returns the records, values are passed as a single parameter map
+func (e *Neo4jExecutor) QueryRows(query string, dest *[]map[string]interface{}, values ...interface{}) error {
+	result, err := neo4j.ExecuteQuery(context.Background(), e.wrapper.Driver, query, neo4jParams(values),
+		neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(e.wrapper.Database), neo4j.ExecuteQueryWithReadersRouting())
+	if err != nil {
+		return fmt.Errorf("failed to execute Cypher query: %v", err)
+	}
+
+	rows := make([]map[string]interface{}, 0, len(result.Records))
+	for _, record := range result.Records {
+		row := make(map[string]interface{}, len(record.Keys))
+		for i, key := range record.Keys {
+			row[key] = normalizeNeo4jValue(record.Values[i], nil)
+		}
+		rows = append(rows, row)
+	}
+	*dest = rows
+	return nil
+}
+
+// neo4jParams returns the parameter map passed as the first value, if any
+func neo4jParams(values []interface{}) map[string]any {
+	if len(values) > 0 {
+		if params, ok := values[0].(map[string]interface{}); ok {
+			return params
+		}
+	}
+	return nil
+}
+
+// GetSchema fetches the graph schema of the connected database
+func (e *Neo4jExecutor) GetSchema(ctx context.Context) (*SchemaInfo, error) {
+	driver := &Neo4jDriver{}
+	return driver.GetSchema(ctx, e, []string{"ALL"})
+}
+
+// GetTableChecksum calculates a checksum for a node label or relationship type
+func (e *Neo4jExecutor) GetTableChecksum(ctx context.Context, table string) (string, error) {
+	driver := &Neo4jDriver{}
+	return driver.GetTableChecksum(ctx, e, table)
+}
@@ -84,6 +84,11 @@ func (d *PostgresDriver) Connect(config ConnectionConfig) (*Connection, error) {
 		baseParams += " sslmode=disable"
 	}
 
+	// Same as SET SESSION CHARACTERISTICS AS TRANSACTION READ ONLY on every session of the pool
+	if config.ReadOnly {
+		baseParams += " default_transaction_read_only=on"
+	}
+
 	dsn = baseParams
 
 	// Open connection
@@ -253,6 +258,11 @@ func (d *PostgresDriver) ExecuteQuery(ctx context.Context, conn *Connection, que
 	return result
 }
 
+// StreamQuery runs a read query in a read-only transaction and returns a cursor over its rows
+func (d *PostgresDriver) StreamQuery(ctx context.Context, conn *Connection, query string) (ResultCursor, error) {
+	return streamSQLQuery(ctx, conn, query, &sql.TxOptions{ReadOnly: true}, nil)
+}
+
 func (d *PostgresDriver) BeginTx(ctx context.Context, conn *Connection) Transaction {
 	log.Printf("PostgreSQL/YugabyteDB Driver -> BeginTx -> Starting transaction")
 
@@ -267,7 +277,8 @@ func (d *PostgresDriver) BeginTx(ctx context.Context, conn *Connection) Transact
 		return nil
 	}
 
-	tx, err := sqlDB.BeginTx(ctx, nil)
+	// Read-only chats run each query in a read-only transaction, even if the query changed the session default
+	tx, err := sqlDB.BeginTx(ctx, &sql.TxOptions{ReadOnly: conn.Config.ReadOnly})
 	if err != nil {
 		log.Printf("PostgreSQL/YugabyteDB Driver -> BeginTx -> Failed to begin transaction: %v", err)
 		return nil
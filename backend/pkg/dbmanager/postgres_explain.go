@@ -0,0 +1,115 @@
+package dbmanager
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"strings"
+)
+
+// Plan fields listed in the details of a step, in this order
+var postgresPlanDetailKeys = []string{
+	"Join Type", "Strategy", "Hash Cond", "Merge Cond", "Index Cond", "Recheck Cond", "Join Filter", "Filter",
+	"Rows Removed by Filter", "Sort Key", "Sort Method", "Group Key", "Shared Hit Blocks", "Shared Read Blocks",
+}
+
+// ExplainQuery returns the plan of EXPLAIN (FORMAT JSON), with ANALYZE and BUFFERS when analyze is set.
+// It runs in a transaction that is rolled back.
+func (d *PostgresDriver) ExplainQuery(ctx context.Context, conn *Connection, query string, analyze bool) (*dtos.QueryPlan, error) {
+	if conn == nil || conn.DB == nil {
+		return nil, fmt.Errorf("connection is not initialized")
+	}
+	sqlDB, err := conn.DB.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SQL connection: %v", err)
+	}
+
+	tx, err := sqlDB.BeginTx(ctx, &sql.TxOptions{ReadOnly: conn.Config.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			log.Printf("PostgreSQL/YugabyteDB Driver -> ExplainQuery -> Error rolling back transaction: %v", err)
+		}
+	}()
+
+	options := "FORMAT JSON"
+	if analyze {
+		options += ", ANALYZE, BUFFERS"
+	}
+	var raw []byte
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf("EXPLAIN (%s) %s", options, query)).Scan(&raw); err != nil {
+		return nil, fmt.Errorf("failed to explain query: %v", err)
+	}
+
+	var explained []struct {
+		Plan          map[string]interface{} `json:"Plan"`
+		PlanningTime  *float64               `json:"Planning Time"`
+		ExecutionTime *float64               `json:"Execution Time"`
+	}
+	if err := json.Unmarshal(raw, &explained); err != nil || len(explained) == 0 {
+		return nil, fmt.Errorf("failed to parse query plan: %v", err)
+	}
+
+	root := postgresPlanNode(explained[0].Plan)
+	if explained[0].ExecutionTime != nil {
+		root.Details = append(root.Details, fmt.Sprintf("Execution Time: %sms", formatPlanNumber(*explained[0].ExecutionTime)))
+	}
+	return newQueryPlan(root, analyze, string(raw)), nil
+}
+
+// postgresPlanNode converts a node of a JSON plan and its children
+func postgresPlanNode(plan map[string]interface{}) *dtos.QueryPlanNode {
+	node := &dtos.QueryPlanNode{
+		Operation: planString(plan["Node Type"]),
+		Relation:  planString(plan["Relation Name"]),
+		Index:     planString(plan["Index Name"]),
+		Cost:      planFloat(plan["Total Cost"]),
+		Rows:      planFloat(plan["Plan Rows"]),
+	}
+	node.FullScan = node.Operation == "Seq Scan"
+
+	// Actual values are per loop
+	loops := 1.0
+	if value := planFloat(plan["Actual Loops"]); value != nil {
+		loops = *value
+	}
+	if value := planFloat(plan["Actual Rows"]); value != nil {
+		rows := *value * loops
+		node.ActualRows = &rows
+	}
+	if value := planFloat(plan["Actual Total Time"]); value != nil {
+		totalTime := *value * loops
+		node.ActualTimeMs = &totalTime
+	}
+
+	for _, key := range postgresPlanDetailKeys {
+		switch value := plan[key].(type) {
+		case string:
+			node.Details = append(node.Details, key+": "+value)
+		case float64:
+			if value != 0 {
+				node.Details = append(node.Details, key+": "+formatPlanNumber(value))
+			}
+		case []interface{}:
+			parts := make([]string, 0, len(value))
+			for _, part := range value {
+				parts = append(parts, fmt.Sprint(part))
+			}
+			node.Details = append(node.Details, key+": "+strings.Join(parts, ", "))
+		}
+	}
+
+	if children, ok := plan["Plans"].([]interface{}); ok {
+		for _, child := range children {
+			if childPlan, ok := child.(map[string]interface{}); ok {
+				node.Children = append(node.Children, postgresPlanNode(childPlan))
+			}
+		}
+	}
+	return node
+}
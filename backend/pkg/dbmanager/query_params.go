@@ -0,0 +1,428 @@
+package dbmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"neobase-ai/internal/constants"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Types of the parameters of a query
+const (
+	ParamTypeString    = "string"
+	ParamTypeInteger   = "integer"
+	ParamTypeNumber    = "number"
+	ParamTypeBoolean   = "boolean"
+	ParamTypeDate      = "date"
+	ParamTypeTimestamp = "timestamp"
+)
+
+// QueryParam is a {{name}} placeholder of a query, bound to a literal of its type
+type QueryParam struct {
+	Name     string
+	Type     string
+	Required bool
+	Default  interface{}
+}
+
+var queryPlaceholderRegex = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+var paramNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// IsValidParamType reports whether the parameter type is supported
+func IsValidParamType(paramType string) bool {
+	switch paramType {
+	case ParamTypeString, ParamTypeInteger, ParamTypeNumber, ParamTypeBoolean, ParamTypeDate, ParamTypeTimestamp:
+		return true
+	}
+	return false
+}
+
+// IsValidParamName reports whether the name can be used in a {{name}} placeholder
+func IsValidParamName(name string) bool {
+	return paramNameRegex.MatchString(name)
+}
+
+// QueryPlaceholders returns the names of the placeholders of a query, in the order they first appear
+func QueryPlaceholders(query string) []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, match := range queryPlaceholderRegex.FindAllStringSubmatch(query, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// BindQueryParams replaces the placeholders of a query by the values as literals of the database. Placeholders stand
+// for whole values, so they are written without quotes, e.g. WHERE name = {{name}}. Missing values take the default
+// of their parameter, or are NULL when the parameter isn't required.
+func BindQueryParams(dbType string, query string, params []QueryParam, values map[string]interface{}) (string, error) {
+	if err := CheckQueryPlaceholders(dbType, query); err != nil {
+		return "", err
+	}
+	paramsByName := make(map[string]QueryParam, len(params))
+	for _, param := range params {
+		paramsByName[param.Name] = param
+	}
+	for name := range values {
+		if _, exists := paramsByName[name]; !exists {
+			return "", fmt.Errorf("unknown parameter: %s", name)
+		}
+	}
+
+	literals := make(map[string]string, len(params))
+	for _, name := range QueryPlaceholders(query) {
+		param, exists := paramsByName[name]
+		if !exists {
+			return "", fmt.Errorf("placeholder {{%s}} has no parameter", name)
+		}
+		value, exists := values[name]
+		if !exists || value == nil {
+			value = param.Default
+		}
+		if value == nil {
+			if param.Required {
+				return "", fmt.Errorf("parameter %s is required", name)
+			}
+			literal, err := nullLiteral(dbType)
+			if err != nil {
+				return "", fmt.Errorf("parameter %s: %v", name, err)
+			}
+			literals[name] = literal
+			continue
+		}
+		literal, err := paramLiteral(dbType, param.Type, value)
+		if err != nil {
+			return "", fmt.Errorf("parameter %s: %v", name, err)
+		}
+		literals[name] = literal
+	}
+
+	return queryPlaceholderRegex.ReplaceAllStringFunc(query, func(placeholder string) string {
+		return literals[queryPlaceholderRegex.FindStringSubmatch(placeholder)[1]]
+	}), nil
+}
+
+// CheckQueryPlaceholders checks that the placeholders of a query are in code. A value bound inside a string or a
+// comment could end it, e.g. a new line in a -- comment, and run the rest of the value as code. Placeholders must also
+// stand apart from words and quotes, which would change how the bound literal is read, e.g. E{{name}} makes a
+// PostgreSQL string where backslashes escape.
+func CheckQueryPlaceholders(dbType string, query string) error {
+	matches := queryPlaceholderRegex.FindAllStringSubmatchIndex(query, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	codeOffsets, err := queryCodeOffsets(dbType, query)
+	if err != nil {
+		return fmt.Errorf("failed to parse the query: %v", err)
+	}
+	for _, match := range matches {
+		offset := utf8.RuneCountInString(query[:match[0]])
+		if !codeOffsets[offset] {
+			return fmt.Errorf("placeholder {{%s}} must be outside of strings and comments, it is bound as a quoted value", query[match[2]:match[3]])
+		}
+	}
+	for i, match := range matches {
+		before, _ := utf8.DecodeLastRuneInString(query[:match[0]])
+		after, _ := utf8.DecodeRuneInString(query[match[1]:])
+		touching := (i > 0 && matches[i-1][1] == match[0]) || (i+1 < len(matches) && matches[i+1][0] == match[1])
+		if touching || isPlaceholderNeighbour(before) || isPlaceholderNeighbour(after) {
+			return fmt.Errorf("placeholder {{%s}} must be separated from the words and quotes around it", query[match[2]:match[3]])
+		}
+	}
+	return nil
+}
+
+// isPlaceholderNeighbour reports whether a character next to a placeholder would merge with the bound literal
+func isPlaceholderNeighbour(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_$'\"`\\", r)
+}
+
+// placeholderScanner describes the strings and comments of the query languages the SQL tokenizer doesn't support
+type placeholderScanner struct {
+	lineComments  []string
+	blockComments bool
+	quotes        map[rune]bool // Quote characters, true when backslashes escape in them, doubled quotes escape otherwise
+}
+
+var placeholderScanners = map[string]placeholderScanner{
+	constants.DatabaseTypeMongoDB:   {lineComments: []string{"//"}, blockComments: true, quotes: map[rune]bool{'\'': true, '"': true, '`': true}},
+	constants.DatabaseTypeNeo4j:     {lineComments: []string{"//"}, blockComments: true, quotes: map[rune]bool{'\'': true, '"': true, '`': false}},
+	constants.DatabaseTypeCassandra: {lineComments: []string{"--", "//"}, blockComments: true, quotes: map[rune]bool{'\'': false, '"': false}},
+	constants.DatabaseTypeRedis:     {quotes: map[rune]bool{'"': true, '\'': false}},
+}
+
+// queryCodeOffsets returns the rune offsets of the query that are code, outside of strings, identifiers and comments
+func queryCodeOffsets(dbType string, query string) (map[int]bool, error) {
+	offsets := map[int]bool{}
+	if scanner, ok := placeholderScanners[dbType]; ok {
+		runes := []rune(query)
+	scan:
+		for i := 0; i < len(runes); {
+			rest := string(runes[i:min(i+2, len(runes))])
+			for _, prefix := range scanner.lineComments {
+				if strings.HasPrefix(rest, prefix) {
+					for i < len(runes) && runes[i] != '\n' {
+						i++
+					}
+					continue scan
+				}
+			}
+			if scanner.blockComments && rest == "/*" {
+				end := strings.Index(string(runes[i+2:]), "*/")
+				if end < 0 {
+					return nil, fmt.Errorf("unterminated comment")
+				}
+				i += 2 + len([]rune(string(runes[i+2:])[:end])) + 2
+				continue
+			}
+			if backslashEscapes, isQuote := scanner.quotes[runes[i]]; isQuote {
+				end, err := scanQuoted(runes, i, runes[i], backslashEscapes)
+				if err != nil {
+					return nil, err
+				}
+				i = end
+				continue
+			}
+			offsets[i] = true
+			i++
+		}
+		return offsets, nil
+	}
+
+	dialect, ok := getSQLDialect(dbType)
+	if !ok {
+		// SQLite, DuckDB and SQL Server strings only escape quotes by doubling them
+		dialect = sqlDialect{name: dbType, backtickIdents: dbType == constants.DatabaseTypeSQLite}
+		if dbType == constants.DatabaseTypeSpreadsheet {
+			dialect = postgresDialect
+		}
+	}
+	tokens, err := tokenizeSQL(query, dialect)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		if token.kind == sqlTokenString || token.kind == sqlTokenIdentifier {
+			continue
+		}
+		for i := token.start; i < token.end; i++ {
+			offsets[i] = true
+		}
+	}
+	return offsets, nil
+}
+
+func nullLiteral(dbType string) (string, error) {
+	switch dbType {
+	case constants.DatabaseTypeMongoDB, constants.DatabaseTypeNeo4j:
+		return "null", nil
+	case constants.DatabaseTypeRedis:
+		return "", fmt.Errorf("Redis has no null values")
+	}
+	return "NULL", nil
+}
+
+// paramLiteral converts a value to the type of its parameter and returns it as a literal of the database
+func paramLiteral(dbType, paramType string, value interface{}) (string, error) {
+	switch paramType {
+	case ParamTypeString:
+		text, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("expected a string, got %T", value)
+		}
+		return stringParamLiteral(dbType, text)
+	case ParamTypeInteger:
+		number, err := integerParamValue(value)
+		if err != nil {
+			return "", err
+		}
+		return numberParamLiteral(dbType, strconv.FormatInt(number, 10)), nil
+	case ParamTypeNumber:
+		number, err := numberParamValue(value)
+		if err != nil {
+			return "", err
+		}
+		return numberParamLiteral(dbType, strconv.FormatFloat(number, 'g', -1, 64)), nil
+	case ParamTypeBoolean:
+		flag, err := booleanParamValue(value)
+		if err != nil {
+			return "", err
+		}
+		return booleanParamLiteral(dbType, flag), nil
+	case ParamTypeDate, ParamTypeTimestamp:
+		text, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("expected a %s string, got %T", paramType, value)
+		}
+		return timeParamLiteral(dbType, paramType, text)
+	}
+	return "", fmt.Errorf("unsupported parameter type: %s", paramType)
+}
+
+// numberParamLiteral separates negative numbers from what precedes them, so a - before the placeholder doesn't make
+// a -- comment
+func numberParamLiteral(dbType, number string) string {
+	if strings.HasPrefix(number, "-") && dbType != constants.DatabaseTypeMongoDB && dbType != constants.DatabaseTypeRedis {
+		return " " + number
+	}
+	return number
+}
+
+func integerParamValue(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+			return 0, fmt.Errorf("expected an integer, got %v", v)
+		}
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case json.Number:
+		return v.Int64()
+	case string:
+		number, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("expected an integer, got %q", v)
+		}
+		return number, nil
+	}
+	return 0, fmt.Errorf("expected an integer, got %T", value)
+}
+
+func numberParamValue(value interface{}) (float64, error) {
+	var number float64
+	switch v := value.(type) {
+	case float64:
+		number = v
+	case int:
+		number = float64(v)
+	case int32:
+		number = float64(v)
+	case int64:
+		number = float64(v)
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return 0, err
+		}
+		number = parsed
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("expected a number, got %q", v)
+		}
+		number = parsed
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", value)
+	}
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, fmt.Errorf("expected a finite number, got %v", number)
+	}
+	return number, nil
+}
+
+func booleanParamValue(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		flag, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return false, fmt.Errorf("expected a boolean, got %q", v)
+		}
+		return flag, nil
+	}
+	return false, fmt.Errorf("expected a boolean, got %T", value)
+}
+
+func booleanParamLiteral(dbType string, flag bool) string {
+	switch dbType {
+	case constants.DatabaseTypeMSSQL, constants.DatabaseTypeSQLite, constants.DatabaseTypeRedis:
+		// SQL Server has no boolean literals and SQLite stores booleans as integers
+		if flag {
+			return "1"
+		}
+		return "0"
+	}
+	return strconv.FormatBool(flag)
+}
+
+// timeParamLiteral parses a date (2006-01-02) or a timestamp (RFC 3339) and returns it as a literal. SQL timestamps are
+// written in UTC without an offset, which every dialect parses.
+func timeParamLiteral(dbType, paramType, text string) (string, error) {
+	text = strings.TrimSpace(text)
+	var value time.Time
+	var err error
+	if paramType == ParamTypeDate {
+		value, err = time.Parse("2006-01-02", text)
+	} else {
+		value, err = time.Parse(time.RFC3339Nano, text)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid %s %q: %v", paramType, text, err)
+	}
+	value = value.UTC()
+
+	switch dbType {
+	case constants.DatabaseTypeMongoDB:
+		return fmt.Sprintf(`ISODate("%s")`, value.Format(time.RFC3339Nano)), nil
+	case constants.DatabaseTypeNeo4j:
+		if paramType == ParamTypeDate {
+			return fmt.Sprintf("date('%s')", value.Format("2006-01-02")), nil
+		}
+		return fmt.Sprintf("datetime('%s')", value.Format(time.RFC3339Nano)), nil
+	case constants.DatabaseTypeRedis:
+		return stringParamLiteral(dbType, value.Format(time.RFC3339Nano))
+	}
+	if paramType == ParamTypeDate {
+		return stringParamLiteral(dbType, value.Format("2006-01-02"))
+	}
+	return stringParamLiteral(dbType, value.Format("2006-01-02 15:04:05.999999"))
+}
+
+// stringParamLiteral quotes a string for the database, escaping the characters its string literals give a meaning to
+func stringParamLiteral(dbType, text string) (string, error) {
+	if strings.ContainsRune(text, 0) {
+		return "", fmt.Errorf("strings can't contain NUL characters")
+	}
+	switch dbType {
+	case constants.DatabaseTypeMySQL:
+		// Backslashes escape in MySQL strings unless NO_BACKSLASH_ESCAPES is set
+		return "'" + strings.ReplaceAll(strings.ReplaceAll(text, `\`, `\\`), "'", "''") + "'", nil
+	case constants.DatabaseTypeMSSQL:
+		return "N'" + strings.ReplaceAll(text, "'", "''") + "'", nil
+	case constants.DatabaseTypeClickhouse, constants.DatabaseTypeNeo4j:
+		return "'" + strings.ReplaceAll(strings.ReplaceAll(text, `\`, `\\`), "'", `\'`) + "'", nil
+	case constants.DatabaseTypeMongoDB:
+		quoted, err := json.Marshal(text)
+		if err != nil {
+			return "", err
+		}
+		return string(quoted), nil
+	case constants.DatabaseTypeRedis:
+		replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+		return `"` + replacer.Replace(text) + `"`, nil
+	}
+	// PostgreSQL, YugabyteDB, spreadsheets, SQLite, DuckDB and Cassandra only give a meaning to quotes
+	return "'" + strings.ReplaceAll(text, "'", "''") + "'", nil
+}
+
+// CheckParamValue reports whether the value can be bound to a parameter of the type, e.g. to validate a default
+func CheckParamValue(dbType, paramType string, value interface{}) error {
+	_, err := paramLiteral(dbType, paramType, value)
+	return err
+}
@@ -0,0 +1,159 @@
+package dbmanager
+
+import (
+	"neobase-ai/internal/constants"
+	"strings"
+	"testing"
+)
+
+func TestBindQueryParams(t *testing.T) {
+	params := []QueryParam{
+		{Name: "name", Type: ParamTypeString},
+		{Name: "limit", Type: ParamTypeInteger, Default: float64(10)},
+		{Name: "min", Type: ParamTypeNumber},
+		{Name: "active", Type: ParamTypeBoolean},
+		{Name: "since", Type: ParamTypeDate},
+		{Name: "at", Type: ParamTypeTimestamp},
+		{Name: "required", Type: ParamTypeString, Required: true},
+	}
+
+	tests := []struct {
+		name    string
+		dbType  string
+		query   string
+		values  map[string]interface{}
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "postgres string with a quote",
+			dbType: constants.DatabaseTypePostgreSQL,
+			query:  "SELECT * FROM users WHERE name = {{name}}",
+			values: map[string]interface{}{"name": `O'Brien\`},
+			want:   `SELECT * FROM users WHERE name = 'O''Brien\'`,
+		},
+		{
+			name:   "mysql string escapes backslashes",
+			dbType: constants.DatabaseTypeMySQL,
+			query:  "SELECT * FROM users WHERE name = {{ name }}",
+			values: map[string]interface{}{"name": `a\' OR 1=1 -- `},
+			want:   `SELECT * FROM users WHERE name = 'a\\'' OR 1=1 -- '`,
+		},
+		{
+			name:   "mssql unicode string",
+			dbType: constants.DatabaseTypeMSSQL,
+			query:  "SELECT * FROM users WHERE name = {{name}}",
+			values: map[string]interface{}{"name": "é'"},
+			want:   "SELECT * FROM users WHERE name = N'é'''",
+		},
+		{
+			name:   "mongodb string",
+			dbType: constants.DatabaseTypeMongoDB,
+			query:  `db.users.find({"name": {{name}}})`,
+			values: map[string]interface{}{"name": `a"}`},
+			want:   `db.users.find({"name": "a\"}"})`,
+		},
+		{
+			name:   "default and negative number",
+			dbType: constants.DatabaseTypePostgreSQL,
+			query:  "SELECT * FROM t WHERE x -{{min}} > 0 LIMIT {{limit}}",
+			values: map[string]interface{}{"min": -1.5},
+			want:   "SELECT * FROM t WHERE x - -1.5 > 0 LIMIT 10",
+		},
+		{
+			name:   "booleans and dates",
+			dbType: constants.DatabaseTypeMSSQL,
+			query:  "SELECT * FROM t WHERE active = {{active}} AND d >= {{since}} AND ts < {{at}}",
+			values: map[string]interface{}{"active": "true", "since": "2024-02-29", "at": "2024-03-01T10:00:00+02:00"},
+			want:   "SELECT * FROM t WHERE active = 1 AND d >= N'2024-02-29' AND ts < N'2024-03-01 08:00:00'",
+		},
+		{
+			name:   "missing optional value is null",
+			dbType: constants.DatabaseTypePostgreSQL,
+			query:  "SELECT * FROM t WHERE name = {{name}}",
+			want:   "SELECT * FROM t WHERE name = NULL",
+		},
+		{
+			name:    "missing required value",
+			dbType:  constants.DatabaseTypePostgreSQL,
+			query:   "SELECT * FROM t WHERE name = {{required}}",
+			wantErr: "parameter required is required",
+		},
+		{
+			name:    "unknown value",
+			dbType:  constants.DatabaseTypePostgreSQL,
+			query:   "SELECT * FROM t WHERE name = {{name}}",
+			values:  map[string]interface{}{"other": "x"},
+			wantErr: "unknown parameter: other",
+		},
+		{
+			name:    "placeholder without parameter",
+			dbType:  constants.DatabaseTypePostgreSQL,
+			query:   "SELECT * FROM t WHERE name = {{nickname}}",
+			wantErr: "placeholder {{nickname}} has no parameter",
+		},
+		{
+			name:    "wrong type",
+			dbType:  constants.DatabaseTypePostgreSQL,
+			query:   "SELECT * FROM t LIMIT {{limit}}",
+			values:  map[string]interface{}{"limit": "ten"},
+			wantErr: "expected an integer",
+		},
+		{
+			name:    "placeholder in a string",
+			dbType:  constants.DatabaseTypePostgreSQL,
+			query:   "SELECT * FROM t WHERE name = '{{name}}'",
+			wantErr: "must be outside of strings and comments",
+		},
+		{
+			name:    "placeholder in a comment",
+			dbType:  constants.DatabaseTypePostgreSQL,
+			query:   "SELECT * FROM t -- {{name}}\nWHERE 1 = 1",
+			wantErr: "must be outside of strings and comments",
+		},
+		{
+			name:    "postgres escape string prefix",
+			dbType:  constants.DatabaseTypePostgreSQL,
+			query:   "SELECT * FROM t WHERE name = E{{name}}",
+			values:  map[string]interface{}{"name": `\' OR 1=1 --`},
+			wantErr: "must be separated from the words and quotes around it",
+		},
+		{
+			name:    "mysql charset introducer",
+			dbType:  constants.DatabaseTypeMySQL,
+			query:   "SELECT * FROM t WHERE name = _binary{{name}}",
+			wantErr: "must be separated from the words and quotes around it",
+		},
+		{
+			name:    "adjacent placeholders",
+			dbType:  constants.DatabaseTypePostgreSQL,
+			query:   "SELECT {{name}}{{required}}",
+			wantErr: "must be separated from the words and quotes around it",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BindQueryParams(tt.dbType, tt.query, params, tt.values)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindQueryParams: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryPlaceholders(t *testing.T) {
+	got := QueryPlaceholders("SELECT {{b}}, {{ a }}, {{b}}, {{1x}} FROM t")
+	if strings.Join(got, ",") != "b,a" {
+		t.Errorf("unexpected placeholders %v", got)
+	}
+}
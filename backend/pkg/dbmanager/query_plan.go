@@ -0,0 +1,187 @@
+package dbmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryExplainer is implemented by the drivers that can return the plan of a query
+type QueryExplainer interface {
+	// ExplainQuery returns the plan of the query, analyze also runs it to get actual rows and timings
+	ExplainQuery(ctx context.Context, conn *Connection, query string, analyze bool) (*dtos.QueryPlan, error)
+}
+
+// Timeout of the queries run by ExplainQuery, ANALYZE runs the query itself
+const explainQueryTimeout = 1 * time.Minute
+
+// ExplainQuery returns the normalized plan of a query of the chat's database. With analyze the query is run in a
+// transaction that is rolled back, so only read-only queries can be analyzed.
+func (m *Manager) ExplainQuery(ctx context.Context, chatID string, query string, analyze bool) (*dtos.QueryPlan, error) {
+	m.mu.RLock()
+	conn, exists := m.connections[chatID]
+	m.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("no connection found for chat ID: %s", chatID)
+	}
+
+	driver, exists := m.drivers[conn.Config.Type]
+	if !exists {
+		return nil, fmt.Errorf("no driver found for type: %s", conn.Config.Type)
+	}
+	explainer, ok := driver.(QueryExplainer)
+	if !ok {
+		return nil, fmt.Errorf("query plans are not supported for %s", conn.Config.Type)
+	}
+
+	query = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+	if analysis, ok := AnalyzeSQL(conn.Config.Type, query); ok && len(analysis.Statements) != 1 {
+		return nil, fmt.Errorf("only a single statement can be explained")
+	}
+	if analyze {
+		if err := checkReadOnlyMode(conn.Config.Type, query); err != nil {
+			return nil, fmt.Errorf("only read-only queries can be analyzed as ANALYZE runs them: %v", err)
+		}
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, explainQueryTimeout)
+	defer cancel()
+
+	plan, err := explainer.ExplainQuery(execCtx, conn, query, analyze)
+	if err != nil {
+		log.Printf("Manager -> ExplainQuery -> Error explaining query for chat %s: %v", chatID, err)
+		return nil, err
+	}
+	summarizeQueryPlan(plan)
+	return plan, nil
+}
+
+// newQueryPlan returns the plan of a root step, its summary is filled by ExplainQuery
+func newQueryPlan(root *dtos.QueryPlanNode, analyzed bool, raw string) *dtos.QueryPlan {
+	return &dtos.QueryPlan{
+		Root:        root,
+		Analyzed:    analyzed,
+		IndexesUsed: []string{},
+		FullScans:   []string{},
+		Raw:         raw,
+	}
+}
+
+// summarizeQueryPlan lists the indexes used by the plan and the relations it reads without one
+func summarizeQueryPlan(plan *dtos.QueryPlan) {
+	walkQueryPlan(plan.Root, func(node *dtos.QueryPlanNode) {
+		if node.Index != "" && !slices.Contains(plan.IndexesUsed, node.Index) {
+			plan.IndexesUsed = append(plan.IndexesUsed, node.Index)
+		}
+		if node.FullScan && node.Relation != "" && !slices.Contains(plan.FullScans, node.Relation) {
+			plan.FullScans = append(plan.FullScans, node.Relation)
+		}
+	})
+}
+
+// walkQueryPlan calls fn for every step of the plan, parents first
+func walkQueryPlan(node *dtos.QueryPlanNode, fn func(node *dtos.QueryPlanNode)) {
+	if node == nil {
+		return
+	}
+	fn(node)
+	for _, child := range node.Children {
+		walkQueryPlan(child, fn)
+	}
+}
+
+// QueryPlanRelations returns the tables and collections read by the plan
+func QueryPlanRelations(plan *dtos.QueryPlan) []string {
+	var relations []string
+	walkQueryPlan(plan.Root, func(node *dtos.QueryPlanNode) {
+		if node.Relation != "" && !slices.Contains(relations, node.Relation) {
+			relations = append(relations, node.Relation)
+		}
+	})
+	return relations
+}
+
+// FormatQueryPlan returns the plan as an indented tree, one step per line
+func FormatQueryPlan(plan *dtos.QueryPlan) string {
+	var builder strings.Builder
+	var format func(node *dtos.QueryPlanNode, depth int)
+	format = func(node *dtos.QueryPlanNode, depth int) {
+		builder.WriteString(strings.Repeat("  ", depth) + "-> " + node.Operation)
+		if node.Relation != "" {
+			builder.WriteString(" on " + node.Relation)
+		}
+		if node.Index != "" {
+			builder.WriteString(" using " + node.Index)
+		}
+		var stats []string
+		if node.FullScan {
+			stats = append(stats, "full scan")
+		}
+		if node.Cost != nil {
+			stats = append(stats, "cost="+formatPlanNumber(*node.Cost))
+		}
+		if node.Rows != nil {
+			stats = append(stats, "rows="+formatPlanNumber(*node.Rows))
+		}
+		if node.ActualRows != nil {
+			stats = append(stats, "actual rows="+formatPlanNumber(*node.ActualRows))
+		}
+		if node.ActualTimeMs != nil {
+			stats = append(stats, "actual time="+formatPlanNumber(*node.ActualTimeMs)+"ms")
+		}
+		if len(stats) > 0 {
+			builder.WriteString(" (" + strings.Join(stats, ", ") + ")")
+		}
+		builder.WriteString("\n")
+		for _, detail := range node.Details {
+			builder.WriteString(strings.Repeat("  ", depth+2) + detail + "\n")
+		}
+		for _, child := range node.Children {
+			format(child, depth+1)
+		}
+	}
+	if plan.Root != nil {
+		format(plan.Root, 0)
+	}
+	return builder.String()
+}
+
+// formatPlanNumber formats a cost or a row count without trailing zeros
+func formatPlanNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// planFloat converts a number of a plan, databases return them as numbers or strings
+func planFloat(value interface{}) *float64 {
+	var result float64
+	switch v := value.(type) {
+	case float64:
+		result = v
+	case int64:
+		result = float64(v)
+	case int32:
+		result = float64(v)
+	case int:
+		result = float64(v)
+	case string:
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil
+		}
+		result = parsed
+	default:
+		return nil
+	}
+	return &result
+}
+
+// planString returns a string value of a plan, or "" if it isn't one
+func planString(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
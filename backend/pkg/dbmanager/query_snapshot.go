@@ -0,0 +1,678 @@
+package dbmanager
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// QuerySnapshot is the before-image of the rows an UPDATE or DELETE changes, read in its transaction right before it
+// runs, along with the statements writing the rows back
+type QuerySnapshot struct {
+	Operation  string                   `json:"operation"` // update or delete
+	Table      string                   `json:"table"`     // Table or collection
+	KeyColumns []string                 `json:"key_columns,omitempty"`
+	Columns    []string                 `json:"columns,omitempty"`
+	Rows       []map[string]interface{} `json:"rows,omitempty"`       // SQL rows, for display
+	RowCount   int                      `json:"row_count"`            // Rows or documents in the snapshot
+	Documents  string                   `json:"documents,omitempty"`  // MongoDB documents, as canonical extended JSON
+	Statements []string                 `json:"statements,omitempty"` // SQL statements restoring the rows
+	Inverse    string                   `json:"inverse"`              // Readable form of the restore
+	CapturedAt time.Time                `json:"captured_at"`
+}
+
+// SnapshotTransaction is implemented by the transactions that can take the before-image of an UPDATE or DELETE and
+// restore it. Writes of other transactions can still happen between the snapshot and its restore, which overwrites them.
+type SnapshotTransaction interface {
+	// CaptureSnapshot returns nil when the query doesn't change existing rows, and an error when it does but its rows
+	// can't be restored, e.g. it changes more than maxRows rows
+	CaptureSnapshot(ctx context.Context, query string, maxRows int) (*QuerySnapshot, error)
+	RestoreSnapshot(ctx context.Context, snapshot *QuerySnapshot) (*QueryExecutionResult, error)
+}
+
+// Rows per INSERT statement restoring deleted rows
+const snapshotInsertBatchSize = 100
+
+// Flavors of SQL snapshots, for the catalog queries and the literals
+const (
+	snapshotFlavorPostgres = "postgres"
+	snapshotFlavorMySQL    = "mysql"
+	snapshotFlavorSQLite   = "sqlite"
+)
+
+// sqlSnapshotter takes and restores snapshots with the queries of a SQL transaction
+type sqlSnapshotter struct {
+	flavor  string
+	dialect sqlDialect
+	query   func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	exec    func(ctx context.Context, query string) (int64, error)
+}
+
+// snapshotStatement is the part of an UPDATE or DELETE needed to read the rows it changes
+type snapshotStatement struct {
+	command    string   // UPDATE or DELETE
+	table      string   // Table as written in the query
+	tableName  []string // Unquoted parts of the qualified table name
+	tableRef   string   // Table with its alias
+	where      string   // Condition of the WHERE clause, empty when every row is changed
+	setColumns []string // Columns assigned by an UPDATE
+}
+
+// Keywords that can't be the alias of the table of an UPDATE/DELETE
+var snapshotClauseKeywords = map[string]bool{
+	"SET": true, "WHERE": true, "RETURNING": true, "ORDER": true, "LIMIT": true, "USING": true, "FROM": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "CROSS": true, "NATURAL": true, "STRAIGHT_JOIN": true,
+	"PARTITION": true, "INDEXED": true, "NOT": true,
+}
+
+// parseSnapshotStatement returns the table and the WHERE clause of a query made of a single UPDATE or DELETE, nil
+// when the query doesn't update or delete rows. Multi-table forms, ORDER BY and LIMIT aren't supported.
+func parseSnapshotStatement(query string, dialect sqlDialect) (*snapshotStatement, error) {
+	tokens, err := tokenizeSQL(query, dialect)
+	if err != nil {
+		return nil, err
+	}
+	statements := splitSQLTokens(tokens)
+	if len(statements) != 1 || !statements[0][0].is(sqlTokenWord, "UPDATE") && !statements[0][0].is(sqlTokenWord, "DELETE") {
+		for _, token := range tokens {
+			if token.is(sqlTokenWord, "UPDATE") || token.is(sqlTokenWord, "DELETE") {
+				return nil, fmt.Errorf("only queries made of a single UPDATE or DELETE can be snapshotted")
+			}
+		}
+		return nil, nil
+	}
+
+	tokens = statements[0]
+	runes := []rune(query)
+	text := func(from, to sqlToken) string {
+		return string(runes[from.start:to.end])
+	}
+	statement := &snapshotStatement{command: tokens[0].text}
+	unsupported := fmt.Errorf("this form of %s can't be snapshotted", statement.command)
+
+	i := 1
+	skipModifiers := func() {
+		for i < len(tokens) && tokens[i].kind == sqlTokenWord && (tokens[i].text == "LOW_PRIORITY" || tokens[i].text == "QUICK" ||
+			tokens[i].text == "IGNORE" || tokens[i].text == "ONLY") {
+			i++
+		}
+	}
+	skipModifiers()
+	if statement.command == "DELETE" {
+		if i >= len(tokens) || !tokens[i].is(sqlTokenWord, "FROM") {
+			return nil, unsupported
+		}
+		i++
+		skipModifiers()
+	}
+
+	// Table name, possibly qualified, and its alias
+	if i >= len(tokens) || tokens[i].kind != sqlTokenWord && tokens[i].kind != sqlTokenIdentifier {
+		return nil, unsupported
+	}
+	nameStart := i
+	statement.tableName = append(statement.tableName, snapshotTokenName(tokens[i], runes))
+	for i+2 < len(tokens) && tokens[i+1].is(sqlTokenSymbol, ".") && (tokens[i+2].kind == sqlTokenWord || tokens[i+2].kind == sqlTokenIdentifier) {
+		i += 2
+		statement.tableName = append(statement.tableName, snapshotTokenName(tokens[i], runes))
+	}
+	statement.table = text(tokens[nameStart], tokens[i])
+	refEnd := i
+	i++
+	if i < len(tokens) && tokens[i].is(sqlTokenWord, "AS") {
+		i++
+	}
+	if i < len(tokens) && (tokens[i].kind == sqlTokenIdentifier || tokens[i].kind == sqlTokenWord && !snapshotClauseKeywords[tokens[i].text]) {
+		refEnd = i
+		i++
+	}
+	statement.tableRef = text(tokens[nameStart], tokens[refEnd])
+
+	if statement.command == "UPDATE" {
+		if i >= len(tokens) || !tokens[i].is(sqlTokenWord, "SET") {
+			return nil, unsupported
+		}
+		columns, next, err := parseSetColumns(tokens, i+1, runes)
+		if err != nil {
+			return nil, err
+		}
+		statement.setColumns = columns
+		i = next
+	}
+
+	if i < len(tokens) && tokens[i].is(sqlTokenWord, "WHERE") {
+		end, depth := i+1, 0
+		for ; end < len(tokens); end++ {
+			if tokens[end].is(sqlTokenSymbol, "(") {
+				depth++
+			} else if tokens[end].is(sqlTokenSymbol, ")") {
+				depth--
+			} else if depth == 0 && tokens[end].kind == sqlTokenWord && whereClauseEnd[tokens[end].text] {
+				break
+			}
+		}
+		if end == i+1 {
+			return nil, unsupported
+		}
+		statement.where = text(tokens[i+1], tokens[end-1])
+		i = end
+	}
+	if i < len(tokens) && !tokens[i].is(sqlTokenWord, "RETURNING") {
+		return nil, fmt.Errorf("%s queries with %s can't be snapshotted", statement.command, tokens[i].text)
+	}
+	return statement, nil
+}
+
+// parseSetColumns returns the columns assigned by the SET clause starting at start, and the offset after the clause
+func parseSetColumns(tokens []sqlToken, start int, runes []rune) ([]string, int, error) {
+	var columns []string
+	i := start
+	for {
+		// Column, possibly qualified by the table or its alias
+		if i >= len(tokens) || tokens[i].kind != sqlTokenWord && tokens[i].kind != sqlTokenIdentifier {
+			return nil, 0, fmt.Errorf("only SET clauses assigning single columns can be snapshotted")
+		}
+		for i+2 < len(tokens) && tokens[i+1].is(sqlTokenSymbol, ".") {
+			i += 2
+		}
+		columns = append(columns, snapshotTokenName(tokens[i], runes))
+		i++
+		if i >= len(tokens) || !tokens[i].is(sqlTokenSymbol, "=") {
+			return nil, 0, fmt.Errorf("only SET clauses assigning single columns can be snapshotted")
+		}
+
+		// Value, up to the next assignment or the end of the clause
+		depth := 0
+		for i++; i < len(tokens); i++ {
+			token := tokens[i]
+			if token.is(sqlTokenSymbol, "(") {
+				depth++
+			} else if token.is(sqlTokenSymbol, ")") {
+				depth--
+			} else if depth == 0 && (token.is(sqlTokenSymbol, ",") || token.kind == sqlTokenWord &&
+				(token.text == "WHERE" || token.text == "FROM" || whereClauseEnd[token.text])) {
+				break
+			}
+		}
+		if i < len(tokens) && tokens[i].is(sqlTokenSymbol, ",") {
+			i++
+			continue
+		}
+		if i < len(tokens) && tokens[i].is(sqlTokenWord, "FROM") {
+			return nil, 0, fmt.Errorf("UPDATE queries with FROM can't be snapshotted")
+		}
+		return columns, i, nil
+	}
+}
+
+// snapshotTokenName returns a name as it's written in the query, without its quotes
+func snapshotTokenName(token sqlToken, runes []rune) string {
+	if token.kind == sqlTokenIdentifier {
+		return token.text
+	}
+	return string(runes[token.start:token.end])
+}
+
+// Savepoint keeping a Postgres transaction usable when its snapshot fails
+const snapshotSavepoint = "neobase_snapshot"
+
+// capture reads the rows changed by an UPDATE or DELETE and builds the statements writing them back
+func (s *sqlSnapshotter) capture(ctx context.Context, query string, maxRows int) (*QuerySnapshot, error) {
+	statement, err := parseSnapshotStatement(query, s.dialect)
+	if err != nil || statement == nil {
+		return nil, err
+	}
+	if s.flavor != snapshotFlavorPostgres {
+		return s.read(ctx, statement, maxRows)
+	}
+
+	// A failed statement aborts a Postgres transaction, which would fail the query itself
+	if _, err := s.exec(ctx, "SAVEPOINT "+snapshotSavepoint); err != nil {
+		return nil, fmt.Errorf("failed to create savepoint: %v", err)
+	}
+	snapshot, err := s.read(ctx, statement, maxRows)
+	if err != nil {
+		if _, rollbackErr := s.exec(ctx, "ROLLBACK TO SAVEPOINT "+snapshotSavepoint); rollbackErr != nil {
+			log.Printf("sqlSnapshotter -> capture -> Failed to roll back to savepoint: %v", rollbackErr)
+		}
+		return nil, err
+	}
+	if _, err := s.exec(ctx, "RELEASE SAVEPOINT "+snapshotSavepoint); err != nil {
+		return nil, fmt.Errorf("failed to release savepoint: %v", err)
+	}
+	return snapshot, nil
+}
+
+// read reads the rows changed by the statement
+func (s *sqlSnapshotter) read(ctx context.Context, statement *snapshotStatement, maxRows int) (*QuerySnapshot, error) {
+	keys, err := s.primaryKey(ctx, statement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the primary key of %s: %v", statement.table, err)
+	}
+	if statement.command == "UPDATE" {
+		// Updated rows are written back by their primary key, which must not change
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("%s has no primary key to restore the updated rows by", statement.table)
+		}
+		for _, column := range statement.setColumns {
+			for _, key := range keys {
+				if strings.EqualFold(column, key) {
+					return nil, fmt.Errorf("the UPDATE changes the primary key column %s", key)
+				}
+			}
+		}
+	}
+
+	selectQuery := "SELECT * FROM " + statement.tableRef
+	if statement.where != "" {
+		selectQuery += " WHERE " + statement.where
+	}
+	if s.flavor != snapshotFlavorSQLite {
+		// Lock the rows so they don't change before the write
+		selectQuery += " FOR UPDATE"
+	}
+	rows, err := s.query(ctx, selectQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the rows: %v", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns: %v", err)
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get column types: %v", err)
+	}
+	typeNames := make([]string, len(columnTypes))
+	for i, columnType := range columnTypes {
+		typeNames[i] = strings.ToUpper(columnType.DatabaseTypeName())
+	}
+
+	var values [][]interface{}
+	for rows.Next() {
+		if len(values) >= maxRows {
+			return nil, fmt.Errorf("the %s changes more than %d rows", statement.command, maxRows)
+		}
+		row := make([]interface{}, len(columns))
+		scanArgs := make([]interface{}, len(columns))
+		for i := range row {
+			scanArgs[i] = &row[i]
+		}
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		values = append(values, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the rows: %v", err)
+	}
+
+	snapshot := &QuerySnapshot{
+		Operation:  strings.ToLower(statement.command),
+		Table:      statement.table,
+		KeyColumns: keys,
+		Columns:    columns,
+		RowCount:   len(values),
+		CapturedAt: time.Now(),
+	}
+	for _, row := range values {
+		displayRow := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			displayRow[column] = snapshotDisplayValue(row[i], typeNames[i])
+		}
+		snapshot.Rows = append(snapshot.Rows, displayRow)
+	}
+
+	if statement.command == "DELETE" {
+		snapshot.Statements = s.insertStatements(statement.table, columns, typeNames, values)
+	} else {
+		snapshot.Statements, err = s.updateStatements(statement, keys, columns, typeNames, values)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(snapshot.Statements) > 0 {
+		snapshot.Inverse = strings.Join(snapshot.Statements, ";\n") + ";"
+	}
+	return snapshot, nil
+}
+
+// primaryKey returns the primary key columns of the table of the statement, read in the transaction
+func (s *sqlSnapshotter) primaryKey(ctx context.Context, statement *snapshotStatement) ([]string, error) {
+	var rows *sql.Rows
+	var err error
+	name := statement.tableName[len(statement.tableName)-1]
+	switch s.flavor {
+	case snapshotFlavorPostgres:
+		rows, err = s.query(ctx, `SELECT a.attname FROM pg_index i
+			JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+			WHERE i.indrelid = $1::regclass AND i.indisprimary
+			ORDER BY array_position(i.indkey::int2[], a.attnum)`, statement.table)
+	case snapshotFlavorMySQL:
+		var schema interface{}
+		if len(statement.tableName) > 1 {
+			schema = statement.tableName[len(statement.tableName)-2]
+		}
+		rows, err = s.query(ctx, `SELECT COLUMN_NAME FROM information_schema.KEY_COLUMN_USAGE
+			WHERE TABLE_SCHEMA = COALESCE(?, DATABASE()) AND TABLE_NAME = ? AND CONSTRAINT_NAME = 'PRIMARY'
+			ORDER BY ORDINAL_POSITION`, schema, name)
+	case snapshotFlavorSQLite:
+		rows, err = s.query(ctx, "SELECT name FROM pragma_table_info(?) WHERE pk > 0 ORDER BY pk", name)
+	default:
+		return nil, fmt.Errorf("unsupported snapshot flavor: %s", s.flavor)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// insertStatements returns the INSERTs writing deleted rows back
+func (s *sqlSnapshotter) insertStatements(table string, columns []string, typeNames []string, values [][]interface{}) []string {
+	quotedColumns := make([]string, len(columns))
+	for i, column := range columns {
+		quotedColumns[i] = s.quoteIdentifier(column)
+	}
+	prefix := fmt.Sprintf("INSERT INTO %s (%s)", table, strings.Join(quotedColumns, ", "))
+	if s.flavor == snapshotFlavorPostgres {
+		// Identity columns generated always only take their old values with an override
+		prefix += " OVERRIDING SYSTEM VALUE"
+	}
+
+	var statements []string
+	for start := 0; start < len(values); start += snapshotInsertBatchSize {
+		end := min(start+snapshotInsertBatchSize, len(values))
+		tuples := make([]string, 0, end-start)
+		for _, row := range values[start:end] {
+			literals := make([]string, len(row))
+			for i, value := range row {
+				literals[i] = s.literal(value, typeNames[i])
+			}
+			tuples = append(tuples, "("+strings.Join(literals, ", ")+")")
+		}
+		statements = append(statements, prefix+" VALUES "+strings.Join(tuples, ", "))
+	}
+	return statements
+}
+
+// updateStatements returns the UPDATEs setting the columns changed by the statement back to their old values, row by row
+func (s *sqlSnapshotter) updateStatements(statement *snapshotStatement, keys []string, columns []string, typeNames []string, values [][]interface{}) ([]string, error) {
+	keyIndexes := make([]int, len(keys))
+	for i, key := range keys {
+		if keyIndexes[i] = snapshotColumnIndex(columns, key); keyIndexes[i] < 0 {
+			return nil, fmt.Errorf("the primary key column %s was not read", key)
+		}
+	}
+	var setIndexes []int
+	for _, column := range statement.setColumns {
+		index := snapshotColumnIndex(columns, column)
+		if index < 0 {
+			return nil, fmt.Errorf("the updated column %s was not read", column)
+		}
+		duplicate := false
+		for _, setIndex := range setIndexes {
+			duplicate = duplicate || setIndex == index
+		}
+		if !duplicate {
+			setIndexes = append(setIndexes, index)
+		}
+	}
+
+	statements := make([]string, 0, len(values))
+	for _, row := range values {
+		assignments := make([]string, len(setIndexes))
+		for i, index := range setIndexes {
+			assignments[i] = s.quoteIdentifier(columns[index]) + " = " + s.literal(row[index], typeNames[index])
+		}
+		conditions := make([]string, len(keyIndexes))
+		for i, index := range keyIndexes {
+			conditions[i] = s.quoteIdentifier(columns[index]) + " = " + s.literal(row[index], typeNames[index])
+		}
+		statements = append(statements, fmt.Sprintf("UPDATE %s SET %s WHERE %s", statement.table, strings.Join(assignments, ", "), strings.Join(conditions, " AND ")))
+	}
+	return statements, nil
+}
+
+// snapshotColumnIndex returns the offset of a column, matched case-insensitively when no column has the exact name
+func snapshotColumnIndex(columns []string, name string) int {
+	for i, column := range columns {
+		if column == name {
+			return i
+		}
+	}
+	for i, column := range columns {
+		if strings.EqualFold(column, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// restore runs the statements of a snapshot
+func (s *sqlSnapshotter) restore(ctx context.Context, snapshot *QuerySnapshot) (*QueryExecutionResult, error) {
+	startTime := time.Now()
+	var rowsAffected int64
+	for _, statement := range snapshot.Statements {
+		affected, err := s.exec(ctx, statement)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore the rows of %s: %v", snapshot.Table, err)
+		}
+		rowsAffected += affected
+	}
+
+	result := &QueryExecutionResult{
+		Result: map[string]interface{}{
+			"rowsAffected": rowsAffected,
+			"message":      fmt.Sprintf("%d row(s) of %s restored from the snapshot", rowsAffected, snapshot.Table),
+		},
+		ExecutionTime: int(time.Since(startTime).Milliseconds()),
+		RowsAffected:  rowsAffected,
+	}
+	result.StreamData, _ = json.Marshal(result.Result)
+	return result, nil
+}
+
+func (s *sqlSnapshotter) quoteIdentifier(name string) string {
+	if s.flavor == snapshotFlavorMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// literal returns a value read from the database as a SQL literal of the flavor
+func (s *sqlSnapshotter) literal(value interface{}, typeName string) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int32, int16, int8, int, uint64, uint32, uint16, uint8, uint:
+		return fmt.Sprintf("%d", v)
+	case float64:
+		return s.floatLiteral(v, 64)
+	case float32:
+		return s.floatLiteral(float64(v), 32)
+	case []byte:
+		if isBinaryType(typeName) {
+			if s.flavor == snapshotFlavorPostgres {
+				return `'\x` + hex.EncodeToString(v) + `'`
+			}
+			return "X'" + hex.EncodeToString(v) + "'"
+		}
+		return s.stringLiteral(string(v))
+	case time.Time:
+		return s.stringLiteral(s.timeText(v, typeName))
+	case string:
+		return s.stringLiteral(v)
+	}
+	return s.stringLiteral(fmt.Sprint(value))
+}
+
+func (s *sqlSnapshotter) floatLiteral(value float64, bitSize int) string {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return s.stringLiteral(strconv.FormatFloat(value, 'g', -1, bitSize))
+	}
+	return strconv.FormatFloat(value, 'g', -1, bitSize)
+}
+
+func (s *sqlSnapshotter) stringLiteral(value string) string {
+	if s.dialect.backslashEscapes {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// timeText formats a time read from a column in the format the database parses back to the same value
+func (s *sqlSnapshotter) timeText(value time.Time, typeName string) string {
+	switch {
+	case typeName == "DATE":
+		return value.Format("2006-01-02")
+	case s.flavor == snapshotFlavorPostgres && typeName == "TIME":
+		return value.Format("15:04:05.999999")
+	case s.flavor == snapshotFlavorPostgres && typeName == "TIMETZ":
+		return value.Format("15:04:05.999999-07:00")
+	case s.flavor == snapshotFlavorPostgres && typeName == "TIMESTAMP", s.flavor == snapshotFlavorMySQL:
+		return value.Format("2006-01-02 15:04:05.999999")
+	case s.flavor == snapshotFlavorSQLite && value.Location() == time.UTC:
+		return value.Format("2006-01-02 15:04:05.999999999")
+	}
+	return value.Format("2006-01-02 15:04:05.999999999-07:00")
+}
+
+// isBinaryType reports if a column type holds bytes rather than text
+func isBinaryType(typeName string) bool {
+	return strings.Contains(typeName, "BLOB") || strings.Contains(typeName, "BINARY") || typeName == "BYTEA" ||
+		typeName == "BIT" || typeName == "GEOMETRY"
+}
+
+// snapshotDisplayValue returns a value of a snapshot row as it's shown to the user
+func snapshotDisplayValue(value interface{}, typeName string) interface{} {
+	if bytes, ok := value.([]byte); ok && !isBinaryType(typeName) {
+		return string(bytes)
+	}
+	return value
+}
+
+// CaptureSnapshot reads the rows an UPDATE or DELETE changes, in the transaction
+func (tx *PostgresTransaction) CaptureSnapshot(ctx context.Context, query string, maxRows int) (*QuerySnapshot, error) {
+	return tx.snapshotter().capture(ctx, query, maxRows)
+}
+
+// RestoreSnapshot writes the rows of a snapshot back, in the transaction
+func (tx *PostgresTransaction) RestoreSnapshot(ctx context.Context, snapshot *QuerySnapshot) (*QueryExecutionResult, error) {
+	return tx.snapshotter().restore(ctx, snapshot)
+}
+
+func (tx *PostgresTransaction) snapshotter() *sqlSnapshotter {
+	return &sqlSnapshotter{
+		flavor:  snapshotFlavorPostgres,
+		dialect: postgresDialect,
+		query:   tx.tx.QueryContext,
+		exec: func(ctx context.Context, query string) (int64, error) {
+			result, err := tx.tx.ExecContext(ctx, query)
+			if err != nil {
+				return 0, err
+			}
+			return result.RowsAffected()
+		},
+	}
+}
+
+// CaptureSnapshot reads the rows an UPDATE or DELETE changes, in the transaction
+func (t *MySQLTransaction) CaptureSnapshot(ctx context.Context, query string, maxRows int) (*QuerySnapshot, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("no active transaction")
+	}
+	return newGormSnapshotter(t.tx, snapshotFlavorMySQL, mysqlDialect).capture(ctx, query, maxRows)
+}
+
+// RestoreSnapshot writes the rows of a snapshot back, in the transaction
+func (t *MySQLTransaction) RestoreSnapshot(ctx context.Context, snapshot *QuerySnapshot) (*QueryExecutionResult, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("no active transaction")
+	}
+	return newGormSnapshotter(t.tx, snapshotFlavorMySQL, mysqlDialect).restore(ctx, snapshot)
+}
+
+// CaptureSnapshot reads the rows an UPDATE or DELETE changes, in the transaction
+func (t *SQLiteTransaction) CaptureSnapshot(ctx context.Context, query string, maxRows int) (*QuerySnapshot, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("no active transaction")
+	}
+	return newGormSnapshotter(t.tx, snapshotFlavorSQLite, postgresDialect).capture(ctx, query, maxRows)
+}
+
+// RestoreSnapshot writes the rows of a snapshot back, in the transaction
+func (t *SQLiteTransaction) RestoreSnapshot(ctx context.Context, snapshot *QuerySnapshot) (*QueryExecutionResult, error) {
+	if t.tx == nil {
+		return nil, fmt.Errorf("no active transaction")
+	}
+	// The database file is persisted on commit
+	t.wrote = true
+	return newGormSnapshotter(t.tx, snapshotFlavorSQLite, postgresDialect).restore(ctx, snapshot)
+}
+
+// newGormSnapshotter returns a snapshotter running its queries in a gorm transaction
+func newGormSnapshotter(tx *gorm.DB, flavor string, dialect sqlDialect) *sqlSnapshotter {
+	return &sqlSnapshotter{
+		flavor:  flavor,
+		dialect: dialect,
+		query: func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+			return tx.WithContext(ctx).Raw(query, args...).Rows()
+		},
+		exec: func(ctx context.Context, query string) (int64, error) {
+			result := tx.WithContext(ctx).Exec(query)
+			return result.RowsAffected, result.Error
+		},
+	}
+}
+
+// CaptureSnapshot reads the rows an UPDATE or DELETE changes, in the schema of the spreadsheet
+func (t *SpreadsheetTransaction) CaptureSnapshot(ctx context.Context, query string, maxRows int) (*QuerySnapshot, error) {
+	pgTx, ok := t.pgTx.(SnapshotTransaction)
+	if !ok {
+		return nil, fmt.Errorf("the spreadsheet transaction doesn't support snapshots")
+	}
+	if err := t.setSearchPath(ctx); err != nil {
+		return nil, err
+	}
+	return pgTx.CaptureSnapshot(ctx, query, maxRows)
+}
+
+// RestoreSnapshot writes the rows of a snapshot back, in the schema of the spreadsheet
+func (t *SpreadsheetTransaction) RestoreSnapshot(ctx context.Context, snapshot *QuerySnapshot) (*QueryExecutionResult, error) {
+	pgTx, ok := t.pgTx.(SnapshotTransaction)
+	if !ok {
+		return nil, fmt.Errorf("the spreadsheet transaction doesn't support snapshots")
+	}
+	if err := t.setSearchPath(ctx); err != nil {
+		return nil, err
+	}
+	return pgTx.RestoreSnapshot(ctx, snapshot)
+}
@@ -0,0 +1,156 @@
+package dbmanager
+
+import (
+	"fmt"
+	"neobase-ai/internal/constants"
+	"regexp"
+	"strings"
+)
+
+var (
+	mongoReadQueryRegex = regexp.MustCompile(`^db\.([\w.$-]+\.(find|findOne|aggregate|countDocuments|estimatedDocumentCount|distinct)|getCollectionNames)\(`)
+	mongoMethodRegex    = regexp.MustCompile(`\.(\w+)\s*\(`)
+)
+
+// Methods a read-only MongoDB query may call
+var mongoReadMethods = map[string]bool{
+	"find": true, "findOne": true, "aggregate": true, "countDocuments": true, "estimatedDocumentCount": true,
+	"distinct": true, "getCollectionNames": true, "sort": true, "limit": true, "skip": true, "project": true,
+	"count": true, "toArray": true,
+}
+
+// CheckMongoReadOnlyQuery returns an error unless the MongoDB query only reads data
+func CheckMongoReadOnlyQuery(query string) error {
+	query = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+
+	if !mongoReadQueryRegex.MatchString(query) {
+		return fmt.Errorf("only find, aggregate, countDocuments and distinct queries are allowed")
+	}
+	if strings.Contains(query, "$out") || strings.Contains(query, "$merge") {
+		return fmt.Errorf("$out and $merge stages are not allowed")
+	}
+	for _, match := range mongoMethodRegex.FindAllStringSubmatch(query, -1) {
+		if !mongoReadMethods[match[1]] {
+			return fmt.Errorf("%s is not allowed in read-only queries", match[1])
+		}
+	}
+	return nil
+}
+
+// Commands a read-only Redis query may run
+var redisReadCommands = map[string]bool{
+	"GET": true, "MGET": true, "STRLEN": true, "GETRANGE": true, "EXISTS": true, "TYPE": true, "TTL": true, "PTTL": true,
+	"SCAN": true, "DBSIZE": true, "HGET": true, "HMGET": true, "HGETALL": true, "HKEYS": true, "HVALS": true,
+	"HLEN": true, "HEXISTS": true, "HSCAN": true, "LRANGE": true, "LLEN": true, "LINDEX": true, "SMEMBERS": true,
+	"SCARD": true, "SISMEMBER": true, "SMISMEMBER": true, "SSCAN": true, "SINTER": true, "SUNION": true, "SDIFF": true,
+	"ZRANGE": true, "ZRANGEBYSCORE": true, "ZREVRANGE": true, "ZREVRANGEBYSCORE": true, "ZSCORE": true, "ZRANK": true,
+	"ZREVRANK": true, "ZCARD": true, "ZCOUNT": true, "ZSCAN": true, "XRANGE": true, "XREVRANGE": true, "XLEN": true,
+	"PFCOUNT": true, "GETBIT": true, "BITCOUNT": true, "JSON.GET": true, "JSON.TYPE": true,
+}
+
+// Keywords of the statements that write, for the databases the SQL analyzer doesn't support
+var (
+	sqlWriteKeywords = map[string]bool{
+		"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "INTO": true, "CREATE": true, "ALTER": true,
+		"DROP": true, "TRUNCATE": true, "EXEC": true, "EXECUTE": true, "CALL": true, "GRANT": true, "REVOKE": true,
+		"ATTACH": true, "DETACH": true, "COPY": true, "PRAGMA": true, "LOAD": true, "INSTALL": true, "BATCH": true,
+		"USE": true,
+	}
+	cypherWriteKeywords = map[string]bool{
+		"CREATE": true, "MERGE": true, "DELETE": true, "DETACH": true, "SET": true, "REMOVE": true, "DROP": true,
+		"LOAD": true, "FOREACH": true, "CALL": true,
+	}
+	queryWordRegex = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+)
+
+// CheckReadOnlyQuery returns an error unless the query is a single statement that only reads data. Unlike the checks
+// of read-only connections, which rely on the session rejecting writes, it is strict for every database type.
+func CheckReadOnlyQuery(dbType string, query string) error {
+	switch dbType {
+	case constants.DatabaseTypeMongoDB:
+		return CheckMongoReadOnlyQuery(query)
+	case constants.DatabaseTypeRedis:
+		commands := 0
+		for _, line := range splitRedisCommands(query) {
+			tokens, err := parseRedisCommand(line)
+			if err != nil {
+				return err
+			}
+			if len(tokens) == 0 {
+				continue
+			}
+			if commands++; commands > 1 {
+				return fmt.Errorf("only a single command is allowed")
+			}
+			if !redisReadCommands[strings.ToUpper(tokens[0])] {
+				return fmt.Errorf("%s is not allowed in read-only queries", strings.ToUpper(tokens[0]))
+			}
+		}
+		if commands == 0 {
+			return fmt.Errorf("the query has no command")
+		}
+		return nil
+	}
+
+	if analysis, ok := AnalyzeSQL(dbType, query); ok {
+		if !analysis.IsReadOnly() {
+			return fmt.Errorf("only a single statement reading data is allowed")
+		}
+		return nil
+	}
+
+	// Other databases are checked on the words of the query outside of strings and comments
+	codeOffsets, err := queryCodeOffsets(dbType, query)
+	if err != nil {
+		return fmt.Errorf("the query could not be parsed: %v", err)
+	}
+	runes := []rune(query)
+	for i := range runes {
+		if !codeOffsets[i] {
+			runes[i] = ' '
+		}
+	}
+	code := strings.TrimSuffix(strings.TrimSpace(string(runes)), ";")
+	if strings.Contains(code, ";") {
+		return fmt.Errorf("only a single statement is allowed")
+	}
+	words := queryWordRegex.FindAllString(strings.ToUpper(code), -1)
+	if len(words) == 0 {
+		return fmt.Errorf("the query has no statement")
+	}
+
+	writeKeywords := sqlWriteKeywords
+	if dbType == constants.DatabaseTypeNeo4j {
+		writeKeywords = cypherWriteKeywords
+	} else if words[0] != "SELECT" && (words[0] != "WITH" || dbType == constants.DatabaseTypeCassandra) {
+		return fmt.Errorf("%s statements are not allowed in read-only queries", words[0])
+	}
+	for _, word := range words {
+		if writeKeywords[word] {
+			return fmt.Errorf("%s is not allowed in read-only queries", word)
+		}
+	}
+	return nil
+}
+
+// checkReadOnlyMode returns an error when a query of a read-only connection may write. The session already rejects
+// writes, this also rejects the statements switching it back to read-write, e.g. SET or COMMIT.
+func checkReadOnlyMode(dbType string, query string) error {
+	if dbType == constants.DatabaseTypeMongoDB {
+		return CheckMongoReadOnlyQuery(query)
+	}
+
+	analysis, ok := AnalyzeSQL(dbType, query)
+	if !ok {
+		return nil
+	}
+	if len(analysis.Statements) == 0 {
+		return fmt.Errorf("the query could not be parsed")
+	}
+	for _, statement := range analysis.Statements {
+		if statement.Kind != StatementKindRead {
+			return fmt.Errorf("%s statements are not allowed in read-only mode", statement.Command)
+		}
+	}
+	return nil
+}
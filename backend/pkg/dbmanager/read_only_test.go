@@ -0,0 +1,68 @@
+package dbmanager
+
+import (
+	"neobase-ai/internal/constants"
+	"testing"
+)
+
+func TestCheckMongoReadOnlyQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		readOnly bool
+	}{
+		{`db.users.find({"active": true}).sort({"name": 1}).limit(10)`, true},
+		{`db.users.findOne({"_id": 1});`, true},
+		{`db.orders.aggregate([{"$group": {"_id": "$status", "total": {"$sum": 1}}}])`, true},
+		{`db.users.countDocuments({})`, true},
+		{`db.getCollectionNames()`, true},
+		{`db.users.deleteMany({})`, false},
+		{`db.users.find({}).forEach(function(u) { db.users.remove(u) })`, false},
+		{`db.orders.aggregate([{"$match": {}}, {"$out": "orders_copy"}])`, false},
+		{`db.orders.aggregate([{"$merge": {"into": "orders_copy"}}])`, false},
+		{`db.users.drop()`, false},
+		{`show collections`, false},
+	}
+
+	for _, tt := range tests {
+		err := CheckMongoReadOnlyQuery(tt.query)
+		if (err == nil) != tt.readOnly {
+			t.Errorf("CheckMongoReadOnlyQuery(%q) = %v, want read-only %v", tt.query, err, tt.readOnly)
+		}
+	}
+}
+
+func TestCheckReadOnlyQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		dbType   string
+		query    string
+		readOnly bool
+	}{
+		{"postgres select", constants.DatabaseTypePostgreSQL, "SELECT * FROM users;", true},
+		{"postgres several statements", constants.DatabaseTypePostgreSQL, "SELECT 1; SELECT 2", false},
+		{"postgres write", constants.DatabaseTypePostgreSQL, "UPDATE users SET active = false WHERE id = 1", false},
+		{"mysql executable comment", constants.DatabaseTypeMySQL, "SELECT 1 /*! ; DELETE FROM users */", false},
+		{"mssql select", constants.DatabaseTypeMSSQL, "SELECT TOP 10 [name] FROM [users] WHERE note = 'DELETE'", true},
+		{"mssql batch without semicolons", constants.DatabaseTypeMSSQL, "SELECT 1 DELETE FROM users", false},
+		{"mssql select into", constants.DatabaseTypeMSSQL, "SELECT * INTO users_backup FROM users", false},
+		{"sqlite several statements", constants.DatabaseTypeSQLite, "SELECT 1; SELECT 2", false},
+		{"sqlite pragma", constants.DatabaseTypeSQLite, "PRAGMA journal_mode = DELETE", false},
+		{"duckdb with", constants.DatabaseTypeDuckDB, "WITH t AS (SELECT 1 AS a) SELECT a FROM t", true},
+		{"cassandra with", constants.DatabaseTypeCassandra, "WITH t AS (SELECT 1) SELECT * FROM t", false},
+		{"neo4j match", constants.DatabaseTypeNeo4j, "MATCH (u:User) RETURN u.name LIMIT 10", true},
+		{"neo4j set", constants.DatabaseTypeNeo4j, "MATCH (u:User) SET u.active = false", false},
+		{"redis read", constants.DatabaseTypeRedis, "GET user:1", true},
+		{"redis write", constants.DatabaseTypeRedis, "SET user:1 alice", false},
+		{"redis several commands", constants.DatabaseTypeRedis, "GET a\nGET b", false},
+		{"mongodb read", constants.DatabaseTypeMongoDB, "db.users.find({})", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckReadOnlyQuery(tt.dbType, tt.query)
+			if (err == nil) != tt.readOnly {
+				t.Errorf("CheckReadOnlyQuery(%q) = %v, want read-only %v", tt.query, err, tt.readOnly)
+			}
+		})
+	}
+}
@@ -0,0 +1,459 @@
+package dbmanager
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/utils"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisDriver implements the DatabaseDriver interface for Redis
+type RedisDriver struct{}
+
+// NewRedisDriver creates a new Redis driver
+func NewRedisDriver() DatabaseDriver {
+	return &RedisDriver{}
+}
+
+// buildRedisOptions builds go-redis client options from the connection config, returns temp cert files to clean up
+func buildRedisOptions(config ConnectionConfig) (*redis.Options, []string, error) {
+	var tempFiles []string
+
+	port := "6379" // Default port for Redis
+	if config.Port != nil && *config.Port != "" {
+		port = *config.Port
+	}
+
+	// Database is the logical database index, defaults to 0
+	dbIndex := 0
+	if strings.TrimSpace(config.Database) != "" {
+		index, err := strconv.Atoi(strings.TrimSpace(config.Database))
+		if err != nil || index < 0 {
+			return nil, nil, fmt.Errorf("invalid Redis database index: %s, must be a non-negative number", config.Database)
+		}
+		dbIndex = index
+	}
+
+	options := &redis.Options{
+		Addr:         fmt.Sprintf("%s:%s", config.Host, port),
+		DB:           dbIndex,
+		DialTimeout:  10 * time.Second,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		PoolSize:     25,
+		MinIdleConns: 2,
+	}
+
+	// Username is optional, Redis < 6 only supports password auth
+	if config.Username != nil && *config.Username != "" && *config.Username != "default" {
+		options.Username = *config.Username
+	}
+	if config.Password != nil {
+		options.Password = *config.Password
+	}
+
+	// Configure SSL/TLS
+	if config.UseSSL {
+		sslMode := "require"
+		if config.SSLMode != nil {
+			sslMode = *config.SSLMode
+		}
+
+		if sslMode != "disable" {
+			var certURL, keyURL, rootCertURL string
+			if config.SSLCertURL != nil {
+				certURL = *config.SSLCertURL
+			}
+			if config.SSLKeyURL != nil {
+				keyURL = *config.SSLKeyURL
+			}
+			if config.SSLRootCertURL != nil {
+				rootCertURL = *config.SSLRootCertURL
+			}
+
+			// Fetch certificates from URLs
+			certPath, keyPath, rootCertPath, certTempFiles, err := utils.PrepareCertificatesFromURLs(certURL, keyURL, rootCertURL)
+			if err != nil {
+				return nil, nil, err
+			}
+			tempFiles = certTempFiles
+
+			tlsConfig := &tls.Config{
+				ServerName:         config.Host,
+				MinVersion:         tls.VersionTLS12,
+				InsecureSkipVerify: sslMode == "require", // Require encryption but don't verify certificates
+			}
+
+			// Add client certificates if provided
+			if certPath != "" && keyPath != "" {
+				cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+				if err != nil {
+					for _, file := range tempFiles {
+						os.Remove(file)
+					}
+					return nil, nil, fmt.Errorf("failed to load client certificates: %v", err)
+				}
+				tlsConfig.Certificates = []tls.Certificate{cert}
+			}
+
+			// Add root CA if provided
+			if rootCertPath != "" {
+				rootCA, err := os.ReadFile(rootCertPath)
+				if err != nil {
+					for _, file := range tempFiles {
+						os.Remove(file)
+					}
+					return nil, nil, fmt.Errorf("failed to read root CA: %v", err)
+				}
+
+				rootCertPool := x509.NewCertPool()
+				if ok := rootCertPool.AppendCertsFromPEM(rootCA); !ok {
+					for _, file := range tempFiles {
+						os.Remove(file)
+					}
+					return nil, nil, fmt.Errorf("failed to parse root CA certificate")
+				}
+				tlsConfig.RootCAs = rootCertPool
+			}
+
+			options.TLSConfig = tlsConfig
+		}
+	}
+
+	return options, tempFiles, nil
+}
+
+// Connect establishes a connection to a Redis server
+func (d *RedisDriver) Connect(config ConnectionConfig) (*Connection, error) {
+	log.Printf("RedisDriver -> Connect -> Connecting to Redis at %s:%v", config.Host, config.Port)
+
+	options, tempFiles, err := buildRedisOptions(config)
+	if err != nil {
+		log.Printf("RedisDriver -> Connect -> Error building options: %v", err)
+		return nil, err
+	}
+
+	client := redis.NewClient(options)
+
+	// Ping the server to verify connection
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		for _, file := range tempFiles {
+			os.Remove(file)
+		}
+		log.Printf("RedisDriver -> Connect -> Error pinging Redis: %v", err)
+		return nil, fmt.Errorf("failed to ping Redis: %v", err)
+	}
+
+	// Create a wrapper for the Redis client
+	redisWrapper := &RedisWrapper{
+		Client:   client,
+		Database: options.DB,
+	}
+
+	conn := &Connection{
+		DB:         nil, // Redis doesn't use GORM
+		LastUsed:   time.Now(),
+		Status:     StatusConnected,
+		Config:     config,
+		MongoDBObj: redisWrapper, // Store Redis client in the non-GORM client field
+		TempFiles:  tempFiles,
+	}
+
+	log.Printf("RedisDriver -> Connect -> Successfully connected to Redis at %s:%v (db %d)", config.Host, config.Port, options.DB)
+	return conn, nil
+}
+
+// Disconnect closes the Redis connection
+func (d *RedisDriver) Disconnect(conn *Connection) error {
+	log.Printf("RedisDriver -> Disconnect -> Disconnecting from Redis")
+
+	wrapper, ok := conn.MongoDBObj.(*RedisWrapper)
+	if !ok {
+		return fmt.Errorf("invalid Redis connection")
+	}
+
+	if err := wrapper.Client.Close(); err != nil {
+		log.Printf("RedisDriver -> Disconnect -> Error closing Redis client: %v", err)
+		return fmt.Errorf("failed to disconnect from Redis: %v", err)
+	}
+
+	// Clean up temporary certificate files
+	for _, file := range conn.TempFiles {
+		os.Remove(file)
+	}
+
+	log.Printf("RedisDriver -> Disconnect -> Successfully disconnected from Redis")
+	return nil
+}
+
+// Ping checks if the Redis connection is alive
+func (d *RedisDriver) Ping(conn *Connection) error {
+	wrapper, ok := conn.MongoDBObj.(*RedisWrapper)
+	if !ok {
+		return fmt.Errorf("invalid Redis connection")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := wrapper.Client.Ping(ctx).Err(); err != nil {
+		log.Printf("RedisDriver -> Ping -> Error pinging Redis: %v", err)
+		return fmt.Errorf("failed to ping Redis: %v", err)
+	}
+	return nil
+}
+
+// IsAlive checks if the Redis connection is alive
+func (d *RedisDriver) IsAlive(conn *Connection) bool {
+	return d.Ping(conn) == nil
+}
+
+// ExecuteQuery executes one or more Redis commands, e.g. "HGETALL user:1"
+func (d *RedisDriver) ExecuteQuery(ctx context.Context, conn *Connection, query string, queryType string, findCount bool) *QueryExecutionResult {
+	log.Printf("RedisDriver -> ExecuteQuery -> Executing Redis query: %s", query)
+
+	wrapper, ok := conn.MongoDBObj.(*RedisWrapper)
+	if !ok {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: "Failed to get Redis wrapper from connection",
+				Code:    "INTERNAL_ERROR",
+			},
+		}
+	}
+
+	return executeRedisCommands(ctx, wrapper.Client, query, findCount)
+}
+
+// executeRedisCommands executes a Redis query, multiple commands (or a MULTI ... EXEC block) run atomically in a transaction
+func executeRedisCommands(ctx context.Context, client *redis.Client, query string, findCount bool) *QueryExecutionResult {
+	startTime := time.Now()
+
+	// Parse every command in the query
+	var commands [][]string
+	inMulti := false
+	for _, line := range splitRedisCommands(query) {
+		tokens, err := parseRedisCommand(line)
+		if err != nil {
+			return &QueryExecutionResult{
+				Error: &dtos.QueryError{
+					Message: err.Error(),
+					Code:    "INVALID_QUERY",
+				},
+			}
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+
+		name := strings.ToUpper(tokens[0])
+		switch name {
+		case "MULTI":
+			inMulti = true
+			continue
+		case "EXEC":
+			continue
+		case "DISCARD":
+			// Nothing is sent to the server, so discarding is just dropping the queued commands
+			return &QueryExecutionResult{
+				Result:        map[string]interface{}{"message": "Transaction discarded"},
+				ExecutionTime: int(time.Since(startTime).Milliseconds()),
+				StreamData:    []byte(`{"message":"Transaction discarded"}`),
+			}
+		}
+
+		if redisBlockedCommands[name] {
+			return &QueryExecutionResult{
+				Error: &dtos.QueryError{
+					Message: fmt.Sprintf("Command %s is not supported", name),
+					Code:    "UNSUPPORTED_COMMAND",
+					Details: "Blocking, subscription and connection level commands can't be executed on a shared connection",
+				},
+			}
+		}
+		if redisFullScanCommands[name] {
+			return &QueryExecutionResult{
+				Error: &dtos.QueryError{
+					Message: fmt.Sprintf("Command %s is not supported, use SCAN with a MATCH pattern instead", name),
+					Code:    "UNSUPPORTED_COMMAND",
+					Details: "Commands reading the whole keyspace at once block the server until they finish",
+				},
+			}
+		}
+		commands = append(commands, tokens)
+	}
+
+	if len(commands) == 0 {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: "No Redis command found in query",
+				Code:    "INVALID_QUERY",
+			},
+		}
+	}
+
+	var resultData interface{}
+	var rowsAffected int64
+
+	if len(commands) == 1 && !inMulti {
+		// Single command, execute directly
+		tokens := commands[0]
+		name := strings.ToUpper(tokens[0])
+
+		reply, err := client.Do(ctx, toRedisArgs(tokens)...).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			if ctx.Err() != nil {
+				return &QueryExecutionResult{
+					Error: &dtos.QueryError{
+						Message: "Query execution cancelled",
+						Code:    "EXECUTION_CANCELLED",
+					},
+				}
+			}
+			log.Printf("RedisDriver -> executeRedisCommands -> Error executing command: %v", err)
+			return &QueryExecutionResult{
+				ExecutionTime: int(time.Since(startTime).Milliseconds()),
+				Error: &dtos.QueryError{
+					Message: err.Error(),
+					Code:    "EXECUTION_ERROR",
+				},
+			}
+		}
+
+		if redisWriteCommands[name] {
+			if count, ok := reply.(int64); ok {
+				rowsAffected = count
+			}
+			resultData = map[string]interface{}{
+				"message": fmt.Sprintf("%s executed successfully", name),
+				"result":  normalizeRedisValue(reply),
+			}
+		} else {
+			resultData = map[string]interface{}{
+				"results": formatRedisRows(name, reply, findCount),
+			}
+		}
+	} else {
+		// Multiple commands, run them atomically with MULTI/EXEC
+		pipe := client.TxPipeline()
+		cmds := make([]*redis.Cmd, len(commands))
+		for i, tokens := range commands {
+			cmds[i] = pipe.Do(ctx, toRedisArgs(tokens)...)
+		}
+
+		if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+			if ctx.Err() != nil {
+				return &QueryExecutionResult{
+					Error: &dtos.QueryError{
+						Message: "Query execution cancelled",
+						Code:    "EXECUTION_CANCELLED",
+					},
+				}
+			}
+			log.Printf("RedisDriver -> executeRedisCommands -> Error executing transaction: %v", err)
+			return &QueryExecutionResult{
+				ExecutionTime: int(time.Since(startTime).Milliseconds()),
+				Error: &dtos.QueryError{
+					Message: err.Error(),
+					Code:    "EXECUTION_ERROR",
+					Details: "Redis transaction (MULTI/EXEC) failed",
+				},
+			}
+		}
+
+		rows := make([]map[string]interface{}, 0, len(cmds))
+		for i, cmd := range cmds {
+			row := map[string]interface{}{
+				"command": strings.Join(commands[i], " "),
+				"result":  normalizeRedisValue(cmd.Val()),
+			}
+			if err := cmd.Err(); err != nil && !errors.Is(err, redis.Nil) {
+				row["error"] = err.Error()
+			}
+			if count, ok := cmd.Val().(int64); ok && redisWriteCommands[strings.ToUpper(commands[i][0])] {
+				rowsAffected += count
+			}
+			rows = append(rows, row)
+		}
+		resultData = map[string]interface{}{
+			"results": rows,
+		}
+	}
+
+	executionTime := int(time.Since(startTime).Milliseconds())
+
+	// Marshal the result to JSON
+	resultJSON, err := json.Marshal(resultData)
+	if err != nil {
+		return &QueryExecutionResult{
+			ExecutionTime: executionTime,
+			Error: &dtos.QueryError{
+				Code:    "JSON_MARSHAL_FAILED",
+				Message: err.Error(),
+				Details: "Failed to marshal query results",
+			},
+		}
+	}
+
+	return &QueryExecutionResult{
+		Result:        resultData,
+		ExecutionTime: executionTime,
+		RowsAffected:  rowsAffected,
+		StreamData:    resultJSON,
+	}
+}
+
+// StreamQuery returns a cursor over the rows of ExecuteQuery, the result is read as a whole
+func (d *RedisDriver) StreamQuery(ctx context.Context, conn *Connection, query string) (ResultCursor, error) {
+	return newBufferedCursor(d.ExecuteQuery(ctx, conn, query, "", false))
+}
+
+// BeginTx begins a Redis transaction
+func (d *RedisDriver) BeginTx(ctx context.Context, conn *Connection) Transaction {
+	log.Printf("RedisDriver -> BeginTx -> Beginning Redis transaction")
+
+	wrapper, ok := conn.MongoDBObj.(*RedisWrapper)
+	if !ok || wrapper.Client == nil {
+		log.Printf("RedisDriver -> BeginTx -> Invalid Redis connection, type: %T", conn.MongoDBObj)
+		return &RedisTransaction{
+			Error: fmt.Errorf("invalid Redis connection, try disconnecting and reconnecting"),
+		}
+	}
+
+	return &RedisTransaction{
+		Wrapper: wrapper,
+	}
+}
+
+// GetSchema retrieves the inferred key pattern schema of the Redis database
+func (d *RedisDriver) GetSchema(ctx context.Context, db DBExecutor, selectedTables []string) (*SchemaInfo, error) {
+	fetcher := NewRedisSchemaFetcher(db)
+	return fetcher.GetSchema(ctx, db, selectedTables)
+}
+
+// GetTableChecksum calculates a checksum for a Redis key pattern
+func (d *RedisDriver) GetTableChecksum(ctx context.Context, db DBExecutor, table string) (string, error) {
+	fetcher := NewRedisSchemaFetcher(db)
+	return fetcher.GetTableChecksum(ctx, db, table)
+}
+
+// FetchExampleRecords fetches example values for a Redis key pattern
+func (d *RedisDriver) FetchExampleRecords(ctx context.Context, db DBExecutor, table string, limit int) ([]map[string]interface{}, error) {
+	fetcher := NewRedisSchemaFetcher(db)
+	return fetcher.FetchExampleRecords(ctx, db, table, limit)
+}
@@ -0,0 +1,476 @@
+package dbmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"neobase-ai/internal/utils"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisSchemaFetcher implements SchemaFetcher for Redis, key patterns are treated as tables
+type RedisSchemaFetcher struct {
+	db DBExecutor
+}
+
+// NewRedisSchemaFetcher creates a new Redis schema fetcher
+func NewRedisSchemaFetcher(db DBExecutor) SchemaFetcher {
+	return &RedisSchemaFetcher{
+		db: db,
+	}
+}
+
+// GetSchema infers the Redis schema by sampling keys with SCAN and grouping them into patterns
+func (f *RedisSchemaFetcher) GetSchema(ctx context.Context, db DBExecutor, selectedTables []string) (*SchemaInfo, error) {
+	log.Printf("RedisSchemaFetcher -> GetSchema -> Fetching Redis schema")
+
+	executor, ok := db.(*RedisExecutor)
+	if !ok {
+		return nil, fmt.Errorf("invalid Redis executor")
+	}
+	client := executor.wrapper.Client
+
+	// Group sampled keys into patterns
+	patterns, err := f.scanKeyPatterns(ctx, client, "*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan keys: %v", err)
+	}
+	log.Printf("RedisSchemaFetcher -> GetSchema -> Found %d key patterns", len(patterns))
+
+	// Filter patterns if specific ones are selected
+	selectAll := len(selectedTables) == 0 || (len(selectedTables) == 1 && selectedTables[0] == "ALL")
+	selected := make(map[string]bool, len(selectedTables))
+	for _, table := range selectedTables {
+		selected[table] = true
+	}
+
+	redisSchema := RedisSchema{
+		KeyPatterns: make(map[string]RedisKeyPattern),
+		UpdatedAt:   time.Now(),
+	}
+	for name, pattern := range patterns {
+		if !selectAll && !selected[name] {
+			continue
+		}
+
+		// Check for context cancellation
+		if err := ctx.Err(); err != nil {
+			log.Printf("RedisSchemaFetcher -> GetSchema -> Context cancelled: %v", err)
+			return nil, err
+		}
+
+		if err := f.inspectPattern(ctx, client, &pattern); err != nil {
+			log.Printf("RedisSchemaFetcher -> GetSchema -> Error inspecting pattern %s: %v", name, err)
+			continue
+		}
+		redisSchema.KeyPatterns[name] = pattern
+	}
+
+	return f.convertToSchemaInfo(redisSchema), nil
+}
+
+// scanKeyPatterns iterates keys with SCAN (never KEYS) up to redisMaxScannedKeys and groups them by inferred pattern
+func (f *RedisSchemaFetcher) scanKeyPatterns(ctx context.Context, client *redis.Client, match string) (map[string]RedisKeyPattern, error) {
+	patterns := make(map[string]RedisKeyPattern)
+
+	var cursor uint64
+	scanned := 0
+	for {
+		keys, nextCursor, err := client.Scan(ctx, cursor, match, redisScanBatchSize).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range keys {
+			name := inferRedisKeyPattern(key)
+			pattern, exists := patterns[name]
+			if !exists {
+				pattern = RedisKeyPattern{
+					Pattern: name,
+					Fields:  make(map[string]RedisField),
+				}
+			}
+			pattern.KeyCount++
+			if len(pattern.SampleKeys) < redisSampleKeysPerPattern {
+				pattern.SampleKeys = append(pattern.SampleKeys, key)
+			}
+			patterns[name] = pattern
+		}
+
+		scanned += len(keys)
+		cursor = nextCursor
+		if cursor == 0 || scanned >= redisMaxScannedKeys {
+			break
+		}
+	}
+
+	return patterns, nil
+}
+
+// inspectPattern reads the sample keys of a pattern to infer its type and fields
+func (f *RedisSchemaFetcher) inspectPattern(ctx context.Context, client *redis.Client, pattern *RedisKeyPattern) error {
+	fieldCounts := make(map[string]int)
+	fieldTypes := make(map[string]string)
+	inspected := 0
+
+	for _, key := range pattern.SampleKeys {
+		keyType, err := client.Type(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if keyType == "none" {
+			// Key expired or was deleted after SCAN
+			continue
+		}
+
+		if pattern.Type == "" {
+			pattern.Type = keyType
+		} else if pattern.Type != keyType {
+			pattern.Type = "mixed"
+		}
+
+		if ttl, err := client.TTL(ctx, key).Result(); err == nil && ttl > 0 {
+			pattern.HasTTL = true
+		}
+
+		fields, err := f.readKeyFields(ctx, client, key, keyType)
+		if err != nil {
+			log.Printf("RedisSchemaFetcher -> inspectPattern -> Error reading key %s: %v", key, err)
+			continue
+		}
+		inspected++
+
+		for name, fieldType := range fields {
+			fieldCounts[name]++
+			if existing, ok := fieldTypes[name]; ok && existing != fieldType {
+				fieldTypes[name] = "mixed"
+			} else {
+				fieldTypes[name] = fieldType
+			}
+		}
+	}
+
+	if inspected == 0 {
+		return fmt.Errorf("no readable keys for pattern %s", pattern.Pattern)
+	}
+
+	for name, count := range fieldCounts {
+		pattern.Fields[name] = RedisField{
+			Name:      name,
+			Type:      fieldTypes[name],
+			Frequency: float64(count) / float64(inspected),
+		}
+	}
+	return nil
+}
+
+// readKeyFields returns field name -> type for a single key based on its Redis type
+func (f *RedisSchemaFetcher) readKeyFields(ctx context.Context, client *redis.Client, key, keyType string) (map[string]string, error) {
+	fields := make(map[string]string)
+
+	switch keyType {
+	case "string":
+		value, err := client.Get(ctx, key).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		// JSON objects stored as strings expose their top level keys as fields
+		var doc map[string]interface{}
+		if inferRedisValueType(value) == "json" && json.Unmarshal([]byte(value), &doc) == nil {
+			for name, fieldValue := range doc {
+				fields[name] = inferRedisValueType(fmt.Sprintf("%v", fieldValue))
+			}
+		} else {
+			fields["value"] = inferRedisValueType(value)
+		}
+	case "hash":
+		values, err := client.HGetAll(ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range values {
+			fields[name] = inferRedisValueType(value)
+		}
+	case "list":
+		values, err := client.LRange(ctx, key, 0, 4).Result()
+		if err != nil {
+			return nil, err
+		}
+		fields["element"] = f.mergedValueType(values)
+	case "set":
+		values, err := client.SRandMemberN(ctx, key, 5).Result()
+		if err != nil {
+			return nil, err
+		}
+		fields["member"] = f.mergedValueType(values)
+	case "zset":
+		values, err := client.ZRangeWithScores(ctx, key, 0, 4).Result()
+		if err != nil {
+			return nil, err
+		}
+		members := make([]string, 0, len(values))
+		for _, value := range values {
+			members = append(members, fmt.Sprintf("%v", value.Member))
+		}
+		fields["member"] = f.mergedValueType(members)
+		fields["score"] = "float"
+	case "stream":
+		entries, err := client.XRangeN(ctx, key, "-", "+", 1).Result()
+		if err != nil {
+			return nil, err
+		}
+		fields["id"] = "stream_id"
+		for _, entry := range entries {
+			for name, value := range entry.Values {
+				fields[name] = inferRedisValueType(fmt.Sprintf("%v", value))
+			}
+		}
+	case "ReJSON-RL":
+		// RedisJSON documents
+		value, err := client.Do(ctx, "JSON.GET", key).Text()
+		if err != nil {
+			return nil, err
+		}
+		var doc map[string]interface{}
+		if json.Unmarshal([]byte(value), &doc) == nil {
+			for name, fieldValue := range doc {
+				fields[name] = inferRedisValueType(fmt.Sprintf("%v", fieldValue))
+			}
+		} else {
+			fields["value"] = "json"
+		}
+	default:
+		fields["value"] = keyType
+	}
+
+	return fields, nil
+}
+
+// mergedValueType returns the common type of the sampled values, or mixed
+func (f *RedisSchemaFetcher) mergedValueType(values []string) string {
+	merged := ""
+	for _, value := range values {
+		valueType := inferRedisValueType(value)
+		if merged == "" {
+			merged = valueType
+		} else if merged != valueType {
+			return "mixed"
+		}
+	}
+	if merged == "" {
+		return "string"
+	}
+	return merged
+}
+
+// convertToSchemaInfo converts the inferred Redis schema to generic SchemaInfo
+func (f *RedisSchemaFetcher) convertToSchemaInfo(redisSchema RedisSchema) *SchemaInfo {
+	schema := &SchemaInfo{
+		Tables:    make(map[string]TableSchema),
+		Views:     make(map[string]ViewSchema),
+		UpdatedAt: redisSchema.UpdatedAt,
+	}
+
+	for name, pattern := range redisSchema.KeyPatterns {
+		comment := fmt.Sprintf("Redis %s keys matching %s (%d keys sampled via SCAN, e.g. %s)", pattern.Type, pattern.Pattern, pattern.KeyCount, strings.Join(pattern.SampleKeys, ", "))
+		if pattern.HasTTL {
+			comment += ", keys have an expiry (TTL)"
+		}
+
+		tableSchema := TableSchema{
+			Name:        name,
+			Columns:     make(map[string]ColumnInfo),
+			Indexes:     make(map[string]IndexInfo),
+			ForeignKeys: make(map[string]ForeignKey),
+			Constraints: make(map[string]ConstraintInfo),
+			Comment:     comment,
+			RowCount:    pattern.KeyCount,
+		}
+
+		// The key itself identifies each record
+		tableSchema.Columns["key"] = ColumnInfo{
+			Name:       "key",
+			Type:       "key",
+			IsNullable: false,
+			Comment:    fmt.Sprintf("Redis key matching %s", pattern.Pattern),
+		}
+
+		// Column comments are kept free of sampling details so table checksums stay stable
+		for _, fieldName := range sortedRedisFieldNames(pattern.Fields) {
+			field := pattern.Fields[fieldName]
+			tableSchema.Columns[fieldName] = ColumnInfo{
+				Name:       fieldName,
+				Type:       field.Type,
+				IsNullable: field.Frequency < 1,
+				Comment:    fmt.Sprintf("%s value field", pattern.Type),
+			}
+		}
+
+		schema.Tables[name] = tableSchema
+	}
+
+	return schema
+}
+
+// GetTableChecksum calculates a checksum for a Redis key pattern from its type and fields
+func (f *RedisSchemaFetcher) GetTableChecksum(ctx context.Context, db DBExecutor, table string) (string, error) {
+	executor, ok := db.(*RedisExecutor)
+	if !ok {
+		return "", fmt.Errorf("invalid Redis executor")
+	}
+	client := executor.wrapper.Client
+
+	patterns, err := f.scanKeyPatterns(ctx, client, escapeRedisGlob(table))
+	if err != nil {
+		return "", fmt.Errorf("failed to scan keys: %v", err)
+	}
+
+	pattern, exists := patterns[table]
+	if !exists {
+		return "", fmt.Errorf("key pattern %s not found", table)
+	}
+	if err := f.inspectPattern(ctx, client, &pattern); err != nil {
+		return "", fmt.Errorf("failed to inspect key pattern: %v", err)
+	}
+
+	fieldsChecksum := ""
+	for _, fieldName := range sortedRedisFieldNames(pattern.Fields) {
+		fieldsChecksum += fmt.Sprintf("%s:%s,", fieldName, pattern.Fields[fieldName].Type)
+	}
+
+	return utils.MD5Hash(fmt.Sprintf("%s:%s:%s", table, pattern.Type, fieldsChecksum)), nil
+}
+
+// FetchExampleRecords fetches example values for keys matching a pattern
+func (f *RedisSchemaFetcher) FetchExampleRecords(ctx context.Context, db DBExecutor, table string, limit int) ([]map[string]interface{}, error) {
+	// Ensure limit is reasonable
+	if limit <= 0 {
+		limit = 3
+	} else if limit > 10 {
+		limit = 10 // Cap at 10 records to avoid large data transfers
+	}
+
+	executor, ok := db.(*RedisExecutor)
+	if !ok {
+		return nil, fmt.Errorf("invalid Redis executor")
+	}
+	client := executor.wrapper.Client
+
+	// Collect keys matching the pattern
+	var keys []string
+	var cursor uint64
+	scanned := 0
+	for len(keys) < limit {
+		batch, nextCursor, err := client.Scan(ctx, cursor, escapeRedisGlob(table), redisScanBatchSize).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan keys: %v", err)
+		}
+		for _, key := range batch {
+			// Only keep keys that belong to this exact pattern
+			if inferRedisKeyPattern(key) == table && len(keys) < limit {
+				keys = append(keys, key)
+			}
+		}
+		scanned += len(batch)
+		cursor = nextCursor
+		if cursor == 0 || scanned >= redisMaxScannedKeys {
+			break
+		}
+	}
+	sort.Strings(keys)
+
+	records := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		record, err := f.readKeyRecord(ctx, client, key)
+		if err != nil {
+			log.Printf("RedisSchemaFetcher -> FetchExampleRecords -> Error reading key %s: %v", key, err)
+			continue
+		}
+		records = append(records, record)
+	}
+
+	log.Printf("RedisSchemaFetcher -> FetchExampleRecords -> Fetched %d example records for pattern %s", len(records), table)
+	return records, nil
+}
+
+// readKeyRecord reads a key and its value as a record
+func (f *RedisSchemaFetcher) readKeyRecord(ctx context.Context, client *redis.Client, key string) (map[string]interface{}, error) {
+	keyType, err := client.Type(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	record := map[string]interface{}{"key": key}
+	switch keyType {
+	case "string":
+		value, err := client.Get(ctx, key).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		var doc map[string]interface{}
+		if inferRedisValueType(value) == "json" && json.Unmarshal([]byte(value), &doc) == nil {
+			for name, fieldValue := range doc {
+				record[name] = fieldValue
+			}
+		} else {
+			record["value"] = value
+		}
+	case "hash":
+		values, err := client.HGetAll(ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range values {
+			record[name] = value
+		}
+	case "list":
+		values, err := client.LRange(ctx, key, 0, 4).Result()
+		if err != nil {
+			return nil, err
+		}
+		record["element"] = values
+	case "set":
+		values, err := client.SRandMemberN(ctx, key, 5).Result()
+		if err != nil {
+			return nil, err
+		}
+		record["member"] = values
+	case "zset":
+		values, err := client.ZRangeWithScores(ctx, key, 0, 4).Result()
+		if err != nil {
+			return nil, err
+		}
+		members := make([]map[string]interface{}, 0, len(values))
+		for _, value := range values {
+			members = append(members, map[string]interface{}{"member": value.Member, "score": value.Score})
+		}
+		record["member"] = members
+	case "stream":
+		entries, err := client.XRangeN(ctx, key, "-", "+", 1).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			record["id"] = entry.ID
+			for name, value := range entry.Values {
+				record[name] = value
+			}
+		}
+	default:
+		record["type"] = keyType
+	}
+
+	return record, nil
+}
+
+// escapeRedisGlob escapes glob characters of a pattern except the * placeholders added by inferRedisKeyPattern
+func escapeRedisGlob(pattern string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+	return replacer.Replace(pattern)
+}
@@ -0,0 +1,39 @@
+package dbmanager
+
+// RedisSimplifier implements SchemaSimplifier for Redis
+type RedisSimplifier struct{}
+
+// SimplifyDataType simplifies inferred Redis value types for better readability
+func (s *RedisSimplifier) SimplifyDataType(dbType string) string {
+	switch dbType {
+	case "key":
+		return "Key"
+	case "integer", "float":
+		return "Number"
+	case "string":
+		return "String"
+	case "boolean":
+		return "Boolean"
+	case "json":
+		return "JSON"
+	case "stream_id":
+		return "StreamID"
+	default:
+		return dbType
+	}
+}
+
+// GetColumnConstraints returns constraints for a Redis field
+func (s *RedisSimplifier) GetColumnConstraints(col ColumnInfo, table TableSchema) []string {
+	constraints := []string{}
+
+	// The key uniquely identifies a record
+	if col.Name == "key" {
+		constraints = append(constraints, "PRIMARY KEY")
+	}
+	if !col.IsNullable && col.Name != "key" {
+		constraints = append(constraints, "NOT NULL")
+	}
+
+	return constraints
+}
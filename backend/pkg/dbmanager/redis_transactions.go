@@ -0,0 +1,52 @@
+package dbmanager
+
+import (
+	"context"
+	"fmt"
+	"neobase-ai/internal/apis/dtos"
+)
+
+// RedisTransaction implements the Transaction interface for Redis
+// Redis has no rollback, multi-command queries are executed atomically with MULTI/EXEC inside ExecuteQuery and
+// Rollback reports that the executed commands are still applied
+type RedisTransaction struct {
+	Wrapper *RedisWrapper
+	Error   error
+}
+
+// ExecuteQuery executes a Redis query within the transaction
+func (tx *RedisTransaction) ExecuteQuery(ctx context.Context, query string) (*QueryExecutionResult, error) {
+	if tx.Error != nil {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: tx.Error.Error(),
+				Code:    "TRANSACTION_ERROR",
+			},
+		}, nil
+	}
+
+	if tx.Wrapper == nil || tx.Wrapper.Client == nil {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: "No active Redis connection",
+				Code:    "TRANSACTION_ERROR",
+			},
+		}, nil
+	}
+
+	return executeRedisCommands(ctx, tx.Wrapper.Client, query, false), nil
+}
+
+// Commit commits the Redis transaction, commands are already applied by EXEC
+func (tx *RedisTransaction) Commit() error {
+	if tx.Error != nil {
+		return fmt.Errorf("cannot commit transaction: %v", tx.Error)
+	}
+	return nil
+}
+
+// Rollback fails, Redis can't undo executed commands. Queued MULTI/EXEC commands are never sent when a query fails to
+// parse, so only the commands that already ran are left in place.
+func (tx *RedisTransaction) Rollback() error {
+	return fmt.Errorf("rollback not supported for Redis, executed commands stay applied")
+}
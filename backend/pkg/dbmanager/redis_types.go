@@ -0,0 +1,43 @@
+package dbmanager
+
+import (
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisWrapper wraps a Redis client
+type RedisWrapper struct {
+	Client   *redis.Client
+	Database int // Logical database index (SELECT n)
+}
+
+// RedisSchema represents the inferred schema of a Redis database
+type RedisSchema struct {
+	KeyPatterns map[string]RedisKeyPattern
+	UpdatedAt   time.Time
+}
+
+// RedisKeyPattern represents a group of keys sharing the same pattern (e.g. user:*), treated as a table
+type RedisKeyPattern struct {
+	Pattern    string
+	Type       string // string, hash, list, set, zset, stream
+	KeyCount   int64  // Number of keys matched while sampling
+	SampleKeys []string
+	Fields     map[string]RedisField
+	HasTTL     bool
+}
+
+// RedisField represents a field inferred from the values stored under a key pattern
+type RedisField struct {
+	Name      string
+	Type      string
+	Frequency float64 // Percentage of sampled keys containing this field
+}
+
+// Redis schema sampling limits
+const (
+	redisScanBatchSize        = 1000  // COUNT hint passed to SCAN
+	redisMaxScannedKeys       = 10000 // Max keys scanned while inferring patterns
+	redisSampleKeysPerPattern = 5     // Keys inspected per pattern to infer fields
+)
@@ -0,0 +1,295 @@
+package dbmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	redisNumericSegment = regexp.MustCompile(`^-?\d+$`)
+	redisUUIDSegment    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	redisHexSegment     = regexp.MustCompile(`^[0-9a-fA-F]{16,}$`)
+	redisDateSegment    = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}`)
+)
+
+// redisWriteCommands lists commands that modify data, used to shape results and detect critical commands
+var redisWriteCommands = map[string]bool{
+	"SET": true, "SETNX": true, "SETEX": true, "PSETEX": true, "MSET": true, "MSETNX": true, "GETSET": true, "GETDEL": true, "GETEX": true,
+	"APPEND": true, "INCR": true, "INCRBY": true, "INCRBYFLOAT": true, "DECR": true, "DECRBY": true, "SETRANGE": true,
+	"DEL": true, "UNLINK": true, "EXPIRE": true, "PEXPIRE": true, "EXPIREAT": true, "PEXPIREAT": true, "PERSIST": true,
+	"RENAME": true, "RENAMENX": true, "COPY": true, "MOVE": true, "RESTORE": true,
+	"HSET": true, "HSETNX": true, "HMSET": true, "HDEL": true, "HINCRBY": true, "HINCRBYFLOAT": true,
+	"LPUSH": true, "LPUSHX": true, "RPUSH": true, "RPUSHX": true, "LPOP": true, "RPOP": true, "LSET": true, "LREM": true,
+	"LINSERT": true, "LTRIM": true, "LMOVE": true, "RPOPLPUSH": true, "LMPOP": true,
+	"SADD": true, "SREM": true, "SPOP": true, "SMOVE": true, "SINTERSTORE": true, "SUNIONSTORE": true, "SDIFFSTORE": true,
+	"ZADD": true, "ZREM": true, "ZINCRBY": true, "ZPOPMIN": true, "ZPOPMAX": true, "ZREMRANGEBYRANK": true, "ZREMRANGEBYSCORE": true,
+	"ZREMRANGEBYLEX": true, "ZUNIONSTORE": true, "ZINTERSTORE": true, "ZDIFFSTORE": true, "ZRANGESTORE": true, "ZMPOP": true,
+	"XADD": true, "XDEL": true, "XTRIM": true, "XGROUP": true, "XACK": true, "XCLAIM": true, "XAUTOCLAIM": true,
+	"PFADD": true, "PFMERGE": true, "SETBIT": true, "BITOP": true, "BITFIELD": true, "GEOADD": true,
+	"JSON.SET": true, "JSON.DEL": true, "JSON.MERGE": true, "JSON.ARRAPPEND": true, "JSON.NUMINCRBY": true,
+	"FLUSHDB": true, "FLUSHALL": true, "PUBLISH": true,
+}
+
+// redisBlockedCommands lists commands that block, stream indefinitely or change connection/server state,
+// these can't be executed safely on a pooled connection
+var redisBlockedCommands = map[string]bool{
+	"SUBSCRIBE": true, "PSUBSCRIBE": true, "SSUBSCRIBE": true, "UNSUBSCRIBE": true, "PUNSUBSCRIBE": true,
+	"MONITOR": true, "SYNC": true, "PSYNC": true, "SELECT": true, "QUIT": true, "RESET": true, "AUTH": true, "HELLO": true,
+	"BLPOP": true, "BRPOP": true, "BLMOVE": true, "BRPOPLPUSH": true, "BLMPOP": true, "BZPOPMIN": true, "BZPOPMAX": true, "BZMPOP": true,
+	"SHUTDOWN": true, "DEBUG": true, "REPLICAOF": true, "SLAVEOF": true, "FAILOVER": true, "WAIT": true, "WAITAOF": true,
+}
+
+// redisFullScanCommands walk the whole keyspace in one call and stall the server on large databases, SCAN iterates
+// it instead
+var redisFullScanCommands = map[string]bool{
+	"KEYS": true,
+}
+
+// parseRedisCommand tokenizes a single Redis command line, honouring single/double quotes and escapes
+func parseRedisCommand(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inToken := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == '\\' && quote == '"' && i+1 < len(runes) {
+				// Handle escape sequences inside double quotes
+				i++
+				switch runes[i] {
+				case 'n':
+					current.WriteRune('\n')
+				case 't':
+					current.WriteRune('\t')
+				case 'r':
+					current.WriteRune('\r')
+				default:
+					current.WriteRune(runes[i])
+				}
+			} else if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inToken = true
+		case r == ' ' || r == '\t':
+			if inToken {
+				args = append(args, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in command: %s", line)
+	}
+	if inToken {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// splitRedisCommands splits a query into individual commands, separated by new lines or semicolons outside quotes
+func splitRedisCommands(query string) []string {
+	var commands []string
+	var current strings.Builder
+	var quote rune
+	escaped := false
+
+	flush := func() {
+		cmd := strings.TrimSpace(current.String())
+		if cmd != "" {
+			commands = append(commands, cmd)
+		}
+		current.Reset()
+	}
+
+	for _, r := range query {
+		if escaped {
+			current.WriteRune(r)
+			escaped = false
+			continue
+		}
+		switch {
+		case r == '\\' && quote == '"':
+			current.WriteRune(r)
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			current.WriteRune(r)
+		case r == '\n' || r == ';':
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return commands
+}
+
+// toRedisArgs converts string tokens to the []interface{} expected by go-redis Do
+func toRedisArgs(tokens []string) []interface{} {
+	args := make([]interface{}, len(tokens))
+	for i, token := range tokens {
+		args[i] = token
+	}
+	return args
+}
+
+// inferRedisKeyPattern replaces variable segments (ids, uuids, hashes) of a key with * to group similar keys
+func inferRedisKeyPattern(key string) string {
+	segments := strings.Split(key, ":")
+	if len(segments) == 1 {
+		if redisNumericSegment.MatchString(key) || redisUUIDSegment.MatchString(key) || redisHexSegment.MatchString(key) {
+			return "*"
+		}
+		return key
+	}
+
+	for i, segment := range segments {
+		if redisNumericSegment.MatchString(segment) ||
+			redisUUIDSegment.MatchString(segment) ||
+			redisHexSegment.MatchString(segment) ||
+			redisDateSegment.MatchString(segment) ||
+			strings.Contains(segment, "@") {
+			segments[i] = "*"
+		}
+	}
+	return strings.Join(segments, ":")
+}
+
+// inferRedisValueType infers a simple type name for a stored Redis value
+func inferRedisValueType(value string) string {
+	if value == "" {
+		return "string"
+	}
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return "integer"
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return "float"
+	}
+	if value == "true" || value == "false" {
+		return "boolean"
+	}
+	trimmed := strings.TrimSpace(value)
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		return "json"
+	}
+	return "string"
+}
+
+// normalizeRedisValue converts go-redis replies (including RESP3 maps) into JSON friendly values
+func normalizeRedisValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			normalized[fmt.Sprintf("%v", key)] = normalizeRedisValue(val)
+		}
+		return normalized
+	case map[string]interface{}:
+		for key, val := range v {
+			v[key] = normalizeRedisValue(val)
+		}
+		return v
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, val := range v {
+			normalized[i] = normalizeRedisValue(val)
+		}
+		return normalized
+	case []byte:
+		return string(v)
+	case error:
+		return v.Error()
+	default:
+		return v
+	}
+}
+
+// pairRedisArray converts a flat RESP2 [field, value, field, value] reply into a map
+func pairRedisArray(values []interface{}) (map[string]interface{}, bool) {
+	if len(values)%2 != 0 {
+		return nil, false
+	}
+	paired := make(map[string]interface{}, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		key, ok := values[i].(string)
+		if !ok {
+			return nil, false
+		}
+		paired[key] = values[i+1]
+	}
+	return paired, true
+}
+
+// formatRedisRows converts a Redis reply into rows so that it can be rendered like a table
+func formatRedisRows(command string, reply interface{}, findCount bool) []map[string]interface{} {
+	reply = normalizeRedisValue(reply)
+
+	// Field/value replies come back as flat arrays on RESP2 servers
+	if values, ok := reply.([]interface{}); ok && (command == "HGETALL" || command == "CONFIG") {
+		if paired, ok := pairRedisArray(values); ok {
+			reply = paired
+		}
+	}
+
+	switch v := reply.(type) {
+	case nil:
+		return []map[string]interface{}{}
+	case map[string]interface{}:
+		// A hash is a single record with its fields as columns
+		return []map[string]interface{}{v}
+	case []interface{}:
+		rows := make([]map[string]interface{}, 0, len(v))
+		for i, item := range v {
+			if itemMap, ok := item.(map[string]interface{}); ok {
+				rows = append(rows, itemMap)
+				continue
+			}
+			rows = append(rows, map[string]interface{}{
+				"index": i,
+				"value": item,
+			})
+		}
+		return rows
+	case int64:
+		if findCount {
+			return []map[string]interface{}{{"count": v}}
+		}
+		return []map[string]interface{}{{"value": v}}
+	default:
+		return []map[string]interface{}{{"value": v}}
+	}
+}
+
+// sortedRedisFieldNames returns field names sorted alphabetically for stable output
+func sortedRedisFieldNames(fields map[string]RedisField) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
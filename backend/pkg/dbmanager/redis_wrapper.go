@@ -0,0 +1,102 @@
+package dbmanager
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisExecutor implements the DBExecutor interface for Redis
+type RedisExecutor struct {
+	wrapper *RedisWrapper
+	conn    *Connection
+}
+
+// NewRedisExecutor creates a new Redis executor
+func NewRedisExecutor(conn *Connection) (*RedisExecutor, error) {
+	wrapper, ok := conn.MongoDBObj.(*RedisWrapper)
+	if !ok || wrapper == nil {
+		return nil, fmt.Errorf("invalid Redis connection")
+	}
+
+	return &RedisExecutor{
+		wrapper: wrapper,
+		conn:    conn,
+	}, nil
+}
+
+// GetDB returns nil for Redis as it doesn't use sql.DB
+func (e *RedisExecutor) GetDB() *sql.DB {
+	return nil
+}
+
+// GetClient returns the underlying Redis client
+func (e *RedisExecutor) GetClient() *redis.Client {
+	return e.wrapper.Client
+}
+
+// Close closes the Redis executor
+func (e *RedisExecutor) Close() error {
+	// Connection is managed by the Redis driver
+	return nil
+}
+
+// Exec executes a Redis command, *Not Used By DBManager*
+func (e *RedisExecutor) Exec(command string, values ...interface{}) error {
+	log.Printf("RedisExecutor -> Exec -> Command: %s", command)
+
+	result := executeRedisCommands(context.Background(), e.wrapper.Client, command, false)
+	if result.Error != nil {
+		return fmt.Errorf("failed to execute Redis command: %v", result.Error.Message)
+	}
+	return nil
+}
+
+// Raw executes a raw Redis command, *Not Used By DBManager*
+func (e *RedisExecutor) Raw(command string, values ...interface{}) error {
+	return e.Exec(command, values...)
+}
+
+// Query executes a Redis command and scans the result into dest
+func (e *RedisExecutor) Query(query string, dest interface{}, values ...interface{}) error {
+	destMap, ok := dest.(*[]map[string]interface{})
+	if !ok {
+		return fmt.Errorf("destination must be *[]map[string]interface{}")
+	}
+	return e.QueryRows(query, destMap, values...)
+}
+
+// QueryRows executes a Redis command and returns the reply as rows
+func (e *RedisExecutor) QueryRows(query string, dest *[]map[string]interface{}, values ...interface{}) error {
+	result := executeRedisCommands(context.Background(), e.wrapper.Client, query, false)
+	if result.Error != nil {
+		return fmt.Errorf("failed to execute Redis command: %v", result.Error.Message)
+	}
+
+	resultMap, ok := result.Result.(map[string]interface{})
+	if !ok {
+		*dest = []map[string]interface{}{}
+		return nil
+	}
+	if rows, ok := resultMap["results"].([]map[string]interface{}); ok {
+		*dest = rows
+	} else {
+		*dest = []map[string]interface{}{resultMap}
+	}
+	return nil
+}
+
+// GetSchema fetches the Redis key pattern schema
+func (e *RedisExecutor) GetSchema(ctx context.Context) (*SchemaInfo, error) {
+	driver := &RedisDriver{}
+	return driver.GetSchema(ctx, e, []string{"ALL"})
+}
+
+// GetTableChecksum calculates a checksum for a Redis key pattern
+func (e *RedisExecutor) GetTableChecksum(ctx context.Context, table string) (string, error) {
+	driver := &RedisDriver{}
+	return driver.GetTableChecksum(ctx, e, table)
+}
@@ -0,0 +1,406 @@
+package dbmanager
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"neobase-ai/internal/constants"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ResultCursor reads the rows of a query in batches, so large results are never held in memory as a whole
+type ResultCursor interface {
+	// Columns returns the column names, empty for document databases
+	Columns() []string
+	// Next returns up to batchSize rows, an empty batch once every row was read
+	Next(ctx context.Context, batchSize int) ([]map[string]interface{}, error)
+	// Close releases the query, e.g. rolls back the transaction it runs in
+	Close() error
+}
+
+// ResultLimits caps the rows read by StreamQuery
+type ResultLimits struct {
+	BatchSize   int   // Rows per batch
+	MaxRows     int64 // 0 for no cap
+	MaxBytes    int64 // Size of the rows as JSON, 0 for no cap
+	PreviewRows int   // Rows kept in the preview of the result
+}
+
+// Reasons a streamed result was truncated
+const (
+	ResultTruncatedByRows  = "max_rows"
+	ResultTruncatedByBytes = "max_bytes"
+)
+
+// StreamedResult is what StreamQuery keeps of a result, the rows themselves are only passed to its callback
+type StreamedResult struct {
+	Columns       []string
+	Preview       []map[string]interface{}
+	RowCount      int64
+	ByteCount     int64
+	Truncated     bool
+	TruncatedBy   string // ResultTruncatedByRows or ResultTruncatedByBytes
+	ExecutionTime int
+}
+
+// CanStreamQuery reports whether a query is a single statement that only reads, so it can be run with StreamQuery
+func CanStreamQuery(dbType string, query string) bool {
+	query = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+	switch dbType {
+	case constants.DatabaseTypeMongoDB:
+		return CheckMongoReadOnlyQuery(query) == nil
+	case constants.DatabaseTypeSpreadsheet:
+		// Spreadsheets are stored in PostgreSQL
+		dbType = constants.DatabaseTypePostgreSQL
+	case constants.DatabaseTypeSQLite, constants.DatabaseTypeDuckDB, constants.DatabaseTypeMSSQL:
+		// The analyzer doesn't model these dialects, e.g. T-SQL runs statements without semicolons, and their drivers
+		// have no read-only transactions, so only the queries without any write keyword are streamed
+		return CheckReadOnlyQuery(dbType, query) == nil
+	}
+
+	analysis, ok := AnalyzeSQL(dbType, query)
+	return ok && len(analysis.Statements) == 1 && analysis.Kind == StatementKindRead
+}
+
+// StreamQuery runs a read query and passes its rows to onBatch batch by batch, until every row was read or a limit
+// of limits was reached. Only the first limits.PreviewRows rows are kept in the returned result.
+func (m *Manager) StreamQuery(ctx context.Context, chatID, messageID, queryID, streamID string, query string, limits ResultLimits, onBatch func(columns []string, batch []map[string]interface{}) error) (*StreamedResult, *dtos.QueryError) {
+	startTime := time.Now()
+	m.mu.RLock()
+	conn, exists := m.connections[chatID]
+	m.mu.RUnlock()
+	if !exists {
+		return nil, &dtos.QueryError{
+			Code:    "NO_CONNECTION_FOUND",
+			Message: "no connection found",
+			Details: "No connection found for chat ID: " + chatID,
+		}
+	}
+
+	driver, exists := m.drivers[conn.Config.Type]
+	if !exists {
+		return nil, &dtos.QueryError{
+			Code:    "NO_DRIVER_FOUND",
+			Message: "no driver found",
+			Details: "No driver found for type: " + conn.Config.Type,
+		}
+	}
+	if !CanStreamQuery(conn.Config.Type, query) {
+		return nil, &dtos.QueryError{
+			Code:    "STREAMING_NOT_SUPPORTED",
+			Message: "only single read queries can be streamed",
+			Details: "Run the query with ExecuteQuery instead",
+		}
+	}
+	// Read-only chats only run queries that read data, like in ExecuteQuery
+	if conn.Config.ReadOnly {
+		if err := checkReadOnlyMode(conn.Config.Type, query); err != nil {
+			log.Printf("Manager -> StreamQuery -> Rejected query on read-only connection: %v", err)
+			return nil, &dtos.QueryError{
+				Code:    "READ_ONLY_MODE",
+				Message: "writes are not allowed in read-only mode",
+				Details: err.Error(),
+			}
+		}
+	}
+
+	// Tracked like ExecuteQuery so CancelQueryExecution stops it
+	execCtx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	m.executionMu.Lock()
+	m.activeExecutions[streamID] = &QueryExecution{
+		QueryID:     queryID,
+		MessageID:   messageID,
+		StartTime:   startTime,
+		IsExecuting: true,
+		CancelFunc:  cancel,
+	}
+	m.executionMu.Unlock()
+	defer func() {
+		m.executionMu.Lock()
+		delete(m.activeExecutions, streamID)
+		m.executionMu.Unlock()
+		cancel()
+	}()
+
+	cursor, err := driver.StreamQuery(execCtx, conn, query)
+	if err != nil {
+		log.Printf("Manager -> StreamQuery -> Error running query for chat %s: %v", chatID, err)
+		return nil, streamQueryError(execCtx, err)
+	}
+	defer func() {
+		if err := cursor.Close(); err != nil {
+			log.Printf("Manager -> StreamQuery -> Error closing cursor: %v", err)
+		}
+	}()
+
+	result := &StreamedResult{Columns: cursor.Columns(), Preview: []map[string]interface{}{}}
+	batchSize := limits.BatchSize
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	for !result.Truncated {
+		rows, err := cursor.Next(execCtx, batchSize)
+		if err != nil {
+			log.Printf("Manager -> StreamQuery -> Error reading rows for chat %s: %v", chatID, err)
+			return nil, streamQueryError(execCtx, err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		batch := make([]map[string]interface{}, 0, len(rows))
+		for _, row := range rows {
+			if limits.MaxRows > 0 && result.RowCount >= limits.MaxRows {
+				result.Truncated, result.TruncatedBy = true, ResultTruncatedByRows
+				break
+			}
+			rowJSON, err := json.Marshal(row)
+			if err != nil {
+				return nil, &dtos.QueryError{
+					Code:    "RESULT_PROCESSING_FAILED",
+					Message: err.Error(),
+					Details: "Failed to process query results",
+				}
+			}
+			if limits.MaxBytes > 0 && result.ByteCount+int64(len(rowJSON)) > limits.MaxBytes {
+				result.Truncated, result.TruncatedBy = true, ResultTruncatedByBytes
+				break
+			}
+			result.RowCount++
+			result.ByteCount += int64(len(rowJSON))
+			if len(result.Preview) < limits.PreviewRows {
+				result.Preview = append(result.Preview, row)
+			}
+			batch = append(batch, row)
+		}
+
+		if len(batch) > 0 && onBatch != nil {
+			if err := onBatch(result.Columns, batch); err != nil {
+				return nil, &dtos.QueryError{
+					Code:    "RESULT_STREAMING_FAILED",
+					Message: err.Error(),
+					Details: "Failed to send query results",
+				}
+			}
+		}
+	}
+
+	result.ExecutionTime = int(time.Since(startTime).Milliseconds())
+	return result, nil
+}
+
+// streamQueryError converts an error of a cursor, telling timeouts and cancellations apart like ExecuteQuery
+func streamQueryError(ctx context.Context, err error) *dtos.QueryError {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return &dtos.QueryError{
+			Code:    "QUERY_EXECUTION_TIMED_OUT",
+			Message: "query execution timed out",
+			Details: "Query execution timed out",
+		}
+	case context.Canceled:
+		return &dtos.QueryError{
+			Code:    "QUERY_EXECUTION_CANCELLED",
+			Message: "query execution cancelled",
+			Details: "Query execution cancelled",
+		}
+	}
+	return &dtos.QueryError{
+		Code:    "QUERY_EXECUTION_FAILED",
+		Message: err.Error(),
+		Details: "Query execution failed",
+	}
+}
+
+// sqlRowsCursor reads the rows of a database/sql query
+type sqlRowsCursor struct {
+	rows        *sql.Rows
+	columnTypes []*sql.ColumnType
+	normalize   func(columnType *sql.ColumnType, value interface{}) interface{}
+	release     func() error // Releases what the query runs on, may be nil
+}
+
+// newSQLRowsCursor returns a cursor over rows, normalize converts the scanned values into JSON friendly types and
+// release is called once the rows are closed
+func newSQLRowsCursor(rows *sql.Rows, normalize func(columnType *sql.ColumnType, value interface{}) interface{}, release func() error) (ResultCursor, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		rows.Close()
+		if release != nil {
+			release()
+		}
+		return nil, fmt.Errorf("failed to get columns: %v", err)
+	}
+	if normalize == nil {
+		normalize = normalizeSQLValue
+	}
+	return &sqlRowsCursor{rows: rows, columnTypes: columnTypes, normalize: normalize, release: release}, nil
+}
+
+func (c *sqlRowsCursor) Columns() []string {
+	columns := make([]string, len(c.columnTypes))
+	for i, columnType := range c.columnTypes {
+		columns[i] = columnType.Name()
+	}
+	return columns
+}
+
+func (c *sqlRowsCursor) Next(ctx context.Context, batchSize int) ([]map[string]interface{}, error) {
+	batch := make([]map[string]interface{}, 0, batchSize)
+	for len(batch) < batchSize && c.rows.Next() {
+		values := make([]interface{}, len(c.columnTypes))
+		scanArgs := make([]interface{}, len(c.columnTypes))
+		for i := range values {
+			scanArgs[i] = &values[i]
+		}
+		if err := c.rows.Scan(scanArgs...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+
+		row := make(map[string]interface{}, len(c.columnTypes))
+		for i, columnType := range c.columnTypes {
+			row[columnType.Name()] = c.normalize(columnType, values[i])
+		}
+		batch = append(batch, row)
+	}
+	if err := c.rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %v", err)
+	}
+	return batch, nil
+}
+
+func (c *sqlRowsCursor) Close() error {
+	err := c.rows.Close()
+	if c.release != nil {
+		if releaseErr := c.release(); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}
+	return err
+}
+
+// normalizeSQLValue converts a scanned value like processRows does
+func normalizeSQLValue(columnType *sql.ColumnType, value interface{}) interface{} {
+	if raw, ok := value.([]byte); ok {
+		return string(raw)
+	}
+	return value
+}
+
+// streamSQLQuery runs a query in a transaction that is rolled back once the cursor is closed. The setup statements
+// run in the transaction first, e.g. SET LOCAL search_path.
+func streamSQLQuery(ctx context.Context, conn *Connection, query string, opts *sql.TxOptions, normalize func(columnType *sql.ColumnType, value interface{}) interface{}, setup ...string) (ResultCursor, error) {
+	if conn == nil || conn.DB == nil {
+		return nil, fmt.Errorf("connection is not initialized")
+	}
+	sqlDB, err := conn.DB.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SQL connection: %v", err)
+	}
+
+	tx, err := sqlDB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	for _, statement := range setup {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return newSQLRowsCursor(rows, normalize, tx.Rollback)
+}
+
+// bufferedCursor serves the rows of a result that a driver can only return as a whole
+type bufferedCursor struct {
+	columns []string
+	rows    []map[string]interface{}
+	offset  int
+}
+
+// newBufferedCursor returns a cursor over the rows of an ExecuteQuery result: the "results" list, or the result
+// itself as a single row when it isn't a list
+func newBufferedCursor(result *QueryExecutionResult) (ResultCursor, error) {
+	rows, err := ResultRows(result)
+	if err != nil {
+		return nil, err
+	}
+	return &bufferedCursor{rows: rows}, nil
+}
+
+// ResultRows returns the rows of the result of ExecuteQuery, whatever the driver put in "results"
+func ResultRows(result *QueryExecutionResult) ([]map[string]interface{}, error) {
+	if result == nil {
+		return nil, fmt.Errorf("query returned no result")
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("%s", result.Error.Message)
+	}
+
+	var rows []map[string]interface{}
+	resultMap, ok := result.Result.(map[string]interface{})
+	var resultsValue interface{} = resultMap["results"]
+	if !ok {
+		// Some drivers return the list itself
+		resultsValue = result.Result
+	}
+	switch results := resultsValue.(type) {
+	case []map[string]interface{}:
+		rows = results
+	case []interface{}:
+		for _, item := range results {
+			switch row := item.(type) {
+			case map[string]interface{}:
+				rows = append(rows, row)
+			case bson.M:
+				rows = append(rows, row)
+			default:
+				rows = append(rows, map[string]interface{}{"value": item})
+			}
+		}
+	default:
+		if results != nil {
+			// Lists of driver specific types, e.g. []bson.M, are converted through JSON
+			resultsJSON, err := json.Marshal(results)
+			if err != nil {
+				return nil, fmt.Errorf("failed to process query results: %v", err)
+			}
+			if err := json.Unmarshal(resultsJSON, &rows); err != nil {
+				rows = []map[string]interface{}{{"value": results}}
+			}
+		} else if resultMap != nil {
+			rows = []map[string]interface{}{resultMap}
+		}
+	}
+	return rows, nil
+}
+
+func (c *bufferedCursor) Columns() []string {
+	return c.columns
+}
+
+func (c *bufferedCursor) Next(ctx context.Context, batchSize int) ([]map[string]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	end := min(c.offset+batchSize, len(c.rows))
+	batch := c.rows[c.offset:end]
+	c.offset = end
+	return batch, nil
+}
+
+func (c *bufferedCursor) Close() error {
+	c.rows = nil
+	return nil
+}
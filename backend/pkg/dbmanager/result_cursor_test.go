@@ -0,0 +1,44 @@
+package dbmanager
+
+import (
+	"neobase-ai/internal/constants"
+	"testing"
+)
+
+func TestCanStreamQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		dbType string
+		query  string
+		want   bool
+	}{
+		{"postgres select", constants.DatabaseTypePostgreSQL, "SELECT * FROM users ORDER BY id;", true},
+		{"postgres with", constants.DatabaseTypePostgreSQL, "WITH t AS (SELECT 1 AS a) SELECT a FROM t", true},
+		{"postgres several reads", constants.DatabaseTypePostgreSQL, "SELECT 1; SELECT 2", false},
+		{"postgres write", constants.DatabaseTypePostgreSQL, "DELETE FROM users WHERE id = 1", false},
+		{"postgres explain analyze", constants.DatabaseTypePostgreSQL, "EXPLAIN ANALYZE DELETE FROM users", false},
+		{"mysql select", constants.DatabaseTypeMySQL, "SELECT `name` FROM `users` LIMIT 10", true},
+		{"mysql select into outfile", constants.DatabaseTypeMySQL, "SELECT * FROM users INTO OUTFILE '/tmp/u'", false},
+		{"clickhouse select", constants.DatabaseTypeClickhouse, "SELECT count() FROM events", true},
+		{"spreadsheet select", constants.DatabaseTypeSpreadsheet, "SELECT * FROM sheet1", true},
+		{"spreadsheet write", constants.DatabaseTypeSpreadsheet, "UPDATE sheet1 SET a = 1", false},
+		{"mssql select", constants.DatabaseTypeMSSQL, "SELECT TOP 10 * FROM users", true},
+		{"mssql batch without semicolons", constants.DatabaseTypeMSSQL, "SELECT 1 DELETE FROM users", false},
+		{"sqlite select", constants.DatabaseTypeSQLite, "SELECT * FROM users", true},
+		{"sqlite several statements", constants.DatabaseTypeSQLite, "SELECT 1; DROP TABLE users", false},
+		{"duckdb select", constants.DatabaseTypeDuckDB, "SELECT * FROM 'data.parquet'", true},
+		{"duckdb copy", constants.DatabaseTypeDuckDB, "COPY users TO 'users.csv'", false},
+		{"mongodb find", constants.DatabaseTypeMongoDB, "db.users.find({})", true},
+		{"mongodb write", constants.DatabaseTypeMongoDB, "db.users.insertOne({})", false},
+		{"redis", constants.DatabaseTypeRedis, "GET user:1", false},
+		{"neo4j", constants.DatabaseTypeNeo4j, "MATCH (n) RETURN n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanStreamQuery(tt.dbType, tt.query); got != tt.want {
+				t.Errorf("CanStreamQuery(%s, %q) = %v, want %v", tt.dbType, tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,458 @@
+package dbmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Weights of the places a term is found in, a match on the table name counts more than one in an example value
+const (
+	tableNameTermWeight    = 3.0
+	columnNameTermWeight   = 2.0
+	commentTermWeight      = 1.0
+	exampleValueTermWeight = 0.5
+
+	// Example values longer than this are free text rather than identifiers worth indexing
+	maxIndexedExampleValueLength = 64
+
+	// BM25 parameters
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Words that say nothing about which tables a question is about
+var retrievalStopWords = map[string]bool{
+	"a": true, "about": true, "all": true, "an": true, "and": true, "are": true, "as": true, "at": true, "by": true,
+	"can": true, "do": true, "doe": true, "each": true, "for": true, "from": true, "get": true, "give": true, "have": true,
+	"how": true, "i": true, "in": true, "is": true, "it": true, "list": true, "many": true, "me": true, "much": true,
+	"my": true, "of": true, "on": true, "or": true, "our": true, "per": true, "please": true, "show": true, "that": true,
+	"the": true, "their": true, "there": true, "thi": true, "to": true, "top": true, "wa": true, "we": true, "what": true,
+	"when": true, "where": true, "which": true, "who": true, "with": true, "you": true,
+}
+
+// SchemaRetrievalIndex ranks the tables of a stored schema against questions. It is cached in the SchemaStorage and
+// rebuilt when the schema is refreshed.
+type SchemaRetrievalIndex struct {
+	SchemaUpdatedAt time.Time                     `json:"schema_updated_at"` // UpdatedAt of the schema it was built from
+	Terms           map[string]map[string]float64 `json:"terms"`             // Weighted term frequencies per table
+	EmbeddingModel  string                        `json:"embedding_model,omitempty"`
+	Embeddings      map[string][]float32          `json:"embeddings,omitempty"` // Embedding of each table's description
+}
+
+// EmbedFunc returns the embedding of each text
+type EmbedFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// SchemaRetrievalOptions configures FormatRelevantSchema
+type SchemaRetrievalOptions struct {
+	TopK           int       // Tables picked by relevance, their related tables are added on top
+	MinTables      int       // Schemas with fewer tables are sent in full
+	Embed          EmbedFunc // Optional, ranks tables by semantic similarity as well
+	EmbeddingModel string
+}
+
+// FormatRelevantSchema formats only the stored tables relevant to the question and their related tables, listing
+// the names of the other tables. It returns ok false when the full schema should be used instead, because the schema
+// is small or nothing in it matches the question.
+func (sm *SchemaManager) FormatRelevantSchema(ctx context.Context, chatID string, question string, opts SchemaRetrievalOptions) (string, bool, error) {
+	storage, err := sm.getStoredSchema(ctx, chatID)
+	if err != nil {
+		return "", false, err
+	}
+	if storage.LLMSchema == nil || len(storage.LLMSchema.Tables) < opts.MinTables || len(storage.LLMSchema.Tables) <= opts.TopK {
+		return "", false, nil
+	}
+
+	index := sm.getRetrievalIndex(ctx, chatID, storage, opts)
+
+	scores := index.lexicalScores(tokenizeForRetrieval(question))
+	if opts.Embed != nil && len(index.Embeddings) > 0 && index.EmbeddingModel == opts.EmbeddingModel {
+		embeddings, err := opts.Embed(ctx, []string{question})
+		if err != nil || len(embeddings) == 0 {
+			log.Printf("FormatRelevantSchema -> Error embedding question, using lexical ranking only: %v", err)
+		} else {
+			scores = combineRetrievalScores(scores, index.similarities(embeddings[0]))
+		}
+	}
+
+	selected := selectRelevantTables(storage, scores, opts.TopK)
+	if len(selected) == 0 {
+		log.Printf("FormatRelevantSchema -> No table matches the question for chatID %s, using the full schema", chatID)
+		return "", false, nil
+	}
+	log.Printf("FormatRelevantSchema -> Selected %d of %d tables for chatID %s", len(selected), len(storage.LLMSchema.Tables), chatID)
+
+	var result strings.Builder
+	result.WriteString("Only the tables relevant to the current question are detailed below.\n\n")
+	result.WriteString(sm.FormatSchemaForLLMWithExamples(filterSchemaStorage(storage, selected)))
+
+	otherTables := make([]string, 0, len(storage.LLMSchema.Tables)-len(selected))
+	for tableName := range storage.LLMSchema.Tables {
+		if !selected[tableName] {
+			otherTables = append(otherTables, tableName)
+		}
+	}
+	sort.Strings(otherTables)
+	result.WriteString(fmt.Sprintf("Other tables (columns not shown): %s\n", strings.Join(otherTables, ", ")))
+	return result.String(), true, nil
+}
+
+// getRetrievalIndex returns the index cached with the schema, rebuilding and storing it when it is missing or stale
+func (sm *SchemaManager) getRetrievalIndex(ctx context.Context, chatID string, storage *SchemaStorage, opts SchemaRetrievalOptions) *SchemaRetrievalIndex {
+	index := storage.RetrievalIndex
+	changed := false
+	if index == nil || !index.SchemaUpdatedAt.Equal(storage.UpdatedAt) {
+		index = buildRetrievalIndex(storage)
+		changed = true
+	}
+
+	if opts.Embed != nil && opts.EmbeddingModel != "" && index.EmbeddingModel != opts.EmbeddingModel {
+		tableNames := make([]string, 0, len(storage.LLMSchema.Tables))
+		texts := make([]string, 0, len(storage.LLMSchema.Tables))
+		for tableName, table := range storage.LLMSchema.Tables {
+			tableNames = append(tableNames, tableName)
+			texts = append(texts, describeTableForEmbedding(table))
+		}
+
+		embeddings, err := opts.Embed(ctx, texts)
+		if err == nil && len(embeddings) != len(texts) {
+			err = fmt.Errorf("got %d embeddings for %d tables", len(embeddings), len(texts))
+		}
+		if err != nil {
+			log.Printf("getRetrievalIndex -> Error embedding tables for chatID %s: %v", chatID, err)
+		} else {
+			index.Embeddings = make(map[string][]float32, len(tableNames))
+			for i, tableName := range tableNames {
+				index.Embeddings[tableName] = embeddings[i]
+			}
+			index.EmbeddingModel = opts.EmbeddingModel
+			changed = true
+		}
+	}
+
+	if changed {
+		// Skip storing if the schema was refreshed meanwhile, the next question rebuilds the index for it
+		latest, err := sm.getStoredSchema(ctx, chatID)
+		if err == nil && latest.UpdatedAt.Equal(storage.UpdatedAt) {
+			storage.RetrievalIndex = index
+			if err := sm.storageService.Store(ctx, chatID, storage); err != nil {
+				log.Printf("getRetrievalIndex -> Error storing retrieval index for chatID %s: %v", chatID, err)
+			}
+		}
+	}
+	return index
+}
+
+// buildRetrievalIndex collects the weighted terms of every table's name, columns, comments and example values
+func buildRetrievalIndex(storage *SchemaStorage) *SchemaRetrievalIndex {
+	index := &SchemaRetrievalIndex{
+		SchemaUpdatedAt: storage.UpdatedAt,
+		Terms:           make(map[string]map[string]float64, len(storage.LLMSchema.Tables)),
+	}
+
+	for tableName, table := range storage.LLMSchema.Tables {
+		terms := make(map[string]float64)
+		addTerms := func(text string, weight float64) {
+			for _, term := range tokenizeForRetrieval(text) {
+				terms[term] += weight
+			}
+		}
+
+		addTerms(tableName, tableNameTermWeight)
+		addTerms(table.Description, commentTermWeight)
+		for _, column := range table.Columns {
+			addTerms(column.Name, columnNameTermWeight)
+			addTerms(column.Description, commentTermWeight)
+		}
+		if fullTable, ok := storage.FullSchema.Tables[tableName]; ok {
+			addTerms(fullTable.Comment, commentTermWeight)
+		}
+		for _, record := range table.ExampleRecords {
+			for _, value := range record {
+				if text, ok := value.(string); ok && len(text) <= maxIndexedExampleValueLength {
+					addTerms(text, exampleValueTermWeight)
+				}
+			}
+		}
+		index.Terms[tableName] = terms
+	}
+	return index
+}
+
+// lexicalScores scores every table against the question terms with BM25 over the weighted term frequencies
+func (idx *SchemaRetrievalIndex) lexicalScores(queryTerms []string) map[string]float64 {
+	scores := make(map[string]float64)
+	if len(idx.Terms) == 0 || len(queryTerms) == 0 {
+		return scores
+	}
+
+	lengths := make(map[string]float64, len(idx.Terms))
+	totalLength := 0.0
+	docFrequency := make(map[string]int)
+	for tableName, terms := range idx.Terms {
+		for term, weight := range terms {
+			lengths[tableName] += weight
+			docFrequency[term]++
+		}
+		totalLength += lengths[tableName]
+	}
+	averageLength := totalLength / float64(len(idx.Terms))
+	if averageLength == 0 {
+		return scores
+	}
+
+	seen := make(map[string]bool)
+	for _, term := range queryTerms {
+		if seen[term] || docFrequency[term] == 0 {
+			continue
+		}
+		seen[term] = true
+
+		n := float64(len(idx.Terms))
+		df := float64(docFrequency[term])
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for tableName, terms := range idx.Terms {
+			tf := terms[term]
+			if tf == 0 {
+				continue
+			}
+			norm := bm25K1 * (1 - bm25B + bm25B*lengths[tableName]/averageLength)
+			scores[tableName] += idf * tf * (bm25K1 + 1) / (tf + norm)
+		}
+	}
+	return scores
+}
+
+// similarities returns the cosine similarity of every embedded table to the question embedding
+func (idx *SchemaRetrievalIndex) similarities(question []float32) map[string]float64 {
+	similarities := make(map[string]float64, len(idx.Embeddings))
+	for tableName, embedding := range idx.Embeddings {
+		similarities[tableName] = cosineSimilarity(question, embedding)
+	}
+	return similarities
+}
+
+// combineRetrievalScores adds the similarities to the lexical scores scaled to [0, 1], so both count about the same
+func combineRetrievalScores(lexical map[string]float64, similarities map[string]float64) map[string]float64 {
+	maxLexical := 0.0
+	for _, score := range lexical {
+		maxLexical = math.Max(maxLexical, score)
+	}
+
+	combined := make(map[string]float64, len(similarities))
+	for tableName, similarity := range similarities {
+		if similarity > 0 {
+			combined[tableName] = similarity
+		}
+	}
+	for tableName, score := range lexical {
+		if maxLexical > 0 {
+			combined[tableName] += score / maxLexical
+		}
+	}
+	return combined
+}
+
+// selectRelevantTables picks the topK best scored tables and adds up to topK of their direct neighbours through
+// foreign keys and relationships, so the joins between them can be written
+func selectRelevantTables(storage *SchemaStorage, scores map[string]float64, topK int) map[string]bool {
+	ranked := make([]string, 0, len(scores))
+	for tableName, score := range scores {
+		if score > 0 {
+			ranked = append(ranked, tableName)
+		}
+	}
+	byScore := func(tables []string) {
+		sort.Slice(tables, func(i, j int) bool {
+			if scores[tables[i]] != scores[tables[j]] {
+				return scores[tables[i]] > scores[tables[j]]
+			}
+			return tables[i] < tables[j]
+		})
+	}
+	byScore(ranked)
+	if len(ranked) > topK {
+		ranked = ranked[:topK]
+	}
+
+	selected := make(map[string]bool, len(ranked))
+	for _, tableName := range ranked {
+		selected[tableName] = true
+	}
+
+	neighbours := make(map[string]bool)
+	addNeighbour := func(from, to string) {
+		if selected[from] && !selected[to] {
+			if _, ok := storage.LLMSchema.Tables[to]; ok {
+				neighbours[to] = true
+			}
+		}
+	}
+	if storage.FullSchema != nil {
+		for tableName, table := range storage.FullSchema.Tables {
+			for _, fk := range table.ForeignKeys {
+				addNeighbour(tableName, fk.RefTable)
+				addNeighbour(fk.RefTable, tableName)
+			}
+		}
+	}
+	for _, rel := range storage.LLMSchema.Relationships {
+		addNeighbour(rel.FromTable, rel.ToTable)
+		addNeighbour(rel.ToTable, rel.FromTable)
+		if rel.Through != "" {
+			addNeighbour(rel.FromTable, rel.Through)
+			addNeighbour(rel.ToTable, rel.Through)
+		}
+	}
+
+	// Hub tables can have many neighbours, the most relevant ones are kept
+	neighbourList := make([]string, 0, len(neighbours))
+	for tableName := range neighbours {
+		neighbourList = append(neighbourList, tableName)
+	}
+	byScore(neighbourList)
+	if len(neighbourList) > topK {
+		neighbourList = neighbourList[:topK]
+	}
+	for _, tableName := range neighbourList {
+		selected[tableName] = true
+	}
+	return selected
+}
+
+// filterSchemaStorage returns a copy of the storage with only the selected tables and the relationships among them
+func filterSchemaStorage(storage *SchemaStorage, selected map[string]bool) *SchemaStorage {
+	llmSchema := &LLMSchemaInfo{Tables: make(map[string]LLMTableInfo, len(selected))}
+	for tableName, table := range storage.LLMSchema.Tables {
+		if selected[tableName] {
+			llmSchema.Tables[tableName] = table
+		}
+	}
+	for _, rel := range storage.LLMSchema.Relationships {
+		if selected[rel.FromTable] && selected[rel.ToTable] {
+			llmSchema.Relationships = append(llmSchema.Relationships, rel)
+		}
+	}
+
+	fullSchema := &SchemaInfo{Tables: make(map[string]TableSchema, len(selected))}
+	if storage.FullSchema != nil {
+		*fullSchema = *storage.FullSchema
+		fullSchema.Tables = make(map[string]TableSchema, len(selected))
+		for tableName, table := range storage.FullSchema.Tables {
+			if selected[tableName] {
+				fullSchema.Tables[tableName] = table
+			}
+		}
+		fullSchema.Relationships = nil
+		for _, rel := range storage.FullSchema.Relationships {
+			if selected[rel.FromTable] && selected[rel.ToTable] {
+				fullSchema.Relationships = append(fullSchema.Relationships, rel)
+			}
+		}
+	}
+
+	return &SchemaStorage{
+		FullSchema:     fullSchema,
+		LLMSchema:      llmSchema,
+		TableChecksums: storage.TableChecksums,
+		UpdatedAt:      storage.UpdatedAt,
+	}
+}
+
+// describeTableForEmbedding renders a table as the text embedded for semantic ranking
+func describeTableForEmbedding(table LLMTableInfo) string {
+	var text strings.Builder
+	text.WriteString("Table " + strings.Join(splitIdentifier(table.Name), " "))
+	if table.Description != "" {
+		text.WriteString(": " + table.Description)
+	}
+	text.WriteString("\nColumns: ")
+	for i, column := range table.Columns {
+		if i > 0 {
+			text.WriteString(", ")
+		}
+		text.WriteString(strings.Join(splitIdentifier(column.Name), " "))
+		if column.Description != "" {
+			text.WriteString(" (" + column.Description + ")")
+		}
+	}
+	return text.String()
+}
+
+// tokenizeForRetrieval splits text and identifiers (snake_case, camelCase) into lowercase singular terms
+func tokenizeForRetrieval(text string) []string {
+	var terms []string
+	for _, word := range splitIdentifier(text) {
+		term := singularize(word)
+		if len(term) < 2 || retrievalStopWords[term] {
+			continue
+		}
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// splitIdentifier splits on anything but letters and digits and on camelCase boundaries, lowercasing the words
+func splitIdentifier(text string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		// "orderItems" -> order, items and "HTTPStatus" -> http, status
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := current[len(current)-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
+
+// singularize strips common English plural endings so "orders" matches "order"
+func singularize(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 4 && (strings.HasSuffix(word, "sses") || strings.HasSuffix(word, "xes") || strings.HasSuffix(word, "ches") || strings.HasSuffix(word, "shes")):
+		return word[:len(word)-2]
+	case len(word) > 1 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
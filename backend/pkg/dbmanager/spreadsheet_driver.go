@@ -264,6 +264,15 @@ func (d *SpreadsheetDriver) ExecuteQuery(ctx context.Context, conn *Connection,
 	return result
 }
 
+// StreamQuery runs a read query in a read-only transaction using the chat's schema and returns a cursor over its rows
+func (d *SpreadsheetDriver) StreamQuery(ctx context.Context, conn *Connection, query string) (ResultCursor, error) {
+	schemaName := conn.Config.SchemaName
+	if schemaName == "" {
+		schemaName = fmt.Sprintf("conn_%s", conn.ChatID)
+	}
+	return streamSQLQuery(ctx, conn, query, &sql.TxOptions{ReadOnly: true}, nil, fmt.Sprintf("SET LOCAL search_path TO %s, public", schemaName))
+}
+
 // BeginTx begins a transaction with schema context
 func (d *SpreadsheetDriver) BeginTx(ctx context.Context, conn *Connection) Transaction {
 	// Get the underlying PostgreSQL transaction
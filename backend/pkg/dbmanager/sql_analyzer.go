@@ -0,0 +1,647 @@
+package dbmanager
+
+import (
+	"fmt"
+	"neobase-ai/internal/constants"
+	"strings"
+	"unicode"
+)
+
+// StatementKind classifies what a SQL statement does
+type StatementKind string
+
+const (
+	StatementKindRead  StatementKind = "read"
+	StatementKindWrite StatementKind = "write" // Changes data or the session/server state
+	StatementKindDDL   StatementKind = "ddl"   // Changes the schema or permissions
+)
+
+// StatementAnalysis is the classification of a single statement
+type StatementAnalysis struct {
+	Command  string // Leading keyword, e.g. SELECT or DELETE
+	Kind     StatementKind
+	Warnings []string
+}
+
+// QueryAnalysis is the static analysis of a query before it is executed
+type QueryAnalysis struct {
+	Statements []StatementAnalysis
+	Kind       StatementKind // Kind of the most impactful statement
+	IsCritical bool          // The query changes data or schema, has several statements or couldn't be parsed
+	Warnings   []string      // Dangerous patterns found, e.g. a DELETE without WHERE
+}
+
+// IsReadOnly reports if the query is a single statement that only reads data
+func (a *QueryAnalysis) IsReadOnly() bool {
+	return len(a.Statements) == 1 && a.Kind == StatementKindRead
+}
+
+// sqlDialect holds the lexical rules that differ between databases
+type sqlDialect struct {
+	name              string
+	hashComments      bool // # starts a comment
+	nestedComments    bool // /* */ comments can be nested
+	backslashEscapes  bool // Backslash escapes characters in '...' strings
+	doubleQuoteString bool // "..." is a string rather than an identifier
+	backtickIdents    bool // `...` is an identifier
+	dollarQuotes      bool // $tag$...$tag$ strings
+	execComments      bool // The server runs the text of /*! */ comments, /*+ */ optimizer hints aren't analyzed
+	sideEffectCalls   bool // Functions called from a SELECT may change data or the server state
+}
+
+var (
+	postgresDialect   = sqlDialect{name: "postgres", nestedComments: true, dollarQuotes: true, sideEffectCalls: true}
+	mysqlDialect      = sqlDialect{name: "mysql", hashComments: true, backslashEscapes: true, doubleQuoteString: true, backtickIdents: true, execComments: true, sideEffectCalls: true}
+	clickhouseDialect = sqlDialect{name: "clickhouse", hashComments: true, backslashEscapes: true, backtickIdents: true}
+)
+
+// getSQLDialect returns the dialect of the database type, false for databases the analyzer doesn't support
+func getSQLDialect(dbType string) (sqlDialect, bool) {
+	switch dbType {
+	case constants.DatabaseTypePostgreSQL, constants.DatabaseTypeYugabyteDB:
+		return postgresDialect, true
+	case constants.DatabaseTypeMySQL:
+		return mysqlDialect, true
+	case constants.DatabaseTypeClickhouse:
+		return clickhouseDialect, true
+	}
+	return sqlDialect{}, false
+}
+
+type sqlTokenKind int
+
+const (
+	sqlTokenWord       sqlTokenKind = iota // Keyword or unquoted identifier, upper-cased
+	sqlTokenIdentifier                     // Quoted identifier
+	sqlTokenString
+	sqlTokenNumber
+	sqlTokenSymbol
+)
+
+type sqlToken struct {
+	kind  sqlTokenKind
+	text  string
+	start int // Offsets of the token in the runes of the query
+	end   int
+}
+
+func (t sqlToken) is(kind sqlTokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+// AnalyzeSQL classifies the statements of a query in the SQL dialect of the database type and flags the dangerous ones.
+// Returns false for databases whose dialect isn't supported.
+func AnalyzeSQL(dbType string, query string) (*QueryAnalysis, bool) {
+	dialect, ok := getSQLDialect(dbType)
+	if !ok {
+		return nil, false
+	}
+
+	tokens, err := tokenizeSQL(query, dialect)
+	if err != nil {
+		return &QueryAnalysis{
+			Kind:       StatementKindWrite,
+			IsCritical: true,
+			Warnings:   []string{fmt.Sprintf("The query could not be parsed: %v", err)},
+		}, true
+	}
+
+	analysis := &QueryAnalysis{Kind: StatementKindRead}
+	for _, statement := range splitSQLTokens(tokens) {
+		result := analyzeStatement(statement, dialect)
+		analysis.Statements = append(analysis.Statements, result)
+		analysis.Kind = moreImpactfulKind(analysis.Kind, result.Kind)
+		analysis.Warnings = append(analysis.Warnings, result.Warnings...)
+	}
+	if len(analysis.Statements) > 1 {
+		analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("The query has %d statements", len(analysis.Statements)))
+	}
+	analysis.IsCritical = len(analysis.Statements) > 1 || analysis.Kind != StatementKindRead
+	return analysis, true
+}
+
+// tokenizeSQL splits a query into tokens, dropping whitespace and comments. The text of MySQL executable comments,
+// e.g. /*!40101 SET ... */, is tokenized as code since the server runs it.
+func tokenizeSQL(query string, dialect sqlDialect) ([]sqlToken, error) {
+	runes := []rune(query)
+	var tokens []sqlToken
+	inExecComment := false
+	for i := 0; i < len(runes); {
+		tokenStart, count := i, len(tokens)
+		r := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '-' && next == '-', r == '#' && dialect.hashComments:
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		case r == '/' && next == '*' && dialect.execComments && i+2 < len(runes) && runes[i+2] == '+':
+			return nil, fmt.Errorf("optimizer hint comments are not supported")
+
+		case r == '/' && next == '*' && dialect.execComments && executableCommentLength(runes[i:]) > 0:
+			if inExecComment {
+				return nil, fmt.Errorf("nested executable comment")
+			}
+			inExecComment = true
+			i += executableCommentLength(runes[i:])
+			// Skip the server version the comment is restricted to, e.g. /*!40101
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+
+		case r == '*' && next == '/' && inExecComment:
+			inExecComment = false
+			i += 2
+
+		case r == '/' && next == '*':
+			depth := 0
+			for ; i < len(runes); i++ {
+				if i+1 < len(runes) && runes[i] == '/' && runes[i+1] == '*' && (depth == 0 || dialect.nestedComments) {
+					depth++
+					i++
+				} else if i+1 < len(runes) && runes[i] == '*' && runes[i+1] == '/' {
+					depth--
+					i++
+					if depth == 0 {
+						i++
+						break
+					}
+				}
+			}
+			if depth > 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+
+		case r == '\'' || (r == '"' && dialect.doubleQuoteString):
+			end, err := scanQuoted(runes, i, r, dialect.backslashEscapes)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenString, text: string(runes[i+1 : end-1])})
+			i = end
+
+		case r == '"' || (r == '`' && dialect.backtickIdents):
+			end, err := scanQuoted(runes, i, r, false)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenIdentifier, text: string(runes[i+1 : end-1])})
+			i = end
+
+		case r == '$' && dialect.dollarQuotes && (next == '$' || unicode.IsLetter(next) || next == '_'):
+			// $tag$ strings, $1 parameters fall through to symbols
+			tagEnd := i + 1
+			for tagEnd < len(runes) && (unicode.IsLetter(runes[tagEnd]) || unicode.IsDigit(runes[tagEnd]) || runes[tagEnd] == '_') {
+				tagEnd++
+			}
+			if tagEnd >= len(runes) || runes[tagEnd] != '$' {
+				tokens = append(tokens, sqlToken{kind: sqlTokenSymbol, text: "$"})
+				i++
+				break
+			}
+			tag := string(runes[i : tagEnd+1])
+			body := string(runes[tagEnd+1:])
+			end := strings.Index(body, tag)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated dollar-quoted string")
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenString, text: body[:end]})
+			i = tagEnd + 1 + len([]rune(body[:end+len(tag)]))
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+				i++
+			}
+			word := strings.ToUpper(string(runes[start:i]))
+			// Postgres E'...' strings use backslash escapes
+			if word == "E" && dialect.name == postgresDialect.name && i < len(runes) && runes[i] == '\'' {
+				end, err := scanQuoted(runes, i, '\'', true)
+				if err != nil {
+					return nil, err
+				}
+				tokens = append(tokens, sqlToken{kind: sqlTokenString, text: string(runes[i+1 : end-1])})
+				i = end
+				break
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenWord, text: word})
+
+		case unicode.IsDigit(r) || (r == '.' && unicode.IsDigit(next)):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || unicode.IsLetter(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenNumber, text: string(runes[start:i])})
+
+		default:
+			tokens = append(tokens, sqlToken{kind: sqlTokenSymbol, text: string(r)})
+			i++
+		}
+		if len(tokens) > count {
+			tokens[count].start, tokens[count].end = tokenStart, i
+		}
+	}
+	if inExecComment {
+		return nil, fmt.Errorf("unterminated comment")
+	}
+	return tokens, nil
+}
+
+// executableCommentLength returns the length of the opening of a MySQL /*! or MariaDB /*M! executable comment the
+// runes start with, 0 for other text
+func executableCommentLength(runes []rune) int {
+	switch {
+	case len(runes) >= 3 && runes[0] == '/' && runes[1] == '*' && runes[2] == '!':
+		return 3
+	case len(runes) >= 4 && runes[0] == '/' && runes[1] == '*' && runes[2] == 'M' && runes[3] == '!':
+		return 4
+	}
+	return 0
+}
+
+// scanQuoted returns the offset after the closing quote of the quoted text starting at start.
+// A doubled quote is an escaped quote.
+func scanQuoted(runes []rune, start int, quote rune, backslashEscapes bool) (int, error) {
+	for i := start + 1; i < len(runes); i++ {
+		switch {
+		case backslashEscapes && runes[i] == '\\':
+			i++
+		case runes[i] == quote:
+			if i+1 < len(runes) && runes[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated %c quoted text", quote)
+}
+
+// splitSQLTokens splits the tokens into statements on semicolons, dropping empty statements
+func splitSQLTokens(tokens []sqlToken) [][]sqlToken {
+	var statements [][]sqlToken
+	start := 0
+	for i := 0; i <= len(tokens); i++ {
+		if i < len(tokens) && !tokens[i].is(sqlTokenSymbol, ";") {
+			continue
+		}
+		if i > start {
+			statements = append(statements, tokens[start:i])
+		}
+		start = i + 1
+	}
+	return statements
+}
+
+// Statements by their leading keyword. WITH, EXPLAIN, SELECT, UPDATE, DELETE and ALTER are analyzed further.
+var (
+	readCommands = map[string]bool{
+		"SELECT": true, "VALUES": true, "TABLE": true, "SHOW": true, "DESCRIBE": true, "DESC": true, "EXPLAIN": true,
+		"EXISTS": true, "WITH": true,
+	}
+	writeCommands = map[string]bool{
+		"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "REPLACE": true, "UPSERT": true, "COPY": true,
+		"CALL": true, "DO": true, "LOAD": true, "LOCK": true, "UNLOCK": true, "SET": true, "RESET": true, "USE": true,
+		"BEGIN": true, "START": true, "COMMIT": true, "END": true, "ROLLBACK": true, "SAVEPOINT": true, "RELEASE": true,
+		"VACUUM": true, "ANALYZE": true, "REINDEX": true, "CLUSTER": true, "REFRESH": true, "OPTIMIZE": true,
+		"KILL": true, "SYSTEM": true, "HANDLER": true, "PREPARE": true, "EXECUTE": true, "DEALLOCATE": true,
+		"LISTEN": true, "NOTIFY": true, "DISCARD": true, "CHECKPOINT": true, "FLUSH": true, "INSTALL": true,
+		"UNINSTALL": true,
+	}
+	ddlCommands = map[string]bool{
+		"CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true, "RENAME": true, "COMMENT": true,
+		"GRANT": true, "REVOKE": true, "ATTACH": true, "DETACH": true, "EXCHANGE": true, "UNDROP": true,
+	}
+
+	// Keywords ending the WHERE clause of an UPDATE/DELETE
+	whereClauseEnd = map[string]bool{
+		"ORDER": true, "LIMIT": true, "RETURNING": true, "SETTINGS": true,
+	}
+
+	// Statements a WITH query can end with, and the data-modifying statements its CTEs can hold
+	withMainCommands = map[string]bool{
+		"SELECT": true, "VALUES": true, "TABLE": true, "INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true,
+		"REPLACE": true,
+	}
+	cteBodyCommands = map[string]bool{
+		"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "WITH": true,
+	}
+)
+
+// analyzeStatement classifies a single statement
+func analyzeStatement(tokens []sqlToken, dialect sqlDialect) StatementAnalysis {
+	for len(tokens) > 0 && tokens[0].is(sqlTokenSymbol, "(") {
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 0 || tokens[0].kind != sqlTokenWord {
+		return StatementAnalysis{Kind: StatementKindWrite, Warnings: []string{"A statement could not be recognized"}}
+	}
+
+	command := tokens[0].text
+	result := StatementAnalysis{Command: command}
+	switch {
+	case command == "WITH":
+		return analyzeWithStatement(tokens, dialect)
+	case command == "EXPLAIN":
+		return analyzeExplainStatement(tokens, dialect)
+	case command == "SELECT":
+		result.Kind = StatementKindRead
+		analyzeSelectStatement(tokens, dialect, &result)
+	case command == "VALUES":
+		result.Kind = StatementKindRead
+		checkFunctionCalls(tokens, dialect, false, &result)
+	case readCommands[command]:
+		result.Kind = StatementKindRead
+	case command == "UPDATE" || command == "DELETE":
+		result.Kind = StatementKindWrite
+		if warning := checkWhereClause(tokens[1:], command, statementTable(tokens, command)); warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+		}
+	case writeCommands[command]:
+		result.Kind = StatementKindWrite
+	case command == "ALTER":
+		result.Kind = StatementKindDDL
+		analyzeAlterStatement(tokens, dialect, &result)
+	case command == "TRUNCATE":
+		result.Kind = StatementKindDDL
+		result.Warnings = append(result.Warnings, fmt.Sprintf("TRUNCATE removes every row of %s", statementTable(tokens, command)))
+	case command == "DROP":
+		result.Kind = StatementKindDDL
+		object := "object"
+		if len(tokens) > 1 && tokens[1].kind == sqlTokenWord {
+			object = strings.ToLower(tokens[1].text)
+		}
+		result.Warnings = append(result.Warnings, fmt.Sprintf("DROP deletes the %s %s", object, statementTable(tokens, command)))
+	case command == "CREATE":
+		result.Kind = StatementKindDDL
+		if len(tokens) > 3 && tokens[1].text == "OR" && tokens[2].text == "REPLACE" && tokens[3].text == "TABLE" {
+			result.Warnings = append(result.Warnings, "CREATE OR REPLACE TABLE drops the existing table and its data")
+		}
+	case ddlCommands[command]:
+		result.Kind = StatementKindDDL
+	default:
+		result.Kind = StatementKindWrite
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s statements are not recognized", command))
+	}
+	return result
+}
+
+// analyzeSelectStatement flags SELECT statements that write: SELECT INTO and calls of functions with side effects
+func analyzeSelectStatement(tokens []sqlToken, dialect sqlDialect, result *StatementAnalysis) {
+	checkFunctionCalls(tokens, dialect, false, result)
+	depth := 0
+	for i, token := range tokens {
+		switch {
+		case token.is(sqlTokenSymbol, "("):
+			depth++
+		case token.is(sqlTokenSymbol, ")"):
+			depth--
+		case token.is(sqlTokenWord, "INTO") && depth == 0:
+			target := ""
+			if i+1 < len(tokens) {
+				target = tokens[i+1].text
+			}
+			switch {
+			case target == "OUTFILE" || target == "DUMPFILE":
+				result.Kind = moreImpactfulKind(result.Kind, StatementKindWrite)
+				result.Warnings = append(result.Warnings, "SELECT INTO "+target+" writes a file on the database server")
+			case dialect.name == postgresDialect.name:
+				result.Kind = moreImpactfulKind(result.Kind, StatementKindDDL)
+				result.Warnings = append(result.Warnings, "SELECT INTO creates the table "+statementTable(tokens[i:], "INTO"))
+			}
+		}
+	}
+}
+
+// analyzeWithStatement classifies the main statement of a WITH query and the data-modifying statements of its CTEs
+func analyzeWithStatement(tokens []sqlToken, dialect sqlDialect) StatementAnalysis {
+	result := StatementAnalysis{Command: "WITH", Kind: StatementKindRead}
+	merge := func(inner StatementAnalysis) {
+		result.Kind = moreImpactfulKind(result.Kind, inner.Kind)
+		result.Warnings = append(result.Warnings, inner.Warnings...)
+	}
+
+	// Every parenthesized statement is analyzed, which covers the CTE bodies of both Postgres/MySQL and ClickHouse
+	depth := 0
+	mainStart := -1
+	for i := 1; i < len(tokens); i++ {
+		switch {
+		case tokens[i].is(sqlTokenSymbol, "("):
+			depth++
+			if mainStart < 0 && i+1 < len(tokens) && tokens[i+1].kind == sqlTokenWord && cteBodyCommands[tokens[i+1].text] {
+				merge(analyzeStatement(tokens[i+1:matchingParenthesis(tokens, i)], dialect))
+			}
+		case tokens[i].is(sqlTokenSymbol, ")"):
+			depth--
+		case depth == 0 && tokens[i].kind == sqlTokenWord && mainStart < 0 && i > 1 && !tokens[i-1].is(sqlTokenWord, "AS") &&
+			withMainCommands[tokens[i].text]:
+			mainStart = i
+		}
+	}
+
+	if mainStart < 0 {
+		result.Warnings = append(result.Warnings, "The main statement of the WITH query could not be found")
+		result.Kind = moreImpactfulKind(result.Kind, StatementKindWrite)
+		return result
+	}
+	// The words before parentheses at the top level of the CTE list are CTE names, e.g. WITH t(a, b) AS (...)
+	checkFunctionCalls(tokens[1:mainStart], dialect, true, &result)
+	main := analyzeStatement(tokens[mainStart:], dialect)
+	result.Command = main.Command
+	merge(main)
+	return result
+}
+
+// analyzeExplainStatement classifies EXPLAIN as a read, unless ANALYZE makes it run the explained statement
+func analyzeExplainStatement(tokens []sqlToken, dialect sqlDialect) StatementAnalysis {
+	result := StatementAnalysis{Command: "EXPLAIN", Kind: StatementKindRead}
+	if dialect.name == clickhouseDialect.name {
+		return result
+	}
+
+	analyze := false
+	for i := 1; i < len(tokens); i++ {
+		token := tokens[i]
+		if token.is(sqlTokenSymbol, "(") {
+			// Postgres options list, e.g. EXPLAIN (ANALYZE, BUFFERS)
+			end := matchingParenthesis(tokens, i)
+			for j := i + 1; j < end; j++ {
+				if tokens[j].is(sqlTokenWord, "ANALYZE") || tokens[j].is(sqlTokenWord, "ANALYSE") {
+					analyze = !(j+1 < end && (tokens[j+1].is(sqlTokenWord, "FALSE") || tokens[j+1].is(sqlTokenWord, "OFF")))
+				}
+			}
+			i = end
+			continue
+		}
+		if token.is(sqlTokenWord, "ANALYZE") || token.is(sqlTokenWord, "ANALYSE") {
+			analyze = true
+			continue
+		}
+		if token.kind == sqlTokenWord && (readCommands[token.text] || writeCommands[token.text] || ddlCommands[token.text]) {
+			if analyze {
+				inner := analyzeStatement(tokens[i:], dialect)
+				result.Kind = inner.Kind
+				result.Warnings = inner.Warnings
+				if inner.Kind != StatementKindRead {
+					result.Warnings = append(result.Warnings, "EXPLAIN ANALYZE runs the explained "+inner.Command)
+				}
+			}
+			break
+		}
+	}
+	return result
+}
+
+// Commands of an ALTER that change the schema, an ALTER made only of ClickHouse mutations is a write
+var alterSchemaCommands = map[string]bool{
+	"ADD": true, "DROP": true, "MODIFY": true, "RENAME": true, "CLEAR": true, "CHANGE": true, "MATERIALIZE": true,
+	"FREEZE": true, "ATTACH": true, "DETACH": true, "MOVE": true, "REPLACE": true, "COMMENT": true,
+}
+
+// analyzeAlterStatement flags the parts of an ALTER that remove data: DROP of columns/partitions and ClickHouse mutations
+func analyzeAlterStatement(tokens []sqlToken, dialect sqlDialect, result *StatementAnalysis) {
+	table := statementTable(tokens, "ALTER")
+	depth := 0
+	mutation, schemaChange := false, false
+	for i, token := range tokens {
+		switch {
+		case token.is(sqlTokenSymbol, "("):
+			depth++
+		case token.is(sqlTokenSymbol, ")"):
+			depth--
+		case depth > 0 || token.kind != sqlTokenWord:
+		case alterSchemaCommands[token.text] && token.text != "DROP":
+			schemaChange = true
+		case token.text == "DROP":
+			schemaChange = true
+			object := "part"
+			if i+1 < len(tokens) && tokens[i+1].kind == sqlTokenWord {
+				object = strings.ToLower(tokens[i+1].text)
+			}
+			result.Warnings = append(result.Warnings, fmt.Sprintf("ALTER drops a %s of %s", object, table))
+		case (token.text == "DELETE" || token.text == "UPDATE") && dialect.name == clickhouseDialect.name:
+			// ClickHouse mutations, ALTER TABLE t DELETE WHERE ...
+			mutation = true
+			result.Command = "ALTER " + token.text
+			if warning := checkWhereClause(tokens[i+1:], token.text, table); warning != "" {
+				result.Warnings = append(result.Warnings, warning)
+			}
+		}
+	}
+	if mutation && !schemaChange {
+		result.Kind = StatementKindWrite
+	}
+}
+
+// checkWhereClause returns a warning when an UPDATE/DELETE has no WHERE clause, or one that is always true
+func checkWhereClause(tokens []sqlToken, command string, table string) string {
+	depth := 0
+	for i, token := range tokens {
+		switch {
+		case token.is(sqlTokenSymbol, "("):
+			depth++
+		case token.is(sqlTokenSymbol, ")"):
+			depth--
+		case depth == 0 && token.is(sqlTokenWord, "WHERE"):
+			// The clause ends at a trailing keyword or, in ClickHouse ALTERs, at the next command
+			end, endDepth := i+1, 0
+			for ; end < len(tokens); end++ {
+				if tokens[end].is(sqlTokenSymbol, "(") {
+					endDepth++
+				} else if tokens[end].is(sqlTokenSymbol, ")") {
+					endDepth--
+				} else if endDepth == 0 && (tokens[end].is(sqlTokenSymbol, ",") || tokens[end].kind == sqlTokenWord && whereClauseEnd[tokens[end].text]) {
+					break
+				}
+			}
+			if isAlwaysTrue(tokens[i+1 : end]) {
+				return fmt.Sprintf("%s on %s has an always true WHERE clause and affects every row", command, table)
+			}
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s without a WHERE clause affects every row of %s", command, table)
+}
+
+// isAlwaysTrue reports if a condition is a constant true, e.g. 1, TRUE or 1 = 1
+func isAlwaysTrue(condition []sqlToken) bool {
+	for len(condition) >= 2 && condition[0].is(sqlTokenSymbol, "(") && matchingParenthesis(condition, 0) == len(condition)-1 {
+		condition = condition[1 : len(condition)-1]
+	}
+	switch len(condition) {
+	case 1:
+		return condition[0].is(sqlTokenWord, "TRUE") || condition[0].is(sqlTokenNumber, "1")
+	case 3:
+		left, operator, right := condition[0], condition[1], condition[2]
+		constant := left.kind == sqlTokenNumber || left.kind == sqlTokenString
+		return constant && operator.is(sqlTokenSymbol, "=") && right.is(left.kind, left.text)
+	}
+	return false
+}
+
+// statementTable returns the name of the table a statement works on, for warnings
+func statementTable(tokens []sqlToken, command string) string {
+	skip := map[string]bool{
+		"FROM": true, "TABLE": true, "ONLY": true, "IF": true, "EXISTS": true, "LOW_PRIORITY": true, "QUICK": true,
+		"IGNORE": true, "DATABASE": true, "SCHEMA": true, "VIEW": true, "INDEX": true, "MATERIALIZED": true,
+		"DICTIONARY": true, "TEMPORARY": true, "SEQUENCE": true, "FUNCTION": true, "USER": true, "ROLE": true,
+		"CONCURRENTLY": true,
+	}
+	for i := 1; i < len(tokens); i++ {
+		token := tokens[i]
+		if token.kind == sqlTokenWord && skip[token.text] {
+			continue
+		}
+		if token.kind != sqlTokenWord && token.kind != sqlTokenIdentifier {
+			break
+		}
+		name := token.text
+		if token.kind == sqlTokenWord {
+			name = strings.ToLower(name)
+		}
+		// Qualified names, e.g. schema.table
+		for i+2 < len(tokens) && tokens[i+1].is(sqlTokenSymbol, ".") {
+			i += 2
+			part := tokens[i].text
+			if tokens[i].kind == sqlTokenWord {
+				part = strings.ToLower(part)
+			}
+			name += "." + part
+		}
+		return name
+	}
+	return "the table"
+}
+
+// matchingParenthesis returns the offset of the parenthesis closing the one at start, or the last offset if it isn't closed
+func matchingParenthesis(tokens []sqlToken, start int) int {
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		if tokens[i].is(sqlTokenSymbol, "(") {
+			depth++
+		} else if tokens[i].is(sqlTokenSymbol, ")") {
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+// moreImpactfulKind returns the kind with the larger impact, DDL over writes over reads
+func moreImpactfulKind(a StatementKind, b StatementKind) StatementKind {
+	rank := map[StatementKind]int{StatementKindRead: 0, StatementKindWrite: 1, StatementKindDDL: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
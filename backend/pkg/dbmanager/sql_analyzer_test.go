@@ -0,0 +1,302 @@
+package dbmanager
+
+import (
+	"neobase-ai/internal/constants"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeSQL(t *testing.T) {
+	tests := []struct {
+		name           string
+		dbType         string
+		query          string
+		wantKind       StatementKind
+		wantCritical   bool
+		wantStatements int
+		wantWarning    string // Substring of one of the warnings, empty when there must be none
+	}{
+		{
+			name:           "select",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "SELECT id, lower(name) FROM users WHERE id = 1;",
+			wantKind:       StatementKindRead,
+			wantStatements: 1,
+		},
+		{
+			name:           "delete without where",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "DELETE FROM users",
+			wantKind:       StatementKindWrite,
+			wantCritical:   true,
+			wantStatements: 1,
+			wantWarning:    "DELETE without a WHERE clause affects every row of users",
+		},
+		{
+			name:           "update without where",
+			dbType:         constants.DatabaseTypeMySQL,
+			query:          "UPDATE `users` SET active = 0",
+			wantKind:       StatementKindWrite,
+			wantCritical:   true,
+			wantStatements: 1,
+			wantWarning:    "UPDATE without a WHERE clause affects every row of users",
+		},
+		{
+			name:           "delete with where",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "DELETE FROM users WHERE id = 1",
+			wantKind:       StatementKindWrite,
+			wantCritical:   true,
+			wantStatements: 1,
+		},
+		{
+			name:           "where 1=1",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "DELETE FROM users WHERE 1=1",
+			wantKind:       StatementKindWrite,
+			wantCritical:   true,
+			wantStatements: 1,
+			wantWarning:    "always true WHERE clause",
+		},
+		{
+			name:           "where 'a'='a'",
+			dbType:         constants.DatabaseTypeMySQL,
+			query:          "UPDATE users SET active = 0 WHERE 'a' = 'a' LIMIT 10",
+			wantKind:       StatementKindWrite,
+			wantCritical:   true,
+			wantStatements: 1,
+			wantWarning:    "always true WHERE clause",
+		},
+		{
+			name:           "where (true)",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "UPDATE users SET active = false WHERE (TRUE) RETURNING id",
+			wantKind:       StatementKindWrite,
+			wantCritical:   true,
+			wantStatements: 1,
+			wantWarning:    "always true WHERE clause",
+		},
+		{
+			name:           "several reads",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "SELECT 1; SELECT 2",
+			wantKind:       StatementKindRead,
+			wantCritical:   true,
+			wantStatements: 2,
+			wantWarning:    "The query has 2 statements",
+		},
+		{
+			name:           "read then drop",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "SELECT * FROM users; DROP TABLE users",
+			wantKind:       StatementKindDDL,
+			wantCritical:   true,
+			wantStatements: 2,
+			wantWarning:    "DROP deletes the table users",
+		},
+		{
+			name:           "semicolon in a string",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "SELECT 'a; DELETE FROM users'",
+			wantKind:       StatementKindRead,
+			wantStatements: 1,
+		},
+		{
+			name:           "statement in a comment",
+			dbType:         constants.DatabaseTypeMySQL,
+			query:          "SELECT 1 /* ; DELETE FROM users */ # ; DROP TABLE users",
+			wantKind:       StatementKindRead,
+			wantStatements: 1,
+		},
+		{
+			name:           "mysql executable comment",
+			dbType:         constants.DatabaseTypeMySQL,
+			query:          "SELECT 1 /*!50000 ; DELETE FROM users */",
+			wantKind:       StatementKindWrite,
+			wantCritical:   true,
+			wantStatements: 2,
+			wantWarning:    "DELETE without a WHERE clause",
+		},
+		{
+			name:           "mariadb executable comment",
+			dbType:         constants.DatabaseTypeMySQL,
+			query:          "/*M! DROP TABLE users */",
+			wantKind:       StatementKindDDL,
+			wantCritical:   true,
+			wantStatements: 1,
+			wantWarning:    "DROP deletes the table users",
+		},
+		{
+			name:         "mysql optimizer hint",
+			dbType:       constants.DatabaseTypeMySQL,
+			query:        "SELECT /*+ MAX_EXECUTION_TIME(1000) */ * FROM users",
+			wantKind:     StatementKindWrite,
+			wantCritical: true,
+			wantWarning:  "optimizer hint comments are not supported",
+		},
+		{
+			name:           "postgres dollar-quoted string",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "SELECT $$; DELETE FROM users$$, $tag$it's $$ here$tag$",
+			wantKind:       StatementKindRead,
+			wantStatements: 1,
+		},
+		{
+			name:           "explain",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "EXPLAIN DELETE FROM users",
+			wantKind:       StatementKindRead,
+			wantStatements: 1,
+		},
+		{
+			name:           "explain analyze write",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "EXPLAIN ANALYZE DELETE FROM users WHERE id = 1",
+			wantKind:       StatementKindWrite,
+			wantCritical:   true,
+			wantStatements: 1,
+			wantWarning:    "EXPLAIN ANALYZE runs the explained DELETE",
+		},
+		{
+			name:           "explain with analyze option",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "EXPLAIN (ANALYZE, BUFFERS) UPDATE users SET active = false WHERE id = 1",
+			wantKind:       StatementKindWrite,
+			wantCritical:   true,
+			wantStatements: 1,
+			wantWarning:    "EXPLAIN ANALYZE runs the explained UPDATE",
+		},
+		{
+			name:           "explain with analyze off",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "EXPLAIN (ANALYZE FALSE) DELETE FROM users",
+			wantKind:       StatementKindRead,
+			wantStatements: 1,
+		},
+		{
+			name:           "with select",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "WITH active (id) AS (SELECT id FROM users WHERE active) SELECT count(*) FROM active",
+			wantKind:       StatementKindRead,
+			wantStatements: 1,
+		},
+		{
+			name:           "with delete",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "WITH inactive AS (SELECT id FROM users WHERE NOT active) DELETE FROM users WHERE id IN (SELECT id FROM inactive)",
+			wantKind:       StatementKindWrite,
+			wantCritical:   true,
+			wantStatements: 1,
+		},
+		{
+			name:           "delete in a cte",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "WITH deleted AS (DELETE FROM users RETURNING *) SELECT * FROM deleted",
+			wantKind:       StatementKindWrite,
+			wantCritical:   true,
+			wantStatements: 1,
+			wantWarning:    "DELETE without a WHERE clause affects every row of users",
+		},
+		{
+			name:           "postgres select into",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "SELECT * INTO users_backup FROM users",
+			wantKind:       StatementKindDDL,
+			wantCritical:   true,
+			wantStatements: 1,
+			wantWarning:    "SELECT INTO creates the table users_backup",
+		},
+		{
+			name:           "mysql select into outfile",
+			dbType:         constants.DatabaseTypeMySQL,
+			query:          "SELECT * FROM users INTO OUTFILE '/tmp/users.csv'",
+			wantKind:       StatementKindWrite,
+			wantCritical:   true,
+			wantStatements: 1,
+			wantWarning:    "writes a file on the database server",
+		},
+		{
+			name:           "function with side effects",
+			dbType:         constants.DatabaseTypePostgreSQL,
+			query:          "SELECT pg_terminate_backend(1234)",
+			wantKind:       StatementKindWrite,
+			wantCritical:   true,
+			wantStatements: 1,
+			wantWarning:    "is not a known read-only function",
+		},
+		{
+			name:           "clickhouse table function",
+			dbType:         constants.DatabaseTypeClickhouse,
+			query:          "SELECT number FROM numbers(10)",
+			wantKind:       StatementKindRead,
+			wantStatements: 1,
+		},
+		{
+			name:           "clickhouse mutation",
+			dbType:         constants.DatabaseTypeClickhouse,
+			query:          "ALTER TABLE events DELETE WHERE 1",
+			wantKind:       StatementKindWrite,
+			wantCritical:   true,
+			wantStatements: 1,
+			wantWarning:    "always true WHERE clause",
+		},
+		{
+			name:         "unterminated string",
+			dbType:       constants.DatabaseTypePostgreSQL,
+			query:        "SELECT 'unterminated",
+			wantKind:     StatementKindWrite,
+			wantCritical: true,
+			wantWarning:  "The query could not be parsed",
+		},
+		{
+			name:         "unterminated executable comment",
+			dbType:       constants.DatabaseTypeMySQL,
+			query:        "SELECT 1 /*! DELETE FROM users",
+			wantKind:     StatementKindWrite,
+			wantCritical: true,
+			wantWarning:  "unterminated comment",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis, ok := AnalyzeSQL(tt.dbType, tt.query)
+			if !ok {
+				t.Fatalf("AnalyzeSQL(%s) is not supported", tt.dbType)
+			}
+			if analysis.Kind != tt.wantKind || analysis.IsCritical != tt.wantCritical || len(analysis.Statements) != tt.wantStatements {
+				t.Errorf("got kind %s, critical %v, %d statements, want %s, %v, %d", analysis.Kind, analysis.IsCritical,
+					len(analysis.Statements), tt.wantKind, tt.wantCritical, tt.wantStatements)
+			}
+			warnings := strings.Join(analysis.Warnings, "\n")
+			if tt.wantWarning == "" && warnings != "" {
+				t.Errorf("unexpected warnings %q", warnings)
+			}
+			if !strings.Contains(warnings, tt.wantWarning) {
+				t.Errorf("warnings %q don't contain %q", warnings, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestAnalyzeSQLUnsupportedDatabases(t *testing.T) {
+	for _, dbType := range []string{constants.DatabaseTypeMongoDB, constants.DatabaseTypeMSSQL, constants.DatabaseTypeSQLite, constants.DatabaseTypeNeo4j} {
+		if _, ok := AnalyzeSQL(dbType, "SELECT 1"); ok {
+			t.Errorf("AnalyzeSQL(%s) should not be supported", dbType)
+		}
+	}
+}
+
+func TestUnparseableQueriesAreNotReadOnly(t *testing.T) {
+	for _, query := range []string{"SELECT 'unterminated", "SELECT 1 /* unterminated", "SELECT $tag$ unterminated"} {
+		if analysis, ok := AnalyzeSQL(constants.DatabaseTypePostgreSQL, query); !ok || analysis.IsReadOnly() {
+			t.Errorf("%q must not be read-only", query)
+		}
+		if err := CheckReadOnlyQuery(constants.DatabaseTypePostgreSQL, query); err == nil {
+			t.Errorf("CheckReadOnlyQuery(%q) must fail", query)
+		}
+		if CanStreamQuery(constants.DatabaseTypePostgreSQL, query) {
+			t.Errorf("%q must not be streamed", query)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package dbmanager
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Functions known not to change data or the server state when called from a SELECT, in PostgreSQL and MySQL. Calls of
+// other functions, e.g. nextval(), pg_advisory_lock() or user-defined functions, make the statement a write.
+var readOnlyFunctions = map[string]bool{
+	// Aggregates and window functions
+	"COUNT": true, "SUM": true, "AVG": true, "MIN": true, "MAX": true, "ARRAY_AGG": true, "STRING_AGG": true,
+	"GROUP_CONCAT": true, "JSON_AGG": true, "JSONB_AGG": true, "JSON_OBJECT_AGG": true, "JSONB_OBJECT_AGG": true,
+	"JSON_ARRAYAGG": true, "JSON_OBJECTAGG": true, "BOOL_AND": true, "BOOL_OR": true, "EVERY": true, "BIT_AND": true,
+	"BIT_OR": true, "BIT_XOR": true, "STDDEV": true, "STDDEV_POP": true, "STDDEV_SAMP": true, "STD": true,
+	"VARIANCE": true, "VAR_POP": true, "VAR_SAMP": true, "PERCENTILE_CONT": true, "PERCENTILE_DISC": true,
+	"MODE": true, "CORR": true, "COVAR_POP": true, "COVAR_SAMP": true, "REGR_SLOPE": true, "REGR_INTERCEPT": true,
+	"REGR_COUNT": true, "REGR_R2": true, "REGR_AVGX": true, "REGR_AVGY": true, "ANY_VALUE": true, "GROUPING": true,
+	"ROW_NUMBER": true, "RANK": true, "DENSE_RANK": true, "PERCENT_RANK": true, "CUME_DIST": true, "NTILE": true,
+	"LAG": true, "LEAD": true, "FIRST_VALUE": true, "LAST_VALUE": true, "NTH_VALUE": true,
+
+	// Conditionals and conversions
+	"COALESCE": true, "NULLIF": true, "GREATEST": true, "LEAST": true, "IF": true, "IFNULL": true, "ISNULL": true,
+	"ELT": true, "FIELD": true, "CAST": true, "CONVERT": true, "TO_CHAR": true, "TO_DATE": true, "TO_NUMBER": true,
+	"TO_TIMESTAMP": true, "FORMAT": true, "BIN": true, "HEX": true, "UNHEX": true, "OCT": true, "CONV": true,
+	"TO_BASE64": true, "FROM_BASE64": true, "ENCODE": true, "DECODE": true, "TO_HEX": true,
+
+	// Strings
+	"LENGTH": true, "CHAR_LENGTH": true, "CHARACTER_LENGTH": true, "OCTET_LENGTH": true, "BIT_LENGTH": true,
+	"LOWER": true, "UPPER": true, "LCASE": true, "UCASE": true, "INITCAP": true, "TRIM": true, "LTRIM": true,
+	"RTRIM": true, "BTRIM": true, "SUBSTRING": true, "SUBSTR": true, "MID": true, "LEFT": true, "RIGHT": true,
+	"POSITION": true, "STRPOS": true, "LOCATE": true, "INSTR": true, "REPLACE": true, "TRANSLATE": true,
+	"OVERLAY": true, "REVERSE": true, "REPEAT": true, "LPAD": true, "RPAD": true, "CONCAT": true, "CONCAT_WS": true,
+	"SPLIT_PART": true, "SUBSTRING_INDEX": true, "REGEXP_REPLACE": true, "REGEXP_MATCHES": true,
+	"REGEXP_MATCH": true, "REGEXP_SUBSTR": true, "REGEXP_INSTR": true, "REGEXP_LIKE": true, "REGEXP_COUNT": true,
+	"REGEXP_SPLIT_TO_ARRAY": true, "REGEXP_SPLIT_TO_TABLE": true, "STARTS_WITH": true, "ASCII": true, "CHR": true,
+	"CHAR": true, "ORD": true, "MD5": true, "SHA1": true, "SHA2": true, "SHA224": true, "SHA256": true,
+	"SHA384": true, "SHA512": true, "CRC32": true, "QUOTE": true, "QUOTE_IDENT": true, "QUOTE_LITERAL": true,
+	"QUOTE_NULLABLE": true, "SOUNDEX": true, "SPACE": true, "STRCMP": true, "STRING_TO_ARRAY": true,
+	"ARRAY_TO_STRING": true, "NORMALIZE": true, "LIKE": true,
+
+	// Numbers
+	"ABS": true, "CEIL": true, "CEILING": true, "FLOOR": true, "ROUND": true, "TRUNC": true, "TRUNCATE": true,
+	"MOD": true, "POWER": true, "POW": true, "SQRT": true, "CBRT": true, "EXP": true, "LN": true, "LOG": true,
+	"LOG10": true, "LOG2": true, "SIGN": true, "PI": true, "DEGREES": true, "RADIANS": true, "SIN": true, "COS": true,
+	"TAN": true, "ASIN": true, "ACOS": true, "ATAN": true, "ATAN2": true, "COT": true, "DIV": true,
+	"WIDTH_BUCKET": true, "GCD": true, "LCM": true, "FACTORIAL": true, "RANDOM": true, "RAND": true,
+
+	// Dates and times
+	"NOW": true, "CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true, "LOCALTIME": true,
+	"LOCALTIMESTAMP": true, "CURDATE": true, "CURTIME": true, "SYSDATE": true, "UTC_DATE": true, "UTC_TIME": true,
+	"UTC_TIMESTAMP": true, "UNIX_TIMESTAMP": true, "FROM_UNIXTIME": true, "DATE": true, "TIME": true,
+	"TIMESTAMP": true, "YEAR": true, "MONTH": true, "DAY": true, "DAYOFMONTH": true, "DAYOFWEEK": true,
+	"DAYOFYEAR": true, "DAYNAME": true, "MONTHNAME": true, "WEEK": true, "WEEKDAY": true, "WEEKOFYEAR": true,
+	"YEARWEEK": true, "QUARTER": true, "HOUR": true, "MINUTE": true, "SECOND": true, "MICROSECOND": true,
+	"EXTRACT": true, "DATE_PART": true, "DATE_TRUNC": true, "DATE_BIN": true, "DATE_ADD": true, "DATE_SUB": true,
+	"ADDDATE": true, "SUBDATE": true, "ADDTIME": true, "SUBTIME": true, "DATEDIFF": true, "TIMEDIFF": true,
+	"TIMESTAMPDIFF": true, "TIMESTAMPADD": true, "DATE_FORMAT": true, "TIME_FORMAT": true, "STR_TO_DATE": true,
+	"LAST_DAY": true, "MAKEDATE": true, "MAKETIME": true, "MAKE_DATE": true, "MAKE_TIME": true,
+	"MAKE_TIMESTAMP": true, "MAKE_TIMESTAMPTZ": true, "MAKE_INTERVAL": true, "AGE": true, "JUSTIFY_DAYS": true,
+	"JUSTIFY_HOURS": true, "JUSTIFY_INTERVAL": true, "CLOCK_TIMESTAMP": true, "STATEMENT_TIMESTAMP": true,
+	"TRANSACTION_TIMESTAMP": true, "TIMEOFDAY": true, "TO_DAYS": true, "FROM_DAYS": true, "PERIOD_ADD": true,
+	"PERIOD_DIFF": true, "CONVERT_TZ": true, "SEC_TO_TIME": true, "TIME_TO_SEC": true, "ISFINITE": true,
+	"TIMEZONE": true,
+
+	// JSON
+	"JSON_EXTRACT": true, "JSON_UNQUOTE": true, "JSON_OBJECT": true, "JSON_ARRAY": true, "JSON_CONTAINS": true,
+	"JSON_CONTAINS_PATH": true, "JSON_KEYS": true, "JSON_LENGTH": true, "JSON_TYPE": true, "JSON_VALID": true,
+	"JSON_SEARCH": true, "JSON_VALUE": true, "JSON_QUERY": true, "JSON_EXISTS": true, "JSON_TABLE": true,
+	"JSON_PRETTY": true, "JSON_DEPTH": true, "JSON_SET": true, "JSON_INSERT": true, "JSON_REPLACE": true,
+	"JSON_REMOVE": true, "JSON_MERGE_PATCH": true, "JSON_MERGE_PRESERVE": true, "JSON_ARRAY_APPEND": true,
+	"JSON_QUOTE": true, "JSON_OVERLAPS": true, "JSON_BUILD_OBJECT": true, "JSONB_BUILD_OBJECT": true,
+	"JSON_BUILD_ARRAY": true, "JSONB_BUILD_ARRAY": true, "TO_JSON": true, "TO_JSONB": true, "ROW_TO_JSON": true,
+	"ARRAY_TO_JSON": true, "JSON_ARRAY_LENGTH": true, "JSONB_ARRAY_LENGTH": true, "JSON_ARRAY_ELEMENTS": true,
+	"JSONB_ARRAY_ELEMENTS": true, "JSON_ARRAY_ELEMENTS_TEXT": true, "JSONB_ARRAY_ELEMENTS_TEXT": true,
+	"JSON_EACH": true, "JSONB_EACH": true, "JSON_EACH_TEXT": true, "JSONB_EACH_TEXT": true,
+	"JSON_OBJECT_KEYS": true, "JSONB_OBJECT_KEYS": true, "JSON_EXTRACT_PATH": true, "JSONB_EXTRACT_PATH": true,
+	"JSON_EXTRACT_PATH_TEXT": true, "JSONB_EXTRACT_PATH_TEXT": true, "JSON_TYPEOF": true, "JSONB_TYPEOF": true,
+	"JSONB_SET": true, "JSONB_INSERT": true, "JSON_STRIP_NULLS": true, "JSONB_STRIP_NULLS": true,
+	"JSONB_PRETTY": true, "JSON_POPULATE_RECORD": true, "JSONB_POPULATE_RECORD": true, "JSON_TO_RECORD": true,
+	"JSONB_TO_RECORD": true, "JSON_TO_RECORDSET": true, "JSONB_TO_RECORDSET": true, "JSONB_PATH_QUERY": true,
+	"JSONB_PATH_EXISTS": true, "JSONB_PATH_MATCH": true, "JSONB_PATH_QUERY_ARRAY": true,
+	"JSONB_PATH_QUERY_FIRST": true,
+
+	// Arrays, ranges and set returning functions
+	"ARRAY_LENGTH": true, "CARDINALITY": true, "ARRAY_POSITION": true, "ARRAY_POSITIONS": true,
+	"ARRAY_APPEND": true, "ARRAY_PREPEND": true, "ARRAY_CAT": true, "ARRAY_REMOVE": true, "ARRAY_REPLACE": true,
+	"ARRAY_DIMS": true, "ARRAY_LOWER": true, "ARRAY_UPPER": true, "ARRAY_NDIMS": true, "UNNEST": true,
+	"GENERATE_SERIES": true, "GENERATE_SUBSCRIPTS": true, "LOWER_INC": true, "UPPER_INC": true, "ISEMPTY": true,
+
+	// Full text search
+	"TO_TSVECTOR": true, "TO_TSQUERY": true, "PLAINTO_TSQUERY": true, "PHRASETO_TSQUERY": true,
+	"WEBSEARCH_TO_TSQUERY": true, "TS_RANK": true, "TS_RANK_CD": true, "TS_HEADLINE": true, "MATCH": true,
+
+	// Session and catalog information
+	"VERSION": true, "DATABASE": true, "CURRENT_DATABASE": true, "CURRENT_SCHEMA": true, "CURRENT_SCHEMAS": true,
+	"CURRENT_USER": true, "SESSION_USER": true, "USER": true, "SCHEMA": true, "CONNECTION_ID": true,
+	"FOUND_ROWS": true, "ROW_COUNT": true, "CURRENT_SETTING": true, "CURRVAL": true, "UUID": true,
+	"GEN_RANDOM_UUID": true, "PG_TYPEOF": true, "PG_SIZE_PRETTY": true, "PG_TOTAL_RELATION_SIZE": true,
+	"PG_RELATION_SIZE": true, "PG_TABLE_SIZE": true, "PG_INDEXES_SIZE": true, "PG_DATABASE_SIZE": true,
+	"PG_COLUMN_SIZE": true, "PG_GET_VIEWDEF": true, "PG_GET_INDEXDEF": true, "PG_GET_CONSTRAINTDEF": true,
+	"PG_GET_FUNCTIONDEF": true, "PG_GET_EXPR": true, "PG_GET_SERIAL_SEQUENCE": true, "PG_GET_USERBYID": true,
+	"PG_TABLE_IS_VISIBLE": true, "HAS_TABLE_PRIVILEGE": true, "HAS_SCHEMA_PRIVILEGE": true,
+	"HAS_DATABASE_PRIVILEGE": true, "HAS_COLUMN_PRIVILEGE": true, "OBJ_DESCRIPTION": true, "COL_DESCRIPTION": true,
+	"FORMAT_TYPE": true, "TO_REGCLASS": true, "PG_BACKEND_PID": true, "PG_POSTMASTER_START_TIME": true,
+	"PG_IS_IN_RECOVERY": true,
+}
+
+// Keywords followed by a parenthesis that are not function calls, e.g. IN (...), OVER (...) or the types of a CAST
+var sqlParenthesisKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "JOIN": true, "ON": true, "USING": true, "WHERE": true, "AND": true, "OR": true,
+	"XOR": true, "NOT": true, "IN": true, "EXISTS": true, "ANY": true, "ALL": true, "SOME": true, "VALUES": true,
+	"AS": true, "OVER": true, "FILTER": true, "GROUP": true, "BY": true, "HAVING": true, "LIMIT": true, "OFFSET": true,
+	"FETCH": true, "THEN": true, "ELSE": true, "WHEN": true, "CASE": true, "IS": true, "DISTINCT": true,
+	"UNION": true, "INTERSECT": true, "EXCEPT": true, "LIKE": true, "ILIKE": true, "BETWEEN": true, "ESCAPE": true,
+	"RETURNING": true, "WINDOW": true, "SETS": true, "CUBE": true, "ROLLUP": true, "LATERAL": true, "ROW": true,
+	"ARRAY": true, "MATERIALIZED": true, "RECURSIVE": true, "ONLY": true, "INDEX": true, "KEY": true,
+	"PARTITION": true, "OF": true, "AGAINST": true, "TABLESAMPLE": true, "SYSTEM": true, "BERNOULLI": true,
+	"REPEATABLE": true, "WITH": true, "TO": true,
+	// Types
+	"DECIMAL": true, "NUMERIC": true, "DEC": true, "VARCHAR": true, "CHARACTER": true, "VARYING": true,
+	"NCHAR": true, "NVARCHAR": true, "BIT": true, "VARBIT": true, "BINARY": true, "VARBINARY": true, "FLOAT": true,
+	"DOUBLE": true, "REAL": true, "TIMESTAMPTZ": true, "DATETIME": true, "INT": true, "INTEGER": true,
+	"BIGINT": true, "SMALLINT": true, "TINYINT": true, "MEDIUMINT": true, "SIGNED": true, "UNSIGNED": true,
+	"ENUM": true, "INTERVAL": true,
+}
+
+// ClickHouse table functions that only read data of the server, others such as url(), file() or remote() reach
+// outside of it. ClickHouse scalar functions have no side effects.
+var clickhouseReadOnlyTableFunctions = map[string]bool{
+	"NUMBERS": true, "NUMBERS_MT": true, "ZEROS": true, "ZEROS_MT": true, "GENERATERANDOM": true,
+	"GENERATE_SERIES": true, "MERGE": true, "NULL": true, "VIEW": true, "FORMAT": true,
+}
+
+// checkFunctionCalls makes the statement a write when it calls a function that isn't known to be read-only.
+// With skipTopLevel the words before parentheses outside of any parenthesis are ignored.
+func checkFunctionCalls(tokens []sqlToken, dialect sqlDialect, skipTopLevel bool, result *StatementAnalysis) {
+	flagged := map[string]bool{}
+	flag := func(warning string) {
+		result.Kind = moreImpactfulKind(result.Kind, StatementKindWrite)
+		if !flagged[warning] {
+			flagged[warning] = true
+			result.Warnings = append(result.Warnings, warning)
+		}
+	}
+
+	depth := 0
+	for i, token := range tokens {
+		switch {
+		case token.is(sqlTokenSymbol, "("):
+			depth++
+			continue
+		case token.is(sqlTokenSymbol, ")"):
+			depth--
+			continue
+		}
+		if i+1 >= len(tokens) || !tokens[i+1].is(sqlTokenSymbol, "(") || (skipTopLevel && depth == 0) {
+			continue
+		}
+		if token.kind != sqlTokenWord && token.kind != sqlTokenIdentifier {
+			continue
+		}
+		name := token.text
+		if token.kind == sqlTokenWord {
+			name = strings.ToLower(name)
+		}
+
+		if !dialect.sideEffectCalls {
+			tableFunction := i > 0 && (tokens[i-1].is(sqlTokenWord, "FROM") || tokens[i-1].is(sqlTokenWord, "JOIN"))
+			if tableFunction && !clickhouseReadOnlyTableFunctions[strings.ToUpper(token.text)] {
+				flag(fmt.Sprintf("The table function %s() may reach outside of the database", name))
+			}
+			continue
+		}
+
+		// Functions qualified by a schema other than pg_catalog are user-defined
+		qualifier := ""
+		if i >= 2 && tokens[i-1].is(sqlTokenSymbol, ".") {
+			qualifier = strings.ToUpper(tokens[i-2].text)
+			name = strings.ToLower(tokens[i-2].text) + "." + name
+		}
+		if token.kind == sqlTokenWord && qualifier == "" && sqlParenthesisKeywords[token.text] {
+			continue
+		}
+		if token.kind == sqlTokenWord && readOnlyFunctions[token.text] && (qualifier == "" || qualifier == "PG_CATALOG") {
+			continue
+		}
+		flag(fmt.Sprintf("%s() is not a known read-only function and may change data", name))
+	}
+}
@@ -31,8 +31,10 @@ type SchemaInfo struct {
 	Views     map[string]ViewSchema     `json:"views,omitempty"`
 	Sequences map[string]SequenceSchema `json:"sequences,omitempty"`
 	Enums     map[string]EnumSchema     `json:"enums,omitempty"`
-	UpdatedAt time.Time                 `json:"updated_at"`
-	Checksum  string                    `json:"checksum"`
+	// Relationships holds relationships that aren't foreign keys, e.g. Neo4j relationship types
+	Relationships []SchemaRelationship `json:"relationships,omitempty"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+	Checksum      string               `json:"checksum"`
 }
 
 type TableSchema struct {
@@ -102,6 +104,9 @@ type SchemaStorage struct {
 	TableChecksums map[string]string `json:"table_checksums"`
 
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// Index ranking the tables against questions, built on first use
+	RetrievalIndex *SchemaRetrievalIndex `json:"retrieval_index,omitempty"`
 }
 
 // LLMSchemaInfo is a simplified schema representation for the LLM
@@ -559,8 +564,8 @@ func (sm *SchemaManager) getTableChecksums(ctx context.Context, db DBExecutor, d
 			checksums[tableName] = checksum
 		}
 		return checksums, nil
-	case constants.DatabaseTypeMySQL:
-		// Implement MySQL checksum calculation
+	case constants.DatabaseTypeMySQL, constants.DatabaseTypeMSSQL, constants.DatabaseTypeSQLite, constants.DatabaseTypeDuckDB:
+		// Implement MySQL, SQL Server, SQLite and DuckDB checksum calculation
 		checksums := make(map[string]string)
 
 		// Get schema directly from the database
@@ -663,6 +668,106 @@ func (sm *SchemaManager) getTableChecksums(ctx context.Context, db DBExecutor, d
 			checksums[collectionName] = checksum
 		}
 		return checksums, nil
+	case constants.DatabaseTypeRedis:
+		// Implement Redis checksum calculation
+		checksums := make(map[string]string)
+
+		// Get schema directly from the database
+		schema, err := db.GetSchema(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get schema: %v", err)
+		}
+
+		// Calculate checksums for each key pattern (table)
+		for patternName, pattern := range schema.Tables {
+			// Check for context cancellation
+			if err := ctx.Err(); err != nil {
+				log.Printf("getTableChecksums -> context cancelled: %v", err)
+				return nil, err
+			}
+
+			// Convert key pattern definition to string for checksum
+			patternStr := fmt.Sprintf("%s:%v:%v:%v:%v",
+				patternName,
+				pattern.Columns,
+				pattern.Indexes,
+				pattern.ForeignKeys,
+				pattern.Constraints,
+			)
+
+			// Calculate checksum using crypto/md5
+			hasher := md5.New()
+			hasher.Write([]byte(patternStr))
+			checksum := hex.EncodeToString(hasher.Sum(nil))
+			checksums[patternName] = checksum
+		}
+		return checksums, nil
+	case constants.DatabaseTypeCassandra:
+		// Implement Cassandra checksum calculation
+		checksums := make(map[string]string)
+
+		// Get schema directly from the database
+		schema, err := db.GetSchema(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get schema: %v", err)
+		}
+
+		// Calculate checksums for each table
+		for tableName, table := range schema.Tables {
+			// Check for context cancellation
+			if err := ctx.Err(); err != nil {
+				log.Printf("getTableChecksums -> context cancelled: %v", err)
+				return nil, err
+			}
+
+			// Convert table definition to string for checksum
+			tableStr := fmt.Sprintf("%s:%v:%v:%v:%v",
+				tableName,
+				table.Columns,
+				table.Indexes,
+				table.ForeignKeys,
+				table.Constraints,
+			)
+
+			// Calculate checksum using crypto/md5
+			hasher := md5.New()
+			hasher.Write([]byte(tableStr))
+			checksum := hex.EncodeToString(hasher.Sum(nil))
+			checksums[tableName] = checksum
+		}
+		return checksums, nil
+	case constants.DatabaseTypeNeo4j:
+		checksums := make(map[string]string)
+
+		// Get schema directly from the database
+		schema, err := db.GetSchema(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get schema: %v", err)
+		}
+
+		// Calculate checksums for each label and relationship type, the comment holds the relationship patterns
+		for tableName, table := range schema.Tables {
+			// Check for context cancellation
+			if err := ctx.Err(); err != nil {
+				log.Printf("getTableChecksums -> context cancelled: %v", err)
+				return nil, err
+			}
+
+			tableStr := fmt.Sprintf("%s:%v:%v:%v:%s",
+				tableName,
+				table.Columns,
+				table.Indexes,
+				table.Constraints,
+				table.Comment,
+			)
+
+			// Calculate checksum using crypto/md5
+			hasher := md5.New()
+			hasher.Write([]byte(tableStr))
+			checksum := hex.EncodeToString(hasher.Sum(nil))
+			checksums[tableName] = checksum
+		}
+		return checksums, nil
 	case "spreadsheet":
 		// Spreadsheet needs special handling to get schema with the fetcher
 		checksums := make(map[string]string)
@@ -757,6 +862,29 @@ func (sm *SchemaManager) getStoredSchema(ctx context.Context, chatID string) (*S
 	return schema, nil
 }
 
+// GetStoredIndexes returns the indexes of the given tables from the stored schema, by table name.
+// Tables may be qualified with their schema or database, e.g. "analytics.events".
+func (sm *SchemaManager) GetStoredIndexes(ctx context.Context, chatID string, tables []string) (map[string]map[string]IndexInfo, error) {
+	storage, err := sm.getStoredSchema(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make(map[string]map[string]IndexInfo)
+	for _, tableName := range tables {
+		table, exists := storage.FullSchema.Tables[tableName]
+		if !exists {
+			if dot := strings.LastIndex(tableName, "."); dot != -1 {
+				table, exists = storage.FullSchema.Tables[tableName[dot+1:]]
+			}
+		}
+		if exists {
+			indexes[tableName] = table.Indexes
+		}
+	}
+	return indexes, nil
+}
+
 // Add type-specific schema simplification
 type SchemaSimplifier interface {
 	SimplifyDataType(dbType string) string
@@ -1170,6 +1298,17 @@ func (m *SchemaManager) FormatSchemaForLLMWithExamples(storage *SchemaStorage) s
 		result.WriteString("\n")
 	}
 
+	// Add graph relationships information
+	if len(storage.FullSchema.Relationships) > 0 {
+		result.WriteString("Relationships:\n")
+		log.Printf("FormatSchemaForLLMWithExamples -> Formatting %d relationships", len(storage.FullSchema.Relationships))
+
+		for _, rel := range storage.FullSchema.Relationships {
+			result.WriteString(fmt.Sprintf("  - (:%s)-[:%s]->(:%s)\n", rel.FromTable, rel.Through, rel.ToTable))
+		}
+		result.WriteString("\n")
+	}
+
 	// Add sequences information
 	if len(storage.FullSchema.Sequences) > 0 {
 		result.WriteString("Sequences:\n")
@@ -1632,6 +1771,16 @@ func (sm *SchemaManager) extractRelationships(schema *SchemaInfo) []SchemaRelati
 		}
 	}
 
+	// Graph databases report their relationships directly
+	for _, rel := range schema.Relationships {
+		pairKey := fmt.Sprintf("%s:%s:%s", rel.FromTable, rel.ToTable, rel.Through)
+		if processedPairs[pairKey] {
+			continue
+		}
+		relationships = append(relationships, rel)
+		processedPairs[pairKey] = true
+	}
+
 	return relationships
 }
 
@@ -1682,6 +1831,11 @@ func (sm *SchemaManager) registerDefaultFetchers() {
 		return NewMySQLSchemaFetcher(db)
 	})
 
+	// Register SQL Server schema fetcher
+	sm.RegisterFetcher("mssql", func(db DBExecutor) SchemaFetcher {
+		return NewMSSQLSchemaFetcher(db)
+	})
+
 	// Register ClickHouse schema fetcher
 	sm.RegisterFetcher("clickhouse", func(db DBExecutor) SchemaFetcher {
 		return NewClickHouseSchemaFetcher(db)
@@ -1692,6 +1846,31 @@ func (sm *SchemaManager) registerDefaultFetchers() {
 		return NewMongoDBSchemaFetcher(db)
 	})
 
+	// Register Redis schema fetcher
+	sm.RegisterFetcher("redis", func(db DBExecutor) SchemaFetcher {
+		return NewRedisSchemaFetcher(db)
+	})
+
+	// Register Cassandra schema fetcher
+	sm.RegisterFetcher("cassandra", func(db DBExecutor) SchemaFetcher {
+		return NewCassandraSchemaFetcher(db)
+	})
+
+	// Register Neo4j schema fetcher
+	sm.RegisterFetcher("neo4j", func(db DBExecutor) SchemaFetcher {
+		return NewNeo4jSchemaFetcher(db)
+	})
+
+	// Register SQLite schema fetcher
+	sm.RegisterFetcher("sqlite", func(db DBExecutor) SchemaFetcher {
+		return NewSQLiteSchemaFetcher(db)
+	})
+
+	// Register DuckDB schema fetcher
+	sm.RegisterFetcher("duckdb", func(db DBExecutor) SchemaFetcher {
+		return NewDuckDBSchemaFetcher(db)
+	})
+
 	// Register Spreadsheet schema fetcher (uses custom SpreadsheetDriver fetcher)
 	sm.RegisterFetcher("spreadsheet", func(db DBExecutor) SchemaFetcher {
 		return &SpreadsheetDriver{
@@ -2147,9 +2326,27 @@ func (sm *SchemaManager) registerDefaultSimplifiers() {
 	// Register MySQL simplifier
 	sm.RegisterSimplifier("mysql", &MySQLSimplifier{})
 
+	// Register SQL Server simplifier
+	sm.RegisterSimplifier("mssql", &MSSQLSimplifier{})
+
 	// Register ClickHouse simplifier
 	sm.RegisterSimplifier("clickhouse", &ClickHouseSimplifier{})
 
 	// Register MongoDB simplifier
 	sm.RegisterSimplifier("mongodb", &MongoDBSimplifier{})
+
+	// Register Redis simplifier
+	sm.RegisterSimplifier("redis", &RedisSimplifier{})
+
+	// Register Cassandra simplifier
+	sm.RegisterSimplifier("cassandra", &CassandraSimplifier{})
+
+	// Register Neo4j simplifier
+	sm.RegisterSimplifier("neo4j", &Neo4jSimplifier{})
+
+	// Register SQLite simplifier
+	sm.RegisterSimplifier("sqlite", &SQLiteSimplifier{})
+
+	// Register DuckDB simplifier
+	sm.RegisterSimplifier("duckdb", &DuckDBSimplifier{})
 }
@@ -0,0 +1,379 @@
+package dbmanager
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// SQLiteDriver implements the DatabaseDriver interface for uploaded SQLite files
+type SQLiteDriver struct{}
+
+// NewSQLiteDriver creates a new SQLite driver
+func NewSQLiteDriver() DatabaseDriver {
+	return &SQLiteDriver{}
+}
+
+// Connect decrypts the uploaded SQLite file and opens it
+func (d *SQLiteDriver) Connect(config ConnectionConfig) (*Connection, error) {
+	wrapper, err := openDatabaseFile(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait on locks instead of failing when a write is in progress and enforce foreign keys like the original app would
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)", wrapper.WorkingPath)
+	gormDB, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		wrapper.removeWorkingFiles()
+		return nil, fmt.Errorf("failed to open SQLite file: %v", err)
+	}
+
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		wrapper.removeWorkingFiles()
+		return nil, fmt.Errorf("failed to get SQL DB: %v", err)
+	}
+
+	// Make sure the file really is a SQLite database
+	var tableCount int64
+	if err := gormDB.Raw("SELECT COUNT(*) FROM sqlite_master").Scan(&tableCount).Error; err != nil {
+		sqlDB.Close()
+		wrapper.removeWorkingFiles()
+		return nil, fmt.Errorf("file is not a valid SQLite database: %v", err)
+	}
+
+	sqlDB.SetMaxOpenConns(5)
+	sqlDB.SetMaxIdleConns(2)
+	sqlDB.SetConnMaxLifetime(time.Hour)
+
+	conn := &Connection{
+		DB:          gormDB,
+		LastUsed:    time.Now(),
+		Status:      StatusConnected,
+		Config:      config,
+		Subscribers: make(map[string]bool),
+		SubLock:     sync.RWMutex{},
+		MongoDBObj:  wrapper,
+	}
+
+	return conn, nil
+}
+
+// Disconnect persists pending writes, closes the SQLite file and removes the working copy
+func (d *SQLiteDriver) Disconnect(conn *Connection) error {
+	wrapper, err := getDatabaseFileWrapper(conn)
+	if err != nil {
+		return err
+	}
+
+	if wrapper.Dirty {
+		if err := persistSQLiteFile(conn); err != nil {
+			log.Printf("SQLiteDriver -> Disconnect -> Failed to persist database file: %v", err)
+		}
+	}
+
+	sqlDB, err := conn.DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get SQL DB: %v", err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("failed to close connection: %v", err)
+	}
+
+	wrapper.removeWorkingFiles()
+	return nil
+}
+
+// Ping checks if the SQLite connection is alive
+func (d *SQLiteDriver) Ping(conn *Connection) error {
+	if conn == nil || conn.DB == nil {
+		return fmt.Errorf("no active connection to ping")
+	}
+
+	sqlDB, err := conn.DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %v", err)
+	}
+
+	return sqlDB.Ping()
+}
+
+// IsAlive checks if the SQLite connection is still valid
+func (d *SQLiteDriver) IsAlive(conn *Connection) bool {
+	return d.Ping(conn) == nil
+}
+
+// ExecuteQuery executes a SQL query on the SQLite file
+func (d *SQLiteDriver) ExecuteQuery(ctx context.Context, conn *Connection, query string, queryType string, findCount bool) *QueryExecutionResult {
+	if conn == nil || conn.DB == nil {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: "No active connection",
+				Code:    "CONNECTION_ERROR",
+			},
+		}
+	}
+
+	result, wrote := executeSQLiteQuery(ctx, conn.DB, query)
+	if wrote && result.Error == nil {
+		if err := persistSQLiteFile(conn); err != nil {
+			log.Printf("SQLiteDriver -> ExecuteQuery -> Failed to persist database file: %v", err)
+		}
+	}
+	return result
+}
+
+// executeSQLiteQuery runs each statement of the query and reports whether anything was written
+func executeSQLiteQuery(ctx context.Context, db *gorm.DB, query string) (*QueryExecutionResult, bool) {
+	startTime := time.Now()
+	result := &QueryExecutionResult{}
+	wrote := false
+
+	// SQLite accepts the same quoting styles as MySQL
+	statements := splitMySQLStatements(query)
+
+	for _, stmt := range statements {
+		if strings.TrimSpace(stmt) == "" {
+			continue
+		}
+
+		// Check for context cancellation
+		if ctx.Err() != nil {
+			result.Error = &dtos.QueryError{
+				Message: "Query execution cancelled",
+				Code:    "EXECUTION_CANCELLED",
+			}
+			return result, wrote
+		}
+
+		if isSQLiteReadStatement(stmt) {
+			rows, err := scanSQLiteRows(db.WithContext(ctx), stmt)
+			if err != nil {
+				result.Error = &dtos.QueryError{
+					Message: err.Error(),
+					Code:    "EXECUTION_ERROR",
+				}
+				return result, wrote
+			}
+
+			result.Result = map[string]interface{}{
+				"results": processSQLiteRows(rows),
+			}
+		} else {
+			// For other queries (INSERT, UPDATE, DELETE, DDL), execute and return affected rows
+			execResult := db.WithContext(ctx).Exec(stmt)
+			if execResult.Error != nil {
+				result.Error = &dtos.QueryError{
+					Message: execResult.Error.Error(),
+					Code:    "EXECUTION_ERROR",
+				}
+				return result, wrote
+			}
+			wrote = true
+
+			rowsAffected := execResult.RowsAffected
+			result.RowsAffected += rowsAffected
+			if rowsAffected > 0 {
+				result.Result = map[string]interface{}{
+					"rowsAffected": rowsAffected,
+					"message":      fmt.Sprintf("%d row(s) affected", rowsAffected),
+				}
+			} else {
+				result.Result = map[string]interface{}{
+					"message": "Query performed successfully",
+				}
+			}
+		}
+	}
+
+	result.ExecutionTime = int(time.Since(startTime).Milliseconds())
+
+	// Marshal the result to JSON
+	resultJSON, err := json.Marshal(result.Result)
+	if err != nil {
+		return &QueryExecutionResult{
+			ExecutionTime: int(time.Since(startTime).Milliseconds()),
+			Error: &dtos.QueryError{
+				Code:    "JSON_MARSHAL_FAILED",
+				Message: err.Error(),
+				Details: "Failed to marshal query results",
+			},
+		}, wrote
+	}
+	result.StreamData = resultJSON
+
+	return result, wrote
+}
+
+// isSQLiteReadStatement checks whether a statement returns rows
+func isSQLiteReadStatement(stmt string) bool {
+	upperStmt := strings.ToUpper(strings.TrimSpace(stmt))
+	for _, prefix := range []string{"SELECT", "WITH", "PRAGMA", "EXPLAIN", "VALUES"} {
+		if strings.HasPrefix(upperStmt, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// scanSQLiteRows scans a query into maps, SQLite values don't have to match the declared column type so they are scanned without conversion
+func scanSQLiteRows(db *gorm.DB, query string, values ...interface{}) ([]map[string]interface{}, error) {
+	rows, err := db.Raw(query, values...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		rowValues := make([]interface{}, len(columns))
+		rowPointers := make([]interface{}, len(columns))
+		for i := range rowValues {
+			rowPointers[i] = &rowValues[i]
+		}
+		if err := rows.Scan(rowPointers...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			row[column] = rowValues[i]
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err()
+}
+
+// processSQLiteRows converts SQLite values into JSON friendly types
+func processSQLiteRows(rows []map[string]interface{}) []map[string]interface{} {
+	processedRows := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		processedRow := make(map[string]interface{}, len(row))
+		for key, val := range row {
+			processedRow[key] = normalizeSQLiteValue(val)
+		}
+		processedRows[i] = processedRow
+	}
+	return processedRows
+}
+
+// normalizeSQLiteValue converts a SQLite value into a JSON friendly type
+func normalizeSQLiteValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case nil, string, int64, float64, bool:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// persistSQLiteFile snapshots the working copy with VACUUM INTO and stores it encrypted
+func persistSQLiteFile(conn *Connection) error {
+	wrapper, err := getDatabaseFileWrapper(conn)
+	if err != nil {
+		return err
+	}
+
+	wrapper.mu.Lock()
+	defer wrapper.mu.Unlock()
+
+	// VACUUM INTO produces a consistent copy even while other connections are reading
+	snapshotPath := wrapper.WorkingPath + ".snapshot"
+	os.Remove(snapshotPath)
+	defer os.Remove(snapshotPath)
+
+	if err := conn.DB.Exec("VACUUM INTO ?", snapshotPath).Error; err != nil {
+		wrapper.Dirty = true
+		return fmt.Errorf("failed to snapshot SQLite file: %v", err)
+	}
+
+	if err := wrapper.persistFrom(snapshotPath); err != nil {
+		wrapper.Dirty = true
+		return err
+	}
+
+	log.Printf("SQLiteDriver -> persistSQLiteFile -> Persisted database file for %s", wrapper.StoragePath)
+	return nil
+}
+
+// StreamQuery runs a read query and returns a cursor over its rows
+func (d *SQLiteDriver) StreamQuery(ctx context.Context, conn *Connection, query string) (ResultCursor, error) {
+	return streamSQLQuery(ctx, conn, query, nil, func(columnType *sql.ColumnType, value interface{}) interface{} {
+		return normalizeSQLiteValue(value)
+	})
+}
+
+// BeginTx starts a new transaction
+func (d *SQLiteDriver) BeginTx(ctx context.Context, conn *Connection) Transaction {
+	if conn == nil || conn.DB == nil {
+		log.Printf("SQLiteDriver.BeginTx: Connection or DB is nil")
+		return nil
+	}
+
+	tx := conn.DB.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		log.Printf("Failed to begin transaction: %v", tx.Error)
+		return nil
+	}
+
+	return &SQLiteTransaction{
+		tx:   tx,
+		conn: conn,
+	}
+}
+
+// GetSchema retrieves the database schema
+func (d *SQLiteDriver) GetSchema(ctx context.Context, db DBExecutor, selectedTables []string) (*SchemaInfo, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("SQLiteDriver -> GetSchema -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	fetcher := NewSQLiteSchemaFetcher(db)
+	return fetcher.GetSchema(ctx, db, selectedTables)
+}
+
+// GetTableChecksum calculates a checksum for a table
+func (d *SQLiteDriver) GetTableChecksum(ctx context.Context, db DBExecutor, table string) (string, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("SQLiteDriver -> GetTableChecksum -> Context cancelled: %v", err)
+		return "", err
+	}
+
+	fetcher := NewSQLiteSchemaFetcher(db)
+	return fetcher.GetTableChecksum(ctx, db, table)
+}
+
+// FetchExampleRecords fetches example records from a table
+func (d *SQLiteDriver) FetchExampleRecords(ctx context.Context, db DBExecutor, table string, limit int) ([]map[string]interface{}, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("SQLiteDriver -> FetchExampleRecords -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	fetcher := NewSQLiteSchemaFetcher(db)
+	return fetcher.FetchExampleRecords(ctx, db, table, limit)
+}
@@ -0,0 +1,411 @@
+package dbmanager
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SQLiteSchemaFetcher implements schema fetching for SQLite
+type SQLiteSchemaFetcher struct {
+	db DBExecutor
+}
+
+// NewSQLiteSchemaFetcher creates a new SQLite schema fetcher
+func NewSQLiteSchemaFetcher(db DBExecutor) SchemaFetcher {
+	return &SQLiteSchemaFetcher{db: db}
+}
+
+// GetSchema retrieves the schema for the selected tables
+func (f *SQLiteSchemaFetcher) GetSchema(ctx context.Context, db DBExecutor, selectedTables []string) (*SchemaInfo, error) {
+	log.Printf("SQLiteSchemaFetcher -> GetSchema -> Starting schema fetch with selected tables: %v", selectedTables)
+
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("SQLiteSchemaFetcher -> GetSchema -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	schema, err := f.FetchSchema(ctx)
+	if err != nil {
+		log.Printf("SQLiteSchemaFetcher -> GetSchema -> Error fetching schema: %v", err)
+		return nil, err
+	}
+
+	log.Printf("SQLiteSchemaFetcher -> GetSchema -> Successfully fetched schema with %d tables", len(schema.Tables))
+
+	return f.filterSchemaForSelectedTables(schema, selectedTables), nil
+}
+
+// FetchSchema retrieves the full database schema
+func (f *SQLiteSchemaFetcher) FetchSchema(ctx context.Context) (*SchemaInfo, error) {
+	schema := &SchemaInfo{
+		Tables:    make(map[string]TableSchema),
+		Views:     make(map[string]ViewSchema),
+		UpdatedAt: time.Now(),
+	}
+
+	tables, err := f.fetchTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range tables {
+		// Check for context cancellation
+		if err := ctx.Err(); err != nil {
+			log.Printf("SQLiteSchemaFetcher -> FetchSchema -> Context cancelled: %v", err)
+			return nil, err
+		}
+
+		tableSchema := TableSchema{
+			Name:        table,
+			Indexes:     make(map[string]IndexInfo),
+			ForeignKeys: make(map[string]ForeignKey),
+			Constraints: make(map[string]ConstraintInfo),
+		}
+
+		columns, primaryKey, err := f.fetchColumns(ctx, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch columns for table %s: %v", table, err)
+		}
+		tableSchema.Columns = columns
+		if len(primaryKey) > 0 {
+			tableSchema.Constraints["PRIMARY"] = ConstraintInfo{
+				Name:    "PRIMARY",
+				Type:    "PRIMARY KEY",
+				Columns: primaryKey,
+			}
+		}
+
+		indexes, uniqueConstraints, err := f.fetchIndexes(ctx, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch indexes for table %s: %v", table, err)
+		}
+		tableSchema.Indexes = indexes
+		for name, constraint := range uniqueConstraints {
+			tableSchema.Constraints[name] = constraint
+		}
+
+		fkeys, err := f.fetchForeignKeys(ctx, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch foreign keys for table %s: %v", table, err)
+		}
+		tableSchema.ForeignKeys = fkeys
+
+		tableSchema.RowCount = f.getTableRowCount(ctx, table)
+
+		// Calculate table schema checksum
+		tableData, _ := json.Marshal(tableSchema)
+		tableSchema.Checksum = fmt.Sprintf("%x", md5.Sum(tableData))
+
+		schema.Tables[table] = tableSchema
+	}
+
+	views, err := f.fetchViews(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch views: %v", err)
+	}
+	schema.Views = views
+
+	// Calculate overall schema checksum
+	schemaData, _ := json.Marshal(schema.Tables)
+	schema.Checksum = fmt.Sprintf("%x", md5.Sum(schemaData))
+
+	log.Printf("SQLiteSchemaFetcher -> FetchSchema -> Fetched %d tables and %d views", len(schema.Tables), len(schema.Views))
+	return schema, nil
+}
+
+// fetchTables retrieves all user tables in the database
+func (f *SQLiteSchemaFetcher) fetchTables(_ context.Context) ([]string, error) {
+	var tables []string
+	query := `
+        SELECT name
+        FROM sqlite_master
+        WHERE type = 'table'
+        AND name NOT LIKE 'sqlite_%'
+        ORDER BY name
+    `
+	if err := f.db.Query(query, &tables); err != nil {
+		log.Printf("SQLiteSchemaFetcher -> fetchTables -> Error: %v", err)
+		return nil, fmt.Errorf("failed to fetch tables: %v", err)
+	}
+	return tables, nil
+}
+
+// fetchColumns retrieves all columns of a table along with its primary key columns
+func (f *SQLiteSchemaFetcher) fetchColumns(_ context.Context, table string) (map[string]ColumnInfo, []string, error) {
+	columns := make(map[string]ColumnInfo)
+
+	var columnRows []map[string]interface{}
+	query := `SELECT name, type, "notnull" AS not_null, dflt_value, pk FROM pragma_table_info(?) ORDER BY cid;`
+	if err := f.db.QueryRows(query, &columnRows, table); err != nil {
+		log.Printf("SQLiteSchemaFetcher -> fetchColumns -> Error for table %s: %v", table, err)
+		return nil, nil, err
+	}
+
+	// The pk value is the 1-based position of the column in the primary key
+	primaryKeyPositions := make(map[int64]string)
+	for _, row := range columnRows {
+		name := sqliteString(row["name"])
+		if name == "" {
+			continue
+		}
+
+		isPrimaryKey := sqliteInt(row["pk"]) > 0
+		columns[name] = ColumnInfo{
+			Name: name,
+			Type: sqliteString(row["type"]),
+			// Primary key columns other than INTEGER PRIMARY KEY can technically hold NULLs, but are never meant to
+			IsNullable:   sqliteInt(row["not_null"]) == 0 && !isPrimaryKey,
+			DefaultValue: sqliteString(row["dflt_value"]),
+		}
+		if isPrimaryKey {
+			primaryKeyPositions[sqliteInt(row["pk"])] = name
+		}
+	}
+
+	positions := make([]int64, 0, len(primaryKeyPositions))
+	for position := range primaryKeyPositions {
+		positions = append(positions, position)
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+
+	primaryKey := make([]string, 0, len(positions))
+	for _, position := range positions {
+		primaryKey = append(primaryKey, primaryKeyPositions[position])
+	}
+
+	return columns, primaryKey, nil
+}
+
+// fetchIndexes retrieves the indexes of a table, unique constraints are returned separately
+func (f *SQLiteSchemaFetcher) fetchIndexes(_ context.Context, table string) (map[string]IndexInfo, map[string]ConstraintInfo, error) {
+	indexes := make(map[string]IndexInfo)
+	constraints := make(map[string]ConstraintInfo)
+
+	var indexRows []map[string]interface{}
+	query := `SELECT name, "unique" AS is_unique, origin FROM pragma_index_list(?);`
+	if err := f.db.QueryRows(query, &indexRows, table); err != nil {
+		log.Printf("SQLiteSchemaFetcher -> fetchIndexes -> Error for table %s: %v", table, err)
+		return indexes, constraints, nil
+	}
+
+	for _, row := range indexRows {
+		name := sqliteString(row["name"])
+		origin := sqliteString(row["origin"])
+		// Primary key indexes are already covered by the PRIMARY constraint
+		if name == "" || origin == "pk" {
+			continue
+		}
+
+		var indexColumns []string
+		columnQuery := `SELECT name FROM pragma_index_info(?) WHERE name IS NOT NULL ORDER BY seqno;`
+		if err := f.db.Query(columnQuery, &indexColumns, name); err != nil {
+			log.Printf("SQLiteSchemaFetcher -> fetchIndexes -> Error fetching columns of index %s: %v", name, err)
+			continue
+		}
+
+		isUnique := sqliteInt(row["is_unique"]) == 1
+		indexes[name] = IndexInfo{
+			Name:     name,
+			Columns:  indexColumns,
+			IsUnique: isUnique,
+		}
+
+		// Indexes created by a UNIQUE clause in CREATE TABLE
+		if origin == "u" {
+			constraints[name] = ConstraintInfo{
+				Name:    name,
+				Type:    "UNIQUE",
+				Columns: indexColumns,
+			}
+		}
+	}
+
+	return indexes, constraints, nil
+}
+
+// fetchForeignKeys retrieves all foreign keys of a table
+func (f *SQLiteSchemaFetcher) fetchForeignKeys(_ context.Context, table string) (map[string]ForeignKey, error) {
+	fkeys := make(map[string]ForeignKey)
+
+	var fkRows []map[string]interface{}
+	query := `SELECT id, seq, "table" AS ref_table, "from" AS column_name, "to" AS ref_column, on_update, on_delete FROM pragma_foreign_key_list(?) ORDER BY id, seq;`
+	if err := f.db.QueryRows(query, &fkRows, table); err != nil {
+		log.Printf("SQLiteSchemaFetcher -> fetchForeignKeys -> Error for table %s: %v", table, err)
+		return fkeys, nil
+	}
+
+	for _, row := range fkRows {
+		columnName := sqliteString(row["column_name"])
+		// SQLite foreign keys are unnamed, composite keys get one entry per column
+		name := fmt.Sprintf("fk_%s_%s", table, columnName)
+		fkeys[name] = ForeignKey{
+			Name:       name,
+			ColumnName: columnName,
+			RefTable:   sqliteString(row["ref_table"]),
+			RefColumn:  sqliteString(row["ref_column"]),
+			OnDelete:   sqliteString(row["on_delete"]),
+			OnUpdate:   sqliteString(row["on_update"]),
+		}
+	}
+	return fkeys, nil
+}
+
+// fetchViews retrieves all views in the database
+func (f *SQLiteSchemaFetcher) fetchViews(_ context.Context) (map[string]ViewSchema, error) {
+	views := make(map[string]ViewSchema)
+
+	var viewRows []map[string]interface{}
+	query := `SELECT name, sql FROM sqlite_master WHERE type = 'view' ORDER BY name;`
+	if err := f.db.QueryRows(query, &viewRows); err != nil {
+		log.Printf("SQLiteSchemaFetcher -> fetchViews -> Error: %v", err)
+		return views, nil
+	}
+
+	for _, row := range viewRows {
+		name := sqliteString(row["name"])
+		views[name] = ViewSchema{
+			Name:       name,
+			Definition: sqliteString(row["sql"]),
+		}
+	}
+	return views, nil
+}
+
+// getTableRowCount gets the number of rows in a table
+func (f *SQLiteSchemaFetcher) getTableRowCount(_ context.Context, table string) int64 {
+	var count int64
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", quoteFileDBIdentifier(table))
+	if err := f.db.Query(query, &count); err != nil {
+		log.Printf("SQLiteSchemaFetcher -> getTableRowCount -> Error for table %s: %v", table, err)
+		return 0
+	}
+	return count
+}
+
+// GetTableChecksum calculates a checksum for a table's structure from its stored DDL
+func (f *SQLiteSchemaFetcher) GetTableChecksum(ctx context.Context, db DBExecutor, table string) (string, error) {
+	var definitions []string
+	query := `SELECT sql FROM sqlite_master WHERE tbl_name = ? AND sql IS NOT NULL ORDER BY type, name;`
+	if err := db.Query(query, &definitions, table); err != nil {
+		return "", fmt.Errorf("failed to get table definition: %v", err)
+	}
+	if len(definitions) == 0 {
+		return "", fmt.Errorf("table %s not found", table)
+	}
+
+	return fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(definitions, "\n")))), nil
+}
+
+// FetchExampleRecords retrieves sample records from a table
+func (f *SQLiteSchemaFetcher) FetchExampleRecords(ctx context.Context, db DBExecutor, table string, limit int) ([]map[string]interface{}, error) {
+	// Check for context cancellation
+	if err := ctx.Err(); err != nil {
+		log.Printf("SQLiteSchemaFetcher -> FetchExampleRecords -> Context cancelled: %v", err)
+		return nil, err
+	}
+
+	// Ensure limit is reasonable
+	if limit <= 0 {
+		limit = 3
+	} else if limit > 10 {
+		limit = 10
+	}
+
+	columnTypes := make(map[string]string)
+	var columnRows []map[string]interface{}
+	if err := db.QueryRows(`SELECT name, type FROM pragma_table_info(?)`, &columnRows, table); err == nil {
+		for _, row := range columnRows {
+			columnTypes[sqliteString(row["name"])] = sqliteString(row["type"])
+		}
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s LIMIT %d", quoteFileDBIdentifier(table), limit)
+	if orderByColumn := findExampleOrderColumn(columnTypes); orderByColumn != "" {
+		query = fmt.Sprintf("SELECT * FROM %s ORDER BY %s DESC LIMIT %d",
+			quoteFileDBIdentifier(table), quoteFileDBIdentifier(orderByColumn), limit)
+	}
+
+	var records []map[string]interface{}
+	if err := db.QueryRows(query, &records); err != nil {
+		log.Printf("SQLiteSchemaFetcher -> FetchExampleRecords -> Error fetching records from table %s: %v", table, err)
+		return nil, fmt.Errorf("failed to fetch example records for table %s: %v", table, err)
+	}
+
+	return processSQLiteRows(records), nil
+}
+
+// FetchTableList retrieves a list of all tables in the database
+func (f *SQLiteSchemaFetcher) FetchTableList(ctx context.Context) ([]string, error) {
+	return f.fetchTables(ctx)
+}
+
+// filterSchemaForSelectedTables filters the schema to only include the selected tables
+func (f *SQLiteSchemaFetcher) filterSchemaForSelectedTables(schema *SchemaInfo, selectedTables []string) *SchemaInfo {
+	// If no tables are selected or "ALL" is selected, return the full schema
+	if len(selectedTables) == 0 || (len(selectedTables) == 1 && selectedTables[0] == "ALL") {
+		return schema
+	}
+
+	selectedTablesMap := make(map[string]bool)
+	for _, table := range selectedTables {
+		selectedTablesMap[table] = true
+	}
+
+	filteredSchema := &SchemaInfo{
+		Tables:    make(map[string]TableSchema),
+		Views:     make(map[string]ViewSchema),
+		UpdatedAt: schema.UpdatedAt,
+	}
+
+	for tableName, tableSchema := range schema.Tables {
+		if selectedTablesMap[tableName] {
+			filteredSchema.Tables[tableName] = tableSchema
+		}
+	}
+
+	// Calculate new checksum for filtered schema
+	schemaData, _ := json.Marshal(filteredSchema.Tables)
+	filteredSchema.Checksum = fmt.Sprintf("%x", md5.Sum(schemaData))
+
+	return filteredSchema
+}
+
+// sqliteString converts a scanned SQLite value to a string
+func sqliteString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// sqliteInt converts a scanned SQLite value to an integer
+func sqliteInt(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case bool:
+		if v {
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+package dbmanager
+
+import (
+	"strings"
+)
+
+// SQLiteSimplifier implements the SchemaSimplifier interface for SQLite
+type SQLiteSimplifier struct{}
+
+// SimplifyDataType converts SQLite declared types to simplified versions for LLM, following SQLite's type affinity rules
+func (s *SQLiteSimplifier) SimplifyDataType(dbType string) string {
+	lowerType := strings.ToLower(dbType)
+
+	switch {
+	case lowerType == "":
+		return "any"
+	case strings.Contains(lowerType, "int"):
+		return "integer"
+	case strings.Contains(lowerType, "bool"):
+		return "boolean"
+	case strings.Contains(lowerType, "date") || strings.Contains(lowerType, "time"):
+		// Dates are stored as TEXT, REAL or INTEGER depending on the application
+		return "datetime"
+	case strings.Contains(lowerType, "char") || strings.Contains(lowerType, "clob") || strings.Contains(lowerType, "text"):
+		return "string"
+	case strings.Contains(lowerType, "blob"):
+		return "binary"
+	case strings.Contains(lowerType, "real") || strings.Contains(lowerType, "floa") || strings.Contains(lowerType, "doub") ||
+		strings.Contains(lowerType, "numeric") || strings.Contains(lowerType, "decimal"):
+		return "number"
+	case strings.Contains(lowerType, "json"):
+		return "json"
+	default:
+		return dbType
+	}
+}
+
+// GetColumnConstraints returns a list of constraints for a column
+func (s *SQLiteSimplifier) GetColumnConstraints(col ColumnInfo, table TableSchema) []string {
+	var constraints []string
+
+	if !col.IsNullable {
+		constraints = append(constraints, "NOT NULL")
+	}
+
+	if col.DefaultValue != "" {
+		constraints = append(constraints, "DEFAULT "+col.DefaultValue)
+	}
+
+	for _, constraint := range table.Constraints {
+		for _, colName := range constraint.Columns {
+			if colName != col.Name {
+				continue
+			}
+			switch constraint.Type {
+			case "PRIMARY KEY":
+				constraints = append(constraints, "PRIMARY KEY")
+			case "UNIQUE":
+				if len(constraint.Columns) == 1 {
+					constraints = append(constraints, "UNIQUE")
+				}
+			}
+		}
+	}
+
+	for _, fk := range table.ForeignKeys {
+		if fk.ColumnName == col.Name {
+			constraints = append(constraints, "FOREIGN KEY REFERENCES "+fk.RefTable+"("+fk.RefColumn+")")
+			break
+		}
+	}
+
+	return constraints
+}
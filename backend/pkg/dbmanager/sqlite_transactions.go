@@ -0,0 +1,59 @@
+package dbmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"neobase-ai/internal/apis/dtos"
+
+	"gorm.io/gorm"
+)
+
+// SQLiteTransaction implements the Transaction interface for SQLite
+type SQLiteTransaction struct {
+	tx    *gorm.DB
+	conn  *Connection
+	wrote bool // Whether the stored file needs to be updated on commit
+}
+
+// ExecuteQuery executes a query within a transaction
+func (t *SQLiteTransaction) ExecuteQuery(ctx context.Context, query string) (*QueryExecutionResult, error) {
+	if t.tx == nil {
+		return &QueryExecutionResult{
+			Error: &dtos.QueryError{
+				Message: "No active transaction",
+				Code:    "TRANSACTION_ERROR",
+			},
+		}, nil
+	}
+
+	result, wrote := executeSQLiteQuery(ctx, t.tx, query)
+	t.wrote = t.wrote || wrote
+	return result, nil
+}
+
+// Commit commits the transaction and persists the database file if it was modified
+func (t *SQLiteTransaction) Commit() error {
+	if t.tx == nil {
+		return fmt.Errorf("no active transaction to commit")
+	}
+	if err := t.tx.Commit().Error; err != nil {
+		return err
+	}
+
+	// The commit already succeeded on the working copy, a failed persist is retried on disconnect
+	if t.wrote {
+		if err := persistSQLiteFile(t.conn); err != nil {
+			log.Printf("SQLiteTransaction -> Commit -> Failed to persist database file: %v", err)
+		}
+	}
+	return nil
+}
+
+// Rollback rolls back the transaction
+func (t *SQLiteTransaction) Rollback() error {
+	if t.tx == nil {
+		return fmt.Errorf("no active transaction to rollback")
+	}
+	return t.tx.Rollback().Error
+}
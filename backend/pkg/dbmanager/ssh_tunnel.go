@@ -0,0 +1,376 @@
+package dbmanager
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"neobase-ai/config"
+	"neobase-ai/internal/constants"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	sshDialTimeout       = 10 * time.Second
+	sshKeepAliveInterval = 30 * time.Second
+	sshAcceptMinDelay    = 5 * time.Millisecond
+	sshAcceptMaxDelay    = 1 * time.Second
+)
+
+// sshTunnelDefaultPorts are the ports used when a connection doesn't specify one, keyed by database type
+var sshTunnelDefaultPorts = map[string]string{
+	constants.DatabaseTypePostgreSQL: "5432",
+	constants.DatabaseTypeYugabyteDB: "5433",
+	constants.DatabaseTypeMySQL:      "3306",
+	constants.DatabaseTypeMSSQL:      "1433",
+	constants.DatabaseTypeClickhouse: "9000",
+	constants.DatabaseTypeMongoDB:    "27017",
+}
+
+// SupportsSSHTunnel reports whether connections of a database type can be routed through an SSH tunnel
+func SupportsSSHTunnel(dbType string) bool {
+	_, ok := sshTunnelDefaultPorts[dbType]
+	return ok
+}
+
+// SSHTunnel forwards connections from a local port to the database through an SSH bastion
+type SSHTunnel struct {
+	sshAddr    string
+	remoteAddr string
+	sshConfig  *ssh.ClientConfig
+	listener   net.Listener
+
+	mu     sync.Mutex
+	client *ssh.Client
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// openSSHTunnel opens a tunnel for the connection and returns a copy of the config pointing at the local end
+func openSSHTunnel(cfg ConnectionConfig) (ConnectionConfig, *SSHTunnel, error) {
+	if !cfg.SSHEnabled {
+		return cfg, nil, nil
+	}
+
+	remotePort, ok := sshTunnelDefaultPorts[cfg.Type]
+	if !ok {
+		return cfg, nil, fmt.Errorf("SSH tunnels are not supported for %s connections", cfg.Type)
+	}
+	if cfg.Port != nil && *cfg.Port != "" {
+		remotePort = *cfg.Port
+	}
+
+	// Both need the client to resolve or reach other hosts, which the tunnel can't forward
+	if cfg.Type == constants.DatabaseTypeMongoDB && strings.Contains(cfg.Host, ".mongodb.net") {
+		return cfg, nil, fmt.Errorf("MongoDB SRV connections can't be used through an SSH tunnel, use a direct host instead")
+	}
+	if cfg.Type == constants.DatabaseTypeMSSQL && strings.Contains(cfg.Host, `\`) {
+		return cfg, nil, fmt.Errorf("SQL Server named instances can't be used through an SSH tunnel, use the instance port instead")
+	}
+
+	sshConfig, err := buildSSHClientConfig(cfg)
+	if err != nil {
+		return cfg, nil, err
+	}
+
+	sshPort := "22"
+	if cfg.SSHPort != nil && *cfg.SSHPort != "" {
+		sshPort = *cfg.SSHPort
+	}
+
+	tunnel := &SSHTunnel{
+		sshAddr:    net.JoinHostPort(*cfg.SSHHost, sshPort),
+		remoteAddr: net.JoinHostPort(cfg.Host, remotePort),
+		sshConfig:  sshConfig,
+		done:       make(chan struct{}),
+	}
+
+	// Connect eagerly so authentication errors are reported to the user
+	if _, err := tunnel.getClient(); err != nil {
+		return cfg, nil, err
+	}
+
+	tunnel.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tunnel.Close()
+		return cfg, nil, fmt.Errorf("failed to open local port for SSH tunnel: %v", err)
+	}
+
+	go tunnel.serve()
+	go tunnel.keepAlive()
+
+	localPort := fmt.Sprintf("%d", tunnel.listener.Addr().(*net.TCPAddr).Port)
+	log.Printf("SSHTunnel -> openSSHTunnel -> Forwarding 127.0.0.1:%s to %s through %s", localPort, tunnel.remoteAddr, tunnel.sshAddr)
+
+	tunneled := cfg
+	tunneled.Host = "127.0.0.1"
+	tunneled.Port = &localPort
+	return tunneled, tunnel, nil
+}
+
+// buildSSHClientConfig builds the SSH authentication from the private key or, without a key, the password
+func buildSSHClientConfig(cfg ConnectionConfig) (*ssh.ClientConfig, error) {
+	if cfg.SSHHost == nil || *cfg.SSHHost == "" {
+		return nil, fmt.Errorf("SSH host is required when SSH tunnel is enabled")
+	}
+	if cfg.SSHUsername == nil || *cfg.SSHUsername == "" {
+		return nil, fmt.Errorf("SSH username is required when SSH tunnel is enabled")
+	}
+
+	var authMethods []ssh.AuthMethod
+	if cfg.SSHPrivateKey != nil && *cfg.SSHPrivateKey != "" {
+		var signer ssh.Signer
+		var err error
+		if cfg.SSHPassphrase != nil && *cfg.SSHPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(*cfg.SSHPrivateKey), []byte(*cfg.SSHPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey([]byte(*cfg.SSHPrivateKey))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SSH private key: %v", err)
+		}
+		authMethods = append(authMethods, ssh.PublicKeys(signer))
+	} else if cfg.SSHPassphrase != nil && *cfg.SSHPassphrase != "" {
+		authMethods = append(authMethods, ssh.Password(*cfg.SSHPassphrase))
+	} else {
+		return nil, fmt.Errorf("SSH private key or passphrase is required when SSH tunnel is enabled")
+	}
+
+	hostKeyCallback, hostKeyAlgorithms, err := sshHostKeyCallback(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ssh.ClientConfig{
+		User:              *cfg.SSHUsername,
+		Auth:              authMethods,
+		HostKeyCallback:   hostKeyCallback,
+		HostKeyAlgorithms: hostKeyAlgorithms,
+		Timeout:           sshDialTimeout,
+	}, nil
+}
+
+// ValidateSSHHostKey checks that the bastion of a connection can be authenticated: with the host key stored on the
+// connection, with SSH_KNOWN_HOSTS_FILE, or not at all when the connection explicitly skips the check
+func ValidateSSHHostKey(hostKey *string, skipHostKey bool) error {
+	if hostKey != nil && strings.TrimSpace(*hostKey) != "" {
+		_, _, err := parseSSHHostKey(*hostKey)
+		return err
+	}
+	if config.Env.SSHKnownHostsFile == "" && !skipHostKey {
+		return fmt.Errorf("SSH host key is required when SSH tunnel is enabled: set the public key or SHA256 fingerprint of the SSH host, or explicitly skip host key verification")
+	}
+	return nil
+}
+
+// sshHostKeyCallback verifies the bastion host key against the key stored on the connection, then against
+// SSH_KNOWN_HOSTS_FILE. Without either, the connection fails unless it explicitly skips the check.
+func sshHostKeyCallback(cfg ConnectionConfig) (ssh.HostKeyCallback, []string, error) {
+	if cfg.SSHHostKey != nil && strings.TrimSpace(*cfg.SSHHostKey) != "" {
+		return parseSSHHostKey(*cfg.SSHHostKey)
+	}
+
+	if config.Env.SSHKnownHostsFile != "" {
+		callback, err := knownhosts.New(config.Env.SSHKnownHostsFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load SSH known hosts file: %v", err)
+		}
+		return callback, nil, nil
+	}
+
+	if cfg.SSHSkipHostKey {
+		return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			log.Printf("SSHTunnel -> Accepting unverified host key %s for %s, host key verification is skipped for this connection", ssh.FingerprintSHA256(key), hostname)
+			return nil
+		}, nil, nil
+	}
+
+	return nil, nil, fmt.Errorf("SSH host key can't be verified: set the public key or SHA256 fingerprint of the SSH host, or SSH_KNOWN_HOSTS_FILE")
+}
+
+// parseSSHHostKey builds the host key check of a key stored on a connection. It is either a SHA256 fingerprint, as
+// printed by ssh-keygen -lf, or a public key in authorized_keys or known_hosts format. A public key also pins the
+// algorithms the server may present, so it can't offer another key type instead.
+func parseSSHHostKey(hostKey string) (ssh.HostKeyCallback, []string, error) {
+	hostKey = strings.TrimSpace(hostKey)
+	if strings.HasPrefix(hostKey, "SHA256:") {
+		expected := strings.TrimRight(hostKey, "=")
+		return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			if subtle.ConstantTimeCompare([]byte(ssh.FingerprintSHA256(key)), []byte(expected)) != 1 {
+				return fmt.Errorf("SSH host key mismatch for %s: got %s", hostname, ssh.FingerprintSHA256(key))
+			}
+			return nil
+		}, nil, nil
+	}
+
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+	if err != nil {
+		// known_hosts lines start with the host names
+		if _, _, knownKey, _, _, knownErr := ssh.ParseKnownHosts([]byte(hostKey)); knownErr == nil {
+			publicKey, err = knownKey, nil
+		}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid SSH host key, expected a public key or a SHA256 fingerprint: %v", err)
+	}
+
+	algorithms := []string{publicKey.Type()}
+	if publicKey.Type() == ssh.KeyAlgoRSA {
+		algorithms = []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+	}
+	return ssh.FixedHostKey(publicKey), algorithms, nil
+}
+
+// getClient returns the SSH client, reconnecting if the previous one was lost
+func (t *SSHTunnel) getClient() (*ssh.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client != nil {
+		return t.client, nil
+	}
+
+	client, err := ssh.Dial("tcp", t.sshAddr, t.sshConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SSH host %s: %v", t.sshAddr, err)
+	}
+	t.client = client
+	return client, nil
+}
+
+// resetClient drops a broken SSH client so the next connection dials a new one
+func (t *SSHTunnel) resetClient(client *ssh.Client) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client == client {
+		t.client.Close()
+		t.client = nil
+	}
+}
+
+// serve accepts local connections until the tunnel is closed. Like net/http, accept errors are retried after a
+// growing delay, so a lasting error such as too many open files doesn't spin. A closed listener tears the tunnel down.
+func (t *SSHTunnel) serve() {
+	var delay time.Duration
+	for {
+		local, err := t.listener.Accept()
+		if err != nil {
+			select {
+			case <-t.done:
+				return
+			default:
+			}
+			if errors.Is(err, net.ErrClosed) {
+				log.Printf("SSHTunnel -> serve -> Listener closed: %v", err)
+				t.Close()
+				return
+			}
+			if delay == 0 {
+				delay = sshAcceptMinDelay
+			} else if delay *= 2; delay > sshAcceptMaxDelay {
+				delay = sshAcceptMaxDelay
+			}
+			log.Printf("SSHTunnel -> serve -> Error accepting connection, retrying in %v: %v", delay, err)
+			select {
+			case <-time.After(delay):
+			case <-t.done:
+				return
+			}
+			continue
+		}
+		delay = 0
+		go t.forward(local)
+	}
+}
+
+// forward copies data between a local connection and the database through the SSH client
+func (t *SSHTunnel) forward(local net.Conn) {
+	defer local.Close()
+
+	client, err := t.getClient()
+	if err != nil {
+		log.Printf("SSHTunnel -> forward -> %v", err)
+		return
+	}
+
+	remote, err := client.Dial("tcp", t.remoteAddr)
+	if err != nil {
+		// The SSH session may have been dropped, retry once with a new one
+		t.resetClient(client)
+		if client, err = t.getClient(); err == nil {
+			remote, err = client.Dial("tcp", t.remoteAddr)
+		}
+		if err != nil {
+			log.Printf("SSHTunnel -> forward -> Failed to reach %s: %v", t.remoteAddr, err)
+			return
+		}
+	}
+	defer remote.Close()
+
+	copyDone := make(chan struct{}, 2)
+	go func() {
+		io.Copy(remote, local)
+		copyDone <- struct{}{}
+	}()
+	go func() {
+		io.Copy(local, remote)
+		copyDone <- struct{}{}
+	}()
+
+	// Closing both ends when either direction finishes stops the other copy
+	select {
+	case <-copyDone:
+	case <-t.done:
+	}
+}
+
+// keepAlive keeps the SSH session from being dropped by idle timeouts on the bastion
+func (t *SSHTunnel) keepAlive() {
+	ticker := time.NewTicker(sshKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+			t.mu.Lock()
+			client := t.client
+			t.mu.Unlock()
+			if client == nil {
+				continue
+			}
+			if _, _, err := client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+				log.Printf("SSHTunnel -> keepAlive -> SSH session to %s lost: %v", t.sshAddr, err)
+				t.resetClient(client)
+			}
+		}
+	}
+}
+
+// Close stops the local listener and closes the SSH session
+func (t *SSHTunnel) Close() error {
+	t.closeOnce.Do(func() {
+		close(t.done)
+		if t.listener != nil {
+			t.listener.Close()
+		}
+		t.mu.Lock()
+		if t.client != nil {
+			t.client.Close()
+			t.client = nil
+		}
+		t.mu.Unlock()
+		log.Printf("SSHTunnel -> Close -> Closed tunnel to %s through %s", t.remoteAddr, t.sshAddr)
+	})
+	return nil
+}
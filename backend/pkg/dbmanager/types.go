@@ -39,8 +39,11 @@ type ConnectionConfig struct {
 	SSHUsername      *string `json:"ssh_username,omitempty"`
 	SSHPrivateKey    *string `json:"ssh_private_key,omitempty"`
 	SSHPassphrase    *string `json:"ssh_passphrase,omitempty"`
+	SSHHostKey       *string `json:"ssh_host_key,omitempty"`            // Public key or SHA256 fingerprint of the bastion
+	SSHSkipHostKey   bool    `json:"ssh_skip_host_key_check,omitempty"` // Accept any bastion host key, an explicit opt-in
 	MongoDBURI       *string `json:"mongodb_uri,omitempty"`
 	SchemaName       string  `json:"schema_name,omitempty"` // For spreadsheet connections
+	ReadOnly         bool    `json:"read_only,omitempty"`   // Open every session read-only, writes are rejected
 }
 
 // Connection represents an active database connection
@@ -67,6 +70,7 @@ type DatabaseDriver interface {
 	Ping(conn *Connection) error
 	IsAlive(conn *Connection) bool
 	ExecuteQuery(ctx context.Context, conn *Connection, query string, queryType string, findCount bool) *QueryExecutionResult
+	StreamQuery(ctx context.Context, conn *Connection, query string) (ResultCursor, error) // Read queries only, see CanStreamQuery
 	BeginTx(ctx context.Context, conn *Connection) Transaction
 	GetSchema(ctx context.Context, db DBExecutor, selectedTables []string) (*SchemaInfo, error)
 	GetTableChecksum(ctx context.Context, db DBExecutor, table string) (string, error)
@@ -87,6 +91,7 @@ type QueryExecutionResult struct {
 	ExecutionTime int                `json:"execution_time"`
 	RowsAffected  int64              `json:"rows_affected,omitempty"`
 	StreamData    []byte             `json:"stream_data,omitempty"`
+	Snapshot      *QuerySnapshot     `json:"-"` // Rows an UPDATE/DELETE changed, to roll it back
 }
 
 // SSEEvent represents a Server-Sent Event
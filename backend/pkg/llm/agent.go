@@ -0,0 +1,475 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"neobase-ai/internal/models"
+	"neobase-ai/internal/utils"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/sashabaranov/go-openai"
+)
+
+// ToolParameter is an argument of an AgentTool
+type ToolParameter struct {
+	Name        string
+	Type        string // "string" or "integer"
+	Description string
+	Required    bool
+}
+
+// AgentTool is a tool the model can call while exploring the database
+type AgentTool struct {
+	Name        string
+	Description string
+	Parameters  []ToolParameter
+}
+
+// ToolCall is a call of a tool requested by the model
+type ToolCall struct {
+	ID        string                 `bson:"id" json:"id"`
+	Name      string                 `bson:"name" json:"name"`
+	Arguments map[string]interface{} `bson:"arguments" json:"arguments"`
+}
+
+// ToolResult is the output of a tool call sent back to the model
+type ToolResult struct {
+	CallID  string `bson:"call_id" json:"call_id"`
+	Name    string `bson:"name" json:"name"`
+	Content string `bson:"content" json:"content"`
+	IsError bool   `bson:"is_error,omitempty" json:"is_error,omitempty"`
+}
+
+// AgentStep is a round of tool calls requested by the model and their results. A step without calls holds the
+// findings the model sent once it was ready to answer.
+type AgentStep struct {
+	Thought string       `bson:"thought,omitempty" json:"thought,omitempty"` // Text the model sent with its calls
+	Calls   []ToolCall   `bson:"calls,omitempty" json:"calls,omitempty"`
+	Results []ToolResult `bson:"results,omitempty" json:"results,omitempty"`
+}
+
+// ToolCallingClient is implemented by clients able to call tools
+type ToolCallingClient interface {
+	// NextToolCalls returns the next step requested by the model after the steps done so far, without results
+	NextToolCalls(ctx context.Context, systemPrompt string, messages []*models.LLMMessage, tools []AgentTool, steps []AgentStep) (*AgentStep, error)
+}
+
+// agentTurn is a message of the chat history sent while the model explores the database
+type agentTurn struct {
+	Role    string // user, assistant or system
+	Content string
+}
+
+// agentHistory converts the chat history, the mode markers of the response prompts aren't needed to explore
+func agentHistory(messages []*models.LLMMessage) []agentTurn {
+	turns := make([]agentTurn, 0, len(messages))
+	for _, msg := range messages {
+		content := ""
+		switch msg.Role {
+		case "user":
+			if userMsg, ok := msg.Content["user_message"].(string); ok {
+				content = userMsg
+			}
+		case "assistant":
+			if assistantMsg, ok := msg.Content["assistant_response"].(map[string]interface{}); ok {
+				content = formatAssistantResponse(assistantMsg)
+			}
+		case "system":
+			if schemaUpdate, ok := msg.Content["schema_update"].(string); ok {
+				content = fmt.Sprintf("Database schema update:\n%s", schemaUpdate)
+			}
+		case "summary":
+			if summary, ok := msg.Content["summary"].(string); ok {
+				content = fmt.Sprintf("Summary of the earlier conversation:\n%s", summary)
+			}
+		}
+		if content != "" {
+			turns = append(turns, agentTurn{Role: mapRole(msg.Role), Content: content})
+		}
+	}
+	return turns
+}
+
+// toolParametersSchema returns the JSON schema of a tool's arguments
+func toolParametersSchema(params []ToolParameter) map[string]interface{} {
+	properties := make(map[string]interface{}, len(params))
+	required := make([]string, 0, len(params))
+	for _, param := range params {
+		properties[param.Name] = map[string]interface{}{
+			"type":        param.Type,
+			"description": param.Description,
+		}
+		if param.Required {
+			required = append(required, param.Name)
+		}
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// parseToolArguments decodes the JSON arguments of a tool call, invalid arguments are left to the tool to report
+func parseToolArguments(raw string) map[string]interface{} {
+	args := make(map[string]interface{})
+	if strings.TrimSpace(raw) == "" {
+		return args
+	}
+	if err := json.Unmarshal([]byte(raw), &args); err != nil {
+		log.Printf("parseToolArguments -> Invalid tool arguments %q: %v", raw, err)
+	}
+	return args
+}
+
+// encodeToolArguments encodes the arguments of a tool call, always as a JSON object
+func encodeToolArguments(args map[string]interface{}) string {
+	if args == nil {
+		return "{}"
+	}
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return "{}"
+	}
+	return string(encoded)
+}
+
+// NextToolCalls returns the tool calls requested by the model after the steps done so far
+func (c *OpenAIClient) NextToolCalls(ctx context.Context, systemPrompt string, messages []*models.LLMMessage, tools []AgentTool, steps []AgentStep) (*AgentStep, error) {
+	// Check if the context is cancelled
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	chatMessages := append([]openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleSystem, Content: systemPrompt}},
+		openAIAgentMessages(messages, steps)...)
+	resp, err := c.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model:               c.model,
+		Messages:            chatMessages,
+		MaxCompletionTokens: c.maxCompletionTokens,
+		Temperature:         float32(c.temperature),
+		Tools:               openAITools(tools),
+	})
+	if err != nil {
+		log.Printf("NextToolCalls -> err: %v", err)
+		return nil, fmt.Errorf("OpenAI API error: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from OpenAI")
+	}
+	return openAIAgentStep(resp.Choices[0].Message), nil
+}
+
+// NextToolCalls returns the tool calls requested by the model after the steps done so far
+func (c *OpenAICompatibleClient) NextToolCalls(ctx context.Context, systemPrompt string, messages []*models.LLMMessage, tools []AgentTool, steps []AgentStep) (*AgentStep, error) {
+	// Check if the context is cancelled
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	req := c.newCompletionRequest(systemPrompt, openAIAgentMessages(messages, steps))
+	req.Tools = openAITools(tools)
+	resp, err := c.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		log.Printf("%s -> NextToolCalls -> err: %v", c.provider, err)
+		return nil, fmt.Errorf("%s API error: %w", c.provider, err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from %s", c.provider)
+	}
+
+	step := openAIAgentStep(resp.Choices[0].Message)
+	// Reasoning models prefix their answer with their thoughts
+	step.Thought = strings.TrimSpace(thinkBlockRegex.ReplaceAllString(step.Thought, ""))
+	return step, nil
+}
+
+func openAITools(tools []AgentTool) []openai.Tool {
+	openAITools := make([]openai.Tool, 0, len(tools))
+	for _, tool := range tools {
+		openAITools = append(openAITools, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  toolParametersSchema(tool.Parameters),
+			},
+		})
+	}
+	return openAITools
+}
+
+// openAIAgentMessages converts the history followed by each step's tool calls and results
+func openAIAgentMessages(messages []*models.LLMMessage, steps []AgentStep) []openai.ChatCompletionMessage {
+	turns := agentHistory(messages)
+	chatMessages := make([]openai.ChatCompletionMessage, 0, len(turns)+len(steps)*2)
+	for _, turn := range turns {
+		chatMessages = append(chatMessages, openai.ChatCompletionMessage{Role: turn.Role, Content: turn.Content})
+	}
+
+	for _, step := range steps {
+		if len(step.Calls) == 0 {
+			continue
+		}
+		toolCalls := make([]openai.ToolCall, 0, len(step.Calls))
+		for _, call := range step.Calls {
+			toolCalls = append(toolCalls, openai.ToolCall{
+				ID:   call.ID,
+				Type: openai.ToolTypeFunction,
+				Function: openai.FunctionCall{
+					Name:      call.Name,
+					Arguments: encodeToolArguments(call.Arguments),
+				},
+			})
+		}
+		chatMessages = append(chatMessages, openai.ChatCompletionMessage{
+			Role:      openai.ChatMessageRoleAssistant,
+			Content:   step.Thought,
+			ToolCalls: toolCalls,
+		})
+		for _, result := range step.Results {
+			chatMessages = append(chatMessages, openai.ChatCompletionMessage{
+				Role:       openai.ChatMessageRoleTool,
+				Content:    result.Content,
+				ToolCallID: result.CallID,
+			})
+		}
+	}
+	return chatMessages
+}
+
+func openAIAgentStep(message openai.ChatCompletionMessage) *AgentStep {
+	step := &AgentStep{Thought: strings.TrimSpace(message.Content)}
+	for _, toolCall := range message.ToolCalls {
+		step.Calls = append(step.Calls, ToolCall{
+			ID:        toolCall.ID,
+			Name:      toolCall.Function.Name,
+			Arguments: parseToolArguments(toolCall.Function.Arguments),
+		})
+	}
+	return step
+}
+
+// NextToolCalls returns the tool calls requested by the model after the steps done so far
+func (c *GeminiClient) NextToolCalls(ctx context.Context, systemPrompt string, messages []*models.LLMMessage, tools []AgentTool, steps []AgentStep) (*AgentStep, error) {
+	// Check if the context is cancelled
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	declarations := make([]*genai.FunctionDeclaration, 0, len(tools))
+	for _, tool := range tools {
+		declaration := &genai.FunctionDeclaration{Name: tool.Name, Description: tool.Description}
+		if len(tool.Parameters) > 0 {
+			declaration.Parameters = geminiParametersSchema(tool.Parameters)
+		}
+		declarations = append(declarations, declaration)
+	}
+
+	model := c.client.GenerativeModel(c.model)
+	model.MaxOutputTokens = utils.ToInt32Ptr(int32(c.maxCompletionTokens))
+	model.SetTemperature(float32(c.temperature))
+	model.SystemInstruction = &genai.Content{
+		Parts: []genai.Part{genai.Text(systemPrompt)},
+	}
+	model.Tools = []*genai.Tool{{FunctionDeclarations: declarations}}
+
+	contents := make([]*genai.Content, 0, len(messages)+len(steps)*2+1)
+	for _, turn := range agentHistory(messages) {
+		role := "user"
+		if turn.Role == "assistant" {
+			role = "model"
+		}
+		contents = append(contents, &genai.Content{Role: role, Parts: []genai.Part{genai.Text(turn.Content)}})
+	}
+	for _, step := range steps {
+		if len(step.Calls) == 0 {
+			continue
+		}
+		callParts := make([]genai.Part, 0, len(step.Calls)+1)
+		if step.Thought != "" {
+			callParts = append(callParts, genai.Text(step.Thought))
+		}
+		for _, call := range step.Calls {
+			callParts = append(callParts, genai.FunctionCall{Name: call.Name, Args: call.Arguments})
+		}
+		resultParts := make([]genai.Part, 0, len(step.Results))
+		for _, result := range step.Results {
+			key := "content"
+			if result.IsError {
+				key = "error"
+			}
+			resultParts = append(resultParts, genai.FunctionResponse{Name: result.Name, Response: map[string]any{key: result.Content}})
+		}
+		contents = append(contents,
+			&genai.Content{Role: "model", Parts: callParts},
+			&genai.Content{Role: "user", Parts: resultParts},
+		)
+	}
+	if len(contents) == 0 || contents[len(contents)-1].Role != "user" {
+		contents = append(contents, &genai.Content{Role: "user", Parts: []genai.Part{genai.Text(geminiResponsePrompt)}})
+	}
+
+	// The last turn is sent, the others are the session's history
+	session := model.StartChat()
+	session.History = contents[:len(contents)-1]
+	result, err := session.SendMessage(ctx, contents[len(contents)-1].Parts...)
+	if err != nil {
+		log.Printf("Gemini NextToolCalls -> err: %v", err)
+		return nil, fmt.Errorf("gemini API error: %w", err)
+	}
+	if len(result.Candidates) == 0 || result.Candidates[0].Content == nil {
+		return nil, fmt.Errorf("no response from Gemini")
+	}
+
+	step := &AgentStep{}
+	var thought strings.Builder
+	for _, part := range result.Candidates[0].Content.Parts {
+		switch p := part.(type) {
+		case genai.Text:
+			thought.WriteString(string(p))
+		case genai.FunctionCall:
+			// Gemini doesn't identify its calls
+			step.Calls = append(step.Calls, ToolCall{
+				ID:        fmt.Sprintf("call_%d_%d", len(steps), len(step.Calls)),
+				Name:      p.Name,
+				Arguments: p.Args,
+			})
+		}
+	}
+	step.Thought = strings.TrimSpace(thought.String())
+	return step, nil
+}
+
+func geminiParametersSchema(params []ToolParameter) *genai.Schema {
+	schema := &genai.Schema{
+		Type:       genai.TypeObject,
+		Properties: make(map[string]*genai.Schema, len(params)),
+	}
+	for _, param := range params {
+		paramType := genai.TypeString
+		if param.Type == "integer" {
+			paramType = genai.TypeInteger
+		}
+		schema.Properties[param.Name] = &genai.Schema{Type: paramType, Description: param.Description}
+		if param.Required {
+			schema.Required = append(schema.Required, param.Name)
+		}
+	}
+	return schema
+}
+
+// NextToolCalls returns the tool calls requested by the model after the steps done so far
+func (c *AnthropicClient) NextToolCalls(ctx context.Context, systemPrompt string, messages []*models.LLMMessage, tools []AgentTool, steps []AgentStep) (*AgentStep, error) {
+	anthropicTools := make([]anthropicTool, 0, len(tools))
+	for _, tool := range tools {
+		inputSchema, err := json.Marshal(toolParametersSchema(tool.Parameters))
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal tool schema: %v", err)
+		}
+		anthropicTools = append(anthropicTools, anthropicTool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: inputSchema,
+		})
+	}
+
+	anthropicMessages := make([]anthropicMessage, 0, len(messages)+len(steps)*2+1)
+	for _, turn := range agentHistory(messages) {
+		anthropicMessages = appendAnthropicMessage(anthropicMessages, turn.Role, turn.Content)
+	}
+	anthropicMessages = ensureAnthropicUserTurn(anthropicMessages, "Please explore the database for my last request.")
+	for _, step := range steps {
+		if len(step.Calls) == 0 {
+			continue
+		}
+		callBlocks := make([]anthropicContentBlock, 0, len(step.Calls)+1)
+		if step.Thought != "" {
+			callBlocks = append(callBlocks, anthropicContentBlock{Type: "text", Text: step.Thought})
+		}
+		for _, call := range step.Calls {
+			callBlocks = append(callBlocks, anthropicContentBlock{
+				Type:  "tool_use",
+				ID:    call.ID,
+				Name:  call.Name,
+				Input: json.RawMessage(encodeToolArguments(call.Arguments)),
+			})
+		}
+		resultBlocks := make([]anthropicContentBlock, 0, len(step.Results))
+		for _, result := range step.Results {
+			resultBlocks = append(resultBlocks, anthropicContentBlock{
+				Type:      "tool_result",
+				ToolUseID: result.CallID,
+				Content:   result.Content,
+				IsError:   result.IsError,
+			})
+		}
+		anthropicMessages = append(anthropicMessages,
+			anthropicMessage{Role: "assistant", Blocks: callBlocks},
+			anthropicMessage{Role: "user", Blocks: resultBlocks},
+		)
+	}
+
+	anthropicResp, err := c.sendRequest(ctx, anthropicRequest{
+		Model:       c.model,
+		System:      systemPrompt,
+		Messages:    anthropicMessages,
+		MaxTokens:   c.maxCompletionTokens,
+		Temperature: c.temperature,
+		Tools:       anthropicTools,
+	})
+	if err != nil {
+		log.Printf("ANTHROPIC -> NextToolCalls -> err: %v", err)
+		return nil, err
+	}
+
+	step := &AgentStep{}
+	var thought strings.Builder
+	for _, block := range anthropicResp.Content {
+		switch block.Type {
+		case "text":
+			thought.WriteString(block.Text)
+		case "tool_use":
+			args := make(map[string]interface{})
+			if len(block.Input) > 0 {
+				if err := json.Unmarshal(block.Input, &args); err != nil {
+					log.Printf("ANTHROPIC -> NextToolCalls -> Invalid tool input %s: %v", string(block.Input), err)
+				}
+			}
+			step.Calls = append(step.Calls, ToolCall{ID: block.ID, Name: block.Name, Arguments: args})
+		}
+	}
+	step.Thought = strings.TrimSpace(thought.String())
+	return step, nil
+}
+
+// NextToolCalls asks the clients of the chain able to call tools
+func (c *FallbackClient) NextToolCalls(ctx context.Context, systemPrompt string, messages []*models.LLMMessage, tools []AgentTool, steps []AgentStep) (*AgentStep, error) {
+	entries := make([]FallbackEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		if _, ok := entry.Client.(ToolCallingClient); ok {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("none of the LLM providers supports tool calling")
+	}
+	toolClient := &FallbackClient{entries: entries, policy: c.policy, breakers: c.breakers}
+
+	var step *AgentStep
+	_, err := toolClient.generate(ctx, "NextToolCalls", func(client Client) (string, error) {
+		next, err := client.(ToolCallingClient).NextToolCalls(ctx, systemPrompt, messages, tools, steps)
+		if err != nil {
+			return "", err
+		}
+		step = next
+		return "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return step, nil
+}
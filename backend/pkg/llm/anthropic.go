@@ -0,0 +1,407 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"neobase-ai/internal/constants"
+	"neobase-ai/internal/models"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	anthropicResponseTool        = "neobase_response"
+	anthropicRecommendationsTool = "neobase_recommendations"
+	anthropicRequestTimeout      = 5 * time.Minute
+)
+
+type AnthropicClient struct {
+	httpClient          *http.Client
+	apiKey              string
+	baseURL             string
+	model               string
+	maxCompletionTokens int
+	temperature         float64
+	contextLimit        int
+	DBConfigs           []LLMDBConfig
+}
+
+// anthropicMessage is a single turn of the Messages API conversation
+type anthropicMessage struct {
+	Role    string                  `json:"role"`
+	Content string                  `json:"content"`
+	Blocks  []anthropicContentBlock `json:"-"` // Sent instead of Content when set, e.g. tool calls and their results
+}
+
+func (m anthropicMessage) MarshalJSON() ([]byte, error) {
+	if len(m.Blocks) == 0 {
+		type textMessage anthropicMessage
+		return json.Marshal(textMessage(m))
+	}
+	return json.Marshal(struct {
+		Role    string                  `json:"role"`
+		Content []anthropicContentBlock `json:"content"`
+	}{Role: m.Role, Content: m.Blocks})
+}
+
+// anthropicTool describes a tool whose input schema is the structured response we expect
+type anthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+type anthropicRequest struct {
+	Model       string               `json:"model"`
+	System      string               `json:"system,omitempty"`
+	Messages    []anthropicMessage   `json:"messages"`
+	MaxTokens   int                  `json:"max_tokens"`
+	Temperature float64              `json:"temperature"`
+	Tools       []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice  *anthropicToolChoice `json:"tool_choice,omitempty"`
+}
+
+type anthropicContentBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+	IsError   bool            `json:"is_error,omitempty"`
+}
+
+type anthropicResponse struct {
+	Content    []anthropicContentBlock `json:"content"`
+	StopReason string                  `json:"stop_reason"`
+	Error      *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func NewAnthropicClient(config Config) (*AnthropicClient, error) {
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("anthropic API key is required")
+	}
+
+	model := config.Model
+	if model == "" {
+		model = constants.AnthropicModel
+	}
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = constants.AnthropicBaseURL
+	}
+	maxCompletionTokens := config.MaxCompletionTokens
+	if maxCompletionTokens <= 0 {
+		maxCompletionTokens = constants.AnthropicMaxCompletionTokens
+	}
+
+	return &AnthropicClient{
+		httpClient:          &http.Client{Timeout: anthropicRequestTimeout},
+		apiKey:              config.APIKey,
+		baseURL:             strings.TrimSuffix(baseURL, "/"),
+		model:               model,
+		maxCompletionTokens: maxCompletionTokens,
+		temperature:         config.Temperature,
+		contextLimit:        config.ContextLimit,
+		DBConfigs:           config.DBConfigs,
+	}, nil
+}
+
+func (c *AnthropicClient) GenerateResponse(ctx context.Context, messages []*models.LLMMessage, dbType string, nonTechMode bool) (string, error) {
+	// Check if the context is cancelled
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	// Get the system prompt with non-tech mode if enabled
+	systemPrompt := constants.GetSystemPrompt(constants.Anthropic, dbType, nonTechMode)
+	responseSchema := ""
+
+	for _, dbConfig := range c.DBConfigs {
+		if dbConfig.DBType == dbType {
+			responseSchema = dbConfig.Schema.(string)
+			break
+		}
+	}
+	if responseSchema == "" {
+		responseSchema = constants.GetLLMResponseSchema(constants.Anthropic, dbType).(string)
+	}
+
+	anthropicMessages := make([]anthropicMessage, 0, len(messages)+1)
+	for _, msg := range messages {
+		content := ""
+
+		// Handle different message types
+		switch msg.Role {
+		case "user":
+			if userMsg, ok := msg.Content["user_message"].(string); ok {
+				content = userMsg
+				// Add non-tech mode context if the mode differs from current request
+				if msg.NonTechMode != nonTechMode {
+					if msg.NonTechMode {
+						content = "[This message was sent in NON-TECHNICAL MODE] " + content
+					} else {
+						content = "[This message was sent in TECHNICAL MODE] " + content
+					}
+				}
+			}
+		case "assistant":
+			if assistantMsg, ok := msg.Content["assistant_response"].(map[string]interface{}); ok {
+				content = formatAssistantResponse(assistantMsg)
+				// Add non-tech mode context if the mode differs from current request
+				if msg.NonTechMode != nonTechMode {
+					if msg.NonTechMode {
+						content = "[This response was generated in NON-TECHNICAL MODE]\n" + content
+					} else {
+						content = "[This response was generated in TECHNICAL MODE]\n" + content
+					}
+				}
+			}
+		case "system":
+			if schemaUpdate, ok := msg.Content["schema_update"].(string); ok {
+				content = fmt.Sprintf("Database schema update:\n%s", schemaUpdate)
+			}
+		case "summary":
+			if summary, ok := msg.Content["summary"].(string); ok {
+				content = fmt.Sprintf("Summary of the earlier conversation:\n%s", summary)
+			}
+		}
+
+		anthropicMessages = appendAnthropicMessage(anthropicMessages, msg.Role, content)
+	}
+	anthropicMessages = ensureAnthropicUserTurn(anthropicMessages, "Please provide a response based on our conversation history.")
+
+	responseText, err := c.createMessage(ctx, systemPrompt, anthropicMessages, anthropicTool{
+		Name:        anthropicResponseTool,
+		Description: "A friendly AI Response/Explanation or clarification question (Must Send this)",
+		InputSchema: json.RawMessage(responseSchema),
+	})
+	if err != nil {
+		log.Printf("ANTHROPIC -> GenerateResponse -> err: %v", err)
+		return "", err
+	}
+
+	log.Printf("ANTHROPIC -> GenerateResponse -> resp: %v", responseText)
+	// Validate response against schema
+	var llmResponse constants.LLMResponse
+	if err := json.Unmarshal([]byte(responseText), &llmResponse); err != nil {
+		return "", fmt.Errorf("invalid response format: %v", err)
+	}
+
+	return responseText, nil
+}
+
+// GenerateRecommendations generates query recommendations using a different prompt and schema
+func (c *AnthropicClient) GenerateRecommendations(ctx context.Context, messages []*models.LLMMessage, dbType string) (string, error) {
+	// Check if the context is cancelled
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	systemPrompt := constants.GetRecommendationsPrompt(constants.Anthropic)
+	responseSchema := constants.GetRecommendationsSchema(constants.Anthropic).(string)
+
+	anthropicMessages := make([]anthropicMessage, 0, len(messages)+1)
+	for _, msg := range messages {
+		content := ""
+
+		// Handle different message types
+		switch msg.Role {
+		case "user":
+			if userMsg, ok := msg.Content["user_message"].(string); ok {
+				content = userMsg
+			}
+		case "assistant":
+			if assistantMsg, ok := msg.Content["assistant_response"].(map[string]interface{}); ok {
+				content = formatAssistantResponse(assistantMsg)
+			}
+		case "system":
+			if schemaUpdate, ok := msg.Content["schema_update"].(string); ok {
+				content = fmt.Sprintf("Database schema update:\n%s", schemaUpdate)
+			}
+		case "summary":
+			if summary, ok := msg.Content["summary"].(string); ok {
+				content = fmt.Sprintf("Summary of the earlier conversation:\n%s", summary)
+			}
+		}
+
+		anthropicMessages = appendAnthropicMessage(anthropicMessages, msg.Role, content)
+	}
+	anthropicMessages = ensureAnthropicUserTurn(anthropicMessages, "Please provide query recommendations based on our conversation history.")
+
+	responseText, err := c.createMessage(ctx, systemPrompt, anthropicMessages, anthropicTool{
+		Name:        anthropicRecommendationsTool,
+		Description: "Query recommendations response",
+		InputSchema: json.RawMessage(responseSchema),
+	})
+	if err != nil {
+		log.Printf("ANTHROPIC -> GenerateRecommendations -> err: %v", err)
+		return "", err
+	}
+
+	log.Printf("ANTHROPIC -> GenerateRecommendations -> resp: %v", responseText)
+	return responseText, nil
+}
+
+// GenerateText returns a plain text completion for a single prompt
+func (c *AnthropicClient) GenerateText(ctx context.Context, systemPrompt string, prompt string) (string, error) {
+	anthropicResp, err := c.sendRequest(ctx, anthropicRequest{
+		Model:       c.model,
+		System:      systemPrompt,
+		Messages:    []anthropicMessage{{Role: "user", Content: prompt}},
+		MaxTokens:   c.maxCompletionTokens,
+		Temperature: c.temperature,
+	})
+	if err != nil {
+		log.Printf("ANTHROPIC -> GenerateText -> err: %v", err)
+		return "", err
+	}
+
+	var responseText strings.Builder
+	for _, block := range anthropicResp.Content {
+		if block.Type == "text" {
+			responseText.WriteString(block.Text)
+		}
+	}
+	return strings.TrimSpace(responseText.String()), nil
+}
+
+// createMessage calls the Messages API, forcing the model to answer through the given tool so the
+// tool input follows the response schema. A plain text answer is accepted if it is valid JSON.
+func (c *AnthropicClient) createMessage(ctx context.Context, systemPrompt string, messages []anthropicMessage, tool anthropicTool) (string, error) {
+	anthropicResp, err := c.sendRequest(ctx, anthropicRequest{
+		Model:       c.model,
+		System:      systemPrompt,
+		Messages:    messages,
+		MaxTokens:   c.maxCompletionTokens,
+		Temperature: c.temperature,
+		Tools:       []anthropicTool{tool},
+		ToolChoice:  &anthropicToolChoice{Type: "tool", Name: tool.Name},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// The structured response is the input of the forced tool call
+	for _, block := range anthropicResp.Content {
+		if block.Type == "tool_use" && block.Name == tool.Name && len(block.Input) > 0 {
+			return string(block.Input), nil
+		}
+	}
+
+	// Fall back to a JSON text answer
+	for _, block := range anthropicResp.Content {
+		if block.Type != "text" {
+			continue
+		}
+		responseText := strings.ReplaceAll(block.Text, "```json", "")
+		responseText = strings.TrimSpace(strings.ReplaceAll(responseText, "```", ""))
+		if json.Valid([]byte(responseText)) {
+			return responseText, nil
+		}
+	}
+
+	return "", fmt.Errorf("no response from Anthropic")
+}
+
+// sendRequest posts a request to the Messages API
+func (c *AnthropicClient) sendRequest(ctx context.Context, request anthropicRequest) (*anthropicResponse, error) {
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal anthropic request: %v", err)
+	}
+
+	// Check if the context is cancelled
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/messages", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create anthropic request: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", c.apiKey)
+	httpReq.Header.Set("anthropic-version", constants.AnthropicAPIVersion)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic API error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read anthropic response: %v", err)
+	}
+
+	var anthropicResp anthropicResponse
+	if err := json.Unmarshal(respBody, &anthropicResp); err != nil {
+		return nil, &APIError{Provider: "anthropic", StatusCode: resp.StatusCode, Message: fmt.Sprintf("status %d: %s", resp.StatusCode, string(respBody))}
+	}
+	if resp.StatusCode != http.StatusOK {
+		if anthropicResp.Error != nil {
+			return nil, &APIError{Provider: "anthropic", StatusCode: resp.StatusCode, Message: anthropicResp.Error.Type + ": " + anthropicResp.Error.Message}
+		}
+		return nil, &APIError{Provider: "anthropic", StatusCode: resp.StatusCode}
+	}
+	if anthropicResp.StopReason == "max_tokens" {
+		return nil, fmt.Errorf("anthropic response was truncated, increase ANTHROPIC_MAX_COMPLETION_TOKENS")
+	}
+	return &anthropicResp, nil
+}
+
+// appendAnthropicMessage adds a turn, merging consecutive turns of the same role as the API expects alternating roles
+func appendAnthropicMessage(messages []anthropicMessage, role string, content string) []anthropicMessage {
+	if content == "" {
+		return messages
+	}
+
+	// Schema updates are sent as user turns since the system prompt is separate
+	role = mapRole(role)
+	if role == "system" {
+		role = "user"
+	}
+
+	if len(messages) > 0 && messages[len(messages)-1].Role == role {
+		messages[len(messages)-1].Content += "\n\n" + content
+		return messages
+	}
+	// The conversation must start with a user turn
+	if len(messages) == 0 && role == "assistant" {
+		return messages
+	}
+
+	return append(messages, anthropicMessage{Role: role, Content: content})
+}
+
+// ensureAnthropicUserTurn makes sure the conversation ends with a user turn the model can answer
+func ensureAnthropicUserTurn(messages []anthropicMessage, prompt string) []anthropicMessage {
+	if len(messages) == 0 || messages[len(messages)-1].Role != "user" {
+		return append(messages, anthropicMessage{Role: "user", Content: prompt})
+	}
+	return messages
+}
+
+func (c *AnthropicClient) GetModelInfo() ModelInfo {
+	return ModelInfo{
+		Name:                c.model,
+		Provider:            "anthropic",
+		MaxCompletionTokens: c.maxCompletionTokens,
+		ContextLimit:        c.contextLimit,
+	}
+}
@@ -0,0 +1,185 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"neobase-ai/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestAnthropicClient returns a client talking to a fake Messages API that replies with the given status and body,
+// and records the last request it received
+func newTestAnthropicClient(t *testing.T, status int, body string) (*AnthropicClient, *anthropicRequest) {
+	t.Helper()
+	received := &anthropicRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "test-key" {
+			t.Errorf("missing api key header")
+		}
+		if r.Header.Get("anthropic-version") == "" {
+			t.Errorf("missing anthropic-version header")
+		}
+		reqBody, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(reqBody, received); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewAnthropicClient(Config{APIKey: "test-key", BaseURL: server.URL + "/", Model: "test-model"})
+	if err != nil {
+		t.Fatalf("NewAnthropicClient: %v", err)
+	}
+	return client, received
+}
+
+func testAnthropicMessages() []*models.LLMMessage {
+	return []*models.LLMMessage{
+		{Role: "user", Content: map[string]interface{}{"user_message": "How many users signed up today?"}},
+	}
+}
+
+func TestAnthropicGenerateResponseForcedToolUse(t *testing.T) {
+	body := `{
+		"content": [
+			{"type": "text", "text": "Let me write the query."},
+			{"type": "tool_use", "id": "toolu_1", "name": "neobase_response", "input": {"assistantMessage": "Here is the count", "queries": [{"query": "SELECT COUNT(*) FROM users", "isCritical": false, "canRollback": false}]}}
+		],
+		"stop_reason": "tool_use"
+	}`
+	client, received := newTestAnthropicClient(t, http.StatusOK, body)
+
+	resp, err := client.GenerateResponse(context.Background(), testAnthropicMessages(), "postgresql", false)
+	if err != nil {
+		t.Fatalf("GenerateResponse: %v", err)
+	}
+
+	var llmResponse struct {
+		AssistantMessage string `json:"assistantMessage"`
+		Queries          []struct {
+			Query string `json:"query"`
+		} `json:"queries"`
+	}
+	if err := json.Unmarshal([]byte(resp), &llmResponse); err != nil {
+		t.Fatalf("response is not JSON: %v", err)
+	}
+	if llmResponse.AssistantMessage != "Here is the count" || len(llmResponse.Queries) != 1 {
+		t.Errorf("unexpected response %s", resp)
+	}
+
+	if received.ToolChoice == nil || received.ToolChoice.Type != "tool" || received.ToolChoice.Name != anthropicResponseTool {
+		t.Errorf("tool choice not forced: %+v", received.ToolChoice)
+	}
+	if len(received.Tools) != 1 || received.Tools[0].Name != anthropicResponseTool || !json.Valid(received.Tools[0].InputSchema) {
+		t.Errorf("unexpected tools %+v", received.Tools)
+	}
+	if received.Model != "test-model" || received.System == "" {
+		t.Errorf("unexpected model %q or empty system prompt", received.Model)
+	}
+}
+
+func TestAnthropicGenerateResponseTextFallback(t *testing.T) {
+	body := `{
+		"content": [
+			{"type": "text", "text": "` + "```json\\n" + `{\"assistantMessage\": \"No query needed\"}` + "\\n```" + `"}
+		],
+		"stop_reason": "end_turn"
+	}`
+	client, _ := newTestAnthropicClient(t, http.StatusOK, body)
+
+	resp, err := client.GenerateResponse(context.Background(), testAnthropicMessages(), "postgresql", false)
+	if err != nil {
+		t.Fatalf("GenerateResponse: %v", err)
+	}
+	if resp != `{"assistantMessage": "No query needed"}` {
+		t.Errorf("unexpected response %q", resp)
+	}
+}
+
+func TestAnthropicGenerateResponseTextWithoutJSON(t *testing.T) {
+	body := `{"content": [{"type": "text", "text": "I cannot help with that."}], "stop_reason": "end_turn"}`
+	client, _ := newTestAnthropicClient(t, http.StatusOK, body)
+
+	if _, err := client.GenerateResponse(context.Background(), testAnthropicMessages(), "postgresql", false); err == nil {
+		t.Fatal("expected an error for a text answer that is not JSON")
+	}
+}
+
+func TestAnthropicAPIErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantMessage string
+	}{
+		{
+			name:        "rate limited",
+			status:      http.StatusTooManyRequests,
+			body:        `{"type": "error", "error": {"type": "rate_limit_error", "message": "Number of requests has exceeded your rate limit"}}`,
+			wantMessage: "rate_limit_error: Number of requests has exceeded your rate limit",
+		},
+		{
+			name:        "overloaded",
+			status:      529,
+			body:        `{"type": "error", "error": {"type": "overloaded_error", "message": "Overloaded"}}`,
+			wantMessage: "overloaded_error: Overloaded",
+		},
+		{
+			name:        "error without body",
+			status:      http.StatusInternalServerError,
+			body:        `{}`,
+			wantMessage: "",
+		},
+		{
+			name:        "non JSON body",
+			status:      http.StatusBadGateway,
+			body:        `<html>Bad Gateway</html>`,
+			wantMessage: "status 502: <html>Bad Gateway</html>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTestAnthropicClient(t, tt.status, tt.body)
+
+			_, err := client.GenerateResponse(context.Background(), testAnthropicMessages(), "postgresql", false)
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected an APIError, got %v", err)
+			}
+			if apiErr.Provider != "anthropic" || apiErr.StatusCode != tt.status || apiErr.Message != tt.wantMessage {
+				t.Errorf("unexpected error %+v", apiErr)
+			}
+		})
+	}
+}
+
+func TestAnthropicMaxTokens(t *testing.T) {
+	body := `{
+		"content": [
+			{"type": "tool_use", "id": "toolu_1", "name": "neobase_response", "input": {"assistantMessage": "Here is the"}}
+		],
+		"stop_reason": "max_tokens"
+	}`
+	client, _ := newTestAnthropicClient(t, http.StatusOK, body)
+
+	_, err := client.GenerateResponse(context.Background(), testAnthropicMessages(), "postgresql", false)
+	if err == nil || !strings.Contains(err.Error(), "truncated") {
+		t.Fatalf("expected a truncation error, got %v", err)
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		t.Errorf("a truncated response must not be reported as an API error: %v", err)
+	}
+}
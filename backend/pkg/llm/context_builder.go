@@ -0,0 +1,180 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"neobase-ai/internal/models"
+	"strings"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Per message overhead of the role and separators added by the chat formats
+const messageTokenOverhead = 4
+
+// charsPerToken approximates each provider's tokenizer on English text and code, erring towards more tokens
+var charsPerToken = map[string]float64{
+	"openai":            4,
+	"gemini":            4,
+	"anthropic":         3.5,
+	"ollama":            3.5,
+	"openai-compatible": 3.5,
+}
+
+// EstimateTokens estimates the number of tokens of the text for the provider's tokenizer
+func EstimateTokens(provider string, text string) int {
+	ratio, ok := charsPerToken[provider]
+	if !ok {
+		ratio = 3.5
+	}
+
+	// Characters outside ASCII (CJK, emojis...) are usually a token or more each
+	asciiChars, otherChars := 0, 0
+	for _, char := range text {
+		if char < utf8.RuneSelf {
+			asciiChars++
+		} else {
+			otherChars++
+		}
+	}
+	return int(float64(asciiChars)/ratio+0.5) + otherChars
+}
+
+// EstimateMessageTokens estimates the tokens a message takes once formatted by the clients
+func EstimateMessageTokens(provider string, msg *models.LLMMessage) int {
+	return EstimateTokens(provider, messageText(msg)) + messageTokenOverhead
+}
+
+// messageText returns the text the clients send for a message
+func messageText(msg *models.LLMMessage) string {
+	switch msg.Role {
+	case "user":
+		userMsg, _ := msg.Content["user_message"].(string)
+		return userMsg
+	case "assistant":
+		if assistantMsg, ok := msg.Content["assistant_response"].(map[string]interface{}); ok {
+			return formatAssistantResponse(assistantMsg)
+		}
+	case "system":
+		if schemaUpdate, ok := msg.Content["schema_update"].(string); ok {
+			return fmt.Sprintf("Database schema update:\n%s", schemaUpdate)
+		}
+	case "summary":
+		if summary, ok := msg.Content["summary"].(string); ok {
+			return fmt.Sprintf("Summary of the earlier conversation:\n%s", summary)
+		}
+	}
+	return ""
+}
+
+// ContextWindow is the part of the conversation that fits the model's context
+type ContextWindow struct {
+	System  []*models.LLMMessage // Schema messages, always kept
+	Kept    []*models.LLMMessage // Pinned and most recent messages in conversation order
+	Dropped []*models.LLMMessage // Older messages that didn't fit, in conversation order
+}
+
+// BuildContextWindow keeps the schema, the pinned messages and as many recent messages as fit the token budget.
+// The budget must already exclude the system prompt, the completion and summaryReserve is set aside for the
+// summary of the dropped messages. The last message is always kept as it is the one being answered.
+func BuildContextWindow(provider string, messages []*models.LLMMessage, pinned map[primitive.ObjectID]bool, budget int, summaryReserve int) ContextWindow {
+	window := ContextWindow{}
+	used := 0
+	var turns []*models.LLMMessage
+	for _, msg := range messages {
+		switch {
+		case msg.Role == "summary":
+			// Summaries are handled by the caller
+		case msg.Role == "system":
+			window.System = append(window.System, msg)
+			used += EstimateMessageTokens(provider, msg)
+		default:
+			if pinned[msg.MessageID] {
+				used += EstimateMessageTokens(provider, msg)
+			}
+			turns = append(turns, msg)
+		}
+	}
+
+	// Everything fits, no summary needed
+	total := used
+	for _, msg := range turns {
+		if !pinned[msg.MessageID] {
+			total += EstimateMessageTokens(provider, msg)
+		}
+	}
+	if total <= budget {
+		window.Kept = turns
+		return window
+	}
+
+	// Keep the most recent messages that fit next to the summary
+	remaining := budget - used - summaryReserve
+	firstKept := len(turns)
+	for i := len(turns) - 1; i >= 0; i-- {
+		if pinned[turns[i].MessageID] {
+			continue
+		}
+		tokens := EstimateMessageTokens(provider, turns[i])
+		if tokens > remaining && i != len(turns)-1 {
+			break
+		}
+		remaining -= tokens
+		firstKept = i
+	}
+
+	for i, msg := range turns {
+		if i >= firstKept || pinned[msg.MessageID] {
+			window.Kept = append(window.Kept, msg)
+		} else {
+			window.Dropped = append(window.Dropped, msg)
+		}
+	}
+	return window
+}
+
+// SummaryTranscript renders messages as a compact transcript to be summarized, leaving out example results
+func SummaryTranscript(messages []*models.LLMMessage) string {
+	var transcript strings.Builder
+	for _, msg := range messages {
+		switch msg.Role {
+		case "user":
+			if userMsg, ok := msg.Content["user_message"].(string); ok {
+				transcript.WriteString("User: " + userMsg + "\n\n")
+			}
+		case "assistant":
+			assistantMsg, ok := msg.Content["assistant_response"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if text, ok := assistantMsg["assistantMessage"].(string); ok {
+				transcript.WriteString("Assistant: " + text + "\n")
+			}
+			if queries, ok := assistantMsg["queries"].([]interface{}); ok {
+				for _, query := range queries {
+					if queryMap, ok := query.(map[string]interface{}); ok {
+						if queryText, ok := queryMap["query"].(string); ok {
+							transcript.WriteString("Query: " + queryText + "\n")
+						}
+					}
+				}
+			}
+			transcript.WriteString("\n")
+		}
+	}
+	return transcript.String()
+}
+
+// ContextFingerprint identifies the content of messages, so a summary of them can be detected as stale after edits
+func ContextFingerprint(messages []*models.LLMMessage) string {
+	hash := sha256.New()
+	for _, msg := range messages {
+		content, _ := json.Marshal(msg.Content)
+		hash.Write([]byte(msg.ID.Hex()))
+		hash.Write(content)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,93 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/sashabaranov/go-openai"
+)
+
+// Texts sent per embeddings request
+const embeddingBatchSize = 100
+
+// Embedder is implemented by clients whose provider offers an embeddings API
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	EmbeddingModel() string
+}
+
+func (c *OpenAIClient) EmbeddingModel() string {
+	return c.embeddingModel
+}
+
+// Embed returns the embedding of each text
+func (c *OpenAIClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return embedWithOpenAI(ctx, c.client, c.embeddingModel, "OpenAI", texts)
+}
+
+func (c *OpenAICompatibleClient) EmbeddingModel() string {
+	return c.embeddingModel
+}
+
+// Embed returns the embedding of each text
+func (c *OpenAICompatibleClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return embedWithOpenAI(ctx, c.client, c.embeddingModel, c.provider, texts)
+}
+
+func embedWithOpenAI(ctx context.Context, client *openai.Client, model string, provider string, texts []string) ([][]float32, error) {
+	if model == "" {
+		return nil, fmt.Errorf("no embedding model configured for %s", provider)
+	}
+
+	embeddings := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += embeddingBatchSize {
+		end := min(start+embeddingBatchSize, len(texts))
+		resp, err := client.CreateEmbeddings(ctx, openai.EmbeddingRequest{
+			Input: texts[start:end],
+			Model: openai.EmbeddingModel(model),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s embeddings API error: %w", provider, err)
+		}
+		if len(resp.Data) != end-start {
+			return nil, fmt.Errorf("%s returned %d embeddings for %d texts", provider, len(resp.Data), end-start)
+		}
+		for _, data := range resp.Data {
+			embeddings = append(embeddings, data.Embedding)
+		}
+	}
+	return embeddings, nil
+}
+
+func (c *GeminiClient) EmbeddingModel() string {
+	return c.embeddingModel
+}
+
+// Embed returns the embedding of each text
+func (c *GeminiClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if c.embeddingModel == "" {
+		return nil, fmt.Errorf("no embedding model configured for gemini")
+	}
+
+	model := c.client.EmbeddingModel(c.embeddingModel)
+	embeddings := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += embeddingBatchSize {
+		end := min(start+embeddingBatchSize, len(texts))
+		batch := model.NewBatch()
+		for _, text := range texts[start:end] {
+			batch.AddContent(genai.Text(text))
+		}
+		resp, err := model.BatchEmbedContents(ctx, batch)
+		if err != nil {
+			return nil, fmt.Errorf("gemini embeddings API error: %w", err)
+		}
+		if len(resp.Embeddings) != end-start {
+			return nil, fmt.Errorf("gemini returned %d embeddings for %d texts", len(resp.Embeddings), end-start)
+		}
+		for _, embedding := range resp.Embeddings {
+			embeddings = append(embeddings, embedding.Values)
+		}
+	}
+	return embeddings, nil
+}
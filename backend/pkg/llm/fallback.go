@@ -0,0 +1,314 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"neobase-ai/internal/models"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// FallbackPolicy configures how a FallbackClient retries and falls back between providers
+type FallbackPolicy struct {
+	Providers        []string      // Ordered providers tried after the chat's own provider
+	MaxAttempts      int           // Attempts per provider for retryable errors
+	BaseDelay        time.Duration // Delay before the first retry, doubled on every retry
+	MaxDelay         time.Duration
+	BreakerThreshold int           // Consecutive failures that open a provider's circuit
+	BreakerCooldown  time.Duration // Time an open circuit rejects requests before a trial request is let through
+}
+
+// DefaultFallbackPolicy retries each provider a few times without falling back to others
+func DefaultFallbackPolicy() FallbackPolicy {
+	return FallbackPolicy{
+		MaxAttempts:      3,
+		BaseDelay:        500 * time.Millisecond,
+		MaxDelay:         8 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  time.Minute,
+	}
+}
+
+// ResponseInfo describes which provider answered a request
+type ResponseInfo struct {
+	Provider        string
+	Model           string
+	Attempts        int      // Requests made across all providers
+	FailedProviders []string // Providers that failed before the answering one
+}
+
+type responseInfoKey struct{}
+
+// WithResponseInfo returns a context in which a FallbackClient records the provider that answered
+func WithResponseInfo(ctx context.Context) (context.Context, *ResponseInfo) {
+	info := &ResponseInfo{}
+	return context.WithValue(ctx, responseInfoKey{}, info), info
+}
+
+func recordResponseInfo(ctx context.Context, info ResponseInfo) {
+	if target, ok := ctx.Value(responseInfoKey{}).(*ResponseInfo); ok {
+		*target = info
+	}
+}
+
+// APIError is returned by clients calling a provider's HTTP API directly, so the status code can be checked
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s API error: status %d", e.Provider, e.StatusCode)
+	}
+	return fmt.Sprintf("%s API error: %s", e.Provider, e.Message)
+}
+
+// circuitBreaker stops sending requests to a provider after repeated failures
+type circuitBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool // A request is testing whether the provider recovered
+}
+
+// allow reports whether a request may be sent, letting a single trial request through once the cooldown passed
+func (b *circuitBreaker) allow(threshold int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if threshold <= 0 || b.failures < threshold {
+		return true
+	}
+	if time.Now().Before(b.openUntil) || b.trial {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.trial = false
+}
+
+// releaseTrial ends a trial request whose outcome is unknown, e.g. a cancelled one, so another request may test
+// the provider
+func (b *circuitBreaker) releaseTrial() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+// recordFailure counts a failure, returning true if it opened the circuit
+func (b *circuitBreaker) recordFailure(threshold int, cooldown time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.trial = false
+	if threshold > 0 && b.failures >= threshold {
+		b.openUntil = time.Now().Add(cooldown)
+		return true
+	}
+	return false
+}
+
+// FallbackEntry is a provider's client in a fallback chain
+type FallbackEntry struct {
+	Name   string
+	Client Client
+}
+
+// FallbackClient tries its clients in order, retrying retryable errors with exponential backoff and
+// skipping providers whose circuit is open
+type FallbackClient struct {
+	entries  []FallbackEntry
+	policy   FallbackPolicy
+	breakers func(name string) *circuitBreaker
+}
+
+func (c *FallbackClient) GenerateResponse(ctx context.Context, messages []*models.LLMMessage, dbType string, nonTechMode bool) (string, error) {
+	return c.generate(ctx, "GenerateResponse", func(client Client) (string, error) {
+		return client.GenerateResponse(ctx, messages, dbType, nonTechMode)
+	})
+}
+
+// GenerateResponseStream streams the response of clients supporting it. A retry or fallback after parts of the
+// response were reported calls the handler's OnRestart first.
+func (c *FallbackClient) GenerateResponseStream(ctx context.Context, messages []*models.LLMMessage, dbType string, nonTechMode bool, handler StreamHandler) (string, error) {
+	emitted := false
+	tracked := StreamHandler{OnRestart: handler.OnRestart}
+	if handler.OnMessageDelta != nil {
+		tracked.OnMessageDelta = func(delta string) {
+			emitted = true
+			handler.OnMessageDelta(delta)
+		}
+	}
+	if handler.OnQuery != nil {
+		tracked.OnQuery = func(index int, query map[string]interface{}) {
+			emitted = true
+			handler.OnQuery(index, query)
+		}
+	}
+
+	return c.generate(ctx, "GenerateResponseStream", func(client Client) (string, error) {
+		if emitted {
+			emitted = false
+			if handler.OnRestart != nil {
+				handler.OnRestart()
+			}
+		}
+		if streamingClient, ok := client.(StreamingClient); ok {
+			return streamingClient.GenerateResponseStream(ctx, messages, dbType, nonTechMode, tracked)
+		}
+		return client.GenerateResponse(ctx, messages, dbType, nonTechMode)
+	})
+}
+
+func (c *FallbackClient) GenerateRecommendations(ctx context.Context, messages []*models.LLMMessage, dbType string) (string, error) {
+	return c.generate(ctx, "GenerateRecommendations", func(client Client) (string, error) {
+		return client.GenerateRecommendations(ctx, messages, dbType)
+	})
+}
+
+func (c *FallbackClient) GenerateText(ctx context.Context, systemPrompt string, prompt string) (string, error) {
+	return c.generate(ctx, "GenerateText", func(client Client) (string, error) {
+		return client.GenerateText(ctx, systemPrompt, prompt)
+	})
+}
+
+// GetModelInfo returns the model info of the first client of the chain
+func (c *FallbackClient) GetModelInfo() ModelInfo {
+	return c.entries[0].Client.GetModelInfo()
+}
+
+func (c *FallbackClient) generate(ctx context.Context, method string, call func(client Client) (string, error)) (string, error) {
+	attempts := 0
+	var failedProviders []string
+	var lastErr error
+
+	for _, entry := range c.entries {
+		breaker := c.breakers(entry.Name)
+		if !breaker.allow(c.policy.BreakerThreshold) {
+			log.Printf("FallbackClient -> %s -> Circuit open for %s, skipping", method, entry.Name)
+			failedProviders = append(failedProviders, entry.Name)
+			continue
+		}
+
+		for attempt := 1; ; attempt++ {
+			attempts++
+			response, err := call(entry.Client)
+			if err == nil {
+				breaker.recordSuccess()
+				recordResponseInfo(ctx, ResponseInfo{
+					Provider:        entry.Name,
+					Model:           entry.Client.GetModelInfo().Name,
+					Attempts:        attempts,
+					FailedProviders: failedProviders,
+				})
+				return response, nil
+			}
+
+			// A cancelled request must not be retried nor count against the provider
+			if ctx.Err() != nil {
+				breaker.releaseTrial()
+				return "", err
+			}
+			lastErr = err
+
+			retryable := IsRetryableError(err)
+			if !retryable || attempt >= c.policy.MaxAttempts {
+				log.Printf("FallbackClient -> %s -> %s failed after %d attempt(s): %v", method, entry.Name, attempt, err)
+				if breaker.recordFailure(c.policy.BreakerThreshold, c.policy.BreakerCooldown) {
+					log.Printf("FallbackClient -> %s -> Circuit opened for %s for %v", method, entry.Name, c.policy.BreakerCooldown)
+				}
+				break
+			}
+
+			delay := c.backoff(attempt)
+			log.Printf("FallbackClient -> %s -> %s returned a retryable error, retrying in %v: %v", method, entry.Name, delay, err)
+			select {
+			case <-ctx.Done():
+				breaker.releaseTrial()
+				return "", ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+		failedProviders = append(failedProviders, entry.Name)
+	}
+
+	if lastErr == nil {
+		return "", fmt.Errorf("all LLM providers are unavailable: %s", strings.Join(failedProviders, ", "))
+	}
+	if len(c.entries) == 1 {
+		return "", lastErr
+	}
+	return "", fmt.Errorf("all LLM providers failed (%s), last error: %w", strings.Join(failedProviders, ", "), lastErr)
+}
+
+// backoff returns the delay before the next attempt, doubling for every attempt with some jitter
+func (c *FallbackClient) backoff(attempt int) time.Duration {
+	delay := c.policy.BaseDelay << (attempt - 1)
+	if delay <= 0 || (c.policy.MaxDelay > 0 && delay > c.policy.MaxDelay) {
+		delay = c.policy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	// Jitter spreads out retries of concurrent requests hitting the same rate limit
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// IsRetryableError checks if an LLM error is temporary, like rate limits, overloaded servers or timeouts
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	statusCode := 0
+	var apiErr *APIError
+	var openaiAPIErr *openai.APIError
+	var openaiReqErr *openai.RequestError
+	var httpCodeErr interface{ HTTPCode() int }
+	switch {
+	case errors.As(err, &apiErr):
+		statusCode = apiErr.StatusCode
+	case errors.As(err, &openaiAPIErr):
+		statusCode = openaiAPIErr.HTTPStatusCode
+	case errors.As(err, &openaiReqErr):
+		statusCode = openaiReqErr.HTTPStatusCode
+	case errors.As(err, &httpCodeErr):
+		statusCode = httpCodeErr.HTTPCode()
+	}
+	if statusCode > 0 {
+		return statusCode == http.StatusTooManyRequests || statusCode == http.StatusRequestTimeout || statusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	// Some SDKs (e.g. Gemini over gRPC) only expose the status in the message
+	message := strings.ToLower(err.Error())
+	for _, marker := range []string{"rate limit", "resourceexhausted", "resource exhausted", "unavailable", "overloaded", "timeout", "connection reset", "connection refused", "eof"} {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
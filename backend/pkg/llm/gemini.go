@@ -3,6 +3,7 @@ package llm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"neobase-ai/internal/constants"
@@ -11,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -19,6 +21,8 @@ type GeminiClient struct {
 	model               string
 	maxCompletionTokens int
 	temperature         float64
+	contextLimit        int
+	embeddingModel      string
 	DBConfigs           []LLMDBConfig
 }
 
@@ -40,6 +44,8 @@ func NewGeminiClient(config Config) (*GeminiClient, error) {
 		model:               config.Model,
 		maxCompletionTokens: maxCompletionTokens,
 		temperature:         temperature,
+		contextLimit:        config.ContextLimit,
+		embeddingModel:      config.EmbeddingModel,
 		DBConfigs:           DBConfigs,
 	}, nil
 }
@@ -50,6 +56,67 @@ func (c *GeminiClient) GenerateResponse(ctx context.Context, messages []*models.
 		return "", ctx.Err()
 	}
 
+	session := c.newResponseSession(messages, dbType, nonTechMode)
+
+	// Check if the context is cancelled
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	// Send empty message to get response based on history
+	result, err := session.SendMessage(ctx, genai.Text(geminiResponsePrompt))
+	if err != nil {
+		log.Printf("Gemini API error: %v", err)
+		return "", fmt.Errorf("gemini API error: %w", err)
+	}
+
+	log.Printf("GEMINI -> GenerateResponse -> result: %v", result)
+	log.Printf("GEMINI -> GenerateResponse -> result.Candidates[0].Content.Parts[0]: %v", result.Candidates[0].Content.Parts[0])
+	return normalizeGeminiResponse(fmt.Sprintf("%v", result.Candidates[0].Content.Parts[0]))
+}
+
+// GenerateResponseStream streams the response, reporting the assistant message and the queries as they are generated
+func (c *GeminiClient) GenerateResponseStream(ctx context.Context, messages []*models.LLMMessage, dbType string, nonTechMode bool, handler StreamHandler) (string, error) {
+	// Check if the context is cancelled
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	session := c.newResponseSession(messages, dbType, nonTechMode)
+	iter := session.SendMessageStream(ctx, genai.Text(geminiResponsePrompt))
+
+	parser := newResponseStreamParser(handler)
+	var content strings.Builder
+	for {
+		result, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			log.Printf("Gemini API error: %v", err)
+			return "", fmt.Errorf("gemini API error: %w", err)
+		}
+		if len(result.Candidates) == 0 || result.Candidates[0].Content == nil {
+			continue
+		}
+		for _, part := range result.Candidates[0].Content.Parts {
+			if text, ok := part.(genai.Text); ok {
+				content.WriteString(string(text))
+				parser.Write(string(text))
+			}
+		}
+	}
+
+	if content.Len() == 0 {
+		return "", fmt.Errorf("no response from gemini")
+	}
+	return normalizeGeminiResponse(content.String())
+}
+
+// Message sent after the history to get the response
+const geminiResponsePrompt = "Please provide a response based on our conversation history."
+
+// newResponseSession starts a chat session with the history and the response schema
+func (c *GeminiClient) newResponseSession(messages []*models.LLMMessage, dbType string, nonTechMode bool) *genai.ChatSession {
 	// Convert messages into parts for the Gemini API.
 	geminiMessages := make([]*genai.Content, 0)
 
@@ -73,10 +140,6 @@ func (c *GeminiClient) GenerateResponse(ctx context.Context, messages []*models.
 			genai.Text(systemPrompt),
 		},
 	})
-	// Check if the context is cancelled
-	if ctx.Err() != nil {
-		return "", ctx.Err()
-	}
 	// Add conversation history
 	for _, msg := range messages {
 		content := ""
@@ -109,6 +172,10 @@ func (c *GeminiClient) GenerateResponse(ctx context.Context, messages []*models.
 			if schemaUpdate, ok := msg.Content["schema_update"].(string); ok {
 				content = fmt.Sprintf("Database schema update:\n%s", schemaUpdate)
 			}
+		case "summary":
+			if summary, ok := msg.Content["summary"].(string); ok {
+				content = fmt.Sprintf("Summary of the earlier conversation:\n%s", summary)
+			}
 		}
 
 		if content != "" {
@@ -153,21 +220,12 @@ func (c *GeminiClient) GenerateResponse(ctx context.Context, messages []*models.
 	// Start chat session
 	session := model.StartChat()
 	session.History = geminiMessages
+	return session
+}
 
-	// Check if the context is cancelled
-	if ctx.Err() != nil {
-		return "", ctx.Err()
-	}
-	// Send empty message to get response based on history
-	result, err := session.SendMessage(ctx, genai.Text("Please provide a response based on our conversation history."))
-	if err != nil {
-		log.Printf("Gemini API error: %v", err)
-		return "", fmt.Errorf("gemini API error: %v", err)
-	}
-
-	log.Printf("GEMINI -> GenerateResponse -> result: %v", result)
-	log.Printf("GEMINI -> GenerateResponse -> result.Candidates[0].Content.Parts[0]: %v", result.Candidates[0].Content.Parts[0])
-	responseText := strings.ReplaceAll(fmt.Sprintf("%v", result.Candidates[0].Content.Parts[0]), "```json", "")
+// normalizeGeminiResponse strips code fences from the response and decodes the example results of its queries
+func normalizeGeminiResponse(responseText string) (string, error) {
+	responseText = strings.ReplaceAll(responseText, "```json", "")
 	responseText = strings.ReplaceAll(responseText, "```", "")
 
 	var llmResponse constants.LLMResponse
@@ -250,6 +308,10 @@ func (c *GeminiClient) GenerateRecommendations(ctx context.Context, messages []*
 			if schemaUpdate, ok := msg.Content["schema_update"].(string); ok {
 				content = fmt.Sprintf("Database schema update:\n%s", schemaUpdate)
 			}
+		case "summary":
+			if summary, ok := msg.Content["summary"].(string); ok {
+				content = fmt.Sprintf("Summary of the earlier conversation:\n%s", summary)
+			}
 		}
 
 		if content != "" {
@@ -300,7 +362,7 @@ func (c *GeminiClient) GenerateRecommendations(ctx context.Context, messages []*
 	result, err := session.SendMessage(ctx, genai.Text("Please provide query recommendations based on our conversation history."))
 	if err != nil {
 		log.Printf("Gemini API error: %v", err)
-		return "", fmt.Errorf("gemini API error: %v", err)
+		return "", fmt.Errorf("gemini API error: %w", err)
 	}
 
 	log.Printf("GEMINI -> GenerateRecommendations -> result: %v", result)
@@ -311,11 +373,44 @@ func (c *GeminiClient) GenerateRecommendations(ctx context.Context, messages []*
 	return responseText, nil
 }
 
+// GenerateText returns a plain text completion for a single prompt
+func (c *GeminiClient) GenerateText(ctx context.Context, systemPrompt string, prompt string) (string, error) {
+	// Check if the context is cancelled
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	model := c.client.GenerativeModel(c.model)
+	model.MaxOutputTokens = utils.ToInt32Ptr(int32(c.maxCompletionTokens))
+	model.SetTemperature(float32(c.temperature))
+	model.SystemInstruction = &genai.Content{
+		Parts: []genai.Part{genai.Text(systemPrompt)},
+	}
+
+	result, err := model.GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		log.Printf("Gemini API error: %v", err)
+		return "", fmt.Errorf("gemini API error: %w", err)
+	}
+	if len(result.Candidates) == 0 || result.Candidates[0].Content == nil {
+		return "", fmt.Errorf("no response from Gemini")
+	}
+
+	var responseText strings.Builder
+	for _, part := range result.Candidates[0].Content.Parts {
+		if text, ok := part.(genai.Text); ok {
+			responseText.WriteString(string(text))
+		}
+	}
+	return strings.TrimSpace(responseText.String()), nil
+}
+
 // GetModelInfo returns information about the Gemini model.
 func (c *GeminiClient) GetModelInfo() ModelInfo {
 	return ModelInfo{
 		Name:                c.model,
 		Provider:            "gemini",
 		MaxCompletionTokens: c.maxCompletionTokens,
+		ContextLimit:        c.contextLimit,
 	}
 }
@@ -0,0 +1,247 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// thinkBlockRegex matches the reasoning blocks some local models emit before their answer
+var thinkBlockRegex = regexp.MustCompile(`(?s)<think>.*?</think>`)
+
+// extractJSONResponse returns the JSON object contained in a model reply, repairing common mistakes
+// of models without structured output such as markdown fences, trailing commas, raw newlines inside
+// strings, Python literals and missing closing brackets
+func extractJSONResponse(text string) (string, error) {
+	text = strings.TrimSpace(thinkBlockRegex.ReplaceAllString(text, ""))
+	if isJSONObject(text) {
+		return text, nil
+	}
+
+	text = strings.ReplaceAll(text, "```json", "")
+	text = strings.ReplaceAll(text, "```JSON", "")
+	text = strings.ReplaceAll(text, "```", "")
+
+	candidate := extractJSONObject(text)
+	if candidate == "" {
+		return "", fmt.Errorf("no JSON object found in response")
+	}
+	if isJSONObject(candidate) {
+		return candidate, nil
+	}
+
+	repaired := repairJSON(candidate)
+	if isJSONObject(repaired) {
+		return repaired, nil
+	}
+	return "", fmt.Errorf("response is not valid JSON and could not be repaired")
+}
+
+// isJSONObject checks if the text is a valid JSON object
+func isJSONObject(text string) bool {
+	var object map[string]interface{}
+	return json.Unmarshal([]byte(text), &object) == nil
+}
+
+// extractJSONObject returns the first balanced JSON object in the text, or the rest of the text if it is never closed
+func extractJSONObject(text string) string {
+	start := strings.Index(text, "{")
+	if start == -1 {
+		return ""
+	}
+
+	depth := 0
+	inString := false
+	escaped := false
+	for i := start; i < len(text); i++ {
+		char := text[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case char == '\\':
+				escaped = true
+			case char == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch char {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return text[start : i+1]
+			}
+		}
+	}
+	return text[start:]
+}
+
+// openBracket is a bracket repairJSON has not seen closed yet
+type openBracket struct {
+	char    byte
+	start   int  // Offset of the bracket in the repaired output
+	inArray bool // Whether the bracket opens an element of an array
+}
+
+// repairJSON fixes a JSON object that almost parses
+func repairJSON(text string) string {
+	var out strings.Builder
+	var stack []openBracket
+	inString := false
+	escaped := false
+
+	for i := 0; i < len(text); i++ {
+		char := text[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+				out.WriteByte(char)
+			case char == '\\':
+				escaped = true
+				out.WriteByte(char)
+			case char == '"':
+				inString = false
+				out.WriteByte(char)
+			case char == '\n':
+				out.WriteString(`\n`)
+			case char == '\r':
+				out.WriteString(`\r`)
+			case char == '\t':
+				out.WriteString(`\t`)
+			default:
+				out.WriteByte(char)
+			}
+			continue
+		}
+
+		switch char {
+		case '"':
+			inString = true
+			out.WriteByte(char)
+		case '{', '[':
+			inArray := len(stack) > 0 && stack[len(stack)-1].char == '['
+			stack = append(stack, openBracket{char: char, start: out.Len(), inArray: inArray})
+			out.WriteByte(char)
+		case '}', ']':
+			trimTrailingComma(&out)
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			out.WriteByte(char)
+		default:
+			// Python style literals are a common mistake of smaller models
+			if literal, replacement, ok := matchLiteral(text[i:]); ok {
+				out.WriteString(replacement)
+				i += len(literal) - 1
+				continue
+			}
+			out.WriteByte(char)
+		}
+	}
+
+	// A string cut in the middle may be a partial query, which must never be executed
+	if inString {
+		return text
+	}
+
+	// Close the brackets a truncated reply left open. The elements of an array it didn't finish are dropped
+	// rather than closed, a query object cut before its isCritical or canRollback fields would otherwise
+	// reach execution looking safe.
+	for len(stack) > 0 {
+		open := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if open.inArray {
+			truncated := out.String()[:open.start]
+			out.Reset()
+			out.WriteString(truncated)
+			trimTrailingComma(&out)
+			continue
+		}
+		trimTrailingComma(&out)
+		trimDanglingKey(&out, open.char == '{')
+		if open.char == '{' {
+			out.WriteByte('}')
+		} else {
+			out.WriteByte(']')
+		}
+	}
+
+	return out.String()
+}
+
+// matchLiteral matches a Python literal at the start of the text
+func matchLiteral(text string) (string, string, bool) {
+	for literal, replacement := range map[string]string{"True": "true", "False": "false", "None": "null"} {
+		if !strings.HasPrefix(text, literal) {
+			continue
+		}
+		if len(text) > len(literal) {
+			next := text[len(literal)]
+			if next == '_' || (next >= 'a' && next <= 'z') || (next >= 'A' && next <= 'Z') || (next >= '0' && next <= '9') {
+				continue
+			}
+		}
+		return literal, replacement, true
+	}
+	return "", "", false
+}
+
+// trimTrailingComma removes a comma left before a closing bracket
+func trimTrailingComma(out *strings.Builder) {
+	trimmed := strings.TrimRight(out.String(), " \t\r\n")
+	if strings.HasSuffix(trimmed, ",") {
+		trimmed = strings.TrimSuffix(trimmed, ",")
+		out.Reset()
+		out.WriteString(trimmed)
+	}
+}
+
+// trimDanglingKey removes a key without value that a truncated reply ended with
+func trimDanglingKey(out *strings.Builder, inObject bool) {
+	if !inObject {
+		return
+	}
+
+	trimmed := strings.TrimRight(out.String(), " \t\r\n")
+	trimmed = strings.TrimRight(strings.TrimSuffix(trimmed, ":"), " \t\r\n")
+	if !strings.HasSuffix(trimmed, `"`) {
+		return
+	}
+
+	// A string right after "{" or "," of an object is a key
+	keyStart := lastStringStart(trimmed)
+	if keyStart == -1 {
+		return
+	}
+	before := strings.TrimRight(trimmed[:keyStart], " \t\r\n")
+	if !strings.HasSuffix(before, "{") && !strings.HasSuffix(before, ",") {
+		return
+	}
+	out.Reset()
+	out.WriteString(strings.TrimSuffix(before, ","))
+}
+
+// lastStringStart returns the index of the opening quote of the string the text ends with
+func lastStringStart(text string) int {
+	for i := len(text) - 2; i >= 0; i-- {
+		if text[i] != '"' {
+			continue
+		}
+		backslashes := 0
+		for j := i - 1; j >= 0 && text[j] == '\\'; j-- {
+			backslashes++
+		}
+		if backslashes%2 == 0 {
+			return i
+		}
+	}
+	return -1
+}
@@ -10,19 +10,91 @@ import (
 
 type Manager struct {
 	clients map[string]Client
+	configs map[string]Config
 	mu      sync.RWMutex
+
+	fallbackPolicy FallbackPolicy
+	breakers       map[string]*circuitBreaker // key: client name, shared by all fallback chains
+	breakersMu     sync.Mutex
 }
 
 func NewManager() *Manager {
 	return &Manager{
-		clients: make(map[string]Client),
+		clients:        make(map[string]Client),
+		configs:        make(map[string]Config),
+		fallbackPolicy: DefaultFallbackPolicy(),
+		breakers:       make(map[string]*circuitBreaker),
+	}
+}
+
+// SetFallbackPolicy sets the retry and fallback policy of the clients returned by GetFallbackClient
+func (m *Manager) SetFallbackPolicy(policy FallbackPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, provider := range policy.Providers {
+		if _, exists := m.clients[provider]; !exists {
+			log.Printf("LLMManager -> SetFallbackPolicy -> Fallback provider %s is not registered, it will be skipped", provider)
+		}
+	}
+	m.fallbackPolicy = policy
+}
+
+// GetFallbackClient returns a client that uses the given client and model first, then the policy's other providers
+// with their default models
+func (m *Manager) GetFallbackClient(name, model string) (Client, error) {
+	primary, err := m.GetClientForModel(name, model)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	policy := m.fallbackPolicy
+	entries := []FallbackEntry{{Name: name, Client: primary}}
+	for _, provider := range policy.Providers {
+		client, exists := m.clients[provider]
+		if !exists || provider == name {
+			continue
+		}
+		entries = append(entries, FallbackEntry{Name: provider, Client: client})
+	}
+	m.mu.RUnlock()
+
+	return &FallbackClient{
+		entries:  entries,
+		policy:   policy,
+		breakers: m.getBreaker,
+	}, nil
+}
+
+func (m *Manager) getBreaker(name string) *circuitBreaker {
+	m.breakersMu.Lock()
+	defer m.breakersMu.Unlock()
+
+	breaker, exists := m.breakers[name]
+	if !exists {
+		breaker = &circuitBreaker{}
+		m.breakers[name] = breaker
 	}
+	return breaker
 }
 
 func (m *Manager) RegisterClient(name string, config Config) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	client, err := newClient(config)
+	if err != nil {
+		return err
+	}
+
+	m.clients[name] = client
+	m.configs[name] = config
+	return nil
+}
+
+// newClient creates the client of the configured provider
+func newClient(config Config) (Client, error) {
 	var client Client
 	var err error
 
@@ -31,17 +103,18 @@ func (m *Manager) RegisterClient(name string, config Config) error {
 		client, err = NewOpenAIClient(config)
 	case "gemini":
 		client, err = NewGeminiClient(config)
-	// Add other providers here (Gemini, etc.)
+	case "anthropic":
+		client, err = NewAnthropicClient(config)
+	case "ollama", "openai-compatible":
+		client, err = NewOpenAICompatibleClient(config)
 	default:
-		return fmt.Errorf("unsupported LLM provider: %s", config.Provider)
+		return nil, fmt.Errorf("unsupported LLM provider: %s", config.Provider)
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to create LLM client: %v", err)
+		return nil, fmt.Errorf("failed to create LLM client: %v", err)
 	}
-
-	m.clients[name] = client
-	return nil
+	return client, nil
 }
 
 func (m *Manager) GetClient(name string) (Client, error) {
@@ -56,10 +129,87 @@ func (m *Manager) GetClient(name string) (Client, error) {
 	return client, nil
 }
 
+// ValidateModel checks that the client is registered and the model is one it may use, an empty model means the client's default
+func (m *Manager) ValidateModel(name, model string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[name]
+	if !exists {
+		return fmt.Errorf("LLM client not found: %s", name)
+	}
+	if model == "" || model == config.Model {
+		return nil
+	}
+	for _, allowedModel := range config.Models {
+		if allowedModel == model {
+			return nil
+		}
+	}
+	return fmt.Errorf("model %s is not available for %s", model, name)
+}
+
+// GetClientForModel returns the client, using the given model instead of its default one if set
+func (m *Manager) GetClientForModel(name, model string) (Client, error) {
+	if err := m.ValidateModel(name, model); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	config := m.configs[name]
+	if model == "" || model == config.Model {
+		client := m.clients[name]
+		m.mu.RUnlock()
+		return client, nil
+	}
+	key := name + "/" + model
+	client, exists := m.clients[key]
+	m.mu.RUnlock()
+	if exists {
+		return client, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Another request may have created it meanwhile
+	if client, exists := m.clients[key]; exists {
+		return client, nil
+	}
+
+	config.Model = model
+	client, err := newClient(config)
+	if err != nil {
+		return nil, err
+	}
+	m.clients[key] = client
+	return client, nil
+}
+
+// GetEmbedder returns the client as an Embedder if its provider supports embeddings and has an embedding model
+func (m *Manager) GetEmbedder(name string) (Embedder, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	embedder, ok := m.clients[name].(Embedder)
+	if !ok || embedder.EmbeddingModel() == "" {
+		return nil, false
+	}
+	return embedder, true
+}
+
 func (m *Manager) RemoveClient(name string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	delete(m.clients, name)
+	delete(m.configs, name)
+
+	// Drop the clients created for other models of it as well
+	for key := range m.clients {
+		if strings.HasPrefix(key, name+"/") {
+			delete(m.clients, key)
+		}
+	}
 }
 
 // Add helper function to properly format assistant response
@@ -80,7 +230,7 @@ func mapRole(role string) string {
 		return "user"
 	case "assistant":
 		return "assistant"
-	case "system":
+	case "system", "summary":
 		return "system"
 	default:
 		return "user"
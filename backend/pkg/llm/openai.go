@@ -3,10 +3,13 @@ package llm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"neobase-ai/internal/constants"
 	"neobase-ai/internal/models"
+	"strings"
 
 	"github.com/sashabaranov/go-openai"
 )
@@ -16,6 +19,8 @@ type OpenAIClient struct {
 	model               string
 	maxCompletionTokens int
 	temperature         float64
+	contextLimit        int
+	embeddingModel      string
 	DBConfigs           []LLMDBConfig
 }
 
@@ -35,6 +40,8 @@ func NewOpenAIClient(config Config) (*OpenAIClient, error) {
 		model:               model,
 		maxCompletionTokens: config.MaxCompletionTokens,
 		temperature:         config.Temperature,
+		contextLimit:        config.ContextLimit,
+		embeddingModel:      config.EmbeddingModel,
 		DBConfigs:           config.DBConfigs,
 	}, nil
 }
@@ -45,6 +52,84 @@ func (c *OpenAIClient) GenerateResponse(ctx context.Context, messages []*models.
 		return "", ctx.Err()
 	}
 
+	req := c.newResponseRequest(messages, dbType, nonTechMode)
+
+	// Check if the context is cancelled
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	// Call OpenAI API
+	resp, err := c.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		log.Printf("GenerateResponse -> err: %v", err)
+		return "", fmt.Errorf("OpenAI API error: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("no response from OpenAI")
+	}
+
+	log.Printf("OPENAI -> GenerateResponse -> resp: %v", resp)
+	// Validate response against schema
+	var llmResponse constants.LLMResponse
+	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &llmResponse); err != nil {
+		return "", fmt.Errorf("invalid response format: %v", err)
+	}
+
+	return resp.Choices[0].Message.Content, nil
+}
+
+// GenerateResponseStream streams the response, reporting the assistant message and the queries as they are generated
+func (c *OpenAIClient) GenerateResponseStream(ctx context.Context, messages []*models.LLMMessage, dbType string, nonTechMode bool, handler StreamHandler) (string, error) {
+	// Check if the context is cancelled
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	req := c.newResponseRequest(messages, dbType, nonTechMode)
+	req.Stream = true
+
+	stream, err := c.client.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		log.Printf("GenerateResponseStream -> err: %v", err)
+		return "", fmt.Errorf("OpenAI API error: %w", err)
+	}
+	defer stream.Close()
+
+	parser := newResponseStreamParser(handler)
+	var content strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Printf("GenerateResponseStream -> err: %v", err)
+			return "", fmt.Errorf("OpenAI API error: %w", err)
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		content.WriteString(chunk.Choices[0].Delta.Content)
+		parser.Write(chunk.Choices[0].Delta.Content)
+	}
+
+	if content.Len() == 0 {
+		return "", fmt.Errorf("no response from OpenAI")
+	}
+
+	// Validate response against schema
+	var llmResponse constants.LLMResponse
+	if err := json.Unmarshal([]byte(content.String()), &llmResponse); err != nil {
+		return "", fmt.Errorf("invalid response format: %v", err)
+	}
+
+	return content.String(), nil
+}
+
+// newResponseRequest builds the completion request of a chat response with its JSON schema
+func (c *OpenAIClient) newResponseRequest(messages []*models.LLMMessage, dbType string, nonTechMode bool) openai.ChatCompletionRequest {
 	// Convert messages to OpenAI format
 	openAIMessages := make([]openai.ChatCompletionMessage, 0, len(messages))
 
@@ -101,6 +186,10 @@ func (c *OpenAIClient) GenerateResponse(ctx context.Context, messages []*models.
 			if schemaUpdate, ok := msg.Content["schema_update"].(string); ok {
 				content = fmt.Sprintf("Database schema update:\n%s", schemaUpdate)
 			}
+		case "summary":
+			if summary, ok := msg.Content["summary"].(string); ok {
+				content = fmt.Sprintf("Summary of the earlier conversation:\n%s", summary)
+			}
 		}
 
 		if content != "" {
@@ -127,31 +216,34 @@ func (c *OpenAIClient) GenerateResponse(ctx context.Context, messages []*models.
 			},
 		},
 	}
+	return req
+}
 
+// GenerateText returns a plain text completion for a single prompt
+func (c *OpenAIClient) GenerateText(ctx context.Context, systemPrompt string, prompt string) (string, error) {
 	// Check if the context is cancelled
 	if ctx.Err() != nil {
 		return "", ctx.Err()
 	}
 
-	// Call OpenAI API
-	resp, err := c.client.CreateChatCompletion(ctx, req)
+	resp, err := c.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model: c.model,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: prompt},
+		},
+		MaxCompletionTokens: c.maxCompletionTokens,
+		Temperature:         float32(c.temperature),
+	})
 	if err != nil {
-		log.Printf("GenerateResponse -> err: %v", err)
-		return "", fmt.Errorf("OpenAI API error: %v", err)
+		log.Printf("GenerateText -> err: %v", err)
+		return "", fmt.Errorf("OpenAI API error: %w", err)
 	}
-
 	if len(resp.Choices) == 0 {
 		return "", fmt.Errorf("no response from OpenAI")
 	}
 
-	log.Printf("OPENAI -> GenerateResponse -> resp: %v", resp)
-	// Validate response against schema
-	var llmResponse constants.LLMResponse
-	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &llmResponse); err != nil {
-		return "", fmt.Errorf("invalid response format: %v", err)
-	}
-
-	return resp.Choices[0].Message.Content, nil
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
 }
 
 // GenerateRecommendations generates query recommendations using a different prompt and schema
@@ -188,6 +280,10 @@ func (c *OpenAIClient) GenerateRecommendations(ctx context.Context, messages []*
 			if schemaUpdate, ok := msg.Content["schema_update"].(string); ok {
 				content = fmt.Sprintf("Database schema update:\n%s", schemaUpdate)
 			}
+		case "summary":
+			if summary, ok := msg.Content["summary"].(string); ok {
+				content = fmt.Sprintf("Summary of the earlier conversation:\n%s", summary)
+			}
 		}
 
 		if content != "" {
@@ -224,7 +320,7 @@ func (c *OpenAIClient) GenerateRecommendations(ctx context.Context, messages []*
 	resp, err := c.client.CreateChatCompletion(ctx, req)
 	if err != nil {
 		log.Printf("GenerateRecommendations -> err: %v", err)
-		return "", fmt.Errorf("OpenAI API error: %v", err)
+		return "", fmt.Errorf("OpenAI API error: %w", err)
 	}
 
 	if len(resp.Choices) == 0 {
@@ -240,5 +336,6 @@ func (c *OpenAIClient) GetModelInfo() ModelInfo {
 		Name:                c.model,
 		Provider:            "openai",
 		MaxCompletionTokens: c.maxCompletionTokens,
+		ContextLimit:        c.contextLimit,
 	}
 }
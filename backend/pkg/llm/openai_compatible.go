@@ -0,0 +1,336 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"neobase-ai/internal/constants"
+	"neobase-ai/internal/models"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// OpenAICompatibleClient talks to self-hosted models (Ollama, vLLM, LM Studio...) through an OpenAI compatible API.
+// Models that can't enforce a JSON schema get the schema in the prompt and their reply is extracted and repaired.
+type OpenAICompatibleClient struct {
+	client              *openai.Client
+	provider            string
+	model               string
+	maxCompletionTokens int
+	temperature         float64
+	contextLimit        int
+	embeddingModel      string
+	DBConfigs           []LLMDBConfig
+
+	// structuredOutput is cleared once the server rejects response_format, so later requests skip it
+	structuredOutput atomic.Bool
+}
+
+func NewOpenAICompatibleClient(config Config) (*OpenAICompatibleClient, error) {
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("%s base URL is required", config.Provider)
+	}
+	if config.Model == "" {
+		return nil, fmt.Errorf("%s model is required", config.Provider)
+	}
+
+	// Local servers usually don't check the API key, but the header can't be empty for some proxies
+	apiKey := config.APIKey
+	if apiKey == "" {
+		apiKey = config.Provider
+	}
+	clientConfig := openai.DefaultConfig(apiKey)
+	clientConfig.BaseURL = config.BaseURL
+
+	client := &OpenAICompatibleClient{
+		client:              openai.NewClientWithConfig(clientConfig),
+		provider:            config.Provider,
+		model:               config.Model,
+		maxCompletionTokens: config.MaxCompletionTokens,
+		temperature:         config.Temperature,
+		contextLimit:        config.ContextLimit,
+		embeddingModel:      config.EmbeddingModel,
+		DBConfigs:           config.DBConfigs,
+	}
+	client.structuredOutput.Store(!config.DisableStructuredOutput)
+	return client, nil
+}
+
+func (c *OpenAICompatibleClient) GenerateResponse(ctx context.Context, messages []*models.LLMMessage, dbType string, nonTechMode bool) (string, error) {
+	// Check if the context is cancelled
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	// Get the system prompt with non-tech mode if enabled
+	systemPrompt := constants.GetSystemPrompt(c.provider, dbType, nonTechMode)
+	responseSchema := ""
+
+	for _, dbConfig := range c.DBConfigs {
+		if dbConfig.DBType == dbType {
+			responseSchema = dbConfig.Schema.(string)
+			break
+		}
+	}
+	if responseSchema == "" {
+		responseSchema = constants.GetLLMResponseSchema(c.provider, dbType).(string)
+	}
+
+	chatMessages := make([]openai.ChatCompletionMessage, 0, len(messages))
+	for _, msg := range messages {
+		content := ""
+
+		// Handle different message types
+		switch msg.Role {
+		case "user":
+			if userMsg, ok := msg.Content["user_message"].(string); ok {
+				content = userMsg
+				// Add non-tech mode context if the mode differs from current request
+				if msg.NonTechMode != nonTechMode {
+					if msg.NonTechMode {
+						content = "[This message was sent in NON-TECHNICAL MODE] " + content
+					} else {
+						content = "[This message was sent in TECHNICAL MODE] " + content
+					}
+				}
+			}
+		case "assistant":
+			if assistantMsg, ok := msg.Content["assistant_response"].(map[string]interface{}); ok {
+				content = formatAssistantResponse(assistantMsg)
+				// Add non-tech mode context if the mode differs from current request
+				if msg.NonTechMode != nonTechMode {
+					if msg.NonTechMode {
+						content = "[This response was generated in NON-TECHNICAL MODE]\n" + content
+					} else {
+						content = "[This response was generated in TECHNICAL MODE]\n" + content
+					}
+				}
+			}
+		case "system":
+			if schemaUpdate, ok := msg.Content["schema_update"].(string); ok {
+				content = fmt.Sprintf("Database schema update:\n%s", schemaUpdate)
+			}
+		case "summary":
+			if summary, ok := msg.Content["summary"].(string); ok {
+				content = fmt.Sprintf("Summary of the earlier conversation:\n%s", summary)
+			}
+		}
+
+		if content != "" {
+			chatMessages = append(chatMessages, openai.ChatCompletionMessage{
+				Role:    mapRole(msg.Role),
+				Content: content,
+			})
+		}
+	}
+
+	responseText, err := c.createStructuredCompletion(ctx, systemPrompt, chatMessages, "neobase-response", responseSchema)
+	if err != nil {
+		log.Printf("%s -> GenerateResponse -> err: %v", c.provider, err)
+		return "", err
+	}
+
+	// Validate response against schema
+	var llmResponse constants.LLMResponse
+	if err := json.Unmarshal([]byte(responseText), &llmResponse); err != nil {
+		return "", fmt.Errorf("invalid response format: %v", err)
+	}
+	if llmResponse.AssistantMessage == "" {
+		return "", fmt.Errorf("invalid response format: assistantMessage is missing")
+	}
+
+	return responseText, nil
+}
+
+// GenerateRecommendations generates query recommendations using a different prompt and schema
+func (c *OpenAICompatibleClient) GenerateRecommendations(ctx context.Context, messages []*models.LLMMessage, dbType string) (string, error) {
+	// Check if the context is cancelled
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	systemPrompt := constants.GetRecommendationsPrompt(c.provider)
+	responseSchema := constants.GetRecommendationsSchema(c.provider).(string)
+
+	chatMessages := make([]openai.ChatCompletionMessage, 0, len(messages))
+	for _, msg := range messages {
+		content := ""
+
+		// Handle different message types
+		switch msg.Role {
+		case "user":
+			if userMsg, ok := msg.Content["user_message"].(string); ok {
+				content = userMsg
+			}
+		case "assistant":
+			if assistantMsg, ok := msg.Content["assistant_response"].(map[string]interface{}); ok {
+				content = formatAssistantResponse(assistantMsg)
+			}
+		case "system":
+			if schemaUpdate, ok := msg.Content["schema_update"].(string); ok {
+				content = fmt.Sprintf("Database schema update:\n%s", schemaUpdate)
+			}
+		case "summary":
+			if summary, ok := msg.Content["summary"].(string); ok {
+				content = fmt.Sprintf("Summary of the earlier conversation:\n%s", summary)
+			}
+		}
+
+		if content != "" {
+			chatMessages = append(chatMessages, openai.ChatCompletionMessage{
+				Role:    mapRole(msg.Role),
+				Content: content,
+			})
+		}
+	}
+
+	responseText, err := c.createStructuredCompletion(ctx, systemPrompt, chatMessages, "recommendations-response", responseSchema)
+	if err != nil {
+		log.Printf("%s -> GenerateRecommendations -> err: %v", c.provider, err)
+		return "", err
+	}
+
+	return responseText, nil
+}
+
+// GenerateText returns a plain text completion for a single prompt
+func (c *OpenAICompatibleClient) GenerateText(ctx context.Context, systemPrompt string, prompt string) (string, error) {
+	req := c.newCompletionRequest(systemPrompt, []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleUser, Content: prompt},
+	})
+	content, err := c.createCompletion(ctx, req)
+	if err != nil {
+		log.Printf("%s -> GenerateText -> err: %v", c.provider, err)
+		return "", err
+	}
+
+	// Reasoning models prefix their answer with their thoughts
+	return strings.TrimSpace(thinkBlockRegex.ReplaceAllString(content, "")), nil
+}
+
+// createStructuredCompletion requests a JSON reply following the schema. The schema is enforced by the server when
+// it supports structured output, otherwise it is described in the prompt and the reply is extracted and repaired,
+// asking the model once more if that fails.
+func (c *OpenAICompatibleClient) createStructuredCompletion(ctx context.Context, systemPrompt string, messages []openai.ChatCompletionMessage, schemaName string, schema string) (string, error) {
+	if c.structuredOutput.Load() {
+		req := c.newCompletionRequest(systemPrompt, messages)
+		req.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   schemaName,
+				Schema: json.RawMessage(schema),
+				Strict: false,
+			},
+		}
+
+		content, err := c.createCompletion(ctx, req)
+		if err == nil {
+			// Servers may accept the schema and still return free text, so the reply goes through the same extraction
+			if responseText, extractErr := extractJSONResponse(content); extractErr == nil {
+				return responseText, nil
+			}
+			log.Printf("%s -> createStructuredCompletion -> Structured reply was not valid JSON, retrying with prompt instructions", c.provider)
+		} else {
+			if !isBadRequestError(err) {
+				return "", err
+			}
+			// The server rejected the request, most likely because it doesn't support response_format
+			log.Printf("%s -> createStructuredCompletion -> Structured output not supported, falling back to JSON extraction: %v", c.provider, err)
+			c.structuredOutput.Store(false)
+		}
+	}
+
+	// Describe the schema in the prompt instead
+	fallbackPrompt := systemPrompt + fmt.Sprintf(constants.StructuredOutputFallbackPrompt, schema)
+	req := c.newCompletionRequest(fallbackPrompt, messages)
+	content, err := c.createCompletion(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	responseText, err := extractJSONResponse(content)
+	if err == nil {
+		return responseText, nil
+	}
+	log.Printf("%s -> createStructuredCompletion -> %v, asking the model to resend it", c.provider, err)
+
+	// Give the model one chance to fix its reply
+	req.Messages = append(req.Messages,
+		openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: content},
+		openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: constants.StructuredOutputRetryPrompt},
+	)
+	content, err = c.createCompletion(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	responseText, err = extractJSONResponse(content)
+	if err != nil {
+		return "", fmt.Errorf("invalid response format: %v", err)
+	}
+	return responseText, nil
+}
+
+// newCompletionRequest builds a chat completion request with the system prompt first
+func (c *OpenAICompatibleClient) newCompletionRequest(systemPrompt string, messages []openai.ChatCompletionMessage) openai.ChatCompletionRequest {
+	chatMessages := make([]openai.ChatCompletionMessage, 0, len(messages)+1)
+	chatMessages = append(chatMessages, openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleSystem,
+		Content: systemPrompt,
+	})
+	chatMessages = append(chatMessages, messages...)
+
+	return openai.ChatCompletionRequest{
+		Model:       c.model,
+		Messages:    chatMessages,
+		MaxTokens:   c.maxCompletionTokens,
+		Temperature: float32(c.temperature),
+	}
+}
+
+// createCompletion calls the chat completion API and returns the reply text
+func (c *OpenAICompatibleClient) createCompletion(ctx context.Context, req openai.ChatCompletionRequest) (string, error) {
+	// Check if the context is cancelled
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	resp, err := c.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("%s API error: %w", c.provider, err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("no response from %s", c.provider)
+	}
+
+	// A truncated reply can't be trusted, its last query may be cut in the middle
+	if resp.Choices[0].FinishReason == openai.FinishReasonLength {
+		return "", fmt.Errorf("%s response was truncated, increase OLLAMA_MAX_COMPLETION_TOKENS", c.provider)
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// isBadRequestError checks if the server rejected the request itself rather than failing to process it
+func isBadRequestError(err error) bool {
+	statusCode := 0
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	if errors.As(err, &apiErr) {
+		statusCode = apiErr.HTTPStatusCode
+	} else if errors.As(err, &reqErr) {
+		statusCode = reqErr.HTTPStatusCode
+	}
+	return statusCode == http.StatusBadRequest || statusCode == http.StatusUnprocessableEntity
+}
+
+func (c *OpenAICompatibleClient) GetModelInfo() ModelInfo {
+	return ModelInfo{
+		Name:                c.model,
+		Provider:            c.provider,
+		MaxCompletionTokens: c.maxCompletionTokens,
+		ContextLimit:        c.contextLimit,
+	}
+}
@@ -0,0 +1,195 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"neobase-ai/internal/models"
+	"strings"
+	"unicode/utf8"
+)
+
+// StreamHandler receives the parts of a response as they are generated. Any callback may be nil.
+type StreamHandler struct {
+	OnMessageDelta func(delta string)                            // Next part of the assistantMessage text
+	OnQuery        func(index int, query map[string]interface{}) // A query of the queries array, once it is complete
+	OnRestart      func()                                        // The response is generated again, the parts received so far are void
+}
+
+// StreamingClient is implemented by clients able to stream their responses
+type StreamingClient interface {
+	// GenerateResponseStream is GenerateResponse reporting the response through the handler while it is generated
+	GenerateResponseStream(ctx context.Context, messages []*models.LLMMessage, dbType string, nonTechMode bool, handler StreamHandler) (string, error)
+}
+
+// responseStreamParser reads the JSON response chunk by chunk, reporting the assistantMessage text as it grows and
+// each query of the queries array as soon as its object is closed
+type responseStreamParser struct {
+	handler StreamHandler
+	buf     []byte
+	pos     int // Next byte of buf to scan
+
+	started   bool // The top-level object was opened, anything before it (e.g. code fences) is skipped
+	depth     int
+	inString  bool
+	escaped   bool
+	unicode   int  // Hex digits left of a \u escape
+	surrogate bool // The last escape was the first half of a surrogate pair
+
+	stringStart int    // Offset of the current string's first byte after the quote
+	expectKey   bool   // The next string at depth 1 is a key
+	lastKey     string // Last key read at depth 1
+
+	messageStart int // Offset of the assistantMessage value, -1 when not inside it
+	messageSafe  int // End of the assistantMessage bytes that don't stop in the middle of an escape
+	emitted      string
+
+	inQueries  bool
+	queryStart int
+	queryIndex int
+}
+
+func newResponseStreamParser(handler StreamHandler) *responseStreamParser {
+	return &responseStreamParser{handler: handler, messageStart: -1}
+}
+
+// Write scans the next chunk of the response
+func (p *responseStreamParser) Write(chunk string) {
+	p.buf = append(p.buf, chunk...)
+	for ; p.pos < len(p.buf); p.pos++ {
+		p.scan(p.buf[p.pos])
+	}
+	p.emitMessage()
+}
+
+func (p *responseStreamParser) scan(b byte) {
+	if !p.started {
+		if b == '{' {
+			p.started = true
+			p.depth = 1
+			p.expectKey = true
+		}
+		return
+	}
+
+	if p.inString {
+		p.scanString(b)
+		return
+	}
+
+	switch b {
+	case '"':
+		p.inString = true
+		p.stringStart = p.pos + 1
+		if p.depth == 1 && !p.expectKey && p.lastKey == "assistantMessage" {
+			p.messageStart = p.pos + 1
+			p.messageSafe = p.messageStart
+		}
+	case '{', '[':
+		p.depth++
+		if p.depth == 2 && b == '[' && p.lastKey == "queries" {
+			p.inQueries = true
+		} else if p.depth == 3 && b == '{' && p.inQueries {
+			p.queryStart = p.pos
+		}
+	case '}', ']':
+		p.depth--
+		if p.depth == 2 && b == '}' && p.inQueries {
+			p.emitQuery(p.buf[p.queryStart : p.pos+1])
+		} else if p.depth == 1 {
+			p.inQueries = false
+		}
+	case ',':
+		if p.depth == 1 {
+			p.expectKey = true
+		}
+	case ':':
+		if p.depth == 1 {
+			p.expectKey = false
+		}
+	}
+}
+
+func (p *responseStreamParser) scanString(b byte) {
+	switch {
+	case p.unicode > 0:
+		p.unicode--
+		if p.unicode == 0 {
+			// The first half of a surrogate pair can't be decoded alone
+			hex := strings.ToLower(string(p.buf[p.pos-3 : p.pos+1]))
+			p.surrogate = hex >= "d800" && hex <= "dbff"
+			if !p.surrogate {
+				p.markMessageSafe()
+			}
+		}
+		return
+	case p.escaped:
+		p.escaped = false
+		if b == 'u' {
+			p.unicode = 4
+			return
+		}
+	case b == '\\':
+		p.escaped = true
+		return
+	case b == '"':
+		p.inString = false
+		if p.depth == 1 && p.expectKey {
+			var key string
+			if err := json.Unmarshal(p.buf[p.stringStart-1:p.pos+1], &key); err == nil {
+				p.lastKey = key
+			}
+		}
+		if p.messageStart >= 0 {
+			p.emitMessage()
+			p.messageStart = -1
+		}
+		return
+	}
+	p.surrogate = false
+	p.markMessageSafe()
+}
+
+func (p *responseStreamParser) markMessageSafe() {
+	if p.messageStart >= 0 {
+		p.messageSafe = p.pos + 1
+	}
+}
+
+// emitMessage reports the assistantMessage text decoded since the last call
+func (p *responseStreamParser) emitMessage() {
+	if p.messageStart < 0 || p.handler.OnMessageDelta == nil {
+		return
+	}
+	raw := p.buf[p.messageStart:p.messageSafe]
+	// A multi-byte character may be split across chunks
+	if !utf8.Valid(raw) {
+		return
+	}
+
+	var decoded string
+	quoted := make([]byte, 0, len(raw)+2)
+	quoted = append(append(append(quoted, '"'), raw...), '"')
+	if err := json.Unmarshal(quoted, &decoded); err != nil || !strings.HasPrefix(decoded, p.emitted) {
+		return
+	}
+	if delta := decoded[len(p.emitted):]; delta != "" {
+		p.emitted = decoded
+		p.handler.OnMessageDelta(delta)
+	}
+}
+
+func (p *responseStreamParser) emitQuery(raw []byte) {
+	index := p.queryIndex
+	p.queryIndex++
+	if p.handler.OnQuery == nil {
+		return
+	}
+
+	var query map[string]interface{}
+	if err := json.Unmarshal(raw, &query); err != nil {
+		log.Printf("responseStreamParser -> emitQuery -> Error decoding query %d: %v", index, err)
+		return
+	}
+	p.handler.OnQuery(index, query)
+}
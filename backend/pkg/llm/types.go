@@ -17,6 +17,8 @@ type Message struct {
 type Client interface {
 	GenerateResponse(ctx context.Context, messages []*models.LLMMessage, dbType string, nonTechMode bool) (string, error)
 	GenerateRecommendations(ctx context.Context, messages []*models.LLMMessage, dbType string) (string, error)
+	// GenerateText returns a plain text answer to a single prompt, for internal tasks like summarizing the conversation
+	GenerateText(ctx context.Context, systemPrompt string, prompt string) (string, error)
 	GetModelInfo() ModelInfo
 }
 
@@ -32,10 +34,17 @@ type ModelInfo struct {
 type Config struct {
 	Provider            string
 	Model               string
+	Models              []string // Other models chats may select instead of Model
 	APIKey              string
+	BaseURL             string // Optional API endpoint override
 	MaxCompletionTokens int
 	Temperature         float64
+	ContextLimit        int    // Maximum tokens of the prompt the model accepts
+	EmbeddingModel      string // Model used to embed text, empty if the provider shouldn't be used for embeddings
 	DBConfigs           []LLMDBConfig
+
+	// DisableStructuredOutput skips response_format for OpenAI compatible servers that don't support it
+	DisableStructuredOutput bool
 }
 
 type LLMDBConfig struct {
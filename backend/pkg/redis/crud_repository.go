@@ -17,9 +17,11 @@ type RedisRepositories struct {
 
 type IRedisRepositories interface {
 	Set(key string, data []byte, expiredTime time.Duration, ctx context.Context) error
+	SetNX(key string, data []byte, expiredTime time.Duration, ctx context.Context) (bool, error)
 	Hset(key string, data string, expireAt time.Time, ctx context.Context) error
 	Get(key string, ctx context.Context) (string, error)
 	Del(key string, ctx context.Context) error
+	DelIfValue(key string, value []byte, ctx context.Context) (bool, error)
 	GetAllByField(ctx context.Context, modelType interface{}, filterFunc func(interface{}) bool) ([]interface{}, error)
 	TTL(key string, ctx context.Context) (time.Duration, error)
 	StartPipeline(ctx context.Context) *Pipeline
@@ -43,6 +45,16 @@ func (r *RedisRepositories) Set(key string, data []byte, expiredTime time.Durati
 	return nil
 }
 
+// SetNX sets the key only when it doesn't exist, returns false when it already did
+func (r *RedisRepositories) SetNX(key string, data []byte, expiredTime time.Duration, ctx context.Context) (bool, error) {
+	set, err := r.Client.SetNX(ctx, key, string(data), expiredTime).Result()
+	if err != nil {
+		log.Printf("Error setting Redis key if not exists: %v", err)
+		return false, err
+	}
+	return set, nil
+}
+
 func (r *RedisRepositories) Hset(key string, data string, expireAt time.Time, ctx context.Context) error {
 	err := r.Client.Set(ctx, key, data, time.Until(expireAt)).Err()
 	if err != nil {
@@ -76,6 +88,25 @@ func (r *RedisRepositories) Del(key string, ctx context.Context) error {
 	return nil
 }
 
+// delIfValueScript deletes a key only while it holds the given value, in a single step
+var delIfValueScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// DelIfValue deletes the key only when it still holds the value, e.g. to release a lock only by its owner. Returns
+// false when the key held another value or had expired.
+func (r *RedisRepositories) DelIfValue(key string, value []byte, ctx context.Context) (bool, error) {
+	deleted, err := delIfValueScript.Run(ctx, r.Client, []string{key}, string(value)).Int()
+	if err != nil {
+		log.Printf("Error deleting Redis key if it holds the value: %v", err)
+		return false, err
+	}
+	return deleted == 1, nil
+}
+
 // GetAllByField fetches all records and filters them using a custom filter function
 func (r *RedisRepositories) GetAllByField(ctx context.Context, modelType interface{}, filterFunc func(interface{}) bool) ([]interface{}, error) {
 	var results []interface{}
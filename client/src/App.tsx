@@ -1,7 +1,7 @@
 import axios from 'axios';
 import { EventSourcePolyfill } from 'event-source-polyfill';
 import { Boxes } from 'lucide-react';
-import { useCallback, useEffect, useState } from 'react';
+import { useCallback, useEffect, useRef, useState } from 'react';
 import toast, { Toaster } from 'react-hot-toast';
 import { Routes, Route, useNavigate, useParams, Navigate } from 'react-router-dom';
 import AuthForm from './components/auth/AuthForm';
@@ -20,7 +20,7 @@ import analyticsService from './services/analyticsService';
 import { LoginFormData, SignupFormData } from './types/auth';
 import { Chat, ChatSettings, ChatsResponse, Connection } from './types/chat';
 import { SendMessageResponse } from './types/messages';
-import { StreamResponse } from './types/stream';
+import { StreamDelta, StreamResponse } from './types/stream';
 import WelcomeSection from './components/app/WelcomeSection';
 import LoadingComponent from './components/app/Loading';
 
@@ -45,6 +45,8 @@ function AppContent() {
   const { streamId, setStreamId, generateStreamId } = useStream();
   const [isMessageSending, setIsMessageSending] = useState(false);
   const [temporaryMessage, setTemporaryMessage] = useState<Message | null>(null);
+  // Whether the assistant message of the current response was streamed through ai-response-delta events
+  const streamedContentRef = useRef(false);
   const { user, setUser } = useUser();
   const [refreshSchemaController, setRefreshSchemaController] = useState<AbortController | null>(null);
   const [isSSEReconnecting, setIsSSEReconnecting] = useState(false);
@@ -999,6 +1001,44 @@ function AppContent() {
             }
             break;
 
+          case 'ai-response-delta':
+            {
+              const delta: StreamDelta = response.data;
+              setMessages(prev => {
+                // Find the most recent streaming message
+                const streamingCandidates = prev.filter(msg => msg.is_streaming);
+                const streamingMessage = streamingCandidates[streamingCandidates.length - 1];
+                if (!streamingMessage) return prev;
+
+                let updatedMessage: Message;
+                if (delta.type === 'reset') {
+                  streamedContentRef.current = false;
+                  updatedMessage = { ...streamingMessage, content: '', queries: [] };
+                } else if (delta.type === 'query' && delta.query) {
+                  // Show the query card as soon as the query is complete, it is replaced by the final response
+                  const streamedQuery: QueryResult = {
+                    id: `temp-query-${delta.index}`,
+                    query: delta.query.query,
+                    description: delta.query.explanation || '',
+                    is_critical: delta.query.isCritical,
+                    can_rollback: delta.query.canRollback,
+                  };
+                  updatedMessage = {
+                    ...streamingMessage,
+                    queries: [...(streamingMessage.queries || []).filter(q => q.id !== streamedQuery.id), streamedQuery]
+                  };
+                } else {
+                  streamedContentRef.current = true;
+                  updatedMessage = { ...streamingMessage, content: streamingMessage.content + (delta.text || '') };
+                }
+
+                return prev.map(msg =>
+                  msg.id === streamingMessage.id ? updatedMessage : msg
+                );
+              });
+            }
+            break;
+
           case 'ai-response':
             if (response.data) {
               console.log('ai-response -> response.data', response.data);
@@ -1076,11 +1116,12 @@ function AppContent() {
                 });
               } else {
                 // For new messages, create a new message
-                // Create base message with empty content for animation
+                // Create base message with empty content for animation, unless the content was already streamed
+                const wasStreamed = streamedContentRef.current;
                 const baseMessage: Message = {
                   id: response.data.id,
                   type: 'assistant' as const,
-                  content: '',
+                  content: wasStreamed ? response.data.content : '',
                   action_buttons: response.data.action_buttons,
                   queries: response.data.non_tech_mode 
                     ? response.data.queries || [] // In non-tech mode, set queries directly
@@ -1102,7 +1143,9 @@ function AppContent() {
                 });
 
                 // Animate content
-                await animateTyping(response.data.content, response.data.id);
+                if (!wasStreamed) {
+                  await animateTyping(response.data.content, response.data.id);
+                }
                 
                 // Animate queries
                 if (response.data.queries && response.data.queries.length > 0) {
@@ -1129,6 +1172,7 @@ function AppContent() {
               // Trigger recommendations refresh shimmer/load in ChatWindow
               setRecoRefreshToken(prev => prev + 1);
             }
+            streamedContentRef.current = false;
             setTemporaryMessage(null);
             break;
 
@@ -1148,6 +1192,7 @@ function AppContent() {
                 created_at: new Date().toISOString()
               }, ...withoutTemp];
             });
+            streamedContentRef.current = false;
             setTemporaryMessage(null);
             // Trigger recommendations refresh on error
             setRecoRefreshToken(prev => prev + 1);
@@ -1181,6 +1226,7 @@ function AppContent() {
             await animateTyping(response.data, cancelMsg.id);
 
             // Clear temporary message state
+            streamedContentRef.current = false;
             setTemporaryMessage(null);
 
             // Set streaming to false for all messages
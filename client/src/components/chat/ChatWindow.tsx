@@ -1048,7 +1048,8 @@ export default function ChatWindow({
                       ...q,
                       query: showEditQueryConfirm.query!,
                       is_edited: true,
-                      original_query: q.query
+                      original_query: q.query,
+                      is_critical: response.data?.data?.is_critical ?? q.is_critical
                     }
                     : q
                 )
@@ -376,6 +376,12 @@ export default function MessageTile({
         const query = message.queries?.find(q => q.id === queryId);
         if (!query) return;
 
+        // Queries of a response still being generated aren't saved yet
+        if (message.is_loading) {
+            toast.error('Wait for the response to complete before executing the query', toastStyle);
+            return;
+        }
+
         // Track query execute click
         if (userId && userName) {
             analyticsService.trackQueryExecuteClick(chatId, queryId, userId, userName);
@@ -2488,7 +2494,10 @@ export default function MessageTile({
             {showCriticalConfirm && (
                 <ConfirmationModal
                     title="Critical Query"
-                    message="This query may affect important data. Are you sure you want to proceed?"
+                    message={[
+                        ...(message.queries?.find(q => q.id === queryToExecute)?.safety_warnings || []),
+                        "This query may affect important data. Are you sure you want to proceed?"
+                    ].join("\n")}
                     onConfirm={async () => {
                         setShowCriticalConfirm(false);
                         if (queryToExecute !== null) {
@@ -18,6 +18,7 @@ export interface QueryResult {
         details?: string;
     };
     is_critical?: boolean;
+    safety_warnings?: string[];
     can_rollback?: boolean;
     rollback_query?: string;
     rollback_dependent_query?: string;
@@ -37,7 +37,7 @@ export default function ConfirmationModal({
         </div>
 
         <div className="p-6">
-          <p className="text-gray-600 mb-6">{message}</p>
+          <p className="text-gray-600 mb-6 whitespace-pre-line">{message}</p>
 
           <div className="flex gap-4">
             <button
@@ -96,6 +96,8 @@ export default function ConnectionModal({
     ssh_username: initialData?.connection.ssh_username || '',
     ssh_private_key: initialData?.connection.ssh_private_key || '',
     ssh_passphrase: initialData?.connection.ssh_passphrase || '',
+    ssh_host_key: initialData?.connection.ssh_host_key || '',
+    ssh_skip_host_key_check: initialData?.connection.ssh_skip_host_key_check || false,
     is_example_db: false
   });
   const [errors, setErrors] = useState<FormErrors>({});
@@ -975,6 +977,10 @@ DATABASE_PASSWORD=`; // Mask password
       result += `\nSSH_USERNAME=${connection.ssh_username || ''}`;
       result += `\nSSH_PRIVATE_KEY=`; // Mask private key
       
+      if (connection.ssh_host_key) {
+        result += `\nSSH_HOST_KEY=${connection.ssh_host_key}`;
+      }
+      
       if (connection.ssh_passphrase) {
         result += `\nSSH_PASSPHRASE=`; // Mask passphrase
       }
@@ -170,6 +170,42 @@ const SSHConnectionTab: React.FC<SSHConnectionTabProps> = ({
                 placeholder="Leave empty if your key doesn't have a passphrase"
               />
             </div>
+
+            <div className="mb-4">
+              <label className="block font-medium mb-1">SSH Host Key</label>
+              <p className="text-gray-600 text-xs mb-1">Public key of your SSH server (a line of ssh-keyscan) or its SHA256 fingerprint (ssh-keygen -lf)</p>
+              <input
+                type="text"
+                name="ssh_host_key"
+                value={formData.ssh_host_key || ''}
+                onChange={handleChange}
+                className="neo-input w-full font-mono text-sm"
+                placeholder="e.g. ssh-ed25519 AAAAC3Nza... or SHA256:..."
+              />
+              <p className="text-gray-500 text-xs mt-1">Required to verify the server unless SSH_KNOWN_HOSTS_FILE is configured on the backend</p>
+            </div>
+
+            <div className="mb-4">
+              <label className="flex items-center gap-2">
+                <input
+                  type="checkbox"
+                  name="ssh_skip_host_key_check"
+                  checked={formData.ssh_skip_host_key_check || false}
+                  onChange={(e) => {
+                    const mockEvent = {
+                      target: {
+                        name: 'ssh_skip_host_key_check',
+                        value: e.target.checked
+                      }
+                    } as unknown as React.ChangeEvent<HTMLInputElement>;
+                    handleChange(mockEvent);
+                  }}
+                  className="w-4 h-4"
+                />
+                <span className="font-medium">Skip host key verification (insecure)</span>
+              </label>
+              <p className="text-gray-600 text-xs mt-1">Connects to any server presenting itself as the SSH host, only use it on trusted networks</p>
+            </div>
           </div>
 
           {/* Database Settings Section */}
@@ -45,6 +45,8 @@ export interface Connection {
     ssh_username?: string;
     ssh_private_key?: string;
     ssh_passphrase?: string;
+    ssh_host_key?: string;
+    ssh_skip_host_key_check?: boolean;
     // Spreadsheet specific fields
     file_uploads?: FileUpload[];
     schema_name?: string; // Schema name in the CSV PostgreSQL database
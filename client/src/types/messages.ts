@@ -31,6 +31,7 @@ export interface BackendMessage {
         example_execution_time: number;
         can_rollback: boolean;
         is_critical: boolean;
+        safety_warnings?: string[];
         is_executed: boolean;
         is_rolled_back: boolean;
         error?: {
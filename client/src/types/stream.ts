@@ -1,6 +1,19 @@
 export interface StreamResponse {
-    event: 'ai-response' | 'ai-response-step' | 'ai-response-error' | 'db-connected' |
+    event: 'ai-response' | 'ai-response-step' | 'ai-response-delta' | 'ai-response-error' | 'db-connected' |
     'db-disconnected' | 'sse-connected' | 'response-cancelled' | 'query-results' |
     'rollback-executed' | 'query-execution-failed' | 'rollback-query-failed';
     data?: any;
-} 
\ No newline at end of file
+}
+
+// Data of an ai-response-delta event, a part of the response while it is generated
+export interface StreamDelta {
+    type: 'message' | 'query' | 'reset';
+    text?: string;
+    index: number;
+    query?: {
+        query: string;
+        explanation?: string;
+        isCritical?: boolean;
+        canRollback?: boolean;
+    };
+}